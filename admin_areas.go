@@ -0,0 +1,282 @@
+package geo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+)
+
+// This is the error that consumers receive when no administrative
+// areas are known for the requested point, such as out at sea.
+var ErrNoAdminAreas = errors.New("no administrative areas found")
+
+// The levels of the administrative hierarchy, from largest to smallest.
+type AdminLevel int
+
+const (
+	AdminCountry AdminLevel = iota
+	AdminState
+	AdminCounty
+	AdminCity
+)
+
+// Returns "country", "state", "county" or "city".
+func (l AdminLevel) String() string {
+	switch l {
+	case AdminCountry:
+		return "country"
+	case AdminState:
+		return "state"
+	case AdminCounty:
+		return "county"
+	case AdminCity:
+		return "city"
+	}
+
+	return fmt.Sprintf("AdminLevel(%d)", int(l))
+}
+
+// An AdminArea is a country, state, county or city.
+// Code is a short code for the area where one is known, such as "US" or "CA".
+type AdminArea struct {
+	Level AdminLevel
+	Name  string
+	Code  string
+}
+
+// An AdminAreaLocator returns the administrative areas a point lies in,
+// ordered from the country down to the city.  Levels the locator doesn't know
+// are left out, so a point in unincorporated land may have no city.
+// Returns ErrNoAdminAreas if the point lies in none at all.
+type AdminAreaLocator interface {
+	AdminAreasOf(p *Point) ([]*AdminArea, error)
+}
+
+// Returns the passed in areas ordered by level, keeping only the first area seen at each level.
+func sortAdminAreas(areas []*AdminArea) []*AdminArea {
+	seen := make(map[AdminLevel]bool)
+	unique := areas[:0]
+	for _, a := range areas {
+		if !seen[a.Level] {
+			seen[a.Level] = true
+			unique = append(unique, a)
+		}
+	}
+
+	sort.SliceStable(unique, func(i, j int) bool { return unique[i].Level < unique[j].Level })
+	return unique
+}
+
+// The Google address component types of each administrative level.
+var googleAdminLevels = map[string]AdminLevel{
+	"country":                     AdminCountry,
+	"administrative_area_level_1": AdminState,
+	"administrative_area_level_2": AdminCounty,
+	"locality":                    AdminCity,
+	"postal_town":                 AdminCity,
+}
+
+// Reverse geocodes the passed in point and returns the administrative areas
+// Google places it in.  Implements the AdminAreaLocator Interface.
+func (g *GoogleGeocoder) AdminAreasOf(p *Point) ([]*AdminArea, error) {
+	params, err := g.params(url.Values{
+		"latlng":      {fmt.Sprintf("%f,%f", p.lat, p.lng)},
+		"result_type": {"country|administrative_area_level_1|administrative_area_level_2|locality|postal_town"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := g.Request(params)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &googleGeocodeResponse{}
+	if err := json.Unmarshal(data, res); err != nil {
+		return nil, err
+	}
+
+	if res.Status == "ZERO_RESULTS" {
+		return nil, ErrNoAdminAreas
+	}
+
+	if len(res.Results) == 0 {
+		return nil, googleStatusError("google", res.Status, res.Error_message)
+	}
+
+	// Results run from most to least specific; each repeats the areas above it.
+	var areas []*AdminArea
+	for _, r := range res.Results {
+		for _, c := range r.AddressComponents {
+			for _, typ := range c.Types {
+				if level, ok := googleAdminLevels[typ]; ok {
+					area := &AdminArea{Level: level, Name: c.LongName}
+					if level == AdminCountry {
+						area.Code = NormalizeCountryCode(c.ShortName)
+					} else if level == AdminState {
+						area.Code = c.ShortName
+					}
+					areas = append(areas, area)
+					break
+				}
+			}
+		}
+	}
+
+	if len(areas) == 0 {
+		return nil, ErrNoAdminAreas
+	}
+
+	return sortAdminAreas(areas), nil
+}
+
+// The fields of a MapQuest reverse geocoding response that AdminAreasOf uses.
+type mapquestReverseResult struct {
+	Error   string            `json:"error"`
+	Address map[string]string `json:"address"`
+}
+
+// Reverse geocodes the passed in point and returns the administrative areas
+// MapQuest places it in.  Implements the AdminAreaLocator Interface.
+func (g *MapQuestGeocoder) AdminAreasOf(p *Point) ([]*AdminArea, error) {
+	data, err := g.Request("reverse.php?" + g.params(url.Values{
+		"lat":            {fmt.Sprintf("%f", p.lat)},
+		"lon":            {fmt.Sprintf("%f", p.lng)},
+		"addressdetails": {"1"},
+		"zoom":           {"10"},
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	res := &mapquestReverseResult{}
+	if err := json.Unmarshal(data, res); err != nil {
+		return nil, err
+	}
+
+	if res.Error != "" {
+		return nil, ErrNoAdminAreas
+	}
+
+	var areas []*AdminArea
+	if name := res.Address["country"]; name != "" {
+		areas = append(areas, &AdminArea{Level: AdminCountry, Name: name, Code: NormalizeCountryCode(res.Address["country_code"])})
+	}
+
+	if name := res.Address["state"]; name != "" {
+		areas = append(areas, &AdminArea{Level: AdminState, Name: name})
+	}
+
+	if name := res.Address["county"]; name != "" {
+		areas = append(areas, &AdminArea{Level: AdminCounty, Name: name})
+	}
+
+	for _, key := range []string{"city", "town", "village"} {
+		if name := res.Address[key]; name != "" {
+			areas = append(areas, &AdminArea{Level: AdminCity, Name: name})
+			break
+		}
+	}
+
+	if len(areas) == 0 {
+		return nil, ErrNoAdminAreas
+	}
+
+	return areas, nil
+}
+
+// An administrative area loaded into AdminBoundaries, along with its boundary.
+type adminBoundary struct {
+	area    *AdminArea
+	polygon *Polygon
+	size    float64
+}
+
+// AdminBoundaries answers AdminAreasOf from boundary polygons loaded into it,
+// such as those published by Natural Earth or a national mapping agency,
+// without calling out to a provider.  Where boundaries at the same level overlap,
+// the smallest containing one is returned.  AdminBoundaries is safe for concurrent use.
+type AdminBoundaries struct {
+	mu         sync.RWMutex
+	boundaries []*adminBoundary
+	index      *RTree[int]
+}
+
+// Creates and returns a pointer to a new AdminBoundaries with no boundaries loaded.
+func NewAdminBoundaries() *AdminBoundaries {
+	return &AdminBoundaries{index: NewRTree[int]()}
+}
+
+// Adds the passed in area, bounded by the passed in polygon.
+// Polygons that are not closed are ignored.
+func (b *AdminBoundaries) Add(area *AdminArea, polygon *Polygon) {
+	if !polygon.IsClosed() {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := len(b.boundaries)
+	b.boundaries = append(b.boundaries, &adminBoundary{area: area, polygon: polygon, size: polygon.Area()})
+	for _, part := range polygon.SplitAtAntimeridian() {
+		r := rectOf(part.Points())
+		b.index.Insert(NewBounds(NewPoint(r.minLat, r.minLng), NewPoint(r.maxLat, r.maxLng)), id)
+	}
+}
+
+// Adds each polygon feature of the passed in collection as an area at the passed in level,
+// named by its nameProperty and coded by its codeProperty, which may be empty.
+// Returns the number of areas added.  Features without a polygon or a name are skipped.
+func (b *AdminBoundaries) AddFeatures(fc *FeatureCollection, level AdminLevel, nameProperty, codeProperty string) int {
+	added := 0
+	for _, f := range fc.Features {
+		polygon, ok := f.Geometry.(*Polygon)
+		name, _ := f.PropertyString(nameProperty)
+		if !ok || name == "" || !polygon.IsClosed() {
+			continue
+		}
+
+		area := &AdminArea{Level: level, Name: name}
+		if codeProperty != "" {
+			area.Code, _ = f.PropertyString(codeProperty)
+		}
+
+		b.Add(area, polygon)
+		added++
+	}
+	b.index.Build()
+
+	return added
+}
+
+// Returns the loaded areas containing the passed in point, one per level.
+// Implements the AdminAreaLocator Interface.
+func (b *AdminBoundaries) AdminAreasOf(p *Point) ([]*AdminArea, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var matches []*adminBoundary
+	for _, id := range b.index.Search(p) {
+		if boundary := b.boundaries[id]; boundary.polygon.Contains(p) {
+			matches = append(matches, boundary)
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil, ErrNoAdminAreas
+	}
+
+	// Smallest first, so that sortAdminAreas keeps the smallest area at each level.
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].size < matches[j].size })
+	areas := make([]*AdminArea, len(matches))
+	for i, m := range matches {
+		areas[i] = m.area
+	}
+
+	return sortAdminAreas(areas), nil
+}
@@ -0,0 +1,149 @@
+package geo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// Returns a description of the passed in areas for comparison in tests, e.g. "country:United States(US)".
+func describeAdminAreas(areas []*AdminArea) []string {
+	described := make([]string, len(areas))
+	for i, a := range areas {
+		described[i] = a.Level.String() + ":" + a.Name
+		if a.Code != "" {
+			described[i] += "(" + a.Code + ")"
+		}
+	}
+
+	return described
+}
+
+// Ensures that the passed in areas are described as expected.
+func assertAdminAreas(t *testing.T, areas []*AdminArea, expected ...string) {
+	t.Helper()
+
+	described := describeAdminAreas(areas)
+	if len(described) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, described)
+	}
+
+	for i := range expected {
+		if described[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, described)
+		}
+	}
+}
+
+// Ensures that GoogleGeocoder reads the administrative hierarchy from the address components.
+func TestGoogleAdminAreasOf(t *testing.T) {
+	var queries []url.Values
+	server := newQueryRecordingServer(t, "test/data/google_reverse_geocode_success.json", &queries)
+
+	areas, err := NewGoogleGeocoder(WithBaseURL(server.URL)).AdminAreasOf(NewPoint(40.714224, -73.961452))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertAdminAreas(t, areas, "country:United States(US)", "state:New York(NY)", "county:Kings", "city:New York")
+
+	if queries[0].Get("latlng") != "40.714224,-73.961452" || queries[0].Get("result_type") == "" {
+		t.Errorf("Unexpected query: %v", queries[0])
+	}
+}
+
+// Ensures that GoogleGeocoder returns ErrNoAdminAreas when there are no results.
+func TestGoogleAdminAreasOfZeroResults(t *testing.T) {
+	var queries []url.Values
+	server := newQueryRecordingServer(t, "test/data/google_geocode_zero_results.json", &queries)
+
+	_, err := NewGoogleGeocoder(WithBaseURL(server.URL)).AdminAreasOf(NewPoint(0, -30))
+	if !errors.Is(err, ErrNoAdminAreas) {
+		t.Errorf("Expected ErrNoAdminAreas, got %v", err)
+	}
+}
+
+// Ensures that MapQuestGeocoder reads the administrative hierarchy from the address details,
+// falling back to towns where there is no city.
+func TestMapQuestAdminAreasOf(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("addressdetails") != "1" {
+			t.Errorf("Expected address details to be requested, got %v", r.URL.Query())
+		}
+
+		w.Write([]byte(`{"address": {"town": "Boulder", "county": "Boulder County", "state": "Colorado", "country": "United States", "country_code": "us"}}`))
+	}))
+	defer server.Close()
+
+	areas, err := NewMapQuestGeocoder(WithBaseURL(server.URL)).AdminAreasOf(NewPoint(40.015, -105.27))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertAdminAreas(t, areas, "country:United States(US)", "state:Colorado", "county:Boulder County", "city:Boulder")
+}
+
+// Ensures that MapQuestGeocoder returns ErrNoAdminAreas when it cannot place the point.
+func TestMapQuestAdminAreasOfError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"error": "Unable to geocode"}`))
+	}))
+	defer server.Close()
+
+	_, err := NewMapQuestGeocoder(WithBaseURL(server.URL)).AdminAreasOf(NewPoint(0, -30))
+	if !errors.Is(err, ErrNoAdminAreas) {
+		t.Errorf("Expected ErrNoAdminAreas, got %v", err)
+	}
+}
+
+// Ensures that AdminBoundaries returns the smallest containing boundary at each level.
+func TestAdminBoundaries(t *testing.T) {
+	nsw, err := polygonFromFile("test/data/nsw.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	act, err := polygonFromFile("test/data/act.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := NewAdminBoundaries()
+	b.Add(&AdminArea{Level: AdminCountry, Name: "Australia", Code: "AU"}, squarePolygon(-45, 110, 45))
+	b.Add(&AdminArea{Level: AdminState, Name: "New South Wales", Code: "NSW"}, nsw)
+	b.Add(&AdminArea{Level: AdminState, Name: "Australian Capital Territory", Code: "ACT"}, act)
+
+	fc := &FeatureCollection{Features: []*Feature{
+		NewFeature(squarePolygon(-34.0, 151.0, 0.3)).Set("name", "Sydney"),
+		NewFeature(NewPoint(-33.9, 151.2)).Set("name", "Not a boundary"),
+		NewFeature(squarePolygon(-35.5, 149.0, 0.3)),
+	}}
+	if added := b.AddFeatures(fc, AdminCity, "name", ""); added != 1 {
+		t.Errorf("Expected only the named polygon to be added, got %d", added)
+	}
+
+	areas, err := b.AdminAreasOf(NewPoint(-33.87, 151.21))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertAdminAreas(t, areas, "country:Australia(AU)", "state:New South Wales(NSW)", "city:Sydney")
+
+	areas, err = b.AdminAreasOf(NewPoint(-35.28, 149.13))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertAdminAreas(t, areas, "country:Australia(AU)", "state:Australian Capital Territory(ACT)")
+
+	if _, err := b.AdminAreasOf(NewPoint(51.5, -0.12)); !errors.Is(err, ErrNoAdminAreas) {
+		t.Errorf("Expected ErrNoAdminAreas outside every boundary, got %v", err)
+	}
+}
+
+// Ensures that every backend satisfies the AdminAreaLocator interface.
+var (
+	_ AdminAreaLocator = &GoogleGeocoder{}
+	_ AdminAreaLocator = &MapQuestGeocoder{}
+	_ AdminAreaLocator = &AdminBoundaries{}
+)
@@ -0,0 +1,434 @@
+package geo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// This contains the default base URL for the OpenAQ v3 API.
+const DEFAULT_OPENAQ_URL = "https://api.openaq.org/v3"
+
+// This contains the default URL for the Google Air Quality current conditions API.
+const DEFAULT_GOOGLE_AIR_QUALITY_URL = "https://airquality.googleapis.com/v1/currentConditions:lookup"
+
+// The distance, in kilometers, an OpenAQProvider searches for a monitoring station
+// unless told otherwise.  This is the most OpenAQ allows.
+const DEFAULT_OPENAQ_RADIUS = 25
+
+// The name of the index reported by providers that compute the US EPA Air Quality Index.
+const US_EPA_AQI = "usa_epa"
+
+// This is the error that consumers receive when there is
+// no monitoring station near enough to the requested point.
+var airQualityNoStationError = errors.New("no air quality monitoring station nearby")
+
+// A single pollutant's measured concentration, such as "pm25" at 12.1 "µg/m³".
+type Pollutant struct {
+	Code          string
+	Concentration float64
+	Units         string
+}
+
+// The air quality at a point: an Air Quality Index on the scale named by Index,
+// and the concentrations of the pollutants it was computed from.
+type AirQualityReport struct {
+	// Where the measurements were taken.  For station based providers
+	// such as OpenAQ, this is the station rather than the requested point.
+	Point *Point
+	Time  time.Time
+
+	Index             string
+	AQI               int
+	Category          string
+	DominantPollutant string
+
+	Pollutants []Pollutant
+}
+
+// An AirQualityProvider looks up the current air quality at a point.
+type AirQualityProvider interface {
+	AirQuality(p *Point) (*AirQualityReport, error)
+}
+
+// A US EPA AQI breakpoint: concentrations from low to high map linearly onto AQIs from aqiLow to aqiHigh.
+type aqiBreakpoint struct {
+	low, high       float64
+	aqiLow, aqiHigh int
+}
+
+// The US EPA AQI breakpoints for the pollutants reported in micrograms per cubic meter.
+var usAQIBreakpoints = map[string][]aqiBreakpoint{
+	"pm25": {
+		{0, 9.0, 0, 50},
+		{9.1, 35.4, 51, 100},
+		{35.5, 55.4, 101, 150},
+		{55.5, 125.4, 151, 200},
+		{125.5, 225.4, 201, 300},
+		{225.5, 325.4, 301, 500},
+	},
+	"pm10": {
+		{0, 54, 0, 50},
+		{55, 154, 51, 100},
+		{155, 254, 101, 150},
+		{255, 354, 151, 200},
+		{355, 424, 201, 300},
+		{425, 604, 301, 500},
+	},
+}
+
+// The US EPA AQI categories, by the highest AQI in each.
+var usAQICategories = []struct {
+	max  int
+	name string
+}{
+	{50, "Good"},
+	{100, "Moderate"},
+	{150, "Unhealthy for Sensitive Groups"},
+	{200, "Unhealthy"},
+	{300, "Very Unhealthy"},
+	{math.MaxInt, "Hazardous"},
+}
+
+// Returns the US EPA Air Quality Index for the passed in concentration of the passed in pollutant,
+// in micrograms per cubic meter.  Only "pm25" and "pm10" are supported; the index for other
+// pollutants depends on averaging periods that single readings don't carry.
+// Returns false if the pollutant isn't supported or the concentration is negative.
+// Concentrations beyond the top of the scale are given an AQI of 500.
+func USAQI(pollutant string, concentration float64) (int, bool) {
+	breakpoints, ok := usAQIBreakpoints[pollutant]
+	if !ok || concentration < 0 {
+		return 0, false
+	}
+
+	// The EPA truncates PM2.5 to one decimal place and PM10 to an integer.
+	if pollutant == "pm25" {
+		concentration = math.Floor(concentration*10) / 10
+	} else {
+		concentration = math.Floor(concentration)
+	}
+
+	for _, b := range breakpoints {
+		if concentration <= b.high {
+			aqi := float64(b.aqiHigh-b.aqiLow)/(b.high-b.low)*(concentration-b.low) + float64(b.aqiLow)
+			return int(math.Round(aqi)), true
+		}
+	}
+
+	return 500, true
+}
+
+// Returns the US EPA category of the passed in AQI, e.g. "Moderate".
+func USAQICategory(aqi int) string {
+	for _, c := range usAQICategories {
+		if aqi <= c.max {
+			return c.name
+		}
+	}
+
+	return ""
+}
+
+// This struct contains all the functionality
+// of interacting with the OpenAQ API, which aggregates government
+// and research monitoring stations.  It reports the latest readings of the
+// nearest station, and computes a US EPA AQI from its particulate readings.
+type OpenAQProvider struct {
+	// If set, every request is counted against this Quota
+	// and refused once the "openaq" daily budget is spent.
+	Quota *Quota
+
+	// The client used to issue requests.  Defaults to a client sharing its connections
+	// with every provider, tuned with SetTransportConfig.
+	HTTPClient *http.Client
+
+	// The base URL of the API.  Defaults to DEFAULT_OPENAQ_URL.
+	BaseURL string
+
+	// How far, in kilometers, to search for a station.  Defaults to DEFAULT_OPENAQ_RADIUS.
+	Radius float64
+
+	apiKey string
+}
+
+// Creates and returns a pointer to a new OpenAQProvider configured by the passed in options.
+// OpenAQ makes use of WithAPIKey, WithHTTPClient, WithBaseURL and WithQuota.
+func NewOpenAQProvider(opts ...Option) *OpenAQProvider {
+	c := newGeocoderConfig(opts)
+	return &OpenAQProvider{
+		Quota:      c.quota,
+		HTTPClient: c.client("openaq"),
+		BaseURL:    c.baseURL,
+		Radius:     DEFAULT_OPENAQ_RADIUS,
+		apiKey:     c.apiKey,
+	}
+}
+
+// This struct contains selected fields from OpenAQ's locations response.
+type openAQLocationsResponse struct {
+	Detail  string `json:"detail"`
+	Results []struct {
+		ID          int `json:"id"`
+		Coordinates struct {
+			Latitude  float64 `json:"latitude"`
+			Longitude float64 `json:"longitude"`
+		} `json:"coordinates"`
+		Sensors []struct {
+			ID        int `json:"id"`
+			Parameter struct {
+				Name  string `json:"name"`
+				Units string `json:"units"`
+			} `json:"parameter"`
+		} `json:"sensors"`
+	} `json:"results"`
+}
+
+// This struct contains selected fields from OpenAQ's latest measurements response.
+type openAQLatestResponse struct {
+	Detail  string `json:"detail"`
+	Results []struct {
+		Datetime struct {
+			UTC time.Time `json:"utc"`
+		} `json:"datetime"`
+		Value     float64 `json:"value"`
+		SensorsID int     `json:"sensorsId"`
+	} `json:"results"`
+}
+
+// Issues a GET request to the passed in path of the OpenAQ API with the passed in query,
+// and decodes the JSON response into v.
+func (o *OpenAQProvider) get(path string, values url.Values, v interface{}) error {
+	if o.Quota != nil {
+		if err := o.Quota.Spend("openaq"); err != nil {
+			return err
+		}
+	}
+
+	base := o.BaseURL
+	if base == "" {
+		base = DEFAULT_OPENAQ_URL
+	}
+
+	req, err := http.NewRequest("GET", base+path+"?"+values.Encode(), nil)
+	if err != nil {
+		return err
+	}
+
+	if o.apiKey != "" {
+		req.Header.Set("X-API-Key", o.apiKey)
+	}
+
+	data, err := httpDo(o.HTTPClient, req)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// Returns the latest readings of the monitoring station nearest the passed in point.
+// Returns an error if there is no station within Radius.
+// Implements the AirQualityProvider Interface.
+func (o *OpenAQProvider) AirQuality(p *Point) (*AirQualityReport, error) {
+	radius := o.Radius
+	if radius <= 0 {
+		radius = DEFAULT_OPENAQ_RADIUS
+	}
+
+	locations := &openAQLocationsResponse{}
+	err := o.get("/locations", url.Values{
+		"coordinates": {strconv.FormatFloat(p.lat, 'f', -1, 64) + "," + strconv.FormatFloat(p.lng, 'f', -1, 64)},
+		"radius":      {strconv.Itoa(int(math.Min(radius, DEFAULT_OPENAQ_RADIUS) * 1000))},
+		"limit":       {"1"},
+	}, locations)
+	if err != nil {
+		return nil, err
+	}
+
+	if locations.Detail != "" {
+		return nil, fmt.Errorf("openaq: %s", locations.Detail)
+	}
+
+	if len(locations.Results) == 0 {
+		return nil, airQualityNoStationError
+	}
+
+	station := locations.Results[0]
+	latest := &openAQLatestResponse{}
+	if err := o.get("/locations/"+strconv.Itoa(station.ID)+"/latest", url.Values{}, latest); err != nil {
+		return nil, err
+	}
+
+	if latest.Detail != "" {
+		return nil, fmt.Errorf("openaq: %s", latest.Detail)
+	}
+
+	report := &AirQualityReport{
+		Point: NewPoint(station.Coordinates.Latitude, station.Coordinates.Longitude),
+		Index: US_EPA_AQI,
+	}
+
+	for _, reading := range latest.Results {
+		for _, sensor := range station.Sensors {
+			if sensor.ID != reading.SensorsID {
+				continue
+			}
+
+			code := sensor.Parameter.Name
+			report.Pollutants = append(report.Pollutants, Pollutant{Code: code, Concentration: reading.Value, Units: sensor.Parameter.Units})
+			if reading.Datetime.UTC.After(report.Time) {
+				report.Time = reading.Datetime.UTC
+			}
+
+			if sensor.Parameter.Units != "µg/m³" {
+				continue
+			}
+
+			if aqi, ok := USAQI(code, reading.Value); ok && aqi > report.AQI {
+				report.AQI = aqi
+				report.DominantPollutant = code
+			}
+		}
+	}
+
+	if report.DominantPollutant != "" {
+		report.Category = USAQICategory(report.AQI)
+	}
+
+	return report, nil
+}
+
+// This struct contains all the functionality
+// of interacting with the Google Air Quality API.  It reports the
+// Universal AQI ("uaqi") unless the region has a local index Google prefers.
+type GoogleAirQualityProvider struct {
+	// If set, every request is counted against this Quota
+	// and refused once the "google-air-quality" daily budget is spent.
+	Quota *Quota
+
+	// The client used to issue requests.  Defaults to a client sharing its connections
+	// with every provider, tuned with SetTransportConfig.
+	HTTPClient *http.Client
+
+	// The URL of the API.  Defaults to DEFAULT_GOOGLE_AIR_QUALITY_URL.
+	BaseURL string
+
+	apiKey   string
+	language string
+}
+
+// Creates and returns a pointer to a new GoogleAirQualityProvider configured by the passed in options.
+// Google's Air Quality API makes use of WithAPIKey, WithLanguage, WithHTTPClient, WithBaseURL and WithQuota.
+func NewGoogleAirQualityProvider(opts ...Option) *GoogleAirQualityProvider {
+	c := newGeocoderConfig(opts)
+	return &GoogleAirQualityProvider{
+		Quota:      c.quota,
+		HTTPClient: c.client("google-air-quality"),
+		BaseURL:    c.baseURL,
+		apiKey:     c.apiKey,
+		language:   c.language,
+	}
+}
+
+// This struct contains the request body of Google's current conditions lookup.
+type googleAirQualityRequest struct {
+	Location struct {
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+	} `json:"location"`
+	ExtraComputations []string `json:"extraComputations"`
+	LanguageCode      string   `json:"languageCode,omitempty"`
+}
+
+// This struct contains selected fields from Google's current conditions response.
+type googleAirQualityResponse struct {
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+	DateTime time.Time `json:"dateTime"`
+	Indexes  []struct {
+		Code              string `json:"code"`
+		AQI               int    `json:"aqi"`
+		Category          string `json:"category"`
+		DominantPollutant string `json:"dominantPollutant"`
+	} `json:"indexes"`
+	Pollutants []struct {
+		Code          string `json:"code"`
+		Concentration struct {
+			Value float64 `json:"value"`
+			Units string  `json:"units"`
+		} `json:"concentration"`
+	} `json:"pollutants"`
+}
+
+// Returns the current air quality at the passed in point.
+// Implements the AirQualityProvider Interface.
+func (g *GoogleAirQualityProvider) AirQuality(p *Point) (*AirQualityReport, error) {
+	if g.Quota != nil {
+		if err := g.Quota.Spend("google-air-quality"); err != nil {
+			return nil, err
+		}
+	}
+
+	body := googleAirQualityRequest{
+		ExtraComputations: []string{"POLLUTANT_CONCENTRATION", "DOMINANT_POLLUTANT_CONCENTRATION"},
+		LanguageCode:      g.language,
+	}
+	body.Location.Latitude, body.Location.Longitude = p.lat, p.lng
+
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	base := g.BaseURL
+	if base == "" {
+		base = DEFAULT_GOOGLE_AIR_QUALITY_URL
+	}
+
+	req, err := http.NewRequest("POST", base+"?"+url.Values{"key": {g.apiKey}}.Encode(), bytes.NewReader(encoded))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	data, err := httpDo(g.HTTPClient, req)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &googleAirQualityResponse{}
+	if err := json.Unmarshal(data, res); err != nil {
+		return nil, err
+	}
+
+	if res.Error != nil {
+		return nil, fmt.Errorf("google air quality: %s (%d)", res.Error.Message, res.Error.Code)
+	}
+
+	report := &AirQualityReport{Point: p, Time: res.DateTime}
+	if len(res.Indexes) > 0 {
+		index := res.Indexes[0]
+		report.Index = index.Code
+		report.AQI = index.AQI
+		report.Category = index.Category
+		report.DominantPollutant = index.DominantPollutant
+	}
+
+	for _, pollutant := range res.Pollutants {
+		report.Pollutants = append(report.Pollutants, Pollutant{
+			Code:          pollutant.Code,
+			Concentration: pollutant.Concentration.Value,
+			Units:         pollutant.Concentration.Units,
+		})
+	}
+
+	return report, nil
+}
@@ -0,0 +1,166 @@
+package geo
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Ensures that USAQI follows the EPA's breakpoints, including truncation and the top of the scale.
+func TestUSAQI(t *testing.T) {
+	tests := []struct {
+		pollutant     string
+		concentration float64
+		expected      int
+	}{
+		{"pm25", 0, 0},
+		{"pm25", 9.0, 50},
+		{"pm25", 9.09, 50},
+		{"pm25", 12.1, 57},
+		{"pm25", 35.5, 101},
+		{"pm25", 400, 500},
+		{"pm10", 54.9, 50},
+		{"pm10", 155, 101},
+	}
+
+	for _, test := range tests {
+		aqi, ok := USAQI(test.pollutant, test.concentration)
+		if !ok || aqi != test.expected {
+			t.Errorf("Expected an AQI of %d for %v of %s, got %d (%v)", test.expected, test.concentration, test.pollutant, aqi, ok)
+		}
+	}
+
+	if _, ok := USAQI("o3", 10); ok {
+		t.Error("Expected ozone to be unsupported")
+	}
+
+	if _, ok := USAQI("pm25", -1); ok {
+		t.Error("Expected a negative concentration to be rejected")
+	}
+
+	if USAQICategory(57) != "Moderate" || USAQICategory(501) != "Hazardous" {
+		t.Errorf("Unexpected categories %q and %q", USAQICategory(57), USAQICategory(501))
+	}
+}
+
+// Ensures that OpenAQProvider finds the nearest station and reports its latest readings.
+func TestOpenAQAirQuality(t *testing.T) {
+	var apiKeys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiKeys = append(apiKeys, r.Header.Get("X-API-Key"))
+		switch r.URL.Path {
+		case "/locations":
+			if r.URL.Query().Get("coordinates") != "34.05,-118.25" || r.URL.Query().Get("radius") != "10000" {
+				t.Errorf("Unexpected query: %v", r.URL.Query())
+			}
+			w.Write([]byte(`{"results": [{
+				"id": 2178,
+				"coordinates": {"latitude": 34.066, "longitude": -118.227},
+				"sensors": [
+					{"id": 1, "parameter": {"name": "pm25", "units": "µg/m³"}},
+					{"id": 2, "parameter": {"name": "pm10", "units": "µg/m³"}},
+					{"id": 3, "parameter": {"name": "no2", "units": "ppm"}}
+				]
+			}]}`))
+		case "/locations/2178/latest":
+			w.Write([]byte(`{"results": [
+				{"datetime": {"utc": "2024-05-01T10:00:00Z"}, "value": 12.1, "sensorsId": 1},
+				{"datetime": {"utc": "2024-05-01T11:00:00Z"}, "value": 20, "sensorsId": 2},
+				{"datetime": {"utc": "2024-05-01T11:00:00Z"}, "value": 0.021, "sensorsId": 3}
+			]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	o := NewOpenAQProvider(WithAPIKey("secret"), WithBaseURL(server.URL))
+	o.Radius = 10
+	report, err := o.AirQuality(NewPoint(34.05, -118.25))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(apiKeys) != 2 || apiKeys[0] != "secret" || apiKeys[1] != "secret" {
+		t.Errorf("Expected both requests to carry the API key, got %v", apiKeys)
+	}
+
+	if report.Index != US_EPA_AQI || report.AQI != 57 || report.DominantPollutant != "pm25" || report.Category != "Moderate" {
+		t.Errorf("Unexpected index: %+v", report)
+	}
+
+	if len(report.Pollutants) != 3 || report.Pollutants[2] != (Pollutant{Code: "no2", Concentration: 0.021, Units: "ppm"}) {
+		t.Errorf("Unexpected pollutants: %+v", report.Pollutants)
+	}
+
+	if report.Point.Lat() != 34.066 || !report.Time.Equal(time.Date(2024, 5, 1, 11, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the station's position and latest reading time, got %v at %v", report.Point, report.Time)
+	}
+}
+
+// Ensures that OpenAQProvider reports when there is no station nearby.
+func TestOpenAQAirQualityNoStation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"results": []}`))
+	}))
+	defer server.Close()
+
+	_, err := NewOpenAQProvider(WithBaseURL(server.URL)).AirQuality(NewPoint(-60, -140))
+	if !errors.Is(err, airQualityNoStationError) {
+		t.Errorf("Expected airQualityNoStationError, got %v", err)
+	}
+}
+
+// Ensures that GoogleAirQualityProvider posts the point and reads the index and pollutants from the response.
+func TestGoogleAirQuality(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Query().Get("key") != "secret" {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL)
+		}
+
+		body, _ := ioutil.ReadAll(r.Body)
+		req := googleAirQualityRequest{}
+		if err := json.Unmarshal(body, &req); err != nil || req.Location.Latitude != 37.42 || req.LanguageCode != "de" {
+			t.Errorf("Unexpected body %s: %v", body, err)
+		}
+
+		w.Write([]byte(`{
+			"dateTime": "2024-05-01T11:00:00Z",
+			"indexes": [{"code": "uaqi", "aqi": 71, "category": "Good air quality", "dominantPollutant": "o3"}],
+			"pollutants": [{"code": "o3", "concentration": {"value": 31.5, "units": "PARTS_PER_BILLION"}}]
+		}`))
+	}))
+	defer server.Close()
+
+	g := NewGoogleAirQualityProvider(WithAPIKey("secret"), WithBaseURL(server.URL), WithLanguage("de"))
+	report, err := g.AirQuality(NewPoint(37.42, -122.08))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if report.Index != "uaqi" || report.AQI != 71 || report.Category != "Good air quality" || report.DominantPollutant != "o3" {
+		t.Errorf("Unexpected index: %+v", report)
+	}
+
+	if len(report.Pollutants) != 1 || report.Pollutants[0].Concentration != 31.5 {
+		t.Errorf("Unexpected pollutants: %+v", report.Pollutants)
+	}
+}
+
+// Ensures that Google's error responses are returned as errors.
+func TestGoogleAirQualityError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error": {"code": 400, "message": "API key not valid."}}`))
+	}))
+	defer server.Close()
+
+	_, err := NewGoogleAirQualityProvider(WithBaseURL(server.URL)).AirQuality(NewPoint(0, 0))
+	if err == nil || err.Error() != "google air quality: API key not valid. (400)" {
+		t.Errorf("Expected an invalid key error, got %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+package geo
+
+import (
+	"math"
+	"sort"
+)
+
+// The distance, in meters, within which PointAnalyzer takes points to be duplicates unless told otherwise.
+const DEFAULT_DUPLICATE_METERS = 10.0
+
+// A PointReport describes the quality of a dataset of points, such as one just imported.
+// Points are referred to by their index in the dataset.
+type PointReport struct {
+	// The number of points in the dataset.
+	Total int
+
+	// The points at exactly (0, 0), "Null Island", where missing coordinates usually end up.
+	NullIsland []int
+
+	// The points that are nil, or whose coordinates are NaN, infinite, or beyond ±90° latitude or ±180° longitude.
+	Invalid []int
+
+	// Groups of points lying within the duplicate distance of one another, each in dataset order.
+	Duplicates [][]int
+
+	// The number of points in each country, by ISO 3166-1 alpha-2 code, with DEFAULT_COUNTRY_SHARD
+	// for those in none.
+	Countries map[string]int
+
+	// The box around every point that is neither invalid nor on Null Island, or nil if there are none.
+	Bounds *Bounds
+}
+
+// Returns the number of points that are neither invalid nor on Null Island.
+func (r *PointReport) Valid() int {
+	return r.Total - len(r.NullIsland) - len(r.Invalid)
+}
+
+// A PointAnalyzer produces PointReports.  The zero value is ready to use.
+type PointAnalyzer struct {
+	// The distance, in meters, within which points are taken to be duplicates.
+	// Defaults to DEFAULT_DUPLICATE_METERS.
+	DuplicateMeters float64
+
+	// If set, finds the country each point lies in, such as AdminBoundaries loaded with
+	// country borders.  Otherwise points are placed by the boxes around each country's land,
+	// in the smallest that contains them, which may be wrong near borders.
+	Locator AdminAreaLocator
+}
+
+// Returns a report on the quality of the passed in points, with the default PointAnalyzer.
+func AnalyzePoints(points []*Point) *PointReport {
+	return PointAnalyzer{}.Analyze(points)
+}
+
+// Returns a report on the quality of the passed in points.  Points that are invalid or on Null Island
+// are reported as such and left out of the duplicates, countries and bounds.
+func (a PointAnalyzer) Analyze(points []*Point) *PointReport {
+	report := &PointReport{Total: len(points), Countries: make(map[string]int)}
+
+	tree := NewKDTree[int]()
+	var valid []int
+	minLat, minLng, maxLat, maxLng := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for i, p := range points {
+		switch {
+		case !validCoordinates(p):
+			report.Invalid = append(report.Invalid, i)
+			continue
+		case p.lat == 0 && p.lng == 0:
+			report.NullIsland = append(report.NullIsland, i)
+			continue
+		}
+
+		valid = append(valid, i)
+		tree.Insert(p, i)
+		report.Countries[a.countryOf(p)]++
+
+		minLat, maxLat = math.Min(minLat, p.lat), math.Max(maxLat, p.lat)
+		minLng, maxLng = math.Min(minLng, p.lng), math.Max(maxLng, p.lng)
+	}
+
+	tree.Build()
+	if len(valid) > 0 {
+		report.Bounds = NewBounds(NewPoint(minLat, minLng), NewPoint(maxLat, maxLng))
+	}
+
+	report.Duplicates = a.duplicates(points, valid, tree)
+	return report
+}
+
+// Returns whether or not the passed in point is non-nil, with finite coordinates in range.
+func validCoordinates(p *Point) bool {
+	return p != nil && !math.IsNaN(p.lat) && !math.IsNaN(p.lng) && p.lat >= -90 && p.lat <= 90 && p.lng >= -180 && p.lng <= 180
+}
+
+// Groups the passed in valid points lying within the duplicate distance of one another,
+// joining chains of near points into a single group.
+func (a PointAnalyzer) duplicates(points []*Point, valid []int, tree *KDTree[int]) [][]int {
+	meters := a.DuplicateMeters
+	if meters <= 0 {
+		meters = DEFAULT_DUPLICATE_METERS
+	}
+
+	parent := make(map[int]int)
+	var find func(i int) int
+	find = func(i int) int {
+		if p, ok := parent[i]; ok && p != i {
+			parent[i] = find(p)
+			return parent[i]
+		}
+		return i
+	}
+
+	for _, i := range valid {
+		for _, near := range tree.Within(points[i], meters/1000) {
+			if ri, rj := find(i), find(near.Value); ri != rj {
+				parent[max(ri, rj)] = min(ri, rj)
+			}
+		}
+	}
+
+	groups := make(map[int][]int)
+	for _, i := range valid {
+		root := find(i)
+		groups[root] = append(groups[root], i)
+	}
+
+	var duplicates [][]int
+	for _, group := range groups {
+		if len(group) > 1 {
+			duplicates = append(duplicates, group)
+		}
+	}
+	sort.Slice(duplicates, func(i, j int) bool { return duplicates[i][0] < duplicates[j][0] })
+
+	return duplicates
+}
+
+// Returns the alpha-2 code of the country the passed in point lies in, or DEFAULT_COUNTRY_SHARD.
+func (a PointAnalyzer) countryOf(p *Point) string {
+	if a.Locator != nil {
+		return CountrySharder{Locator: a.Locator}.Shard(p)
+	}
+
+	code, smallest := DEFAULT_COUNTRY_SHARD, math.Inf(1)
+	for _, c := range Countries() {
+		if c.Bounds == nil || !c.Bounds.Contains(p) {
+			continue
+		}
+
+		width := c.Bounds.ne.lng - c.Bounds.sw.lng
+		if width < 0 {
+			width += 360
+		}
+
+		if area := (c.Bounds.ne.lat - c.Bounds.sw.lat) * width; area < smallest {
+			code, smallest = c.Alpha2, area
+		}
+	}
+
+	return code
+}
@@ -0,0 +1,47 @@
+package geo
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+// Ensures that Null Island, invalid coordinates, duplicates, countries and bounds are all reported.
+func TestAnalyzePoints(t *testing.T) {
+	points := []*Point{
+		NewPoint(37.615223, -122.389979),
+		NewPoint(0, 0),
+		NewPoint(37.615250, -122.389950),
+		NewPoint(math.NaN(), 10),
+		NewPoint(48.856613, 2.352222),
+		nil,
+		NewPoint(91, 0),
+		NewPoint(37.615280, -122.389920),
+		NewPoint(48.856613, 2.352222),
+	}
+
+	report := AnalyzePoints(points)
+	if report.Total != 9 || report.Valid() != 5 {
+		t.Errorf("Expected 9 points, 5 of them valid, got %d and %d", report.Total, report.Valid())
+	}
+
+	if !reflect.DeepEqual(report.NullIsland, []int{1}) || !reflect.DeepEqual(report.Invalid, []int{3, 5, 6}) {
+		t.Errorf("Expected [1] on Null Island and [3 5 6] invalid, got %v and %v", report.NullIsland, report.Invalid)
+	}
+
+	if !reflect.DeepEqual(report.Duplicates, [][]int{{0, 2, 7}, {4, 8}}) {
+		t.Errorf("Expected duplicates [[0 2 7] [4 8]], got %v", report.Duplicates)
+	}
+
+	if report.Countries["US"] != 3 || report.Countries["FR"] != 2 {
+		t.Errorf("Expected 3 points in US and 2 in FR, got %v", report.Countries)
+	}
+
+	if sw, ne := report.Bounds.SouthWest(), report.Bounds.NorthEast(); sw.Lat() != 37.615223 || sw.Lng() != -122.389979 || ne.Lat() != 48.856613 || ne.Lng() != 2.352222 {
+		t.Errorf("Expected bounds from SFO to Paris, got %v and %v", sw, ne)
+	}
+
+	if report := (PointAnalyzer{DuplicateMeters: 1}).Analyze(points); len(report.Duplicates) != 1 {
+		t.Errorf("Expected only the exact duplicates within 1 m, got %v", report.Duplicates)
+	}
+}
@@ -0,0 +1,206 @@
+package geo
+
+import (
+	"math"
+)
+
+// Returns the passed in longitude wrapped into the range [-180, 180].
+// Longitudes already within that range are returned unchanged.
+func NormalizeLng(lng float64) float64 {
+	if lng >= -180 && lng <= 180 {
+		return lng
+	}
+
+	lng = math.Mod(lng+180, 360)
+	if lng < 0 {
+		lng += 360
+	}
+
+	return lng - 180
+}
+
+// Returns the passed in latitude folded back into the range [-90, 90], as if
+// travelling past a pole and back down the other side.  A point that crosses
+// a pole also moves to the opposite longitude; use Point.Normalize to get both.
+func NormalizeLat(lat float64) float64 {
+	lat, _ = foldLat(lat)
+	return lat
+}
+
+// Returns the passed in latitude folded into [-90, 90],
+// and whether or not doing so crossed a pole.
+func foldLat(lat float64) (float64, bool) {
+	if lat >= -90 && lat <= 90 {
+		return lat, false
+	}
+
+	lat = math.Mod(lat+180, 360)
+	if lat < 0 {
+		lat += 360
+	}
+	lat -= 180
+
+	switch {
+	case lat > 90:
+		return 180 - lat, true
+	case lat < -90:
+		return -180 - lat, true
+	default:
+		return lat, false
+	}
+}
+
+// Returns the signed difference, in degrees, from the first passed in longitude
+// to the second, going the short way around the earth.  Positive differences are eastward.
+// For example, the difference from 179 to -179 is 2, not -358.
+func LngDiff(from float64, to float64) float64 {
+	d := math.Mod(to-from, 360)
+	switch {
+	case d > 180:
+		d -= 360
+	case d < -180:
+		d += 360
+	}
+
+	return d
+}
+
+// Returns a new Point equivalent to the current Point, with its latitude
+// within [-90, 90] and its longitude within [-180, 180].
+func (p *Point) Normalize() *Point {
+	lat, crossedPole := foldLat(p.lat)
+	lng := p.lng
+	if crossedPole {
+		lng += 180
+	}
+
+	return &Point{lat: lat, lng: NormalizeLng(lng)}
+}
+
+// Returns whether or not any edge between consecutive points crosses the antimeridian,
+// taking each edge to be the shorter way around the earth.
+func crossesAntimeridian(points []*Point, closed bool) bool {
+	for i := 1; i < len(points); i++ {
+		if math.Abs(points[i].lng-points[i-1].lng) > 180 {
+			return true
+		}
+	}
+
+	return closed && len(points) > 1 && math.Abs(points[0].lng-points[len(points)-1].lng) > 180
+}
+
+// Returns a copy of the passed in points with longitudes made continuous,
+// so that no edge jumps across the antimeridian.  Longitudes may fall outside [-180, 180].
+func unwrapLngs(points []*Point) []*Point {
+	unwrapped := make([]*Point, len(points))
+	for i, p := range points {
+		if i == 0 {
+			unwrapped[i] = &Point{lat: p.lat, lng: p.lng}
+			continue
+		}
+
+		prev := unwrapped[i-1]
+		unwrapped[i] = &Point{lat: p.lat, lng: prev.lng + LngDiff(prev.lng, p.lng)}
+	}
+
+	return unwrapped
+}
+
+// Splits the passed in line wherever it crosses the antimeridian, returning
+// one or more lines that each lie wholly on one side of it.  Crossings are
+// added to both lines they separate, at 180 on the eastern side and -180 on the western.
+func SplitAtAntimeridian(line []*Point) [][]*Point {
+	if !crossesAntimeridian(line, false) {
+		return [][]*Point{line}
+	}
+
+	var lines [][]*Point
+	current := []*Point{line[0]}
+	for i := 1; i < len(line); i++ {
+		prev, p := line[i-1], line[i]
+		d := LngDiff(prev.lng, p.lng)
+		if math.Abs(p.lng-prev.lng) <= 180 {
+			current = append(current, p)
+			continue
+		}
+
+		// The edge crosses the antimeridian; find the latitude it crosses at.
+		edge := 180.0
+		if d < 0 {
+			edge = -180
+		}
+		t := (edge - prev.lng) / d
+		lat := prev.lat + t*(p.lat-prev.lat)
+
+		current = append(current, &Point{lat: lat, lng: edge})
+		lines = append(lines, current)
+		current = []*Point{{lat: lat, lng: -edge}, p}
+	}
+
+	return append(lines, current)
+}
+
+// Splits the current Polygon along the antimeridian, returning a Polygon for
+// each side it covers.  Polygons that don't cross the antimeridian are returned as is.
+func (p *Polygon) SplitAtAntimeridian() []*Polygon {
+	if !crossesAntimeridian(p.points, true) {
+		return []*Polygon{p}
+	}
+
+	unwrapped := unwrapLngs(p.points)
+	edge := 180.0
+	for _, point := range unwrapped {
+		if point.lng < -180 {
+			edge = -180
+			break
+		}
+	}
+
+	var polygons []*Polygon
+	for _, keepBelow := range []bool{true, false} {
+		clipped := clipAtLng(unwrapped, edge, keepBelow)
+		if len(clipped) < 3 {
+			continue
+		}
+
+		// Move the side beyond the antimeridian back into [-180, 180].
+		shift := 0.0
+		if keepBelow && edge == -180 {
+			shift = 360
+		} else if !keepBelow && edge == 180 {
+			shift = -360
+		}
+
+		for i, point := range clipped {
+			clipped[i] = &Point{lat: point.lat, lng: point.lng + shift}
+		}
+		polygons = append(polygons, NewPolygon(clipped))
+	}
+
+	return polygons
+}
+
+// Clips the passed in ring to the half-plane west (keepBelow) or east of the passed
+// in longitude, using the Sutherland-Hodgman algorithm.
+func clipAtLng(ring []*Point, lng float64, keepBelow bool) []*Point {
+	inside := func(p *Point) bool {
+		if keepBelow {
+			return p.lng <= lng
+		}
+		return p.lng >= lng
+	}
+
+	var clipped []*Point
+	for i, cur := range ring {
+		prev := ring[(i+len(ring)-1)%len(ring)]
+		if inside(cur) != inside(prev) {
+			t := (lng - prev.lng) / (cur.lng - prev.lng)
+			clipped = append(clipped, &Point{lat: prev.lat + t*(cur.lat-prev.lat), lng: lng})
+		}
+		if inside(cur) {
+			clipped = append(clipped, cur)
+		}
+	}
+
+	return clipped
+}
@@ -0,0 +1,130 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+// Ensures that longitudes are wrapped into [-180, 180].
+func TestNormalizeLng(t *testing.T) {
+	cases := map[float64]float64{
+		0:    0,
+		180:  180,
+		-180: -180,
+		190:  -170,
+		-190: 170,
+		540:  -180,
+		725:  5,
+	}
+
+	for lng, want := range cases {
+		if got := NormalizeLng(lng); math.Abs(got-want) > 1e-9 {
+			t.Errorf("Expected NormalizeLng(%v) to be %v, got %v", lng, want, got)
+		}
+	}
+}
+
+// Ensures that latitudes past a pole are folded back, and that Normalize moves the longitude with them.
+func TestNormalizeLat(t *testing.T) {
+	cases := map[float64]float64{
+		45:   45,
+		90:   90,
+		95:   85,
+		-100: -80,
+		180:  0,
+		270:  -90,
+	}
+
+	for lat, want := range cases {
+		if got := NormalizeLat(lat); math.Abs(got-want) > 1e-9 {
+			t.Errorf("Expected NormalizeLat(%v) to be %v, got %v", lat, want, got)
+		}
+	}
+
+	p := NewPoint(95, 10).Normalize()
+	if p.Lat() != 85 || p.Lng() != -170 {
+		t.Errorf("Expected [85, -170], got [%v, %v]", p.Lat(), p.Lng())
+	}
+}
+
+// Ensures that longitude differences take the short way around the earth.
+func TestLngDiff(t *testing.T) {
+	cases := []struct{ from, to, want float64 }{
+		{10, 20, 10},
+		{20, 10, -10},
+		{179, -179, 2},
+		{-179, 179, -2},
+		{-170, 170, -20},
+	}
+
+	for _, c := range cases {
+		if got := LngDiff(c.from, c.to); math.Abs(got-c.want) > 1e-9 {
+			t.Errorf("Expected LngDiff(%v, %v) to be %v, got %v", c.from, c.to, c.want, got)
+		}
+	}
+}
+
+// Ensures that distances across the antimeridian are measured the short way.
+func TestGreatCircleDistanceAntimeridian(t *testing.T) {
+	d := NewPoint(0, 179.5).GreatCircleDistance(NewPoint(0, -179.5))
+	if math.Abs(d-111.19) > 0.1 {
+		t.Errorf("Expected about 111.19km, got %f", d)
+	}
+}
+
+// Ensures that a line crossing the antimeridian is split at the crossing.
+func TestSplitAtAntimeridian(t *testing.T) {
+	line := []*Point{NewPoint(0, 170), NewPoint(10, -170), NewPoint(20, -160)}
+
+	lines := SplitAtAntimeridian(line)
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(lines))
+	}
+
+	end := lines[0][len(lines[0])-1]
+	start := lines[1][0]
+	if end.Lat() != 5 || end.Lng() != 180 || start.Lat() != 5 || start.Lng() != -180 {
+		t.Errorf("Expected the lines to meet at [5, ±180], got [%v, %v] and [%v, %v]", end.Lat(), end.Lng(), start.Lat(), start.Lng())
+	}
+
+	if len(lines[0]) != 2 || len(lines[1]) != 3 {
+		t.Errorf("Expected lines of 2 and 3 points, got %d and %d", len(lines[0]), len(lines[1]))
+	}
+
+	if got := SplitAtAntimeridian(line[1:]); len(got) != 1 {
+		t.Errorf("Expected a line not crossing the antimeridian to be returned whole, got %d lines", len(got))
+	}
+}
+
+// Ensures that polygons crossing the antimeridian contain points on either side of it, and can be split.
+func TestPolygonAntimeridian(t *testing.T) {
+	fiji := NewPolygon([]*Point{
+		NewPoint(-21, 177),
+		NewPoint(-21, -178),
+		NewPoint(-12, -178),
+		NewPoint(-12, 177),
+	})
+
+	if !fiji.Contains(NewPoint(-18.1, 178.4)) || !fiji.Contains(NewPoint(-16.5, -179.9)) {
+		t.Error("Expected points either side of the antimeridian to lie within Fiji")
+	}
+
+	if fiji.Contains(NewPoint(-18, 0)) {
+		t.Error("Expected a point on the prime meridian to lie outside Fiji")
+	}
+
+	parts := fiji.SplitAtAntimeridian()
+	if len(parts) != 2 {
+		t.Fatalf("Expected 2 polygons, got %d", len(parts))
+	}
+
+	for _, part := range parts {
+		if crossesAntimeridian(part.Points(), true) {
+			t.Errorf("Expected no part to cross the antimeridian, got %v", part.Points())
+		}
+	}
+
+	if !parts[0].Contains(NewPoint(-18.1, 178.4)) || !parts[1].Contains(NewPoint(-16.5, -179.9)) {
+		t.Error("Expected one part on each side of the antimeridian")
+	}
+}
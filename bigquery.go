@@ -0,0 +1,260 @@
+package geo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// The column BigQueryWriter writes geometries to, and BigQuerySchema types as GEOGRAPHY, by default.
+const DEFAULT_BIGQUERY_GEOGRAPHY_COLUMN = "geometry"
+
+// The column BigQueryWriter writes Feature IDs to.
+const BIGQUERY_ID_COLUMN = "id"
+
+// A BigQueryField is a column of a BigQuery table schema, encoded as the JSON that
+// `bq load --schema` and the tables API accept.
+type BigQueryField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Mode string `json:"mode,omitempty"`
+}
+
+// A BigQueryWriter writes Features as newline-delimited JSON rows for loading into BigQuery,
+// e.g. with `bq load --source_format=NEWLINE_DELIMITED_JSON`: one object per line holding the Feature's ID,
+// its geometry as a WKT string in the GeographyColumn, which BigQuery parses into a GEOGRAPHY,
+// and its properties as columns of their own, named as BigQueryColumnName names them.
+// Properties whose columns clash with the ID or geography column are left out.
+type BigQueryWriter struct {
+	GeographyColumn string
+	w               io.Writer
+}
+
+// Creates and returns a pointer to a new BigQueryWriter writing rows to the passed in writer.
+func NewBigQueryWriter(w io.Writer) *BigQueryWriter {
+	return &BigQueryWriter{GeographyColumn: DEFAULT_BIGQUERY_GEOGRAPHY_COLUMN, w: w}
+}
+
+// Writes the passed in Feature as a row.  Features without a geometry get a NULL geography.
+func (b *BigQueryWriter) Write(f *Feature) error {
+	if err := checkBigQueryColumn(b.GeographyColumn); err != nil {
+		return err
+	}
+
+	row := make(map[string]interface{}, len(f.Properties)+2)
+	for key, value := range f.Properties {
+		row[BigQueryColumnName(key)] = bigQueryValue(value)
+	}
+
+	delete(row, BIGQUERY_ID_COLUMN)
+	if f.ID != nil {
+		row[BIGQUERY_ID_COLUMN] = f.ID
+	}
+
+	row[b.GeographyColumn] = nil
+	if f.Geometry != nil {
+		wkt, err := MarshalWKT(f.Geometry)
+		if err != nil {
+			return err
+		}
+		row[b.GeographyColumn] = wkt
+	}
+
+	line, err := json.Marshal(row)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.w.Write(append(line, '\n'))
+	return err
+}
+
+// Writes each of the passed in FeatureCollection's Features as a row.
+func (b *BigQueryWriter) WriteCollection(c *FeatureCollection) error {
+	for _, f := range c.Features {
+		if err := b.Write(f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Returns the passed in property value as BigQuery loads it: times as RFC 3339 timestamps
+// and non-finite numbers, which JSON can't hold, as NULL.
+func bigQueryValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil
+		}
+	}
+
+	return value
+}
+
+// Returns the passed in property name as a BigQuery column name: characters other than
+// letters, digits and underscores become underscores, and names not starting with a letter
+// or an underscore are prefixed with one.
+func BigQueryColumnName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		isLetter := r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+		if i == 0 && !isLetter {
+			b.WriteByte('_')
+		}
+		if isLetter || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		} else if i > 0 {
+			b.WriteByte('_')
+		}
+	}
+
+	if b.Len() == 0 {
+		return "_"
+	}
+
+	return b.String()
+}
+
+// Returns the BigQuery type of the passed in property value, or "" for NULL.
+// Arrays and objects, which would otherwise need a schema of their own, are typed as JSON.
+func bigQueryType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return "STRING"
+	case bool:
+		return "BOOL"
+	case int, int32, int64:
+		return "INT64"
+	case float32:
+		return "FLOAT64"
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) <= 1<<53 {
+			return "INT64"
+		}
+		return "FLOAT64"
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "INT64"
+		}
+		return "FLOAT64"
+	case time.Time:
+		return "TIMESTAMP"
+	default:
+		return "JSON"
+	}
+}
+
+// Returns the schema of the table BigQueryWriter writes the passed in Features to, with the geography
+// in the passed in column: the ID column, if any Feature has an ID, the geography column, then a NULLABLE
+// column for each property, in order of name.  A property's type is inferred from its values:
+// STRING, BOOL, INT64, FLOAT64 when some values are fractional, TIMESTAMP for time.Time,
+// and JSON for arrays, objects and properties whose values are of different types.
+func BigQuerySchema(geographyColumn string, features []*Feature) []BigQueryField {
+	types := make(map[string]string)
+	idType := ""
+	for _, f := range features {
+		if f.ID != nil {
+			idType = mergeBigQueryTypes(idType, bigQueryType(f.ID))
+		}
+
+		for key, value := range f.Properties {
+			column := BigQueryColumnName(key)
+			types[column] = mergeBigQueryTypes(types[column], bigQueryType(bigQueryValue(value)))
+		}
+	}
+
+	var schema []BigQueryField
+	if idType != "" {
+		schema = append(schema, BigQueryField{Name: BIGQUERY_ID_COLUMN, Type: idType, Mode: "NULLABLE"})
+	}
+	schema = append(schema, BigQueryField{Name: geographyColumn, Type: "GEOGRAPHY", Mode: "NULLABLE"})
+
+	columns := make([]string, 0, len(types))
+	for column := range types {
+		if column != BIGQUERY_ID_COLUMN && column != geographyColumn {
+			columns = append(columns, column)
+		}
+	}
+	sort.Strings(columns)
+
+	for _, column := range columns {
+		t := types[column]
+		if t == "" {
+			// Columns that are only ever NULL hold whatever comes later.
+			t = "JSON"
+		}
+		schema = append(schema, BigQueryField{Name: column, Type: t, Mode: "NULLABLE"})
+	}
+
+	return schema
+}
+
+// Returns the type of a column holding values of both passed in types, either of which may be "" for NULL.
+func mergeBigQueryTypes(a, b string) string {
+	switch {
+	case a == "" || a == b:
+		return b
+	case b == "":
+		return a
+	case a == "INT64" && b == "FLOAT64" || a == "FLOAT64" && b == "INT64":
+		return "FLOAT64"
+	default:
+		return "JSON"
+	}
+}
+
+// Returns the BigQuery schema matching the passed in PropertySchema, with the geography in the passed
+// in column: the geography column, then a column for each property, in order of name, REQUIRED
+// if the property is.  Properties of AnyProperty type are typed as JSON.
+func BigQuerySchemaFor(geographyColumn string, s PropertySchema) []BigQueryField {
+	keys := make([]string, 0, len(s))
+	for key := range s {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	schema := []BigQueryField{{Name: geographyColumn, Type: "GEOGRAPHY", Mode: "NULLABLE"}}
+	for _, key := range keys {
+		column := BigQueryColumnName(key)
+		if column == geographyColumn || column == BIGQUERY_ID_COLUMN {
+			continue
+		}
+
+		field := BigQueryField{Name: column, Type: "JSON", Mode: "NULLABLE"}
+		switch s[key].Type {
+		case StringProperty:
+			field.Type = "STRING"
+		case NumberProperty:
+			field.Type = "FLOAT64"
+		case IntegerProperty:
+			field.Type = "INT64"
+		case BoolProperty:
+			field.Type = "BOOL"
+		}
+		if s[key].Required {
+			field.Mode = "REQUIRED"
+		}
+		schema = append(schema, field)
+	}
+
+	return schema
+}
+
+// Returns an error if the passed in name isn't usable as a BigQuery column.
+func checkBigQueryColumn(name string) error {
+	if name == "" || BigQueryColumnName(name) != name {
+		return fmt.Errorf("invalid BigQuery column name %q", name)
+	}
+
+	return nil
+}
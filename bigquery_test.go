@@ -0,0 +1,97 @@
+package geo
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Ensures that Features are written as one JSON row per line, with their geometry as WKT.
+func TestBigQueryWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewBigQueryWriter(&buf)
+
+	store := NewFeature(NewPoint(37.615223, -122.389979)).Set("name", "SFO").Set("gates-count", 115)
+	store.ID = "sfo"
+	if err := w.WriteCollection(&FeatureCollection{Features: []*Feature{store, {}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	expected := []string{
+		`{"gates_count":115,"geometry":"POINT(-122.389979 37.615223)","id":"sfo","name":"SFO"}`,
+		`{"geometry":null}`,
+	}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Errorf("Expected %v, got %v", expected, lines)
+	}
+
+	w.GeographyColumn = "not a column"
+	if err := w.Write(store); err == nil {
+		t.Error("Expected an error for an invalid geography column")
+	}
+}
+
+// Ensures that column names are made valid for BigQuery.
+func TestBigQueryColumnName(t *testing.T) {
+	tests := map[string]string{
+		"name":        "name",
+		"gates-count": "gates_count",
+		"2nd":         "_2nd",
+		"-x":          "_x",
+		"":            "_",
+	}
+
+	for name, expected := range tests {
+		if column := BigQueryColumnName(name); column != expected {
+			t.Errorf("Expected %s for %q, got %s", expected, name, column)
+		}
+	}
+}
+
+// Ensures that schemas are inferred from the Features' property values.
+func TestBigQuerySchema(t *testing.T) {
+	var fc FeatureCollection
+	err := json.Unmarshal([]byte(`{"type":"FeatureCollection","features":[
+		{"type":"Feature","id":1,"geometry":null,"properties":{"name":"a","count":1,"ratio":1,"tags":["x"],"mixed":1}},
+		{"type":"Feature","geometry":null,"properties":{"name":null,"count":2,"ratio":0.5,"open":true,"mixed":"b"}}
+	]}`), &fc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []BigQueryField{
+		{Name: "id", Type: "INT64", Mode: "NULLABLE"},
+		{Name: "geometry", Type: "GEOGRAPHY", Mode: "NULLABLE"},
+		{Name: "count", Type: "INT64", Mode: "NULLABLE"},
+		{Name: "mixed", Type: "JSON", Mode: "NULLABLE"},
+		{Name: "name", Type: "STRING", Mode: "NULLABLE"},
+		{Name: "open", Type: "BOOL", Mode: "NULLABLE"},
+		{Name: "ratio", Type: "FLOAT64", Mode: "NULLABLE"},
+		{Name: "tags", Type: "JSON", Mode: "NULLABLE"},
+	}
+	if schema := BigQuerySchema(DEFAULT_BIGQUERY_GEOGRAPHY_COLUMN, fc.Features); !reflect.DeepEqual(schema, expected) {
+		t.Errorf("Expected %v, got %v", expected, schema)
+	}
+}
+
+// Ensures that schemas follow a PropertySchema's types and required properties.
+func TestBigQuerySchemaFor(t *testing.T) {
+	schema := BigQuerySchemaFor("geog", PropertySchema{
+		"name":  {Type: StringProperty, Required: true},
+		"floor": {Type: IntegerProperty},
+		"extra": {},
+	})
+
+	expected := []BigQueryField{
+		{Name: "geog", Type: "GEOGRAPHY", Mode: "NULLABLE"},
+		{Name: "extra", Type: "JSON", Mode: "NULLABLE"},
+		{Name: "floor", Type: "INT64", Mode: "NULLABLE"},
+		{Name: "name", Type: "STRING", Mode: "REQUIRED"},
+	}
+	if !reflect.DeepEqual(schema, expected) {
+		t.Errorf("Expected %v, got %v", expected, schema)
+	}
+}
@@ -0,0 +1,199 @@
+package geo
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// The first byte of a binary encoded geometry, telling which geometry follows.
+const (
+	binaryPoint   byte = 1
+	binaryLine    byte = 2
+	binaryPolygon byte = 3
+)
+
+// The size of a binary encoded Point's coordinates: two little endian float64s, latitude first.
+const binaryPointSize = 16
+
+// This is the error that consumers can compare against with errors.Is when binary data
+// isn't a geometry, or isn't the geometry it is decoded into.
+var ErrInvalidBinary = errors.New("geo: invalid binary geometry")
+
+// Returns an error matching ErrInvalidBinary, explaining why the data is invalid.
+func invalidBinary(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: "+format, append([]interface{}{ErrInvalidBinary}, args...)...)
+}
+
+// Appends the coordinates of the passed in point to dst.
+func appendBinaryPoint(dst []byte, p *Point) []byte {
+	dst = binary.LittleEndian.AppendUint64(dst, math.Float64bits(p.lat))
+	return binary.LittleEndian.AppendUint64(dst, math.Float64bits(p.lng))
+}
+
+// Appends the passed in points to dst, preceded by their number.
+func appendBinaryPoints(dst []byte, points []*Point) ([]byte, error) {
+	dst = binary.AppendUvarint(dst, uint64(len(points)))
+	for i, p := range points {
+		if p == nil {
+			return nil, fmt.Errorf("geo: point %d is nil", i)
+		}
+		dst = appendBinaryPoint(dst, p)
+	}
+
+	return dst, nil
+}
+
+// Decodes the coordinates at the start of data.
+func decodeBinaryPoint(data []byte) *Point {
+	return &Point{
+		lat: math.Float64frombits(binary.LittleEndian.Uint64(data)),
+		lng: math.Float64frombits(binary.LittleEndian.Uint64(data[8:])),
+	}
+}
+
+// Decodes the number of points followed by that many points, which must be all of data.
+func decodeBinaryPoints(data []byte) ([]*Point, error) {
+	n, size := binary.Uvarint(data)
+	if size <= 0 {
+		return nil, invalidBinary("bad point count")
+	}
+
+	data = data[size:]
+	if n > uint64(len(data)) || n*binaryPointSize != uint64(len(data)) {
+		return nil, invalidBinary("%d bytes hold no %d points", len(data), n)
+	}
+
+	points := make([]*Point, n)
+	for i := range points {
+		points[i] = decodeBinaryPoint(data[i*binaryPointSize:])
+	}
+
+	return points, nil
+}
+
+// Checks that data starts with the passed in geometry tag and returns what follows it.
+func binaryBody(data []byte, tag byte, name string) ([]byte, error) {
+	if len(data) == 0 || data[0] != tag {
+		return nil, invalidBinary("not a %s", name)
+	}
+
+	return data[1:], nil
+}
+
+// Renders the current Point in 17 bytes: a tag followed by its latitude and longitude as little endian float64s.
+// Implements the encoding.BinaryMarshaler interface, which gob uses too.
+func (p *Point) MarshalBinary() ([]byte, error) {
+	return appendBinaryPoint([]byte{binaryPoint}, p), nil
+}
+
+// Decodes the current Point from data rendered by MarshalBinary.
+// Implements the encoding.BinaryUnmarshaler interface.
+func (p *Point) UnmarshalBinary(data []byte) error {
+	body, err := binaryBody(data, binaryPoint, "point")
+	if err != nil {
+		return err
+	}
+
+	if len(body) != binaryPointSize {
+		return invalidBinary("a point is %d bytes, not %d", binaryPointSize, len(body))
+	}
+
+	*p = *decodeBinaryPoint(body)
+	return nil
+}
+
+// Renders the current Line as a tag and its number of points, followed by 16 bytes per point.
+// Fails if any of its points is nil.  Implements the encoding.BinaryMarshaler interface.
+func (l Line) MarshalBinary() ([]byte, error) {
+	return appendBinaryPoints([]byte{binaryLine}, l)
+}
+
+// Decodes the current Line from data rendered by MarshalBinary.
+// Implements the encoding.BinaryUnmarshaler interface.
+func (l *Line) UnmarshalBinary(data []byte) error {
+	body, err := binaryBody(data, binaryLine, "line")
+	if err != nil {
+		return err
+	}
+
+	points, err := decodeBinaryPoints(body)
+	if err != nil {
+		return err
+	}
+
+	*l = points
+	return nil
+}
+
+// Renders the current Polygon as a tag and its number of points, followed by 16 bytes per point.
+// Fails if any of its points is nil.  Implements the encoding.BinaryMarshaler interface.
+func (p *Polygon) MarshalBinary() ([]byte, error) {
+	return appendBinaryPoints([]byte{binaryPolygon}, p.points)
+}
+
+// Decodes the current Polygon from data rendered by MarshalBinary.
+// Implements the encoding.BinaryUnmarshaler interface.
+func (p *Polygon) UnmarshalBinary(data []byte) error {
+	body, err := binaryBody(data, binaryPolygon, "polygon")
+	if err != nil {
+		return err
+	}
+
+	points, err := decodeBinaryPoints(body)
+	if err != nil {
+		return err
+	}
+
+	p.points = points
+	return nil
+}
+
+// Renders the passed in geometry, a *Point, a Line or a *Polygon, with its MarshalBinary method.
+// The data records which geometry it holds, so that UnmarshalGeometry can decode it without being told,
+// e.g. when caching geometries of several kinds in Redis.
+func MarshalGeometry(g Geometry) ([]byte, error) {
+	switch g := g.(type) {
+	case *Point:
+		return g.MarshalBinary()
+	case Line:
+		return g.MarshalBinary()
+	case *Polygon:
+		return g.MarshalBinary()
+	default:
+		return nil, fmt.Errorf("geo: can't encode a %T", g)
+	}
+}
+
+// Decodes a *Point, a Line or a *Polygon from data rendered by MarshalGeometry or by its MarshalBinary method.
+func UnmarshalGeometry(data []byte) (Geometry, error) {
+	if len(data) == 0 {
+		return nil, invalidBinary("no data")
+	}
+
+	var g interface {
+		Geometry
+		UnmarshalBinary(data []byte) error
+	}
+	switch data[0] {
+	case binaryPoint:
+		g = &Point{}
+	case binaryLine:
+		g = &Line{}
+	case binaryPolygon:
+		g = &Polygon{}
+	default:
+		return nil, invalidBinary("unknown geometry %d", data[0])
+	}
+
+	if err := g.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+
+	if l, ok := g.(*Line); ok {
+		return *l, nil
+	}
+
+	return g, nil
+}
@@ -0,0 +1,63 @@
+package geo
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// Ensures that geometries survive a round trip through their binary encoding, directly and with gob.
+func TestBinaryRoundTrip(t *testing.T) {
+	points := []*Point{NewPoint(37.6, -122.4), NewPoint(37.8, -122.4), NewPoint(37.7, -122.2)}
+	geometries := []Geometry{points[0], Line(points), NewPolygon(points)}
+
+	for _, g := range geometries {
+		data, err := MarshalGeometry(g)
+		if err != nil {
+			t.Fatalf("Expected %T to encode, got %v", g, err)
+		}
+
+		decoded, err := UnmarshalGeometry(data)
+		if err != nil || !reflect.DeepEqual(decoded, g) {
+			t.Errorf("Expected %v, got %v (%v)", g, decoded, err)
+		}
+	}
+
+	if data, _ := points[0].MarshalBinary(); len(data) != 17 {
+		t.Errorf("Expected a point to take 17 bytes, got %d", len(data))
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(NewPolygon(points)); err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := &Polygon{}
+	if err := gob.NewDecoder(&buf).Decode(decoded); err != nil || !reflect.DeepEqual(decoded.Points(), points) {
+		t.Errorf("Expected %v through gob, got %v (%v)", points, decoded.Points(), err)
+	}
+}
+
+// Ensures that data that isn't the expected geometry is refused.
+func TestBinaryInvalid(t *testing.T) {
+	data, _ := Line{NewPoint(1, 2), NewPoint(3, 4)}.MarshalBinary()
+
+	if err := (&Polygon{}).UnmarshalBinary(data); !errors.Is(err, ErrInvalidBinary) {
+		t.Errorf("Expected ErrInvalidBinary decoding a line as a polygon, got %v", err)
+	}
+
+	var l Line
+	if err := l.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, ErrInvalidBinary) {
+		t.Errorf("Expected ErrInvalidBinary decoding a truncated line, got %v", err)
+	}
+
+	if _, err := UnmarshalGeometry([]byte{9}); !errors.Is(err, ErrInvalidBinary) {
+		t.Errorf("Expected ErrInvalidBinary decoding an unknown geometry, got %v", err)
+	}
+
+	if _, err := (Line{nil}).MarshalBinary(); err == nil {
+		t.Error("Expected an error encoding a nil point")
+	}
+}
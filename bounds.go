@@ -0,0 +1,39 @@
+package geo
+
+// A Bounds represents a rectangular area on the earth's surface,
+// described by its south west and north east corners.
+type Bounds struct {
+	sw *Point
+	ne *Point
+}
+
+// Creates and returns a pointer to a new Bounds with the passed in south west and north east corners.
+func NewBounds(sw *Point, ne *Point) *Bounds {
+	return &Bounds{sw: sw, ne: ne}
+}
+
+// Returns the south west corner of the Bounds.
+func (b *Bounds) SouthWest() *Point {
+	return b.sw
+}
+
+// Returns the north east corner of the Bounds.
+func (b *Bounds) NorthEast() *Point {
+	return b.ne
+}
+
+// Returns whether or not the passed in point lies within the Bounds, edges included.
+// Bounds whose west edge is east of their east edge are taken to cross the antimeridian.
+func (b *Bounds) Contains(p *Point) bool {
+	p = p.Normalize()
+	if p.lat < b.sw.lat || p.lat > b.ne.lat {
+		return false
+	}
+
+	west, east := NormalizeLng(b.sw.lng), NormalizeLng(b.ne.lng)
+	if west <= east {
+		return p.lng >= west && p.lng <= east
+	}
+
+	return p.lng >= west || p.lng <= east
+}
@@ -0,0 +1,35 @@
+package geo
+
+import (
+	"testing"
+)
+
+// Ensures that Contains accepts points within and on the edges of a Bounds, and rejects the rest.
+func TestBoundsContains(t *testing.T) {
+	b := NewBounds(NewPoint(37.60, -122.52), NewPoint(37.81, -122.35))
+
+	if !b.Contains(NewPoint(37.7749, -122.4194)) {
+		t.Error("Expected San Francisco to lie within its bounds")
+	}
+
+	if !b.Contains(NewPoint(37.60, -122.35)) {
+		t.Error("Expected a corner to lie within the bounds")
+	}
+
+	if b.Contains(NewPoint(40.7128, -74.0060)) {
+		t.Error("Expected New York to lie outside San Francisco's bounds")
+	}
+}
+
+// Ensures that Bounds crossing the antimeridian contain points on either side of it.
+func TestBoundsContainsAntimeridian(t *testing.T) {
+	fiji := NewBounds(NewPoint(-21, 177), NewPoint(-12, -178))
+
+	if !fiji.Contains(NewPoint(-18.1, 178.4)) || !fiji.Contains(NewPoint(-16.5, -179.9)) {
+		t.Error("Expected points either side of the antimeridian to lie within Fiji")
+	}
+
+	if fiji.Contains(NewPoint(-18, 0)) {
+		t.Error("Expected a point on the prime meridian to lie outside Fiji")
+	}
+}
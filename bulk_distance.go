@@ -0,0 +1,92 @@
+package geo
+
+import (
+	"math"
+	"runtime"
+	"sync"
+)
+
+// The number of points below which BulkDistances doesn't bother spreading work across goroutines.
+const bulkDistanceChunk = 16384
+
+// A PointArray holds many points in a struct-of-arrays layout, with the
+// trigonometry that doesn't depend on the origin computed up front, so that
+// distances from many origins to the same points can be computed quickly.
+type PointArray struct {
+	lats    []float64
+	lngs    []float64
+	cosLats []float64
+}
+
+// Creates and returns a pointer to a new PointArray holding the passed in points.
+func NewPointArray(points []Point) *PointArray {
+	a := &PointArray{
+		lats:    make([]float64, len(points)),
+		lngs:    make([]float64, len(points)),
+		cosLats: make([]float64, len(points)),
+	}
+
+	for i := range points {
+		lat := points[i].lat * (math.Pi / 180.0)
+		a.lats[i] = lat
+		a.lngs[i] = points[i].lng * (math.Pi / 180.0)
+		a.cosLats[i] = math.Cos(lat)
+	}
+
+	return a
+}
+
+// Returns the number of points in the PointArray.
+func (a *PointArray) Len() int {
+	return len(a.lats)
+}
+
+// Returns the Haversine distance, in kilometers, from the passed in origin to
+// each point of the PointArray, in order.  Large arrays are split into chunks
+// computed on separate goroutines.
+func (a *PointArray) DistancesFrom(origin *Point) []float64 {
+	distances := make([]float64, len(a.lats))
+
+	lat1 := origin.lat * (math.Pi / 180.0)
+	lng1 := origin.lng * (math.Pi / 180.0)
+	cosLat1 := math.Cos(lat1)
+
+	chunk := func(start, end int) {
+		lats, lngs, cosLats := a.lats[start:end], a.lngs[start:end], a.cosLats[start:end]
+		out := distances[start:end]
+		for i := range out {
+			sinLat := math.Sin((lats[i] - lat1) / 2)
+			sinLng := math.Sin((lngs[i] - lng1) / 2)
+			h := sinLat*sinLat + sinLng*sinLng*cosLat1*cosLats[i]
+			out[i] = 2 * EARTH_RADIUS * math.Atan2(math.Sqrt(h), math.Sqrt(1-h))
+		}
+	}
+
+	workers := runtime.GOMAXPROCS(0)
+	if len(distances) < 2*bulkDistanceChunk || workers == 1 {
+		chunk(0, len(distances))
+		return distances
+	}
+
+	size := max(bulkDistanceChunk, (len(distances)+workers-1)/workers)
+
+	var wg sync.WaitGroup
+	for start := 0; start < len(distances); start += size {
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			chunk(start, end)
+		}(start, min(start+size, len(distances)))
+	}
+	wg.Wait()
+
+	return distances
+}
+
+// Returns the Haversine distance, in kilometers, from the passed in origin to each
+// of the passed in targets, in order.  Gives the same results as calling
+// GreatCircleDistance for each target, several times faster for large slices.
+// To measure from many origins to the same targets, build a PointArray once instead.
+func BulkDistances(origin *Point, targets []Point) []float64 {
+	return NewPointArray(targets).DistancesFrom(origin)
+}
@@ -0,0 +1,71 @@
+package geo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// Returns the passed in number of points scattered across the earth.  The same points are returned every time.
+func randomPoints(n int) []Point {
+	r := rand.New(rand.NewSource(1))
+	points := make([]Point, n)
+	for i := range points {
+		points[i] = Point{lat: r.Float64()*180 - 90, lng: r.Float64()*360 - 180}
+	}
+
+	return points
+}
+
+// Ensures that BulkDistances agrees with GreatCircleDistance, whether or not the work is split across goroutines.
+func TestBulkDistances(t *testing.T) {
+	origin := NewPoint(37.7749, -122.4194)
+
+	for _, n := range []int{0, 1, 1000, 4*bulkDistanceChunk + 7} {
+		targets := randomPoints(n)
+		distances := BulkDistances(origin, targets)
+
+		if len(distances) != n {
+			t.Fatalf("Expected %d distances, got %d", n, len(distances))
+		}
+
+		for i := range targets {
+			if want := origin.GreatCircleDistance(&targets[i]); math.Abs(distances[i]-want) > 1e-6 {
+				t.Fatalf("Expected distance %d to be %f, got %f", i, want, distances[i])
+			}
+		}
+	}
+}
+
+func BenchmarkGreatCircleDistanceLoop(b *testing.B) {
+	origin := NewPoint(37.7749, -122.4194)
+	targets := randomPoints(1000000)
+	distances := make([]float64, len(targets))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range targets {
+			distances[j] = origin.GreatCircleDistance(&targets[j])
+		}
+	}
+}
+
+func BenchmarkBulkDistances(b *testing.B) {
+	origin := NewPoint(37.7749, -122.4194)
+	targets := randomPoints(1000000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BulkDistances(origin, targets)
+	}
+}
+
+func BenchmarkPointArrayDistancesFrom(b *testing.B) {
+	origin := NewPoint(37.7749, -122.4194)
+	targets := NewPointArray(randomPoints(1000000))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		targets.DistancesFrom(origin)
+	}
+}
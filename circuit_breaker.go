@@ -0,0 +1,221 @@
+package geo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// The number of consecutive failures after which a CircuitBreakerGeocoder stops asking its provider,
+// unless told otherwise.
+const DEFAULT_CIRCUIT_BREAKER_THRESHOLD = 5
+
+// How long a CircuitBreakerGeocoder waits before probing a provider it stopped asking, unless told otherwise.
+const DEFAULT_CIRCUIT_BREAKER_COOLDOWN = 30 * time.Second
+
+// This is the error that consumers can compare against with errors.Is when a CircuitBreakerGeocoder
+// refuses a request because its provider has been failing.  Also matches ErrProviderDown,
+// so that fallback code treats the provider as down without waiting for it.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// The error a CircuitBreakerGeocoder returns while its circuit is open.
+type circuitOpenError struct{}
+
+func (circuitOpenError) Error() string {
+	return ErrCircuitOpen.Error()
+}
+
+// Allows errors.Is(err, ErrCircuitOpen) and errors.Is(err, ErrProviderDown) to succeed.
+func (circuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen || target == ErrProviderDown
+}
+
+// The states of a CircuitBreakerGeocoder's circuit.
+type CircuitState int
+
+const (
+	// Requests are passed on to the provider.
+	CircuitClosed CircuitState = iota
+
+	// Requests are refused without asking the provider.
+	CircuitOpen
+
+	// A single probe request is passed on to find out whether the provider has recovered;
+	// other requests are refused until it answers.
+	CircuitHalfOpen
+)
+
+// A CircuitBreakerGeocoder wraps a provider so that a dead one fails fast: after Threshold consecutive
+// failures it stops asking the provider and refuses every request with ErrCircuitOpen, then once Cooldown
+// has passed lets a single probe request through, closing the circuit again if the probe succeeds and
+// reopening it if it fails.  Wrapping each provider of a RankingGeocoder, or of any chain of fallbacks,
+// keeps a dead provider from adding its timeout to every request.
+//
+// Only failures of the provider count: errors matching ErrNotFound or ErrBadRequest mean the provider
+// answered, so they don't open the circuit.  The zero value is ready to use once Geocoder is set,
+// and is safe to use from multiple goroutines.
+type CircuitBreakerGeocoder struct {
+	Geocoder Geocoder
+
+	// The number of consecutive failures that opens the circuit.
+	// Defaults to DEFAULT_CIRCUIT_BREAKER_THRESHOLD.
+	Threshold int
+
+	// How long the circuit stays open before a probe is let through.
+	// Defaults to DEFAULT_CIRCUIT_BREAKER_COOLDOWN.
+	Cooldown time.Duration
+
+	// If set, called with the new state whenever the circuit opens, half opens or closes,
+	// e.g. for logging or metrics.
+	OnStateChange func(state CircuitState)
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+
+	// Used to determine the current time.  Overridable for testing.
+	now func() time.Time
+}
+
+// Creates and returns a pointer to a new CircuitBreakerGeocoder wrapping the passed in provider,
+// opening after the passed in number of consecutive failures and probing after the passed in cooldown.
+func NewCircuitBreakerGeocoder(g Geocoder, threshold int, cooldown time.Duration) *CircuitBreakerGeocoder {
+	return &CircuitBreakerGeocoder{Geocoder: g, Threshold: threshold, Cooldown: cooldown}
+}
+
+// Returns the current state of the circuit.
+func (c *CircuitBreakerGeocoder) State() CircuitState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state == CircuitOpen && !c.clock().Before(c.openedAt.Add(c.cooldown())) {
+		return CircuitHalfOpen
+	}
+
+	return c.state
+}
+
+// Geocodes the passed in query with the wrapped provider, unless the circuit is open.
+func (c *CircuitBreakerGeocoder) Geocode(query string, opts ...QueryOption) (*Point, error) {
+	if err := c.allow(); err != nil {
+		return nil, err
+	}
+
+	p, err := c.Geocoder.Geocode(query, opts...)
+	c.record(err)
+	return p, err
+}
+
+// Returns every candidate the wrapped provider finds for the passed in query, unless the circuit is open.
+// Providers that aren't ResultGeocoders return a single candidate, without an address.
+func (c *CircuitBreakerGeocoder) GeocodeResults(query string, opts ...QueryOption) ([]*GeocodeResult, error) {
+	if err := c.allow(); err != nil {
+		return nil, err
+	}
+
+	var results []*GeocodeResult
+	var err error
+	if g, ok := c.Geocoder.(ResultGeocoder); ok {
+		results, err = g.GeocodeResults(query, opts...)
+	} else {
+		var p *Point
+		if p, err = c.Geocoder.Geocode(query, opts...); err == nil {
+			results = []*GeocodeResult{{Point: p}}
+		}
+	}
+
+	c.record(err)
+	return results, err
+}
+
+// Reverse geocodes the passed in Point with the wrapped provider, unless the circuit is open.
+func (c *CircuitBreakerGeocoder) ReverseGeocode(p *Point, opts ...QueryOption) (string, error) {
+	if err := c.allow(); err != nil {
+		return "", err
+	}
+
+	address, err := c.Geocoder.ReverseGeocode(p, opts...)
+	c.record(err)
+	return address, err
+}
+
+// Returns the result of the wrapped Geocoder's HealthCheck, if it has one.  Health checks are neither
+// refused by an open circuit nor counted towards opening it, so a readiness probe sees the provider's own health.
+func (c *CircuitBreakerGeocoder) HealthCheck(ctx context.Context) error {
+	return healthCheck(ctx, c.Geocoder)
+}
+
+// Returns an error if a request may not be passed on to the provider now,
+// half opening the circuit if the cooldown is over so that this request becomes the probe.
+func (c *CircuitBreakerGeocoder) allow() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch c.state {
+	case CircuitOpen:
+		if c.clock().Before(c.openedAt.Add(c.cooldown())) {
+			return circuitOpenError{}
+		}
+		c.setState(CircuitHalfOpen)
+		return nil
+	case CircuitHalfOpen:
+		// A probe is already in flight.
+		return circuitOpenError{}
+	default:
+		return nil
+	}
+}
+
+// Records the outcome of a request passed on to the provider.
+func (c *CircuitBreakerGeocoder) record(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err == nil || errors.Is(err, ErrNotFound) || errors.Is(err, ErrBadRequest) {
+		c.failures = 0
+		if c.state != CircuitClosed {
+			c.setState(CircuitClosed)
+		}
+		return
+	}
+
+	c.failures++
+	if c.state == CircuitHalfOpen || (c.state == CircuitClosed && c.failures >= c.threshold()) {
+		c.openedAt = c.clock()
+		c.setState(CircuitOpen)
+	}
+}
+
+// Moves the circuit to the passed in state, telling OnStateChange.  Must be called with mu held.
+func (c *CircuitBreakerGeocoder) setState(state CircuitState) {
+	c.state = state
+	if c.OnStateChange != nil {
+		c.OnStateChange(state)
+	}
+}
+
+func (c *CircuitBreakerGeocoder) threshold() int {
+	if c.Threshold <= 0 {
+		return DEFAULT_CIRCUIT_BREAKER_THRESHOLD
+	}
+
+	return c.Threshold
+}
+
+func (c *CircuitBreakerGeocoder) cooldown() time.Duration {
+	if c.Cooldown <= 0 {
+		return DEFAULT_CIRCUIT_BREAKER_COOLDOWN
+	}
+
+	return c.Cooldown
+}
+
+func (c *CircuitBreakerGeocoder) clock() time.Time {
+	if c.now == nil {
+		return time.Now()
+	}
+
+	return c.now()
+}
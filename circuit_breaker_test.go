@@ -0,0 +1,102 @@
+package geo
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// Ensures that the circuit opens after consecutive failures, refuses requests without asking the provider,
+// and closes again once a probe succeeds after the cooldown.
+func TestCircuitBreakerGeocoder(t *testing.T) {
+	sfo := NewPoint(37.615223, -122.389979)
+	down := &ProviderError{Provider: "google", Kind: ErrProviderDown, Status: "503 Service Unavailable"}
+	upstream := &stubGeocoder{points: map[string]*Point{"SFO": sfo}, err: down}
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var states []CircuitState
+	c := NewCircuitBreakerGeocoder(upstream, 3, time.Minute)
+	c.now = func() time.Time { return now }
+	c.OnStateChange = func(state CircuitState) { states = append(states, state) }
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.Geocode("SFO"); !errors.Is(err, ErrProviderDown) || errors.Is(err, ErrCircuitOpen) {
+			t.Errorf("Expected the provider's error while closed, got %v", err)
+		}
+	}
+
+	if c.State() != CircuitOpen {
+		t.Errorf("Expected the circuit to open after 3 failures, got %v", c.State())
+	}
+
+	_, err := c.Geocode("SFO")
+	if !errors.Is(err, ErrCircuitOpen) || !errors.Is(err, ErrProviderDown) || upstream.calls != 3 {
+		t.Errorf("Expected the open circuit to refuse without asking the provider, got %v after %d calls", err, upstream.calls)
+	}
+
+	// A failed probe reopens the circuit for another cooldown.
+	now = now.Add(time.Minute)
+	if _, err := c.Geocode("SFO"); errors.Is(err, ErrCircuitOpen) || upstream.calls != 4 {
+		t.Errorf("Expected a probe once the cooldown is over, got %v after %d calls", err, upstream.calls)
+	}
+
+	if c.State() != CircuitOpen {
+		t.Errorf("Expected a failed probe to reopen the circuit, got %v", c.State())
+	}
+
+	upstream.err = nil
+	now = now.Add(time.Minute)
+	if p, err := c.Geocode("SFO"); err != nil || p != sfo {
+		t.Errorf("Expected the probe to succeed, got %v (%v)", p, err)
+	}
+
+	if c.State() != CircuitClosed {
+		t.Errorf("Expected a successful probe to close the circuit, got %v", c.State())
+	}
+
+	expected := []CircuitState{CircuitOpen, CircuitHalfOpen, CircuitOpen, CircuitHalfOpen, CircuitClosed}
+	if len(states) != len(expected) {
+		t.Fatalf("Expected state changes %v, got %v", expected, states)
+	}
+	for i := range expected {
+		if states[i] != expected[i] {
+			t.Errorf("Expected state changes %v, got %v", expected, states)
+			break
+		}
+	}
+}
+
+// Ensures that a provider that answers, even with nothing, keeps the circuit closed.
+func TestCircuitBreakerGeocoderNotFound(t *testing.T) {
+	upstream := &stubGeocoder{}
+	c := NewCircuitBreakerGeocoder(upstream, 2, time.Minute)
+
+	for i := 0; i < 5; i++ {
+		if _, err := c.Geocode("Atlantis"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected %v, got %v", ErrNotFound, err)
+		}
+	}
+
+	if c.State() != CircuitClosed || upstream.calls != 5 {
+		t.Errorf("Expected the circuit to stay closed, got %v after %d calls", c.State(), upstream.calls)
+	}
+}
+
+// Ensures that circuit breakers let a RankingGeocoder skip a dead provider.
+func TestCircuitBreakerGeocoderRanking(t *testing.T) {
+	sfo := NewPoint(37.615223, -122.389979)
+	dead := &stubGeocoder{err: &ProviderError{Provider: "mapquest", Kind: ErrProviderDown, Status: "unreachable"}}
+	alive := &stubGeocoder{points: map[string]*Point{"SFO": sfo}}
+	breaker := NewCircuitBreakerGeocoder(dead, 1, time.Minute)
+
+	g := NewRankingGeocoder(nil, breaker, NewCircuitBreakerGeocoder(alive, 1, time.Minute))
+	for i := 0; i < 3; i++ {
+		if p, err := g.Geocode("SFO"); err != nil || p != sfo {
+			t.Errorf("Expected the live provider's result, got %v (%v)", p, err)
+		}
+	}
+
+	if dead.calls != 1 || breaker.State() != CircuitOpen {
+		t.Errorf("Expected the dead provider to be asked once, got %d calls", dead.calls)
+	}
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bufio"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"github.com/kellydunn/golang-geo"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// A PointSink that must be closed once every point has been written,
+// so that it can finish off its document.
+type pointWriter interface {
+	geo.PointSink
+	Close() error
+}
+
+// Reads points in one format from stdin and writes them to stdout in another.
+func convertCommand(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	fs := flag.NewFlagSet("convert", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	from := fs.String("from", "csv", "input format: csv, geojson, wkt or gpx")
+	to := fs.String("to", "geojson", "output format: csv, geojson, wkt or gpx")
+	precision := fs.Int("precision", geo.DEFAULT_COORDINATE_PRECISION, "decimal places of output coordinates, or -1 for all of them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	src, err := newPointReader(*from, stdin)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(stdout)
+	dst, err := newPointWriter(*to, w, *precision)
+	if err != nil {
+		return err
+	}
+
+	if _, err := geo.CopyPoints(dst, src); err != nil {
+		return err
+	}
+
+	if err := dst.Close(); err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
+
+// Returns a PointSource reading the passed in format from r.
+func newPointReader(format string, r io.Reader) (geo.PointSource, error) {
+	switch format {
+	case "csv":
+		return geo.NewCSVPointSource(r)
+	case "geojson":
+		return geo.NewGeoJSONPointSource(r), nil
+	case "wkt":
+		return &wktReader{scanner: bufio.NewScanner(r)}, nil
+	case "gpx":
+		return &gpxReader{decoder: xml.NewDecoder(r)}, nil
+	default:
+		return nil, fmt.Errorf("unknown input format %q", format)
+	}
+}
+
+// Returns a pointWriter writing the passed in format to w, with coordinates rounded
+// to the passed in number of decimal places.
+func newPointWriter(format string, w io.Writer, precision int) (pointWriter, error) {
+	switch format {
+	case "csv":
+		sink := geo.NewCSVPointSink(w)
+		sink.SetPrecision(precision)
+		return &csvWriter{sink}, nil
+	case "geojson":
+		return &geoJSONWriter{w: w, precision: precision}, nil
+	case "wkt":
+		return &wktWriter{w: w, precision: precision}, nil
+	case "gpx":
+		return &gpxWriter{w: w, precision: precision}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// Writes points as CSV, flushing them when closed.
+type csvWriter struct {
+	*geo.CSVPointSink
+}
+
+func (c *csvWriter) Close() error {
+	return c.Flush()
+}
+
+// Writes points as the features of a GeoJSON FeatureCollection, one at a time.
+type geoJSONWriter struct {
+	w         io.Writer
+	precision int
+	count     int
+}
+
+func (g *geoJSONWriter) Write(p *geo.Point) error {
+	data, err := geo.NewFeature(p).MarshalGeoJSON(g.precision)
+	if err != nil {
+		return err
+	}
+
+	prefix := ","
+	if g.count == 0 {
+		prefix = `{"type":"FeatureCollection","features":[`
+	}
+	g.count++
+
+	_, err = fmt.Fprintf(g.w, "%s\n%s", prefix, data)
+	return err
+}
+
+func (g *geoJSONWriter) Close() error {
+	if g.count == 0 {
+		_, err := io.WriteString(g.w, `{"type":"FeatureCollection","features":[]}`+"\n")
+		return err
+	}
+
+	_, err := io.WriteString(g.w, "\n]}\n")
+	return err
+}
+
+// Reads points written as WKT, one POINT per line.  Blank lines are skipped.
+type wktReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (r *wktReader) Next() (*geo.Point, error) {
+	for r.scanner.Scan() {
+		r.line++
+		text := strings.TrimSpace(r.scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		p, err := parseWKTPoint(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", r.line, err)
+		}
+		return p, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, io.EOF
+}
+
+// Parses a WKT point such as "POINT (lng lat)".
+func parseWKTPoint(s string) (*geo.Point, error) {
+	upper := strings.ToUpper(s)
+	if !strings.HasPrefix(upper, "POINT") {
+		return nil, fmt.Errorf("expected a WKT POINT, got %q", s)
+	}
+
+	body := strings.TrimSpace(s[len("POINT"):])
+	if !strings.HasPrefix(body, "(") || !strings.HasSuffix(body, ")") {
+		return nil, fmt.Errorf("malformed WKT POINT %q", s)
+	}
+
+	fields := strings.Fields(body[1 : len(body)-1])
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("malformed WKT POINT %q", s)
+	}
+
+	lng, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid longitude in %q", s)
+	}
+
+	lat, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid latitude in %q", s)
+	}
+
+	return geo.NewPoint(lat, lng), nil
+}
+
+// Writes points as WKT, one POINT per line.
+type wktWriter struct {
+	w         io.Writer
+	precision int
+}
+
+func (w *wktWriter) Write(p *geo.Point) error {
+	_, err := fmt.Fprintf(w.w, "POINT (%s %s)\n", geo.FormatCoordinate(p.Lng(), w.precision), geo.FormatCoordinate(p.Lat(), w.precision))
+	return err
+}
+
+func (w *wktWriter) Close() error {
+	return nil
+}
+
+// Reads the waypoints, track points and route points of a GPX document, in document order.
+type gpxReader struct {
+	decoder *xml.Decoder
+}
+
+func (r *gpxReader) Next() (*geo.Point, error) {
+	for {
+		token, err := r.decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "wpt", "trkpt", "rtept":
+		default:
+			continue
+		}
+
+		var lat, lng string
+		for _, attr := range start.Attr {
+			switch attr.Name.Local {
+			case "lat":
+				lat = attr.Value
+			case "lon":
+				lng = attr.Value
+			}
+		}
+
+		return parseLatLng(lat + "," + lng)
+	}
+}
+
+// Writes points as the waypoints of a GPX document.
+type gpxWriter struct {
+	w         io.Writer
+	precision int
+	started   bool
+}
+
+const gpxHeader = xml.Header + `<gpx version="1.1" creator="golang-geo" xmlns="http://www.topografix.com/GPX/1/1">` + "\n"
+
+func (g *gpxWriter) Write(p *geo.Point) error {
+	if !g.started {
+		if _, err := io.WriteString(g.w, gpxHeader); err != nil {
+			return err
+		}
+		g.started = true
+	}
+
+	_, err := fmt.Fprintf(g.w, "  <wpt lat=\"%s\" lon=\"%s\"></wpt>\n", geo.FormatCoordinate(p.Lat(), g.precision), geo.FormatCoordinate(p.Lng(), g.precision))
+	return err
+}
+
+func (g *gpxWriter) Close() error {
+	if !g.started {
+		if _, err := io.WriteString(g.w, gpxHeader); err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(g.w, "</gpx>\n")
+	return err
+}
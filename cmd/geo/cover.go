@@ -0,0 +1,159 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+	"github.com/kellydunn/golang-geo"
+	"io"
+	"math"
+)
+
+// The most cells cover will print, to guard against a radius far too large for the precision.
+const maxCoverCells = 100000
+
+// Prints the cells of the chosen scheme that cover a circle around the point given as an argument,
+// one per line.  Without a radius, prints the single cell containing the point.
+func coverCommand(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	fs := flag.NewFlagSet("cover", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	scheme := fs.String("scheme", "geohash", "cell scheme: geohash, s2 or h3")
+	precision := fs.Int("precision", 6, "geohash length, s2 cell level or h3 resolution")
+	radius := fs.Float64("radius", 0, "radius of the circle to cover, in kilometers")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("expected a single LAT,LNG to cover")
+	}
+
+	center, err := parseLatLng(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if *radius < 0 {
+		return fmt.Errorf("radius must not be negative")
+	}
+
+	var cells []string
+	switch *scheme {
+	case "geohash":
+		cells, err = geohashCover(center, *radius, *precision)
+	case "s2":
+		cells, err = s2Cover(center, *radius, *precision)
+	case "h3":
+		cells, err = h3Cover(center, *radius, *precision)
+	default:
+		err = fmt.Errorf("unknown scheme %q", *scheme)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for _, cell := range cells {
+		if _, err := fmt.Fprintln(stdout, cell); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Returns the geohashes of the passed in precision with any part within km kilometers of center.
+func geohashCover(center *geo.Point, km float64, precision int) ([]string, error) {
+	if precision < 1 || precision > geo.MAX_GEOHASH_PRECISION {
+		return nil, fmt.Errorf("geohash precision must be between 1 and %d", geo.MAX_GEOHASH_PRECISION)
+	}
+
+	hash := center.Geohash(precision)
+	if km == 0 {
+		return []string{hash}, nil
+	}
+
+	minLat, minLng, maxLat, maxLng, err := geo.DecodeGeohashBounds(hash)
+	if err != nil {
+		return nil, err
+	}
+	height, width := maxLat-minLat, maxLng-minLng
+
+	// The latitude and longitude spanned by the circle, widening to every
+	// longitude when the circle reaches a pole.
+	dLat := km / geo.EARTH_RADIUS * 180 / math.Pi
+	south, north := math.Max(center.Lat()-dLat, -90), math.Min(center.Lat()+dLat, 90)
+	west, east := -180.0, 180.0
+	if south > -90 && north < 90 {
+		dLng := math.Asin(math.Min(math.Sin(km/geo.EARTH_RADIUS)/math.Cos(center.Lat()*math.Pi/180), 1)) * 180 / math.Pi
+		if dLng < 180 {
+			west, east = center.Lng()-dLng, center.Lng()+dLng
+		}
+	}
+
+	rows := math.Floor((north+90)/height) - math.Floor((south+90)/height) + 1
+	cols := math.Min(math.Floor((east+180)/width)-math.Floor((west+180)/width)+1, 360/width)
+	if rows*cols > maxCoverCells {
+		return nil, fmt.Errorf("covering would need more than %d cells; lower the precision", maxCoverCells)
+	}
+
+	var cells []string
+	seen := make(map[string]bool)
+	for row := 0; row < int(rows); row++ {
+		lat := (math.Floor((south+90)/height)+float64(row)+0.5)*height - 90
+		for col := 0; col < int(cols); col++ {
+			lng := (math.Floor((west+180)/width)+float64(col)+0.5)*width - 180
+			cell := geo.EncodeGeohash(lat, lng, precision)
+			if seen[cell] {
+				continue
+			}
+			seen[cell] = true
+
+			if b, _ := geo.GeohashBounds(cell); nearestInBounds(center, b).GreatCircleDistance(center) <= km {
+				cells = append(cells, cell)
+			}
+		}
+	}
+
+	return cells, nil
+}
+
+// Returns the point of the passed in Bounds nearest to p, measured along the
+// meridian and the parallel.
+func nearestInBounds(p *geo.Point, b *geo.Bounds) *geo.Point {
+	sw, ne := b.SouthWest(), b.NorthEast()
+	lat := math.Max(sw.Lat(), math.Min(p.Lat(), ne.Lat()))
+	if b.Contains(geo.NewPoint(sw.Lat(), p.Lng())) {
+		return geo.NewPoint(lat, p.Lng())
+	}
+
+	if math.Abs(geo.LngDiff(p.Lng(), sw.Lng())) < math.Abs(geo.LngDiff(p.Lng(), ne.Lng())) {
+		return geo.NewPoint(lat, sw.Lng())
+	}
+
+	return geo.NewPoint(lat, ne.Lng())
+}
+
+// Returns the tokens of the s2 cells of the passed in level covering a cap of km kilometers around center.
+func s2Cover(center *geo.Point, km float64, level int) ([]string, error) {
+	if level < 0 || level > s2.MaxLevel {
+		return nil, fmt.Errorf("s2 level must be between 0 and %d", s2.MaxLevel)
+	}
+
+	ll := s2.LatLngFromDegrees(center.Lat(), center.Lng())
+	if km == 0 {
+		return []string{s2.CellIDFromLatLng(ll).Parent(level).ToToken()}, nil
+	}
+
+	angle := s1.Angle(math.Min(km/geo.EARTH_RADIUS, math.Pi))
+	coverer := &s2.RegionCoverer{MinLevel: level, MaxLevel: level, MaxCells: maxCoverCells}
+	covering := coverer.Covering(s2.CapFromCenterAngle(s2.PointFromLatLng(ll), angle))
+
+	cells := make([]string, len(covering))
+	for i, id := range covering {
+		cells[i] = id.ToToken()
+	}
+
+	return cells, nil
+}
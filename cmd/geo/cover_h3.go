@@ -0,0 +1,60 @@
+//go:build cgo
+
+package main
+
+import (
+	"fmt"
+	"github.com/kellydunn/golang-geo"
+	"github.com/uber/h3-go/v4"
+)
+
+// Returns the indexes of the h3 cells of the passed in resolution with any part within km kilometers of center.
+func h3Cover(center *geo.Point, km float64, resolution int) ([]string, error) {
+	if resolution < 0 || resolution > h3.MaxResolution {
+		return nil, fmt.Errorf("h3 resolution must be between 0 and %d", h3.MaxResolution)
+	}
+
+	origin, err := h3.LatLngToCell(h3.NewLatLng(center.Lat(), center.Lng()), resolution)
+	if err != nil {
+		return nil, err
+	}
+
+	// The cells reaching into the circle are connected, as the circle is, so they are found by
+	// spreading out from the center's cell to the neighbors of each cell that reaches it.
+	cells := []string{origin.String()}
+	seen := map[h3.Cell]bool{origin: true}
+	for queue := []h3.Cell{origin}; km > 0 && len(queue) > 0; queue = queue[1:] {
+		neighbors, err := h3.GridDisk(queue[0], 1)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, cell := range neighbors {
+			if seen[cell] || !cell.IsValid() {
+				continue
+			}
+			seen[cell] = true
+
+			boundary, err := h3.CellToBoundary(cell)
+			if err != nil {
+				return nil, err
+			}
+
+			points := make([]*geo.Point, len(boundary))
+			for i, vertex := range boundary {
+				points[i] = geo.NewPoint(vertex.Lat, vertex.Lng)
+			}
+			if geo.NewPolygon(points).DistanceTo(center) > km {
+				continue
+			}
+
+			if len(cells) == maxCoverCells {
+				return nil, fmt.Errorf("covering would need more than %d cells; lower the precision", maxCoverCells)
+			}
+			cells = append(cells, cell.String())
+			queue = append(queue, cell)
+		}
+	}
+
+	return cells, nil
+}
@@ -0,0 +1,13 @@
+//go:build !cgo
+
+package main
+
+import (
+	"fmt"
+	"github.com/kellydunn/golang-geo"
+)
+
+// Returns an error, as h3's Go bindings wrap its C library and so need a build with cgo.
+func h3Cover(center *geo.Point, km float64, resolution int) ([]string, error) {
+	return nil, fmt.Errorf("h3 needs geo to be built with cgo enabled")
+}
@@ -0,0 +1,26 @@
+//go:build cgo
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// Ensures that cover prints the h3 cell of a point, and the cells around it within the radius.
+func TestCoverH3(t *testing.T) {
+	status, stdout, _ := runGeo("", "cover", "-scheme", "h3", "-precision", "9", "37.775938728915946,-122.41795063018799")
+	if status != 0 || stdout != "8928308280fffff\n" {
+		t.Errorf("Expected the h3 cell of the point, got %d: %q", status, stdout)
+	}
+
+	status, stdout, _ = runGeo("", "cover", "-scheme", "h3", "-precision", "9", "-radius", "0.5", "37.775938728915946,-122.41795063018799")
+	cells := strings.Fields(stdout)
+	if status != 0 || len(cells) < 7 || cells[0] != "8928308280fffff" {
+		t.Fatalf("Expected the point's cell and the cells around it, got %d: %q", status, stdout)
+	}
+
+	if status, _, stderr := runGeo("", "cover", "-scheme", "h3", "-precision", "16", "0,0"); status != 1 || !strings.Contains(stderr, "resolution") {
+		t.Errorf("Expected an invalid resolution to be refused, got %d: %q", status, stderr)
+	}
+}
@@ -0,0 +1,218 @@
+// Command geo exposes golang-geo on the command line, for use in shell pipelines.
+//
+// Usage:
+//
+//	geo geocode [-provider google|mapquest] [-key KEY] [-lang LANG] ADDRESS
+//	geo reverse [-provider google|mapquest] [-key KEY] [-lang LANG] LAT,LNG
+//	geo distance LAT,LNG LAT,LNG
+//	geo convert -from FORMAT -to FORMAT [-precision N] < INPUT > OUTPUT
+//	geo cover [-scheme geohash|s2|h3] [-precision N] [-radius KM] LAT,LNG
+//
+// Formats for convert are csv, geojson, wkt and gpx, written with 7 decimal places unless -precision
+// says otherwise; -1 writes every digit.  API keys default to the
+// GOOGLE_API_KEY and MAPQUEST_API_KEY environment variables.  The -precision of cover is a geohash
+// length, an s2 cell level or an h3 resolution; h3 needs geo to be built with cgo.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/kellydunn/golang-geo"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// Runs the subcommand named by the first of the passed in arguments,
+// and returns the exit status.
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "usage: geo geocode|reverse|distance|convert|cover [flags] [args]")
+		return 2
+	}
+
+	commands := map[string]func([]string, io.Reader, io.Writer, io.Writer) error{
+		"geocode":  geocodeCommand,
+		"reverse":  reverseCommand,
+		"distance": distanceCommand,
+		"convert":  convertCommand,
+		"cover":    coverCommand,
+	}
+
+	command, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "geo: unknown command %q\n", args[0])
+		return 2
+	}
+
+	if err := command(args[1:], stdin, stdout, stderr); err != nil {
+		fmt.Fprintf(stderr, "geo %s: %v\n", args[0], err)
+		return 1
+	}
+
+	return 0
+}
+
+// The flags shared by the commands that call a geocoding provider.
+type providerFlags struct {
+	provider string
+	key      string
+	language string
+	baseURL  string
+}
+
+// Registers the provider flags on the passed in FlagSet.
+func (p *providerFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&p.provider, "provider", "google", "geocoding provider: google or mapquest")
+	fs.StringVar(&p.key, "key", "", "API key (defaults to $GOOGLE_API_KEY or $MAPQUEST_API_KEY)")
+	fs.StringVar(&p.language, "lang", "", "language to return addresses in, e.g. en")
+	fs.StringVar(&p.baseURL, "url", "", "base URL of the provider's API, for testing")
+}
+
+// Returns the geocoder the flags describe.
+func (p *providerFlags) geocoder() (geo.Geocoder, error) {
+	opts := []geo.Option{geo.WithLanguage(p.language), geo.WithBaseURL(p.baseURL)}
+
+	switch p.provider {
+	case "google":
+		opts = append(opts, geo.WithAPIKey(firstNonEmpty(p.key, os.Getenv("GOOGLE_API_KEY"))))
+		return geo.NewGoogleGeocoder(opts...), nil
+	case "mapquest":
+		opts = append(opts, geo.WithAPIKey(firstNonEmpty(p.key, os.Getenv("MAPQUEST_API_KEY"))))
+		return geo.NewMapQuestGeocoder(opts...), nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q", p.provider)
+	}
+}
+
+// Returns the first of the passed in strings that isn't empty.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// Parses a point written as "lat,lng".
+func parseLatLng(s string) (*geo.Point, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("expected a point as LAT,LNG, got %q", s)
+	}
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid latitude in %q", s)
+	}
+
+	lng, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid longitude in %q", s)
+	}
+
+	if lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		return nil, fmt.Errorf("point %q is out of range", s)
+	}
+
+	return geo.NewPoint(lat, lng), nil
+}
+
+// Formats a point as "lat,lng".
+func formatLatLng(p *geo.Point) string {
+	return strconv.FormatFloat(p.Lat(), 'f', -1, 64) + "," + strconv.FormatFloat(p.Lng(), 'f', -1, 64)
+}
+
+// Prints the location of the address given as arguments.
+func geocodeCommand(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	fs := flag.NewFlagSet("geocode", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var p providerFlags
+	p.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		return fmt.Errorf("expected an address to geocode")
+	}
+
+	g, err := p.geocoder()
+	if err != nil {
+		return err
+	}
+
+	point, err := g.Geocode(strings.Join(fs.Args(), " "))
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(stdout, formatLatLng(point))
+	return err
+}
+
+// Prints the address of the point given as an argument.
+func reverseCommand(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	fs := flag.NewFlagSet("reverse", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var p providerFlags
+	p.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("expected a single LAT,LNG to reverse geocode")
+	}
+
+	point, err := parseLatLng(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	g, err := p.geocoder()
+	if err != nil {
+		return err
+	}
+
+	address, err := g.ReverseGeocode(point)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(stdout, address)
+	return err
+}
+
+// Prints the great circle distance, in kilometers, between the two points given as arguments.
+func distanceCommand(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	fs := flag.NewFlagSet("distance", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 2 {
+		return fmt.Errorf("expected two points as LAT,LNG LAT,LNG")
+	}
+
+	from, err := parseLatLng(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	to, err := parseLatLng(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(stdout, strconv.FormatFloat(from.GreatCircleDistance(to), 'f', 3, 64))
+	return err
+}
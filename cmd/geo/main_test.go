@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"github.com/kellydunn/golang-geo"
+	"github.com/kellydunn/golang-geo/geotest"
+	"strings"
+	"testing"
+)
+
+// Runs the geo command with the passed in arguments and stdin,
+// returning its exit status, stdout and stderr.
+func runGeo(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	status := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return status, stdout.String(), stderr.String()
+}
+
+// Ensures that unknown and missing commands are reported with a usage error.
+func TestRunUsage(t *testing.T) {
+	if status, _, _ := runGeo(""); status != 2 {
+		t.Errorf("Expected status 2 without a command, got %d", status)
+	}
+
+	if status, _, stderr := runGeo("", "teleport"); status != 2 || !strings.Contains(stderr, "teleport") {
+		t.Errorf("Expected status 2 naming the unknown command, got %d: %q", status, stderr)
+	}
+
+	if status, _, stderr := runGeo("", "cover", "-teleport", "0,0"); status != 1 || !strings.Contains(stderr, "-scheme") {
+		t.Errorf("Expected the cover flags to be printed to stderr, got %d: %q", status, stderr)
+	}
+}
+
+// Ensures that geocode and reverse print the results of the chosen provider.
+func TestGeocodeAndReverse(t *testing.T) {
+	s := geotest.NewGoogleServer()
+	defer s.Close()
+
+	sfo := geo.NewPoint(37.615223, -122.389979)
+	s.AddGeocode("San Francisco International Airport", sfo, "San Francisco International Airport, CA, USA")
+	s.AddReverseGeocode(sfo, "San Francisco International Airport, CA, USA")
+
+	status, stdout, stderr := runGeo("", "geocode", "-url", s.URL, "San", "Francisco", "International", "Airport")
+	if status != 0 || stdout != "37.615223,-122.389979\n" {
+		t.Errorf("Expected the airport's location, got %d: %q %q", status, stdout, stderr)
+	}
+
+	status, stdout, stderr = runGeo("", "reverse", "-url", s.URL, "37.615223,-122.389979")
+	if status != 0 || stdout != "San Francisco International Airport, CA, USA\n" {
+		t.Errorf("Expected the airport's address, got %d: %q %q", status, stdout, stderr)
+	}
+
+	if status, _, _ := runGeo("", "geocode", "-url", s.URL, "nowhere"); status != 1 {
+		t.Errorf("Expected status 1 for an address with no results, got %d", status)
+	}
+
+	if status, _, _ := runGeo("", "reverse", "-provider", "bing", "1,2"); status != 1 {
+		t.Errorf("Expected status 1 for an unknown provider, got %d", status)
+	}
+}
+
+// Ensures that distance prints the great circle distance in kilometers.
+func TestDistance(t *testing.T) {
+	status, stdout, _ := runGeo("", "distance", "0,0", "0,1")
+	if status != 0 || stdout != "111.195\n" {
+		t.Errorf("Expected 111.195, got %d: %q", status, stdout)
+	}
+
+	if status, _, _ := runGeo("", "distance", "0,0", "91,0"); status != 1 {
+		t.Errorf("Expected status 1 for an out of range point, got %d", status)
+	}
+}
+
+// Ensures that points survive conversion between every pair of formats.
+func TestConvert(t *testing.T) {
+	csv := "lat,lng\n37.615223,-122.389979\n-12.0219,-77.1143\n"
+	formats := []string{"csv", "geojson", "wkt", "gpx"}
+
+	for _, via := range formats {
+		status, converted, stderr := runGeo(csv, "convert", "-from", "csv", "-to", via)
+		if status != 0 {
+			t.Fatalf("Expected csv to convert to %s, got %q", via, stderr)
+		}
+
+		for _, to := range formats {
+			status, stdout, stderr := runGeo(converted, "convert", "-from", via, "-to", to)
+			if status != 0 {
+				t.Fatalf("Expected %s to convert to %s, got %q", via, to, stderr)
+			}
+
+			status, back, _ := runGeo(stdout, "convert", "-from", to, "-to", "csv")
+			if status != 0 || back != csv {
+				t.Errorf("Expected csv -> %s -> %s -> csv to round trip, got %q", via, to, back)
+			}
+		}
+	}
+
+	if status, _, _ := runGeo("POINT (1)\n", "convert", "-from", "wkt", "-to", "csv"); status != 1 {
+		t.Errorf("Expected status 1 for malformed WKT, got %d", status)
+	}
+
+	if status, _, _ := runGeo("", "convert", "-from", "shp"); status != 1 {
+		t.Errorf("Expected status 1 for an unknown format, got %d", status)
+	}
+}
+
+// Ensures that cover prints the cells around a point for each supported scheme.
+func TestCover(t *testing.T) {
+	status, stdout, _ := runGeo("", "cover", "-precision", "5", "37.615223,-122.389979")
+	if status != 0 || stdout != "9q8vy\n" {
+		t.Errorf("Expected the geohash of the point, got %d: %q", status, stdout)
+	}
+
+	status, stdout, _ = runGeo("", "cover", "-precision", "5", "-radius", "3", "37.615223,-122.389979")
+	cells := strings.Fields(stdout)
+	if status != 0 || len(cells) < 2 || cells[0] == "" {
+		t.Fatalf("Expected several geohashes, got %d: %q", status, stdout)
+	}
+
+	found := false
+	for _, cell := range cells {
+		found = found || cell == "9q8vy"
+	}
+	if !found {
+		t.Errorf("Expected the covering to include the point's own cell, got %v", cells)
+	}
+
+	status, stdout, _ = runGeo("", "cover", "-scheme", "s2", "-precision", "10", "-radius", "3", "37.615223,-122.389979")
+	if status != 0 || len(strings.Fields(stdout)) == 0 {
+		t.Errorf("Expected s2 cell tokens, got %d: %q", status, stdout)
+	}
+
+	if status, _, stderr := runGeo("", "cover", "-scheme", "hilbert", "0,0"); status != 1 || !strings.Contains(stderr, "unknown scheme") {
+		t.Errorf("Expected an unknown scheme to be reported, got %d: %q", status, stderr)
+	}
+}
@@ -0,0 +1,98 @@
+package geo
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// This is the error that callers waiting on a coalesced request receive
+// if the caller that issued it panicked before it could complete.
+var errCoalescedRequestAborted = errors.New("coalesced request aborted")
+
+// A single upstream request that any number of callers may be waiting on.
+type inflightRequest struct {
+	wg      sync.WaitGroup
+	point   *Point
+	address string
+	err     error
+
+	// The number of callers that joined this request rather than issuing their own.
+	dups int
+}
+
+// A Geocoder that coalesces concurrent identical queries so that they
+// produce only one request to the wrapped Geocoder.  Queries are considered
+// identical if they normalize to the same string (see NormalizeQuery)
+// and have the same QueryOptions.
+// This is useful in bursty web handlers, where many clients tend to ask
+// for the same thing at the same time.  The zero value is ready to use
+// once Geocoder is set.
+type CoalescingGeocoder struct {
+	Geocoder Geocoder
+
+	mu       sync.Mutex
+	inflight map[string]*inflightRequest
+}
+
+// Geocodes the passed in query, sharing the result with any concurrent
+// callers asking for the same query.
+func (c *CoalescingGeocoder) Geocode(query string, opts ...QueryOption) (*Point, error) {
+	req := c.do(withQueryKey(geocodeCacheKey(query), opts), func(req *inflightRequest) {
+		req.point, req.err = c.Geocoder.Geocode(query, opts...)
+	})
+
+	if req.err != nil || req.point == nil {
+		return nil, req.err
+	}
+
+	// Each caller gets its own copy so that nobody can
+	// modify a Point out from under another caller.
+	return NewPoint(req.point.lat, req.point.lng), nil
+}
+
+// Reverse geocodes the passed in Point, sharing the result with any
+// concurrent callers asking for the same Point.
+func (c *CoalescingGeocoder) ReverseGeocode(p *Point, opts ...QueryOption) (string, error) {
+	req := c.do(withQueryKey(reverseCacheKey(p), opts), func(req *inflightRequest) {
+		req.address, req.err = c.Geocoder.ReverseGeocode(p, opts...)
+	})
+
+	return req.address, req.err
+}
+
+// Returns the result of the wrapped Geocoder's HealthCheck, if it has one.
+func (c *CoalescingGeocoder) HealthCheck(ctx context.Context) error {
+	return healthCheck(ctx, c.Geocoder)
+}
+
+// Runs fn for the passed in key unless a request for that key is already
+// in flight, in which case it waits for that request to finish instead.
+func (c *CoalescingGeocoder) do(key string, fn func(req *inflightRequest)) *inflightRequest {
+	c.mu.Lock()
+	if c.inflight == nil {
+		c.inflight = make(map[string]*inflightRequest)
+	}
+
+	if req, ok := c.inflight[key]; ok {
+		req.dups++
+		c.mu.Unlock()
+		req.wg.Wait()
+		return req
+	}
+
+	req := &inflightRequest{err: errCoalescedRequestAborted}
+	req.wg.Add(1)
+	c.inflight[key] = req
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.inflight, key)
+		c.mu.Unlock()
+		req.wg.Done()
+	}()
+
+	fn(req)
+	return req
+}
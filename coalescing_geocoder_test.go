@@ -0,0 +1,112 @@
+package geo
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// A Geocoder that blocks every request until released.
+type blockingGeocoder struct {
+	release chan struct{}
+	calls   int32
+}
+
+func (b *blockingGeocoder) Geocode(query string, opts ...QueryOption) (*Point, error) {
+	atomic.AddInt32(&b.calls, 1)
+	<-b.release
+	return NewPoint(37.615223, -122.389979), nil
+}
+
+func (b *blockingGeocoder) ReverseGeocode(p *Point, opts ...QueryOption) (string, error) {
+	atomic.AddInt32(&b.calls, 1)
+	<-b.release
+	return "San Francisco Airport", nil
+}
+
+// Waits until n callers have joined the in-flight request for key.
+func waitForDups(t *testing.T, c *CoalescingGeocoder, key string, n int) {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		c.mu.Lock()
+		req, ok := c.inflight[key]
+		joined := ok && req.dups == n
+		c.mu.Unlock()
+
+		if joined {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Fatalf("Timed out waiting for %d callers to join %q", n, key)
+}
+
+// Ensures that concurrent identical queries produce a single upstream request.
+func TestCoalescingGeocoderGeocode(t *testing.T) {
+	upstream := &blockingGeocoder{release: make(chan struct{})}
+	c := &CoalescingGeocoder{Geocoder: upstream}
+
+	queries := []string{"SFO", "sfo", " Sfo", "SFO ", "sFO"}
+	points := make([]*Point, len(queries))
+
+	wg := sync.WaitGroup{}
+	for i, q := range queries {
+		wg.Add(1)
+		go func(i int, q string) {
+			defer wg.Done()
+			p, err := c.Geocode(q)
+			if err != nil {
+				t.Error(err)
+			}
+			points[i] = p
+		}(i, q)
+	}
+
+	waitForDups(t, c, geocodeCacheKey("sfo"), len(queries)-1)
+	close(upstream.release)
+	wg.Wait()
+
+	if upstream.calls != 1 {
+		t.Errorf("Expected 1 upstream call, got %d", upstream.calls)
+	}
+
+	for i, p := range points {
+		if p == nil || p.Lat() != 37.615223 {
+			t.Errorf("Expected caller %d to receive the shared result, got %v", i, p)
+		}
+
+		if i > 0 && p == points[0] {
+			t.Error("Expected each caller to receive its own copy of the Point")
+		}
+	}
+
+	if len(c.inflight) != 0 {
+		t.Errorf("Expected no requests to remain in flight, got %d", len(c.inflight))
+	}
+}
+
+// Ensures that distinct queries are not coalesced, and that later
+// queries issue a fresh request once the first has completed.
+func TestCoalescingGeocoderDistinctQueries(t *testing.T) {
+	upstream := &blockingGeocoder{release: make(chan struct{})}
+	close(upstream.release)
+	c := &CoalescingGeocoder{Geocoder: upstream}
+
+	c.Geocode("SFO")
+	c.Geocode("SFO")
+	c.ReverseGeocode(NewPoint(37.615223, -122.389979))
+
+	if upstream.calls != 3 {
+		t.Errorf("Expected 3 upstream calls for sequential requests, got %d", upstream.calls)
+	}
+}
+
+// Ensures that a wrapped Geocoder answering with neither a point nor an error is passed through, rather than panicking.
+func TestCoalescingGeocoderNilPoint(t *testing.T) {
+	c := &CoalescingGeocoder{Geocoder: &stubGeocoder{points: map[string]*Point{"Nowhere": nil}}}
+	if p, err := c.Geocode("Nowhere"); p != nil || err != nil {
+		t.Errorf("Expected no point and no error, got %v (%v)", p, err)
+	}
+}
@@ -0,0 +1,101 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync/atomic"
+)
+
+// This is the error that consumers can compare against with errors.Is when coordinates are NaN, infinite,
+// beyond ±90° latitude or ±180° longitude, or on Null Island, or a point is missing altogether.
+var ErrInvalidCoordinates = errors.New("invalid coordinates")
+
+// This is the error that consumers can compare against with errors.Is when coordinates are exactly (0, 0),
+// "Null Island", where missing coordinates usually end up.  Also matches ErrInvalidCoordinates.
+var ErrNullIsland = errors.New("coordinates on null island")
+
+// Describes coordinates that failed validation.  Matches ErrInvalidCoordinates when used with errors.Is,
+// and ErrNullIsland too if they are at (0, 0).
+type CoordinateError struct {
+	Lat, Lng float64
+
+	// Why the coordinates are invalid, e.g. "latitude is NaN".
+	Reason string
+}
+
+func (e *CoordinateError) Error() string {
+	return fmt.Sprintf("%v (%v, %v): %s", ErrInvalidCoordinates, e.Lat, e.Lng, e.Reason)
+}
+
+// Allows errors.Is(err, ErrInvalidCoordinates), and for (0, 0) errors.Is(err, ErrNullIsland), to succeed.
+func (e *CoordinateError) Is(target error) bool {
+	return target == ErrInvalidCoordinates || target == ErrNullIsland && e.Lat == 0 && e.Lng == 0
+}
+
+// Returns a *CoordinateError if the passed in latitude or longitude is NaN, infinite or out of range,
+// or if they are exactly (0, 0), or nil if they are valid.
+func ValidateCoordinates(lat, lng float64) error {
+	reason := ""
+	switch {
+	case math.IsNaN(lat) || math.IsNaN(lng):
+		reason = "coordinate is NaN"
+	case math.IsInf(lat, 0) || math.IsInf(lng, 0):
+		reason = "coordinate is infinite"
+	case lat < -90 || lat > 90:
+		reason = "latitude beyond ±90°"
+	case lng < -180 || lng > 180:
+		reason = "longitude beyond ±180°"
+	case lat == 0 && lng == 0:
+		reason = "null island"
+	default:
+		return nil
+	}
+
+	return &CoordinateError{Lat: lat, Lng: lng, Reason: reason}
+}
+
+// Returns a *CoordinateError if the current Point is nil or its coordinates fail ValidateCoordinates,
+// or nil if it is valid.
+func (p *Point) Validate() error {
+	if p == nil {
+		return &CoordinateError{Lat: math.NaN(), Lng: math.NaN(), Reason: "missing point"}
+	}
+
+	return ValidateCoordinates(p.lat, p.lng)
+}
+
+// Returns a new Point at the passed in latitude and longitude, or a *CoordinateError
+// if they fail ValidateCoordinates.
+func NewValidPoint(lat float64, lng float64) (*Point, error) {
+	if err := ValidateCoordinates(lat, lng); err != nil {
+		return nil, err
+	}
+
+	return NewPoint(lat, lng), nil
+}
+
+// Whether or not strict validation is on.
+var strictValidation atomic.Bool
+
+// Turns strict validation on or off for the whole package.  With it on, points that fail Validate,
+// including those on Null Island, are refused with a *CoordinateError wherever they cross a boundary:
+// by Point's JSON encoding and decoding, by ParsePoint, and by SQLMapper's writes and its queries around a point.
+// Strict validation is off unless turned on, since (0, 0) is a valid, if unlikely, position.
+func SetStrictValidation(on bool) {
+	strictValidation.Store(on)
+}
+
+// Returns whether or not strict validation is on.
+func StrictValidation() bool {
+	return strictValidation.Load()
+}
+
+// Returns the error Validate returns for the passed in point if strict validation is on, or nil otherwise.
+func checkStrict(p *Point) error {
+	if !StrictValidation() {
+		return nil
+	}
+
+	return p.Validate()
+}
@@ -0,0 +1,71 @@
+package geo
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+// Ensures that NaN, infinite, out of range and Null Island coordinates are refused, and others aren't.
+func TestValidateCoordinates(t *testing.T) {
+	invalid := [][2]float64{{math.NaN(), 0}, {0, math.Inf(1)}, {90.5, 0}, {0, -180.5}, {0, 0}}
+	for _, c := range invalid {
+		if _, err := NewValidPoint(c[0], c[1]); !errors.Is(err, ErrInvalidCoordinates) {
+			t.Errorf("Expected %v for %v, got %v", ErrInvalidCoordinates, c, err)
+		}
+	}
+
+	if err := ValidateCoordinates(0, 0); !errors.Is(err, ErrNullIsland) {
+		t.Errorf("Expected %v, got %v", ErrNullIsland, err)
+	}
+
+	if err := ValidateCoordinates(90.5, 0); errors.Is(err, ErrNullIsland) {
+		t.Errorf("Expected only Null Island to match %v, got %v", ErrNullIsland, err)
+	}
+
+	var missing *Point
+	if err := missing.Validate(); !errors.Is(err, ErrInvalidCoordinates) {
+		t.Errorf("Expected %v for a nil point, got %v", ErrInvalidCoordinates, err)
+	}
+
+	if p, err := NewValidPoint(0, 12.5); err != nil || p.Lng() != 12.5 {
+		t.Errorf("Expected the equator to be valid, got %v (%v)", p, err)
+	}
+}
+
+// Ensures that strict validation refuses invalid points wherever they cross a boundary, and only then.
+func TestStrictValidation(t *testing.T) {
+	if _, err := json.Marshal(NewPoint(0, 0)); err != nil {
+		t.Errorf("Expected Null Island to be encoded without strict validation, got %v", err)
+	}
+
+	defer SetStrictValidation(false)
+	SetStrictValidation(true)
+
+	if _, err := json.Marshal(NewPoint(0, 0)); !errors.Is(err, ErrNullIsland) {
+		t.Errorf("Expected %v encoding Null Island, got %v", ErrNullIsland, err)
+	}
+
+	p := &Point{}
+	if err := json.Unmarshal([]byte(`{"lat":0,"lng":0}`), p); !errors.Is(err, ErrNullIsland) {
+		t.Errorf("Expected %v decoding Null Island, got %v", ErrNullIsland, err)
+	}
+
+	if _, err := ParsePoint("0,0"); !errors.Is(err, ErrNullIsland) {
+		t.Errorf("Expected %v parsing 0,0, got %v", ErrNullIsland, err)
+	}
+
+	if _, err := ParsePoint("37.42,-122.08"); err != nil {
+		t.Errorf("Expected valid points to parse, got %v", err)
+	}
+
+	s := &SQLMapper{}
+	if _, err := s.InsertPoint(NewPoint(math.NaN(), 1)); !errors.Is(err, ErrInvalidCoordinates) {
+		t.Errorf("Expected %v inserting NaN, got %v", ErrInvalidCoordinates, err)
+	}
+
+	if err := s.InsertPoints([]*Point{NewPoint(1, 2), nil}); !errors.Is(err, ErrInvalidCoordinates) {
+		t.Errorf("Expected %v inserting a nil point, got %v", ErrInvalidCoordinates, err)
+	}
+}
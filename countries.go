@@ -0,0 +1,424 @@
+package geo
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// A Country describes a country or territory with an ISO 3166-1 code.
+type Country struct {
+	// The ISO 3166-1 alpha-2 and alpha-3 codes, e.g. "US" and "USA".
+	Alpha2 string
+	Alpha3 string
+
+	// The ISO 3166-1 numeric code, e.g. 840, or zero for Kosovo, which has none.
+	Numeric int
+
+	// The English short name, e.g. "United States".
+	Name string
+
+	// The international calling codes, e.g. "+1".  Territories within a shared
+	// numbering plan have the most specific code dialled from abroad, e.g. "+1268".
+	CallingCodes []string
+
+	// The box around the country's land, including its overseas parts, so that
+	// France's reaches French Guiana.  Where a country crosses the antimeridian,
+	// its south west corner lies east of its north east corner.
+	Bounds *Bounds
+
+	// The center of the country's largest landmass.
+	Centroid *Point
+}
+
+// A row of the country table, as it is written in source.
+type countryRecord struct {
+	alpha2, alpha3                 string
+	numeric                        int
+	name                           string
+	callingCodes                   []string
+	minLat, minLng, maxLat, maxLng float64
+	centroidLat, centroidLng       float64
+}
+
+var (
+	countriesOnce  sync.Once
+	countryList    []*Country
+	countryByCode  map[string]*Country
+	countryByName  map[string]*Country
+	countryAliases = map[string]string{
+		"UK":                       "GB",
+		"EL":                       "GR",
+		"England":                  "GB",
+		"Scotland":                 "GB",
+		"Wales":                    "GB",
+		"Great Britain":            "GB",
+		"Britain":                  "GB",
+		"United States of America": "US",
+		"Russian Federation":       "RU",
+		"Republic of Korea":        "KR",
+		"Czech Republic":           "CZ",
+		"Holland":                  "NL",
+		"The Netherlands":          "NL",
+		"Turkey":                   "TR",
+		"Ivory Coast":              "CI",
+		"Swaziland":                "SZ",
+		"Macedonia":                "MK",
+		"Burma":                    "MM",
+		"Vatican":                  "VA",
+		"Cape Verde":               "CV",
+		"East Timor":               "TL",
+		"Viet Nam":                 "VN",
+		"DR Congo":                 "CD",
+		"DRC":                      "CD",
+	}
+)
+
+// Builds the country indexes from the country table.
+func loadCountries() {
+	countryList = make([]*Country, len(countryRecords))
+	countryByCode = make(map[string]*Country)
+	countryByName = make(map[string]*Country)
+
+	for i, r := range countryRecords {
+		c := &Country{
+			Alpha2:       r.alpha2,
+			Alpha3:       r.alpha3,
+			Numeric:      r.numeric,
+			Name:         r.name,
+			CallingCodes: r.callingCodes,
+			Bounds:       NewBounds(NewPoint(r.minLat, r.minLng), NewPoint(r.maxLat, r.maxLng)),
+			Centroid:     NewPoint(r.centroidLat, r.centroidLng),
+		}
+
+		countryList[i] = c
+		countryByCode[c.Alpha2] = c
+		countryByCode[c.Alpha3] = c
+		if c.Numeric != 0 {
+			countryByCode[strconv.Itoa(c.Numeric)] = c
+		}
+		countryByName[nameKey(c.Name)] = c
+
+		// NormalizeName reads "St." as "Street", so register the abbreviated saints too.
+		if strings.HasPrefix(c.Name, "Saint ") {
+			countryByName[nameKey("St. "+strings.TrimPrefix(c.Name, "Saint "))] = c
+		}
+	}
+
+	for alias, code := range countryAliases {
+		if len(alias) <= 3 {
+			countryByCode[alias] = countryByCode[code]
+		} else {
+			countryByName[nameKey(alias)] = countryByCode[code]
+		}
+	}
+}
+
+// Returns every country, ordered by alpha-2 code.
+func Countries() []*Country {
+	countriesOnce.Do(loadCountries)
+
+	countries := make([]*Country, len(countryList))
+	copy(countries, countryList)
+	sort.Slice(countries, func(i, j int) bool { return countries[i].Alpha2 < countries[j].Alpha2 })
+
+	return countries
+}
+
+// Returns the country with the passed in ISO 3166-1 alpha-2, alpha-3 or numeric code,
+// ignoring case and leading zeros, and whether or not there is one.
+func CountryByCode(code string) (*Country, bool) {
+	countriesOnce.Do(loadCountries)
+
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if n, err := strconv.Atoi(code); err == nil {
+		code = strconv.Itoa(n)
+	}
+
+	c, ok := countryByCode[code]
+	return c, ok
+}
+
+// Returns the country with the passed in English name, such as "Germany" or
+// "Côte d'Ivoire", and whether or not there is one.  Names are compared as in
+// MatchName, but must match exactly; a few common alternatives such as
+// "Holland" and "Ivory Coast" are also recognized.
+func CountryByName(name string) (*Country, bool) {
+	countriesOnce.Do(loadCountries)
+
+	c, ok := countryByName[nameKey(name)]
+	return c, ok
+}
+
+// Returns the ISO 3166-1 alpha-2 code of the country the passed in string refers to,
+// by code (see CountryByCode) or by name (see CountryByName), or an empty string
+// if it refers to none.  "usa", "840" and "United States" all give "US".
+func NormalizeCountryCode(country string) string {
+	if c, ok := CountryByCode(country); ok {
+		return c.Alpha2
+	}
+
+	if c, ok := CountryByName(country); ok {
+		return c.Alpha2
+	}
+
+	return ""
+}
+
+// The ISO 3166-1 countries.  Codes follow ISO 3166-1, with Kosovo's widely used
+// user assigned "XK", and calling codes follow the ITU.  Bounds and centroids are
+// derived from Natural Earth's 1:110m countries, or for territories too small to
+// appear in it, from their coastlines at a similar precision.
+var countryRecords = []countryRecord{
+	{"AD", "AND", 20, "Andorra", []string{"+376"}, 42.43, 1.41, 42.66, 1.79, 42.55, 1.60},
+	{"AE", "ARE", 784, "United Arab Emirates", []string{"+971"}, 22.50, 51.58, 26.06, 56.40, 23.87, 54.21},
+	{"AF", "AFG", 4, "Afghanistan", []string{"+93"}, 29.32, 60.53, 38.49, 75.16, 33.86, 66.09},
+	{"AG", "ATG", 28, "Antigua and Barbuda", []string{"+1268"}, 16.93, -62.35, 17.73, -61.66, 17.07, -61.80},
+	{"AI", "AIA", 660, "Anguilla", []string{"+1264"}, 18.15, -63.43, 18.60, -62.92, 18.22, -63.05},
+	{"AL", "ALB", 8, "Albania", []string{"+355"}, 39.62, 19.30, 42.69, 21.02, 41.14, 20.03},
+	{"AM", "ARM", 51, "Armenia", []string{"+374"}, 38.74, 43.58, 41.25, 46.51, 40.22, 45.00},
+	{"AO", "AGO", 24, "Angola", []string{"+244"}, -17.93, 11.64, -4.44, 24.08, -12.29, 17.50},
+	{"AQ", "ATA", 10, "Antarctica", []string{"+672"}, -90.00, -180.00, -60.00, 180.00, -90.00, 0.00},
+	{"AR", "ARG", 32, "Argentina", []string{"+54"}, -55.25, -73.42, -21.83, -53.63, -35.22, -65.15},
+	{"AS", "ASM", 16, "American Samoa", []string{"+1684"}, -14.60, -171.10, -11.04, -168.14, -14.30, -170.70},
+	{"AT", "AUT", 40, "Austria", []string{"+43"}, 46.43, 9.48, 49.04, 16.98, 47.61, 14.08},
+	{"AU", "AUS", 36, "Australia", []string{"+61"}, -43.63, 113.34, -10.67, 153.57, -25.56, 134.38},
+	{"AW", "ABW", 533, "Aruba", []string{"+297"}, 12.41, -70.07, 12.63, -69.87, 12.52, -69.97},
+	{"AX", "ALA", 248, "Åland Islands", []string{"+358"}, 59.73, 19.26, 60.68, 21.34, 60.18, 19.92},
+	{"AZ", "AZE", 31, "Azerbaijan", []string{"+994"}, 38.27, 44.79, 41.86, 50.39, 40.28, 47.68},
+	{"BA", "BIH", 70, "Bosnia and Herzegovina", []string{"+387"}, 42.65, 15.75, 45.23, 19.60, 44.18, 17.82},
+	{"BB", "BRB", 52, "Barbados", []string{"+1246"}, 13.04, -59.65, 13.34, -59.42, 13.19, -59.54},
+	{"BD", "BGD", 50, "Bangladesh", []string{"+880"}, 20.67, 88.08, 26.45, 92.67, 23.84, 90.27},
+	{"BE", "BEL", 56, "Belgium", []string{"+32"}, 49.53, 2.51, 51.48, 6.16, 50.65, 4.58},
+	{"BF", "BFA", 854, "Burkina Faso", []string{"+226"}, 9.61, -5.47, 15.12, 2.18, 12.31, -1.78},
+	{"BG", "BGR", 100, "Bulgaria", []string{"+359"}, 41.23, 22.38, 44.23, 28.56, 42.75, 25.20},
+	{"BH", "BHR", 48, "Bahrain", []string{"+973"}, 25.55, 50.38, 26.33, 50.82, 26.04, 50.55},
+	{"BI", "BDI", 108, "Burundi", []string{"+257"}, -4.50, 29.02, -2.35, 30.75, -3.38, 29.91},
+	{"BJ", "BEN", 204, "Benin", []string{"+229"}, 6.14, 0.77, 12.24, 3.80, 9.65, 2.34},
+	{"BL", "BLM", 652, "Saint Barthélemy", []string{"+590"}, 17.87, -62.95, 17.97, -62.79, 17.90, -62.83},
+	{"BM", "BMU", 60, "Bermuda", []string{"+1441"}, 32.25, -64.89, 32.39, -64.65, 32.32, -64.76},
+	{"BN", "BRN", 96, "Brunei", []string{"+673"}, 4.01, 114.20, 5.45, 115.45, 4.69, 114.92},
+	{"BO", "BOL", 68, "Bolivia", []string{"+591"}, -22.87, -69.59, -9.76, -57.50, -16.73, -64.64},
+	{"BQ", "BES", 535, "Caribbean Netherlands", []string{"+5993", "+5994"}, 12.02, -68.42, 17.65, -62.94, 12.18, -68.26},
+	{"BR", "BRA", 76, "Brazil", []string{"+55"}, -33.77, -73.99, 5.24, -34.73, -10.81, -53.05},
+	{"BS", "BHS", 44, "Bahamas", []string{"+1242"}, 23.71, -78.98, 27.04, -77.00, 24.51, -77.92},
+	{"BT", "BTN", 64, "Bhutan", []string{"+975"}, 26.72, 88.81, 28.30, 92.10, 27.43, 90.47},
+	{"BV", "BVT", 74, "Bouvet Island", []string{"+47"}, -54.46, 3.28, -54.38, 3.44, -54.42, 3.36},
+	{"BW", "BWA", 72, "Botswana", []string{"+267"}, -26.83, 19.90, -17.66, 29.43, -22.10, 23.77},
+	{"BY", "BLR", 112, "Belarus", []string{"+375"}, 51.32, 23.20, 56.17, 32.69, 53.51, 27.98},
+	{"BZ", "BLZ", 84, "Belize", []string{"+501"}, 15.89, -89.23, 18.50, -88.11, 17.20, -88.70},
+	{"CA", "CAN", 124, "Canada", []string{"+1"}, 41.68, -141.00, 83.23, -52.65, 57.75, -101.57},
+	{"CC", "CCK", 166, "Cocos (Keeling) Islands", []string{"+61"}, -12.21, 96.81, -11.82, 96.93, -12.16, 96.87},
+	{"CD", "COD", 180, "Democratic Republic of the Congo", []string{"+243"}, -13.26, 12.18, 5.26, 31.17, -2.85, 23.58},
+	{"CF", "CAF", 140, "Central African Republic", []string{"+236"}, 2.27, 14.46, 11.14, 27.37, 6.54, 20.37},
+	{"CG", "COG", 178, "Republic of the Congo", []string{"+242"}, -5.04, 11.09, 3.73, 18.45, -0.84, 15.13},
+	{"CH", "CHE", 756, "Switzerland", []string{"+41"}, 45.78, 6.02, 47.83, 10.44, 46.79, 8.12},
+	{"CI", "CIV", 384, "Côte d'Ivoire", []string{"+225"}, 4.34, -8.60, 10.52, -2.56, 7.55, -5.61},
+	{"CK", "COK", 184, "Cook Islands", []string{"+682"}, -21.95, -165.85, -8.91, -157.31, -21.24, -159.78},
+	{"CL", "CHL", 152, "Chile", []string{"+56"}, -55.61, -75.64, -17.58, -66.96, -37.34, -71.67},
+	{"CM", "CMR", 120, "Cameroon", []string{"+237"}, 1.73, 8.49, 12.86, 16.01, 5.66, 12.61},
+	{"CN", "CHN", 156, "China", []string{"+86"}, 18.20, 73.68, 53.46, 135.03, 36.61, 103.87},
+	{"CO", "COL", 170, "Colombia", []string{"+57"}, -4.30, -78.99, 12.44, -66.88, 3.93, -73.08},
+	{"CR", "CRI", 188, "Costa Rica", []string{"+506"}, 8.23, -85.94, 11.22, -82.55, 9.97, -84.18},
+	{"CU", "CUB", 192, "Cuba", []string{"+53"}, 19.86, -84.97, 23.19, -74.18, 21.63, -78.96},
+	{"CV", "CPV", 132, "Cabo Verde", []string{"+238"}, 14.80, -25.36, 17.21, -22.66, 16.00, -24.01},
+	{"CW", "CUW", 531, "Curaçao", []string{"+5999"}, 12.03, -69.17, 12.39, -68.73, 12.17, -68.99},
+	{"CX", "CXR", 162, "Christmas Island", []string{"+61"}, -10.57, 105.53, -10.41, 105.71, -10.49, 105.62},
+	{"CY", "CYP", 196, "Cyprus", []string{"+357"}, 34.57, 32.26, 35.17, 34.00, 34.91, 33.04},
+	{"CZ", "CZE", 203, "Czechia", []string{"+420"}, 48.56, 12.24, 51.12, 18.85, 49.78, 15.33},
+	{"DE", "DEU", 276, "Germany", []string{"+49"}, 47.30, 5.99, 54.98, 15.02, 51.13, 10.29},
+	{"DJ", "DJI", 262, "Djibouti", []string{"+253"}, 10.93, 41.66, 12.70, 43.32, 11.77, 42.50},
+	{"DK", "DNK", 208, "Denmark", []string{"+45"}, 54.80, 8.09, 57.73, 12.69, 56.22, 9.31},
+	{"DM", "DMA", 212, "Dominica", []string{"+1767"}, 15.20, -61.48, 15.64, -61.24, 15.42, -61.35},
+	{"DO", "DOM", 214, "Dominican Republic", []string{"+1809", "+1829", "+1849"}, 17.60, -71.95, 19.88, -68.32, 18.88, -70.46},
+	{"DZ", "DZA", 12, "Algeria", []string{"+213"}, 19.06, -8.68, 37.12, 12.00, 28.19, 2.60},
+	{"EC", "ECU", 218, "Ecuador", []string{"+593"}, -4.96, -80.97, 1.38, -75.23, -1.45, -78.38},
+	{"EE", "EST", 233, "Estonia", []string{"+372"}, 57.47, 23.34, 59.61, 28.13, 58.64, 25.82},
+	{"EG", "EGY", 818, "Egypt", []string{"+20"}, 22.00, 24.70, 31.59, 36.87, 26.51, 29.84},
+	{"EH", "ESH", 732, "Western Sahara", []string{"+212"}, 21.00, -17.06, 27.66, -8.67, 24.29, -12.14},
+	{"ER", "ERI", 232, "Eritrea", []string{"+291"}, 12.46, 36.32, 18.00, 43.08, 15.43, 38.68},
+	{"ES", "ESP", 724, "Spain", []string{"+34"}, 35.95, -9.39, 43.75, 3.04, 40.35, -3.62},
+	{"ET", "ETH", 231, "Ethiopia", []string{"+251"}, 3.42, 32.95, 14.96, 47.79, 8.65, 39.55},
+	{"FI", "FIN", 246, "Finland", []string{"+358"}, 59.85, 20.65, 70.16, 31.52, 64.50, 26.21},
+	{"FJ", "FJI", 242, "Fiji", []string{"+679"}, -18.29, 177.29, -16.02, -179.79, -17.83, 178.00},
+	{"FK", "FLK", 238, "Falkland Islands", []string{"+500"}, -52.30, -61.20, -51.10, -57.75, -51.71, -59.42},
+	{"FM", "FSM", 583, "Micronesia", []string{"+691"}, 1.03, 137.33, 10.09, 163.04, 6.92, 158.16},
+	{"FO", "FRO", 234, "Faroe Islands", []string{"+298"}, 61.39, -7.69, 62.40, -6.25, 62.00, -6.79},
+	{"FR", "FRA", 250, "France", []string{"+33"}, 2.05, -54.52, 51.15, 9.56, 46.61, 2.34},
+	{"GA", "GAB", 266, "Gabon", []string{"+241"}, -3.98, 8.80, 2.33, 14.43, -0.65, 11.69},
+	{"GB", "GBR", 826, "United Kingdom", []string{"+44"}, 49.96, -7.57, 58.64, 1.68, 53.88, -2.66},
+	{"GD", "GRD", 308, "Grenada", []string{"+1473"}, 11.98, -61.80, 12.53, -61.38, 12.12, -61.68},
+	{"GE", "GEO", 268, "Georgia", []string{"+995"}, 41.06, 39.96, 43.55, 46.64, 42.16, 43.48},
+	{"GF", "GUF", 254, "French Guiana", []string{"+594"}, 2.11, -54.60, 5.78, -51.63, 3.93, -53.13},
+	{"GG", "GGY", 831, "Guernsey", []string{"+44"}, 49.41, -2.68, 49.74, -2.16, 49.46, -2.58},
+	{"GH", "GHA", 288, "Ghana", []string{"+233"}, 4.71, -3.24, 11.10, 1.06, 7.93, -1.24},
+	{"GI", "GIB", 292, "Gibraltar", []string{"+350"}, 36.11, -5.37, 36.16, -5.34, 36.14, -5.35},
+	{"GL", "GRL", 304, "Greenland", []string{"+299"}, 60.04, -73.30, 83.65, -12.21, 74.77, -41.50},
+	{"GM", "GMB", 270, "Gambia", []string{"+220"}, 13.13, -16.84, 13.88, -13.84, 13.48, -15.43},
+	{"GN", "GIN", 324, "Guinea", []string{"+224"}, 7.31, -15.13, 12.59, -7.83, 10.45, -11.06},
+	{"GP", "GLP", 312, "Guadeloupe", []string{"+590"}, 15.83, -61.81, 16.52, -61.00, 16.25, -61.58},
+	{"GQ", "GNQ", 226, "Equatorial Guinea", []string{"+240"}, 1.01, 9.31, 2.28, 11.29, 1.65, 10.37},
+	{"GR", "GRC", 300, "Greece", []string{"+30"}, 34.92, 20.15, 41.83, 26.60, 39.34, 22.56},
+	{"GS", "SGS", 239, "South Georgia and the South Sandwich Islands", []string{"+500"}, -59.48, -38.03, -53.97, -26.23, -54.43, -36.59},
+	{"GT", "GTM", 320, "Guatemala", []string{"+502"}, 13.74, -92.23, 17.82, -88.23, 15.70, -90.37},
+	{"GU", "GUM", 316, "Guam", []string{"+1671"}, 13.24, 144.62, 13.65, 144.96, 13.44, 144.79},
+	{"GW", "GNB", 624, "Guinea-Bissau", []string{"+245"}, 11.04, -16.68, 12.63, -13.70, 12.02, -15.11},
+	{"GY", "GUY", 328, "Guyana", []string{"+592"}, 1.27, -61.41, 8.37, -56.54, 4.79, -58.97},
+	{"HK", "HKG", 344, "Hong Kong", []string{"+852"}, 22.15, 113.83, 22.56, 114.44, 22.32, 114.17},
+	{"HM", "HMD", 334, "Heard Island and McDonald Islands", []string{"+61"}, -53.19, 72.58, -52.96, 73.87, -53.08, 73.50},
+	{"HN", "HND", 340, "Honduras", []string{"+504"}, 12.98, -89.35, 16.01, -83.15, 14.82, -86.59},
+	{"HR", "HRV", 191, "Croatia", []string{"+385"}, 42.48, 13.66, 46.50, 19.39, 45.02, 16.57},
+	{"HT", "HTI", 332, "Haiti", []string{"+509"}, 18.03, -74.46, 19.92, -71.62, 18.90, -72.66},
+	{"HU", "HUN", 348, "Hungary", []string{"+36"}, 45.76, 16.20, 48.62, 22.71, 47.20, 19.36},
+	{"ID", "IDN", 360, "Indonesia", []string{"+62"}, -10.36, 95.29, 5.48, 141.03, -0.25, 114.02},
+	{"IE", "IRL", 372, "Ireland", []string{"+353"}, 51.67, -9.98, 55.13, -6.03, 53.18, -8.01},
+	{"IL", "ISR", 376, "Israel", []string{"+972"}, 29.50, 34.27, 33.28, 35.84, 31.48, 35.00},
+	{"IM", "IMN", 833, "Isle of Man", []string{"+44"}, 54.04, -4.83, 54.42, -4.31, 54.24, -4.55},
+	{"IN", "IND", 356, "India", []string{"+91"}, 7.97, 68.18, 35.49, 97.40, 22.93, 79.59},
+	{"IO", "IOT", 86, "British Indian Ocean Territory", []string{"+246"}, -7.44, 71.26, -5.23, 72.50, -7.31, 72.41},
+	{"IQ", "IRQ", 368, "Iraq", []string{"+964"}, 29.10, 38.79, 37.39, 48.57, 33.04, 43.76},
+	{"IR", "IRN", 364, "Iran", []string{"+98"}, 25.08, 44.11, 39.71, 63.32, 32.52, 54.29},
+	{"IS", "ISL", 352, "Iceland", []string{"+354"}, 63.50, -24.33, 66.53, -13.61, 65.07, -18.76},
+	{"IT", "ITA", 380, "Italy", []string{"+39"}, 36.62, 6.75, 47.12, 18.48, 43.47, 12.22},
+	{"JE", "JEY", 832, "Jersey", []string{"+44"}, 49.16, -2.26, 49.27, -2.01, 49.21, -2.13},
+	{"JM", "JAM", 388, "Jamaica", []string{"+1876", "+1658"}, 17.70, -78.34, 18.52, -76.20, 18.14, -77.32},
+	{"JO", "JOR", 400, "Jordan", []string{"+962"}, 29.20, 34.92, 33.38, 39.20, 31.25, 36.78},
+	{"JP", "JPN", 392, "Japan", []string{"+81"}, 31.03, 129.41, 45.55, 145.54, 36.02, 136.88},
+	{"KE", "KEN", 404, "Kenya", []string{"+254"}, -4.68, 33.89, 5.51, 41.86, 0.60, 37.79},
+	{"KG", "KGZ", 417, "Kyrgyzstan", []string{"+996"}, 39.28, 69.46, 43.30, 80.26, 41.51, 74.62},
+	{"KH", "KHM", 116, "Cambodia", []string{"+855"}, 10.49, 102.35, 14.57, 107.61, 12.68, 104.88},
+	{"KI", "KIR", 296, "Kiribati", []string{"+686"}, -11.45, 169.53, 4.72, -150.21, 1.33, 172.98},
+	{"KM", "COM", 174, "Comoros", []string{"+269"}, -12.42, 43.22, -11.36, 44.54, -11.70, 43.26},
+	{"KN", "KNA", 659, "Saint Kitts and Nevis", []string{"+1869"}, 17.09, -62.87, 17.42, -62.54, 17.30, -62.72},
+	{"KP", "PRK", 408, "North Korea", []string{"+850"}, 37.67, 124.27, 42.99, 130.78, 40.14, 127.17},
+	{"KR", "KOR", 410, "South Korea", []string{"+82"}, 34.39, 126.12, 38.61, 129.47, 36.43, 127.82},
+	{"KW", "KWT", 414, "Kuwait", []string{"+965"}, 28.53, 46.57, 30.06, 48.42, 29.31, 47.60},
+	{"KY", "CYM", 136, "Cayman Islands", []string{"+1345"}, 19.26, -81.42, 19.76, -79.72, 19.31, -81.25},
+	{"KZ", "KAZ", 398, "Kazakhstan", []string{"+7"}, 40.66, 46.47, 55.39, 87.36, 48.19, 67.28},
+	{"LA", "LAO", 418, "Laos", []string{"+856"}, 13.88, 100.12, 22.46, 107.56, 18.44, 103.75},
+	{"LB", "LBN", 422, "Lebanon", []string{"+961"}, 33.09, 35.13, 34.64, 36.61, 33.91, 35.87},
+	{"LC", "LCA", 662, "Saint Lucia", []string{"+1758"}, 13.71, -61.08, 14.11, -60.87, 13.91, -60.98},
+	{"LI", "LIE", 438, "Liechtenstein", []string{"+423"}, 47.05, 9.47, 47.27, 9.64, 47.16, 9.55},
+	{"LK", "LKA", 144, "Sri Lanka", []string{"+94"}, 5.97, 79.70, 9.82, 81.79, 7.70, 80.67},
+	{"LR", "LBR", 430, "Liberia", []string{"+231"}, 4.36, -11.44, 8.54, -7.54, 6.43, -9.41},
+	{"LS", "LSO", 426, "Lesotho", []string{"+266"}, -30.65, 27.00, -28.65, 29.33, -29.63, 28.17},
+	{"LT", "LTU", 440, "Lithuania", []string{"+370"}, 53.91, 21.06, 56.37, 26.59, 55.28, 23.88},
+	{"LU", "LUX", 442, "Luxembourg", []string{"+352"}, 49.44, 5.67, 50.13, 6.24, 49.77, 5.97},
+	{"LV", "LVA", 428, "Latvia", []string{"+371"}, 55.62, 21.06, 57.97, 28.18, 56.81, 24.83},
+	{"LY", "LBY", 434, "Libya", []string{"+218"}, 19.58, 9.32, 33.14, 25.16, 27.00, 17.97},
+	{"MA", "MAR", 504, "Morocco", []string{"+212"}, 21.42, -17.02, 35.76, -1.12, 29.89, -8.42},
+	{"MC", "MCO", 492, "Monaco", []string{"+377"}, 43.72, 7.41, 43.75, 7.44, 43.74, 7.42},
+	{"MD", "MDA", 498, "Moldova", []string{"+373"}, 45.49, 26.62, 48.47, 30.02, 47.20, 28.41},
+	{"ME", "MNE", 499, "Montenegro", []string{"+382"}, 41.88, 18.45, 43.52, 20.34, 42.79, 19.29},
+	{"MF", "MAF", 663, "Saint Martin", []string{"+590"}, 18.05, -63.15, 18.13, -62.97, 18.08, -63.05},
+	{"MG", "MDG", 450, "Madagascar", []string{"+261"}, -25.60, 43.25, -12.04, 50.48, -19.36, 46.69},
+	{"MH", "MHL", 584, "Marshall Islands", []string{"+692"}, 4.57, 160.80, 14.62, 172.17, 7.09, 171.38},
+	{"MK", "MKD", 807, "North Macedonia", []string{"+389"}, 40.84, 20.46, 42.32, 22.95, 41.61, 21.70},
+	{"ML", "MLI", 466, "Mali", []string{"+223"}, 10.10, -12.17, 24.97, 4.27, 17.27, -3.54},
+	{"MM", "MMR", 104, "Myanmar", []string{"+95"}, 9.93, 92.30, 28.34, 101.18, 21.02, 96.51},
+	{"MN", "MNG", 496, "Mongolia", []string{"+976"}, 41.60, 87.75, 52.05, 119.77, 46.82, 102.95},
+	{"MO", "MAC", 446, "Macao", []string{"+853"}, 22.11, 113.53, 22.22, 113.60, 22.17, 113.55},
+	{"MP", "MNP", 580, "Northern Mariana Islands", []string{"+1670"}, 14.11, 144.89, 20.55, 146.07, 15.18, 145.75},
+	{"MQ", "MTQ", 474, "Martinique", []string{"+596"}, 14.39, -61.23, 14.88, -60.81, 14.64, -61.02},
+	{"MR", "MRT", 478, "Mauritania", []string{"+222"}, 14.62, -17.06, 27.40, -4.92, 20.21, -10.33},
+	{"MS", "MSR", 500, "Montserrat", []string{"+1664"}, 16.67, -62.24, 16.82, -62.14, 16.74, -62.19},
+	{"MT", "MLT", 470, "Malta", []string{"+356"}, 35.79, 14.18, 36.08, 14.58, 35.92, 14.41},
+	{"MU", "MUS", 480, "Mauritius", []string{"+230"}, -20.53, 56.51, -10.32, 63.50, -20.28, 57.57},
+	{"MV", "MDV", 462, "Maldives", []string{"+960"}, -0.69, 72.64, 7.11, 73.76, 3.20, 73.22},
+	{"MW", "MWI", 454, "Malawi", []string{"+265"}, -16.80, 32.69, -9.23, 35.77, -13.17, 34.19},
+	{"MX", "MEX", 484, "Mexico", []string{"+52"}, 14.54, -117.13, 32.72, -86.81, 23.94, -102.58},
+	{"MY", "MYS", 458, "Malaysia", []string{"+60"}, 0.77, 100.09, 6.93, 119.18, 3.55, 114.68},
+	{"MZ", "MOZ", 508, "Mozambique", []string{"+258"}, -26.74, 30.18, -10.32, 40.78, -17.23, 35.47},
+	{"NA", "NAM", 516, "Namibia", []string{"+264"}, -29.05, 11.73, -16.94, 25.08, -22.10, 17.16},
+	{"NC", "NCL", 540, "New Caledonia", []string{"+687"}, -22.40, 164.03, -20.11, 167.12, -21.26, 165.53},
+	{"NE", "NER", 562, "Niger", []string{"+227"}, 11.66, 0.30, 23.47, 15.90, 17.35, 9.32},
+	{"NF", "NFK", 574, "Norfolk Island", []string{"+672"}, -29.14, 167.91, -28.99, 168.00, -29.04, 167.95},
+	{"NG", "NGA", 566, "Nigeria", []string{"+234"}, 4.24, 2.69, 13.87, 14.58, 9.55, 8.00},
+	{"NI", "NIC", 558, "Nicaragua", []string{"+505"}, 10.73, -87.67, 15.02, -83.15, 12.85, -85.02},
+	{"NL", "NLD", 528, "Netherlands", []string{"+31"}, 50.80, 3.31, 53.51, 7.09, 52.30, 5.51},
+	{"NO", "NOR", 578, "Norway", []string{"+47"}, 58.08, 4.99, 80.66, 31.29, 64.54, 14.24},
+	{"NP", "NPL", 524, "Nepal", []string{"+977"}, 26.40, 80.09, 30.42, 88.17, 28.24, 84.01},
+	{"NR", "NRU", 520, "Nauru", []string{"+674"}, -0.55, 166.90, -0.50, 166.96, -0.53, 166.93},
+	{"NU", "NIU", 570, "Niue", []string{"+683"}, -19.15, -169.95, -18.95, -169.77, -19.05, -169.87},
+	{"NZ", "NZL", 554, "New Zealand", []string{"+64"}, -46.64, 166.51, -34.45, 178.52, -43.99, 170.51},
+	{"OM", "OMN", 512, "Oman", []string{"+968"}, 16.65, 52.00, 26.40, 59.81, 20.58, 56.10},
+	{"PA", "PAN", 591, "Panama", []string{"+507"}, 7.22, -82.97, 9.61, -77.24, 8.53, -80.11},
+	{"PE", "PER", 604, "Peru", []string{"+51"}, -18.35, -81.41, -0.06, -68.67, -9.19, -74.39},
+	{"PF", "PYF", 258, "French Polynesia", []string{"+689"}, -27.65, -154.73, -7.90, -134.93, -17.68, -149.41},
+	{"PG", "PNG", 598, "Papua New Guinea", []string{"+675"}, -10.65, 141.00, -2.50, 156.02, -6.65, 144.33},
+	{"PH", "PHL", 608, "Philippines", []string{"+63"}, 5.58, 117.17, 18.51, 126.54, 15.75, 121.54},
+	{"PK", "PAK", 586, "Pakistan", []string{"+92"}, 23.69, 60.87, 37.13, 77.84, 29.97, 69.41},
+	{"PL", "POL", 616, "Poland", []string{"+48"}, 49.03, 14.07, 54.85, 24.03, 52.15, 19.31},
+	{"PM", "SPM", 666, "Saint Pierre and Miquelon", []string{"+508"}, 46.75, -56.41, 47.15, -56.12, 46.92, -56.30},
+	{"PN", "PCN", 612, "Pitcairn Islands", []string{"+64"}, -25.08, -130.75, -23.92, -124.77, -25.07, -130.10},
+	{"PR", "PRI", 630, "Puerto Rico", []string{"+1787", "+1939"}, 17.95, -67.24, 18.52, -65.59, 18.24, -66.48},
+	{"PS", "PSE", 275, "Palestine", []string{"+970"}, 31.35, 34.93, 32.53, 35.55, 31.94, 35.27},
+	{"PT", "PRT", 620, "Portugal", []string{"+351"}, 36.84, -9.53, 42.28, -6.39, 39.63, -8.06},
+	{"PW", "PLW", 585, "Palau", []string{"+680"}, 2.80, 131.12, 8.10, 134.72, 7.51, 134.58},
+	{"PY", "PRY", 600, "Paraguay", []string{"+595"}, -27.55, -62.69, -19.34, -54.29, -23.25, -58.39},
+	{"QA", "QAT", 634, "Qatar", []string{"+974"}, 24.56, 50.74, 26.11, 51.61, 25.32, 51.18},
+	{"RE", "REU", 638, "Réunion", []string{"+262"}, -21.39, 55.22, -20.87, 55.84, -21.12, 55.53},
+	{"RO", "ROU", 642, "Romania", []string{"+40"}, 43.69, 20.22, 48.22, 29.63, 45.86, 24.94},
+	{"RS", "SRB", 688, "Serbia", []string{"+381"}, 42.25, 18.83, 46.17, 22.99, 44.23, 20.82},
+	{"RU", "RUS", 643, "Russia", []string{"+7"}, 41.15, 19.66, 81.25, -169.90, 61.69, 99.22},
+	{"RW", "RWA", 646, "Rwanda", []string{"+250"}, -2.92, 29.02, -1.13, 30.82, -2.01, 29.92},
+	{"SA", "SAU", 682, "Saudi Arabia", []string{"+966"}, 16.35, 34.63, 32.16, 55.67, 24.12, 44.52},
+	{"SB", "SLB", 90, "Solomon Islands", []string{"+677"}, -10.83, 156.49, -6.60, 162.40, -7.90, 159.10},
+	{"SC", "SYC", 690, "Seychelles", []string{"+248"}, -10.23, 46.20, -3.71, 56.29, -4.68, 55.49},
+	{"SD", "SDN", 729, "Sudan", []string{"+249"}, 8.62, 21.94, 22.00, 38.41, 15.99, 29.86},
+	{"SE", "SWE", 752, "Sweden", []string{"+46"}, 55.36, 11.03, 69.11, 23.90, 62.81, 16.60},
+	{"SG", "SGP", 702, "Singapore", []string{"+65"}, 1.16, 103.60, 1.47, 104.09, 1.35, 103.82},
+	{"SH", "SHN", 654, "Saint Helena, Ascension and Tristan da Cunha", []string{"+290"}, -40.40, -14.42, -7.88, -5.64, -15.96, -5.71},
+	{"SI", "SVN", 705, "Slovenia", []string{"+386"}, 45.45, 13.70, 46.85, 16.56, 46.13, 14.94},
+	{"SJ", "SJM", 744, "Svalbard and Jan Mayen", []string{"+47"}, 70.83, -9.08, 80.83, 33.64, 78.22, 15.65},
+	{"SK", "SVK", 703, "Slovakia", []string{"+421"}, 47.76, 16.88, 49.57, 22.56, 48.73, 19.51},
+	{"SL", "SLE", 694, "Sierra Leone", []string{"+232"}, 6.79, -13.25, 10.05, -10.23, 8.53, -11.80},
+	{"SM", "SMR", 674, "San Marino", []string{"+378"}, 43.89, 12.40, 43.99, 12.52, 43.94, 12.46},
+	{"SN", "SEN", 686, "Senegal", []string{"+221"}, 12.33, -17.63, 16.60, -11.47, 14.35, -14.51},
+	{"SO", "SOM", 706, "Somalia", []string{"+252"}, -1.68, 40.98, 12.02, 51.13, 4.75, 45.73},
+	{"SR", "SUR", 740, "Suriname", []string{"+597"}, 1.82, -58.04, 6.03, -53.96, 4.12, -55.91},
+	{"SS", "SSD", 728, "South Sudan", []string{"+211"}, 3.51, 23.89, 12.25, 35.30, 7.29, 30.20},
+	{"ST", "STP", 678, "São Tomé and Príncipe", []string{"+239"}, -0.02, 6.46, 1.70, 7.47, 0.34, 6.73},
+	{"SV", "SLV", 222, "El Salvador", []string{"+503"}, 13.15, -90.10, 14.42, -87.72, 13.73, -88.87},
+	{"SX", "SXM", 534, "Sint Maarten", []string{"+1721"}, 18.00, -63.14, 18.07, -63.01, 18.04, -63.07},
+	{"SY", "SYR", 760, "Syria", []string{"+963"}, 32.31, 35.70, 37.23, 42.35, 35.01, 38.54},
+	{"SZ", "SWZ", 748, "Eswatini", []string{"+268"}, -27.29, 30.68, -25.66, 32.07, -26.49, 31.40},
+	{"TC", "TCA", 796, "Turks and Caicos Islands", []string{"+1649"}, 21.18, -72.49, 21.96, -71.08, 21.69, -71.80},
+	{"TD", "TCD", 148, "Chad", []string{"+235"}, 7.42, 13.54, 23.41, 23.89, 15.33, 18.58},
+	{"TF", "ATF", 260, "French Southern Territories", []string{"+262"}, -49.77, 68.72, -48.62, 70.56, -49.31, 69.53},
+	{"TG", "TGO", 768, "Togo", []string{"+228"}, 5.93, -0.05, 11.02, 1.87, 8.44, 1.00},
+	{"TH", "THA", 764, "Thailand", []string{"+66"}, 5.69, 97.38, 20.42, 105.59, 15.02, 101.01},
+	{"TJ", "TJK", 762, "Tajikistan", []string{"+992"}, 36.74, 67.44, 40.96, 74.98, 38.58, 71.03},
+	{"TK", "TKL", 772, "Tokelau", []string{"+690"}, -9.44, -172.52, -8.53, -171.18, -9.20, -171.85},
+	{"TL", "TLS", 626, "Timor-Leste", []string{"+670"}, -9.39, 124.97, -8.27, 127.34, -8.77, 125.97},
+	{"TM", "TKM", 795, "Turkmenistan", []string{"+993"}, 35.27, 52.50, 42.75, 66.55, 39.09, 59.28},
+	{"TN", "TUN", 788, "Tunisia", []string{"+216"}, 30.31, 7.52, 37.35, 11.49, 34.17, 9.53},
+	{"TO", "TON", 776, "Tonga", []string{"+676"}, -22.35, -176.22, -15.56, -173.70, -21.18, -175.20},
+	{"TR", "TUR", 792, "Türkiye", []string{"+90"}, 35.82, 26.04, 42.14, 44.79, 38.99, 35.39},
+	{"TT", "TTO", 780, "Trinidad and Tobago", []string{"+1868"}, 10.00, -61.95, 10.89, -60.90, 10.43, -61.33},
+	{"TV", "TUV", 798, "Tuvalu", []string{"+688"}, -10.80, 176.06, -5.64, 179.91, -8.52, 179.20},
+	{"TW", "TWN", 158, "Taiwan", []string{"+886"}, 21.97, 120.11, 25.30, 121.95, 23.74, 120.97},
+	{"TZ", "TZA", 834, "Tanzania", []string{"+255"}, -11.72, 29.34, -0.95, 40.32, -6.26, 34.75},
+	{"UA", "UKR", 804, "Ukraine", []string{"+380"}, 44.36, 22.09, 52.34, 40.08, 48.97, 31.37},
+	{"UG", "UGA", 800, "Uganda", []string{"+256"}, -1.44, 29.58, 4.25, 35.04, 1.30, 32.36},
+	{"UM", "UMI", 581, "United States Minor Outlying Islands", []string{"+1"}, -0.39, 166.60, 28.22, -160.02, 19.28, 166.65},
+	{"US", "USA", 840, "United States", []string{"+1"}, 18.92, -171.79, 71.36, -66.96, 39.50, -99.06},
+	{"UY", "URY", 858, "Uruguay", []string{"+598"}, -34.95, -58.43, -30.11, -53.21, -32.78, -56.00},
+	{"UZ", "UZB", 860, "Uzbekistan", []string{"+998"}, 37.14, 55.93, 45.59, 73.06, 41.75, 63.20},
+	{"VA", "VAT", 336, "Vatican City", []string{"+39"}, 41.90, 12.45, 41.91, 12.46, 41.90, 12.45},
+	{"VC", "VCT", 670, "Saint Vincent and the Grenadines", []string{"+1784"}, 12.58, -61.46, 13.38, -61.11, 13.25, -61.20},
+	{"VE", "VEN", 862, "Venezuela", []string{"+58"}, 0.72, -73.30, 12.16, -59.76, 7.16, -66.16},
+	{"VG", "VGB", 92, "British Virgin Islands", []string{"+1284"}, 18.38, -64.85, 18.75, -64.27, 18.43, -64.62},
+	{"VI", "VIR", 850, "U.S. Virgin Islands", []string{"+1340"}, 17.67, -65.09, 18.41, -64.56, 18.34, -64.93},
+	{"VN", "VNM", 704, "Vietnam", []string{"+84"}, 8.60, 102.17, 23.35, 109.34, 16.66, 106.29},
+	{"VU", "VUT", 548, "Vanuatu", []string{"+678"}, -16.60, 166.63, -14.63, 167.84, -15.22, 166.91},
+	{"WF", "WLF", 876, "Wallis and Futuna", []string{"+681"}, -14.36, -178.21, -13.18, -176.12, -13.77, -177.16},
+	{"WS", "WSM", 882, "Samoa", []string{"+685"}, -14.08, -172.80, -13.43, -171.40, -13.76, -172.10},
+	{"XK", "XKX", 0, "Kosovo", []string{"+383"}, 41.85, 20.07, 43.27, 21.78, 42.58, 20.90},
+	{"YE", "YEM", 887, "Yemen", []string{"+967"}, 12.59, 42.60, 19.00, 53.11, 15.91, 47.54},
+	{"YT", "MYT", 175, "Mayotte", []string{"+262"}, -13.00, 45.01, -12.64, 45.30, -12.83, 45.17},
+	{"ZA", "ZAF", 710, "South Africa", []string{"+27"}, -34.82, 16.34, -22.09, 32.83, -28.96, 25.12},
+	{"ZM", "ZMB", 894, "Zambia", []string{"+260"}, -17.96, 21.89, -8.24, 33.49, -13.40, 27.73},
+	{"ZW", "ZWE", 716, "Zimbabwe", []string{"+263"}, -22.27, 25.26, -15.51, 32.85, -18.91, 29.79},
+}
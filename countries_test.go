@@ -0,0 +1,107 @@
+package geo
+
+import (
+	"testing"
+)
+
+// Ensures that countries are found by any of their ISO 3166-1 codes.
+func TestCountryByCode(t *testing.T) {
+	for _, code := range []string{"US", "us", "USA", "840", " usa "} {
+		c, ok := CountryByCode(code)
+		if !ok || c.Alpha2 != "US" || c.Alpha3 != "USA" || c.Numeric != 840 || c.Name != "United States" {
+			t.Errorf("Expected %q to find the United States, got %+v", code, c)
+		}
+	}
+
+	if c, ok := CountryByCode("004"); !ok || c.Name != "Afghanistan" {
+		t.Errorf("Expected a zero padded numeric code to be found, got %+v", c)
+	}
+
+	if c, ok := CountryByCode("UK"); !ok || c.Alpha2 != "GB" {
+		t.Errorf("Expected UK to find the United Kingdom, got %+v", c)
+	}
+
+	for _, code := range []string{"", "ZZ", "ZZZ", "999", "0"} {
+		if _, ok := CountryByCode(code); ok {
+			t.Errorf("Expected %q not to be a country", code)
+		}
+	}
+}
+
+// Ensures that countries are found by name, ignoring case, accents and abbreviations.
+func TestCountryByName(t *testing.T) {
+	tests := map[string]string{
+		"Germany":               "DE",
+		"germany":               "DE",
+		"Cote d'Ivoire":         "CI",
+		"Ivory Coast":           "CI",
+		"St. Lucia":             "LC",
+		"The Netherlands":       "NL",
+		"United States":         "US",
+		"Republic of the Congo": "CG",
+	}
+
+	for name, code := range tests {
+		if c, ok := CountryByName(name); !ok || c.Alpha2 != code {
+			t.Errorf("Expected %q to be %s, got %+v", name, code, c)
+		}
+	}
+
+	if _, ok := CountryByName("Atlantis"); ok {
+		t.Error("Expected Atlantis not to be a country")
+	}
+}
+
+// Ensures that NormalizeCountryCode accepts codes and names alike.
+func TestNormalizeCountryCode(t *testing.T) {
+	tests := map[string]string{
+		"jp":          "JP",
+		"JPN":         "JP",
+		"392":         "JP",
+		"Japan":       "JP",
+		"Switzerland": "CH",
+		"Nowhere":     "",
+		"":            "",
+	}
+
+	for input, expected := range tests {
+		if code := NormalizeCountryCode(input); code != expected {
+			t.Errorf("Expected %q to normalize to %q, got %q", input, expected, code)
+		}
+	}
+}
+
+// Ensures that every country has consistent metadata, and that centroids lie within bounds.
+func TestCountries(t *testing.T) {
+	countries := Countries()
+	if len(countries) != 250 {
+		t.Errorf("Expected 250 countries, got %d", len(countries))
+	}
+
+	seen := make(map[string]bool)
+	for i, c := range countries {
+		if i > 0 && countries[i-1].Alpha2 >= c.Alpha2 {
+			t.Errorf("Expected countries ordered by alpha-2 code, got %s after %s", c.Alpha2, countries[i-1].Alpha2)
+		}
+
+		if len(c.Alpha2) != 2 || len(c.Alpha3) != 3 || seen[c.Alpha3] || c.Name == "" || len(c.CallingCodes) == 0 {
+			t.Errorf("Unexpected country: %+v", c)
+		}
+		seen[c.Alpha3] = true
+
+		if !c.Bounds.Contains(c.Centroid) {
+			t.Errorf("Expected the centroid of %s to lie within its bounds, got %v outside %v", c.Name, c.Centroid, c.Bounds)
+		}
+	}
+
+	// Bounds crossing the antimeridian have their south west corner east of their north east corner.
+	fiji, _ := CountryByCode("FJ")
+	if !fiji.Bounds.Contains(NewPoint(-17.0, 179.9)) || !fiji.Bounds.Contains(NewPoint(-17.0, -179.9)) || fiji.Bounds.Contains(NewPoint(-17.0, 0)) {
+		t.Errorf("Expected Fiji's bounds to cross the antimeridian, got %v", fiji.Bounds)
+	}
+
+	us, _ := CountryByCode("US")
+	if !us.Bounds.Contains(NewPoint(40.7, -74.0)) || us.CallingCodes[0] != "+1" {
+		t.Errorf("Unexpected United States: %+v", us)
+	}
+}
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE points ADD COLUMN id serial PRIMARY KEY;
+
+-- +goose Down
+ALTER TABLE points DROP COLUMN id;
@@ -0,0 +1,105 @@
+package geo
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// An http.RoundTripper writing every request and response in full, credentials aside.
+type debugTransport struct {
+	mu   sync.Mutex
+	w    io.Writer
+	base http.RoundTripper
+}
+
+// Returns an http.RoundTripper that writes every request it issues and every response it receives to the passed in
+// writer in full, headers and bodies included, for debugging a provider integration.  Only credentials are left out:
+// the credential query parameters and headers are redacted, as they are from errors and logs, so dumps
+// can be shared.  Requests are issued with the passed in transport, or the shared transport if it is nil.
+func DebugTransport(w io.Writer, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = defaultTransport()
+	}
+
+	return &debugTransport{w: w, base: base}
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			reqBody, _ = ioutil.ReadAll(body)
+			body.Close()
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	latency := time.Since(start)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "--> %s %s\n", req.Method, scrubURL(req.URL))
+	writeDebugHeader(&buf, scrubHeader(req.Header))
+	writeDebugBody(&buf, reqBody)
+
+	if err != nil {
+		fmt.Fprintf(&buf, "<-- error after %v: %v\n\n", latency, scrubError(err))
+	} else {
+		// Read the body so it can be dumped, and replace it so the caller can still read it.
+		respBody, readErr := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+		fmt.Fprintf(&buf, "<-- %s (%v)\n", resp.Status, latency)
+		writeDebugHeader(&buf, resp.Header)
+		if decoded, err := decodeBody(resp.Header, bytes.NewReader(respBody)); err == nil {
+			// Dump compressed bodies as the caller will read them.
+			if plain, err := ioutil.ReadAll(decoded); err == nil {
+				respBody = plain
+			}
+		}
+		writeDebugBody(&buf, respBody)
+		if readErr != nil {
+			fmt.Fprintf(&buf, "<-- error reading body: %v\n\n", readErr)
+		}
+	}
+
+	t.mu.Lock()
+	t.w.Write(buf.Bytes())
+	t.mu.Unlock()
+
+	return resp, err
+}
+
+// Writes the passed in header, one line per value, sorted by name.
+func writeDebugHeader(w io.Writer, h http.Header) {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, value := range h[name] {
+			fmt.Fprintf(w, "%s: %s\n", name, value)
+		}
+	}
+}
+
+// Writes the passed in body after a blank line, followed by another.
+func writeDebugBody(w io.Writer, body []byte) {
+	fmt.Fprintln(w)
+	if len(body) > 0 {
+		w.Write(body)
+		if body[len(body)-1] != '\n' {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintln(w)
+	}
+}
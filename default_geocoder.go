@@ -0,0 +1,94 @@
+package geo
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// The process-wide Geocoder returned by Default, nil until it is first asked for or set.
+var (
+	defaultGeocoderMu sync.RWMutex
+	defaultGeocoder   Geocoder
+)
+
+// A Geocoder that fails every request with the same error, such as the Default
+// when the environment names a provider golang-geo doesn't know.
+type failingGeocoder struct {
+	err error
+}
+
+func (f failingGeocoder) Geocode(query string, opts ...QueryOption) (*Point, error) {
+	return nil, f.err
+}
+
+func (f failingGeocoder) ReverseGeocode(p *Point, opts ...QueryOption) (string, error) {
+	return "", f.err
+}
+
+// Returns the process-wide Geocoder, for small programs that don't want to pass one around.
+// Unless one has been set with SetDefault, it is created on first use from the environment:
+//
+//	GEO_PROVIDER   "google" (the default) or "mapquest"
+//	GEO_API_KEY    the API key, defaulting to $GOOGLE_API_KEY or $MAPQUEST_API_KEY
+//	GEO_LANGUAGE   the language to ask for results in, e.g. "en"
+//	GEO_BASE_URL   the base URL of the provider's API, for testing
+//
+// If GEO_PROVIDER names an unknown provider, every request to the Default fails with an error saying so.
+// Default is safe to call from multiple goroutines.
+func Default() Geocoder {
+	defaultGeocoderMu.RLock()
+	g := defaultGeocoder
+	defaultGeocoderMu.RUnlock()
+
+	if g != nil {
+		return g
+	}
+
+	defaultGeocoderMu.Lock()
+	defer defaultGeocoderMu.Unlock()
+
+	// Another goroutine may have created or set one while the lock was released.
+	if defaultGeocoder == nil {
+		g, err := geocoderFromEnv(os.Getenv)
+		if err != nil {
+			g = failingGeocoder{err}
+		}
+		defaultGeocoder = g
+	}
+
+	return defaultGeocoder
+}
+
+// Replaces the process-wide Geocoder returned by Default with the passed in one, such as a
+// MockGeocoder in tests or a CachedGeocoder wrapping the provider.  Passing nil makes the next
+// call to Default create one from the environment again.  SetDefault is safe to call from
+// multiple goroutines, but Geocoders already returned by Default are not replaced.
+func SetDefault(g Geocoder) {
+	defaultGeocoderMu.Lock()
+	defer defaultGeocoderMu.Unlock()
+
+	defaultGeocoder = g
+}
+
+// Returns the Geocoder the passed in environment describes, as Default documents.
+func geocoderFromEnv(getenv func(key string) string) (Geocoder, error) {
+	opts := []Option{WithLanguage(getenv("GEO_LANGUAGE")), WithBaseURL(getenv("GEO_BASE_URL"))}
+
+	key := getenv("GEO_API_KEY")
+	switch provider := strings.ToLower(getenv("GEO_PROVIDER")); provider {
+	case "", "google":
+		if key == "" {
+			key = getenv("GOOGLE_API_KEY")
+		}
+		return NewGoogleGeocoder(append(opts, WithAPIKey(key))...), nil
+	case "mapquest":
+		if key == "" {
+			key = getenv("MAPQUEST_API_KEY")
+		}
+		return NewMapQuestGeocoder(append(opts, WithAPIKey(key))...), nil
+	default:
+		return nil, fmt.Errorf("unknown GEO_PROVIDER %q", provider)
+	}
+}
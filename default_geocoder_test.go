@@ -0,0 +1,95 @@
+package geo
+
+import (
+	"sync"
+	"testing"
+)
+
+// Returns a getenv function that looks up the passed in variables.
+func fakeEnv(vars map[string]string) func(key string) string {
+	return func(key string) string {
+		return vars[key]
+	}
+}
+
+// Ensures that the environment chooses the provider and its settings.
+func TestGeocoderFromEnv(t *testing.T) {
+	g, err := geocoderFromEnv(fakeEnv(map[string]string{"GOOGLE_API_KEY": "google-key", "GEO_LANGUAGE": "ja"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	google, ok := g.(*GoogleGeocoder)
+	if !ok || google.apiKey != "google-key" || google.language != "ja" {
+		t.Errorf("Expected a Google geocoder with the Google API key in Japanese, got %#v", g)
+	}
+
+	g, err = geocoderFromEnv(fakeEnv(map[string]string{
+		"GEO_PROVIDER":     "MapQuest",
+		"GEO_API_KEY":      "geo-key",
+		"MAPQUEST_API_KEY": "mapquest-key",
+		"GEO_BASE_URL":     "http://localhost:8080",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mapquest, ok := g.(*MapQuestGeocoder)
+	if !ok || mapquest.apiKey != "geo-key" || mapquest.BaseURL != "http://localhost:8080" {
+		t.Errorf("Expected a MapQuest geocoder with GEO_API_KEY, got %#v", g)
+	}
+
+	if _, err := geocoderFromEnv(fakeEnv(map[string]string{"GEO_PROVIDER": "bing"})); err == nil {
+		t.Errorf("Expected an error for an unknown provider")
+	}
+}
+
+// Ensures that Default is created from the environment once, and can be replaced and reset.
+func TestDefault(t *testing.T) {
+	t.Cleanup(func() { SetDefault(nil) })
+
+	SetDefault(nil)
+	t.Setenv("GEO_PROVIDER", "bing")
+
+	g := Default()
+	if _, err := g.Geocode("SFO"); err == nil {
+		t.Errorf("Expected the default for an unknown provider to fail")
+	}
+
+	if Default() != g {
+		t.Errorf("Expected Default to return the same geocoder every time")
+	}
+
+	stub := &stubGeocoder{}
+	SetDefault(stub)
+	if Default() != Geocoder(stub) {
+		t.Errorf("Expected %v, got %v", stub, Default())
+	}
+
+	SetDefault(nil)
+	t.Setenv("GEO_PROVIDER", "mapquest")
+	if _, ok := Default().(*MapQuestGeocoder); !ok {
+		t.Errorf("Expected a MapQuest geocoder once reset, got %#v", Default())
+	}
+}
+
+// Ensures that Default and SetDefault can be called from multiple goroutines at once.
+func TestDefaultConcurrent(t *testing.T) {
+	t.Cleanup(func() { SetDefault(nil) })
+	SetDefault(nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				SetDefault(&stubGeocoder{})
+			}
+			if Default() == nil {
+				t.Errorf("Expected a default geocoder")
+			}
+		}(i)
+	}
+	wg.Wait()
+}
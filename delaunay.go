@@ -0,0 +1,227 @@
+package geo
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// This is the error that consumers receive when triangulating fewer than four distinct points,
+// or points that all lie on one great circle, which don't divide the sphere into triangles.
+var degenerateTriangulationError = errors.New("points must include four that don't share a great circle")
+
+// The distance, as a fraction of the earth's radius, below which a point is taken
+// to lie on a face of the hull rather than outside it; about a millimeter.
+const hullTolerance = 1e-10
+
+// A face of the convex hull of points on the unit sphere, wound counterclockwise seen from outside.
+type hullFace struct {
+	v      [3]int
+	normal [3]float64
+	offset float64
+	alive  bool
+}
+
+// Returns the indexes of the triangles of the Delaunay triangulation of the passed in points on the sphere:
+// triangles whose circumcircles hold none of the other points, wound counterclockwise seen from above.
+// Unlike a triangulation of the points' coordinates on a flat map, it is unaffected by the antimeridian
+// and the poles, and covers the whole sphere, so points spread over one region are joined by triangles
+// around the back of the earth too.  Duplicate points are left out of every triangle.
+// Points closer than a few centimeters apart may be taken to be duplicates.
+func Delaunay(points []*Point) ([][3]int, error) {
+	faces, err := sphereHull(points)
+	if err != nil {
+		return nil, err
+	}
+
+	triangles := make([][3]int, len(faces))
+	for i, f := range faces {
+		triangles[i] = f.v
+	}
+
+	return triangles, nil
+}
+
+// Returns the live faces of the convex hull of the passed in points' unit vectors,
+// whose faces are the triangles of the points' spherical Delaunay triangulation.
+func sphereHull(points []*Point) ([]*hullFace, error) {
+	vectors := make([][3]float64, len(points))
+	for i, p := range points {
+		vectors[i] = unitVector(p)
+	}
+
+	first, err := initialTetrahedron(vectors)
+	if err != nil {
+		return nil, err
+	}
+
+	// A point inside the hull, used to turn every face outward.
+	var inside [3]float64
+	for _, i := range first {
+		for j := range inside {
+			inside[j] += vectors[i][j] / 4
+		}
+	}
+
+	var faces []*hullFace
+	edges := make(map[[2]int]*hullFace)
+	addFace := func(a, b, c int) {
+		va, vb, vc := vectors[a], vectors[b], vectors[c]
+		n := cross3([3]float64{vb[0] - va[0], vb[1] - va[1], vb[2] - va[2]}, [3]float64{vc[0] - va[0], vc[1] - va[1], vc[2] - va[2]})
+		if dot3(n, [3]float64{inside[0] - va[0], inside[1] - va[1], inside[2] - va[2]}) > 0 {
+			b, c = c, b
+			n = [3]float64{-n[0], -n[1], -n[2]}
+		}
+
+		length := norm3(n)
+		n = [3]float64{n[0] / length, n[1] / length, n[2] / length}
+		f := &hullFace{v: [3]int{a, b, c}, normal: n, offset: dot3(n, va), alive: true}
+		faces = append(faces, f)
+		for k := 0; k < 3; k++ {
+			edges[[2]int{f.v[k], f.v[(k+1)%3]}] = f
+		}
+	}
+
+	a, b, c, d := first[0], first[1], first[2], first[3]
+	addFace(a, b, c)
+	addFace(a, b, d)
+	addFace(a, c, d)
+	addFace(b, c, d)
+
+	for i, v := range vectors {
+		if i == a || i == b || i == c || i == d {
+			continue
+		}
+
+		var visible []*hullFace
+		for _, f := range faces {
+			if f.alive && dot3(f.normal, v)-f.offset > hullTolerance {
+				visible = append(visible, f)
+			}
+		}
+
+		// Points on or within the hull, such as duplicates, aren't part of it.
+		if len(visible) == 0 {
+			continue
+		}
+
+		for _, f := range visible {
+			f.alive = false
+		}
+
+		// The edges of the visible faces whose other face is hidden form the horizon,
+		// each of which is joined to the new point by a new face.
+		var horizon [][2]int
+		for _, f := range visible {
+			for k := 0; k < 3; k++ {
+				u, w := f.v[k], f.v[(k+1)%3]
+				if twin := edges[[2]int{w, u}]; twin != nil && twin.alive {
+					horizon = append(horizon, [2]int{u, w})
+				}
+				delete(edges, [2]int{u, w})
+			}
+		}
+
+		for _, e := range horizon {
+			addFace(e[0], e[1], i)
+		}
+	}
+
+	var live []*hullFace
+	for _, f := range faces {
+		if f.alive {
+			live = append(live, f)
+		}
+	}
+
+	return live, nil
+}
+
+// Returns the indexes of four of the passed in unit vectors spanning a tetrahedron
+// of as large a volume as can be found quickly.
+func initialTetrahedron(vectors [][3]float64) ([4]int, error) {
+	var t [4]int
+	if len(vectors) < 4 {
+		return t, degenerateTriangulationError
+	}
+
+	sub := func(a, b [3]float64) [3]float64 { return [3]float64{a[0] - b[0], a[1] - b[1], a[2] - b[2]} }
+	farthest := func(measure func(v [3]float64) float64) (int, float64) {
+		best, bestValue := -1, 0.0
+		for i, v := range vectors {
+			if m := measure(v); m > bestValue {
+				best, bestValue = i, m
+			}
+		}
+		return best, bestValue
+	}
+
+	t[0] = 0
+	var size float64
+	t[1], size = farthest(func(v [3]float64) float64 { return norm3(sub(v, vectors[t[0]])) })
+	if size < hullTolerance {
+		return t, degenerateTriangulationError
+	}
+
+	ab := sub(vectors[t[1]], vectors[t[0]])
+	t[2], size = farthest(func(v [3]float64) float64 { return norm3(cross3(ab, sub(v, vectors[t[0]]))) })
+	if size < hullTolerance {
+		return t, degenerateTriangulationError
+	}
+
+	n := cross3(ab, sub(vectors[t[2]], vectors[t[0]]))
+	t[3], size = farthest(func(v [3]float64) float64 { return math.Abs(dot3(n, sub(v, vectors[t[0]]))) / norm3(n) })
+	if size < hullTolerance {
+		return t, degenerateTriangulationError
+	}
+
+	return t, nil
+}
+
+// Returns the Voronoi cell of each of the passed in points on the sphere: the region nearer to it than
+// to any other point, as a polygon with an edge for each of its neighbours, such as the territory each
+// of a set of stores is nearest to.  Cells are measured along great circles, so they are unaffected by the
+// antimeridian and the poles, and together cover the whole sphere.  The cells of duplicate points are nil.
+// Cells of points on the edge of a set spread over one region reach around the back of the earth,
+// and cells may surround a pole or be larger than a hemisphere, beyond what Polygon.Contains handles;
+// to find the cell a point lies in, find its nearest site with a KDTree instead.
+func Voronoi(points []*Point) ([]*Polygon, error) {
+	faces, err := sphereHull(points)
+	if err != nil {
+		return nil, err
+	}
+
+	// The circumcenter of each triangle, where the cells of its three points meet,
+	// is the point on the sphere above the middle of its face.
+	around := make([][]*hullFace, len(points))
+	for _, f := range faces {
+		for _, i := range f.v {
+			around[i] = append(around[i], f)
+		}
+	}
+
+	cells := make([]*Polygon, len(points))
+	for i, fs := range around {
+		if len(fs) == 0 {
+			continue
+		}
+
+		// Order the corners counterclockwise around the point, seen from above.
+		site := unitVector(points[i])
+		east := cross3([3]float64{0, 0, 1}, site)
+		if norm3(east) < hullTolerance {
+			east = [3]float64{0, 1, 0}
+		}
+		north := cross3(site, east)
+		angle := func(f *hullFace) float64 { return math.Atan2(dot3(f.normal, north), dot3(f.normal, east)) }
+		sort.Slice(fs, func(a, b int) bool { return angle(fs[a]) < angle(fs[b]) })
+
+		corners := make([]*Point, len(fs))
+		for j, f := range fs {
+			corners[j] = vectorPoint(f.normal)
+		}
+		cells[i] = NewPolygon(corners)
+	}
+
+	return cells, nil
+}
@@ -0,0 +1,108 @@
+package geo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// Ensures that the triangulation of points spread over the sphere covers it with empty circumcircles.
+func TestDelaunay(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var points []*Point
+	for i := 0; i < 500; i++ {
+		points = append(points, NewPoint(math.Asin(r.Float64()*2-1)*180/math.Pi, r.Float64()*360-180))
+	}
+	points = append(points, NewPoint(points[0].Lat(), points[0].Lng()))
+
+	triangles, err := Delaunay(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Triangles covering a sphere number twice its points, less four; the duplicate isn't one of them.
+	if len(triangles) != 2*500-4 {
+		t.Errorf("Expected %d triangles, got %d", 2*500-4, len(triangles))
+	}
+
+	for _, tri := range triangles {
+		a, b, c := unitVector(points[tri[0]]), unitVector(points[tri[1]]), unitVector(points[tri[2]])
+		n := cross3([3]float64{b[0] - a[0], b[1] - a[1], b[2] - a[2]}, [3]float64{c[0] - a[0], c[1] - a[1], c[2] - a[2]})
+		if dot3(n, a) <= 0 {
+			t.Fatalf("Expected triangle %v to be wound counterclockwise from above", tri)
+		}
+
+		// No point lies nearer the triangle's circumcenter than its corners.
+		center := vectorPoint(n)
+		radius := center.GreatCircleDistance(points[tri[0]])
+		for i, p := range points {
+			if d := center.GreatCircleDistance(p); d < radius-1e-6 {
+				t.Fatalf("Expected point %d outside the circumcircle of %v, %fkm inside", i, tri, radius-d)
+			}
+		}
+
+		if tri[0] == 500 || tri[1] == 500 || tri[2] == 500 {
+			t.Errorf("Expected the duplicate point to be left out, got %v", tri)
+		}
+	}
+}
+
+// Ensures that points on a single great circle, or too few points, are refused.
+func TestDelaunayDegenerate(t *testing.T) {
+	for _, points := range [][]*Point{
+		{NewPoint(0, 0), NewPoint(0, 90), NewPoint(0, 180)},
+		{NewPoint(0, 0), NewPoint(0, 90), NewPoint(0, 180), NewPoint(0, -90), NewPoint(0, 45)},
+		{NewPoint(1, 1), NewPoint(1, 1), NewPoint(1, 1), NewPoint(1, 1)},
+	} {
+		if _, err := Delaunay(points); err != degenerateTriangulationError {
+			t.Errorf("Expected degenerateTriangulationError for %v, got %v", points, err)
+		}
+	}
+}
+
+// Ensures that each Voronoi cell surrounds its point, with corners equidistant from the points they divide.
+func TestVoronoi(t *testing.T) {
+	// The corners of an octahedron divide the sphere into six equal cells.
+	points := []*Point{NewPoint(90, 0), NewPoint(-90, 0), NewPoint(0, 0), NewPoint(0, 90), NewPoint(0, 180), NewPoint(0, -90)}
+	cells, err := Voronoi(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	corner := math.Atan(1/math.Sqrt2) * 180 / math.Pi
+	for i, cell := range cells {
+		if len(cell.Points()) != 4 {
+			t.Fatalf("Expected cell %d to be a square, got %v", i, cell.Points())
+		}
+
+		for _, c := range cell.Points() {
+			if math.Abs(math.Abs(c.Lat())-corner) > 1e-9 {
+				t.Errorf("Expected the corners of cell %d at latitude %f, got %v", i, corner, c)
+			}
+		}
+	}
+
+	// Stores in a city each get the territory nearest to them.
+	stores := []*Point{NewPoint(51.5074, -0.1278), NewPoint(51.5155, -0.0922), NewPoint(51.4975, -0.1357), NewPoint(51.5226, -0.1547), NewPoint(51.4700, -0.0500)}
+	cells, err = Voronoi(stores)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !cells[0].Contains(NewPoint(51.508, -0.125)) || cells[1].Contains(NewPoint(51.508, -0.125)) {
+		t.Error("Expected a point beside the first store to lie in its territory alone")
+	}
+
+	for i, cell := range cells {
+		for _, c := range cell.Points() {
+			nearest := math.Inf(1)
+			for _, s := range stores {
+				nearest = math.Min(nearest, c.GreatCircleDistance(s))
+			}
+
+			if d := c.GreatCircleDistance(stores[i]); d-nearest > 1e-6 {
+				t.Errorf("Expected each corner of cell %d to be as near its store as any, got %fkm further", i, d-nearest)
+			}
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package geo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// This contains the default URL for the Google Distance Matrix API.
+const DEFAULT_GOOGLE_DISTANCE_MATRIX_URL = "https://maps.googleapis.com/maps/api/distancematrix/json"
+
+// The Google Distance Matrix API's names for each TravelMode.
+var googleTravelModes = map[TravelMode]string{
+	TravelWalk:  "walking",
+	TravelBike:  "bicycling",
+	TravelDrive: "driving",
+}
+
+// This struct contains all the functionality
+// of interacting with the Google Distance Matrix API.
+type GoogleDistanceMatrix struct {
+	// If set, every request is counted against this Quota
+	// and refused once the "google-distance-matrix" daily budget is spent.
+	Quota *Quota
+
+	// The client used to issue requests.  Defaults to a client sharing its connections
+	// with every provider, tuned with SetTransportConfig.
+	HTTPClient *http.Client
+
+	// The URL of the API.  Defaults to DEFAULT_GOOGLE_DISTANCE_MATRIX_URL.
+	BaseURL string
+
+	apiKey string
+}
+
+// Creates and returns a pointer to a new GoogleDistanceMatrix configured by the passed in options.
+// Google's Distance Matrix API makes use of WithAPIKey, WithHTTPClient, WithBaseURL and WithQuota.
+func NewGoogleDistanceMatrix(opts ...Option) *GoogleDistanceMatrix {
+	c := newGeocoderConfig(opts)
+	return &GoogleDistanceMatrix{
+		Quota:      c.quota,
+		HTTPClient: c.client("google-distance-matrix"),
+		BaseURL:    c.baseURL,
+		apiKey:     c.apiKey,
+	}
+}
+
+// This struct contains selected fields from Google's distance matrix response.
+type googleDistanceMatrixResponse struct {
+	Status       string `json:"status"`
+	ErrorMessage string `json:"error_message"`
+	Rows         []struct {
+		Elements []struct {
+			Status   string `json:"status"`
+			Duration struct {
+				Value int64 `json:"value"`
+			} `json:"duration"`
+			DurationInTraffic *struct {
+				Value int64 `json:"value"`
+			} `json:"duration_in_traffic"`
+		} `json:"elements"`
+	} `json:"rows"`
+}
+
+// Returns how long it takes to travel from the passed in origin to the passed in destination by the passed in mode,
+// departing at the passed in time.  Driving journeys departing in the future take the expected traffic into account.
+// Implements the Router Interface.
+func (g *GoogleDistanceMatrix) TravelTime(origin, dest *Point, mode TravelMode, departAt time.Time) (time.Duration, error) {
+	return g.travelTime(context.Background(), origin, dest, mode, departAt)
+}
+
+// Returns nil if Google answers a canary request for a journey of no distance, or an error saying why it didn't,
+// such as ErrAuth for an invalid API key.  Implements the HealthChecker interface.
+func (g *GoogleDistanceMatrix) HealthCheck(ctx context.Context) error {
+	_, err := g.travelTime(ctx, healthCheckPoint, healthCheckPoint, TravelDrive, time.Time{})
+	return canaryError(err)
+}
+
+// Returns the travel time as TravelTime does, canceled if the passed in context is done first.
+func (g *GoogleDistanceMatrix) travelTime(ctx context.Context, origin, dest *Point, mode TravelMode, departAt time.Time) (time.Duration, error) {
+	googleMode, ok := googleTravelModes[mode]
+	if !ok {
+		return 0, unknownTravelModeError
+	}
+
+	if g.Quota != nil {
+		if err := g.Quota.Spend("google-distance-matrix"); err != nil {
+			return 0, err
+		}
+	}
+
+	base := g.BaseURL
+	if base == "" {
+		base = DEFAULT_GOOGLE_DISTANCE_MATRIX_URL
+	}
+
+	values := url.Values{
+		"origins":      {latLngParam(origin)},
+		"destinations": {latLngParam(dest)},
+		"mode":         {googleMode},
+	}
+	// Google refuses departure times in the past.
+	if departAt.After(time.Now()) {
+		values.Set("departure_time", strconv.FormatInt(departAt.Unix(), 10))
+	}
+	if g.apiKey != "" {
+		values.Set("key", g.apiKey)
+	}
+
+	data, err := providerGetContext(ctx, g.HTTPClient, "google distance matrix", base+"?"+values.Encode())
+	if err != nil {
+		return 0, err
+	}
+
+	res := &googleDistanceMatrixResponse{}
+	if err := json.Unmarshal(data, res); err != nil {
+		return 0, err
+	}
+
+	if res.Status != "OK" {
+		return 0, googleStatusError("google distance matrix", res.Status, res.ErrorMessage)
+	}
+
+	if len(res.Rows) == 0 || len(res.Rows[0].Elements) == 0 {
+		return 0, fmt.Errorf("google distance matrix: no route")
+	}
+
+	element := res.Rows[0].Elements[0]
+	if element.Status != "OK" {
+		return 0, googleStatusError("google distance matrix", element.Status, "")
+	}
+
+	seconds := element.Duration.Value
+	if element.DurationInTraffic != nil {
+		seconds = element.DurationInTraffic.Value
+	}
+
+	return time.Duration(seconds) * time.Second, nil
+}
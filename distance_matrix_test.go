@@ -0,0 +1,64 @@
+package geo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Ensures that GoogleDistanceMatrix asks for a journey and prefers its duration in traffic.
+func TestGoogleDistanceMatrixTravelTime(t *testing.T) {
+	depart := time.Now().Add(time.Hour).Truncate(time.Second)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("origins") != "51.5,-0.12" || q.Get("destinations") != "51.52,-0.08" || q.Get("mode") != "driving" || q.Get("key") != "secret" {
+			t.Errorf("Unexpected query: %v", q)
+		}
+
+		if q.Get("departure_time") != strconv.FormatInt(depart.Unix(), 10) {
+			t.Errorf("Expected a departure time of %d, got %s", depart.Unix(), q.Get("departure_time"))
+		}
+
+		w.Write([]byte(`{"status": "OK", "rows": [{"elements": [{
+			"status": "OK",
+			"distance": {"text": "3.9 km", "value": 3912},
+			"duration": {"text": "14 mins", "value": 840},
+			"duration_in_traffic": {"text": "19 mins", "value": 1140}
+		}]}]}`))
+	}))
+	defer server.Close()
+
+	g := NewGoogleDistanceMatrix(WithAPIKey("secret"), WithBaseURL(server.URL))
+	d, err := g.TravelTime(NewPoint(51.5, -0.12), NewPoint(51.52, -0.08), TravelDrive, depart)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if d != 19*time.Minute {
+		t.Errorf("Expected 19 minutes in traffic, got %v", d)
+	}
+}
+
+// Ensures that GoogleDistanceMatrix reports journeys it can't route.
+func TestGoogleDistanceMatrixNoRoute(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("departure_time") != "" {
+			t.Errorf("Expected no departure time for a past journey, got %v", r.URL.Query())
+		}
+		w.Write([]byte(`{"status": "OK", "rows": [{"elements": [{"status": "ZERO_RESULTS"}]}]}`))
+	}))
+	defer server.Close()
+
+	g := NewGoogleDistanceMatrix(WithBaseURL(server.URL))
+	_, err := g.TravelTime(NewPoint(51.5, -0.12), NewPoint(40.7, -74), TravelWalk, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err == nil || !strings.Contains(err.Error(), "ZERO_RESULTS") {
+		t.Errorf("Expected a no route error, got %v", err)
+	}
+
+	if _, err := g.TravelTime(NewPoint(0, 0), NewPoint(1, 1), TravelMode("sail"), time.Time{}); err != unknownTravelModeError {
+		t.Errorf("Expected an unknown travel mode error, got %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package geo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// This contains the default URL for the Google Elevation API.
+const DEFAULT_GOOGLE_ELEVATION_URL = "https://maps.googleapis.com/maps/api/elevation/json"
+
+// The most samples the Google Elevation API returns along one path.
+const MAX_ELEVATION_SAMPLES = 512
+
+// An ElevationProvider looks up the height of the ground along the great circle between two points.
+type ElevationProvider interface {
+	// Returns the elevations, in meters above sea level, of the passed in number of points
+	// spaced evenly along the great circle from one point to the other, both included.
+	ElevationProfile(from, to *Point, samples int) ([]float64, error)
+}
+
+// This struct contains all the functionality
+// of interacting with the Google Elevation API.
+type GoogleElevationProvider struct {
+	// If set, every request is counted against this Quota
+	// and refused once the "google-elevation" daily budget is spent.
+	Quota *Quota
+
+	// The client used to issue requests.  Defaults to a client sharing its connections
+	// with every provider, tuned with SetTransportConfig.
+	HTTPClient *http.Client
+
+	// The URL of the API.  Defaults to DEFAULT_GOOGLE_ELEVATION_URL.
+	BaseURL string
+
+	apiKey string
+}
+
+// Creates and returns a pointer to a new GoogleElevationProvider configured by the passed in options.
+// Google's Elevation API makes use of WithAPIKey, WithHTTPClient, WithBaseURL and WithQuota.
+func NewGoogleElevationProvider(opts ...Option) *GoogleElevationProvider {
+	c := newGeocoderConfig(opts)
+	return &GoogleElevationProvider{
+		Quota:      c.quota,
+		HTTPClient: c.client("google-elevation"),
+		BaseURL:    c.baseURL,
+		apiKey:     c.apiKey,
+	}
+}
+
+// This struct contains selected fields from Google's elevation response.
+type googleElevationResponse struct {
+	Status       string `json:"status"`
+	ErrorMessage string `json:"error_message"`
+	Results      []struct {
+		Elevation float64 `json:"elevation"`
+	} `json:"results"`
+}
+
+// Returns the elevations of the passed in number of points spaced evenly between the passed in points,
+// which is clamped to between 2 and MAX_ELEVATION_SAMPLES.
+// Implements the ElevationProvider Interface.
+func (g *GoogleElevationProvider) ElevationProfile(from, to *Point, samples int) ([]float64, error) {
+	if g.Quota != nil {
+		if err := g.Quota.Spend("google-elevation"); err != nil {
+			return nil, err
+		}
+	}
+
+	base := g.BaseURL
+	if base == "" {
+		base = DEFAULT_GOOGLE_ELEVATION_URL
+	}
+
+	samples = max(2, min(samples, MAX_ELEVATION_SAMPLES))
+	values := url.Values{
+		"path":    {latLngParam(from) + "|" + latLngParam(to)},
+		"samples": {strconv.Itoa(samples)},
+	}
+	if g.apiKey != "" {
+		values.Set("key", g.apiKey)
+	}
+
+	data, err := httpGet(g.HTTPClient, base+"?"+values.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	res := &googleElevationResponse{}
+	if err := json.Unmarshal(data, res); err != nil {
+		return nil, err
+	}
+
+	if res.Status != "OK" {
+		return nil, googleStatusError("google elevation", res.Status, res.ErrorMessage)
+	}
+
+	if len(res.Results) != samples {
+		return nil, fmt.Errorf("google elevation: expected %d samples, got %d", samples, len(res.Results))
+	}
+
+	elevations := make([]float64, len(res.Results))
+	for i, r := range res.Results {
+		elevations[i] = r.Elevation
+	}
+
+	return elevations, nil
+}
+
+// Returns the passed in point as a "lat,lng" query parameter, as Google's web services take them.
+func latLngParam(p *Point) string {
+	return strconv.FormatFloat(p.lat, 'f', -1, 64) + "," + strconv.FormatFloat(p.lng, 'f', -1, 64)
+}
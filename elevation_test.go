@@ -0,0 +1,48 @@
+package geo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Ensures that GoogleElevationProvider asks for a sampled path and returns its elevations.
+func TestGoogleElevationProfile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("path") != "39.7391536,-104.9847034|36.455556,-116.866667" || q.Get("samples") != "3" || q.Get("key") != "secret" {
+			t.Errorf("Unexpected query: %v", q)
+		}
+		w.Write([]byte(`{"status": "OK", "results": [
+			{"elevation": 1608.637939453125, "location": {"lat": 39.7391536, "lng": -104.9847034}, "resolution": 4.771975994110107},
+			{"elevation": 2188.9, "location": {"lat": 38.4, "lng": -110.9}, "resolution": 19.1},
+			{"elevation": -50.78903579711914, "location": {"lat": 36.455556, "lng": -116.866667}, "resolution": 19.1}
+		]}`))
+	}))
+	defer server.Close()
+
+	g := NewGoogleElevationProvider(WithAPIKey("secret"), WithBaseURL(server.URL))
+	profile, err := g.ElevationProfile(NewPoint(39.7391536, -104.9847034), NewPoint(36.455556, -116.866667), 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(profile) != 3 || profile[0] != 1608.637939453125 || profile[2] != -50.78903579711914 {
+		t.Errorf("Unexpected profile: %v", profile)
+	}
+}
+
+// Ensures that GoogleElevationProvider reports the API's errors.
+func TestGoogleElevationProfileError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": "REQUEST_DENIED", "error_message": "The provided API key is invalid.", "results": []}`))
+	}))
+	defer server.Close()
+
+	g := NewGoogleElevationProvider(WithBaseURL(server.URL))
+	_, err := g.ElevationProfile(NewPoint(0, 0), NewPoint(1, 1), 10)
+	if err == nil || !strings.Contains(err.Error(), "REQUEST_DENIED") {
+		t.Errorf("Expected the API's error, got %v", err)
+	}
+}
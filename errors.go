@@ -0,0 +1,115 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// These are the errors that consumers can compare against with errors.Is, whichever provider failed,
+// so that code using several providers can tell failures apart without knowing each provider's errors.
+var (
+	// The provider found nothing for the request.
+	ErrNotFound = errors.New("no results found")
+
+	// The request was refused because a rate limit or budget, the provider's or a Quota's, is spent.
+	ErrQuota = errors.New("quota exceeded")
+
+	// The request was refused because its credentials are missing or invalid.
+	ErrAuth = errors.New("not authorized")
+
+	// The provider could not understand the request.
+	ErrBadRequest = errors.New("bad request")
+
+	// The provider could not be reached or failed to answer, and the request may succeed if retried later.
+	ErrProviderDown = errors.New("provider unavailable")
+)
+
+// Describes a request a provider failed, and why.  Matches its Kind, one of ErrNotFound, ErrQuota,
+// ErrAuth, ErrBadRequest or ErrProviderDown, when used with errors.Is, and the error that
+// caused it, such as a network error, when used with errors.Is or errors.As.
+type ProviderError struct {
+	Provider string
+	Kind     error
+
+	// The provider's own name for the failure, such as Google's "OVER_QUERY_LIMIT" or an HTTP status.
+	Status string
+
+	// The provider's explanation of the failure, if it gave one.
+	Message string
+
+	// The error that caused the failure, if any.
+	Err error
+}
+
+func (e *ProviderError) Error() string {
+	msg := e.Provider + ": " + e.Status
+	if e.Message != "" {
+		msg += " " + e.Message
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+
+	return msg
+}
+
+// Allows errors.Is(err, e.Kind) to succeed.
+func (e *ProviderError) Is(target error) bool {
+	return target == e.Kind
+}
+
+// Returns the error that caused the failure, if any.
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// Returns the kind of failure the passed in HTTP status code reports.
+func httpStatusKind(code int) error {
+	switch {
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		return ErrAuth
+	case code == http.StatusTooManyRequests:
+		return ErrQuota
+	case code >= 500:
+		return ErrProviderDown
+	default:
+		return ErrBadRequest
+	}
+}
+
+// Returns a ProviderError for a response with the passed in unsuccessful HTTP status code and body.
+func httpStatusError(provider string, code int, body []byte) error {
+	message := strings.TrimSpace(string(body))
+	if len(message) > 200 {
+		message = message[:200] + "..."
+	}
+
+	return &ProviderError{
+		Provider: provider,
+		Kind:     httpStatusKind(code),
+		Status:   fmt.Sprintf("%d %s", code, http.StatusText(code)),
+		Message:  message,
+	}
+}
+
+// Returns a ProviderError for a response from one of Google's web services with the passed in status
+// other than "OK", such as "OVER_QUERY_LIMIT", and error message.
+func googleStatusError(provider, status, message string) error {
+	var kind error
+	switch status {
+	case "ZERO_RESULTS", "NOT_FOUND":
+		kind = ErrNotFound
+	case "OVER_QUERY_LIMIT", "OVER_DAILY_LIMIT":
+		kind = ErrQuota
+	case "REQUEST_DENIED":
+		kind = ErrAuth
+	case "INVALID_REQUEST", "MAX_ELEMENTS_EXCEEDED", "MAX_DIMENSIONS_EXCEEDED", "MAX_ROUTE_LENGTH_EXCEEDED":
+		kind = ErrBadRequest
+	default:
+		kind = ErrProviderDown
+	}
+
+	return &ProviderError{Provider: provider, Kind: kind, Status: status, Message: message}
+}
@@ -0,0 +1,90 @@
+package geo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Ensures that Google's statuses map to the matching kind of error.
+func TestGoogleStatusError(t *testing.T) {
+	kinds := map[string]error{
+		"ZERO_RESULTS":     ErrNotFound,
+		"OVER_QUERY_LIMIT": ErrQuota,
+		"REQUEST_DENIED":   ErrAuth,
+		"INVALID_REQUEST":  ErrBadRequest,
+		"UNKNOWN_ERROR":    ErrProviderDown,
+	}
+
+	for status, kind := range kinds {
+		err := googleStatusError("google", status, "")
+		if !errors.Is(err, kind) {
+			t.Errorf("Expected %s to be %v, got %v", status, kind, err)
+		}
+	}
+}
+
+// Ensures that geocoders report failures with the provider-agnostic errors, whatever the provider.
+func TestGeocoderErrorTaxonomy(t *testing.T) {
+	responses := map[string]struct {
+		status int
+		body   string
+	}{
+		"/denied/json":        {http.StatusOK, `{"results":[],"status":"REQUEST_DENIED","error_message":"The provided API key is invalid."}`},
+		"/limit/json":         {http.StatusOK, `{"results":[],"status":"OVER_QUERY_LIMIT"}`},
+		"/none/json":          {http.StatusOK, `{"results":[],"status":"ZERO_RESULTS"}`},
+		"/down/json":          {http.StatusServiceUnavailable, `Service Unavailable`},
+		"/mq/search.php":      {http.StatusTooManyRequests, `Too Many Requests`},
+		"/mqauth/reverse.php": {http.StatusForbidden, `Forbidden`},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := responses[r.URL.Path]
+		w.WriteHeader(res.status)
+		w.Write([]byte(res.body))
+	}))
+	defer server.Close()
+
+	cases := []struct {
+		g    Geocoder
+		kind error
+	}{
+		{NewGoogleGeocoder(WithBaseURL(server.URL + "/denied/json")), ErrAuth},
+		{NewGoogleGeocoder(WithBaseURL(server.URL + "/limit/json")), ErrQuota},
+		{NewGoogleGeocoder(WithBaseURL(server.URL + "/none/json")), ErrNotFound},
+		{NewGoogleGeocoder(WithBaseURL(server.URL + "/down/json")), ErrProviderDown},
+		{NewMapQuestGeocoder(WithBaseURL(server.URL + "/mq")), ErrQuota},
+		{NewGazetteerGeocoder(nil), ErrNotFound},
+	}
+
+	for _, c := range cases {
+		if _, err := c.g.Geocode("Atlantis"); !errors.Is(err, c.kind) {
+			t.Errorf("Expected %v from %T, got %v", c.kind, c.g, err)
+		}
+	}
+
+	if _, err := NewMapQuestGeocoder(WithBaseURL(server.URL + "/mqauth")).ReverseGeocode(NewPoint(0, 0)); !errors.Is(err, ErrAuth) {
+		t.Errorf("Expected %v, got %v", ErrAuth, err)
+	}
+
+	var pe *ProviderError
+	if _, err := NewGoogleGeocoder(WithBaseURL(server.URL + "/denied/json")).Geocode("Atlantis"); !errors.As(err, &pe) || pe.Provider != "google" || pe.Message != "The provided API key is invalid." {
+		t.Errorf("Expected a ProviderError carrying Google's message, got %v", err)
+	}
+}
+
+// Ensures that a provider that can't be reached is down, and the cause is kept.
+func TestProviderUnreachable(t *testing.T) {
+	r := NewRecorder(t.TempDir(), Replay)
+	_, err := NewGoogleGeocoder(WithHTTPClient(r.Client())).Geocode("Atlantis")
+	if !errors.Is(err, ErrProviderDown) || !errors.Is(err, ErrNotRecorded) {
+		t.Errorf("Expected the provider to be down because nothing was recorded, got %v", err)
+	}
+}
+
+// Ensures that a spent Quota is a quota error.
+func TestBudgetExceededIsQuota(t *testing.T) {
+	if err := error(&BudgetExceededError{Provider: "google", Budget: 1}); !errors.Is(err, ErrQuota) || !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Expected a spent budget to match %v and %v", ErrQuota, ErrBudgetExceeded)
+	}
+}
@@ -0,0 +1,122 @@
+package geo
+
+import (
+	"errors"
+	"time"
+)
+
+// A way of travelling, each with its own speed.
+type TravelMode string
+
+const (
+	TravelWalk  TravelMode = "walk"
+	TravelBike  TravelMode = "bike"
+	TravelDrive TravelMode = "drive"
+)
+
+// This is the error that consumers receive when estimating a journey
+// by a mode with no SpeedProfile, and no Router to ask.
+var unknownTravelModeError = errors.New("no speed profile for travel mode")
+
+// A Router finds how long it takes to travel between two points by road or path,
+// such as a distance matrix service.
+type Router interface {
+	TravelTime(origin, dest *Point, mode TravelMode, departAt time.Time) (time.Duration, error)
+}
+
+// How fast a mode of travel goes, for estimating travel times without a Router.
+type SpeedProfile struct {
+	// The usual speed, in kilometers per hour.
+	Speed float64
+
+	// The speeds, in kilometers per hour, of journeys departing in each hour of the day,
+	// local to the departure time, such as slower driving in the rush hour.  Hours with
+	// no speed use Speed.
+	Hourly [24]float64
+
+	// How much further than the great circle distance journeys go, following roads and paths.
+	// Defaults to 1.
+	Detour float64
+}
+
+// Returns the speed, in kilometers per hour, of journeys departing at the passed in time.
+func (s SpeedProfile) SpeedAt(t time.Time) float64 {
+	if hourly := s.Hourly[t.Hour()]; hourly > 0 {
+		return hourly
+	}
+
+	return s.Speed
+}
+
+// The SpeedProfiles an ETAEstimator uses unless told otherwise: a walking pace, a relaxed cycling pace,
+// and driving at urban speeds, each following roads about 30% longer than the great circle.
+var DefaultSpeedProfiles = map[TravelMode]SpeedProfile{
+	TravelWalk:  {Speed: 5, Detour: 1.3},
+	TravelBike:  {Speed: 15, Detour: 1.3},
+	TravelDrive: {Speed: 40, Detour: 1.3},
+}
+
+// An estimated journey between two points.
+type ETA struct {
+	Departure time.Time
+	Arrival   time.Time
+	Duration  time.Duration
+
+	// Whether the journey was estimated from its great circle distance and a SpeedProfile,
+	// rather than found by the Router.
+	Estimated bool
+}
+
+// An ETAEstimator estimates when journeys will arrive, asking its Router when it has one,
+// and falling back to the great circle distance travelled at the speed of its mode's SpeedProfile
+// when it hasn't, or its Router fails.
+type ETAEstimator struct {
+	Router Router
+	Mode   TravelMode
+
+	// The speeds of each mode when estimating without the Router.  Defaults to DefaultSpeedProfiles.
+	Profiles map[TravelMode]SpeedProfile
+}
+
+// Creates and returns a pointer to a new ETAEstimator of journeys by the passed in mode,
+// asking the passed in Router, which may be nil.
+func NewETAEstimator(router Router, mode TravelMode) *ETAEstimator {
+	return &ETAEstimator{Router: router, Mode: mode}
+}
+
+// Returns when a journey from the passed in origin to the passed in destination,
+// departing at the passed in time, will arrive.  Returns an error only if the Router fails,
+// or there is none, and there is no SpeedProfile with a speed for the estimator's mode.
+func (e *ETAEstimator) ETA(origin, dest *Point, departAt time.Time) (*ETA, error) {
+	var routerErr error
+	if e.Router != nil {
+		d, err := e.Router.TravelTime(origin, dest, e.Mode, departAt)
+		if err == nil {
+			return &ETA{Departure: departAt, Arrival: departAt.Add(d), Duration: d}, nil
+		}
+		routerErr = err
+	}
+
+	profiles := e.Profiles
+	if profiles == nil {
+		profiles = DefaultSpeedProfiles
+	}
+
+	profile, ok := profiles[e.Mode]
+	speed := profile.SpeedAt(departAt)
+	if !ok || speed <= 0 {
+		if routerErr != nil {
+			return nil, routerErr
+		}
+		return nil, unknownTravelModeError
+	}
+
+	detour := profile.Detour
+	if detour <= 0 {
+		detour = 1
+	}
+
+	hours := origin.GreatCircleDistance(dest) * detour / speed
+	d := time.Duration(hours * float64(time.Hour)).Round(time.Second)
+	return &ETA{Departure: departAt, Arrival: departAt.Add(d), Duration: d, Estimated: true}, nil
+}
@@ -0,0 +1,77 @@
+package geo
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// A Router that returns a fixed travel time or error, recording what it is asked.
+type fixedRouter struct {
+	d     time.Duration
+	err   error
+	modes []TravelMode
+}
+
+func (r *fixedRouter) TravelTime(origin, dest *Point, mode TravelMode, departAt time.Time) (time.Duration, error) {
+	r.modes = append(r.modes, mode)
+	return r.d, r.err
+}
+
+// Ensures that ETAs are found by the Router when it has one.
+func TestETARouter(t *testing.T) {
+	router := &fixedRouter{d: 25 * time.Minute}
+	depart := time.Date(2024, 6, 3, 8, 0, 0, 0, time.UTC)
+
+	eta, err := NewETAEstimator(router, TravelBike).ETA(NewPoint(51.5, -0.12), NewPoint(51.52, -0.08), depart)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if eta.Estimated || eta.Duration != 25*time.Minute || !eta.Arrival.Equal(depart.Add(25*time.Minute)) {
+		t.Errorf("Expected the router's travel time, got %+v", eta)
+	}
+
+	if len(router.modes) != 1 || router.modes[0] != TravelBike {
+		t.Errorf("Expected the router to be asked about cycling, got %v", router.modes)
+	}
+}
+
+// Ensures that ETAs fall back to speed profiles without a working Router.
+func TestETAFallback(t *testing.T) {
+	origin, dest := NewPoint(0, 0), NewPoint(0, 0.1)
+	km := origin.GreatCircleDistance(dest)
+	depart := time.Date(2024, 6, 3, 8, 0, 0, 0, time.UTC)
+
+	e := NewETAEstimator(&fixedRouter{err: errors.New("router down")}, TravelWalk)
+	eta, err := e.ETA(origin, dest, depart)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := time.Duration(km * 1.3 / 5 * float64(time.Hour)).Round(time.Second)
+	if !eta.Estimated || eta.Duration != expected || !eta.Arrival.Equal(depart.Add(expected)) {
+		t.Errorf("Expected an estimate of %v, got %+v", expected, eta)
+	}
+
+	// Driving is slower in the morning rush hour.
+	rush := SpeedProfile{Speed: 40}
+	rush.Hourly[8] = 20
+	e = NewETAEstimator(nil, TravelDrive)
+	e.Profiles = map[TravelMode]SpeedProfile{TravelDrive: rush}
+
+	morning, _ := e.ETA(origin, dest, depart)
+	evening, _ := e.ETA(origin, dest, depart.Add(12*time.Hour))
+	if morning.Duration != 2*evening.Duration || evening.Duration != time.Duration(km/40*float64(time.Hour)).Round(time.Second) {
+		t.Errorf("Expected the rush hour to take twice as long, got %v and %v", morning.Duration, evening.Duration)
+	}
+
+	if _, err := NewETAEstimator(nil, TravelMode("sail")).ETA(origin, dest, depart); err != unknownTravelModeError {
+		t.Errorf("Expected an unknown travel mode error, got %v", err)
+	}
+
+	failure := errors.New("router down")
+	if _, err := NewETAEstimator(&fixedRouter{err: failure}, TravelMode("sail")).ETA(origin, dest, depart); err != failure {
+		t.Errorf("Expected the router's error without a profile to fall back to, got %v", err)
+	}
+}
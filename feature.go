@@ -0,0 +1,464 @@
+package geo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// A Geometry is a shape that a Feature can carry: a *Point, a *Polygon or a Line.
+type Geometry interface {
+	// Returns the GeoJSON type of the geometry, e.g. "Point".
+	GeometryType() string
+}
+
+// A Line is a sequence of points joined by straight edges.
+type Line []*Point
+
+// Returns "Point".
+func (p *Point) GeometryType() string {
+	return "Point"
+}
+
+// Returns "Polygon".
+func (p *Polygon) GeometryType() string {
+	return "Polygon"
+}
+
+// Returns "LineString".
+func (l Line) GeometryType() string {
+	return "LineString"
+}
+
+// A Feature is a geometry along with the attributes that describe it,
+// such as a delivery zone and its name, or a store and its opening hours.
+type Feature struct {
+	ID         interface{}
+	Geometry   Geometry
+	Properties map[string]interface{}
+}
+
+// Creates and returns a pointer to a new Feature with the passed in geometry and no properties.
+func NewFeature(g Geometry) *Feature {
+	return &Feature{Geometry: g, Properties: make(map[string]interface{})}
+}
+
+// Sets the passed in property, returning the Feature so that calls can be chained.
+func (f *Feature) Set(key string, value interface{}) *Feature {
+	if f.Properties == nil {
+		f.Properties = make(map[string]interface{})
+	}
+
+	f.Properties[key] = value
+	return f
+}
+
+// Returns the passed in property as a string, and whether or not it is one.
+func (f *Feature) PropertyString(key string) (string, bool) {
+	s, ok := f.Properties[key].(string)
+	return s, ok
+}
+
+// Returns the passed in property as a float64, and whether or not it is a number.
+func (f *Feature) PropertyFloat(key string) (float64, bool) {
+	switch v := f.Properties[key].(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		n, err := v.Float64()
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// Returns the passed in property as an int64, and whether or not it is a whole number.
+func (f *Feature) PropertyInt(key string) (int64, bool) {
+	switch v := f.Properties[key].(type) {
+	case int:
+		return int64(v), true
+	case int64:
+		return v, true
+	case json.Number:
+		n, err := v.Int64()
+		return n, err == nil
+	}
+
+	n, ok := f.PropertyFloat(key)
+	if !ok || n != math.Trunc(n) || math.Abs(n) > 1<<53 {
+		return 0, false
+	}
+
+	return int64(n), true
+}
+
+// Returns the passed in property as a bool, and whether or not it is one.
+func (f *Feature) PropertyBool(key string) (bool, bool) {
+	b, ok := f.Properties[key].(bool)
+	return b, ok
+}
+
+// The types of value a property may be required to have.
+type PropertyType int
+
+const (
+	AnyProperty PropertyType = iota
+	StringProperty
+	NumberProperty
+	IntegerProperty
+	BoolProperty
+)
+
+// Returns the name of the PropertyType.
+func (t PropertyType) String() string {
+	switch t {
+	case StringProperty:
+		return "string"
+	case NumberProperty:
+		return "number"
+	case IntegerProperty:
+		return "integer"
+	case BoolProperty:
+		return "bool"
+	default:
+		return "any"
+	}
+}
+
+// Describes what a single property must look like.
+type PropertyRule struct {
+	Type     PropertyType
+	Required bool
+}
+
+// A PropertySchema describes the properties a Feature is expected to have, keyed by name.
+// Properties not in the schema are allowed.
+type PropertySchema map[string]PropertyRule
+
+// This is the error that consumers can compare against with errors.Is
+// when a Feature's properties don't match a PropertySchema.
+var ErrInvalidProperty = errors.New("invalid feature property")
+
+// Describes a property that doesn't match a PropertySchema.
+// Matches ErrInvalidProperty when used with errors.Is.
+type PropertyError struct {
+	Key    string
+	Reason string
+}
+
+func (e *PropertyError) Error() string {
+	return fmt.Sprintf("property %q %s", e.Key, e.Reason)
+}
+
+// Allows errors.Is(err, ErrInvalidProperty) to succeed.
+func (e *PropertyError) Is(target error) bool {
+	return target == ErrInvalidProperty
+}
+
+// Returns a *PropertyError for the first property, by name, of the passed in
+// Feature that doesn't match the schema, or nil if they all do.
+func (s PropertySchema) Validate(f *Feature) error {
+	keys := make([]string, 0, len(s))
+	for key := range s {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		rule := s[key]
+		value, present := f.Properties[key]
+		if !present || value == nil {
+			if rule.Required {
+				return &PropertyError{Key: key, Reason: "is required"}
+			}
+			continue
+		}
+
+		ok := true
+		switch rule.Type {
+		case StringProperty:
+			_, ok = f.PropertyString(key)
+		case NumberProperty:
+			_, ok = f.PropertyFloat(key)
+		case IntegerProperty:
+			_, ok = f.PropertyInt(key)
+		case BoolProperty:
+			_, ok = f.PropertyBool(key)
+		}
+
+		if !ok {
+			return &PropertyError{Key: key, Reason: fmt.Sprintf("must be a %s, got %T", rule.Type, value)}
+		}
+	}
+
+	return nil
+}
+
+// The GeoJSON representation of a geometry.
+type geoJSONGeometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// The GeoJSON representation of a feature.
+type geoJSONFeature struct {
+	Type       string                 `json:"type"`
+	ID         interface{}            `json:"id,omitempty"`
+	Geometry   *geoJSONGeometry       `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// Returns the points at the passed in GeoJSON positions.
+func pointsFromPositions(positions [][]float64) ([]*Point, error) {
+	points := make([]*Point, len(positions))
+	for i, pos := range positions {
+		if len(pos) < 2 {
+			return nil, fmt.Errorf("GeoJSON position has %d coordinates", len(pos))
+		}
+		points[i] = NewPoint(pos[1], pos[0])
+	}
+
+	return points, nil
+}
+
+// Appends the GeoJSON position of the passed in point, [lng, lat], to dst.
+func appendGeoJSONPosition(dst []byte, p *Point, precision int) []byte {
+	dst = append(dst, '[')
+	dst = appendCoordinatePrecision(dst, p.lng, precision)
+	dst = append(dst, ',')
+	dst = appendCoordinatePrecision(dst, p.lat, precision)
+	return append(dst, ']')
+}
+
+// Appends the GeoJSON positions of the passed in points to dst.
+func appendGeoJSONPositions(dst []byte, points []*Point, precision int) []byte {
+	dst = append(dst, '[')
+	for i, p := range points {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = appendGeoJSONPosition(dst, p, precision)
+	}
+
+	return append(dst, ']')
+}
+
+// Returns the GeoJSON representation of the passed in geometry,
+// its coordinates rounded to the passed in number of decimal places.
+func marshalGeometry(g Geometry, precision int) (*geoJSONGeometry, error) {
+	var coordinates []byte
+	switch g := g.(type) {
+	case nil:
+		return nil, nil
+	case *Point:
+		coordinates = appendGeoJSONPosition(nil, g, precision)
+	case Line:
+		coordinates = appendGeoJSONPositions(nil, g, precision)
+	case *Polygon:
+		// GeoJSON rings repeat their first position at the end.
+		ring := g.Points()
+		if len(ring) > 0 && *ring[0] != *ring[len(ring)-1] {
+			ring = append(ring[:len(ring):len(ring)], ring[0])
+		}
+		coordinates = append(appendGeoJSONPositions([]byte{'['}, ring, precision), ']')
+	default:
+		return nil, fmt.Errorf("cannot encode %s geometry as GeoJSON", g.GeometryType())
+	}
+
+	return &geoJSONGeometry{Type: g.GeometryType(), Coordinates: coordinates}, nil
+}
+
+// Returns the geometry the passed in GeoJSON represents, its polygons repaired with Polygon.Normalize
+// if normalize is true.
+func unmarshalGeometry(g *geoJSONGeometry, normalize bool) (Geometry, error) {
+	if g == nil {
+		return nil, nil
+	}
+
+	switch g.Type {
+	case "Point":
+		var pos []float64
+		if err := json.Unmarshal(g.Coordinates, &pos); err != nil {
+			return nil, err
+		}
+
+		points, err := pointsFromPositions([][]float64{pos})
+		if err != nil {
+			return nil, err
+		}
+		return points[0], nil
+	case "LineString":
+		var positions [][]float64
+		if err := json.Unmarshal(g.Coordinates, &positions); err != nil {
+			return nil, err
+		}
+
+		points, err := pointsFromPositions(positions)
+		if err != nil {
+			return nil, err
+		}
+		return Line(points), nil
+	case "Polygon":
+		var rings [][][]float64
+		if err := json.Unmarshal(g.Coordinates, &rings); err != nil {
+			return nil, err
+		}
+
+		if len(rings) != 1 {
+			return nil, fmt.Errorf("GeoJSON polygons must have exactly one ring, got %d", len(rings))
+		}
+
+		points, err := pointsFromPositions(rings[0])
+		if err != nil {
+			return nil, err
+		}
+
+		if n := len(points); n > 1 && *points[0] == *points[n-1] {
+			points = points[:n-1]
+		}
+
+		polygon := NewPolygon(points)
+		if normalize {
+			polygon.Normalize()
+		}
+		return polygon, nil
+	default:
+		return nil, fmt.Errorf("unsupported GeoJSON geometry type %q", g.Type)
+	}
+}
+
+// Renders the current Feature as a GeoJSON Feature, its coordinates written with CoordinatePrecision decimal places.
+// Implements the json.Marshaler Interface.
+func (f *Feature) MarshalJSON() ([]byte, error) {
+	return f.MarshalGeoJSON(CoordinatePrecision())
+}
+
+// Renders the current Feature as a GeoJSON Feature, its coordinates rounded to the passed in number
+// of decimal places, or written exactly if it is FULL_PRECISION.
+func (f *Feature) MarshalGeoJSON(precision int) ([]byte, error) {
+	geometry, err := marshalGeometry(f.Geometry, precision)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(geoJSONFeature{
+		Type:       "Feature",
+		ID:         f.ID,
+		Geometry:   geometry,
+		Properties: f.Properties,
+	})
+}
+
+// Decodes the current Feature from a GeoJSON Feature, its polygon read as written.
+// Implements the json.Unmarshaler Interface.
+func (f *Feature) UnmarshalJSON(data []byte) error {
+	return f.UnmarshalGeoJSON(data, false)
+}
+
+// Decodes the current Feature from a GeoJSON Feature.  If normalize is true, a polygon is repaired
+// with Polygon.Normalize, so that data from tools winding rings the other way, or repeating points, reads alike.
+func (f *Feature) UnmarshalGeoJSON(data []byte, normalize bool) error {
+	var raw geoJSONFeature
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if raw.Type != "Feature" {
+		return fmt.Errorf("expected a GeoJSON Feature, got %q", raw.Type)
+	}
+
+	geometry, err := unmarshalGeometry(raw.Geometry, normalize)
+	if err != nil {
+		return err
+	}
+
+	if raw.Properties == nil {
+		raw.Properties = make(map[string]interface{})
+	}
+
+	*f = Feature{ID: raw.ID, Geometry: geometry, Properties: raw.Properties}
+	return nil
+}
+
+// A FeatureCollection is a list of features, rendered as a GeoJSON FeatureCollection.
+type FeatureCollection struct {
+	Features []*Feature
+}
+
+// Renders the current FeatureCollection as a GeoJSON FeatureCollection,
+// its coordinates written with CoordinatePrecision decimal places.
+// Implements the json.Marshaler Interface.
+func (c *FeatureCollection) MarshalJSON() ([]byte, error) {
+	return c.MarshalGeoJSON(CoordinatePrecision())
+}
+
+// Renders the current FeatureCollection as a GeoJSON FeatureCollection, its coordinates rounded
+// to the passed in number of decimal places, or written exactly if it is FULL_PRECISION.
+func (c *FeatureCollection) MarshalGeoJSON(precision int) ([]byte, error) {
+	features := make([]json.RawMessage, len(c.Features))
+	for i, f := range c.Features {
+		if f == nil {
+			features[i] = json.RawMessage("null")
+			continue
+		}
+
+		data, err := f.MarshalGeoJSON(precision)
+		if err != nil {
+			return nil, err
+		}
+		features[i] = data
+	}
+
+	return json.Marshal(struct {
+		Type     string            `json:"type"`
+		Features []json.RawMessage `json:"features"`
+	}{"FeatureCollection", features})
+}
+
+// Decodes the current FeatureCollection from a GeoJSON FeatureCollection, its polygons read as written.
+// Implements the json.Unmarshaler Interface.
+func (c *FeatureCollection) UnmarshalJSON(data []byte) error {
+	return c.UnmarshalGeoJSON(data, false)
+}
+
+// Decodes the current FeatureCollection from a GeoJSON FeatureCollection.  If normalize is true,
+// its polygons are repaired with Polygon.Normalize, as Feature.UnmarshalGeoJSON does.
+func (c *FeatureCollection) UnmarshalGeoJSON(data []byte, normalize bool) error {
+	var raw struct {
+		Type     string            `json:"type"`
+		Features []json.RawMessage `json:"features"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if raw.Type != "FeatureCollection" {
+		return fmt.Errorf("expected a GeoJSON FeatureCollection, got %q", raw.Type)
+	}
+
+	var features []*Feature
+	if raw.Features != nil {
+		features = make([]*Feature, len(raw.Features))
+	}
+	for i, data := range raw.Features {
+		if string(data) == "null" {
+			continue
+		}
+
+		features[i] = new(Feature)
+		if err := features[i].UnmarshalGeoJSON(data, normalize); err != nil {
+			return err
+		}
+	}
+
+	c.Features = features
+	return nil
+}
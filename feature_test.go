@@ -0,0 +1,125 @@
+package geo
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// Ensures that properties can be read back with the expected types.
+func TestFeatureProperties(t *testing.T) {
+	f := NewFeature(NewPoint(37.7749, -122.4194)).
+		Set("name", "Store 1042").
+		Set("orders", 12).
+		Set("rating", 4.5).
+		Set("open", true)
+
+	if name, ok := f.PropertyString("name"); !ok || name != "Store 1042" {
+		t.Errorf("Expected the name Store 1042, got %q", name)
+	}
+
+	if orders, ok := f.PropertyInt("orders"); !ok || orders != 12 {
+		t.Errorf("Expected 12 orders, got %d", orders)
+	}
+
+	if _, ok := f.PropertyInt("rating"); ok {
+		t.Error("Expected a fractional rating not to be read as an integer")
+	}
+
+	if rating, ok := f.PropertyFloat("rating"); !ok || rating != 4.5 {
+		t.Errorf("Expected a rating of 4.5, got %f", rating)
+	}
+
+	if open, ok := f.PropertyBool("open"); !ok || !open {
+		t.Error("Expected the store to be open")
+	}
+
+	if _, ok := f.PropertyString("missing"); ok {
+		t.Error("Expected a missing property not to be found")
+	}
+}
+
+// Ensures that a PropertySchema reports missing and mistyped properties.
+func TestPropertySchemaValidate(t *testing.T) {
+	schema := PropertySchema{
+		"name":   {Type: StringProperty, Required: true},
+		"orders": {Type: IntegerProperty},
+	}
+
+	if err := schema.Validate(NewFeature(nil).Set("name", "Zone A").Set("extra", 1)); err != nil {
+		t.Errorf("Expected a valid feature, got %v", err)
+	}
+
+	err := schema.Validate(NewFeature(nil).Set("orders", 3))
+	var propErr *PropertyError
+	if !errors.Is(err, ErrInvalidProperty) || !errors.As(err, &propErr) || propErr.Key != "name" {
+		t.Errorf("Expected the missing name to be reported, got %v", err)
+	}
+
+	err = schema.Validate(NewFeature(nil).Set("name", "Zone A").Set("orders", "three"))
+	if !errors.As(err, &propErr) || propErr.Key != "orders" {
+		t.Errorf("Expected the mistyped orders to be reported, got %v", err)
+	}
+}
+
+// Ensures that features of each geometry type survive a round trip through GeoJSON.
+func TestFeatureGeoJSONRoundTrip(t *testing.T) {
+	zone := NewPolygon([]*Point{NewPoint(37, -123), NewPoint(37, -122), NewPoint(38, -122)})
+	collection := &FeatureCollection{Features: []*Feature{
+		NewFeature(NewPoint(37.7749, -122.4194)).Set("name", "Store 1042").Set("orders", 12),
+		NewFeature(Line{NewPoint(0, 0), NewPoint(1, 1)}),
+		{ID: "zone-a", Geometry: zone, Properties: map[string]interface{}{"fee": 5.0}},
+	}}
+
+	data, err := json.Marshal(collection)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded FeatureCollection
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decoded.Features) != 3 {
+		t.Fatalf("Expected 3 features, got %d", len(decoded.Features))
+	}
+
+	store := decoded.Features[0]
+	if p, ok := store.Geometry.(*Point); !ok || p.Lat() != 37.7749 || p.Lng() != -122.4194 {
+		t.Errorf("Expected the store's point to survive, got %v", store.Geometry)
+	}
+
+	if orders, ok := store.PropertyInt("orders"); !ok || orders != 12 {
+		t.Errorf("Expected the store's orders to survive, got %v", store.Properties["orders"])
+	}
+
+	if line, ok := decoded.Features[1].Geometry.(Line); !ok || len(line) != 2 {
+		t.Errorf("Expected a line of 2 points, got %v", decoded.Features[1].Geometry)
+	}
+
+	z := decoded.Features[2]
+	if polygon, ok := z.Geometry.(*Polygon); !ok || len(polygon.Points()) != 3 || !polygon.Contains(NewPoint(37.6, -122.2)) {
+		t.Errorf("Expected the zone's polygon to survive without its closing point, got %v", z.Geometry)
+	}
+
+	if z.ID != "zone-a" {
+		t.Errorf("Expected the zone's id to survive, got %v", z.ID)
+	}
+}
+
+// Ensures that GeoJSON that isn't a feature, or has unsupported geometry, is rejected.
+func TestFeatureUnmarshalErrors(t *testing.T) {
+	invalid := []string{
+		`{"type": "Point", "coordinates": [0, 0]}`,
+		`{"type": "Feature", "geometry": {"type": "MultiPoint", "coordinates": [[0, 0]]}}`,
+		`{"type": "Feature", "geometry": {"type": "Point", "coordinates": [0]}}`,
+	}
+
+	for _, data := range invalid {
+		var f Feature
+		if err := json.Unmarshal([]byte(data), &f); err == nil {
+			t.Errorf("Expected %s to be rejected", data)
+		}
+	}
+}
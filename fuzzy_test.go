@@ -0,0 +1,40 @@
+package geo
+
+import (
+	"testing"
+)
+
+// Ensures that the Levenshtein distance counts insertions, deletions and substitutions.
+func TestLevenshtein(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"zürich", "zurich", 1},
+		{"sfo", "", 3},
+	}
+
+	for _, c := range cases {
+		if d := levenshtein(c.a, c.b); d != c.expected {
+			t.Errorf("Expected levenshtein(%q, %q) to be %d, got %d", c.a, c.b, c.expected, d)
+		}
+	}
+}
+
+// Ensures that trigram similarity is 1 for identical strings and falls as they diverge.
+func TestTrigramSimilarity(t *testing.T) {
+	same := trigramSimilarity(trigrams("warehouse"), trigrams("warehouse"))
+	near := trigramSimilarity(trigrams("warehouse"), trigrams("warehous"))
+	far := trigramSimilarity(trigrams("warehouse"), trigrams("brooklyn"))
+
+	if same != 1 {
+		t.Errorf("Expected identical strings to have a similarity of 1, got %f", same)
+	}
+
+	if !(near < same && far < near) {
+		t.Errorf("Expected similarity to fall as strings diverge, got %f, %f, %f", same, near, far)
+	}
+}
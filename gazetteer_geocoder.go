@@ -0,0 +1,270 @@
+package geo
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// This is the error that consumers receive when no gazetteer entry
+// resembles the query closely enough.  Matches ErrNotFound.
+var gazetteerNoMatchError error = &ProviderError{Provider: "gazetteer", Kind: ErrNotFound, Status: "ZERO_RESULTS"}
+
+// This is the error that a GazetteerGeocoder with no entries fails its health check with.
+var gazetteerEmptyError = errors.New("gazetteer has no entries")
+
+// The score a fuzzy match must reach before GazetteerGeocoder will return it.
+const DEFAULT_GAZETTEER_MIN_SCORE = 0.5
+
+// A single named place in a gazetteer.
+type GazetteerEntry struct {
+	Name string  `json:"name"`
+	Lat  float64 `json:"lat"`
+	Lng  float64 `json:"lng"`
+
+	// The country the place lies in, by ISO 3166-1 code or English name.  Optional.
+	Country string `json:"country,omitempty"`
+}
+
+// A gazetteer entry along with everything needed to match queries against it.
+type indexedGazetteerEntry struct {
+	GazetteerEntry
+	key      string
+	trigrams map[string]struct{}
+}
+
+// A GazetteerGeocoder resolves place names from a user-supplied list of
+// entries without calling any external API, which makes it suitable for
+// private names such as warehouse codes or store IDs.  Queries that don't
+// match an entry exactly are matched fuzzily, in the same way as MatchName.
+// Results are deterministic: ties go to the entry loaded first.
+type GazetteerGeocoder struct {
+	// The score, from 0 to 1, a fuzzy match must reach to be returned.
+	MinScore float64
+
+	entries []*indexedGazetteerEntry
+	exact   map[string]int
+	index   map[string][]int
+}
+
+// Creates and returns a pointer to a new GazetteerGeocoder over the passed in entries.
+func NewGazetteerGeocoder(entries []GazetteerEntry) *GazetteerGeocoder {
+	g := &GazetteerGeocoder{
+		MinScore: DEFAULT_GAZETTEER_MIN_SCORE,
+		exact:    make(map[string]int),
+		index:    make(map[string][]int),
+	}
+
+	for _, entry := range entries {
+		g.Add(entry)
+	}
+
+	return g
+}
+
+// Reads gazetteer entries from CSV.  The first row must be a header naming
+// the "name", "lat" and "lng" columns, and optionally a "country" column;
+// any other columns are ignored.
+func LoadGazetteerCSV(r io.Reader) (*GazetteerGeocoder, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]int{"name": -1, "lat": -1, "lng": -1}
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if _, ok := columns[column]; ok {
+			columns[column] = i
+		}
+	}
+
+	for column, i := range columns {
+		if i < 0 {
+			return nil, fmt.Errorf("gazetteer CSV is missing the %q column", column)
+		}
+	}
+
+	country := -1
+	for i, column := range header {
+		if strings.ToLower(strings.TrimSpace(column)) == "country" {
+			country = i
+		}
+	}
+
+	fields := 0
+	for _, i := range columns {
+		fields = max(fields, i+1)
+	}
+
+	g := NewGazetteerGeocoder(nil)
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return g, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(record) < fields {
+			return nil, fmt.Errorf("gazetteer CSV line %d: expected at least %d fields, got %d", line, fields, len(record))
+		}
+
+		lat, err := strconv.ParseFloat(strings.TrimSpace(record[columns["lat"]]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("gazetteer CSV line %d: %v", line, err)
+		}
+
+		lng, err := strconv.ParseFloat(strings.TrimSpace(record[columns["lng"]]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("gazetteer CSV line %d: %v", line, err)
+		}
+
+		entry := GazetteerEntry{Name: record[columns["name"]], Lat: lat, Lng: lng}
+		if country >= 0 && country < len(record) {
+			entry.Country = strings.TrimSpace(record[country])
+		}
+
+		g.Add(entry)
+	}
+}
+
+// Reads gazetteer entries from a JSON array of {"name", "lat", "lng"} objects.
+func LoadGazetteerJSON(r io.Reader) (*GazetteerGeocoder, error) {
+	var entries []GazetteerEntry
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, err
+	}
+
+	return NewGazetteerGeocoder(entries), nil
+}
+
+// Adds the passed in entry to the gazetteer.
+// If an entry with the same normalized name exists, the earlier one wins exact matches.
+func (g *GazetteerGeocoder) Add(entry GazetteerEntry) {
+	key := nameKey(entry.Name)
+	indexed := &indexedGazetteerEntry{
+		GazetteerEntry: entry,
+		key:            key,
+		trigrams:       trigrams(key),
+	}
+
+	i := len(g.entries)
+	g.entries = append(g.entries, indexed)
+
+	if _, ok := g.exact[key]; !ok {
+		g.exact[key] = i
+	}
+
+	for t := range indexed.trigrams {
+		g.index[t] = append(g.index[t], i)
+	}
+}
+
+// Returns the number of entries in the gazetteer.
+func (g *GazetteerGeocoder) Len() int {
+	return len(g.entries)
+}
+
+// Returns the entry that best matches the passed in query along with its
+// score from 0 to 1, or an error if no entry reaches MinScore.
+func (g *GazetteerGeocoder) Match(query string) (*GazetteerEntry, float64, error) {
+	key := nameKey(query)
+	if i, ok := g.exact[key]; ok {
+		return &g.entries[i].GazetteerEntry, 1, nil
+	}
+
+	// Only entries sharing at least one trigram with the query can score above zero.
+	queryTrigrams := trigrams(key)
+	candidates := make(map[int]struct{})
+	for t := range queryTrigrams {
+		for _, i := range g.index[t] {
+			candidates[i] = struct{}{}
+		}
+	}
+
+	best, bestScore := -1, 0.0
+	for i := range candidates {
+		entry := g.entries[i]
+		score := math.Max(
+			trigramSimilarity(queryTrigrams, entry.trigrams),
+			levenshteinSimilarity(key, entry.key),
+		)
+
+		if score > bestScore || (score == bestScore && i < best) {
+			best, bestScore = i, score
+		}
+	}
+
+	if best < 0 || bestScore < g.MinScore {
+		return nil, bestScore, gazetteerNoMatchError
+	}
+
+	return &g.entries[best].GazetteerEntry, bestScore, nil
+}
+
+// Returns nil if the GazetteerGeocoder has entries to answer from.  Implements the HealthChecker interface.
+func (g *GazetteerGeocoder) HealthCheck(ctx context.Context) error {
+	if len(g.entries) == 0 {
+		return gazetteerEmptyError
+	}
+
+	return nil
+}
+
+// Returns the location of the entry that best matches the passed in query.
+// QueryOptions are ignored.
+func (g *GazetteerGeocoder) Geocode(query string, opts ...QueryOption) (*Point, error) {
+	entry, _, err := g.Match(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPoint(entry.Lat, entry.Lng), nil
+}
+
+// Returns the entry that best matches the passed in query as a GeocodeResult,
+// with its match score as its Confidence.  Entries are the exact sites they
+// name, so their Quality is Rooftop.  QueryOptions are ignored.
+func (g *GazetteerGeocoder) GeocodeResults(query string, opts ...QueryOption) ([]*GeocodeResult, error) {
+	entry, score, err := g.Match(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*GeocodeResult{{
+		Point:            NewPoint(entry.Lat, entry.Lng),
+		FormattedAddress: entry.Name,
+		CountryCode:      NormalizeCountryCode(entry.Country),
+		Provider:         "gazetteer",
+		Quality:          Rooftop,
+		Confidence:       score,
+	}}, nil
+}
+
+// Returns the name of the entry closest to the passed in Point.  QueryOptions are ignored.
+func (g *GazetteerGeocoder) ReverseGeocode(p *Point, opts ...QueryOption) (string, error) {
+	if len(g.entries) == 0 {
+		return "", gazetteerNoMatchError
+	}
+
+	best, bestDist := 0, math.Inf(1)
+	for i, entry := range g.entries {
+		dist := p.GreatCircleDistance(NewPoint(entry.Lat, entry.Lng))
+		if dist < bestDist {
+			best, bestDist = i, dist
+		}
+	}
+
+	return g.entries[best].Name, nil
+}
@@ -0,0 +1,117 @@
+package geo
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// Loads the test gazetteer from the passed in file.
+func gazetteerFromFile(t *testing.T, filename string, load func(*os.File) (*GazetteerGeocoder, error)) *GazetteerGeocoder {
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	g, err := load(file)
+	if err != nil {
+		t.Fatalf("%s failed to load: %v", filename, err)
+	}
+
+	return g
+}
+
+// Ensures that gazetteers load from CSV and JSON, and resolve exact and fuzzy queries.
+func TestGazetteerGeocoderGeocode(t *testing.T) {
+	gazetteers := map[string]*GazetteerGeocoder{
+		"csv":  gazetteerFromFile(t, "test/data/gazetteer.csv", func(f *os.File) (*GazetteerGeocoder, error) { return LoadGazetteerCSV(f) }),
+		"json": gazetteerFromFile(t, "test/data/gazetteer.json", func(f *os.File) (*GazetteerGeocoder, error) { return LoadGazetteerJSON(f) }),
+	}
+
+	for name, g := range gazetteers {
+		if g.Len() != 3 {
+			t.Errorf("%s: expected 3 entries, got %d", name, g.Len())
+		}
+
+		for _, query := range []string{"warehouse sfo-01", "Warehouse SFO01", "warehuose sfo-01"} {
+			p, err := g.Geocode(query)
+			if err != nil {
+				t.Errorf("%s: expected %q to match, got %v", name, query, err)
+				continue
+			}
+
+			if p.Lat() != 37.615223 || p.Lng() != -122.389979 {
+				t.Errorf("%s: expected %q to resolve to Warehouse SFO-01, got %v", name, query, p)
+			}
+		}
+
+		if _, err := g.Geocode("Kuala Lumpur"); err != gazetteerNoMatchError {
+			t.Errorf("%s: expected an unrelated query not to match, got %v", name, err)
+		}
+	}
+}
+
+// Ensures that match scores are reported, and exact matches score 1.
+func TestGazetteerGeocoderMatch(t *testing.T) {
+	g := NewGazetteerGeocoder([]GazetteerEntry{
+		{Name: "Store 1042", Lat: 1, Lng: 1},
+		{Name: "Store 1043", Lat: 2, Lng: 2},
+	})
+
+	entry, score, err := g.Match("STORE 1042")
+	if err != nil || entry.Name != "Store 1042" || score != 1 {
+		t.Errorf("Expected an exact match with a score of 1, got %v, %f, %v", entry, score, err)
+	}
+
+	// Equally good fuzzy matches resolve to the entry loaded first.
+	entry, score, err = g.Match("Store 104")
+	if err != nil || entry.Name != "Store 1042" || score >= 1 {
+		t.Errorf("Expected a deterministic fuzzy match on Store 1042, got %v, %f, %v", entry, score, err)
+	}
+}
+
+// Ensures that each entry's country is normalized to its alpha-2 code in GeocodeResults.
+func TestGazetteerGeocoderCountryCode(t *testing.T) {
+	gazetteers := map[string]*GazetteerGeocoder{
+		"csv":  gazetteerFromFile(t, "test/data/gazetteer.csv", func(f *os.File) (*GazetteerGeocoder, error) { return LoadGazetteerCSV(f) }),
+		"json": gazetteerFromFile(t, "test/data/gazetteer.json", func(f *os.File) (*GazetteerGeocoder, error) { return LoadGazetteerJSON(f) }),
+	}
+
+	expected := map[string]map[string]string{
+		"csv":  {"Warehouse SFO-01": "US", "Warehouse SEA-02": "US", "Store 1042 Brooklyn": "US"},
+		"json": {"Warehouse SFO-01": "US", "Warehouse SEA-02": "US", "Store 1042 Brooklyn": ""},
+	}
+
+	for name, g := range gazetteers {
+		for query, code := range expected[name] {
+			results, err := g.GeocodeResults(query)
+			if err != nil || results[0].CountryCode != code {
+				t.Errorf("%s: expected %q to be in %q, got %v (%v)", name, query, code, results, err)
+			}
+		}
+	}
+}
+
+// Ensures that reverse geocoding returns the nearest entry.
+func TestGazetteerGeocoderReverseGeocode(t *testing.T) {
+	g := gazetteerFromFile(t, "test/data/gazetteer.json", func(f *os.File) (*GazetteerGeocoder, error) { return LoadGazetteerJSON(f) })
+
+	name, err := g.ReverseGeocode(NewPoint(47.45, -122.30))
+	if err != nil || name != "Warehouse SEA-02" {
+		t.Errorf("Expected the nearest entry to be Warehouse SEA-02, got %q (%v)", name, err)
+	}
+
+	if _, err := NewGazetteerGeocoder(nil).ReverseGeocode(NewPoint(0, 0)); err == nil {
+		t.Error("Expected an empty gazetteer to fail to reverse geocode")
+	}
+}
+
+// Ensures that loading a CSV gazetteer fails, rather than panics, on a row missing fields.
+func TestLoadGazetteerCSVTruncatedRow(t *testing.T) {
+	csv := "name,lat,lng,country\nWarehouse SEA-02,47.45,-122.30,US\nWarehouse PDX-01,45.52\n"
+	_, err := LoadGazetteerCSV(strings.NewReader(csv))
+	if err == nil || !strings.Contains(err.Error(), "line 3: expected at least 3 fields") {
+		t.Errorf("Expected an error about the fields of line 3, got %v", err)
+	}
+}
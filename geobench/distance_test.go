@@ -0,0 +1,48 @@
+package geobench
+
+import (
+	"github.com/kellydunn/golang-geo"
+	"math"
+	"testing"
+)
+
+// Ensures that Karney's method agrees with GeographicLib, even between antipodal points.
+func TestKarney(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     *geo.Point
+		expected float64
+	}{
+		{"JFK to Heathrow", geo.NewPoint(40.6, -73.8), geo.NewPoint(51.6, -0.5), 5551.759400319},
+		{"pole to pole", geo.NewPoint(-90, 0), geo.NewPoint(90, 0), 20003.9314586},
+		{"antipodes on the equator", geo.NewPoint(0, 0), geo.NewPoint(0, 180), 20003.9314586},
+		{"nearly antipodal points", geo.NewPoint(0, 0), geo.NewPoint(0.5, 179.5), 19936.288579},
+		{"along the equator", geo.NewPoint(0, -10), geo.NewPoint(0, 10), 2226.389816},
+		{"the same point", geo.NewPoint(37.615223, -122.389979), geo.NewPoint(37.615223, -122.389979), 0},
+	}
+
+	for _, test := range tests {
+		if d := Karney(test.a, test.b); math.Abs(d-test.expected) > 1e-6 {
+			t.Errorf("Expected %s to be %fkm apart, got %f", test.name, test.expected, d)
+		}
+		if d := Karney(test.b, test.a); math.Abs(d-test.expected) > 1e-6 {
+			t.Errorf("Expected %s to be %fkm apart the other way around, got %f", test.name, test.expected, d)
+		}
+	}
+}
+
+// Ensures that Vincenty's method agrees with Karney's to a millimeter, but fails between nearly antipodal points.
+func TestVincenty(t *testing.T) {
+	jfk, lhr := geo.NewPoint(40.6, -73.8), geo.NewPoint(51.6, -0.5)
+	if d := Vincenty(jfk, lhr); math.Abs(d-Karney(jfk, lhr)) > 1e-6 {
+		t.Errorf("Expected %fkm, got %f", Karney(jfk, lhr), d)
+	}
+
+	if d := Vincenty(jfk, jfk); d != 0 {
+		t.Errorf("Expected no distance between the same point, got %f", d)
+	}
+
+	if d := Vincenty(geo.NewPoint(0, 0), geo.NewPoint(0.5, 179.7)); !math.IsNaN(d) {
+		t.Errorf("Expected Vincenty's method to fail between nearly antipodal points, got %f", d)
+	}
+}
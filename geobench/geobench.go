@@ -0,0 +1,245 @@
+// Package geobench measures how accurate, and how fast, each way of computing the distance between two points is,
+// across bands of latitude: the Haversine formula on a sphere, as golang-geo computes distances, and Vincenty's
+// and Karney's methods on the WGS84 ellipsoid.  Karney's method, accurate to nanometers, is the reference the
+// others' errors are measured against.  The resulting Table tells which method to choose for a given accuracy:
+//
+//	table := geobench.NewBenchmark(1).Run()
+//	fmt.Print(table)
+//	method, ok := table.Recommend(52.5, 1) // the fastest accurate to a meter at 52.5 degrees north
+//	km := method.Distance(a, b)
+package geobench
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/kellydunn/golang-geo"
+	"math"
+	"math/rand"
+	"text/tabwriter"
+	"time"
+)
+
+// The number of pairs of points a Benchmark measures each method with in each band, unless told otherwise.
+const DEFAULT_PAIRS = 1000
+
+// The longest distance, in kilometers, between the pairs of points a Benchmark measures, unless told otherwise:
+// about half way around the earth, so that nearly antipodal points, where Vincenty's method fails, are included.
+const DEFAULT_MAX_DISTANCE = 20000.0
+
+// The time a Benchmark spends timing each method in each band, unless told otherwise.
+const DEFAULT_TIMING_DURATION = 20 * time.Millisecond
+
+// A Method computes the distance, in kilometers, between two points, or NaN where it fails to.
+type Method struct {
+	Name     string
+	Distance func(a, b *geo.Point) float64
+}
+
+// Returns the methods a Benchmark compares: Haversine, Vincenty and Karney.
+func Methods() []Method {
+	return []Method{
+		{Name: "haversine", Distance: Haversine},
+		{Name: "vincenty", Distance: Vincenty},
+		{Name: "karney", Distance: Karney},
+	}
+}
+
+// Returns the distance, in kilometers, between the passed in points along a great circle of a sphere
+// of radius geo.EARTH_RADIUS, as geo.Point's GreatCircleDistance computes it.  The earth being flattened
+// at the poles, it is off by up to about half a percent.
+func Haversine(a, b *geo.Point) float64 {
+	return a.GreatCircleDistance(b)
+}
+
+// A Band of latitudes, in degrees, north or south of the equator.
+type Band struct {
+	Min, Max float64
+}
+
+// Returns the band as "30-45°".
+func (b Band) String() string {
+	return fmt.Sprintf("%g-%g°", b.Min, b.Max)
+}
+
+// Returns whether or not the passed in latitude, north or south, lies within the band.
+func (b Band) Contains(lat float64) bool {
+	return math.Abs(lat) >= b.Min && math.Abs(lat) <= b.Max
+}
+
+// Returns the bands a Benchmark measures, unless told otherwise: every 15 degrees from the equator to the poles.
+func DefaultBands() []Band {
+	return []Band{{0, 15}, {15, 30}, {30, 45}, {45, 60}, {60, 75}, {75, 90}}
+}
+
+// A Benchmark measures the errors and speed of Methods over random pairs of points, the first of each
+// within a band, and the second a random distance away from it in a random direction.
+// Set its fields after creating it to change the defaults.
+type Benchmark struct {
+	// The methods measured.
+	Methods []Method
+
+	// The bands of latitude measured.
+	Bands []Band
+
+	// The number of pairs of points measured in each band.
+	Pairs int
+
+	// The longest distance, in kilometers, between the points of a pair.
+	MaxDistance float64
+
+	// The time spent timing each method in each band.
+	TimingDuration time.Duration
+
+	seed int64
+}
+
+// Creates and returns a pointer to a new Benchmark of Methods over DefaultBands, drawing pairs of points
+// from a source seeded with the passed in seed, so that benchmarks seeded alike measure the same pairs.
+func NewBenchmark(seed int64) *Benchmark {
+	return &Benchmark{
+		Methods:        Methods(),
+		Bands:          DefaultBands(),
+		Pairs:          DEFAULT_PAIRS,
+		MaxDistance:    DEFAULT_MAX_DISTANCE,
+		TimingDuration: DEFAULT_TIMING_DURATION,
+		seed:           seed,
+	}
+}
+
+// The errors and speed of a Method over the pairs of points of a Band.
+type Result struct {
+	Method Method
+	Band   Band
+
+	// The largest and the mean difference, in meters, from Karney's method, over the pairs the method computed.
+	MaxError, MeanError float64
+
+	// The largest difference from Karney's method as a fraction of the distance.
+	MaxRelativeError float64
+
+	// The number of pairs the method failed to compute a distance for.
+	Failures int
+
+	// The mean time the method takes to compute a distance.
+	TimePerDistance time.Duration
+}
+
+// The Results of a Benchmark, by band, then method, in the order they were measured.
+type Table []Result
+
+// Measures each of the Benchmark's methods over the pairs of points of each of its bands,
+// and returns their Results.
+func (b *Benchmark) Run() Table {
+	r := rand.New(rand.NewSource(b.seed))
+
+	var table Table
+	for _, band := range b.Bands {
+		pairs := b.pairs(r, band)
+		reference := make([]float64, len(pairs))
+		for i, pair := range pairs {
+			reference[i] = Karney(pair[0], pair[1]) * 1000
+		}
+
+		for _, method := range b.Methods {
+			result := Result{Method: method, Band: band}
+			sum, computed := 0.0, 0
+			for i, pair := range pairs {
+				d := method.Distance(pair[0], pair[1]) * 1000
+				if math.IsNaN(d) {
+					result.Failures++
+					continue
+				}
+
+				e := math.Abs(d - reference[i])
+				result.MaxError = math.Max(result.MaxError, e)
+				if reference[i] > 0 {
+					result.MaxRelativeError = math.Max(result.MaxRelativeError, e/reference[i])
+				}
+				sum, computed = sum+e, computed+1
+			}
+			if computed > 0 {
+				result.MeanError = sum / float64(computed)
+			}
+
+			result.TimePerDistance = b.time(method, pairs)
+			table = append(table, result)
+		}
+	}
+
+	return table
+}
+
+// Returns the Benchmark's number of random pairs of points, the first within the passed in band,
+// north or south of the equator, each part of it as likely as any other of the same area.
+func (b *Benchmark) pairs(r *rand.Rand, band Band) [][2]*geo.Point {
+	sinMin, sinMax := math.Sin(band.Min*math.Pi/180), math.Sin(band.Max*math.Pi/180)
+
+	pairs := make([][2]*geo.Point, b.Pairs)
+	for i := range pairs {
+		lat := math.Asin(sinMin+r.Float64()*(sinMax-sinMin)) * 180 / math.Pi
+		if r.Intn(2) == 0 {
+			lat = -lat
+		}
+		a := geo.NewPoint(lat, 360*r.Float64()-180)
+
+		c := a.PointAtDistanceAndBearing(r.Float64()*b.MaxDistance, 360*r.Float64())
+		pairs[i] = [2]*geo.Point{a, c.Normalize()}
+	}
+
+	return pairs
+}
+
+// Returns the mean time the passed in method takes to compute the distances between the passed in pairs,
+// over as many rounds of them as fit in the Benchmark's TimingDuration, and at least one.
+func (b *Benchmark) time(method Method, pairs [][2]*geo.Point) time.Duration {
+	if len(pairs) == 0 {
+		return 0
+	}
+
+	start, n := time.Now(), 0
+	for {
+		for _, pair := range pairs {
+			method.Distance(pair[0], pair[1])
+		}
+		n += len(pairs)
+
+		if elapsed := time.Since(start); elapsed >= b.TimingDuration {
+			return elapsed / time.Duration(n)
+		}
+	}
+}
+
+// Returns the fastest method that never failed, and whose largest error was within the passed in tolerance,
+// in meters, in the band the passed in latitude lies within.  Returns false if there is no such method,
+// or no such band.
+func (t Table) Recommend(lat, tolerance float64) (Method, bool) {
+	var best *Result
+	for i := range t {
+		r := &t[i]
+		if !r.Band.Contains(lat) || r.Failures > 0 || r.MaxError > tolerance {
+			continue
+		}
+		if best == nil || r.TimePerDistance < best.TimePerDistance {
+			best = r
+		}
+	}
+
+	if best == nil {
+		return Method{}, false
+	}
+	return best.Method, true
+}
+
+// Returns the table as aligned columns of text, a row for each Result.
+func (t Table) String() string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "band\tmethod\tmax error (m)\tmean error (m)\tmax relative error\tfailures\ttime\t")
+	for _, r := range t {
+		fmt.Fprintf(w, "%s\t%s\t%.6g\t%.6g\t%.3g\t%d\t%v\t\n",
+			r.Band, r.Method.Name, r.MaxError, r.MeanError, r.MaxRelativeError, r.Failures, r.TimePerDistance)
+	}
+	w.Flush()
+
+	return buf.String()
+}
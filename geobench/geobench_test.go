@@ -0,0 +1,73 @@
+package geobench
+
+import (
+	"github.com/kellydunn/golang-geo"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Returns a Benchmark small and quick enough for tests.
+func testBenchmark(maxDistance float64) *Benchmark {
+	b := NewBenchmark(1)
+	b.Pairs = 200
+	b.MaxDistance = maxDistance
+	b.TimingDuration = time.Millisecond
+	return b
+}
+
+// Ensures that a Benchmark measures each method in each band, against Karney's method.
+func TestBenchmarkRun(t *testing.T) {
+	table := testBenchmark(DEFAULT_MAX_DISTANCE).Run()
+	if len(table) != len(DefaultBands())*len(Methods()) {
+		t.Fatalf("Expected a result for each method in each band, got %d", len(table))
+	}
+
+	for _, r := range table {
+		if r.TimePerDistance <= 0 {
+			t.Errorf("Expected %s to have been timed in %s, got %v", r.Method.Name, r.Band, r.TimePerDistance)
+		}
+
+		switch r.Method.Name {
+		case "karney":
+			if r.MaxError != 0 || r.Failures != 0 {
+				t.Errorf("Expected Karney's method to be the reference in %s, got %+v", r.Band, r)
+			}
+		case "vincenty":
+			if r.MaxError > 0.001 {
+				t.Errorf("Expected Vincenty's method to be accurate to a millimeter in %s, got %fm", r.Band, r.MaxError)
+			}
+		case "haversine":
+			if r.MaxError < 100 || r.MaxRelativeError > 0.006 || r.MeanError > r.MaxError {
+				t.Errorf("Expected the Haversine formula to be off by up to half a percent in %s, got %+v", r.Band, r)
+			}
+		}
+	}
+
+	if s := table.String(); !strings.Contains(s, "45-60°") || strings.Count(s, "\n") != len(table)+1 {
+		t.Errorf("Expected a row for each result, got\n%s", s)
+	}
+}
+
+// Ensures that the fastest method accurate enough is recommended.
+func TestTableRecommend(t *testing.T) {
+	table := testBenchmark(10).Run()
+
+	// Even over 10km, the Haversine formula is off by meters, but no more than 50.
+	if method, ok := table.Recommend(-52.5, 50); !ok || method.Name != "haversine" {
+		t.Errorf("Expected the Haversine formula within 50m, got %v", method.Name)
+	}
+
+	if method, ok := table.Recommend(52.5, 0.001); !ok || method.Name == "haversine" {
+		t.Errorf("Expected an ellipsoidal method within a millimeter, got %v", method.Name)
+	} else if d := method.Distance(geo.NewPoint(52.5, 13.4), geo.NewPoint(52.5, 13.5)); d < 6.7 || d > 6.8 {
+		t.Errorf("Expected the recommended method to compute distances, got %fkm", d)
+	}
+
+	if _, ok := table.Recommend(52.5, -1); ok {
+		t.Error("Expected no method to be recommended for an impossible tolerance")
+	}
+	if _, ok := table.Recommend(91, 1); ok {
+		t.Error("Expected no method to be recommended outside the bands")
+	}
+}
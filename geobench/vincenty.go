@@ -0,0 +1,63 @@
+package geobench
+
+import (
+	"github.com/kellydunn/golang-geo"
+	"math"
+)
+
+// The number of iterations Vincenty's method takes before giving up on converging.
+const VINCENTY_MAX_ITERATIONS = 200
+
+// The change in longitude on the auxiliary sphere, in radians, below which Vincenty's method has converged:
+// about 0.06 millimeters.
+const vincentyTolerance = 1e-12
+
+// Returns the distance, in kilometers, between the passed in points along the shortest path between them
+// on the WGS84 ellipsoid, by Vincenty's method ("Direct and inverse solutions of geodesics on the ellipsoid",
+// 1975).  It is accurate to half a millimeter, but converges slowly, or not at all, between nearly antipodal
+// points; for those that it doesn't converge for within VINCENTY_MAX_ITERATIONS, it returns NaN.
+func Vincenty(a, b *geo.Point) float64 {
+	e := wgs84
+	l := (b.Lng() - a.Lng()) * math.Pi / 180
+	u1 := math.Atan(e.f1 * math.Tan(a.Lat()*math.Pi/180))
+	u2 := math.Atan(e.f1 * math.Tan(b.Lat()*math.Pi/180))
+	sinU1, cosU1 := math.Sincos(u1)
+	sinU2, cosU2 := math.Sincos(u2)
+
+	lambda := l
+	for i := 0; i < VINCENTY_MAX_ITERATIONS; i++ {
+		sinLambda, cosLambda := math.Sincos(lambda)
+		sinSigma := math.Hypot(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda)
+		if sinSigma == 0 {
+			return 0
+		}
+		cosSigma := sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma := math.Atan2(sinSigma, cosSigma)
+
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cos2Alpha := 1 - sinAlpha*sinAlpha
+
+		// Along the equator, cos2Alpha is zero, and so is the term it divides.
+		cos2SigmaM := 0.0
+		if cos2Alpha != 0 {
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cos2Alpha
+		}
+
+		c := e.f / 16 * cos2Alpha * (4 + e.f*(4-3*cos2Alpha))
+		previous := lambda
+		lambda = l + (1-c)*e.f*sinAlpha*(sigma+c*sinSigma*(cos2SigmaM+c*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+		if math.Abs(lambda-previous) > vincentyTolerance {
+			continue
+		}
+
+		u2 := cos2Alpha * e.ep2
+		a := 1 + u2/16384*(4096+u2*(-768+u2*(320-175*u2)))
+		b := u2 / 1024 * (256 + u2*(-128+u2*(74-47*u2)))
+		deltaSigma := b * sinSigma * (cos2SigmaM + b/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+			b/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+
+		return e.b * a * (sigma - deltaSigma) / 1000
+	}
+
+	return math.NaN()
+}
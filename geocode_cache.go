@@ -0,0 +1,500 @@
+package geo
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	bolt "go.etcd.io/bbolt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// The name of the bolt bucket that holds all cached geocodes.
+var geocodeCacheBucket = []byte("geocodes")
+
+// This is the error that consumers receive when a cached geocoder
+// cannot answer a query from its cache and is refused upstream.
+var ErrCacheMiss = errors.New("cache miss")
+
+// A single cached geocoding result.
+// Forward geocodes populate Lat and Lng, reverse geocodes populate Address,
+// and other provider responses, such as those of an OverpassClient, populate Response.
+type CacheEntry struct {
+	Key      string    `json:"key"`
+	Lat      float64   `json:"lat"`
+	Lng      float64   `json:"lng"`
+	Address  string    `json:"address,omitempty"`
+	Response []byte    `json:"response,omitempty"`
+	Stored   time.Time `json:"stored"`
+}
+
+// A GeocodeCache persists geocoding results to a BoltDB file on disk,
+// so that long-running batch jobs can survive restarts without re-geocoding.
+// Entries are keyed by their normalized query.
+type GeocodeCache struct {
+	db *bolt.DB
+
+	// The number of entries in the bucket.  Only modified once a write
+	// transaction has committed, so that rolled back ones don't count.
+	entries atomic.Int64
+
+	// Entries older than TTL are treated as absent and removed on Evict.
+	// A TTL of zero keeps entries forever.
+	TTL time.Duration
+
+	// When more than MaxEntries entries are stored, the oldest are evicted.
+	// A MaxEntries of zero places no limit on the size of the cache.
+	MaxEntries int
+
+	// If set, every lookup is counted as a hit or a miss of the "geocode" cache.
+	Metrics *Metrics
+
+	// Used to determine entry age.  Overridable for testing.
+	now func() time.Time
+}
+
+// Opens (creating if necessary) the BoltDB file at path and returns
+// a pointer to a GeocodeCache backed by it.
+func OpenGeocodeCache(path string, ttl time.Duration, maxEntries int) (*GeocodeCache, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	c := &GeocodeCache{db: db, TTL: ttl, MaxEntries: maxEntries, now: time.Now}
+	err = db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(geocodeCacheBucket)
+		if err != nil {
+			return err
+		}
+
+		c.entries.Store(int64(b.Stats().KeyN))
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Closes the underlying BoltDB file.
+func (c *GeocodeCache) Close() error {
+	return c.db.Close()
+}
+
+// Returns the normalized form of a query used as a cache key:
+// lower case, with surrounding and repeated whitespace collapsed.
+func NormalizeQuery(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(query)), " ")
+}
+
+// Returns the cache key for a forward geocode of the passed in query.
+func geocodeCacheKey(query string) string {
+	return "geocode:" + NormalizeQuery(query)
+}
+
+// Returns the cache key for a reverse geocode of the passed in point.
+func reverseCacheKey(p *Point) string {
+	return fmt.Sprintf("reverse:%f,%f", p.lat, p.lng)
+}
+
+// Returns the passed in cache key, followed by the passed in QueryOptions if there are any,
+// so that requests without options share their entries with Get and Put.
+func withQueryKey(key string, opts []QueryOption) string {
+	if q := queryKey(opts); q != "" {
+		return key + "?" + q
+	}
+
+	return key
+}
+
+// Returns the cached Point for the passed in query, if one exists and has not expired.
+func (c *GeocodeCache) Get(query string) (*Point, bool) {
+	entry, ok := c.lookup(geocodeCacheKey(query))
+	if !ok {
+		return nil, false
+	}
+
+	return NewPoint(entry.Lat, entry.Lng), true
+}
+
+// Stores the passed in Point as the result of geocoding the passed in query.
+func (c *GeocodeCache) Put(query string, p *Point) error {
+	return c.store(&CacheEntry{Key: geocodeCacheKey(query), Lat: p.lat, Lng: p.lng})
+}
+
+// Returns the cached address for the passed in Point, if one exists and has not expired.
+func (c *GeocodeCache) GetAddress(p *Point) (string, bool) {
+	entry, ok := c.lookup(reverseCacheKey(p))
+	if !ok {
+		return "", false
+	}
+
+	return entry.Address, true
+}
+
+// Stores the passed in address as the result of reverse geocoding the passed in Point.
+func (c *GeocodeCache) PutAddress(p *Point, address string) error {
+	return c.store(&CacheEntry{Key: reverseCacheKey(p), Lat: p.lat, Lng: p.lng, Address: address})
+}
+
+// Returns the cached provider response stored under the passed in key, if one exists and has not expired.
+func (c *GeocodeCache) GetResponse(key string) ([]byte, bool) {
+	entry, ok := c.lookup("response:" + key)
+	if !ok {
+		return nil, false
+	}
+
+	return entry.Response, true
+}
+
+// Stores the passed in provider response under the passed in key.
+func (c *GeocodeCache) PutResponse(key string, data []byte) error {
+	return c.store(&CacheEntry{Key: "response:" + key, Response: data})
+}
+
+// Returns the number of entries currently stored, including expired ones.
+func (c *GeocodeCache) Len() int {
+	return int(c.entries.Load())
+}
+
+// Removes all expired entries, then the oldest entries beyond MaxEntries.
+// Returns the number of entries removed.
+func (c *GeocodeCache) Evict() (int, error) {
+	removed := 0
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		n, err := c.evict(tx.Bucket(geocodeCacheBucket), 0)
+		removed = n
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	c.entries.Add(int64(-removed))
+	return removed, nil
+}
+
+// Writes every unexpired entry to w as a JSON array.
+func (c *GeocodeCache) ExportJSON(w io.Writer) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+
+	first := true
+	err := c.each(func(entry *CacheEntry) error {
+		if !first {
+			if _, err := io.WriteString(w, ",\n"); err != nil {
+				return err
+			}
+		}
+		first = false
+
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+
+		_, err = w.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "]\n")
+	return err
+}
+
+// Reads a JSON array of entries, as written by ExportJSON, into the cache.
+// Returns the number of entries imported.
+func (c *GeocodeCache) ImportJSON(r io.Reader) (int, error) {
+	dec := json.NewDecoder(r)
+	if _, err := dec.Token(); err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for dec.More() {
+		entry := &CacheEntry{}
+		if err := dec.Decode(entry); err != nil {
+			return n, err
+		}
+
+		if err := c.restore(entry); err != nil {
+			return n, err
+		}
+		n++
+	}
+
+	return n, nil
+}
+
+// Writes every unexpired geocode to w as CSV, with a header row of
+// key, lat, lng, address, stored.  Provider responses are only exported by ExportJSON.
+func (c *GeocodeCache) ExportCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"key", "lat", "lng", "address", "stored"}); err != nil {
+		return err
+	}
+
+	err := c.each(func(entry *CacheEntry) error {
+		if entry.Response != nil {
+			return nil
+		}
+
+		return cw.Write([]string{
+			entry.Key,
+			strconv.FormatFloat(entry.Lat, 'f', -1, 64),
+			strconv.FormatFloat(entry.Lng, 'f', -1, 64),
+			entry.Address,
+			entry.Stored.Format(time.RFC3339Nano),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// Reads CSV rows, as written by ExportCSV, into the cache.
+// Returns the number of entries imported.
+func (c *GeocodeCache) ImportCSV(r io.Reader) (int, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 5
+
+	if _, err := cr.Read(); err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+
+		lat, err := strconv.ParseFloat(record[1], 64)
+		if err != nil {
+			return n, err
+		}
+
+		lng, err := strconv.ParseFloat(record[2], 64)
+		if err != nil {
+			return n, err
+		}
+
+		stored, err := time.Parse(time.RFC3339Nano, record[4])
+		if err != nil {
+			return n, err
+		}
+
+		entry := &CacheEntry{Key: record[0], Lat: lat, Lng: lng, Address: record[3], Stored: stored}
+		if err := c.restore(entry); err != nil {
+			return n, err
+		}
+		n++
+	}
+}
+
+// Returns the entry stored under key if it exists and has not expired.
+func (c *GeocodeCache) lookup(key string) (*CacheEntry, bool) {
+	var entry *CacheEntry
+	c.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(geocodeCacheBucket).Get([]byte(key))
+		if data == nil {
+			return nil
+		}
+
+		e := &CacheEntry{}
+		if json.Unmarshal(data, e) == nil && !c.expired(e) {
+			entry = e
+		}
+		return nil
+	})
+
+	c.Metrics.recordCache("geocode", entry != nil)
+	return entry, entry != nil
+}
+
+// Stamps the passed in entry with the current time and stores it.
+func (c *GeocodeCache) store(entry *CacheEntry) error {
+	entry.Stored = c.now()
+	return c.restore(entry)
+}
+
+// Stores the passed in entry as is, evicting old entries if the cache is full.
+func (c *GeocodeCache) restore(entry *CacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	// The change in the number of entries, applied once the transaction has committed.
+	var delta int64
+	err = c.db.Update(func(tx *bolt.Tx) error {
+		delta = 0
+		b := tx.Bucket(geocodeCacheBucket)
+		existed := b.Get([]byte(entry.Key)) != nil
+		if err := b.Put([]byte(entry.Key), data); err != nil {
+			return err
+		}
+
+		if !existed {
+			delta++
+		}
+
+		if c.MaxEntries > 0 && c.Len()+int(delta) > c.MaxEntries {
+			// Make some headroom so that a full cache
+			// doesn't scan itself on every subsequent Put.
+			removed, err := c.evict(b, c.MaxEntries/10)
+			delta -= int64(removed)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	c.entries.Add(delta)
+	return nil
+}
+
+// Calls fn with every unexpired entry in key order.
+func (c *GeocodeCache) each(fn func(entry *CacheEntry) error) error {
+	return c.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(geocodeCacheBucket).ForEach(func(k, v []byte) error {
+			entry := &CacheEntry{}
+			if err := json.Unmarshal(v, entry); err != nil {
+				return err
+			}
+
+			if c.expired(entry) {
+				return nil
+			}
+
+			return fn(entry)
+		})
+	})
+}
+
+// Returns whether or not the passed in entry has outlived the TTL.
+func (c *GeocodeCache) expired(entry *CacheEntry) bool {
+	return c.TTL > 0 && c.now().Sub(entry.Stored) > c.TTL
+}
+
+// Removes expired entries from b, then the oldest entries until
+// at most MaxEntries - headroom remain.
+func (c *GeocodeCache) evict(b *bolt.Bucket, headroom int) (int, error) {
+	var live []*CacheEntry
+	var doomed [][]byte
+
+	err := b.ForEach(func(k, v []byte) error {
+		entry := &CacheEntry{}
+		if json.Unmarshal(v, entry) != nil || c.expired(entry) {
+			doomed = append(doomed, append([]byte(nil), k...))
+			return nil
+		}
+
+		live = append(live, entry)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if c.MaxEntries > 0 && len(live) > c.MaxEntries-headroom {
+		sort.Slice(live, func(i, j int) bool {
+			return live[i].Stored.Before(live[j].Stored)
+		})
+
+		for _, entry := range live[:len(live)-(c.MaxEntries-headroom)] {
+			doomed = append(doomed, []byte(entry.Key))
+		}
+	}
+
+	for _, k := range doomed {
+		if err := b.Delete(k); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(doomed), nil
+}
+
+// A Geocoder that answers from a GeocodeCache where it can,
+// and otherwise asks the wrapped Geocoder and remembers the result.
+// If the wrapped Geocoder refuses a request because its budget is spent,
+// CachedGeocoder keeps serving cached results in a cache-only mode,
+// returning ErrCacheMiss for anything it hasn't seen before.
+type CachedGeocoder struct {
+	Geocoder Geocoder
+	Cache    *GeocodeCache
+}
+
+// Returns the result of the wrapped Geocoder's HealthCheck, if it has one.
+// A cache alone can't answer new queries, so it doesn't make a CachedGeocoder healthy.
+func (c *CachedGeocoder) HealthCheck(ctx context.Context) error {
+	return healthCheck(ctx, c.Geocoder)
+}
+
+// Geocodes the passed in query, consulting the cache first.
+// Results of queries with QueryOptions are cached apart from those without.
+func (c *CachedGeocoder) Geocode(query string, opts ...QueryOption) (*Point, error) {
+	key := withQueryKey(geocodeCacheKey(query), opts)
+	if entry, ok := c.Cache.lookup(key); ok {
+		return NewPoint(entry.Lat, entry.Lng), nil
+	}
+
+	p, err := c.Geocoder.Geocode(query, opts...)
+	if errors.Is(err, ErrBudgetExceeded) {
+		return nil, fmt.Errorf("%w: %w", ErrCacheMiss, err)
+	}
+	if err != nil || p == nil {
+		return nil, err
+	}
+
+	if err := c.Cache.store(&CacheEntry{Key: key, Lat: p.lat, Lng: p.lng}); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Reverse geocodes the passed in Point, consulting the cache first.
+// Results of requests with QueryOptions are cached apart from those without.
+func (c *CachedGeocoder) ReverseGeocode(p *Point, opts ...QueryOption) (string, error) {
+	key := withQueryKey(reverseCacheKey(p), opts)
+	if entry, ok := c.Cache.lookup(key); ok {
+		return entry.Address, nil
+	}
+
+	address, err := c.Geocoder.ReverseGeocode(p, opts...)
+	if errors.Is(err, ErrBudgetExceeded) {
+		return "", fmt.Errorf("%w: %w", ErrCacheMiss, err)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := c.Cache.store(&CacheEntry{Key: key, Lat: p.lat, Lng: p.lng, Address: address}); err != nil {
+		return "", err
+	}
+
+	return address, nil
+}
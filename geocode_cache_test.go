@@ -0,0 +1,251 @@
+package geo
+
+import (
+	"bytes"
+	"errors"
+	bolt "go.etcd.io/bbolt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// A Geocoder that answers from fixed maps and counts how often it is asked.
+type stubGeocoder struct {
+	points    map[string]*Point
+	addresses map[string]string
+	err       error
+	calls     int
+}
+
+func (s *stubGeocoder) Geocode(query string, opts ...QueryOption) (*Point, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	p, ok := s.points[query]
+	if !ok {
+		return nil, googleZeroResultsError
+	}
+
+	return p, nil
+}
+
+func (s *stubGeocoder) ReverseGeocode(p *Point, opts ...QueryOption) (string, error) {
+	s.calls++
+	if s.err != nil {
+		return "", s.err
+	}
+
+	return s.addresses[reverseCacheKey(p)], nil
+}
+
+// Opens a GeocodeCache in a temporary directory that is removed after the test.
+func openTestCache(t *testing.T, ttl time.Duration, maxEntries int) *GeocodeCache {
+	c, err := OpenGeocodeCache(filepath.Join(t.TempDir(), "cache.db"), ttl, maxEntries)
+	if err != nil {
+		t.Fatalf("Could not open cache: %v", err)
+	}
+
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+// Ensures that queries are normalized before being used as keys.
+func TestNormalizeQuery(t *testing.T) {
+	if NormalizeQuery("  San Francisco\tAirport ") != "san francisco airport" {
+		t.Errorf("Unexpected normalized query: %q", NormalizeQuery("  San Francisco\tAirport "))
+	}
+}
+
+// Ensures that cached results are returned for equivalent queries and survive reopening.
+func TestGeocodeCachePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+	c, err := OpenGeocodeCache(path, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sfo := NewPoint(37.615223, -122.389979)
+	c.Put("SFO", sfo)
+	c.PutAddress(sfo, "San Francisco Airport")
+	c.Close()
+
+	c, err = OpenGeocodeCache(path, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	p, ok := c.Get(" sfo ")
+	if !ok || p.Lat() != sfo.Lat() || p.Lng() != sfo.Lng() {
+		t.Errorf("Expected the cached point for SFO after reopening, got %v (%v)", p, ok)
+	}
+
+	if address, ok := c.GetAddress(sfo); !ok || address != "San Francisco Airport" {
+		t.Errorf("Expected the cached address for SFO after reopening, got %q (%v)", address, ok)
+	}
+
+	if c.Len() != 2 {
+		t.Errorf("Expected 2 entries after reopening, got %d", c.Len())
+	}
+}
+
+// Ensures that expired entries are not returned and are removed on Evict.
+func TestGeocodeCacheTTL(t *testing.T) {
+	now := time.Now()
+	c := openTestCache(t, time.Hour, 0)
+	c.now = func() time.Time { return now }
+
+	c.Put("sfo", NewPoint(37.615223, -122.389979))
+	now = now.Add(2 * time.Hour)
+
+	if _, ok := c.Get("sfo"); ok {
+		t.Error("Expected an expired entry to be a miss")
+	}
+
+	removed, err := c.Evict()
+	if err != nil || removed != 1 || c.Len() != 0 {
+		t.Errorf("Expected Evict to remove the expired entry, removed %d (%v), %d left", removed, err, c.Len())
+	}
+}
+
+// Ensures that the oldest entries are evicted once the cache is full.
+func TestGeocodeCacheMaxEntries(t *testing.T) {
+	now := time.Now()
+	c := openTestCache(t, 0, 3)
+	c.now = func() time.Time { return now }
+
+	for _, q := range []string{"a", "b", "c", "d"} {
+		now = now.Add(time.Second)
+		c.Put(q, NewPoint(1, 1))
+	}
+
+	if c.Len() != 3 {
+		t.Errorf("Expected the cache to hold 3 entries, but it holds %d", c.Len())
+	}
+
+	if _, ok := c.Get("a"); ok {
+		t.Error("Expected the oldest entry to be evicted")
+	}
+
+	if _, ok := c.Get("d"); !ok {
+		t.Error("Expected the newest entry to be kept")
+	}
+
+	if err := c.Put(strings.Repeat("e", bolt.MaxKeySize), NewPoint(1, 1)); err == nil || c.Len() != 3 {
+		t.Errorf("Expected a failed Put to leave 3 entries, got %d (%v)", c.Len(), err)
+	}
+}
+
+// Ensures that entries survive a round trip through JSON and CSV.
+func TestGeocodeCacheExportImport(t *testing.T) {
+	src := openTestCache(t, 0, 0)
+	src.Put("sfo", NewPoint(37.615223, -122.389979))
+	src.PutAddress(NewPoint(40.714224, -73.961452), "285 Bedford Avenue, Brooklyn, NY 11211, USA")
+
+	formats := map[string]struct {
+		dump func(*GeocodeCache, *bytes.Buffer) error
+		load func(*GeocodeCache, *bytes.Buffer) (int, error)
+	}{
+		"json": {
+			func(c *GeocodeCache, b *bytes.Buffer) error { return c.ExportJSON(b) },
+			func(c *GeocodeCache, b *bytes.Buffer) (int, error) { return c.ImportJSON(b) },
+		},
+		"csv": {
+			func(c *GeocodeCache, b *bytes.Buffer) error { return c.ExportCSV(b) },
+			func(c *GeocodeCache, b *bytes.Buffer) (int, error) { return c.ImportCSV(b) },
+		},
+	}
+
+	for name, format := range formats {
+		buf := &bytes.Buffer{}
+		if err := format.dump(src, buf); err != nil {
+			t.Fatalf("%s: could not export: %v", name, err)
+		}
+
+		dst := openTestCache(t, 0, 0)
+		n, err := format.load(dst, buf)
+		if err != nil || n != 2 {
+			t.Fatalf("%s: expected to import 2 entries, imported %d (%v)", name, n, err)
+		}
+
+		if p, ok := dst.Get("SFO"); !ok || p.Lat() != 37.615223 || p.Lng() != -122.389979 {
+			t.Errorf("%s: expected SFO to survive the round trip, got %v", name, p)
+		}
+
+		address, ok := dst.GetAddress(NewPoint(40.714224, -73.961452))
+		if !ok || address != "285 Bedford Avenue, Brooklyn, NY 11211, USA" {
+			t.Errorf("%s: expected the address to survive the round trip, got %q", name, address)
+		}
+	}
+}
+
+// Ensures that a wrapped Geocoder answering with neither a point nor an error is passed through,
+// rather than panicking, and that nothing is cached for it.
+func TestCachedGeocoderNilPoint(t *testing.T) {
+	c := openTestCache(t, 0, 0)
+	g := &CachedGeocoder{Geocoder: &stubGeocoder{points: map[string]*Point{"Nowhere": nil}}, Cache: c}
+	if p, err := g.Geocode("Nowhere"); p != nil || err != nil {
+		t.Errorf("Expected no point and no error, got %v (%v)", p, err)
+	}
+
+	if c.Len() != 0 {
+		t.Errorf("Expected nothing to be cached, got %d entries", c.Len())
+	}
+}
+
+// Ensures that a CachedGeocoder only asks upstream once per query,
+// and serves cached results once the upstream budget is spent.
+func TestCachedGeocoder(t *testing.T) {
+	sfo := NewPoint(37.615223, -122.389979)
+	upstream := &stubGeocoder{points: map[string]*Point{"SFO": sfo}}
+	g := &CachedGeocoder{Geocoder: upstream, Cache: openTestCache(t, 0, 0)}
+
+	for i := 0; i < 3; i++ {
+		if _, err := g.Geocode("SFO"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if upstream.calls != 1 {
+		t.Errorf("Expected 1 upstream call, got %d", upstream.calls)
+	}
+
+	upstream.err = &BudgetExceededError{Provider: "google", Budget: 1}
+
+	if p, err := g.Geocode("sfo"); err != nil || p.Lat() != sfo.Lat() {
+		t.Errorf("Expected a cached result once the budget is spent, got %v (%v)", p, err)
+	}
+
+	_, err := g.Geocode("LAX")
+	if !errors.Is(err, ErrCacheMiss) || !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Expected a cache miss caused by the budget, got %v", err)
+	}
+}
+
+// Ensures that a CachedGeocoder caches the results of requests with QueryOptions apart from those without.
+func TestCachedGeocoderQueryOptions(t *testing.T) {
+	sfo := NewPoint(37.615223, -122.389979)
+	upstream := &stubGeocoder{points: map[string]*Point{"SFO": sfo}}
+	g := &CachedGeocoder{Geocoder: upstream, Cache: openTestCache(t, 0, 0)}
+
+	if _, err := g.Geocode("SFO"); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := g.Geocode("SFO", QueryLanguage("ja")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if upstream.calls != 2 {
+		t.Errorf("Expected 2 upstream calls, got %d", upstream.calls)
+	}
+
+	if _, ok := g.Cache.Get("SFO"); !ok {
+		t.Errorf("Expected the request without options to be cached under its query")
+	}
+}
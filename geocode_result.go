@@ -0,0 +1,34 @@
+package geo
+
+// A single candidate location returned by a geocoding provider.
+type GeocodeResult struct {
+	// The location of the candidate.
+	Point *Point
+
+	// The provider's full, human readable address for the candidate.
+	FormattedAddress string
+
+	// The address in Latin letters, for geocoders created WithTransliteration, or empty otherwise.
+	RomanizedAddress string
+
+	// The ISO 3166-1 alpha-2 code of the country the candidate lies in, e.g. "US",
+	// or empty if the provider did not say.
+	CountryCode string
+
+	// The name of the provider that returned the candidate, e.g. "google".
+	Provider string
+
+	// How precisely the candidate's Point locates it, as reported by the provider.
+	Quality MatchQuality
+
+	// How likely the candidate is to be what was asked for, from 0 to 1.
+	// Providers that score their own matches, such as a GazetteerGeocoder, may set it; a Ranker overwrites it.
+	Confidence float64
+}
+
+// A ResultGeocoder is a Geocoder that can return every candidate it finds
+// for a query, rather than just the first one's location.
+type ResultGeocoder interface {
+	Geocoder
+	GeocodeResults(query string, opts ...QueryOption) ([]*GeocodeResult, error)
+}
@@ -3,7 +3,8 @@ package geo
 // This interface describes a Geocoder, which provides the ability to Geocode and Reverse Geocode geographic points of interest.
 // Geocoding should accept a string that represents a street address, and returns a pointer to a Point that most closely identifies it.
 // Reverse geocoding should accept a pointer to a Point, and return the street address that most closely represents it.
+// Both accept QueryOptions changing a single request, ignoring those the Geocoder has no use for.
 type Geocoder interface {
-	Geocode(query string) (*Point, error)
-	ReverseGeocode(p *Point) (string, error)
+	Geocode(query string, opts ...QueryOption) (*Point, error)
+	ReverseGeocode(p *Point, opts ...QueryOption) (string, error)
 }
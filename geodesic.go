@@ -0,0 +1,644 @@
+package geo
+
+import (
+	"math"
+)
+
+// The order of the series expansions in the third flattening of Karney's method,
+// enough for the errors of areas on the WGS84 ellipsoid to be well under a square meter per edge.
+const geodesicOrder = 6
+
+// The number of Newton steps Karney's method takes in solving for the azimuth, and the number
+// of steps it takes in all, bisecting after the Newton steps, before settling for where it is.
+const (
+	geodesicNewtonSteps = 20
+	geodesicMaxSteps    = geodesicNewtonSteps + 53 + 10
+)
+
+// The tolerances of Karney's method, in terms of the precision of float64.
+var (
+	geodesicTiny    = math.Sqrt(0x1p-1022)
+	geodesicTol0    = math.Nextafter(1, 2) - 1
+	geodesicTol1    = 200 * geodesicTol0
+	geodesicTol2    = math.Sqrt(geodesicTol0)
+	geodesicTolB    = geodesicTol0 * geodesicTol2
+	geodesicXThresh = 1000 * geodesicTol2
+)
+
+// The WGS84 ellipsoid, in kilometers, and the coefficients of its series in Karney's method.
+var wgs84 = newEllipsoid(WGS84_SEMI_MAJOR_AXIS, WGS84_FLATTENING)
+
+// An ellipsoid of revolution, flattened at the poles, as the earth is.
+type ellipsoid struct {
+	a, f, f1, e2, ep2, n, b, etol2 float64
+
+	// The square of the radius of the sphere with the same surface area as the ellipsoid.
+	c2 float64
+
+	// The coefficients, as polynomials in the third flattening, of the series A3, C3 and C4.
+	a3x [geodesicOrder]float64
+	c3x [geodesicOrder * (geodesicOrder - 1) / 2]float64
+	c4x [geodesicOrder * (geodesicOrder + 1) / 2]float64
+}
+
+// Returns the ellipsoid of the passed in semi-major axis and flattening, which must be positive.
+func newEllipsoid(a, f float64) *ellipsoid {
+	e := &ellipsoid{a: a, f: f, f1: 1 - f, e2: f * (2 - f), n: f / (2 - f), b: a * (1 - f)}
+	e.ep2 = e.e2 / (e.f1 * e.f1)
+	e.c2 = (a*a + e.b*e.b*math.Atanh(math.Sqrt(e.e2))/math.Sqrt(e.e2)) / 2
+	e.etol2 = 0.1 * geodesicTol2 / math.Sqrt(math.Max(0.001, f)*math.Min(1, 1-f/2)/2)
+
+	// A3, highest power of eps first, each as a polynomial in n followed by its denominator.
+	a3 := []float64{
+		-3, 128,
+		-2, -3, 64,
+		-1, -3, -1, 16,
+		3, -1, -2, 8,
+		1, -1, 2,
+		1, 1,
+	}
+	o, k := 0, 0
+	for j := geodesicOrder - 1; j >= 0; j-- {
+		m := polynomialOrder(j)
+		e.a3x[k] = polyval(a3[o:o+m+1], e.n) / a3[o+m+1]
+		k, o = k+1, o+m+2
+	}
+
+	// C3[l], for l from 1, each highest power of eps first.
+	c3 := []float64{
+		3, 128,
+		2, 5, 128,
+		-1, 3, 3, 64,
+		-1, 0, 1, 8,
+		-1, 1, 4,
+		5, 256,
+		1, 3, 128,
+		-3, -2, 3, 64,
+		1, -3, 2, 32,
+		7, 512,
+		-10, 9, 384,
+		5, -9, 5, 192,
+		7, 512,
+		-14, 7, 512,
+		21, 2560,
+	}
+	o, k = 0, 0
+	for l := 1; l < geodesicOrder; l++ {
+		for j := geodesicOrder - 1; j >= l; j-- {
+			m := polynomialOrder(j)
+			e.c3x[k] = polyval(c3[o:o+m+1], e.n) / c3[o+m+1]
+			k, o = k+1, o+m+2
+		}
+	}
+
+	// C4[l], for l from 0, each highest power of eps first.
+	c4 := []float64{
+		97, 15015,
+		1088, 156, 45045,
+		-224, -4784, 1573, 45045,
+		-10656, 14144, -4576, -858, 45045,
+		64, 624, -4576, 6864, -3003, 15015,
+		100, 208, 572, 3432, -12012, 30030, 45045,
+		1, 9009,
+		-2944, 468, 135135,
+		5792, 1040, -1287, 135135,
+		5952, -11648, 9152, -2574, 135135,
+		-64, -624, 4576, -6864, 3003, 135135,
+		8, 10725,
+		1856, -936, 225225,
+		-8448, 4992, -1144, 225225,
+		-1440, 4160, -4576, 1716, 225225,
+		-136, 63063,
+		1024, -208, 105105,
+		3584, -3328, 1144, 315315,
+		-128, 135135,
+		-2560, 832, 405405,
+		128, 99099,
+	}
+	o, k = 0, 0
+	for l := 0; l < geodesicOrder; l++ {
+		for j := geodesicOrder - 1; j >= l; j-- {
+			m := geodesicOrder - j - 1
+			e.c4x[k] = polyval(c4[o:o+m+1], e.n) / c4[o+m+1]
+			k, o = k+1, o+m+2
+		}
+	}
+
+	return e
+}
+
+// Returns the order, in n, of the polynomial for the coefficient of eps^j in the series A3 and C3.
+func polynomialOrder(j int) int {
+	if geodesicOrder-j-1 < j {
+		return geodesicOrder - j - 1
+	}
+
+	return j
+}
+
+// Returns the area, in square kilometers, between the geodesic from the first of the passed in latitudes
+// and longitudes to the second and the equator, by Karney's method ("Algorithms for geodesics", 2013),
+// as GeographicLib computes it.  It is signed such that the areas of the edges of a ring sum to the area it
+// encloses, up to the area of the ellipsoid, negative when the ring is wound counterclockwise.
+func (e *ellipsoid) edgeArea(lat1, lon1, lat2, lon2 float64) float64 {
+	if math.Abs(lat1) > 90 || math.Abs(lat2) > 90 {
+		return math.NaN()
+	}
+
+	// Bring the points into the canonical form 0 <= lon12 <= 180, -90 <= lat1 <= -0 and lat1 <= lat2 <= -lat1,
+	// keeping the signs to undo it with.
+	lon12 := math.Remainder(lon2-lon1, 360)
+	lonSign := 1.0
+	if math.Signbit(lon12) {
+		lonSign = -1
+	}
+	lon12 = angRound(lonSign * lon12)
+	lon12s := angRound(180 - lon12)
+	lam12 := lon12 * math.Pi / 180
+	var slam12, clam12 float64
+	if lon12 > 90 {
+		slam12, clam12 = sincosd(lon12s)
+		clam12 = -clam12
+	} else {
+		slam12, clam12 = sincosd(lon12)
+	}
+
+	lat1, lat2 = angRound(lat1), angRound(lat2)
+	swapSign := 1.0
+	if math.Abs(lat1) < math.Abs(lat2) {
+		swapSign, lonSign = -1, -lonSign
+		lat1, lat2 = lat2, lat1
+	}
+	latSign := 1.0
+	if !math.Signbit(lat1) {
+		latSign = -1
+		lat1, lat2 = -lat1, -lat2
+	}
+
+	sbet1, cbet1 := sincosd(lat1)
+	sbet1, cbet1 = norm2(e.f1*sbet1, cbet1)
+	cbet1 = math.Max(geodesicTiny, cbet1)
+	sbet2, cbet2 := sincosd(lat2)
+	sbet2, cbet2 = norm2(e.f1*sbet2, cbet2)
+	cbet2 = math.Max(geodesicTiny, cbet2)
+
+	// Make |bet2| = |bet1| exactly when they are nearly so, as the iteration is sensitive to it.
+	if cbet1 < -sbet1 {
+		if cbet2 == cbet1 {
+			sbet2 = math.Copysign(sbet1, sbet2)
+		}
+	} else if math.Abs(sbet2) == -sbet1 {
+		cbet2 = cbet1
+	}
+
+	dn1, dn2 := math.Sqrt(1+e.ep2*sbet1*sbet1), math.Sqrt(1+e.ep2*sbet2*sbet2)
+
+	// The azimuths at either point, and, away from meridians, the longitude difference on the auxiliary sphere.
+	var salp1, calp1, salp2, calp2, somg12, comg12 float64
+
+	// Along a meridian, heading for the second point's longitude and arriving heading north.
+	meridian := lat1 == -90 || slam12 == 0
+	if meridian {
+		salp1, calp1 = slam12, clam12
+		salp2, calp2 = 0, 1
+		ssig1, csig1 := sbet1, calp1*cbet1
+		ssig2, csig2 := sbet2, calp2*cbet2
+
+		sig12 := math.Atan2(math.Max(0, csig1*ssig2-ssig1*csig2), csig1*csig2+ssig1*ssig2)
+		_, m12x := e.lengths(e.n, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2)
+		meridian = sig12 < 1 || m12x >= 0
+	}
+
+	switch {
+	case meridian:
+	case sbet1 == 0 && lon12s >= e.f*180:
+		// Along the equator.
+		salp1, calp1, salp2, calp2 = 1, 0, 1, 0
+		somg12, comg12 = math.Sincos(lam12 / e.f1)
+	default:
+		var sig12, dnm float64
+		salp1, calp1, salp2, calp2, sig12, dnm = e.inverseStart(sbet1, cbet1, sbet2, cbet2, lam12, slam12, clam12)
+		if sig12 >= 0 {
+			// Short lines, which the starting guess solves.
+			somg12, comg12 = math.Sincos(lam12 / (e.f1 * dnm))
+			break
+		}
+
+		// Newton's method on the azimuth at the first point, keeping a bracket around the root to bisect
+		// when a step would leave it.
+		var domg12 float64
+		salp1a, calp1a, salp1b, calp1b := geodesicTiny, 1.0, geodesicTiny, -1.0
+		tripn, tripb := false, false
+		for steps := 0; ; steps++ {
+			var v, dv float64
+			v, dv, salp2, calp2, domg12 = e.lambda12(sbet1, cbet1, dn1, sbet2, cbet2, dn2, salp1, calp1, slam12, clam12, steps < geodesicNewtonSteps)
+			tolerance := geodesicTol0
+			if tripn {
+				tolerance *= 8
+			}
+			if tripb || !(math.Abs(v) >= tolerance) || steps == geodesicMaxSteps {
+				break
+			}
+
+			if v > 0 && (steps > geodesicNewtonSteps || calp1/salp1 > calp1b/salp1b) {
+				salp1b, calp1b = salp1, calp1
+			} else if v < 0 && (steps > geodesicNewtonSteps || calp1/salp1 < calp1a/salp1a) {
+				salp1a, calp1a = salp1, calp1
+			}
+
+			if steps < geodesicNewtonSteps && dv > 0 {
+				if dalp1 := -v / dv; math.Abs(dalp1) < math.Pi {
+					sdalp1, cdalp1 := math.Sincos(dalp1)
+					if nsalp1 := salp1*cdalp1 + calp1*sdalp1; nsalp1 > 0 {
+						salp1, calp1 = norm2(nsalp1, calp1*cdalp1-salp1*sdalp1)
+						tripn = math.Abs(v) <= 16*geodesicTol0
+						continue
+					}
+				}
+			}
+
+			salp1, calp1 = norm2((salp1a+salp1b)/2, (calp1a+calp1b)/2)
+			tripn = false
+			tripb = math.Abs(salp1a-salp1)+(calp1a-calp1) < geodesicTolB || math.Abs(salp1-salp1b)+(calp1-calp1b) < geodesicTolB
+		}
+
+		// The longitude difference on the auxiliary sphere is lam12 less domg12.
+		sdomg12, cdomg12 := math.Sincos(domg12)
+		somg12 = slam12*cdomg12 - clam12*sdomg12
+		comg12 = clam12*cdomg12 + slam12*sdomg12
+	}
+
+	// The ellipsoid's departure from the authalic sphere, which vanishes along meridians and the equator.
+	s12 := 0.0
+	salp0, calp0 := salp1*cbet1, math.Hypot(calp1, salp1*sbet1)
+	if calp0 != 0 && salp0 != 0 {
+		ssig1, csig1 := norm2(sbet1, calp1*cbet1)
+		ssig2, csig2 := norm2(sbet2, calp2*cbet2)
+		k2 := calp0 * calp0 * e.ep2
+		eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+		var c4 [geodesicOrder]float64
+		e.c4f(eps, c4[:])
+		s12 = e.a * e.a * calp0 * salp0 * e.e2 * (cosSeries(ssig2, csig2, c4[:]) - cosSeries(ssig1, csig1, c4[:]))
+	}
+
+	// The spherical excess of the region between the geodesic and the equator, from the turn in its azimuth.
+	var alp12 float64
+	if !meridian && comg12 > -0.7071 && sbet2-sbet1 < 1.75 {
+		domg12, dbet1, dbet2 := 1+comg12, 1+cbet1, 1+cbet2
+		alp12 = 2 * math.Atan2(somg12*(sbet1*dbet2+sbet2*dbet1), domg12*(sbet1*sbet2+dbet1*dbet2))
+	} else {
+		salp12, calp12 := salp2*calp1-calp2*salp1, calp2*calp1+salp2*salp1
+		if salp12 == 0 && calp12 < 0 {
+			salp12, calp12 = geodesicTiny*calp1, -1
+		}
+		alp12 = math.Atan2(salp12, calp12)
+	}
+
+	return 0 + (s12+e.c2*alp12)*swapSign*lonSign*latSign
+}
+
+// Returns the distance and reduced length, both over b, of the geodesic of the passed in eps
+// and spherical arc length, between points of the passed in reduced latitudes and arc lengths from the equator.
+func (e *ellipsoid) lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2 float64) (s12b, m12b float64) {
+	var c1, c2 [geodesicOrder + 1]float64
+	a1 := a1m1f(eps)
+	c1f(eps, c1[:])
+	a2 := a2m1f(eps)
+	c2f(eps, c2[:])
+	m0 := a1 - a2
+	a1, a2 = 1+a1, 1+a2
+
+	b1 := sinSeries(ssig2, csig2, c1[:]) - sinSeries(ssig1, csig1, c1[:])
+	b2 := sinSeries(ssig2, csig2, c2[:]) - sinSeries(ssig1, csig1, c2[:])
+	j12 := m0*sig12 + (a1*b1 - a2*b2)
+
+	s12b = a1 * (sig12 + b1)
+	m12b = dn2*(csig1*ssig2) - dn1*(ssig1*csig2) - csig1*csig2*j12
+	return s12b, m12b
+}
+
+// Returns the starting guess of the azimuth at the first point, and, for short lines, for which it is
+// good enough, the azimuth at the second point, the spherical arc length of the geodesic and the scale
+// of the sphere it's on; otherwise the arc length is -1.
+func (e *ellipsoid) inverseStart(sbet1, cbet1, sbet2, cbet2, lam12, slam12, clam12 float64) (salp1, calp1, salp2, calp2, sig12, dnm float64) {
+	sig12 = -1
+	sbet12 := sbet2*cbet1 - cbet2*sbet1
+	cbet12 := cbet2*cbet1 + sbet2*sbet1
+	sbet12a := sbet2*cbet1 + cbet2*sbet1
+	shortline := cbet12 >= 0 && sbet12 < 0.5 && cbet2*lam12 < 0.5
+
+	somg12, comg12 := slam12, clam12
+	if shortline {
+		sbetm2 := (sbet1 + sbet2) * (sbet1 + sbet2)
+		sbetm2 /= sbetm2 + (cbet1+cbet2)*(cbet1+cbet2)
+		dnm = math.Sqrt(1 + e.ep2*sbetm2)
+		somg12, comg12 = math.Sincos(lam12 / (e.f1 * dnm))
+	}
+
+	salp1 = cbet2 * somg12
+	if comg12 >= 0 {
+		calp1 = sbet12 + cbet2*sbet1*somg12*somg12/(1+comg12)
+	} else {
+		calp1 = sbet12a - cbet2*sbet1*somg12*somg12/(1-comg12)
+	}
+
+	ssig12 := math.Hypot(salp1, calp1)
+	csig12 := sbet1*sbet2 + cbet1*cbet2*comg12
+
+	switch {
+	case shortline && ssig12 < e.etol2:
+		salp2 = cbet1 * somg12
+		if comg12 >= 0 {
+			calp2 = sbet12 - cbet1*sbet2*somg12*somg12/(1+comg12)
+		} else {
+			calp2 = sbet12 - cbet1*sbet2*(1-comg12)
+		}
+		salp2, calp2 = norm2(salp2, calp2)
+		sig12 = math.Atan2(ssig12, csig12)
+	case math.Abs(e.n) > 0.1 || csig12 >= 0 || ssig12 >= 6*math.Abs(e.n)*math.Pi*cbet1*cbet1:
+		// The spherical guess will do.
+	default:
+		// Nearly antipodal: scale to coordinates where the antipode is at the origin, and solve the astroid.
+		lam12x := math.Atan2(-slam12, -clam12)
+		k2 := sbet1 * sbet1 * e.ep2
+		eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+		lamscale := e.f * cbet1 * e.a3f(eps) * math.Pi
+		betscale := lamscale * cbet1
+		x, y := lam12x/lamscale, sbet12a/betscale
+
+		if y > -geodesicTol1 && x > -1-geodesicXThresh {
+			salp1 = math.Min(1, -x)
+			calp1 = -math.Sqrt(1 - salp1*salp1)
+		} else {
+			k := astroid(x, y)
+			omg12a := lamscale * -x * k / (1 + k)
+			somg12, comg12 = math.Sincos(omg12a)
+			comg12 = -comg12
+			salp1 = cbet2 * somg12
+			calp1 = sbet12a - cbet2*sbet1*somg12*somg12/(1-comg12)
+		}
+	}
+
+	if !(salp1 <= 0) {
+		salp1, calp1 = norm2(salp1, calp1)
+	} else {
+		salp1, calp1 = 1, 0
+	}
+
+	return salp1, calp1, salp2, calp2, sig12, dnm
+}
+
+// Returns how far the longitude the geodesic leaving the first point at the passed in azimuth reaches,
+// at the latitude of the second point, falls short of the second point's, and, if asked for, its derivative
+// with respect to the azimuth, along with the azimuth at the second point and how far the longitude
+// falls short of the longitude on the auxiliary sphere.
+func (e *ellipsoid) lambda12(sbet1, cbet1, dn1, sbet2, cbet2, dn2, salp1, calp1, slam120, clam120 float64, diffp bool) (v, dlam12, salp2, calp2, domg12 float64) {
+	if sbet1 == 0 && calp1 == 0 {
+		calp1 = -geodesicTiny
+	}
+
+	salp0 := salp1 * cbet1
+	calp0 := math.Hypot(calp1, salp1*sbet1)
+
+	ssig1, csig1 := norm2(sbet1, calp1*cbet1)
+	somg1, comg1 := salp0*sbet1, calp1*cbet1
+
+	salp2, calp2 = salp1, math.Abs(calp1)
+	if cbet2 != cbet1 {
+		salp2 = salp0 / cbet2
+	}
+	if cbet2 != cbet1 || math.Abs(sbet2) != -sbet1 {
+		d := (sbet1 - sbet2) * (sbet1 + sbet2)
+		if cbet1 < -sbet1 {
+			d = (cbet2 - cbet1) * (cbet1 + cbet2)
+		}
+		calp2 = math.Sqrt((calp1*cbet1)*(calp1*cbet1)+d) / cbet2
+	}
+
+	ssig2, csig2 := norm2(sbet2, calp2*cbet2)
+	somg2, comg2 := salp0*sbet2, calp2*cbet2
+
+	sig12 := math.Atan2(math.Max(0, csig1*ssig2-ssig1*csig2), csig1*csig2+ssig1*ssig2)
+	somg12 := math.Max(0, comg1*somg2-somg1*comg2)
+	comg12 := comg1*comg2 + somg1*somg2
+	eta := math.Atan2(somg12*clam120-comg12*slam120, comg12*clam120+somg12*slam120)
+
+	k2 := calp0 * calp0 * e.ep2
+	eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+	var c3 [geodesicOrder]float64
+	e.c3f(eps, c3[:])
+	b312 := sinSeries(ssig2, csig2, c3[:]) - sinSeries(ssig1, csig1, c3[:])
+	domg12 = -e.f * e.a3f(eps) * salp0 * (sig12 + b312)
+	v = eta + domg12
+
+	if diffp {
+		if calp2 == 0 {
+			dlam12 = -2 * e.f1 * dn1 / sbet1
+		} else {
+			_, m12b := e.lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2)
+			dlam12 = m12b * e.f1 / (calp2 * cbet2)
+		}
+	}
+
+	return v, dlam12, salp2, calp2, domg12
+}
+
+// Returns the series A3 at the passed in eps.
+func (e *ellipsoid) a3f(eps float64) float64 {
+	return polyval(e.a3x[:], eps)
+}
+
+// Sets c[1:] to the series C3 at the passed in eps.
+func (e *ellipsoid) c3f(eps float64, c []float64) {
+	mult, o := 1.0, 0
+	for l := 1; l < geodesicOrder; l++ {
+		m := geodesicOrder - l - 1
+		mult *= eps
+		c[l] = mult * polyval(e.c3x[o:o+m+1], eps)
+		o += m + 1
+	}
+}
+
+// Sets c[0:] to the series C4 at the passed in eps.
+func (e *ellipsoid) c4f(eps float64, c []float64) {
+	mult, o := 1.0, 0
+	for l := 0; l < geodesicOrder; l++ {
+		m := geodesicOrder - l - 1
+		c[l] = mult * polyval(e.c4x[o:o+m+1], eps)
+		o += m + 1
+		mult *= eps
+	}
+}
+
+// Returns the series A1, less one, at the passed in eps.
+func a1m1f(eps float64) float64 {
+	t := polyval([]float64{1, 4, 64, 0}, eps*eps) / 256
+	return (t + eps) / (1 - eps)
+}
+
+// Sets c[1:] to the series C1 at the passed in eps.
+func c1f(eps float64, c []float64) {
+	coefficients := []float64{
+		-1, 6, -16, 32,
+		-9, 64, -128, 2048,
+		9, -16, 768,
+		3, -5, 512,
+		-7, 1280,
+		-7, 2048,
+	}
+	evenSeries(eps, c, coefficients)
+}
+
+// Returns the series A2, less one, at the passed in eps.
+func a2m1f(eps float64) float64 {
+	t := polyval([]float64{-11, -28, -192, 0}, eps*eps) / 256
+	return (t - eps) / (1 + eps)
+}
+
+// Sets c[1:] to the series C2 at the passed in eps.
+func c2f(eps float64, c []float64) {
+	coefficients := []float64{
+		1, 2, 16, 32,
+		35, 64, 384, 2048,
+		15, 80, 768,
+		7, 35, 512,
+		63, 1280,
+		77, 2048,
+	}
+	evenSeries(eps, c, coefficients)
+}
+
+// Sets c[l], for l from 1, to eps^l times the polynomial in eps² that the passed in coefficients hold for it,
+// each highest power first and followed by its denominator.
+func evenSeries(eps float64, c []float64, coefficients []float64) {
+	eps2, d, o := eps*eps, eps, 0
+	for l := 1; l <= geodesicOrder; l++ {
+		m := (geodesicOrder - l) / 2
+		c[l] = d * polyval(coefficients[o:o+m+1], eps2) / coefficients[o+m+1]
+		o += m + 2
+		d *= eps
+	}
+}
+
+// Returns the sum of c[l]·sin(2l·x), for l from 1, by Clenshaw summation.
+func sinSeries(sinx, cosx float64, c []float64) float64 {
+	k := len(c)
+	ar := 2 * (cosx - sinx) * (cosx + sinx)
+	y0, y1 := 0.0, 0.0
+	if (k-1)&1 != 0 {
+		k--
+		y0 = c[k]
+	}
+	for i := (len(c) - 1) / 2; i > 0; i-- {
+		k--
+		y1 = ar*y0 - y1 + c[k]
+		k--
+		y0 = ar*y1 - y0 + c[k]
+	}
+
+	return 2 * sinx * cosx * y0
+}
+
+// Returns the sum of c[l]·cos((2l+1)·x), for l from 0, by Clenshaw summation.
+func cosSeries(sinx, cosx float64, c []float64) float64 {
+	k := len(c)
+	ar := 2 * (cosx - sinx) * (cosx + sinx)
+	y0, y1 := 0.0, 0.0
+	if k&1 != 0 {
+		k--
+		y0 = c[k]
+	}
+	for i := len(c) / 2; i > 0; i-- {
+		k--
+		y1 = ar*y0 - y1 + c[k]
+		k--
+		y0 = ar*y1 - y0 + c[k]
+	}
+
+	return cosx * (y0 - y1)
+}
+
+// Returns the positive root k of k⁴ + 2k³ - (x² + y² - 1)k² - 2y²k - y² = 0.
+func astroid(x, y float64) float64 {
+	p, q := x*x, y*y
+	r := (p + q - 1) / 6
+	if q == 0 && r <= 0 {
+		return 0
+	}
+
+	s := p * q / 4
+	r2 := r * r
+	r3 := r * r2
+	disc := s * (s + 2*r3)
+	u := r
+	if disc >= 0 {
+		t3 := s + r3
+		if t3 < 0 {
+			t3 -= math.Sqrt(disc)
+		} else {
+			t3 += math.Sqrt(disc)
+		}
+		t := math.Cbrt(t3)
+		u += t
+		if t != 0 {
+			u += r2 / t
+		}
+	} else {
+		u += 2 * r * math.Cos(math.Atan2(math.Sqrt(-disc), -(s+r3))/3)
+	}
+
+	v := math.Sqrt(u*u + q)
+	uv := u + v
+	if u < 0 {
+		uv = q / (v - u)
+	}
+	w := (uv - q) / (2 * v)
+	return uv / (math.Sqrt(uv+w*w) + w)
+}
+
+// Returns the passed in polynomial's value at x, its coefficients highest power first.
+func polyval(p []float64, x float64) float64 {
+	y := 0.0
+	for _, c := range p {
+		y = y*x + c
+	}
+
+	return y
+}
+
+// Returns the passed in sine and cosine scaled to be those of an angle.
+func norm2(s, c float64) (float64, float64) {
+	r := math.Hypot(s, c)
+	return s / r, c / r
+}
+
+// Returns the passed in angle, in degrees, rounded to a multiple of 2⁻⁵⁷ when close to zero,
+// so that angles near the equator are treated as on it, and tiny ones don't lose accuracy.
+func angRound(x float64) float64 {
+	const z = 1.0 / 16
+	y := math.Abs(x)
+	if w := z - y; w > 0 {
+		y = z - w
+	}
+
+	return math.Copysign(y, x)
+}
+
+// Returns the sine and cosine of the passed in angle, in degrees, exactly at multiples of 90.
+func sincosd(x float64) (float64, float64) {
+	q := math.Round(x / 90)
+	s, c := math.Sincos((x - 90*q) * math.Pi / 180)
+	switch int(math.Mod(q, 4)+4) % 4 {
+	case 1:
+		s, c = c, -s
+	case 2:
+		s, c = -s, -c
+	case 3:
+		s, c = -c, s
+	}
+	if s == 0 {
+		s = math.Copysign(0, x)
+	}
+
+	return s, 0 + c
+}
@@ -0,0 +1,174 @@
+package geo
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// A Geofence is a named area whose boundary crossings are reported by a GeofenceEngine.
+type Geofence struct {
+	ID      string
+	Polygon *Polygon
+}
+
+// The kinds of boundary crossing a GeofenceEngine reports.
+type GeofenceEventType int
+
+const (
+	GeofenceEnter GeofenceEventType = iota
+	GeofenceExit
+)
+
+// Returns "enter" or "exit".
+func (t GeofenceEventType) String() string {
+	if t == GeofenceExit {
+		return "exit"
+	}
+
+	return "enter"
+}
+
+// A GeofenceEvent records a subject, such as a vehicle or a phone, crossing the boundary of a Geofence.
+type GeofenceEvent struct {
+	Type    GeofenceEventType
+	FenceID string
+	Subject string
+	Point   *Point
+	Time    time.Time
+}
+
+// A GeofenceNotifier is told about every event a GeofenceEngine reports.
+type GeofenceNotifier interface {
+	Notify(e GeofenceEvent) error
+}
+
+// A GeofenceEngine follows subjects as they move, and reports when they enter or leave its geofences.
+// A GeofenceEngine is safe for concurrent use.
+type GeofenceEngine struct {
+	mu        sync.Mutex
+	fences    []*Geofence
+	index     *RTree[int]
+	inside    map[string]map[string]bool
+	notifiers []GeofenceNotifier
+
+	// Used to timestamp events.  Overridable for testing.
+	now func() time.Time
+}
+
+// Creates and returns a pointer to a new GeofenceEngine watching the passed in geofences.
+func NewGeofenceEngine(fences ...*Geofence) *GeofenceEngine {
+	e := &GeofenceEngine{
+		index:  NewRTree[int](),
+		inside: make(map[string]map[string]bool),
+		now:    time.Now,
+	}
+
+	for _, f := range fences {
+		e.AddFence(f)
+	}
+
+	return e
+}
+
+// Starts watching the passed in geofence.  Subjects already inside it are
+// reported as entering on their next update.
+func (e *GeofenceEngine) AddFence(f *Geofence) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	id := len(e.fences)
+	e.fences = append(e.fences, f)
+	if !f.Polygon.IsClosed() {
+		return
+	}
+
+	for _, part := range f.Polygon.SplitAtAntimeridian() {
+		r := rectOf(part.Points())
+		e.index.Insert(NewBounds(NewPoint(r.minLat, r.minLng), NewPoint(r.maxLat, r.maxLng)), id)
+	}
+	e.index.Build()
+}
+
+// Adds a notifier that is told about every event from subsequent updates.
+func (e *GeofenceEngine) AddNotifier(n GeofenceNotifier) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.notifiers = append(e.notifiers, n)
+}
+
+// Records the passed in subject's new position, and returns the events it causes:
+// exits from the geofences it has left, then entries into those it has joined.
+// Every notifier is told about every event; errors from notifiers are joined and
+// returned alongside the events.  Notifiers are called on the calling goroutine.
+func (e *GeofenceEngine) Update(subject string, p *Point) ([]GeofenceEvent, error) {
+	e.mu.Lock()
+	return e.update(subject, p, e.now())
+}
+
+// Records the passed in fix as the subject's new position, as Update does, timestamping events
+// with when the fix was taken rather than when it arrived, for fixes that are delayed or replayed.
+func (e *GeofenceEngine) UpdateFix(subject string, fix *TimedPoint) ([]GeofenceEvent, error) {
+	e.mu.Lock()
+	return e.update(subject, fix.Point, fix.Time)
+}
+
+// Records the passed in subject's new position, reached at the passed in time, with the lock held,
+// and reports the events it causes after releasing it.
+func (e *GeofenceEngine) update(subject string, p *Point, now time.Time) ([]GeofenceEvent, error) {
+	was := e.inside[subject]
+	is := make(map[string]bool)
+	for _, id := range e.index.Search(p) {
+		if f := e.fences[id]; f.Polygon.Contains(p) {
+			is[f.ID] = true
+		}
+	}
+
+	var events []GeofenceEvent
+	for _, f := range e.fences {
+		if was[f.ID] && !is[f.ID] {
+			events = append(events, GeofenceEvent{Type: GeofenceExit, FenceID: f.ID, Subject: subject, Point: p, Time: now})
+		}
+	}
+	for _, f := range e.fences {
+		if is[f.ID] && !was[f.ID] {
+			events = append(events, GeofenceEvent{Type: GeofenceEnter, FenceID: f.ID, Subject: subject, Point: p, Time: now})
+		}
+	}
+
+	if len(is) == 0 {
+		delete(e.inside, subject)
+	} else {
+		e.inside[subject] = is
+	}
+
+	notifiers := e.notifiers
+	e.mu.Unlock()
+
+	var errs []error
+	for _, event := range events {
+		for _, n := range notifiers {
+			if err := n.Notify(event); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return events, errors.Join(errs...)
+}
+
+// Returns the IDs of the geofences the passed in subject was inside as of its last update.
+func (e *GeofenceEngine) FencesOf(subject string) []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var ids []string
+	for _, f := range e.fences {
+		if e.inside[subject][f.ID] {
+			ids = append(ids, f.ID)
+		}
+	}
+
+	return ids
+}
@@ -0,0 +1,103 @@
+package geo
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// A GeofenceNotifier that records the events it is told about.
+type recordingNotifier struct {
+	events []GeofenceEvent
+	err    error
+}
+
+func (r *recordingNotifier) Notify(e GeofenceEvent) error {
+	r.events = append(r.events, e)
+	return r.err
+}
+
+// Ensures that subjects are reported entering and leaving geofences as they move.
+func TestGeofenceEngineUpdate(t *testing.T) {
+	e := NewGeofenceEngine(
+		&Geofence{ID: "depot", Polygon: squarePolygon(51, -1, 1)},
+		&Geofence{ID: "city", Polygon: squarePolygon(51, -0.5, 1)},
+	)
+	at := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	e.now = func() time.Time { return at }
+
+	n := &recordingNotifier{}
+	e.AddNotifier(n)
+
+	events, err := e.Update("van", NewPoint(51.5, -0.75))
+	if err != nil || len(events) != 1 || events[0].Type != GeofenceEnter || events[0].FenceID != "depot" {
+		t.Fatalf("Expected the van to enter the depot, got %v (%v)", events, err)
+	}
+
+	if !events[0].Time.Equal(at) || events[0].Subject != "van" {
+		t.Errorf("Expected the event to carry the subject and time, got %+v", events[0])
+	}
+
+	events, _ = e.Update("van", NewPoint(51.5, -0.25))
+	if len(events) != 1 || events[0].Type != GeofenceEnter || events[0].FenceID != "city" {
+		t.Errorf("Expected the van to enter the city while staying in the depot, got %v", events)
+	}
+
+	if fences := e.FencesOf("van"); len(fences) != 2 {
+		t.Errorf("Expected the van to be in both fences, got %v", fences)
+	}
+
+	events, _ = e.Update("van", NewPoint(10, 10))
+	if len(events) != 2 || events[0].Type != GeofenceExit || events[1].Type != GeofenceExit {
+		t.Errorf("Expected the van to exit both fences, got %v", events)
+	}
+
+	if events, _ := e.Update("van", NewPoint(10, 10)); len(events) != 0 {
+		t.Errorf("Expected no events without a crossing, got %v", events)
+	}
+
+	if len(n.events) != 4 {
+		t.Errorf("Expected the notifier to be told about 4 events, got %d", len(n.events))
+	}
+}
+
+// Ensures that events from fixes are timestamped with when the fix was taken.
+func TestGeofenceEngineUpdateFix(t *testing.T) {
+	e := NewGeofenceEngine(&Geofence{ID: "depot", Polygon: squarePolygon(51, -1, 1)})
+	e.now = func() time.Time { return time.Date(2024, 1, 2, 16, 0, 0, 0, time.UTC) }
+
+	taken := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	events, err := e.UpdateFix("van", NewTimedPoint(51.5, -0.75, taken))
+	if err != nil || len(events) != 1 || events[0].Type != GeofenceEnter {
+		t.Fatalf("Expected the van to enter the depot, got %v (%v)", events, err)
+	}
+
+	if !events[0].Time.Equal(taken) {
+		t.Errorf("Expected the event at %v, got %v", taken, events[0].Time)
+	}
+}
+
+// Ensures that geofences crossing the antimeridian are handled.
+func TestGeofenceEngineAntimeridian(t *testing.T) {
+	fiji := NewPolygon([]*Point{NewPoint(-21, 177), NewPoint(-21, -178), NewPoint(-12, -178), NewPoint(-12, 177)})
+	e := NewGeofenceEngine(&Geofence{ID: "fiji", Polygon: fiji})
+
+	for _, p := range []*Point{NewPoint(-17, 179), NewPoint(-17, -179)} {
+		e.Update("boat", NewPoint(0, 0))
+		if events, _ := e.Update("boat", p); len(events) != 1 || events[0].Type != GeofenceEnter {
+			t.Errorf("Expected the boat to enter fiji at %v, got %v", p, events)
+		}
+	}
+}
+
+// Ensures that notifier errors are returned alongside the events.
+func TestGeofenceEngineNotifierError(t *testing.T) {
+	failure := errors.New("notifier down")
+	e := NewGeofenceEngine(&Geofence{ID: "depot", Polygon: squarePolygon(0, 0, 1)})
+	e.AddNotifier(&recordingNotifier{err: failure})
+
+	events, err := e.Update("van", NewPoint(0.5, 0.5))
+	if len(events) != 1 || !errors.Is(err, failure) {
+		t.Errorf("Expected the event and the notifier's error, got %v (%v)", events, err)
+	}
+}
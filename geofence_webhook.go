@@ -0,0 +1,172 @@
+package geo
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// The number of times a WebhookNotifier retries a delivery that fails, unless told otherwise.
+const DEFAULT_WEBHOOK_RETRIES = 3
+
+// How long a WebhookNotifier waits before its first retry, unless told otherwise.
+// The wait doubles with each further retry.
+const DEFAULT_WEBHOOK_BACKOFF = 500 * time.Millisecond
+
+// The header a WebhookNotifier signs its requests in, as "sha256=" followed by
+// the hex encoded HMAC-SHA256 of the request body.
+const WEBHOOK_SIGNATURE_HEADER = "X-Geo-Signature"
+
+// A WebhookNotifier is a GeofenceNotifier that POSTs each event as JSON to a URL,
+// such as:
+//
+//	{"type":"enter","fence":"depot","subject":"van-7","point":{"lat":51.5,"lng":-0.12},"time":"2024-01-02T15:04:05Z"}
+//
+// Deliveries that fail with a network error, a 429 or a 5xx are retried with
+// exponential backoff; other responses outside 2xx are not retried.
+type WebhookNotifier struct {
+	URL string
+
+	// Signs each request in WEBHOOK_SIGNATURE_HEADER when set, so that receivers
+	// can check it with VerifyWebhookSignature.
+	Secret string
+
+	Client     *http.Client
+	MaxRetries int
+	Backoff    time.Duration
+
+	// Used to wait between retries.  Overridable for testing.
+	sleep func(time.Duration)
+}
+
+// Creates and returns a pointer to a new WebhookNotifier POSTing to the passed in URL,
+// signing with the passed in secret unless it is empty.
+func NewWebhookNotifier(url string, secret string) *WebhookNotifier {
+	return &WebhookNotifier{
+		URL:        url,
+		Secret:     secret,
+		MaxRetries: DEFAULT_WEBHOOK_RETRIES,
+		Backoff:    DEFAULT_WEBHOOK_BACKOFF,
+		sleep:      time.Sleep,
+	}
+}
+
+// The JSON body of a webhook delivery.
+type webhookPayload struct {
+	Type    string    `json:"type"`
+	Fence   string    `json:"fence"`
+	Subject string    `json:"subject"`
+	Point   *Point    `json:"point"`
+	Time    time.Time `json:"time"`
+}
+
+// Describes a webhook delivery that was given up on.
+type WebhookError struct {
+	URL        string
+	StatusCode int
+	Attempts   int
+	Err        error
+}
+
+func (e *WebhookError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("webhook %s failed after %d attempts: %v", e.URL, e.Attempts, e.Err)
+	}
+
+	return fmt.Sprintf("webhook %s responded %d after %d attempts", e.URL, e.StatusCode, e.Attempts)
+}
+
+func (e *WebhookError) Unwrap() error {
+	return e.Err
+}
+
+// Returns the value of WEBHOOK_SIGNATURE_HEADER for the passed in body and secret.
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Returns whether or not the passed in signature, as found in WEBHOOK_SIGNATURE_HEADER,
+// matches the passed in body and secret.  Compares in constant time.
+func VerifyWebhookSignature(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(SignWebhookPayload(secret, body)), []byte(signature))
+}
+
+// Delivers the passed in event, retrying as described on WebhookNotifier.
+// Implements the GeofenceNotifier Interface.
+func (w *WebhookNotifier) Notify(e GeofenceEvent) error {
+	body, err := json.Marshal(webhookPayload{
+		Type:    e.Type.String(),
+		Fence:   e.FenceID,
+		Subject: e.Subject,
+		Point:   e.Point,
+		Time:    e.Time.UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	sleep := w.sleep
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+
+	backoff := w.Backoff
+	var last *WebhookError
+	for attempt := 1; attempt <= w.MaxRetries+1; attempt++ {
+		if attempt > 1 {
+			sleep(backoff)
+			backoff *= 2
+		}
+
+		status, err := w.post(body)
+		if err == nil && status >= 200 && status < 300 {
+			return nil
+		}
+
+		last = &WebhookError{URL: w.URL, StatusCode: status, Attempts: attempt, Err: err}
+		if err == nil && status != http.StatusTooManyRequests && status < 500 {
+			break
+		}
+	}
+
+	return last
+}
+
+// POSTs the passed in body to the webhook, returning the response status.
+func (w *WebhookNotifier) post(body []byte) (int, error) {
+	client := w.Client
+	if client == nil {
+		client = defaultClient()
+	}
+
+	req, err := http.NewRequest("POST", w.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if w.Secret != "" {
+		req.Header.Set(WEBHOOK_SIGNATURE_HEADER, SignWebhookPayload(w.Secret, body))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, scrubError(err)
+	}
+	defer resp.Body.Close()
+
+	// Drain the body so that the connection can be reused.
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,83 @@
+package geo
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Ensures that events are POSTed as signed JSON.
+func TestWebhookNotifierNotify(t *testing.T) {
+	var body []byte
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = ioutil.ReadAll(r.Body)
+		signature = r.Header.Get(WEBHOOK_SIGNATURE_HEADER)
+	}))
+	defer server.Close()
+
+	n := NewWebhookNotifier(server.URL, "s3cret")
+	err := n.Notify(GeofenceEvent{
+		Type:    GeofenceExit,
+		FenceID: "depot",
+		Subject: "van",
+		Point:   NewPoint(51.5, -0.12),
+		Time:    time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatal(err)
+	}
+
+	if payload["type"] != "exit" || payload["fence"] != "depot" || payload["subject"] != "van" || payload["time"] != "2024-01-02T15:04:05Z" {
+		t.Errorf("Expected the payload to describe the event, got %s", body)
+	}
+
+	if !VerifyWebhookSignature("s3cret", body, signature) {
+		t.Errorf("Expected a valid signature, got %q", signature)
+	}
+
+	if VerifyWebhookSignature("wrong", body, signature) {
+		t.Error("Expected the signature not to verify with the wrong secret")
+	}
+}
+
+// Ensures that server errors are retried with backoff, and client errors are not.
+func TestWebhookNotifierRetries(t *testing.T) {
+	statuses := []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK}
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statuses[attempts])
+		attempts++
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	n := NewWebhookNotifier(server.URL, "")
+	n.sleep = func(d time.Duration) { waits = append(waits, d) }
+
+	if err := n.Notify(GeofenceEvent{Point: NewPoint(0, 0)}); err != nil {
+		t.Errorf("Expected the third attempt to succeed, got %v", err)
+	}
+
+	if attempts != 3 || len(waits) != 2 || waits[1] != 2*waits[0] {
+		t.Errorf("Expected 3 attempts with doubling waits, got %d attempts and %v", attempts, waits)
+	}
+
+	statuses = []int{http.StatusBadRequest, http.StatusOK}
+	attempts = 0
+	err := n.Notify(GeofenceEvent{Point: NewPoint(0, 0)})
+
+	var webhookErr *WebhookError
+	if !errors.As(err, &webhookErr) || webhookErr.StatusCode != http.StatusBadRequest || attempts != 1 {
+		t.Errorf("Expected a single attempt failing with a 400, got %v after %d attempts", err, attempts)
+	}
+}
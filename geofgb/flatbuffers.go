@@ -0,0 +1,336 @@
+package geofgb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// A field of a FlatBuffers table being written: a scalar of 1, 2, 4 or 8 bytes, or a reference to
+// a string, vector or table, which is written after the table.
+type fbField struct {
+	id    int
+	size  int
+	bits  uint64
+	write func(b *fbBuilder) int
+}
+
+// Returns a field holding the passed in scalar, of the passed in size in bytes.
+func fbScalar(id, size int, bits uint64) fbField {
+	return fbField{id: id, size: size, bits: bits}
+}
+
+// Returns a field referring to the object the passed in function writes, which returns its position.
+func fbRef(id int, write func(b *fbBuilder) int) fbField {
+	return fbField{id: id, size: 4, write: write}
+}
+
+// Writes a FlatBuffer front to back: each table is followed by the objects it refers to, since
+// FlatBuffers' offsets point forward.  Positions are aligned relative to the start of the buffer.
+type fbBuilder struct {
+	buf []byte
+}
+
+// Pads the buffer with zeros until its end is at a multiple of the passed in alignment.
+func (b *fbBuilder) align(n int) {
+	for len(b.buf)%n != 0 {
+		b.buf = append(b.buf, 0)
+	}
+}
+
+// Pads the buffer so that, after a 4 byte length, it is aligned to the passed in element size.
+func (b *fbBuilder) alignVector(size int) {
+	b.align(4)
+	if size > 4 {
+		for (len(b.buf)+4)%size != 0 {
+			b.buf = append(b.buf, 0)
+		}
+	}
+}
+
+// Sets the offset at the passed in position to refer to the object at the other passed in position.
+func (b *fbBuilder) patch(at, target int) {
+	binary.LittleEndian.PutUint32(b.buf[at:], uint32(target-at))
+}
+
+// Writes a buffer whose root is the table of the passed in fields, returning it.
+func fbBuild(fields ...fbField) []byte {
+	b := &fbBuilder{buf: make([]byte, 4, 256)}
+	b.patch(0, b.table(fields...))
+	return b.buf
+}
+
+// Writes a table of the passed in fields, then the objects they refer to, returning the table's position.
+func (b *fbBuilder) table(fields ...fbField) int {
+	// Lay the fields out from largest to smallest, after the offset to the vtable, so that each is aligned.
+	sorted := append([]fbField(nil), fields...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].size > sorted[j].size })
+
+	offsets := make(map[int]int, len(fields))
+	size := 4
+	for _, f := range sorted {
+		for size%f.size != 0 {
+			size++
+		}
+		offsets[f.id] = size
+		size += f.size
+	}
+
+	vtableLength := 0
+	for _, f := range fields {
+		if f.id+1 > vtableLength {
+			vtableLength = f.id + 1
+		}
+	}
+
+	b.align(2)
+	vtable := len(b.buf)
+	b.buf = binary.LittleEndian.AppendUint16(b.buf, uint16(4+2*vtableLength))
+	b.buf = binary.LittleEndian.AppendUint16(b.buf, uint16(size))
+	for id := 0; id < vtableLength; id++ {
+		b.buf = binary.LittleEndian.AppendUint16(b.buf, uint16(offsets[id]))
+	}
+
+	b.align(8)
+	table := len(b.buf)
+	b.buf = append(b.buf, make([]byte, size)...)
+	binary.LittleEndian.PutUint32(b.buf[table:], uint32(int32(table-vtable)))
+
+	for _, f := range fields {
+		at := table + offsets[f.id]
+		switch f.size {
+		case 1:
+			b.buf[at] = byte(f.bits)
+		case 2:
+			binary.LittleEndian.PutUint16(b.buf[at:], uint16(f.bits))
+		case 4:
+			binary.LittleEndian.PutUint32(b.buf[at:], uint32(f.bits))
+		case 8:
+			binary.LittleEndian.PutUint64(b.buf[at:], f.bits)
+		}
+	}
+
+	for _, f := range fields {
+		if f.write != nil {
+			b.patch(table+offsets[f.id], f.write(b))
+		}
+	}
+
+	return table
+}
+
+// Writes a string, returning its position.
+func (b *fbBuilder) string(s string) int {
+	b.align(4)
+	at := len(b.buf)
+	b.buf = binary.LittleEndian.AppendUint32(b.buf, uint32(len(s)))
+	b.buf = append(append(b.buf, s...), 0)
+	return at
+}
+
+// Writes a vector of bytes, returning its position.
+func (b *fbBuilder) bytes(v []byte) int {
+	b.align(4)
+	at := len(b.buf)
+	b.buf = binary.LittleEndian.AppendUint32(b.buf, uint32(len(v)))
+	b.buf = append(b.buf, v...)
+	return at
+}
+
+// Writes a vector of float64s, returning its position.
+func (b *fbBuilder) float64s(v []float64) int {
+	b.alignVector(8)
+	at := len(b.buf)
+	b.buf = binary.LittleEndian.AppendUint32(b.buf, uint32(len(v)))
+	for _, f := range v {
+		b.buf = binary.LittleEndian.AppendUint64(b.buf, math.Float64bits(f))
+	}
+	return at
+}
+
+// Writes a vector of the tables of the passed in fields, returning its position.
+func (b *fbBuilder) tables(tables [][]fbField) int {
+	b.align(4)
+	at := len(b.buf)
+	b.buf = binary.LittleEndian.AppendUint32(b.buf, uint32(len(tables)))
+	b.buf = append(b.buf, make([]byte, 4*len(tables))...)
+	for i, fields := range tables {
+		b.patch(at+4+4*i, b.table(fields...))
+	}
+	return at
+}
+
+var errFlatBuffer = errors.New("geofgb: invalid FlatBuffer")
+
+// Reads a FlatBuffer, recording the first error it runs into.
+type fbReader struct {
+	buf []byte
+	err error
+}
+
+// Returns whether the passed in number of bytes at the passed in position lie within the buffer.
+func (r *fbReader) check(at, n int) bool {
+	if r.err == nil && (at < 0 || n < 0 || at > len(r.buf)-n) {
+		r.err = fmt.Errorf("%w: %d bytes at %d of %d", errFlatBuffer, n, at, len(r.buf))
+	}
+	return r.err == nil
+}
+
+func (r *fbReader) uint16(at int) uint16 {
+	if !r.check(at, 2) {
+		return 0
+	}
+	return binary.LittleEndian.Uint16(r.buf[at:])
+}
+
+func (r *fbReader) uint32(at int) uint32 {
+	if !r.check(at, 4) {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(r.buf[at:])
+}
+
+func (r *fbReader) uint64(at int) uint64 {
+	if !r.check(at, 8) {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(r.buf[at:])
+}
+
+// Returns the position the offset at the passed in position refers to.
+func (r *fbReader) deref(at int) int {
+	return at + int(r.uint32(at))
+}
+
+// A table of a FlatBuffer being read.
+type fbTable struct {
+	r      *fbReader
+	at     int
+	vtable int
+	fields int
+}
+
+// Returns the root table of the buffer.
+func (r *fbReader) root() fbTable {
+	return r.table(r.deref(0))
+}
+
+// Returns the table at the passed in position.
+func (r *fbReader) table(at int) fbTable {
+	vtable := at - int(int32(r.uint32(at)))
+	length := int(r.uint16(vtable))
+	if r.err == nil && length < 4 {
+		r.err = fmt.Errorf("%w: vtable of %d bytes", errFlatBuffer, length)
+	}
+	r.check(vtable, length)
+	if r.err != nil {
+		return fbTable{r: r}
+	}
+
+	return fbTable{r: r, at: at, vtable: vtable, fields: (length - 4) / 2}
+}
+
+// Returns the position of the passed in field, or -1 if it is absent.
+func (t fbTable) field(id int) int {
+	if t.r.err != nil || id >= t.fields {
+		return -1
+	}
+
+	offset := int(t.r.uint16(t.vtable + 4 + 2*id))
+	if offset == 0 {
+		return -1
+	}
+	return t.at + offset
+}
+
+func (t fbTable) uint8(id int, def uint8) uint8 {
+	at := t.field(id)
+	if at < 0 || !t.r.check(at, 1) {
+		return def
+	}
+	return t.r.buf[at]
+}
+
+func (t fbTable) uint16(id int, def uint16) uint16 {
+	if at := t.field(id); at >= 0 {
+		return t.r.uint16(at)
+	}
+	return def
+}
+
+func (t fbTable) int32(id int, def int32) int32 {
+	if at := t.field(id); at >= 0 {
+		return int32(t.r.uint32(at))
+	}
+	return def
+}
+
+func (t fbTable) uint64(id int) uint64 {
+	if at := t.field(id); at >= 0 {
+		return t.r.uint64(at)
+	}
+	return 0
+}
+
+// Returns the table the passed in field refers to, and whether it is present.
+func (t fbTable) table(id int) (fbTable, bool) {
+	at := t.field(id)
+	if at < 0 {
+		return fbTable{}, false
+	}
+
+	table := t.r.table(t.r.deref(at))
+	return table, t.r.err == nil
+}
+
+// Returns the position of the first element of the vector the passed in field refers to,
+// and its number of elements of the passed in size, which must lie within the buffer.
+func (t fbTable) vector(id int, size int) (int, int) {
+	at := t.field(id)
+	if at < 0 {
+		return 0, 0
+	}
+
+	at = t.r.deref(at)
+	n := int(t.r.uint32(at))
+	if !t.r.check(at+4, n*size) {
+		return 0, 0
+	}
+	return at + 4, n
+}
+
+// Returns the string, or vector of bytes, the passed in field refers to.
+func (t fbTable) bytes(id int) []byte {
+	at, n := t.vector(id, 1)
+	return t.r.buf[at : at+n]
+}
+
+func (t fbTable) float64s(id int) []float64 {
+	at, n := t.vector(id, 8)
+	v := make([]float64, n)
+	for i := range v {
+		v[i] = math.Float64frombits(binary.LittleEndian.Uint64(t.r.buf[at+8*i:]))
+	}
+	return v
+}
+
+func (t fbTable) uint32s(id int) []uint32 {
+	at, n := t.vector(id, 4)
+	v := make([]uint32, n)
+	for i := range v {
+		v[i] = binary.LittleEndian.Uint32(t.r.buf[at+4*i:])
+	}
+	return v
+}
+
+// Returns the tables of the vector the passed in field refers to.
+func (t fbTable) tables(id int) []fbTable {
+	at, n := t.vector(id, 4)
+	tables := make([]fbTable, 0, n)
+	for i := 0; i < n && t.r.err == nil; i++ {
+		tables = append(tables, t.r.table(t.r.deref(at+4*i)))
+	}
+	return tables
+}
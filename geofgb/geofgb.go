@@ -0,0 +1,130 @@
+// Package geofgb reads and writes FlatGeobuf, the binary format of geometries with a packed Hilbert R-tree
+// index, so that the features within a bounding box can be read from large files, such as a country's
+// boundaries, without reading the rest: a Writer writes geo.Features along with the index, and a Reader
+// reads them back in turn, or searches the index for those within Bounds.
+//
+//	w, err := geofgb.NewWriter(f, geo.PropertySchema{"name": {Type: geo.StringProperty}})
+//	err = w.Write(geo.NewFeature(polygon).Set("name", "Mission District"))
+//	err = w.Close()
+//
+//	r, err := geofgb.NewReader(f, size)
+//	features, err := r.Search(geo.NewBounds(geo.NewPoint(37.74, -122.43), geo.NewPoint(37.77, -122.40)))
+//
+// geofgb encodes FlatGeobuf's FlatBuffers itself, so it depends on no FlatBuffers library.
+// Features' IDs aren't stored; keep them in a property.
+package geofgb
+
+import (
+	"github.com/kellydunn/golang-geo"
+)
+
+// The bytes FlatGeobuf files start with: "fgb", the major version of the format, "fgb", then its patch version.
+const MAGIC = "fgb\x03fgb\x00"
+
+// The number of children of the nodes of the index a Writer writes, unless told otherwise.
+const DEFAULT_INDEX_NODE_SIZE = 16
+
+// The spatial reference system of the files a Writer writes: WGS 84.
+const SRID = 4326
+
+// The geometry types of FlatGeobuf.
+const (
+	geometryUnknown    = 0
+	geometryPoint      = 1
+	geometryLineString = 2
+	geometryPolygon    = 3
+)
+
+// The types of the values of a FlatGeobuf column.
+type ColumnType uint8
+
+const (
+	ByteColumn ColumnType = iota
+	UByteColumn
+	BoolColumn
+	ShortColumn
+	UShortColumn
+	IntColumn
+	UIntColumn
+	LongColumn
+	ULongColumn
+	FloatColumn
+	DoubleColumn
+	StringColumn
+	JSONColumn
+	DateTimeColumn
+	BinaryColumn
+)
+
+// Returns the number of bytes values of the ColumnType take, or zero if they are prefixed with their length.
+func (t ColumnType) size() int {
+	switch t {
+	case ByteColumn, UByteColumn, BoolColumn:
+		return 1
+	case ShortColumn, UShortColumn:
+		return 2
+	case IntColumn, UIntColumn, FloatColumn:
+		return 4
+	case LongColumn, ULongColumn, DoubleColumn:
+		return 8
+	default:
+		return 0
+	}
+}
+
+// A Column is an attribute the features of a FlatGeobuf file may have.
+type Column struct {
+	Name     string
+	Type     ColumnType
+	Nullable bool
+}
+
+// The Header of a FlatGeobuf file describes its features.
+type Header struct {
+	Name string
+
+	// The bounding box of the features, or nil if it is unknown.
+	Bounds *geo.Bounds
+
+	// The GeoJSON type of every feature's geometry, e.g. "Polygon", or "" if they differ.
+	GeometryType string
+
+	Columns []Column
+
+	// The number of features, which is zero if it is unknown.
+	FeaturesCount uint64
+
+	// The number of children of the nodes of the index, or zero if the file has none.
+	IndexNodeSize uint16
+
+	// The EPSG code of the spatial reference system of the features, or zero if it is unknown.
+	SRID int
+}
+
+// Returns the FlatGeobuf geometry type of the passed in GeoJSON type, e.g. "Point".
+func geometryTypeCode(t string) uint8 {
+	switch t {
+	case "Point":
+		return geometryPoint
+	case "LineString":
+		return geometryLineString
+	case "Polygon":
+		return geometryPolygon
+	default:
+		return geometryUnknown
+	}
+}
+
+// Returns the GeoJSON type of the passed in FlatGeobuf geometry type, or "" if it has none in this package.
+func geometryTypeName(code uint8) string {
+	switch code {
+	case geometryPoint:
+		return "Point"
+	case geometryLineString:
+		return "LineString"
+	case geometryPolygon:
+		return "Polygon"
+	default:
+		return ""
+	}
+}
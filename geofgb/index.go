@@ -0,0 +1,193 @@
+package geofgb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// The size of a node of a packed Hilbert R-tree: its bounding box, then an offset.
+const nodeItemSize = 40
+
+// A node of a packed Hilbert R-tree: the bounding box of a feature, and its offset in bytes from the
+// first feature, or the bounding box of a node's children, and the index of the first of them.
+type nodeItem struct {
+	minX, minY, maxX, maxY float64
+	offset                 uint64
+}
+
+// Returns an empty node, which expand grows to cover others.
+func emptyNode() nodeItem {
+	return nodeItem{minX: math.Inf(1), minY: math.Inf(1), maxX: math.Inf(-1), maxY: math.Inf(-1)}
+}
+
+// Grows the node to cover the passed in one.
+func (n *nodeItem) expand(other nodeItem) {
+	n.minX, n.minY = math.Min(n.minX, other.minX), math.Min(n.minY, other.minY)
+	n.maxX, n.maxY = math.Max(n.maxX, other.maxX), math.Max(n.maxY, other.maxY)
+}
+
+// Returns whether the node's bounding box intersects the passed in one's.
+func (n nodeItem) intersects(other nodeItem) bool {
+	return n.minX <= other.maxX && n.minY <= other.maxY && n.maxX >= other.minX && n.maxY >= other.minY
+}
+
+func appendNodeItem(dst []byte, n nodeItem) []byte {
+	for _, f := range []float64{n.minX, n.minY, n.maxX, n.maxY} {
+		dst = binary.LittleEndian.AppendUint64(dst, math.Float64bits(f))
+	}
+	return binary.LittleEndian.AppendUint64(dst, n.offset)
+}
+
+func readNodeItem(data []byte) nodeItem {
+	f := func(i int) float64 { return math.Float64frombits(binary.LittleEndian.Uint64(data[8*i:])) }
+	return nodeItem{minX: f(0), minY: f(1), maxX: f(2), maxY: f(3), offset: binary.LittleEndian.Uint64(data[32:])}
+}
+
+// Returns the ranges of node indices of each level of a packed R-tree of the passed in number of items
+// and node size, from its leaves up to its root.  The root is the first node, and the leaves the last,
+// so the leaves' range ends with the number of nodes.
+func levelBounds(items uint64, nodeSize uint16) ([][2]uint64, error) {
+	if nodeSize < 2 {
+		return nil, fmt.Errorf("geofgb: index node size %d is less than 2", nodeSize)
+	}
+	if items == 0 {
+		return nil, nil
+	}
+	if items > math.MaxUint64/nodeItemSize/2 {
+		return nil, fmt.Errorf("geofgb: index of %d items", items)
+	}
+
+	counts := []uint64{items}
+	total := items
+	for n := items; n != 1; {
+		n = (n + uint64(nodeSize) - 1) / uint64(nodeSize)
+		counts = append(counts, n)
+		total += n
+	}
+
+	bounds := make([][2]uint64, len(counts))
+	for i, n := range counts {
+		total -= n
+		bounds[i] = [2]uint64{total, total + n}
+	}
+
+	return bounds, nil
+}
+
+// Returns the size in bytes of a packed R-tree of the passed in number of items and node size.
+func indexSize(items uint64, nodeSize uint16) (uint64, error) {
+	bounds, err := levelBounds(items, nodeSize)
+	if err != nil || len(bounds) == 0 {
+		return 0, err
+	}
+
+	return bounds[0][1] * nodeItemSize, nil
+}
+
+// Returns the packed R-tree of the passed in leaves, which must be sorted.
+func buildIndex(leaves []nodeItem, nodeSize uint16) ([]nodeItem, error) {
+	bounds, err := levelBounds(uint64(len(leaves)), nodeSize)
+	if err != nil || len(bounds) == 0 {
+		return nil, err
+	}
+
+	nodes := make([]nodeItem, bounds[0][1])
+	copy(nodes[bounds[0][0]:], leaves)
+	for level := 0; level < len(bounds)-1; level++ {
+		parent := bounds[level+1][0]
+		for child := bounds[level][0]; child < bounds[level][1]; parent++ {
+			node := emptyNode()
+			node.offset = child
+			for end := child + uint64(nodeSize); child < end && child < bounds[level][1]; child++ {
+				node.expand(nodes[child])
+			}
+			nodes[parent] = node
+		}
+	}
+
+	return nodes, nil
+}
+
+// Sorts the passed in leaves, along with the features they are the bounding boxes of,
+// along the Hilbert curve through the centers of their bounding boxes within the passed in extent.
+func sortByHilbert(leaves []nodeItem, features [][]byte, extent nodeItem) {
+	const max = 1<<16 - 1
+	width, height := extent.maxX-extent.minX, extent.maxY-extent.minY
+	values := make([]uint32, len(leaves))
+	for i, n := range leaves {
+		var x, y uint32
+		if width > 0 {
+			x = uint32(max * ((n.minX+n.maxX)/2 - extent.minX) / width)
+		}
+		if height > 0 {
+			y = uint32(max * ((n.minY+n.maxY)/2 - extent.minY) / height)
+		}
+		values[i] = hilbert(x, y)
+	}
+
+	sort.Stable(&hilbertSort{values, leaves, features})
+}
+
+type hilbertSort struct {
+	values   []uint32
+	leaves   []nodeItem
+	features [][]byte
+}
+
+func (s *hilbertSort) Len() int           { return len(s.values) }
+func (s *hilbertSort) Less(i, j int) bool { return s.values[i] > s.values[j] }
+func (s *hilbertSort) Swap(i, j int) {
+	s.values[i], s.values[j] = s.values[j], s.values[i]
+	s.leaves[i], s.leaves[j] = s.leaves[j], s.leaves[i]
+	s.features[i], s.features[j] = s.features[j], s.features[i]
+}
+
+// Returns the distance along a Hilbert curve of the passed in 16 bit coordinates.
+// Adapted from rawrunprotected's public domain hilbert_curves.
+func hilbert(x, y uint32) uint32 {
+	a := x ^ y
+	b := 0xffff ^ a
+	c := 0xffff ^ (x | y)
+	d := x & (y ^ 0xffff)
+
+	A := a | (b >> 1)
+	B := (a >> 1) ^ a
+	C := ((c >> 1) ^ (b & (d >> 1))) ^ c
+	D := ((a & (c >> 1)) ^ (d >> 1)) ^ d
+
+	a, b, c, d = A, B, C, D
+	A = (a & (a >> 2)) ^ (b & (b >> 2))
+	B = (a & (b >> 2)) ^ (b & ((a ^ b) >> 2))
+	C ^= (a & (c >> 2)) ^ (b & (d >> 2))
+	D ^= (b & (c >> 2)) ^ ((a ^ b) & (d >> 2))
+
+	a, b, c, d = A, B, C, D
+	A = (a & (a >> 4)) ^ (b & (b >> 4))
+	B = (a & (b >> 4)) ^ (b & ((a ^ b) >> 4))
+	C ^= (a & (c >> 4)) ^ (b & (d >> 4))
+	D ^= (b & (c >> 4)) ^ ((a ^ b) & (d >> 4))
+
+	a, b, c, d = A, B, C, D
+	C ^= (a & (c >> 8)) ^ (b & (d >> 8))
+	D ^= (b & (c >> 8)) ^ ((a ^ b) & (d >> 8))
+
+	a = C ^ (C >> 1)
+	b = D ^ (D >> 1)
+
+	i0 := x ^ y
+	i1 := b | (0xffff ^ (i0 | a))
+
+	i0 = (i0 | (i0 << 8)) & 0x00ff00ff
+	i0 = (i0 | (i0 << 4)) & 0x0f0f0f0f
+	i0 = (i0 | (i0 << 2)) & 0x33333333
+	i0 = (i0 | (i0 << 1)) & 0x55555555
+
+	i1 = (i1 | (i1 << 8)) & 0x00ff00ff
+	i1 = (i1 | (i1 << 4)) & 0x0f0f0f0f
+	i1 = (i1 | (i1 << 2)) & 0x33333333
+	i1 = (i1 | (i1 << 1)) & 0x55555555
+
+	return (i1 << 1) | i0
+}
@@ -0,0 +1,438 @@
+package geofgb
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/kellydunn/golang-geo"
+	"io"
+	"math"
+	"sort"
+)
+
+// This is the error that consumers can compare against with errors.Is when a file isn't FlatGeobuf.
+var ErrNotFlatGeobuf = errors.New("geofgb: not a FlatGeobuf file")
+
+// The largest header or feature a Reader reads, so that corrupt lengths can't exhaust memory.
+const maxBufferSize = 1 << 30
+
+// A Reader reads the Features of a FlatGeobuf file: in turn with Next, or those within Bounds with Search,
+// which reads only the parts of the index, and the Features, that it needs.  Features' properties are
+// their columns' non-null values: strings, int64s, float64s, bools, values decoded from JSON columns,
+// date-times as ISO 8601 strings, and []byte for binary columns.  Multi-part geometries,
+// and polygons with holes, aren't supported.
+type Reader struct {
+	r        io.ReaderAt
+	size     int64
+	header   *Header
+	levels   [][2]uint64
+	features int64
+	next     int64
+}
+
+// Creates and returns a pointer to a new Reader for the FlatGeobuf file of the passed in size, such as an *os.File,
+// after reading its header.  Returns an error matching ErrNotFlatGeobuf if it isn't FlatGeobuf, and an error
+// if its geometries aren't in WGS 84.
+func NewReader(r io.ReaderAt, size int64) (*Reader, error) {
+	start := make([]byte, len(MAGIC)+4)
+	if size < int64(len(start)) {
+		return nil, fmt.Errorf("%w: %d bytes", ErrNotFlatGeobuf, size)
+	}
+	if _, err := r.ReadAt(start, 0); err != nil {
+		return nil, err
+	}
+	if string(start[:4]) != MAGIC[:4] || string(start[4:7]) != MAGIC[4:7] {
+		return nil, fmt.Errorf("%w: no FlatGeobuf magic number", ErrNotFlatGeobuf)
+	}
+
+	length := int64(binary.LittleEndian.Uint32(start[len(MAGIC):]))
+	if length > size-int64(len(start)) || length > maxBufferSize {
+		return nil, fmt.Errorf("%w: header of %d bytes", errFlatBuffer, length)
+	}
+	data := make([]byte, length)
+	if _, err := r.ReadAt(data, int64(len(start))); err != nil {
+		return nil, err
+	}
+
+	header, err := readHeader(data)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := &Reader{r: r, size: size, header: header, features: int64(len(start)) + length}
+	if header.IndexNodeSize > 0 && header.FeaturesCount > 0 {
+		if reader.levels, err = levelBounds(header.FeaturesCount, header.IndexNodeSize); err != nil {
+			return nil, err
+		}
+		index, _ := indexSize(header.FeaturesCount, header.IndexNodeSize)
+		if index > uint64(size-reader.features) {
+			return nil, fmt.Errorf("%w: index of %d bytes", errFlatBuffer, index)
+		}
+		reader.features += int64(index)
+	}
+	reader.next = reader.features
+
+	return reader, nil
+}
+
+// Decodes a file's header.
+func readHeader(data []byte) (*Header, error) {
+	r := &fbReader{buf: data}
+	t := r.root()
+	header := &Header{
+		Name:          string(t.bytes(0)),
+		GeometryType:  geometryTypeName(t.uint8(2, geometryUnknown)),
+		FeaturesCount: t.uint64(8),
+		IndexNodeSize: t.uint16(9, DEFAULT_INDEX_NODE_SIZE),
+	}
+
+	if code := t.uint8(2, geometryUnknown); code != geometryUnknown && header.GeometryType == "" {
+		return nil, fmt.Errorf("geofgb: geometry type %d isn't supported", code)
+	}
+
+	if envelope := t.float64s(1); len(envelope) >= 4 {
+		header.Bounds = geo.NewBounds(geo.NewPoint(envelope[1], envelope[0]), geo.NewPoint(envelope[3], envelope[2]))
+	}
+
+	header.Columns = readColumns(t, 7)
+
+	if crs, ok := t.table(10); ok {
+		header.SRID = int(crs.int32(1, 0))
+	}
+	if header.SRID != 0 && header.SRID != SRID {
+		return nil, fmt.Errorf("geofgb: features are in EPSG:%d, not WGS 84", header.SRID)
+	}
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	return header, nil
+}
+
+// Returns the columns the passed in field of the passed in table refers to.
+func readColumns(t fbTable, id int) []Column {
+	var columns []Column
+	for _, c := range t.tables(id) {
+		columns = append(columns, Column{
+			Name:     string(c.bytes(0)),
+			Type:     ColumnType(c.uint8(1, 0)),
+			Nullable: c.uint8(7, 1) != 0,
+		})
+	}
+
+	return columns
+}
+
+// Returns the file's header.
+func (r *Reader) Header() *Header {
+	return r.header
+}
+
+// Returns the next Feature in the file, or io.EOF once every Feature has been read.
+func (r *Reader) Next() (*geo.Feature, error) {
+	if r.next >= r.size {
+		return nil, io.EOF
+	}
+
+	f, next, err := r.readFeature(r.next)
+	if err != nil {
+		return nil, err
+	}
+
+	r.next = next
+	return f, nil
+}
+
+// Reads the Feature at the passed in position, returning it along with the position following it.
+func (r *Reader) readFeature(at int64) (*geo.Feature, int64, error) {
+	var prefix [4]byte
+	if _, err := r.r.ReadAt(prefix[:], at); err != nil {
+		return nil, 0, err
+	}
+
+	length := int64(binary.LittleEndian.Uint32(prefix[:]))
+	if length > r.size-at-4 || length > maxBufferSize {
+		return nil, 0, fmt.Errorf("%w: feature of %d bytes", errFlatBuffer, length)
+	}
+	data := make([]byte, length)
+	if _, err := r.r.ReadAt(data, at+4); err != nil {
+		return nil, 0, err
+	}
+
+	f, err := r.decodeFeature(data)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return f, at + 4 + length, nil
+}
+
+// Decodes a Feature.
+func (r *Reader) decodeFeature(data []byte) (*geo.Feature, error) {
+	fb := &fbReader{buf: data}
+	t := fb.root()
+
+	f := &geo.Feature{Properties: make(map[string]interface{})}
+	if geometry, ok := t.table(0); ok {
+		g, err := r.decodeGeometry(geometry)
+		if err != nil {
+			return nil, err
+		}
+		f.Geometry = g
+	}
+
+	columns := r.header.Columns
+	if own := readColumns(t, 2); len(own) > 0 {
+		columns = own
+	}
+	if err := decodeProperties(t.bytes(1), columns, f.Properties); err != nil {
+		return nil, err
+	}
+
+	if fb.err != nil {
+		return nil, fb.err
+	}
+
+	return f, nil
+}
+
+// Decodes a geometry, which has the header's geometry type unless it has its own.
+func (r *Reader) decodeGeometry(t fbTable) (geo.Geometry, error) {
+	name := r.header.GeometryType
+	if code := t.uint8(6, geometryUnknown); code != geometryUnknown {
+		if name = geometryTypeName(code); name == "" {
+			return nil, fmt.Errorf("geofgb: geometry type %d isn't supported", code)
+		}
+	}
+	if _, n := t.vector(7, 4); n > 0 {
+		return nil, errors.New("geofgb: multi-part geometries aren't supported")
+	}
+
+	xy := t.float64s(1)
+	points := make([]*geo.Point, len(xy)/2)
+	for i := range points {
+		points[i] = geo.NewPoint(xy[2*i+1], xy[2*i])
+	}
+
+	switch name {
+	case "Point":
+		if len(points) != 1 {
+			return nil, fmt.Errorf("geofgb: point of %d coordinates", len(xy))
+		}
+		return points[0], nil
+	case "LineString":
+		return geo.Line(points), nil
+	case "Polygon":
+		if ends := t.uint32s(0); len(ends) > 1 {
+			return nil, errors.New("geofgb: polygons with holes aren't supported")
+		}
+		if n := len(points); n > 1 && *points[0] == *points[n-1] {
+			points = points[:n-1]
+		}
+		return geo.NewPolygon(points), nil
+	default:
+		return nil, errors.New("geofgb: feature without a geometry type")
+	}
+}
+
+// Decodes properties, each the index of its column followed by its value, into the passed in map.
+func decodeProperties(data []byte, columns []Column, properties map[string]interface{}) error {
+	for len(data) > 0 {
+		if len(data) < 2 {
+			return fmt.Errorf("%w: truncated properties", errFlatBuffer)
+		}
+		i := int(binary.LittleEndian.Uint16(data))
+		data = data[2:]
+		if i >= len(columns) {
+			return fmt.Errorf("%w: property of column %d of %d", errFlatBuffer, i, len(columns))
+		}
+		c := columns[i]
+
+		size := c.Type.size()
+		if size == 0 {
+			if len(data) < 4 || int64(binary.LittleEndian.Uint32(data)) > int64(len(data)-4) {
+				return fmt.Errorf("%w: truncated property %q", errFlatBuffer, c.Name)
+			}
+			size = 4 + int(binary.LittleEndian.Uint32(data))
+		}
+		if len(data) < size {
+			return fmt.Errorf("%w: truncated property %q", errFlatBuffer, c.Name)
+		}
+		value := data[:size]
+		data = data[size:]
+
+		switch c.Type {
+		case ByteColumn:
+			properties[c.Name] = int64(int8(value[0]))
+		case UByteColumn:
+			properties[c.Name] = int64(value[0])
+		case BoolColumn:
+			properties[c.Name] = value[0] != 0
+		case ShortColumn:
+			properties[c.Name] = int64(int16(binary.LittleEndian.Uint16(value)))
+		case UShortColumn:
+			properties[c.Name] = int64(binary.LittleEndian.Uint16(value))
+		case IntColumn:
+			properties[c.Name] = int64(int32(binary.LittleEndian.Uint32(value)))
+		case UIntColumn:
+			properties[c.Name] = int64(binary.LittleEndian.Uint32(value))
+		case LongColumn:
+			properties[c.Name] = int64(binary.LittleEndian.Uint64(value))
+		case ULongColumn:
+			if n := binary.LittleEndian.Uint64(value); n <= math.MaxInt64 {
+				properties[c.Name] = int64(n)
+			} else {
+				properties[c.Name] = float64(n)
+			}
+		case FloatColumn:
+			properties[c.Name] = float64(math.Float32frombits(binary.LittleEndian.Uint32(value)))
+		case DoubleColumn:
+			properties[c.Name] = math.Float64frombits(binary.LittleEndian.Uint64(value))
+		case StringColumn, DateTimeColumn:
+			properties[c.Name] = string(value[4:])
+		case JSONColumn:
+			var decoded interface{}
+			if err := json.Unmarshal(value[4:], &decoded); err != nil {
+				return fmt.Errorf("geofgb: property %q: %w", c.Name, err)
+			}
+			properties[c.Name] = decoded
+		case BinaryColumn:
+			properties[c.Name] = append([]byte(nil), value[4:]...)
+		default:
+			return fmt.Errorf("geofgb: column %q has unknown type %d", c.Name, c.Type)
+		}
+	}
+
+	return nil
+}
+
+// Returns the Features whose bounding boxes intersect the passed in Bounds, in the order they are stored.
+// Bounds whose west edge is east of their east edge are taken to cross the antimeridian.  Files without
+// an index are read in full.
+func (r *Reader) Search(bounds *geo.Bounds) ([]*geo.Feature, error) {
+	sw, ne := bounds.SouthWest(), bounds.NorthEast()
+	boxes := []nodeItem{{minX: sw.Lng(), minY: sw.Lat(), maxX: ne.Lng(), maxY: ne.Lat()}}
+	if sw.Lng() > ne.Lng() {
+		boxes = []nodeItem{
+			{minX: sw.Lng(), minY: sw.Lat(), maxX: 180, maxY: ne.Lat()},
+			{minX: -180, minY: sw.Lat(), maxX: ne.Lng(), maxY: ne.Lat()},
+		}
+	}
+
+	if r.levels == nil {
+		return r.scan(boxes)
+	}
+
+	found := make(map[uint64]bool)
+	for _, box := range boxes {
+		if err := r.searchIndex(box, found); err != nil {
+			return nil, err
+		}
+	}
+
+	offsets := make([]uint64, 0, len(found))
+	for offset := range found {
+		offsets = append(offsets, offset)
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+
+	features := make([]*geo.Feature, 0, len(offsets))
+	for _, offset := range offsets {
+		if offset > uint64(r.size-r.features) {
+			return nil, fmt.Errorf("%w: feature at %d", errFlatBuffer, offset)
+		}
+		f, _, err := r.readFeature(r.features + int64(offset))
+		if err != nil {
+			return nil, err
+		}
+		features = append(features, f)
+	}
+
+	return features, nil
+}
+
+// Adds the offsets of the features whose bounding boxes intersect the passed in box to found,
+// reading the index a node at a time, from its root down.
+func (r *Reader) searchIndex(box nodeItem, found map[uint64]bool) error {
+	type entry struct {
+		node  uint64
+		level int
+	}
+
+	start := r.features - int64(r.levels[0][1]*nodeItemSize)
+	leaves := r.levels[0][0]
+	queue := []entry{{0, len(r.levels) - 1}}
+	for len(queue) > 0 {
+		e := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+
+		end := e.node + uint64(r.header.IndexNodeSize)
+		if levelEnd := r.levels[e.level][1]; end > levelEnd {
+			end = levelEnd
+		}
+		if e.node >= end {
+			return fmt.Errorf("%w: index node %d outside level %d", errFlatBuffer, e.node, e.level)
+		}
+
+		data := make([]byte, (end-e.node)*nodeItemSize)
+		if _, err := r.r.ReadAt(data, start+int64(e.node*nodeItemSize)); err != nil {
+			return err
+		}
+
+		for i := uint64(0); i < end-e.node; i++ {
+			node := readNodeItem(data[i*nodeItemSize:])
+			if !node.intersects(box) {
+				continue
+			}
+
+			if e.node >= leaves {
+				found[node.offset] = true
+			} else if e.level > 0 {
+				queue = append(queue, entry{node.offset, e.level - 1})
+			}
+		}
+	}
+
+	return nil
+}
+
+// Returns the Features whose bounding boxes intersect any of the passed in boxes, reading every Feature.
+func (r *Reader) scan(boxes []nodeItem) ([]*geo.Feature, error) {
+	var features []*geo.Feature
+	for at := r.features; at < r.size; {
+		f, next, err := r.readFeature(at)
+		if err != nil {
+			return nil, err
+		}
+		at = next
+
+		bbox := emptyNode()
+		for _, p := range geometryPoints(f.Geometry) {
+			bbox.expand(nodeItem{minX: p.Lng(), minY: p.Lat(), maxX: p.Lng(), maxY: p.Lat()})
+		}
+		for _, box := range boxes {
+			if bbox.intersects(box) {
+				features = append(features, f)
+				break
+			}
+		}
+	}
+
+	return features, nil
+}
+
+// Returns the points of the passed in geometry.
+func geometryPoints(g geo.Geometry) []*geo.Point {
+	switch g := g.(type) {
+	case *geo.Point:
+		return []*geo.Point{g}
+	case geo.Line:
+		return g
+	case *geo.Polygon:
+		return g.Points()
+	default:
+		return nil
+	}
+}
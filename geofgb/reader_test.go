@@ -0,0 +1,177 @@
+package geofgb
+
+import (
+	"bytes"
+	"errors"
+	"github.com/kellydunn/golang-geo"
+	"io"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// Writes a grid of points, one a degree apart, with the passed in index node size.
+func writeGrid(t *testing.T, nodeSize uint16) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, geo.PropertySchema{
+		"name": {Type: geo.StringProperty, Required: true},
+		"n":    {Type: geo.IntegerProperty},
+	})
+	w.IndexNodeSize = nodeSize
+	for lat := -10; lat < 10; lat++ {
+		for lng := 170; lng < 190; lng++ {
+			f := geo.NewFeature(geo.NewPoint(float64(lat), geo.NormalizeLng(float64(lng)))).Set("name", "p")
+			if err := w.Write(f.Set("n", lat*1000+lng)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+// Ensures that Features are read back as they were written.
+func TestRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, geo.PropertySchema{
+		"name":   {Type: geo.StringProperty, Required: true},
+		"gates":  {Type: geo.IntegerProperty},
+		"rating": {Type: geo.NumberProperty},
+		"open":   {Type: geo.BoolProperty},
+		"tags":   {},
+	})
+	w.Name = "places"
+	features := []*geo.Feature{
+		geo.NewFeature(geo.NewPoint(37.615223, -122.389979)).Set("name", "SFO").Set("gates", 115).Set("open", true),
+		geo.NewFeature(geo.Line{geo.NewPoint(0, 0), geo.NewPoint(1, 1)}).Set("name", "route").Set("rating", 4.5),
+		geo.NewFeature(geo.NewPolygon([]*geo.Point{geo.NewPoint(0, 0), geo.NewPoint(0, 2), geo.NewPoint(2, 2)})).Set("name", "zone").Set("tags", []interface{}{"a", "b"}),
+	}
+	for _, f := range features {
+		if err := w.Write(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := r.Header()
+	if h.Name != "places" || h.GeometryType != "" || h.FeaturesCount != 3 || h.IndexNodeSize != DEFAULT_INDEX_NODE_SIZE || h.SRID != SRID || len(h.Columns) != 5 {
+		t.Errorf("Expected the header of 3 places, got %+v", h)
+	}
+	if *h.Bounds.SouthWest() != *geo.NewPoint(0, -122.389979) || *h.Bounds.NorthEast() != *geo.NewPoint(37.615223, 2) {
+		t.Errorf("Expected the bounds of the features, got %v %v", h.Bounds.SouthWest(), h.Bounds.NorthEast())
+	}
+
+	var read []*geo.Feature
+	for {
+		f, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		read = append(read, f)
+	}
+
+	// The index orders features along a Hilbert curve.
+	sort.Slice(read, func(i, j int) bool { return read[i].Properties["name"].(string) < read[j].Properties["name"].(string) })
+	expected := features
+	for i, f := range read {
+		properties := map[string]interface{}{}
+		for key, value := range expected[i].Properties {
+			properties[key] = value
+		}
+		if gates, ok := properties["gates"]; ok {
+			properties["gates"] = int64(gates.(int))
+		}
+
+		if !reflect.DeepEqual(f.Geometry, expected[i].Geometry) || !reflect.DeepEqual(f.Properties, properties) {
+			t.Errorf("Expected %v %v, got %v %v", expected[i].Geometry, properties, f.Geometry, f.Properties)
+		}
+	}
+	if len(read) != 3 {
+		t.Errorf("Expected 3 features, got %d", len(read))
+	}
+}
+
+// Ensures that searches find the features within Bounds, with and without an index,
+// including Bounds crossing the antimeridian.
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		bounds   *geo.Bounds
+		expected []int
+	}{
+		{geo.NewBounds(geo.NewPoint(0.5, 171.5), geo.NewPoint(2.5, 172.5)), []int{1172, 2172}},
+		{geo.NewBounds(geo.NewPoint(-1, 178.5), geo.NewPoint(0, -178.5)), []int{-821, -820, -819, 179, 180, 181}},
+		{geo.NewBounds(geo.NewPoint(20, 0), geo.NewPoint(30, 10)), nil},
+	}
+
+	for _, nodeSize := range []uint16{0, 2, DEFAULT_INDEX_NODE_SIZE} {
+		data := writeGrid(t, nodeSize)
+		r, err := NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, test := range tests {
+			features, err := r.Search(test.bounds)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var found []int
+			for _, f := range features {
+				found = append(found, int(f.Properties["n"].(int64)))
+			}
+			sort.Ints(found)
+			expected := append([]int(nil), test.expected...)
+			sort.Ints(expected)
+			if !reflect.DeepEqual(found, expected) {
+				t.Errorf("Expected %v with node size %d, got %v", expected, nodeSize, found)
+			}
+		}
+	}
+}
+
+// Ensures that the index has a level for each node size of items, from its leaves to its root.
+func TestLevelBounds(t *testing.T) {
+	bounds, err := levelBounds(10, 4)
+	expected := [][2]uint64{{4, 14}, {1, 4}, {0, 1}}
+	if err != nil || !reflect.DeepEqual(bounds, expected) {
+		t.Errorf("Expected %v, got %v (%v)", expected, bounds, err)
+	}
+
+	if _, err := levelBounds(10, 1); err == nil {
+		t.Error("Expected an error for a node size of 1")
+	}
+}
+
+// Ensures that invalid features and files are refused.
+func TestInvalid(t *testing.T) {
+	w := NewWriter(io.Discard, nil)
+	if err := w.Write(geo.NewFeature(nil)); err == nil {
+		t.Error("Expected an error for a feature without a geometry")
+	}
+
+	data := []byte("not a FlatGeobuf file")
+	if _, err := NewReader(bytes.NewReader(data), int64(len(data))); !errors.Is(err, ErrNotFlatGeobuf) {
+		t.Errorf("Expected ErrNotFlatGeobuf, got %v", err)
+	}
+
+	data = writeGrid(t, DEFAULT_INDEX_NODE_SIZE)
+	if _, err := NewReader(bytes.NewReader(data[:100]), 100); err == nil {
+		t.Error("Expected an error for a truncated file")
+	}
+}
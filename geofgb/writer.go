@@ -0,0 +1,261 @@
+package geofgb
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/kellydunn/golang-geo"
+	"io"
+	"math"
+	"sort"
+)
+
+// A Writer writes Features to a FlatGeobuf file, along with a packed Hilbert R-tree index of their bounding boxes
+// unless told otherwise.  Its columns are the properties of the schema it is created with, in order of name:
+// StringProperty properties are written as strings, NumberProperty as doubles, IntegerProperty as longs,
+// BoolProperty as bools, and AnyProperty as JSON.  Properties outside the schema aren't written.
+// Since the index orders features along a Hilbert curve and precedes them, Features are buffered,
+// and the file is written on Close.
+type Writer struct {
+	// The name of the dataset, recorded in the header.
+	Name string
+
+	// The number of children of the index's nodes, or zero to write no index.  Defaults to DEFAULT_INDEX_NODE_SIZE.
+	IndexNodeSize uint16
+
+	w        io.Writer
+	schema   geo.PropertySchema
+	columns  []Column
+	features [][]byte
+	leaves   []nodeItem
+	extent   nodeItem
+	types    map[string]bool
+	closed   bool
+}
+
+// Creates and returns a pointer to a new Writer writing the Features of the passed in schema to the passed in writer.
+func NewWriter(w io.Writer, schema geo.PropertySchema) *Writer {
+	keys := make([]string, 0, len(schema))
+	for key := range schema {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	columns := make([]Column, len(keys))
+	for i, key := range keys {
+		columns[i] = Column{Name: key, Type: JSONColumn, Nullable: !schema[key].Required}
+		switch schema[key].Type {
+		case geo.StringProperty:
+			columns[i].Type = StringColumn
+		case geo.NumberProperty:
+			columns[i].Type = DoubleColumn
+		case geo.IntegerProperty:
+			columns[i].Type = LongColumn
+		case geo.BoolProperty:
+			columns[i].Type = BoolColumn
+		}
+	}
+
+	return &Writer{
+		IndexNodeSize: DEFAULT_INDEX_NODE_SIZE,
+		w:             w,
+		schema:        schema,
+		columns:       columns,
+		extent:        emptyNode(),
+		types:         make(map[string]bool),
+	}
+}
+
+// Buffers the passed in Feature.  Returns an error if its properties don't match the schema,
+// or it has no geometry, which every feature in the index must have.
+func (w *Writer) Write(f *geo.Feature) error {
+	if w.closed {
+		return errors.New("geofgb: write to a closed Writer")
+	}
+	if f.Geometry == nil {
+		return errors.New("geofgb: features must have a geometry")
+	}
+	if err := w.schema.Validate(f); err != nil {
+		return err
+	}
+
+	var points []*geo.Point
+	switch g := f.Geometry.(type) {
+	case *geo.Point:
+		points = []*geo.Point{g}
+	case geo.Line:
+		points = g
+	case *geo.Polygon:
+		// FlatGeobuf rings repeat their first point at the end.
+		points = g.Points()
+		if len(points) > 0 && *points[0] != *points[len(points)-1] {
+			points = append(points[:len(points):len(points)], points[0])
+		}
+	default:
+		return fmt.Errorf("geofgb: can't write a %T", f.Geometry)
+	}
+	if len(points) == 0 {
+		return errors.New("geofgb: features must have a geometry")
+	}
+
+	bbox := emptyNode()
+	xy := make([]float64, 0, 2*len(points))
+	for i, p := range points {
+		if p == nil {
+			return fmt.Errorf("geofgb: point %d is nil", i)
+		}
+		xy = append(xy, p.Lng(), p.Lat())
+		bbox.expand(nodeItem{minX: p.Lng(), minY: p.Lat(), maxX: p.Lng(), maxY: p.Lat()})
+	}
+
+	properties, err := w.encodeProperties(f)
+	if err != nil {
+		return err
+	}
+
+	geometry := []fbField{
+		fbRef(1, func(b *fbBuilder) int { return b.float64s(xy) }),
+		fbScalar(6, 1, uint64(geometryTypeCode(f.Geometry.GeometryType()))),
+	}
+	feature := fbBuild(
+		fbRef(0, func(b *fbBuilder) int { return b.table(geometry...) }),
+		fbRef(1, func(b *fbBuilder) int { return b.bytes(properties) }),
+	)
+
+	w.features = append(w.features, feature)
+	w.leaves = append(w.leaves, bbox)
+	w.extent.expand(bbox)
+	w.types[f.Geometry.GeometryType()] = true
+	return nil
+}
+
+// Returns the passed in Feature's properties as FlatGeobuf encodes them: the index of each
+// non-null property's column, followed by its value.
+func (w *Writer) encodeProperties(f *geo.Feature) ([]byte, error) {
+	var properties []byte
+	for i, c := range w.columns {
+		if value, ok := f.Properties[c.Name]; !ok || value == nil {
+			continue
+		}
+
+		properties = binary.LittleEndian.AppendUint16(properties, uint16(i))
+		switch c.Type {
+		case BoolColumn:
+			b, _ := f.PropertyBool(c.Name)
+			if b {
+				properties = append(properties, 1)
+			} else {
+				properties = append(properties, 0)
+			}
+		case DoubleColumn:
+			n, _ := f.PropertyFloat(c.Name)
+			properties = binary.LittleEndian.AppendUint64(properties, math.Float64bits(n))
+		case LongColumn:
+			n, _ := f.PropertyInt(c.Name)
+			properties = binary.LittleEndian.AppendUint64(properties, uint64(n))
+		case StringColumn:
+			s, _ := f.PropertyString(c.Name)
+			properties = binary.LittleEndian.AppendUint32(properties, uint32(len(s)))
+			properties = append(properties, s...)
+		default:
+			data, err := json.Marshal(f.Properties[c.Name])
+			if err != nil {
+				return nil, fmt.Errorf("geofgb: property %q: %w", c.Name, err)
+			}
+			properties = binary.LittleEndian.AppendUint32(properties, uint32(len(data)))
+			properties = append(properties, data...)
+		}
+	}
+
+	return properties, nil
+}
+
+// Writes the file: its header, the index of the buffered Features, then the Features themselves.
+// Close doesn't close the underlying writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	var index []nodeItem
+	if w.IndexNodeSize > 0 && len(w.features) > 0 {
+		sortByHilbert(w.leaves, w.features, w.extent)
+
+		var offset uint64
+		for i, feature := range w.features {
+			w.leaves[i].offset = offset
+			offset += 4 + uint64(len(feature))
+		}
+
+		var err error
+		if index, err = buildIndex(w.leaves, w.IndexNodeSize); err != nil {
+			return err
+		}
+	}
+
+	data := append([]byte(MAGIC), w.header()...)
+	for _, node := range index {
+		data = appendNodeItem(data, node)
+	}
+	if _, err := w.w.Write(data); err != nil {
+		return err
+	}
+
+	for _, feature := range w.features {
+		data = binary.LittleEndian.AppendUint32(data[:0], uint32(len(feature)))
+		if _, err := w.w.Write(append(data, feature...)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Returns the file's header, prefixed with its length.
+func (w *Writer) header() []byte {
+	geometryType := uint8(geometryUnknown)
+	for t := range w.types {
+		if len(w.types) == 1 {
+			geometryType = geometryTypeCode(t)
+		}
+	}
+
+	columns := make([][]fbField, len(w.columns))
+	for i, c := range w.columns {
+		name := c.Name
+		nullable := uint64(0)
+		if c.Nullable {
+			nullable = 1
+		}
+		columns[i] = []fbField{
+			fbRef(0, func(b *fbBuilder) int { return b.string(name) }),
+			fbScalar(1, 1, uint64(c.Type)),
+			fbScalar(7, 1, nullable),
+		}
+	}
+
+	fields := []fbField{
+		fbScalar(2, 1, uint64(geometryType)),
+		fbRef(7, func(b *fbBuilder) int { return b.tables(columns) }),
+		fbScalar(8, 8, uint64(len(w.features))),
+		fbScalar(9, 2, uint64(w.IndexNodeSize)),
+		fbRef(10, func(b *fbBuilder) int {
+			return b.table(
+				fbRef(0, func(b *fbBuilder) int { return b.string("EPSG") }),
+				fbScalar(1, 4, SRID),
+			)
+		}),
+	}
+	if w.Name != "" {
+		fields = append(fields, fbRef(0, func(b *fbBuilder) int { return b.string(w.Name) }))
+	}
+	if len(w.features) > 0 {
+		envelope := []float64{w.extent.minX, w.extent.minY, w.extent.maxX, w.extent.maxY}
+		fields = append(fields, fbRef(1, func(b *fbBuilder) int { return b.float64s(envelope) }))
+	}
+
+	header := fbBuild(fields...)
+	return append(binary.LittleEndian.AppendUint32(nil, uint32(len(header))), header...)
+}
@@ -0,0 +1,112 @@
+package geogrpc
+
+import (
+	"fmt"
+	"github.com/kellydunn/golang-geo"
+)
+
+// Returns the LatLng of the passed in Point, or nil if it is nil.
+func FromPoint(p *geo.Point) *LatLng {
+	if p == nil {
+		return nil
+	}
+
+	return &LatLng{Lat: p.Lat(), Lng: p.Lng()}
+}
+
+// Returns the Point the current LatLng describes, or nil if it is nil.
+// Unlike the Server, it doesn't check that the location is in range.
+func (x *LatLng) GeoPoint() *geo.Point {
+	if x == nil {
+		return nil
+	}
+
+	return geo.NewPoint(x.GetLat(), x.GetLng())
+}
+
+// Returns the LatLngs of the passed in points.
+func fromPoints(points []*geo.Point) []*LatLng {
+	latLngs := make([]*LatLng, len(points))
+	for i, p := range points {
+		latLngs[i] = FromPoint(p)
+	}
+
+	return latLngs
+}
+
+// Returns the Points the passed in LatLngs describe.
+func geoPoints(latLngs []*LatLng) []*geo.Point {
+	points := make([]*geo.Point, len(latLngs))
+	for i, ll := range latLngs {
+		points[i] = ll.GeoPoint()
+	}
+
+	return points
+}
+
+// Returns the Polyline through the points of the passed in Line.
+func FromLine(l geo.Line) *Polyline {
+	return &Polyline{Points: fromPoints(l)}
+}
+
+// Returns the Line through the points of the current Polyline, or nil if it is nil.
+func (x *Polyline) GeoLine() geo.Line {
+	if x == nil {
+		return nil
+	}
+
+	return geo.Line(geoPoints(x.GetPoints()))
+}
+
+// Returns the Polygon of the passed in Polygon's points, or nil if it is nil.
+func FromPolygon(p *geo.Polygon) *Polygon {
+	if p == nil {
+		return nil
+	}
+
+	return &Polygon{Points: fromPoints(p.Points())}
+}
+
+// Returns the Polygon of the current Polygon's points, or nil if it is nil.
+func (x *Polygon) GeoPolygon() *geo.Polygon {
+	if x == nil {
+		return nil
+	}
+
+	return geo.NewPolygon(geoPoints(x.GetPoints()))
+}
+
+// Returns the Geometry holding the passed in *geo.Point, geo.Line or *geo.Polygon.
+func FromGeometry(g geo.Geometry) (*Geometry, error) {
+	switch g := g.(type) {
+	case *geo.Point:
+		return &Geometry{Shape: &Geometry_Point{Point: FromPoint(g)}}, nil
+	case geo.Line:
+		return &Geometry{Shape: &Geometry_Polyline{Polyline: FromLine(g)}}, nil
+	case *geo.Polygon:
+		return &Geometry{Shape: &Geometry_Polygon{Polygon: FromPolygon(g)}}, nil
+	default:
+		return nil, fmt.Errorf("geogrpc: can't convert a %T", g)
+	}
+}
+
+// Returns the *geo.Point, geo.Line or *geo.Polygon the current Geometry holds,
+// or an error if it holds none.
+func (x *Geometry) GeoGeometry() (geo.Geometry, error) {
+	switch shape := x.GetShape().(type) {
+	case *Geometry_Point:
+		if shape.Point != nil {
+			return shape.Point.GeoPoint(), nil
+		}
+	case *Geometry_Polyline:
+		if shape.Polyline != nil {
+			return shape.Polyline.GeoLine(), nil
+		}
+	case *Geometry_Polygon:
+		if shape.Polygon != nil {
+			return shape.Polygon.GeoPolygon(), nil
+		}
+	}
+
+	return nil, fmt.Errorf("geogrpc: geometry is empty")
+}
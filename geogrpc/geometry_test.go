@@ -0,0 +1,40 @@
+package geogrpc
+
+import (
+	"github.com/kellydunn/golang-geo"
+	"google.golang.org/protobuf/proto"
+	"reflect"
+	"testing"
+)
+
+// Ensures that geometries survive a round trip through their messages and the wire.
+func TestGeometryRoundTrip(t *testing.T) {
+	points := []*geo.Point{geo.NewPoint(37.6, -122.4), geo.NewPoint(37.8, -122.4), geo.NewPoint(37.7, -122.2)}
+	geometries := []geo.Geometry{points[0], geo.Line(points), geo.NewPolygon(points)}
+
+	for _, g := range geometries {
+		msg, err := FromGeometry(g)
+		if err != nil {
+			t.Fatalf("Expected %T to convert, got %v", g, err)
+		}
+
+		data, err := proto.Marshal(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decoded := &Geometry{}
+		if err := proto.Unmarshal(data, decoded); err != nil {
+			t.Fatal(err)
+		}
+
+		back, err := decoded.GeoGeometry()
+		if err != nil || !reflect.DeepEqual(back, g) {
+			t.Errorf("Expected %v, got %v (%v)", g, back, err)
+		}
+	}
+
+	if _, err := (&Geometry{}).GeoGeometry(); err == nil {
+		t.Error("Expected an error converting an empty geometry")
+	}
+}
@@ -0,0 +1,184 @@
+// Package geogrpc serves golang-geo over gRPC, for microservice environments
+// where clients are written in other languages.  The service is defined in geo.proto;
+// clients in any language can be generated from it.
+package geogrpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative geo.proto
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/kellydunn/golang-geo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"math"
+)
+
+// The largest radius, in kilometers, that Nearby accepts unless the Server says otherwise.
+const DEFAULT_MAX_RADIUS = 100.0
+
+// A Server implements GeoServiceServer on top of a geocoder and an index of places.
+// Register it with a grpc.Server using RegisterGeoServiceServer.
+type Server struct {
+	UnimplementedGeoServiceServer
+
+	// Answers Geocode and ReverseGeocode.  Those calls fail with Unimplemented when nil.
+	Geocoder geo.Geocoder
+
+	// Answers Nearby.  That call fails with Unimplemented when nil.
+	// Requests search it concurrently; call its Build once places have been inserted, so that they're indexed.
+	Places *geo.KDTree[*geo.Feature]
+
+	// The largest radius, in kilometers, Nearby accepts.  Defaults to DEFAULT_MAX_RADIUS.
+	MaxRadius float64
+}
+
+// Creates and returns a pointer to a new Server answering from the passed in geocoder and places.
+// Either may be nil to disable the calls that use it.
+func NewServer(g geo.Geocoder, places *geo.KDTree[*geo.Feature]) *Server {
+	return &Server{Geocoder: g, Places: places}
+}
+
+// Returns the Point the passed in LatLng describes, or an InvalidArgument error
+// if it is missing or out of range.
+func pointFromLatLng(ll *LatLng) (*geo.Point, error) {
+	if ll == nil {
+		return nil, status.Error(codes.InvalidArgument, "point is required")
+	}
+
+	lat, lng := ll.GetLat(), ll.GetLng()
+	if math.IsNaN(lat) || math.IsNaN(lng) || lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		return nil, status.Errorf(codes.InvalidArgument, "point (%v, %v) is out of range", lat, lng)
+	}
+
+	return geo.NewPoint(lat, lng), nil
+}
+
+// Returns the passed in geocoder error as a status of the matching code, unless it already carries one:
+// NotFound, ResourceExhausted or InvalidArgument for geo.ErrNotFound, geo.ErrQuota or geo.ErrBadRequest,
+// and Unavailable otherwise.
+func providerError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, geo.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, geo.ErrQuota):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, geo.ErrBadRequest):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Error(codes.Unavailable, err.Error())
+	}
+}
+
+// Returns the location of the requested address.
+func (s *Server) Geocode(ctx context.Context, req *GeocodeRequest) (*GeocodeResponse, error) {
+	if s.Geocoder == nil {
+		return nil, status.Error(codes.Unimplemented, "geocoding is not configured")
+	}
+
+	if req.GetQuery() == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+
+	p, err := s.Geocoder.Geocode(req.GetQuery())
+	if err != nil {
+		return nil, providerError(err)
+	}
+
+	return &GeocodeResponse{Point: FromPoint(p)}, nil
+}
+
+// Returns the address of the requested point.
+func (s *Server) ReverseGeocode(ctx context.Context, req *ReverseGeocodeRequest) (*ReverseGeocodeResponse, error) {
+	if s.Geocoder == nil {
+		return nil, status.Error(codes.Unimplemented, "geocoding is not configured")
+	}
+
+	p, err := pointFromLatLng(req.GetPoint())
+	if err != nil {
+		return nil, err
+	}
+
+	address, err := s.Geocoder.ReverseGeocode(p)
+	if err != nil {
+		return nil, providerError(err)
+	}
+
+	return &ReverseGeocodeResponse{Address: address}, nil
+}
+
+// Returns the places within the requested radius of the requested point, nearest first.
+func (s *Server) Nearby(ctx context.Context, req *NearbyRequest) (*NearbyResponse, error) {
+	if s.Places == nil {
+		return nil, status.Error(codes.Unimplemented, "nearby search is not configured")
+	}
+
+	p, err := pointFromLatLng(req.GetPoint())
+	if err != nil {
+		return nil, err
+	}
+
+	maxRadius := s.MaxRadius
+	if maxRadius <= 0 {
+		maxRadius = DEFAULT_MAX_RADIUS
+	}
+
+	radius := req.GetRadiusKm()
+	if math.IsNaN(radius) || radius < 0 || radius > maxRadius {
+		return nil, status.Errorf(codes.InvalidArgument, "radius_km must be between 0 and %v", maxRadius)
+	}
+
+	found := s.Places.Within(p, radius)
+
+	if limit := int(req.GetLimit()); limit > 0 && len(found) > limit {
+		found = found[:limit]
+	}
+
+	res := &NearbyResponse{Places: make([]*Place, len(found))}
+	for i, f := range found {
+		place, err := placeFromResult(f)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		res.Places[i] = place
+	}
+
+	return res, nil
+}
+
+// Returns the Place describing the passed in search result.
+func placeFromResult(r geo.KDResult[*geo.Feature]) (*Place, error) {
+	place := &Place{Point: FromPoint(r.Point), DistanceKm: r.Distance}
+	if r.Value == nil {
+		return place, nil
+	}
+
+	if r.Value.ID != nil {
+		place.Id = fmt.Sprint(r.Value.ID)
+	}
+
+	// Round trip the properties through JSON so that any value the
+	// GeoJSON encoding accepts can be carried in a Struct.
+	data, err := json.Marshal(r.Value.Properties)
+	if err != nil {
+		return nil, err
+	}
+
+	var properties map[string]interface{}
+	if err := json.Unmarshal(data, &properties); err != nil {
+		return nil, err
+	}
+
+	if place.Properties, err = structpb.NewStruct(properties); err != nil {
+		return nil, err
+	}
+
+	return place, nil
+}
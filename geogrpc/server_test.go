@@ -0,0 +1,117 @@
+package geogrpc
+
+import (
+	"context"
+	"errors"
+	"github.com/kellydunn/golang-geo"
+	"github.com/kellydunn/golang-geo/geotest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"testing"
+)
+
+// Starts the passed in Server on an in-memory listener and returns a client connected to it.
+func dialServer(t *testing.T, s *Server) GeoServiceClient {
+	t.Helper()
+
+	l := bufconn.Listen(1 << 20)
+	gs := grpc.NewServer()
+	RegisterGeoServiceServer(gs, s)
+	go gs.Serve(l)
+	t.Cleanup(gs.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return l.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return NewGeoServiceClient(conn)
+}
+
+// Ensures that Geocode and ReverseGeocode answer from the configured geocoder.
+func TestGeocodeAndReverseGeocode(t *testing.T) {
+	sfo := geo.NewPoint(37.615223, -122.389979)
+	g := geotest.NewMockGeocoder().
+		AddGeocode("sfo", sfo, nil).
+		AddGeocode("down", nil, errors.New("provider unavailable")).
+		AddGeocode("nowhere", nil, &geo.ProviderError{Provider: "google", Kind: geo.ErrNotFound, Status: "ZERO_RESULTS"}).
+		AddReverseGeocode(sfo, "San Francisco International Airport", nil)
+	client := dialServer(t, NewServer(g, nil))
+	ctx := context.Background()
+
+	res, err := client.Geocode(ctx, &GeocodeRequest{Query: "sfo"})
+	if err != nil || res.GetPoint().GetLat() != sfo.Lat() || res.GetPoint().GetLng() != sfo.Lng() {
+		t.Errorf("Expected the airport's location, got %v (%v)", res, err)
+	}
+
+	rev, err := client.ReverseGeocode(ctx, &ReverseGeocodeRequest{Point: &LatLng{Lat: sfo.Lat(), Lng: sfo.Lng()}})
+	if err != nil || rev.GetAddress() != "San Francisco International Airport" {
+		t.Errorf("Expected the airport's address, got %v (%v)", rev, err)
+	}
+
+	if _, err := client.Geocode(ctx, &GeocodeRequest{Query: "down"}); status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected Unavailable for a provider error, got %v", err)
+	}
+
+	if _, err := client.Geocode(ctx, &GeocodeRequest{Query: "nowhere"}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound when the provider finds nothing, got %v", err)
+	}
+
+	if _, err := client.Geocode(ctx, &GeocodeRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument without a query, got %v", err)
+	}
+
+	if _, err := client.ReverseGeocode(ctx, &ReverseGeocodeRequest{Point: &LatLng{Lat: 91}}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for an out of range point, got %v", err)
+	}
+
+	if _, err := client.Nearby(ctx, &NearbyRequest{Point: &LatLng{}}); status.Code(err) != codes.Unimplemented {
+		t.Errorf("Expected Unimplemented for nearby search without places, got %v", err)
+	}
+}
+
+// Ensures that Nearby returns the places within the radius, nearest first and up to the limit.
+func TestNearby(t *testing.T) {
+	places := geo.NewKDTree[*geo.Feature]()
+	far := geo.NewFeature(geo.NewPoint(0, 0.2)).Set("name", "far")
+	near := geo.NewFeature(geo.NewPoint(0, 0.1)).Set("name", "near").Set("rating", 4)
+	near.ID = 7
+	places.Insert(geo.NewPoint(0, 0.2), far)
+	places.Insert(geo.NewPoint(0, 0.1), near)
+	places.Insert(geo.NewPoint(10, 10), geo.NewFeature(geo.NewPoint(10, 10)))
+	places.Build()
+
+	client := dialServer(t, NewServer(nil, places))
+	ctx := context.Background()
+
+	res, err := client.Nearby(ctx, &NearbyRequest{Point: &LatLng{}, RadiusKm: 30})
+	if err != nil || len(res.GetPlaces()) != 2 {
+		t.Fatalf("Expected 2 places, got %v (%v)", res, err)
+	}
+
+	first := res.GetPlaces()[0]
+	properties := first.GetProperties().AsMap()
+	if first.GetId() != "7" || properties["name"] != "near" || properties["rating"] != 4.0 {
+		t.Errorf("Expected the nearest place first with its properties, got %v", first)
+	}
+
+	if first.GetDistanceKm() < 11.1 || first.GetDistanceKm() > 11.2 {
+		t.Errorf("Expected the nearest place to be 11.1km away, got %v", first.GetDistanceKm())
+	}
+
+	res, err = client.Nearby(ctx, &NearbyRequest{Point: &LatLng{}, RadiusKm: 30, Limit: 1})
+	if err != nil || len(res.GetPlaces()) != 1 {
+		t.Errorf("Expected the limit to be applied, got %v (%v)", res, err)
+	}
+
+	if _, err := client.Nearby(ctx, &NearbyRequest{Point: &LatLng{}, RadiusKm: 1000}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for a radius over the maximum, got %v", err)
+	}
+}
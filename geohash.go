@@ -0,0 +1,145 @@
+package geo
+
+import (
+	"errors"
+)
+
+// The alphabet geohashes are written in.
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// The longest geohash precision supported; 12 characters locate a point to within a few centimeters.
+const MAX_GEOHASH_PRECISION = 12
+
+// This is the error that consumers receive when decoding
+// a string that contains characters outside the geohash alphabet.
+var invalidGeohashError = errors.New("invalid geohash")
+
+// Maps each byte to its value in the geohash alphabet, or -1 if it is not part of it.
+var geohashValues = func() [256]int8 {
+	var values [256]int8
+	for i := range values {
+		values[i] = -1
+	}
+
+	for i := 0; i < len(geohashAlphabet); i++ {
+		values[geohashAlphabet[i]] = int8(i)
+	}
+
+	return values
+}()
+
+// Appends the geohash of the passed in coordinates, of the passed in number of characters,
+// to dst and returns the extended slice.  Precision is clamped to between 1 and
+// MAX_GEOHASH_PRECISION.  Never allocates if dst has room for the geohash, so that
+// a buffer can be reused across many calls.
+func AppendGeohash(dst []byte, lat float64, lng float64, precision int) []byte {
+	precision = max(1, min(precision, MAX_GEOHASH_PRECISION))
+
+	minLat, maxLat := -90.0, 90.0
+	minLng, maxLng := -180.0, 180.0
+	lng = NormalizeLng(lng)
+
+	even := true
+	for i := 0; i < precision; i++ {
+		ch := 0
+		for bit := 4; bit >= 0; bit-- {
+			if even {
+				mid := (minLng + maxLng) / 2
+				if lng >= mid {
+					ch |= 1 << uint(bit)
+					minLng = mid
+				} else {
+					maxLng = mid
+				}
+			} else {
+				mid := (minLat + maxLat) / 2
+				if lat >= mid {
+					ch |= 1 << uint(bit)
+					minLat = mid
+				} else {
+					maxLat = mid
+				}
+			}
+			even = !even
+		}
+		dst = append(dst, geohashAlphabet[ch])
+	}
+
+	return dst
+}
+
+// Returns the geohash of the passed in coordinates, of the passed in number of characters.
+func EncodeGeohash(lat float64, lng float64, precision int) string {
+	var buf [MAX_GEOHASH_PRECISION]byte
+	return string(AppendGeohash(buf[:0], lat, lng, precision))
+}
+
+// Returns the geohash of the current Point, of the passed in number of characters.
+func (p *Point) Geohash(precision int) string {
+	return EncodeGeohash(p.lat, p.lng, precision)
+}
+
+// Returns the edges of the cell the passed in geohash names.  Never allocates.
+func DecodeGeohashBounds(hash string) (minLat, minLng, maxLat, maxLng float64, err error) {
+	if hash == "" {
+		return 0, 0, 0, 0, invalidGeohashError
+	}
+
+	minLat, maxLat = -90.0, 90.0
+	minLng, maxLng = -180.0, 180.0
+
+	even := true
+	for i := 0; i < len(hash); i++ {
+		c := hash[i]
+		if c >= 'A' && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+
+		v := geohashValues[c]
+		if v < 0 {
+			return 0, 0, 0, 0, invalidGeohashError
+		}
+
+		for bit := 4; bit >= 0; bit-- {
+			set := v&(1<<uint(bit)) != 0
+			if even {
+				mid := (minLng + maxLng) / 2
+				if set {
+					minLng = mid
+				} else {
+					maxLng = mid
+				}
+			} else {
+				mid := (minLat + maxLat) / 2
+				if set {
+					minLat = mid
+				} else {
+					maxLat = mid
+				}
+			}
+			even = !even
+		}
+	}
+
+	return minLat, minLng, maxLat, maxLng, nil
+}
+
+// Returns the coordinates of the center of the cell the passed in geohash names.  Never allocates.
+func DecodeGeohash(hash string) (float64, float64, error) {
+	minLat, minLng, maxLat, maxLng, err := DecodeGeohashBounds(hash)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return (minLat + maxLat) / 2, (minLng + maxLng) / 2, nil
+}
+
+// Returns the Bounds of the cell the passed in geohash names.
+func GeohashBounds(hash string) (*Bounds, error) {
+	minLat, minLng, maxLat, maxLng, err := DecodeGeohashBounds(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBounds(NewPoint(minLat, minLng), NewPoint(maxLat, maxLng)), nil
+}
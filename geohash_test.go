@@ -0,0 +1,84 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+// Ensures that points are encoded to their well known geohashes.
+func TestEncodeGeohash(t *testing.T) {
+	if hash := EncodeGeohash(42.6, -5.6, 5); hash != "ezs42" {
+		t.Errorf("Expected ezs42, got %s", hash)
+	}
+
+	if hash := NewPoint(37.7749, -122.4194).Geohash(9); hash != "9q8yyk8yt" {
+		t.Errorf("Expected 9q8yyk8yt, got %s", hash)
+	}
+
+	if hash := EncodeGeohash(0, 0, 20); len(hash) != MAX_GEOHASH_PRECISION {
+		t.Errorf("Expected precision to be clamped to %d, got %d", MAX_GEOHASH_PRECISION, len(hash))
+	}
+}
+
+// Ensures that geohashes decode to cells containing the point they were encoded from.
+func TestDecodeGeohash(t *testing.T) {
+	lat, lng, err := DecodeGeohash("ezs42")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if math.Abs(lat-42.605) > 0.01 || math.Abs(lng+5.603) > 0.01 {
+		t.Errorf("Expected about [42.605, -5.603], got [%f, %f]", lat, lng)
+	}
+
+	b, err := GeohashBounds("9Q8YYK8YT")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !b.Contains(NewPoint(37.7749, -122.4194)) {
+		t.Errorf("Expected the cell to contain the encoded point, got %v to %v", b.SouthWest(), b.NorthEast())
+	}
+
+	for _, invalid := range []string{"", "ezs4a", "ezs 42"} {
+		if _, _, err := DecodeGeohash(invalid); err != invalidGeohashError {
+			t.Errorf("Expected %q to be rejected, got %v", invalid, err)
+		}
+	}
+}
+
+// Ensures that the hot paths of tracking services never allocate.
+func TestZeroAllocations(t *testing.T) {
+	sea := &Point{lat: 47.4489, lng: -122.3094}
+	sfo := &Point{lat: 37.6160933, lng: -122.3924223}
+	buf := make([]byte, 0, MAX_GEOHASH_PRECISION)
+
+	cases := map[string]func(){
+		"GreatCircleDistance": func() { sea.GreatCircleDistance(sfo) },
+		"BearingTo":           func() { sea.BearingTo(sfo) },
+		"Destination":         func() { Destination(sea.lat, sea.lng, 100, 90) },
+		"AppendGeohash":       func() { buf = AppendGeohash(buf[:0], sea.lat, sea.lng, 9) },
+		"DecodeGeohash":       func() { DecodeGeohash("c22yzugqw") },
+	}
+
+	for name, f := range cases {
+		if allocs := testing.AllocsPerRun(100, f); allocs != 0 {
+			t.Errorf("Expected %s not to allocate, got %v allocations per run", name, allocs)
+		}
+	}
+}
+
+func BenchmarkAppendGeohash(b *testing.B) {
+	buf := make([]byte, 0, MAX_GEOHASH_PRECISION)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = AppendGeohash(buf[:0], 47.4489, -122.3094, 9)
+	}
+}
+
+func BenchmarkDecodeGeohash(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		DecodeGeohash("c22yzugqw")
+	}
+}
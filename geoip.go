@@ -0,0 +1,140 @@
+package geo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// This contains the default base URL for the ipinfo.io API.
+const DEFAULT_IPINFO_URL = "https://ipinfo.io"
+
+// This is the error that consumers receive when asked to locate a private,
+// loopback or otherwise reserved IP address, which has no place on the map.
+var ErrPrivateIP = errors.New("IP address is private or reserved")
+
+// The approximate location of an IP address: usually the city its network is registered in.
+type IPLocation struct {
+	IP    net.IP
+	Point *Point
+
+	City   string
+	Region string
+
+	// The ISO 3166-1 alpha-2 code of the country, e.g. "US".
+	CountryCode string
+}
+
+// An IPLocator looks up the approximate location of an IP address.
+type IPLocator interface {
+	LocateIP(ip net.IP) (*IPLocation, error)
+}
+
+// Returns whether or not the passed in address can't be located, being private,
+// loopback, link local, multicast or unspecified.
+func unroutableIP(ip net.IP) bool {
+	return ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsMulticast() || ip.IsUnspecified()
+}
+
+// This struct contains all the functionality
+// of interacting with the ipinfo.io API.
+type IPInfoProvider struct {
+	// If set, every request is counted against this Quota
+	// and refused once the "ipinfo" daily budget is spent.
+	Quota *Quota
+
+	// The client used to issue requests.  Defaults to a client sharing its connections
+	// with every provider, tuned with SetTransportConfig.
+	HTTPClient *http.Client
+
+	// The base URL of the API.  Defaults to DEFAULT_IPINFO_URL.
+	BaseURL string
+
+	apiKey string
+}
+
+// Creates and returns a pointer to a new IPInfoProvider configured by the passed in options.
+// ipinfo.io makes use of WithAPIKey, WithHTTPClient, WithBaseURL and WithQuota.
+// Without an API key, requests are subject to ipinfo.io's anonymous rate limit.
+func NewIPInfoProvider(opts ...Option) *IPInfoProvider {
+	c := newGeocoderConfig(opts)
+	return &IPInfoProvider{
+		Quota:      c.quota,
+		HTTPClient: c.client("ipinfo"),
+		BaseURL:    c.baseURL,
+		apiKey:     c.apiKey,
+	}
+}
+
+// This struct contains selected fields from ipinfo.io's response.
+type ipInfoResponse struct {
+	City    string `json:"city"`
+	Region  string `json:"region"`
+	Country string `json:"country"`
+	Loc     string `json:"loc"`
+	Bogon   bool   `json:"bogon"`
+	Error   *struct {
+		Title   string `json:"title"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Returns the approximate location of the passed in IP address.
+// Returns ErrPrivateIP, without making a request, for addresses that can't be located.
+// Implements the IPLocator Interface.
+func (i *IPInfoProvider) LocateIP(ip net.IP) (*IPLocation, error) {
+	if unroutableIP(ip) {
+		return nil, ErrPrivateIP
+	}
+
+	if i.Quota != nil {
+		if err := i.Quota.Spend("ipinfo"); err != nil {
+			return nil, err
+		}
+	}
+
+	base := i.BaseURL
+	if base == "" {
+		base = DEFAULT_IPINFO_URL
+	}
+
+	u := base + "/" + url.PathEscape(ip.String()) + "/json"
+	if i.apiKey != "" {
+		u += "?" + url.Values{"token": {i.apiKey}}.Encode()
+	}
+
+	data, err := httpGet(i.HTTPClient, u)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ipInfoResponse{}
+	if err := json.Unmarshal(data, res); err != nil {
+		return nil, err
+	}
+
+	if res.Error != nil {
+		return nil, fmt.Errorf("ipinfo: %s: %s", res.Error.Title, res.Error.Message)
+	}
+
+	if res.Bogon {
+		return nil, ErrPrivateIP
+	}
+
+	lat, lng, ok := strings.Cut(res.Loc, ",")
+	if !ok {
+		return nil, fmt.Errorf("ipinfo: no location for %s", ip)
+	}
+
+	location := &IPLocation{IP: ip, City: res.City, Region: res.Region, CountryCode: NormalizeCountryCode(res.Country)}
+	if location.Point, err = parseLatLng(lat, lng); err != nil {
+		return nil, fmt.Errorf("ipinfo: %v", err)
+	}
+
+	return location, nil
+}
@@ -0,0 +1,78 @@
+package geo
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Ensures that ipinfo.io responses are read, and that the token and address are sent.
+func TestIPInfoProvider(t *testing.T) {
+	var paths, tokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		tokens = append(tokens, r.URL.Query().Get("token"))
+		w.Write([]byte(`{
+			"ip": "8.8.8.8",
+			"hostname": "dns.google",
+			"city": "Mountain View",
+			"region": "California",
+			"country": "US",
+			"loc": "37.4056,-122.0775",
+			"postal": "94043",
+			"timezone": "America/Los_Angeles"
+		}`))
+	}))
+	defer server.Close()
+
+	i := NewIPInfoProvider(WithBaseURL(server.URL), WithAPIKey("token"))
+	location, err := i.LocateIP(net.ParseIP("8.8.8.8"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if location.City != "Mountain View" || location.Region != "California" || location.CountryCode != "US" {
+		t.Errorf("Unexpected location: %+v", location)
+	}
+
+	if location.Point.Lat() != 37.4056 || location.Point.Lng() != -122.0775 {
+		t.Errorf("Expected 37.4056, -122.0775, got %v", location.Point)
+	}
+
+	if paths[0] != "/8.8.8.8/json" || tokens[0] != "token" {
+		t.Errorf("Unexpected request: %s?token=%s", paths[0], tokens[0])
+	}
+}
+
+// Ensures that private addresses are refused without a request, and that errors are reported.
+func TestIPInfoProviderErrors(t *testing.T) {
+	requests := 0
+	body := `{"ip": "192.0.2.1", "bogon": true}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	i := NewIPInfoProvider(WithBaseURL(server.URL))
+	for _, ip := range []string{"10.1.2.3", "127.0.0.1", "::1", "fe80::1", "192.168.0.1"} {
+		if _, err := i.LocateIP(net.ParseIP(ip)); err != ErrPrivateIP {
+			t.Errorf("Expected ErrPrivateIP for %s, got %v", ip, err)
+		}
+	}
+
+	if requests != 0 {
+		t.Errorf("Expected no requests for private addresses, got %d", requests)
+	}
+
+	if _, err := i.LocateIP(net.ParseIP("192.0.2.1")); !errors.Is(err, ErrPrivateIP) {
+		t.Errorf("Expected ErrPrivateIP for a bogon, got %v", err)
+	}
+
+	body = `{"error": {"title": "Wrong ip", "message": "Please provide a valid IP address"}}`
+	if _, err := i.LocateIP(net.ParseIP("8.8.8.8")); err == nil {
+		t.Error("Expected an error for an error response")
+	}
+}
@@ -0,0 +1,154 @@
+package geo
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"math"
+)
+
+// The points along each edge, besides its ends, at which EqualWithin measures how far it strays from the other geometry.
+const EQUAL_WITHIN_SAMPLES = 3
+
+// Returns a stable hash of the passed in geometry, a *Point, a Line or a *Polygon, with its coordinates rounded
+// to the passed in number of decimal places, or taken exactly if it is FULL_PRECISION, e.g. for deduplicating
+// geometries in an ETL pipeline or keying a cache.  Geometries that differ only in how they were written hash alike:
+// lines run in either direction, rings starting at any of their points, wound either way and with or without
+// their closing point, points repeated one after another, and longitudes of 180 and -180.
+// Geometries nearer each other than the precision may still round apart, so pair a hash with EqualWithin
+// where near misses matter.  Returns the empty string for geometries of other types and nil ones.
+func GeomHash(g Geometry, precision int) string {
+	var points []*Point
+	switch g := g.(type) {
+	case *Point:
+		if g != nil {
+			points = canonicalPoints([]*Point{g}, precision)
+		}
+	case Line:
+		points = canonicalPoints(g, precision)
+		reversed := make([]*Point, len(points))
+		for i, p := range points {
+			reversed[len(points)-1-i] = p
+		}
+		if comparePoints(reversed, points) < 0 {
+			points = reversed
+		}
+	case *Polygon:
+		if g == nil {
+			return ""
+		}
+		ring := NewPolygon(canonicalPoints(g.points, precision))
+		ring.Normalize()
+		points = ring.points
+
+		start := 0
+		for i, p := range points {
+			if comparePoints([]*Point{p}, []*Point{points[start]}) < 0 {
+				start = i
+			}
+		}
+		points = append(points[start:len(points):len(points)], points[:start]...)
+	default:
+		return ""
+	}
+
+	key := []byte(g.GeometryType())
+	for _, p := range points {
+		key = append(key, ' ')
+		key = appendCoordinatePrecision(key, p.lng, precision)
+		key = append(key, ' ')
+		key = appendCoordinatePrecision(key, p.lat, precision)
+	}
+
+	sum := sha1.Sum(key)
+	return hex.EncodeToString(sum[:])
+}
+
+// Returns copies of the passed in points with their coordinates rounded to the passed in number of decimal places,
+// unless it is negative, and a longitude of 180 taken as -180, dropping nil points and points repeating the one before.
+func canonicalPoints(points []*Point, precision int) []*Point {
+	round := func(f float64) float64 {
+		if precision < 0 {
+			return f
+		}
+
+		scale := math.Pow(10, float64(precision))
+		if f = math.Round(f*scale) / scale; f == 0 {
+			// Negative zeros are written as positive ones, but compare unequal bit for bit.
+			return 0
+		}
+		return f
+	}
+
+	var canonical []*Point
+	for _, p := range points {
+		if p == nil {
+			continue
+		}
+
+		q := &Point{lat: round(p.lat), lng: round(NormalizeLng(p.lng))}
+		if q.lng == 180 {
+			q.lng = -180
+		}
+		if len(canonical) == 0 || *q != *canonical[len(canonical)-1] {
+			canonical = append(canonical, q)
+		}
+	}
+
+	return canonical
+}
+
+// Compares the passed in sequences of points by longitude, then latitude, point by point,
+// returning a negative number if a comes first, a positive one if b does, and zero if they are equal.
+func comparePoints(a, b []*Point) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		switch {
+		case a[i].lng != b[i].lng:
+			return int(math.Copysign(1, a[i].lng-b[i].lng))
+		case a[i].lat != b[i].lat:
+			return int(math.Copysign(1, a[i].lat-b[i].lat))
+		}
+	}
+
+	return len(a) - len(b)
+}
+
+// Returns whether or not the passed in geometries, each a *Point, a Line or a *Polygon, are the same shape
+// give or take the passed in number of meters: whether they are points, lines or polygons alike and no point
+// of either lies further than that from the other, along great circles.  As with GeomHash, lines may run
+// in either direction and rings may start anywhere and be wound either way.  The distance from one geometry
+// to the other is measured at its vertices and at EQUAL_WITHIN_SAMPLES points along each edge between them.
+// Empty geometries are only equal to each other.
+func EqualWithin(a, b Geometry, meters float64) bool {
+	ra, rb := newRelateGeometry(a), newRelateGeometry(b)
+	if ra.dim != rb.dim {
+		return false
+	}
+
+	return ra.dim < 0 || (strayWithin(ra, rb, meters/1000) && strayWithin(rb, ra, meters/1000))
+}
+
+// Returns whether or not every point of the passed in geometry, as sampled by EqualWithin,
+// lies within the passed in number of kilometers of the other.
+func strayWithin(g, other *relateGeometry, km float64) bool {
+	// A millimeter's slack absorbs rounding, so that a geometry is equal to itself with no tolerance.
+	within := func(p *Point) bool {
+		return geometryDistance(&relateGeometry{dim: 0, points: []*Point{p}}, other) <= km+1e-6
+	}
+
+	if g.dim == 0 {
+		return within(g.points[0])
+	}
+
+	for _, s := range g.segments() {
+		if !within(s[0]) || !within(s[1]) {
+			return false
+		}
+		for i := 1; i <= EQUAL_WITHIN_SAMPLES; i++ {
+			if !within(intermediatePoint(s[0], s[1], float64(i)/(EQUAL_WITHIN_SAMPLES+1))) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,89 @@
+package geo
+
+import (
+	"testing"
+)
+
+// Ensures that geometries differing only in how they were written hash alike, and others don't.
+func TestGeomHash(t *testing.T) {
+	square := boxPolygon(0, 0, 1, 1)
+	same := []struct {
+		name string
+		a, b Geometry
+	}{
+		{"rounded points", NewPoint(37.6152231, -122.3899791), NewPoint(37.6152229, -122.3899789)},
+		{"the antimeridian", NewPoint(10, 180), NewPoint(10, -180)},
+		{"negative zero", NewPoint(-0.0000001, 1), NewPoint(0, 1)},
+		{"reversed lines", lineOf(0, 0, 1, 1, 2, 0), lineOf(2, 0, 1, 1, 0, 0)},
+		{"repeated points", lineOf(0, 0, 1, 1, 1, 1, 2, 0), lineOf(0, 0, 1, 1, 2, 0)},
+		{"rotated rings", square, NewPolygon([]*Point{NewPoint(1, 1), NewPoint(0, 1), NewPoint(0, 0), NewPoint(1, 0)})},
+		{"reversed rings", square, NewPolygon([]*Point{NewPoint(0, 0), NewPoint(0, 1), NewPoint(1, 1), NewPoint(1, 0)})},
+		{"closed rings", square, NewPolygon(append(square.Points(), NewPoint(0, 0)))},
+	}
+
+	for _, c := range same {
+		if GeomHash(c.a, 6) != GeomHash(c.b, 6) {
+			t.Errorf("Expected %s to hash alike", c.name)
+		}
+	}
+
+	different := []struct {
+		name string
+		a, b Geometry
+	}{
+		{"distant points", NewPoint(37.615223, -122.389979), NewPoint(37.615233, -122.389979)},
+		{"a point and a line of it", NewPoint(1, 1), lineOf(1, 1)},
+		{"lines through points in another order", lineOf(0, 0, 1, 1, 2, 0), lineOf(1, 1, 0, 0, 2, 0)},
+		{"a ring and a closed line", square, Line(append(square.Points(), NewPoint(0, 0)))},
+	}
+
+	for _, c := range different {
+		if GeomHash(c.a, 6) == GeomHash(c.b, 6) {
+			t.Errorf("Expected %s to hash differently", c.name)
+		}
+	}
+
+	// Hashes are stable across releases, for use as keys in caches that outlive them.
+	expected := "00b983901605702109d7d1e4948743ef6a97e9f8"
+	if hash := GeomHash(NewPoint(37.615223, -122.389979), 6); hash != expected {
+		t.Errorf("Expected %s, got %s", expected, hash)
+	}
+
+	if GeomHash(nil, 6) != "" || GeomHash((*Polygon)(nil), 6) != "" {
+		t.Error("Expected nil geometries to have no hash")
+	}
+}
+
+// Ensures that geometries are equal when no part of either strays further than the tolerance from the other.
+func TestEqualWithin(t *testing.T) {
+	square := boxPolygon(0, 0, 0.01, 0.01)
+	cases := []struct {
+		name     string
+		a, b     Geometry
+		meters   float64
+		expected bool
+	}{
+		{"equal points", NewPoint(1, 1), NewPoint(1, 1), 0, true},
+		{"points 11m apart", NewPoint(0, 0), NewPoint(0, 0.0001), 10, false},
+		{"points 11m apart within 12m", NewPoint(0, 0), NewPoint(0, 0.0001), 12, true},
+		{"reversed lines", lineOf(0, 0, 0, 0.01), lineOf(0, 0.01, 0, 0), 0, true},
+		{"lines with an extra vertex along them", lineOf(0, 0, 0, 0.01), lineOf(0, 0, 0, 0.005, 0, 0.01), 0.01, true},
+		{"lines with a detour", lineOf(0, 0, 0, 0.01), lineOf(0, 0, 0.001, 0.005, 0, 0.01), 100, false},
+		{"lines with a detour within 120m", lineOf(0, 0, 0, 0.01), lineOf(0, 0, 0.001, 0.005, 0, 0.01), 120, true},
+		{"a line and part of it", lineOf(0, 0, 0, 0.01), lineOf(0, 0, 0, 0.005), 100, false},
+		{"rotated and reversed rings", square, NewPolygon([]*Point{NewPoint(0.01, 0), NewPoint(0, 0), NewPoint(0, 0.01), NewPoint(0.01, 0.01)}), 0, true},
+		{"shifted rings", square, boxPolygon(0.00005, 0, 0.01005, 0.01), 10, true},
+		{"a ring and a closed line", square, Line(append(square.Points(), NewPoint(0, 0))), 1000, false},
+		{"empty geometries", Line{}, (*Polygon)(nil), 0, true},
+		{"an empty geometry", Line{}, NewPoint(0, 0), 1e9, false},
+	}
+
+	for _, c := range cases {
+		if got := EqualWithin(c.a, c.b, c.meters); got != c.expected {
+			t.Errorf("Expected EqualWithin to be %t for %s, got %t", c.expected, c.name, got)
+		}
+		if got := EqualWithin(c.b, c.a, c.meters); got != c.expected {
+			t.Errorf("Expected EqualWithin to be %t for %s the other way around, got %t", c.expected, c.name, got)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package geoparquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// The compression codecs of Parquet pages.
+type Codec int32
+
+const (
+	Uncompressed Codec = 0
+	Snappy       Codec = 1
+	Gzip         Codec = 2
+	Zstd         Codec = 6
+)
+
+// Returns the name of the Codec.
+func (c Codec) String() string {
+	switch c {
+	case Uncompressed:
+		return "UNCOMPRESSED"
+	case Snappy:
+		return "SNAPPY"
+	case Gzip:
+		return "GZIP"
+	case Zstd:
+		return "ZSTD"
+	default:
+		return fmt.Sprintf("codec %d", int32(c))
+	}
+}
+
+// Returns the passed in page data compressed with the passed in codec, which must be Uncompressed or Gzip.
+func compress(codec Codec, data []byte) ([]byte, error) {
+	switch codec {
+	case Uncompressed:
+		return data, nil
+	case Gzip:
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(data); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("geoparquet: can't write %s pages", codec)
+	}
+}
+
+// Returns the passed in page data decompressed with the passed in codec, which is uncompressed to
+// the passed in size.
+func decompress(codec Codec, data []byte, size int) ([]byte, error) {
+	switch codec {
+	case Uncompressed:
+		return data, nil
+	case Snappy:
+		return decodeSnappy(data)
+	case Gzip:
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if _, err := io.Copy(&buf, io.LimitReader(zr, int64(size)+1)); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("geoparquet: can't read %s pages", codec)
+	}
+}
+
+var errSnappy = errors.New("geoparquet: invalid snappy data")
+
+// Decodes a block of Snappy-compressed data, the format Parquet's SNAPPY pages are in:
+// its uncompressed length, then literals and copies of earlier output.
+func decodeSnappy(src []byte) ([]byte, error) {
+	n, read := binary.Uvarint(src)
+	if read <= 0 || n > math.MaxInt32 || n > uint64(len(src))*64 {
+		return nil, errSnappy
+	}
+	src = src[read:]
+
+	dst := make([]byte, 0, n)
+	for len(src) > 0 {
+		tag := src[0]
+		src = src[1:]
+
+		var length, offset int
+		switch tag & 3 {
+		case 0:
+			length = int(tag>>2) + 1
+			if length > 60 {
+				// Long literals' lengths follow in 1 to 4 bytes.
+				size := length - 60
+				if len(src) < size {
+					return nil, errSnappy
+				}
+				length = 1
+				for i := 0; i < size; i++ {
+					length += int(src[i]) << (8 * i)
+				}
+				src = src[size:]
+			}
+			if length <= 0 || length > len(src) || len(dst)+length > int(n) {
+				return nil, errSnappy
+			}
+			dst = append(dst, src[:length]...)
+			src = src[length:]
+			continue
+		case 1:
+			if len(src) < 1 {
+				return nil, errSnappy
+			}
+			length = 4 + int(tag>>2)&7
+			offset = int(tag>>5)<<8 | int(src[0])
+			src = src[1:]
+		case 2:
+			if len(src) < 2 {
+				return nil, errSnappy
+			}
+			length = 1 + int(tag>>2)
+			offset = int(binary.LittleEndian.Uint16(src))
+			src = src[2:]
+		case 3:
+			if len(src) < 4 {
+				return nil, errSnappy
+			}
+			length = 1 + int(tag>>2)
+			offset = int(binary.LittleEndian.Uint32(src))
+			src = src[4:]
+		}
+
+		if offset <= 0 || offset > len(dst) || len(dst)+length > int(n) {
+			return nil, errSnappy
+		}
+		// Copies may overlap their own output, repeating it.
+		for i := 0; i < length; i++ {
+			dst = append(dst, dst[len(dst)-offset])
+		}
+	}
+
+	if len(dst) != int(n) {
+		return nil, errSnappy
+	}
+
+	return dst, nil
+}
+
+// Appends the passed in levels, or dictionary indices, of the passed in bit width to dst with
+// Parquet's hybrid of run length encoding and bit packing, as runs of repeated values.
+func appendRLE(dst []byte, values []int32, width int) []byte {
+	size := (width + 7) / 8
+	for i := 0; i < len(values); {
+		j := i + 1
+		for j < len(values) && values[j] == values[i] {
+			j++
+		}
+
+		dst = binary.AppendUvarint(dst, uint64(j-i)<<1)
+		for b := 0; b < size; b++ {
+			dst = append(dst, byte(values[i]>>(8*b)))
+		}
+		i = j
+	}
+
+	return dst
+}
+
+// Decodes n levels, or dictionary indices, of the passed in bit width encoded with Parquet's hybrid
+// of run length encoding and bit packing.
+func decodeRLE(data []byte, width int, n int) ([]int32, error) {
+	if width < 0 || width > 32 {
+		return nil, fmt.Errorf("geoparquet: invalid bit width %d", width)
+	}
+
+	values := make([]int32, 0, n)
+	size := (width + 7) / 8
+	for len(values) < n {
+		header, read := binary.Uvarint(data)
+		if read <= 0 {
+			return nil, errors.New("geoparquet: truncated levels")
+		}
+		data = data[read:]
+
+		if header&1 == 0 {
+			count := header >> 1
+			if len(data) < size || count > uint64(n-len(values)) {
+				return nil, errors.New("geoparquet: invalid levels run")
+			}
+			var v int32
+			for b := 0; b < size; b++ {
+				v |= int32(data[b]) << (8 * b)
+			}
+			data = data[size:]
+			for ; count > 0; count-- {
+				values = append(values, v)
+			}
+			continue
+		}
+
+		// Bit packed runs hold groups of eight values, least significant bits first.
+		groups := header >> 1
+		if groups > uint64(len(data)) || int(groups)*width > len(data) {
+			return nil, errors.New("geoparquet: invalid levels run")
+		}
+		packed := data[:int(groups)*width]
+		data = data[int(groups)*width:]
+		for i := 0; i < int(groups)*8 && len(values) < n; i++ {
+			var v int32
+			for b := 0; b < width; b++ {
+				bit := i*width + b
+				v |= int32(packed[bit/8]>>(bit%8)&1) << b
+			}
+			values = append(values, v)
+		}
+	}
+
+	return values, nil
+}
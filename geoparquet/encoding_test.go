@@ -0,0 +1,39 @@
+package geoparquet
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Ensures that Snappy literals and overlapping copies are decoded.
+func TestDecodeSnappy(t *testing.T) {
+	// "abc" as a literal, then a copy of 9 bytes from 3 bytes back.
+	data, err := decodeSnappy([]byte{12, 0x08, 'a', 'b', 'c', 0x15, 0x03})
+	if err != nil || string(data) != "abcabcabcabc" {
+		t.Errorf("Expected abcabcabcabc, got %q (%v)", data, err)
+	}
+
+	for _, invalid := range [][]byte{{}, {12, 0x08, 'a'}, {4, 0x15, 0x03}, {2, 0x08, 'a', 'b', 'c'}} {
+		if _, err := decodeSnappy(invalid); err == nil {
+			t.Errorf("Expected an error for %v", invalid)
+		}
+	}
+}
+
+// Ensures that levels are encoded as runs, and decoded from runs and bit packed groups.
+func TestRLE(t *testing.T) {
+	levels := []int32{1, 1, 1, 0, 1}
+	if decoded, err := decodeRLE(appendRLE(nil, levels, 1), 1, len(levels)); err != nil || !reflect.DeepEqual(decoded, levels) {
+		t.Errorf("Expected %v, got %v (%v)", levels, decoded, err)
+	}
+
+	// One bit packed group of eight values, of which five are wanted.
+	expected := []int32{1, 0, 1, 0, 0}
+	if decoded, err := decodeRLE([]byte{0x03, 0x05}, 1, 5); err != nil || !reflect.DeepEqual(decoded, expected) {
+		t.Errorf("Expected %v, got %v (%v)", expected, decoded, err)
+	}
+
+	if _, err := decodeRLE([]byte{0x03}, 1, 5); err == nil {
+		t.Error("Expected an error for a truncated group")
+	}
+}
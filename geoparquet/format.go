@@ -0,0 +1,383 @@
+package geoparquet
+
+// Parquet's physical types.
+const (
+	typeBoolean           = 0
+	typeInt32             = 1
+	typeInt64             = 2
+	typeInt96             = 3
+	typeFloat             = 4
+	typeDouble            = 5
+	typeByteArray         = 6
+	typeFixedLenByteArray = 7
+)
+
+// Whether a Parquet column's values are required, optional or repeated.
+const (
+	repetitionRequired = 0
+	repetitionOptional = 1
+	repetitionRepeated = 2
+)
+
+// The converted types, and the members of the logical type union, marking byte arrays as text or JSON.
+const (
+	convertedUTF8 = 0
+	convertedEnum = 4
+	convertedJSON = 19
+	logicalString = 1
+	logicalEnum   = 4
+	logicalJSON   = 12
+)
+
+// Parquet's encodings of values and levels.
+const (
+	encodingPlain           = 0
+	encodingPlainDictionary = 2
+	encodingRLE             = 3
+	encodingRLEDictionary   = 8
+)
+
+// The types of Parquet pages.
+const (
+	pageData       = 0
+	pageDictionary = 2
+	pageDataV2     = 3
+)
+
+// A node of a Parquet file's schema: a column, or a group of them.
+type schemaElement struct {
+	typ         int32
+	typeLength  int32
+	repetition  int32
+	name        string
+	numChildren int32
+	converted   int32
+	logical     int16
+}
+
+// A key and value of a Parquet file's metadata.
+type keyValue struct {
+	key   string
+	value string
+}
+
+// Where a column's pages lie within a row group, and how they are encoded.
+type columnMetaData struct {
+	typ                  int32
+	encodings            []int32
+	path                 []string
+	codec                Codec
+	numValues            int64
+	totalUncompressed    int64
+	totalCompressed      int64
+	dataPageOffset       int64
+	dictionaryPageOffset int64
+}
+
+type rowGroup struct {
+	columns       []columnMetaData
+	totalByteSize int64
+	numRows       int64
+}
+
+// The footer of a Parquet file.
+type fileMetaData struct {
+	version   int32
+	schema    []schemaElement
+	numRows   int64
+	rowGroups []rowGroup
+	keyValues []keyValue
+	createdBy string
+}
+
+// The header of a Parquet page, along with those of its type.
+type pageHeader struct {
+	typ              int32
+	uncompressedSize int32
+	compressedSize   int32
+
+	// For data and dictionary pages.
+	numValues int32
+	encoding  int32
+
+	// For version 2 data pages, whose levels are never compressed.
+	defLevelsLength int32
+	repLevelsLength int32
+	isCompressed    bool
+}
+
+func (m *fileMetaData) write(w *thriftWriter) {
+	w.begin()
+	w.i32(1, m.version)
+
+	w.list(2, thriftStruct, len(m.schema))
+	for _, e := range m.schema {
+		w.begin()
+		if e.numChildren == 0 {
+			w.i32(1, e.typ)
+			w.i32(3, e.repetition)
+		}
+		w.binary(4, e.name)
+		if e.numChildren > 0 {
+			w.i32(5, e.numChildren)
+		}
+		if e.converted >= 0 {
+			w.i32(6, e.converted)
+		}
+		if e.logical != 0 {
+			w.structField(10)
+			w.structField(e.logical)
+			w.end()
+			w.end()
+		}
+		w.end()
+	}
+
+	w.i64(3, m.numRows)
+
+	w.list(4, thriftStruct, len(m.rowGroups))
+	for _, g := range m.rowGroups {
+		w.begin()
+		w.list(1, thriftStruct, len(g.columns))
+		for _, c := range g.columns {
+			w.begin()
+			w.i64(2, c.dataPageOffset)
+			w.structField(3)
+			w.i32(1, c.typ)
+			w.list(2, thriftI32, len(c.encodings))
+			for _, e := range c.encodings {
+				w.i32Element(e)
+			}
+			w.list(3, thriftBinary, len(c.path))
+			for _, name := range c.path {
+				w.binaryElement(name)
+			}
+			w.i32(4, int32(c.codec))
+			w.i64(5, c.numValues)
+			w.i64(6, c.totalUncompressed)
+			w.i64(7, c.totalCompressed)
+			w.i64(9, c.dataPageOffset)
+			w.end()
+			w.end()
+		}
+		w.i64(2, g.totalByteSize)
+		w.i64(3, g.numRows)
+		w.end()
+	}
+
+	w.list(5, thriftStruct, len(m.keyValues))
+	for _, kv := range m.keyValues {
+		w.begin()
+		w.binary(1, kv.key)
+		w.binary(2, kv.value)
+		w.end()
+	}
+
+	w.binary(6, m.createdBy)
+	w.end()
+}
+
+func readFileMetaData(r *thriftReader) *fileMetaData {
+	m := &fileMetaData{}
+	r.readStruct(func(id int16, kind byte) bool {
+		switch {
+		case id == 1 && kind == thriftI32:
+			m.version = r.i32()
+		case id == 2 && kind == thriftList:
+			r.readList(func(byte) { m.schema = append(m.schema, readSchemaElement(r)) })
+		case id == 3 && kind == thriftI64:
+			m.numRows = r.i64()
+		case id == 4 && kind == thriftList:
+			r.readList(func(byte) { m.rowGroups = append(m.rowGroups, readRowGroup(r)) })
+		case id == 5 && kind == thriftList:
+			r.readList(func(byte) { m.keyValues = append(m.keyValues, readKeyValue(r)) })
+		case id == 6 && kind == thriftBinary:
+			m.createdBy = r.binary()
+		default:
+			return false
+		}
+		return true
+	})
+
+	return m
+}
+
+func readSchemaElement(r *thriftReader) schemaElement {
+	e := schemaElement{typ: -1, converted: -1}
+	r.readStruct(func(id int16, kind byte) bool {
+		switch {
+		case id == 1 && kind == thriftI32:
+			e.typ = r.i32()
+		case id == 2 && kind == thriftI32:
+			e.typeLength = r.i32()
+		case id == 3 && kind == thriftI32:
+			e.repetition = r.i32()
+		case id == 4 && kind == thriftBinary:
+			e.name = r.binary()
+		case id == 5 && kind == thriftI32:
+			e.numChildren = r.i32()
+		case id == 6 && kind == thriftI32:
+			e.converted = r.i32()
+		case id == 10 && kind == thriftStruct:
+			// The logical type is a union, whose only field says which type it is.
+			r.readStruct(func(id int16, kind byte) bool {
+				e.logical = id
+				return false
+			})
+		default:
+			return false
+		}
+		return true
+	})
+
+	return e
+}
+
+func readRowGroup(r *thriftReader) rowGroup {
+	var g rowGroup
+	r.readStruct(func(id int16, kind byte) bool {
+		switch {
+		case id == 1 && kind == thriftList:
+			r.readList(func(byte) { g.columns = append(g.columns, readColumnChunk(r)) })
+		case id == 2 && kind == thriftI64:
+			g.totalByteSize = r.i64()
+		case id == 3 && kind == thriftI64:
+			g.numRows = r.i64()
+		default:
+			return false
+		}
+		return true
+	})
+
+	return g
+}
+
+// Reads a column chunk, returning its metadata.  Chunks whose metadata lies elsewhere in the file are refused.
+func readColumnChunk(r *thriftReader) columnMetaData {
+	c := columnMetaData{dictionaryPageOffset: -1}
+	found := false
+	r.readStruct(func(id int16, kind byte) bool {
+		if id != 3 || kind != thriftStruct {
+			return false
+		}
+
+		found = true
+		r.readStruct(func(id int16, kind byte) bool {
+			switch {
+			case id == 1 && kind == thriftI32:
+				c.typ = r.i32()
+			case id == 2 && kind == thriftList:
+				r.readList(func(byte) { c.encodings = append(c.encodings, r.i32()) })
+			case id == 3 && kind == thriftList:
+				r.readList(func(byte) { c.path = append(c.path, r.binary()) })
+			case id == 4 && kind == thriftI32:
+				c.codec = Codec(r.i32())
+			case id == 5 && kind == thriftI64:
+				c.numValues = r.i64()
+			case id == 6 && kind == thriftI64:
+				c.totalUncompressed = r.i64()
+			case id == 7 && kind == thriftI64:
+				c.totalCompressed = r.i64()
+			case id == 9 && kind == thriftI64:
+				c.dataPageOffset = r.i64()
+			case id == 11 && kind == thriftI64:
+				c.dictionaryPageOffset = r.i64()
+			default:
+				return false
+			}
+			return true
+		})
+		return true
+	})
+
+	if !found {
+		r.fail("column chunk without metadata")
+	}
+
+	return c
+}
+
+func readKeyValue(r *thriftReader) keyValue {
+	var kv keyValue
+	r.readStruct(func(id int16, kind byte) bool {
+		switch {
+		case id == 1 && kind == thriftBinary:
+			kv.key = r.binary()
+		case id == 2 && kind == thriftBinary:
+			kv.value = r.binary()
+		default:
+			return false
+		}
+		return true
+	})
+
+	return kv
+}
+
+func (h *pageHeader) write(w *thriftWriter) {
+	w.begin()
+	w.i32(1, h.typ)
+	w.i32(2, h.uncompressedSize)
+	w.i32(3, h.compressedSize)
+	w.structField(5)
+	w.i32(1, h.numValues)
+	w.i32(2, h.encoding)
+	w.i32(3, encodingRLE)
+	w.i32(4, encodingRLE)
+	w.end()
+	w.end()
+}
+
+func readPageHeader(r *thriftReader) *pageHeader {
+	h := &pageHeader{isCompressed: true}
+	r.readStruct(func(id int16, kind byte) bool {
+		switch {
+		case id == 1 && kind == thriftI32:
+			h.typ = r.i32()
+		case id == 2 && kind == thriftI32:
+			h.uncompressedSize = r.i32()
+		case id == 3 && kind == thriftI32:
+			h.compressedSize = r.i32()
+		case (id == 5 || id == 7) && kind == thriftStruct:
+			// Data and dictionary page headers both start with their number of values and encoding.
+			r.readStruct(func(id int16, kind byte) bool {
+				switch {
+				case id == 1 && kind == thriftI32:
+					h.numValues = r.i32()
+				case id == 2 && kind == thriftI32:
+					h.encoding = r.i32()
+				default:
+					return false
+				}
+				return true
+			})
+		case id == 8 && kind == thriftStruct:
+			r.readStruct(func(id int16, kind byte) bool {
+				switch {
+				case id == 1 && kind == thriftI32:
+					h.numValues = r.i32()
+				case id == 4 && kind == thriftI32:
+					h.encoding = r.i32()
+				case id == 5 && kind == thriftI32:
+					h.defLevelsLength = r.i32()
+				case id == 6 && kind == thriftI32:
+					h.repLevelsLength = r.i32()
+				case id == 7 && (kind == thriftTrue || kind == thriftFalse):
+					h.isCompressed = kind == thriftTrue
+				default:
+					return false
+				}
+				return true
+			})
+		default:
+			return false
+		}
+		return true
+	})
+
+	if h.compressedSize < 0 || h.uncompressedSize < 0 {
+		r.fail("negative page size")
+	}
+
+	return h
+}
@@ -0,0 +1,67 @@
+// Package geoparquet reads and writes GeoParquet, the Parquet files of geometries that data lake tooling
+// such as DuckDB, GeoPandas, Apache Sedona and BigQuery exchange: a Writer writes geo.Features as rows whose
+// geometry is a WKB column and whose properties are columns of their own, and a Reader reads them back
+// one row group at a time, so that files larger than memory can be streamed.
+//
+//	w, err := geoparquet.NewWriter(f, geo.PropertySchema{"name": {Type: geo.StringProperty}})
+//	err = w.Write(geo.NewFeature(geo.NewPoint(37.615223, -122.389979)).Set("name", "SFO"))
+//	err = w.Close()
+//
+//	r, err := geoparquet.NewReader(f, size)
+//	for {
+//		feature, err := r.Next()
+//		...
+//	}
+//
+// geoparquet implements the parts of Parquet it needs itself, so it depends on no Parquet library:
+// it reads flat columns of pages that are uncompressed or compressed with Snappy or gzip, and plainly
+// or dictionary encoded, as most writers write them by default.  Features' IDs aren't stored;
+// keep them in a property.
+package geoparquet
+
+import (
+	"encoding/json"
+	"github.com/kellydunn/golang-geo"
+)
+
+// The bytes Parquet files start and end with.
+const MAGIC = "PAR1"
+
+// The key of the Parquet metadata describing a GeoParquet file's geometry columns.
+const METADATA_KEY = "geo"
+
+// The version of GeoParquet a Writer writes.
+const VERSION = "1.1.0"
+
+// The GeoParquet metadata of a file, stored as JSON under METADATA_KEY.
+type Metadata struct {
+	Version       string                    `json:"version"`
+	PrimaryColumn string                    `json:"primary_column"`
+	Columns       map[string]ColumnMetadata `json:"columns"`
+}
+
+// The GeoParquet metadata of a geometry column.
+type ColumnMetadata struct {
+	// How the column's geometries are encoded.  A Reader only reads "WKB".
+	Encoding string `json:"encoding"`
+
+	// The types of geometry the column holds, e.g. "Point", or none if they are unknown.
+	GeometryTypes []string `json:"geometry_types"`
+
+	// The PROJJSON coordinate reference system of the column's geometries.  Without one,
+	// they are longitude and latitude on WGS 84.
+	CRS json.RawMessage `json:"crs,omitempty"`
+
+	// The bounding box of the column's geometries: their least longitude and latitude,
+	// then their greatest, or nil if it is unknown.
+	BBox []float64 `json:"bbox,omitempty"`
+}
+
+// Returns the bounding box of the column's geometries as Bounds, or nil if it is unknown.
+func (c ColumnMetadata) Bounds() *geo.Bounds {
+	if len(c.BBox) != 4 {
+		return nil
+	}
+
+	return geo.NewBounds(geo.NewPoint(c.BBox[1], c.BBox[0]), geo.NewPoint(c.BBox[3], c.BBox[2]))
+}
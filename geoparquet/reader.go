@@ -0,0 +1,560 @@
+package geoparquet
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/kellydunn/golang-geo"
+	"io"
+	"math"
+)
+
+// This is the error that consumers can compare against with errors.Is when a file isn't Parquet,
+// or has no GeoParquet metadata.
+var ErrNotGeoParquet = errors.New("geoparquet: not a GeoParquet file")
+
+// The most values whose room is set aside before they are read, so that corrupt counts can't exhaust memory.
+const maxPreallocated = 1 << 16
+
+// A column a Reader reads: a top-level column that isn't repeated.
+type column struct {
+	chunk      int
+	name       string
+	typ        int32
+	typeLength int32
+	optional   bool
+	text       bool
+	json       bool
+	geometry   bool
+}
+
+// A Reader reads the Features of a GeoParquet file one row group at a time, so that files
+// larger than memory can be processed.  Each row becomes a Feature whose geometry is the primary
+// geometry column's, and whose properties are the other columns' non-null values: strings, int64s,
+// float64s, bools, geo.Geometry values for other geometry columns, values decoded from JSON columns,
+// and []byte for other binary columns.  Nested and repeated columns are skipped.
+type Reader struct {
+	r        io.ReaderAt
+	size     int64
+	meta     *fileMetaData
+	metadata *Metadata
+	columns  []column
+	next     int
+	rows     []*geo.Feature
+}
+
+// Creates and returns a pointer to a new Reader for the GeoParquet file of the passed in size, such as an *os.File,
+// after reading its metadata.  Returns an error matching ErrNotGeoParquet if it isn't GeoParquet, and an error
+// if its geometries aren't WKB, or aren't longitude and latitude on WGS 84.
+func NewReader(r io.ReaderAt, size int64) (*Reader, error) {
+	if size < 12 {
+		return nil, fmt.Errorf("%w: %d bytes", ErrNotGeoParquet, size)
+	}
+
+	var head, tail [8]byte
+	if _, err := r.ReadAt(head[:4], 0); err != nil {
+		return nil, err
+	}
+	if _, err := r.ReadAt(tail[:], size-8); err != nil {
+		return nil, err
+	}
+	if string(head[:4]) != MAGIC || string(tail[4:]) != MAGIC {
+		return nil, fmt.Errorf("%w: no Parquet magic number", ErrNotGeoParquet)
+	}
+
+	length := int64(binary.LittleEndian.Uint32(tail[:4]))
+	if length > size-12 {
+		return nil, fmt.Errorf("%w: footer of %d bytes", errThrift, length)
+	}
+	footer := make([]byte, length)
+	if _, err := r.ReadAt(footer, size-8-length); err != nil {
+		return nil, err
+	}
+
+	tr := &thriftReader{data: footer}
+	meta := readFileMetaData(tr)
+	if tr.err != nil {
+		return nil, tr.err
+	}
+
+	reader := &Reader{r: r, size: size, meta: meta}
+	if err := reader.readMetadata(); err != nil {
+		return nil, err
+	}
+	if err := reader.readSchema(); err != nil {
+		return nil, err
+	}
+
+	return reader, nil
+}
+
+// Decodes and checks the file's GeoParquet metadata.
+func (r *Reader) readMetadata() error {
+	for _, kv := range r.meta.keyValues {
+		if kv.key != METADATA_KEY {
+			continue
+		}
+
+		r.metadata = &Metadata{}
+		if err := json.Unmarshal([]byte(kv.value), r.metadata); err != nil {
+			return fmt.Errorf("%w: %v", ErrNotGeoParquet, err)
+		}
+	}
+
+	if r.metadata == nil {
+		return fmt.Errorf("%w: no %q metadata", ErrNotGeoParquet, METADATA_KEY)
+	}
+	if _, ok := r.metadata.Columns[r.metadata.PrimaryColumn]; !ok {
+		return fmt.Errorf("%w: no metadata for primary column %q", ErrNotGeoParquet, r.metadata.PrimaryColumn)
+	}
+
+	for name, c := range r.metadata.Columns {
+		if c.Encoding != "WKB" {
+			return fmt.Errorf("geoparquet: column %q is encoded as %s; only WKB is supported", name, c.Encoding)
+		}
+		if !isWGS84(c.CRS) {
+			return fmt.Errorf("geoparquet: column %q isn't longitude and latitude on WGS 84", name)
+		}
+	}
+
+	return nil
+}
+
+// Returns whether the passed in PROJJSON coordinate reference system is longitude and latitude on WGS 84,
+// as GeoParquet's default, OGC:CRS84, is.  Geometries without one are taken to be.
+func isWGS84(crs json.RawMessage) bool {
+	if len(crs) == 0 || string(crs) == "null" {
+		return true
+	}
+
+	var projjson struct {
+		ID struct {
+			Authority string      `json:"authority"`
+			Code      interface{} `json:"code"`
+		} `json:"id"`
+	}
+	if err := json.Unmarshal(crs, &projjson); err != nil {
+		return false
+	}
+
+	id := projjson.ID.Authority + ":" + fmt.Sprint(projjson.ID.Code)
+	return id == "OGC:CRS84" || id == "EPSG:4326"
+}
+
+// Finds the columns the Reader reads among the file's schema.
+func (r *Reader) readSchema() error {
+	schema := r.meta.schema
+	if len(schema) == 0 {
+		return fmt.Errorf("%w: empty schema", errThrift)
+	}
+
+	chunk := 0
+	i := 1
+	for child := 0; child < int(schema[0].numChildren); child++ {
+		if i >= len(schema) {
+			return fmt.Errorf("%w: truncated schema", errThrift)
+		}
+
+		e := schema[i]
+		if e.numChildren > 0 {
+			next, leaves, err := skipSchema(schema, i, 0)
+			if err != nil {
+				return err
+			}
+			i, chunk = next, chunk+leaves
+			continue
+		}
+
+		if e.repetition != repetitionRepeated {
+			_, geometry := r.metadata.Columns[e.name]
+			r.columns = append(r.columns, column{
+				chunk:      chunk,
+				name:       e.name,
+				typ:        e.typ,
+				typeLength: e.typeLength,
+				optional:   e.repetition == repetitionOptional,
+				text:       e.converted == convertedUTF8 || e.converted == convertedEnum || e.logical == logicalString || e.logical == logicalEnum,
+				json:       e.converted == convertedJSON || e.logical == logicalJSON,
+				geometry:   geometry,
+			})
+		}
+		i, chunk = i+1, chunk+1
+	}
+
+	for _, c := range r.columns {
+		if c.geometry && c.typ != typeByteArray {
+			return fmt.Errorf("geoparquet: geometry column %q isn't binary", c.name)
+		}
+	}
+	for _, g := range r.meta.rowGroups {
+		if len(g.columns) != chunk {
+			return fmt.Errorf("%w: row group of %d columns, expected %d", errThrift, len(g.columns), chunk)
+		}
+	}
+
+	return nil
+}
+
+// Returns the index of the schema element following the group at the passed in index, along with the number
+// of columns within the group.
+func skipSchema(schema []schemaElement, i int, depth int) (int, int, error) {
+	if depth > thriftMaxDepth {
+		return 0, 0, fmt.Errorf("%w: schema nested too deeply", errThrift)
+	}
+
+	children := int(schema[i].numChildren)
+	i++
+	leaves := 0
+	for child := 0; child < children; child++ {
+		if i >= len(schema) {
+			return 0, 0, fmt.Errorf("%w: truncated schema", errThrift)
+		}
+		if schema[i].numChildren == 0 {
+			i++
+			leaves++
+			continue
+		}
+
+		next, n, err := skipSchema(schema, i, depth+1)
+		if err != nil {
+			return 0, 0, err
+		}
+		i, leaves = next, leaves+n
+	}
+
+	return i, leaves, nil
+}
+
+// Returns the file's GeoParquet metadata.
+func (r *Reader) Metadata() *Metadata {
+	return r.metadata
+}
+
+// Returns the number of rows in the file.
+func (r *Reader) NumRows() int64 {
+	return r.meta.numRows
+}
+
+// Returns the number of row groups in the file.
+func (r *Reader) NumRowGroups() int {
+	return len(r.meta.rowGroups)
+}
+
+// Returns the Features of the passed in row group.
+func (r *Reader) ReadRowGroup(i int) ([]*geo.Feature, error) {
+	if i < 0 || i >= len(r.meta.rowGroups) {
+		return nil, fmt.Errorf("geoparquet: no row group %d", i)
+	}
+
+	g := r.meta.rowGroups[i]
+	if g.numRows < 0 || g.numRows > math.MaxInt32 {
+		return nil, fmt.Errorf("%w: row group of %d rows", errThrift, g.numRows)
+	}
+
+	features := make([]*geo.Feature, 0, min(int(g.numRows), maxPreallocated))
+	for row := int64(0); row < g.numRows; row++ {
+		features = append(features, &geo.Feature{Properties: make(map[string]interface{})})
+	}
+
+	for _, c := range r.columns {
+		values, err := r.readColumn(c, g.columns[c.chunk], int(g.numRows))
+		if err != nil {
+			return nil, fmt.Errorf("geoparquet: column %q: %w", c.name, err)
+		}
+
+		for row, value := range values {
+			switch {
+			case value == nil:
+			case c.name == r.metadata.PrimaryColumn:
+				features[row].Geometry = value.(geo.Geometry)
+			default:
+				features[row].Properties[c.name] = value
+			}
+		}
+	}
+
+	return features, nil
+}
+
+// Returns the next Feature in the file, reading the next row group once the current one's run out,
+// or io.EOF once every Feature has been read.
+func (r *Reader) Next() (*geo.Feature, error) {
+	for len(r.rows) == 0 {
+		if r.next >= len(r.meta.rowGroups) {
+			return nil, io.EOF
+		}
+
+		rows, err := r.ReadRowGroup(r.next)
+		if err != nil {
+			return nil, err
+		}
+		r.next++
+		r.rows = rows
+	}
+
+	f := r.rows[0]
+	r.rows[0] = nil
+	r.rows = r.rows[1:]
+	return f, nil
+}
+
+// Reads the values of the passed in column in a row group of the passed in number of rows, nil for nulls.
+func (r *Reader) readColumn(c column, chunk columnMetaData, rows int) ([]interface{}, error) {
+	if chunk.typ != c.typ {
+		return nil, fmt.Errorf("%w: chunk of type %d, expected %d", errThrift, chunk.typ, c.typ)
+	}
+
+	start := chunk.dataPageOffset
+	if chunk.dictionaryPageOffset > 0 && chunk.dictionaryPageOffset < start {
+		start = chunk.dictionaryPageOffset
+	}
+	if start < 4 || chunk.totalCompressed < 0 || start+chunk.totalCompressed > r.size {
+		return nil, fmt.Errorf("%w: chunk outside the file", errThrift)
+	}
+
+	data := make([]byte, chunk.totalCompressed)
+	if _, err := r.r.ReadAt(data, start); err != nil {
+		return nil, err
+	}
+
+	var dictionary []interface{}
+	values := make([]interface{}, 0, min(rows, maxPreallocated))
+	for len(values) < rows {
+		tr := &thriftReader{data: data}
+		h := readPageHeader(tr)
+		if tr.err != nil {
+			return nil, tr.err
+		}
+		if int(h.compressedSize) > len(tr.data) {
+			return nil, fmt.Errorf("%w: truncated page", errThrift)
+		}
+		page := tr.data[:h.compressedSize]
+		data = tr.data[h.compressedSize:]
+
+		var err error
+		switch h.typ {
+		case pageDictionary:
+			var raw []byte
+			if raw, err = decompress(chunk.codec, page, int(h.uncompressedSize)); err == nil {
+				dictionary, err = decodePlain(c, raw, int(h.numValues))
+			}
+		case pageData:
+			var raw []byte
+			if raw, err = decompress(chunk.codec, page, int(h.uncompressedSize)); err == nil {
+				values, err = appendPage(values, c, h, raw, dictionary, true)
+			}
+		case pageDataV2:
+			levels := int(h.repLevelsLength) + int(h.defLevelsLength)
+			if h.repLevelsLength < 0 || h.defLevelsLength < 0 || levels > len(page) {
+				return nil, fmt.Errorf("%w: invalid page levels", errThrift)
+			}
+			raw := page[levels:]
+			if h.isCompressed {
+				raw, err = decompress(chunk.codec, raw, int(h.uncompressedSize)-levels)
+			}
+			if err == nil {
+				raw = append(page[int(h.repLevelsLength):levels:levels], raw...)
+				values, err = appendPage(values, c, h, raw, dictionary, false)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(values) != rows {
+		return nil, fmt.Errorf("%w: %d values for %d rows", errThrift, len(values), rows)
+	}
+
+	return values, nil
+}
+
+// Appends the values of a data page to values, nil for nulls.  The page's data starts with its definition
+// levels, prefixed with their length if it is a version 1 page.
+func appendPage(values []interface{}, c column, h *pageHeader, data []byte, dictionary []interface{}, prefixed bool) ([]interface{}, error) {
+	n := int(h.numValues)
+	if n < 0 {
+		return nil, fmt.Errorf("%w: page of %d values", errThrift, n)
+	}
+
+	var defs []int32
+	if c.optional {
+		length := int(h.defLevelsLength)
+		if prefixed {
+			if len(data) < 4 {
+				return nil, fmt.Errorf("%w: truncated levels", errThrift)
+			}
+			length = int(binary.LittleEndian.Uint32(data))
+			data = data[4:]
+		}
+		if length < 0 || length > len(data) {
+			return nil, fmt.Errorf("%w: truncated levels", errThrift)
+		}
+
+		var err error
+		if defs, err = decodeRLE(data[:length], 1, n); err != nil {
+			return nil, err
+		}
+		data = data[length:]
+	}
+
+	present := n
+	if defs != nil {
+		present = 0
+		for _, d := range defs {
+			if d > 1 {
+				return nil, fmt.Errorf("%w: definition level %d of a flat column", errThrift, d)
+			}
+			present += int(d)
+		}
+	}
+
+	var decoded []interface{}
+	var err error
+	switch h.encoding {
+	case encodingPlain:
+		decoded, err = decodePlain(c, data, present)
+	case encodingPlainDictionary, encodingRLEDictionary:
+		decoded, err = decodeDictionary(data, present, dictionary)
+	case encodingRLE:
+		decoded, err = decodeRLEBooleans(c, data, present)
+	default:
+		err = fmt.Errorf("geoparquet: unsupported encoding %d", h.encoding)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if defs == nil {
+		return append(values, decoded...), nil
+	}
+
+	for _, d := range defs {
+		if d == 0 {
+			values = append(values, nil)
+			continue
+		}
+		values = append(values, decoded[0])
+		decoded = decoded[1:]
+	}
+
+	return values, nil
+}
+
+// Decodes n values encoded as indices into the passed in dictionary: their bit width, then the indices.
+func decodeDictionary(data []byte, n int, dictionary []interface{}) ([]interface{}, error) {
+	if n == 0 {
+		return nil, nil
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: truncated dictionary indices", errThrift)
+	}
+
+	indices, err := decodeRLE(data[1:], int(data[0]), n)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, n)
+	for i, index := range indices {
+		if index < 0 || int(index) >= len(dictionary) {
+			return nil, fmt.Errorf("%w: dictionary index %d of %d", errThrift, index, len(dictionary))
+		}
+		values[i] = dictionary[index]
+	}
+
+	return values, nil
+}
+
+// Decodes n booleans encoded as runs: their length, then levels of one bit.
+func decodeRLEBooleans(c column, data []byte, n int) ([]interface{}, error) {
+	if c.typ != typeBoolean {
+		return nil, fmt.Errorf("geoparquet: unsupported encoding %d", encodingRLE)
+	}
+	if len(data) < 4 || int(binary.LittleEndian.Uint32(data)) > len(data)-4 {
+		return nil, fmt.Errorf("%w: truncated booleans", errThrift)
+	}
+
+	bits, err := decodeRLE(data[4:4+binary.LittleEndian.Uint32(data)], 1, n)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, n)
+	for i, b := range bits {
+		values[i] = b == 1
+	}
+
+	return values, nil
+}
+
+// Decodes n plainly encoded values of the passed in column.
+func decodePlain(c column, data []byte, n int) ([]interface{}, error) {
+	size := 4
+	switch c.typ {
+	case typeBoolean:
+		size = 0
+	case typeInt64, typeDouble:
+		size = 8
+	case typeInt96:
+		size = 12
+	case typeFixedLenByteArray:
+		size = int(c.typeLength)
+	}
+	if n < 0 || size > 0 && n > len(data)/size || size <= 0 && c.typ != typeBoolean || n > len(data)*8 {
+		return nil, fmt.Errorf("%w: truncated values", errThrift)
+	}
+
+	values := make([]interface{}, n)
+	for i := range values {
+		switch c.typ {
+		case typeBoolean:
+			values[i] = data[i/8]>>(i%8)&1 == 1
+		case typeInt32:
+			values[i] = int64(int32(binary.LittleEndian.Uint32(data[i*4:])))
+		case typeInt64:
+			values[i] = int64(binary.LittleEndian.Uint64(data[i*8:]))
+		case typeFloat:
+			values[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:])))
+		case typeDouble:
+			values[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[i*8:]))
+		case typeInt96, typeFixedLenByteArray:
+			values[i] = append([]byte(nil), data[i*size:(i+1)*size]...)
+		case typeByteArray:
+			if len(data) < 4 || int64(binary.LittleEndian.Uint32(data)) > int64(len(data)-4) {
+				return nil, fmt.Errorf("%w: truncated values", errThrift)
+			}
+			b := data[4 : 4+binary.LittleEndian.Uint32(data)]
+			data = data[4+len(b):]
+
+			value, err := byteArrayValue(c, b)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+		default:
+			return nil, fmt.Errorf("%w: unknown type %d", errThrift, c.typ)
+		}
+	}
+
+	return values, nil
+}
+
+// Returns the value of a byte array of the passed in column: a geometry, a string, a value decoded from JSON,
+// or a copy of the bytes.
+func byteArrayValue(c column, b []byte) (interface{}, error) {
+	switch {
+	case c.geometry:
+		g, _, err := geo.UnmarshalWKB(b)
+		return g, err
+	case c.text:
+		return string(b), nil
+	case c.json:
+		var value interface{}
+		err := json.Unmarshal(b, &value)
+		return value, err
+	default:
+		return append([]byte(nil), b...), nil
+	}
+}
@@ -0,0 +1,157 @@
+package geoparquet
+
+import (
+	"bytes"
+	"errors"
+	"github.com/kellydunn/golang-geo"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// Writes the passed in Features to a GeoParquet file of the passed in schema, with row groups of two rows.
+func writeFile(t *testing.T, schema geo.PropertySchema, compression Codec, features ...*geo.Feature) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.RowGroupSize = 2
+	w.Compression = compression
+
+	for _, f := range features {
+		if err := w.Write(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+// Ensures that Features written by a Writer are read back by a Reader, one row group at a time.
+func TestRoundTrip(t *testing.T) {
+	schema := geo.PropertySchema{
+		"name":   {Type: geo.StringProperty, Required: true},
+		"gates":  {Type: geo.IntegerProperty},
+		"rating": {Type: geo.NumberProperty},
+		"open":   {Type: geo.BoolProperty},
+		"tags":   {},
+	}
+	features := []*geo.Feature{
+		geo.NewFeature(geo.NewPoint(37.615223, -122.389979)).Set("name", "SFO").Set("gates", 115).Set("open", true),
+		geo.NewFeature(geo.Line{geo.NewPoint(0, 0), geo.NewPoint(1, 1)}).Set("name", "route").Set("rating", 4.5),
+		geo.NewFeature(geo.NewPolygon([]*geo.Point{geo.NewPoint(0, 0), geo.NewPoint(0, 2), geo.NewPoint(2, 2)})).Set("name", "zone").Set("tags", []interface{}{"a", "b"}),
+		geo.NewFeature(nil).Set("name", "nowhere").Set("open", false),
+	}
+
+	for _, compression := range []Codec{Uncompressed, Gzip} {
+		data := writeFile(t, schema, compression, features...)
+		r, err := NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if r.NumRows() != 4 || r.NumRowGroups() != 2 {
+			t.Errorf("Expected 4 rows in 2 row groups, got %d in %d", r.NumRows(), r.NumRowGroups())
+		}
+
+		column := r.Metadata().Columns[DEFAULT_GEOMETRY_COLUMN]
+		expectedTypes := []string{"LineString", "Point", "Polygon"}
+		if r.Metadata().PrimaryColumn != DEFAULT_GEOMETRY_COLUMN || column.Encoding != "WKB" || !reflect.DeepEqual(column.GeometryTypes, expectedTypes) {
+			t.Errorf("Expected WKB %v geometries, got %+v", expectedTypes, r.Metadata())
+		}
+		if b := column.Bounds(); *b.SouthWest() != *geo.NewPoint(0, -122.389979) || *b.NorthEast() != *geo.NewPoint(37.615223, 2) {
+			t.Errorf("Expected the bounding box of the geometries, got %v", column.BBox)
+		}
+
+		for i, expected := range features {
+			f, err := r.Next()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if expected.Geometry == nil && f.Geometry != nil || expected.Geometry != nil && !reflect.DeepEqual(f.Geometry, expected.Geometry) {
+				t.Errorf("Expected geometry %v for row %d, got %v", expected.Geometry, i, f.Geometry)
+			}
+
+			properties := map[string]interface{}{}
+			for key, value := range expected.Properties {
+				properties[key] = value
+			}
+			if gates, ok := properties["gates"]; ok {
+				properties["gates"] = int64(gates.(int))
+			}
+			if !reflect.DeepEqual(f.Properties, properties) {
+				t.Errorf("Expected properties %v for row %d, got %v", properties, i, f.Properties)
+			}
+		}
+
+		if _, err := r.Next(); err != io.EOF {
+			t.Errorf("Expected io.EOF after the last row, got %v", err)
+		}
+	}
+}
+
+// Ensures that Features that don't match the schema are refused.
+func TestWriterValidation(t *testing.T) {
+	w, err := NewWriter(io.Discard, geo.PropertySchema{"name": {Type: geo.StringProperty, Required: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Write(geo.NewFeature(geo.NewPoint(0, 0)).Set("name", 1)); !errors.Is(err, geo.ErrInvalidProperty) {
+		t.Errorf("Expected geo.ErrInvalidProperty for a name that isn't a string, got %v", err)
+	}
+
+	if _, err := NewWriter(io.Discard, geo.PropertySchema{DEFAULT_GEOMETRY_COLUMN: {}}); err == nil {
+		t.Error("Expected an error for a property named as the geometry column is")
+	}
+}
+
+// Ensures that files that aren't GeoParquet are refused.
+func TestNotGeoParquet(t *testing.T) {
+	for _, data := range [][]byte{
+		[]byte("not parquet at all"),
+		[]byte("PAR1\x00\x00\x00\x00PAR1"),
+	} {
+		if _, err := NewReader(bytes.NewReader(data), int64(len(data))); !errors.Is(err, ErrNotGeoParquet) && !errors.Is(err, errThrift) {
+			t.Errorf("Expected an error for %q, got %v", data, err)
+		}
+	}
+
+	// A Parquet file without GeoParquet metadata.
+	tw := &thriftWriter{}
+	(&fileMetaData{version: 1, schema: []schemaElement{{name: "schema", converted: -1}}}).write(tw)
+	data := append([]byte(MAGIC), tw.buf...)
+	data = append(data, byte(len(tw.buf)), 0, 0, 0)
+	data = append(data, MAGIC...)
+	if _, err := NewReader(bytes.NewReader(data), int64(len(data))); !errors.Is(err, ErrNotGeoParquet) {
+		t.Errorf("Expected ErrNotGeoParquet without metadata, got %v", err)
+	}
+}
+
+// Ensures that optional, dictionary encoded values are looked up in their dictionary.
+func TestDictionaryPage(t *testing.T) {
+	c := column{name: "name", typ: typeByteArray, optional: true, text: true}
+	dictionary, err := decodePlain(c, []byte("\x03\x00\x00\x00SFO\x03\x00\x00\x00OAK"), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Definition levels 1, 0, 1, then indices 1, 0 of one bit each.
+	levels := appendRLE(nil, []int32{1, 0, 1}, 1)
+	data := append(levels, 1)
+	data = append(data, appendRLE(nil, []int32{1, 0}, 1)...)
+
+	h := &pageHeader{numValues: 3, encoding: encodingRLEDictionary, defLevelsLength: int32(len(levels))}
+	values, err := appendPage(nil, c, h, data, dictionary, false)
+	expected := []interface{}{"OAK", nil, "SFO"}
+	if err != nil || !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected %v, got %v (%v)", expected, values, err)
+	}
+}
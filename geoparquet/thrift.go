@@ -0,0 +1,283 @@
+package geoparquet
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// The types of the Thrift compact protocol, which Parquet encodes its metadata with.
+const (
+	thriftStop   = 0
+	thriftTrue   = 1
+	thriftFalse  = 2
+	thriftByte   = 3
+	thriftI16    = 4
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftDouble = 7
+	thriftBinary = 8
+	thriftList   = 9
+	thriftSet    = 10
+	thriftMap    = 11
+	thriftStruct = 12
+)
+
+// Limits on the metadata a thriftReader reads, so that corrupt files can't exhaust memory or the stack.
+const (
+	thriftMaxDepth  = 64
+	thriftMaxLength = 1 << 28
+)
+
+// Writes Thrift structs with the compact protocol.
+type thriftWriter struct {
+	buf  []byte
+	last []int16
+}
+
+// Starts a struct, whose fields must then be written in increasing order.
+func (w *thriftWriter) begin() {
+	w.last = append(w.last, 0)
+}
+
+// Ends the current struct.
+func (w *thriftWriter) end() {
+	w.buf = append(w.buf, thriftStop)
+	w.last = w.last[:len(w.last)-1]
+}
+
+func (w *thriftWriter) field(id int16, kind byte) {
+	last := &w.last[len(w.last)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		w.buf = append(w.buf, byte(delta)<<4|kind)
+	} else {
+		w.buf = append(w.buf, kind)
+		w.buf = binary.AppendVarint(w.buf, int64(id))
+	}
+	*last = id
+}
+
+func (w *thriftWriter) i32(id int16, v int32) {
+	w.field(id, thriftI32)
+	w.buf = binary.AppendVarint(w.buf, int64(v))
+}
+
+func (w *thriftWriter) i64(id int16, v int64) {
+	w.field(id, thriftI64)
+	w.buf = binary.AppendVarint(w.buf, v)
+}
+
+func (w *thriftWriter) binary(id int16, v string) {
+	w.field(id, thriftBinary)
+	w.buf = binary.AppendUvarint(w.buf, uint64(len(v)))
+	w.buf = append(w.buf, v...)
+}
+
+// Starts a struct field, to be ended with end.
+func (w *thriftWriter) structField(id int16) {
+	w.field(id, thriftStruct)
+	w.begin()
+}
+
+// Starts a list field of n elements of the passed in type, which must then be written with the element methods.
+func (w *thriftWriter) list(id int16, kind byte, n int) {
+	w.field(id, thriftList)
+	if n < 15 {
+		w.buf = append(w.buf, byte(n)<<4|kind)
+	} else {
+		w.buf = append(w.buf, 0xf0|kind)
+		w.buf = binary.AppendUvarint(w.buf, uint64(n))
+	}
+}
+
+// Writes an i32 list element.
+func (w *thriftWriter) i32Element(v int32) {
+	w.buf = binary.AppendVarint(w.buf, int64(v))
+}
+
+// Writes a binary list element.
+func (w *thriftWriter) binaryElement(v string) {
+	w.buf = binary.AppendUvarint(w.buf, uint64(len(v)))
+	w.buf = append(w.buf, v...)
+}
+
+var errThrift = errors.New("geoparquet: invalid file metadata")
+
+// Reads Thrift structs encoded with the compact protocol, recording the first error it runs into.
+type thriftReader struct {
+	data  []byte
+	err   error
+	depth int
+}
+
+func (r *thriftReader) fail(format string, args ...interface{}) {
+	if r.err == nil {
+		r.err = fmt.Errorf("%w: "+format, append([]interface{}{errThrift}, args...)...)
+	}
+}
+
+func (r *thriftReader) byte() byte {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.data) == 0 {
+		r.fail("truncated")
+		return 0
+	}
+
+	b := r.data[0]
+	r.data = r.data[1:]
+	return b
+}
+
+func (r *thriftReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+
+	v, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.fail("invalid varint")
+		return 0
+	}
+	r.data = r.data[n:]
+	return v
+}
+
+func (r *thriftReader) varint() int64 {
+	v := r.uvarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *thriftReader) i32() int32 {
+	v := r.varint()
+	if v < math.MinInt32 || v > math.MaxInt32 {
+		r.fail("i32 out of range")
+	}
+	return int32(v)
+}
+
+func (r *thriftReader) i64() int64 {
+	return r.varint()
+}
+
+func (r *thriftReader) binary() string {
+	n := r.uvarint()
+	if r.err == nil && n > uint64(len(r.data)) {
+		r.fail("truncated")
+	}
+	if r.err != nil {
+		return ""
+	}
+
+	v := string(r.data[:n])
+	r.data = r.data[n:]
+	return v
+}
+
+// Reads a struct, calling the passed in function with the ID and type of each of its fields.
+// The function must read the field's value, or return false to have it skipped.
+func (r *thriftReader) readStruct(field func(id int16, kind byte) bool) {
+	r.depth++
+	defer func() { r.depth-- }()
+	if r.depth > thriftMaxDepth {
+		r.fail("nested too deeply")
+		return
+	}
+
+	var last int16
+	for r.err == nil {
+		header := r.byte()
+		kind := header & 0x0f
+		if kind == thriftStop {
+			return
+		}
+
+		if delta := int16(header >> 4); delta != 0 {
+			last += delta
+		} else {
+			last = int16(r.varint())
+		}
+
+		if !field(last, kind) {
+			r.skip(kind)
+		}
+	}
+}
+
+// Reads a list's header, returning the type and number of its elements.
+func (r *thriftReader) listHeader() (byte, int) {
+	header := r.byte()
+	n := uint64(header >> 4)
+	if n == 15 {
+		n = r.uvarint()
+	}
+	if n > thriftMaxLength || n > uint64(len(r.data)) {
+		r.fail("list of %d elements", n)
+		return 0, 0
+	}
+
+	return header & 0x0f, int(n)
+}
+
+// Reads a list, calling the passed in function for each of its elements, which it must read.
+func (r *thriftReader) readList(element func(kind byte)) {
+	kind, n := r.listHeader()
+	for i := 0; i < n && r.err == nil; i++ {
+		element(kind)
+	}
+}
+
+// Skips a value of the passed in type.
+func (r *thriftReader) skip(kind byte) {
+	switch kind {
+	case thriftTrue, thriftFalse:
+		// Booleans are held in their field's type.
+	case thriftByte:
+		r.byte()
+	case thriftI16, thriftI32, thriftI64:
+		r.uvarint()
+	case thriftDouble:
+		if len(r.data) < 8 {
+			r.fail("truncated")
+			return
+		}
+		r.data = r.data[8:]
+	case thriftBinary:
+		r.binary()
+	case thriftList, thriftSet:
+		elementKind, n := r.listHeader()
+		for i := 0; i < n && r.err == nil; i++ {
+			r.skipElement(elementKind)
+		}
+	case thriftMap:
+		n := r.uvarint()
+		if n == 0 {
+			return
+		}
+		kinds := r.byte()
+		if n > thriftMaxLength || n > uint64(len(r.data)) {
+			r.fail("map of %d entries", n)
+			return
+		}
+		for i := uint64(0); i < n && r.err == nil; i++ {
+			r.skipElement(kinds >> 4)
+			r.skipElement(kinds & 0x0f)
+		}
+	case thriftStruct:
+		r.readStruct(func(int16, byte) bool { return false })
+	default:
+		r.fail("unknown type %d", kind)
+	}
+}
+
+// Skips a list, set or map element, whose booleans take a byte, unlike fields'.
+func (r *thriftReader) skipElement(kind byte) {
+	if kind == thriftTrue || kind == thriftFalse {
+		r.byte()
+		return
+	}
+
+	r.skip(kind)
+}
@@ -0,0 +1,341 @@
+package geoparquet
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/kellydunn/golang-geo"
+	"io"
+	"math"
+	"sort"
+)
+
+// The column a Writer writes geometries to.
+const DEFAULT_GEOMETRY_COLUMN = "geometry"
+
+// The number of rows a Writer buffers before writing them as a row group, unless told otherwise.
+const DEFAULT_ROW_GROUP_SIZE = 10000
+
+// The application Writers record as having written their files.
+const CREATED_BY = "github.com/kellydunn/golang-geo"
+
+// A Writer writes Features as the rows of a GeoParquet file: their geometry as WKB in the optional
+// DEFAULT_GEOMETRY_COLUMN, then a column for each property of the schema it is created with, in order
+// of name.  Properties of StringProperty type are written as strings, NumberProperty as doubles,
+// IntegerProperty as int64s, BoolProperty as booleans, and AnyProperty as JSON.  Required
+// properties' columns are required; others' are optional.  Properties outside the schema aren't written.
+// Rows are buffered and written a row group at a time; call Close to write the rest, and the file's metadata.
+type Writer struct {
+	// The number of rows buffered before they are written as a row group.  Defaults to DEFAULT_ROW_GROUP_SIZE.
+	RowGroupSize int
+
+	// The codec pages are compressed with: Uncompressed, the default, or Gzip.
+	Compression Codec
+
+	w      io.Writer
+	offset int64
+	schema geo.PropertySchema
+	keys   []string
+	rows   []*geo.Feature
+	wkbs   [][]byte
+	meta   fileMetaData
+	types  map[string]bool
+	bbox   []float64
+	closed bool
+}
+
+// Creates and returns a pointer to a new Writer writing the Features of the passed in schema to the
+// passed in writer, after writing the start of the file.  Returns an error if a property is named
+// as the geometry column is.
+func NewWriter(w io.Writer, schema geo.PropertySchema) (*Writer, error) {
+	keys := make([]string, 0, len(schema))
+	for key := range schema {
+		if key == DEFAULT_GEOMETRY_COLUMN {
+			return nil, fmt.Errorf("geoparquet: property %q is named as the geometry column is", key)
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	writer := &Writer{
+		RowGroupSize: DEFAULT_ROW_GROUP_SIZE,
+		w:            w,
+		schema:       schema,
+		keys:         keys,
+		types:        make(map[string]bool),
+	}
+
+	writer.meta.schema = []schemaElement{{name: "schema", numChildren: int32(len(keys) + 1), converted: -1}}
+	writer.meta.schema = append(writer.meta.schema, schemaElement{typ: typeByteArray, repetition: repetitionOptional, name: DEFAULT_GEOMETRY_COLUMN, converted: -1})
+	for _, key := range keys {
+		e := schemaElement{repetition: repetitionOptional, name: key, converted: -1}
+		if schema[key].Required {
+			e.repetition = repetitionRequired
+		}
+		switch schema[key].Type {
+		case geo.StringProperty:
+			e.typ, e.converted, e.logical = typeByteArray, convertedUTF8, logicalString
+		case geo.NumberProperty:
+			e.typ = typeDouble
+		case geo.IntegerProperty:
+			e.typ = typeInt64
+		case geo.BoolProperty:
+			e.typ = typeBoolean
+		default:
+			e.typ, e.converted, e.logical = typeByteArray, convertedJSON, logicalJSON
+		}
+		writer.meta.schema = append(writer.meta.schema, e)
+	}
+
+	if err := writer.write([]byte(MAGIC)); err != nil {
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+// Writes the passed in bytes to the underlying writer.
+func (w *Writer) write(data []byte) error {
+	n, err := w.w.Write(data)
+	w.offset += int64(n)
+	return err
+}
+
+// Buffers the passed in Feature as a row, writing the buffered rows once there are RowGroupSize of them.
+// Returns an error if the Feature's properties don't match the schema, or its geometry can't be written as WKB.
+func (w *Writer) Write(f *geo.Feature) error {
+	if w.closed {
+		return errors.New("geoparquet: write to a closed Writer")
+	}
+	if err := w.schema.Validate(f); err != nil {
+		return err
+	}
+
+	var wkb []byte
+	if f.Geometry != nil {
+		var err error
+		if wkb, err = geo.MarshalWKB(f.Geometry); err != nil {
+			return err
+		}
+		w.types[f.Geometry.GeometryType()] = true
+		w.extend(f.Geometry)
+	}
+
+	w.rows = append(w.rows, f)
+	w.wkbs = append(w.wkbs, wkb)
+	if len(w.rows) >= w.RowGroupSize {
+		return w.Flush()
+	}
+
+	return nil
+}
+
+// Extends the bounding box of the file's geometries to cover the passed in geometry.
+func (w *Writer) extend(g geo.Geometry) {
+	var points []*geo.Point
+	switch g := g.(type) {
+	case *geo.Point:
+		points = []*geo.Point{g}
+	case geo.Line:
+		points = g
+	case *geo.Polygon:
+		points = g.Points()
+	}
+
+	for _, p := range points {
+		if w.bbox == nil {
+			w.bbox = []float64{p.Lng(), p.Lat(), p.Lng(), p.Lat()}
+			continue
+		}
+		w.bbox[0], w.bbox[1] = math.Min(w.bbox[0], p.Lng()), math.Min(w.bbox[1], p.Lat())
+		w.bbox[2], w.bbox[3] = math.Max(w.bbox[2], p.Lng()), math.Max(w.bbox[3], p.Lat())
+	}
+}
+
+// Writes the buffered rows as a row group, if there are any.
+func (w *Writer) Flush() error {
+	if len(w.rows) == 0 {
+		return nil
+	}
+
+	g := rowGroup{numRows: int64(len(w.rows))}
+	for i, e := range w.meta.schema[1:] {
+		var data []byte
+		var err error
+		if i == 0 {
+			data = w.encodeGeometries()
+		} else {
+			data, err = w.encodeProperty(e, w.keys[i-1])
+		}
+		if err != nil {
+			return err
+		}
+
+		chunk, err := w.writePage(e, data)
+		if err != nil {
+			return err
+		}
+		g.columns = append(g.columns, chunk)
+		g.totalByteSize += chunk.totalUncompressed
+	}
+
+	w.meta.rowGroups = append(w.meta.rowGroups, g)
+	w.meta.numRows += g.numRows
+	w.rows, w.wkbs = w.rows[:0], w.wkbs[:0]
+	return nil
+}
+
+// Returns the definition levels of the passed in optional column, prefixed with their length,
+// as version 1 data pages start with.
+func appendLevels(dst []byte, defs []int32) []byte {
+	levels := appendRLE(nil, defs, 1)
+	dst = binary.LittleEndian.AppendUint32(dst, uint32(len(levels)))
+	return append(dst, levels...)
+}
+
+// Returns the buffered rows' geometries as the data of a page.
+func (w *Writer) encodeGeometries() []byte {
+	defs := make([]int32, len(w.wkbs))
+	var values []byte
+	for i, wkb := range w.wkbs {
+		if w.rows[i].Geometry == nil {
+			continue
+		}
+		defs[i] = 1
+		values = binary.LittleEndian.AppendUint32(values, uint32(len(wkb)))
+		values = append(values, wkb...)
+	}
+
+	return append(appendLevels(nil, defs), values...)
+}
+
+// Returns the buffered rows' values of the passed in property as the data of a page.
+func (w *Writer) encodeProperty(e schemaElement, key string) ([]byte, error) {
+	defs := make([]int32, 0, len(w.rows))
+	var values []byte
+	var bools int
+	for _, f := range w.rows {
+		if value, ok := f.Properties[key]; !ok || value == nil {
+			defs = append(defs, 0)
+			continue
+		}
+		defs = append(defs, 1)
+
+		switch e.typ {
+		case typeBoolean:
+			b, _ := f.PropertyBool(key)
+			if bools%8 == 0 {
+				values = append(values, 0)
+			}
+			if b {
+				values[len(values)-1] |= 1 << (bools % 8)
+			}
+			bools++
+		case typeDouble:
+			n, _ := f.PropertyFloat(key)
+			values = binary.LittleEndian.AppendUint64(values, math.Float64bits(n))
+		case typeInt64:
+			n, _ := f.PropertyInt(key)
+			values = binary.LittleEndian.AppendUint64(values, uint64(n))
+		default:
+			var b []byte
+			if e.converted == convertedUTF8 {
+				s, _ := f.PropertyString(key)
+				b = []byte(s)
+			} else {
+				var err error
+				if b, err = json.Marshal(f.Properties[key]); err != nil {
+					return nil, fmt.Errorf("geoparquet: property %q: %w", key, err)
+				}
+			}
+			values = binary.LittleEndian.AppendUint32(values, uint32(len(b)))
+			values = append(values, b...)
+		}
+	}
+
+	if e.repetition == repetitionRequired {
+		return values, nil
+	}
+
+	return append(appendLevels(nil, defs), values...), nil
+}
+
+// Writes the passed in data of the passed in column as a page of the buffered rows, returning the column chunk
+// holding it.
+func (w *Writer) writePage(e schemaElement, data []byte) (columnMetaData, error) {
+	compressed, err := compress(w.Compression, data)
+	if err != nil {
+		return columnMetaData{}, err
+	}
+	if len(data) > math.MaxInt32 || len(compressed) > math.MaxInt32 {
+		return columnMetaData{}, fmt.Errorf("geoparquet: page of column %q is too large; write smaller row groups", e.name)
+	}
+
+	h := &pageHeader{
+		typ:              pageData,
+		uncompressedSize: int32(len(data)),
+		compressedSize:   int32(len(compressed)),
+		numValues:        int32(len(w.rows)),
+		encoding:         encodingPlain,
+	}
+	tw := &thriftWriter{}
+	h.write(tw)
+
+	chunk := columnMetaData{
+		typ:               e.typ,
+		encodings:         []int32{encodingPlain, encodingRLE},
+		path:              []string{e.name},
+		codec:             w.Compression,
+		numValues:         int64(len(w.rows)),
+		totalUncompressed: int64(len(tw.buf) + len(data)),
+		totalCompressed:   int64(len(tw.buf) + len(compressed)),
+		dataPageOffset:    w.offset,
+	}
+
+	if err := w.write(tw.buf); err != nil {
+		return columnMetaData{}, err
+	}
+	if err := w.write(compressed); err != nil {
+		return columnMetaData{}, err
+	}
+
+	return chunk, nil
+}
+
+// Writes the buffered rows, then the file's metadata and end.  Close doesn't close the underlying writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	w.closed = true
+
+	types := make([]string, 0, len(w.types))
+	for t := range w.types {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	metadata, err := json.Marshal(&Metadata{
+		Version:       VERSION,
+		PrimaryColumn: DEFAULT_GEOMETRY_COLUMN,
+		Columns: map[string]ColumnMetadata{
+			DEFAULT_GEOMETRY_COLUMN: {Encoding: "WKB", GeometryTypes: types, BBox: w.bbox},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	w.meta.version = 1
+	w.meta.keyValues = []keyValue{{key: METADATA_KEY, value: string(metadata)}}
+	w.meta.createdBy = CREATED_BY
+
+	tw := &thriftWriter{}
+	w.meta.write(tw)
+	footer := binary.LittleEndian.AppendUint32(tw.buf, uint32(len(tw.buf)))
+	return w.write(append(footer, MAGIC...))
+}
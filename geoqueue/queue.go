@@ -0,0 +1,427 @@
+// Package geoqueue geocodes addresses in the background: jobs are submitted to a Queue,
+// persisted to a BoltDB file so that they survive restarts, processed by workers that honor
+// the provider's rate limits, and their status and results looked up later.  It suits batch
+// work such as a nightly ETL geocoding the addresses added during the day:
+//
+//	q, err := geoqueue.Open("geocodes.db", geo.NewGoogleGeocoder(geo.WithAPIKey(key)))
+//	ids, err := q.Submit("1600 Amphitheatre Parkway", "1 Infinite Loop")
+//	err = q.Run(ctx)
+//	job, err := q.Job(ids[0])
+package geoqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/kellydunn/golang-geo"
+	bolt "go.etcd.io/bbolt"
+	"sync"
+	"time"
+)
+
+// The number of jobs a Queue geocodes at once unless told otherwise.
+const DEFAULT_WORKERS = 4
+
+// The number of times a Queue tries a job that fails for a transient reason before giving up on it,
+// unless told otherwise.
+const DEFAULT_MAX_ATTEMPTS = 3
+
+// The time a Queue waits before retrying a job that failed for a transient reason, unless told otherwise.
+// It doubles with each retry after the first.
+const DEFAULT_BACKOFF = time.Second
+
+// The longest a Queue waits before retrying a job, however many times it has failed, unless told otherwise.
+const DEFAULT_MAX_BACKOFF = 5 * time.Minute
+
+// The name of the bolt bucket that holds every job.
+var jobsBucket = []byte("jobs")
+
+// This is the error that consumers receive when looking up a job that was never submitted.
+var ErrJobNotFound = errors.New("geoqueue: job not found")
+
+// The states of a Job.
+type JobStatus string
+
+const (
+	// The job is waiting to be geocoded, for the first time or again after a transient failure,
+	// once its NextAttempt is due.
+	Pending JobStatus = "pending"
+
+	// The job is being geocoded.
+	Running JobStatus = "running"
+
+	// The job was geocoded, and its Point is set.
+	Done JobStatus = "done"
+
+	// The job can't be geocoded: the provider found nothing or refused the query,
+	// or it failed MaxAttempts times.  Its Error says why.
+	Failed JobStatus = "failed"
+)
+
+// A Job is a single query submitted to a Queue, along with its outcome.
+type Job struct {
+	ID     string    `json:"id"`
+	Query  string    `json:"query"`
+	Status JobStatus `json:"status"`
+
+	// The geocoded Point, once the job is Done.
+	Point *geo.Point `json:"point,omitempty"`
+
+	// Why the job last failed, if it has.
+	Error string `json:"error,omitempty"`
+
+	// The number of times the job has been tried.
+	Attempts int `json:"attempts"`
+
+	// When a job that failed for a transient reason may be tried again.  Zero for jobs that may be tried now.
+	NextAttempt time.Time `json:"next_attempt"`
+
+	Submitted time.Time `json:"submitted"`
+	Updated   time.Time `json:"updated"`
+}
+
+// A Queue persists geocoding jobs to a BoltDB file and processes them with a pool of workers.
+// A Queue is safe to use from multiple goroutines, though only one Run may process it at a time.
+type Queue struct {
+	db *bolt.DB
+
+	// The Geocoder jobs are geocoded with.
+	Geocoder geo.Geocoder
+
+	// The number of jobs geocoded at once.  Defaults to DEFAULT_WORKERS.
+	Workers int
+
+	// If set, the least time between two requests to the Geocoder across all workers,
+	// e.g. time.Second / 50 for a provider allowing 50 requests per second.
+	Interval time.Duration
+
+	// The number of times a job failing for a transient reason, such as the provider being down,
+	// is tried before it is Failed.  Defaults to DEFAULT_MAX_ATTEMPTS.
+	MaxAttempts int
+
+	// The time a job waits before it is retried after a transient failure, doubling with each retry after
+	// the first, up to MaxBackoff.  Defaults to DEFAULT_BACKOFF and DEFAULT_MAX_BACKOFF.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+
+	running sync.Mutex
+
+	// Used to determine the current time.  Overridable for testing.
+	now func() time.Time
+}
+
+// Opens the queue stored in the BoltDB file at the passed in path, creating it if it doesn't exist,
+// to be processed with the passed in Geocoder.  Jobs left Running by a process that stopped
+// before finishing them are made Pending again.
+func Open(path string, g geo.Geocoder) (*Queue, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Queue{db: db, Geocoder: g, now: time.Now}
+	err = db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(jobsBucket)
+		if err != nil {
+			return err
+		}
+
+		// Buckets mustn't be modified while iterating over them, so collect the jobs first.
+		var interrupted []*Job
+		err = forEachJob(b, func(job *Job) error {
+			if job.Status == Running {
+				interrupted = append(interrupted, job)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, job := range interrupted {
+			job.Status = Pending
+			if err := putJob(b, job); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return q, nil
+}
+
+// Closes the queue's BoltDB file.
+func (q *Queue) Close() error {
+	return q.db.Close()
+}
+
+// Submits a Pending job for each of the passed in queries, to be geocoded by the next Run.
+// Returns the IDs of the jobs, in the order of the queries.  Job IDs sort in the order
+// jobs were submitted, which is the order they are geocoded in.
+func (q *Queue) Submit(queries ...string) ([]string, error) {
+	ids := make([]string, 0, len(queries))
+	err := q.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(jobsBucket)
+		now := q.now().UTC()
+		for _, query := range queries {
+			seq, err := b.NextSequence()
+			if err != nil {
+				return err
+			}
+
+			job := &Job{ID: fmt.Sprintf("%016x", seq), Query: query, Status: Pending, Submitted: now, Updated: now}
+			if err := putJob(b, job); err != nil {
+				return err
+			}
+			ids = append(ids, job.ID)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// Returns the job with the passed in ID, or ErrJobNotFound if there is none.
+func (q *Queue) Job(id string) (*Job, error) {
+	var job *Job
+	err := q.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(jobsBucket).Get([]byte(id))
+		if data == nil {
+			return ErrJobNotFound
+		}
+
+		job = &Job{}
+		return json.Unmarshal(data, job)
+	})
+
+	return job, err
+}
+
+// Returns every job with the passed in status, in the order they were submitted,
+// or every job if status is empty.
+func (q *Queue) Jobs(status JobStatus) ([]*Job, error) {
+	var jobs []*Job
+	err := q.db.View(func(tx *bolt.Tx) error {
+		return forEachJob(tx.Bucket(jobsBucket), func(job *Job) error {
+			if status == "" || job.Status == status {
+				jobs = append(jobs, job)
+			}
+			return nil
+		})
+	})
+
+	return jobs, err
+}
+
+// Geocodes every Pending job, retrying those that fail for a transient reason, each after waiting its
+// Backoff, until they succeed or have been tried MaxAttempts times, and returns once no job is Pending.  Returns early, leaving
+// the remaining jobs Pending for a later Run, if the passed in context is done or if the Geocoder
+// reports that its quota is spent, with an error matching the context's error or geo.ErrQuota.
+func (q *Queue) Run(ctx context.Context) error {
+	q.running.Lock()
+	defer q.running.Unlock()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var tick <-chan time.Time
+	if q.Interval > 0 {
+		ticker := time.NewTicker(q.Interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		pending, err := q.Jobs(Pending)
+		if err != nil {
+			return err
+		}
+
+		if len(pending) == 0 {
+			return nil
+		}
+
+		// Jobs waiting out their backoff are left for a later pass; if every job is, wait for the first.
+		now := q.now()
+		var due []*Job
+		var next time.Time
+		for _, job := range pending {
+			if !job.NextAttempt.After(now) {
+				due = append(due, job)
+			} else if next.IsZero() || job.NextAttempt.Before(next) {
+				next = job.NextAttempt
+			}
+		}
+
+		if len(due) == 0 {
+			timer := time.NewTimer(next.Sub(now))
+			select {
+			case <-timer.C:
+				continue
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			}
+		}
+
+		jobs := make(chan *Job)
+		var wg sync.WaitGroup
+		var once sync.Once
+		var stopErr error
+		for i := 0; i < q.workers(); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for job := range jobs {
+					if tick != nil {
+						select {
+						case <-tick:
+						case <-ctx.Done():
+							continue
+						}
+					}
+
+					if err := q.process(ctx, job); err != nil {
+						once.Do(func() {
+							stopErr = err
+							cancel()
+						})
+					}
+				}
+			}()
+		}
+
+	feed:
+		for _, job := range due {
+			select {
+			case jobs <- job:
+			case <-ctx.Done():
+				break feed
+			}
+		}
+		close(jobs)
+		wg.Wait()
+
+		if stopErr != nil {
+			return stopErr
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+// Geocodes the passed in job and stores its outcome.  Returns an error only if the Run should stop:
+// the job couldn't be stored, or the Geocoder's quota is spent.
+func (q *Queue) process(ctx context.Context, job *Job) error {
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	job.Status = Running
+	job.Attempts++
+	if err := q.put(job); err != nil {
+		return err
+	}
+
+	p, err := q.Geocoder.Geocode(job.Query)
+	job.NextAttempt = time.Time{}
+	switch {
+	case err == nil:
+		job.Status = Done
+		job.Point = p
+		job.Error = ""
+	case errors.Is(err, geo.ErrQuota):
+		// The attempt doesn't count, since the provider never tried.
+		job.Status = Pending
+		job.Attempts--
+		job.Error = err.Error()
+		if putErr := q.put(job); putErr != nil {
+			return putErr
+		}
+		return err
+	case errors.Is(err, geo.ErrNotFound), errors.Is(err, geo.ErrBadRequest), job.Attempts >= q.maxAttempts():
+		job.Status = Failed
+		job.Error = err.Error()
+	default:
+		job.Status = Pending
+		job.Error = err.Error()
+		job.NextAttempt = q.now().UTC().Add(q.backoff(job.Attempts))
+	}
+
+	return q.put(job)
+}
+
+// Stores the passed in job, marking it updated.
+func (q *Queue) put(job *Job) error {
+	job.Updated = q.now().UTC()
+	return q.db.Update(func(tx *bolt.Tx) error {
+		return putJob(tx.Bucket(jobsBucket), job)
+	})
+}
+
+func (q *Queue) workers() int {
+	if q.Workers <= 0 {
+		return DEFAULT_WORKERS
+	}
+
+	return q.Workers
+}
+
+// Returns the time a job that has failed the passed in number of attempts waits before the next.
+func (q *Queue) backoff(attempts int) time.Duration {
+	backoff, maxBackoff := q.Backoff, q.MaxBackoff
+	if backoff <= 0 {
+		backoff = DEFAULT_BACKOFF
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = DEFAULT_MAX_BACKOFF
+	}
+
+	for i := 1; i < attempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, maxBackoff)
+}
+
+func (q *Queue) maxAttempts() int {
+	if q.MaxAttempts <= 0 {
+		return DEFAULT_MAX_ATTEMPTS
+	}
+
+	return q.MaxAttempts
+}
+
+// Stores the passed in job in the passed in bucket.
+func putJob(b *bolt.Bucket, job *Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	return b.Put([]byte(job.ID), data)
+}
+
+// Calls fn with every job in the passed in bucket, in the order they were submitted.
+func forEachJob(b *bolt.Bucket, fn func(job *Job) error) error {
+	return b.ForEach(func(k, v []byte) error {
+		job := &Job{}
+		if err := json.Unmarshal(v, job); err != nil {
+			return fmt.Errorf("geoqueue: job %s: %v", k, err)
+		}
+
+		return fn(job)
+	})
+}
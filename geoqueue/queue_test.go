@@ -0,0 +1,196 @@
+package geoqueue
+
+import (
+	"context"
+	"errors"
+	"github.com/kellydunn/golang-geo"
+	"github.com/kellydunn/golang-geo/geotest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Returns a new Queue stored in a temporary directory, closed once the test is over.
+func openTestQueue(t *testing.T, g geo.Geocoder) (*Queue, string) {
+	path := filepath.Join(t.TempDir(), "queue.db")
+	q, err := Open(path, g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { q.Close() })
+
+	return q, path
+}
+
+// Ensures that jobs are geocoded, retried after transient failures, failed when they can't be,
+// and that their outcomes survive reopening the queue.
+func TestQueueRun(t *testing.T) {
+	sfo := geo.NewPoint(37.615223, -122.389979)
+	down := &geo.ProviderError{Provider: "google", Kind: geo.ErrProviderDown, Status: "503 Service Unavailable"}
+	mock := geotest.NewMockGeocoder().
+		AddGeocode("SFO", sfo, nil).
+		AddGeocode("JFK", nil, down).
+		AddGeocode("JFK", geo.NewPoint(40.641311, -73.778139), nil).
+		AddGeocode("Atlantis", nil, geo.ErrNotFound).
+		AddGeocode("Down", nil, down)
+
+	q, path := openTestQueue(t, mock)
+	q.Workers = 2
+	q.Backoff = time.Millisecond
+	ids, err := q.Submit("SFO", "JFK", "Atlantis", "Down")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := q.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	q.Close()
+
+	q, err = Open(path, mock)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		status   JobStatus
+		attempts int
+	}{{Done, 1}, {Done, 2}, {Failed, 1}, {Failed, DEFAULT_MAX_ATTEMPTS}}
+	for i, id := range ids {
+		job, err := q.Job(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if job.Status != expected[i].status || job.Attempts != expected[i].attempts {
+			t.Errorf("Expected %s to be %s after %d attempts, got %s after %d", job.Query, expected[i].status, expected[i].attempts, job.Status, job.Attempts)
+		}
+	}
+
+	if job, _ := q.Job(ids[0]); job.Point == nil || job.Point.Lat() != sfo.Lat() {
+		t.Errorf("Expected %v, got %v", sfo, job.Point)
+	}
+
+	if failed, _ := q.Jobs(Failed); len(failed) != 2 || failed[0].Query != "Atlantis" {
+		t.Errorf("Expected Atlantis and Down to fail, got %v", failed)
+	}
+
+	if _, err := q.Job("missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("Expected %v, got %v", ErrJobNotFound, err)
+	}
+}
+
+// Ensures that a spent quota stops the run, leaving jobs pending for the next.
+func TestQueueRunQuota(t *testing.T) {
+	quota := geo.NewQuota()
+	quota.SetDailyBudget("mock", 1)
+
+	mock := geotest.NewMockGeocoder().AddGeocode("SFO", geo.NewPoint(37.615223, -122.389979), nil).AddGeocode("JFK", geo.NewPoint(40.641311, -73.778139), nil)
+	q, _ := openTestQueue(t, &quotaGeocoder{Geocoder: mock, quota: quota})
+	q.Workers = 1
+	q.Submit("SFO", "JFK")
+
+	if err := q.Run(context.Background()); !errors.Is(err, geo.ErrQuota) {
+		t.Errorf("Expected %v, got %v", geo.ErrQuota, err)
+	}
+
+	if pending, _ := q.Jobs(Pending); len(pending) != 1 || pending[0].Query != "JFK" || pending[0].Attempts != 0 {
+		t.Errorf("Expected JFK to be left pending, got %v", pending)
+	}
+}
+
+// Ensures that workers wait the Interval between requests.
+func TestQueueRunInterval(t *testing.T) {
+	mock := geotest.NewMockGeocoder().AddGeocode("SFO", geo.NewPoint(37.615223, -122.389979), nil)
+	q, _ := openTestQueue(t, mock)
+	q.Interval = 20 * time.Millisecond
+	q.Submit("SFO", "SFO", "SFO", "SFO")
+
+	start := time.Now()
+	if err := q.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if elapsed := time.Since(start); elapsed < 4*q.Interval {
+		t.Errorf("Expected at least %v, got %v", 4*q.Interval, elapsed)
+	}
+}
+
+// Ensures that a job failing while its provider is down is retried after a backoff doubling with each
+// failure, rather than at once, and is geocoded once the provider recovers.
+func TestQueueRunBackoff(t *testing.T) {
+	sfo := geo.NewPoint(37.615223, -122.389979)
+	g := &recoveringGeocoder{point: sfo, failures: 3}
+	q, _ := openTestQueue(t, g)
+	q.MaxAttempts = 5
+	q.Backoff = 20 * time.Millisecond
+	ids, _ := q.Submit("SFO")
+
+	if err := q.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if job, _ := q.Job(ids[0]); job.Status != Done || job.Attempts != 4 || !job.NextAttempt.IsZero() {
+		t.Errorf("Expected SFO to be done after 4 attempts, got %s after %d, next at %v", job.Status, job.Attempts, job.NextAttempt)
+	}
+
+	for i := 1; i < len(g.calls); i++ {
+		expected := q.Backoff << (i - 1)
+		if waited := g.calls[i].Sub(g.calls[i-1]); waited < expected {
+			t.Errorf("Expected attempt %d to wait at least %v, waited %v", i+1, expected, waited)
+		}
+	}
+
+	q.MaxBackoff = 30 * time.Millisecond
+	if backoff := q.backoff(10); backoff != q.MaxBackoff {
+		t.Errorf("Expected the backoff to be capped at %v, got %v", q.MaxBackoff, backoff)
+	}
+}
+
+// Ensures that a run waiting out a job's backoff stops when its context is done.
+func TestQueueRunBackoffCancel(t *testing.T) {
+	q, _ := openTestQueue(t, &recoveringGeocoder{failures: 1})
+	q.Backoff = time.Minute
+	ids, _ := q.Submit("SFO")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := q.Run(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected %v, got %v", context.DeadlineExceeded, err)
+	}
+
+	if job, _ := q.Job(ids[0]); job.Status != Pending || job.Attempts != 1 || job.NextAttempt.Before(time.Now().Add(59*time.Second)) {
+		t.Errorf("Expected SFO to be pending a retry in a minute, got %s after %d, next at %v", job.Status, job.Attempts, job.NextAttempt)
+	}
+}
+
+// A Geocoder whose provider is down for its first failures requests, recording when each was made.
+type recoveringGeocoder struct {
+	geo.Geocoder
+	point    *geo.Point
+	failures int
+	calls    []time.Time
+}
+
+func (g *recoveringGeocoder) Geocode(query string, opts ...geo.QueryOption) (*geo.Point, error) {
+	g.calls = append(g.calls, time.Now())
+	if len(g.calls) <= g.failures {
+		return nil, &geo.ProviderError{Provider: "mock", Kind: geo.ErrProviderDown, Status: "503 Service Unavailable"}
+	}
+
+	return g.point, nil
+}
+
+// A Geocoder spending a Quota before every request.
+type quotaGeocoder struct {
+	geo.Geocoder
+	quota *geo.Quota
+}
+
+func (g *quotaGeocoder) Geocode(query string, opts ...geo.QueryOption) (*geo.Point, error) {
+	if err := g.quota.Spend("mock"); err != nil {
+		return nil, err
+	}
+
+	return g.Geocoder.Geocode(query, opts...)
+}
@@ -0,0 +1,313 @@
+// Package geoserver exposes golang-geo as a small JSON over HTTP service, so that it
+// can be deployed as a sidecar next to applications written in other languages.
+//
+// The endpoints are:
+//
+//	GET /geocode?q=ADDRESS                      {"point": {"lat": ..., "lng": ...}}
+//	GET /reverse?lat=LAT&lng=LNG                {"address": "..."}
+//	GET /distance?from=LAT,LNG&to=LAT,LNG       {"distance": KM}
+//	GET /within?lat=LAT&lng=LNG&radius=KM       {"results": [{"distance": KM, "feature": {...}}]}
+//	GET /healthz                                {"status": "ok"}
+//
+// /healthz responds 503 if the geocoder or any of the Server's HealthChecks fails its health check,
+// for use as a readiness probe.
+//
+// Errors are reported as {"error": "..."} with a 4xx or 5xx status.
+package geoserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/kellydunn/golang-geo"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The largest radius, in kilometers, that /within accepts unless the Server says otherwise.
+const DEFAULT_MAX_RADIUS = 100.0
+
+// How long ListenAndServe waits for requests in flight to finish once it is asked to stop.
+const DEFAULT_SHUTDOWN_TIMEOUT = 10 * time.Second
+
+// A Server answers geocoding and nearby search requests over HTTP.
+type Server struct {
+	// Answers /geocode and /reverse.  Those endpoints respond 404 when nil.
+	Geocoder geo.Geocoder
+
+	// Answers /within.  That endpoint responds 404 when nil.
+	// Requests search it concurrently; call its Build once places have been inserted, so that they're indexed.
+	Places *geo.KDTree[*geo.Feature]
+
+	// The largest radius, in kilometers, /within accepts.  Defaults to DEFAULT_MAX_RADIUS.
+	MaxRadius float64
+
+	// How long to wait for requests in flight when shutting down.  Defaults to DEFAULT_SHUTDOWN_TIMEOUT.
+	ShutdownTimeout time.Duration
+
+	// Other dependencies /healthz checks, such as a SQLMapper, by name.
+	// The Geocoder is checked too if it is a geo.HealthChecker.
+	HealthChecks map[string]geo.HealthChecker
+}
+
+// Creates and returns a pointer to a new Server answering from the passed in geocoder and places.
+// Either may be nil to disable the endpoints that use it.
+func NewServer(g geo.Geocoder, places *geo.KDTree[*geo.Feature]) *Server {
+	return &Server{Geocoder: g, Places: places}
+}
+
+// Returns an http.Handler serving the Server's endpoints.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/geocode", s.get(s.geocode))
+	mux.HandleFunc("/reverse", s.get(s.reverse))
+	mux.HandleFunc("/distance", s.get(s.distance))
+	mux.HandleFunc("/within", s.get(s.within))
+	mux.HandleFunc("/healthz", s.get(s.health))
+	return mux
+}
+
+// Serves the Server's endpoints on the passed in address until the passed in context is done,
+// then stops accepting connections and waits up to ShutdownTimeout for requests in flight.
+// Returns nil after a graceful shutdown.
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(ctx, l)
+}
+
+// Serves the Server's endpoints on the passed in listener until the passed in context is done,
+// shutting down as ListenAndServe does.
+func (s *Server) Serve(ctx context.Context, l net.Listener) error {
+	srv := &http.Server{Handler: s.Handler()}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- srv.Serve(l)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	timeout := s.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = DEFAULT_SHUTDOWN_TIMEOUT
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+// An error to report to the client with the passed in HTTP status.
+type httpError struct {
+	status  int
+	message string
+}
+
+func (e *httpError) Error() string {
+	return e.message
+}
+
+// Returns an httpError with the passed in status and formatted message.
+func errorf(status int, format string, args ...interface{}) error {
+	return &httpError{status: status, message: fmt.Sprintf(format, args...)}
+}
+
+// Wraps an endpoint so that it only answers GET requests, and writes its
+// result or error as JSON.
+func (s *Server) get(endpoint func(r *http.Request) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+			return
+		}
+
+		body, err := endpoint(r)
+		if err != nil {
+			status := http.StatusBadGateway
+			var he *httpError
+			switch {
+			case errors.As(err, &he):
+				status = he.status
+			case errors.Is(err, geo.ErrNotFound):
+				status = http.StatusNotFound
+			case errors.Is(err, geo.ErrBadRequest):
+				status = http.StatusBadRequest
+			case errors.Is(err, geo.ErrQuota):
+				status = http.StatusServiceUnavailable
+			}
+
+			writeJSON(w, status, map[string]string{"error": err.Error()})
+			return
+		}
+
+		writeJSON(w, http.StatusOK, body)
+	}
+}
+
+// Writes the passed in body as JSON with the passed in status.
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// Returns the point named by the lat and lng query parameters.
+func pointParam(r *http.Request) (*geo.Point, error) {
+	q := r.URL.Query()
+	return parsePoint(q.Get("lat") + "," + q.Get("lng"))
+}
+
+// Parses a point written as "lat,lng", rejecting coordinates that are out of range.
+func parsePoint(s string) (*geo.Point, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return nil, errorf(http.StatusBadRequest, "expected a point as lat,lng, got %q", s)
+	}
+
+	lat, latErr := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	lng, lngErr := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if latErr != nil || lngErr != nil || lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		return nil, errorf(http.StatusBadRequest, "invalid point %q", s)
+	}
+
+	return geo.NewPoint(lat, lng), nil
+}
+
+func (s *Server) geocode(r *http.Request) (interface{}, error) {
+	if s.Geocoder == nil {
+		return nil, errorf(http.StatusNotFound, "geocoding is not configured")
+	}
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		return nil, errorf(http.StatusBadRequest, "missing query parameter q")
+	}
+
+	p, err := s.Geocoder.Geocode(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]*geo.Point{"point": p}, nil
+}
+
+func (s *Server) reverse(r *http.Request) (interface{}, error) {
+	if s.Geocoder == nil {
+		return nil, errorf(http.StatusNotFound, "geocoding is not configured")
+	}
+
+	p, err := pointParam(r)
+	if err != nil {
+		return nil, err
+	}
+
+	address, err := s.Geocoder.ReverseGeocode(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{"address": address}, nil
+}
+
+func (s *Server) health(r *http.Request) (interface{}, error) {
+	checks := make(map[string]geo.HealthChecker, len(s.HealthChecks)+1)
+	for name, h := range s.HealthChecks {
+		checks[name] = h
+	}
+	if h, ok := s.Geocoder.(geo.HealthChecker); ok {
+		checks["geocoder"] = h
+	}
+
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var failures []string
+	for _, name := range names {
+		if err := checks[name].HealthCheck(r.Context()); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+
+	if len(failures) > 0 {
+		return nil, errorf(http.StatusServiceUnavailable, "%s", strings.Join(failures, "; "))
+	}
+
+	return map[string]string{"status": "ok"}, nil
+}
+
+func (s *Server) distance(r *http.Request) (interface{}, error) {
+	q := r.URL.Query()
+	from, err := parsePoint(q.Get("from"))
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := parsePoint(q.Get("to"))
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]float64{"distance": from.GreatCircleDistance(to)}, nil
+}
+
+// A single place found by /within.
+type withinResult struct {
+	Distance float64      `json:"distance"`
+	Feature  *geo.Feature `json:"feature"`
+}
+
+func (s *Server) within(r *http.Request) (interface{}, error) {
+	if s.Places == nil {
+		return nil, errorf(http.StatusNotFound, "nearby search is not configured")
+	}
+
+	p, err := pointParam(r)
+	if err != nil {
+		return nil, err
+	}
+
+	maxRadius := s.MaxRadius
+	if maxRadius <= 0 {
+		maxRadius = DEFAULT_MAX_RADIUS
+	}
+
+	radius, err := strconv.ParseFloat(r.URL.Query().Get("radius"), 64)
+	if err != nil || radius < 0 || radius > maxRadius {
+		return nil, errorf(http.StatusBadRequest, "radius must be a number of kilometers between 0 and %v", maxRadius)
+	}
+
+	found := s.Places.Within(p, radius)
+
+	results := make([]withinResult, len(found))
+	for i, f := range found {
+		results[i] = withinResult{Distance: f.Distance, Feature: f.Value}
+	}
+
+	return map[string][]withinResult{"results": results}, nil
+}
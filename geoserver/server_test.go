@@ -0,0 +1,178 @@
+package geoserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/kellydunn/golang-geo"
+	"github.com/kellydunn/golang-geo/geotest"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Issues a request to the passed in handler and decodes its JSON response.
+func getJSON(t *testing.T, h http.Handler, method, target string) (int, map[string]interface{}) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected a JSON response from %s, got %q", target, rec.Body.String())
+	}
+
+	return rec.Code, body
+}
+
+// Ensures that /geocode and /reverse answer from the configured geocoder.
+func TestGeocodeAndReverse(t *testing.T) {
+	sfo := geo.NewPoint(37.615223, -122.389979)
+	g := geotest.NewMockGeocoder().
+		AddGeocode("sfo", sfo, nil).
+		AddGeocode("down", nil, errors.New("provider unavailable")).
+		AddGeocode("nowhere", nil, &geo.ProviderError{Provider: "google", Kind: geo.ErrNotFound, Status: "ZERO_RESULTS"}).
+		AddReverseGeocode(sfo, "San Francisco International Airport", nil)
+	h := NewServer(g, nil).Handler()
+
+	status, body := getJSON(t, h, "GET", "/geocode?q=sfo")
+	point, _ := body["point"].(map[string]interface{})
+	if status != http.StatusOK || point["lat"] != 37.615223 || point["lng"] != -122.389979 {
+		t.Errorf("Expected the airport's location, got %d: %v", status, body)
+	}
+
+	status, body = getJSON(t, h, "GET", "/reverse?lat=37.615223&lng=-122.389979")
+	if status != http.StatusOK || body["address"] != "San Francisco International Airport" {
+		t.Errorf("Expected the airport's address, got %d: %v", status, body)
+	}
+
+	if status, body := getJSON(t, h, "GET", "/geocode?q=down"); status != http.StatusBadGateway || body["error"] != "provider unavailable" {
+		t.Errorf("Expected a 502 carrying the provider's error, got %d: %v", status, body)
+	}
+
+	if status, _ := getJSON(t, h, "GET", "/geocode?q=nowhere"); status != http.StatusNotFound {
+		t.Errorf("Expected a 404 when the provider finds nothing, got %d", status)
+	}
+
+	if status, _ := getJSON(t, h, "GET", "/geocode"); status != http.StatusBadRequest {
+		t.Errorf("Expected a 400 without a query, got %d", status)
+	}
+
+	if status, _ := getJSON(t, h, "GET", "/reverse?lat=100&lng=0"); status != http.StatusBadRequest {
+		t.Errorf("Expected a 400 for an out of range point, got %d", status)
+	}
+
+	if status, _ := getJSON(t, h, "POST", "/geocode?q=sfo"); status != http.StatusMethodNotAllowed {
+		t.Errorf("Expected a 405 for a POST, got %d", status)
+	}
+
+	if status, _ := getJSON(t, h, "GET", "/within?lat=0&lng=0&radius=1"); status != http.StatusNotFound {
+		t.Errorf("Expected a 404 for nearby search without places, got %d", status)
+	}
+}
+
+// Ensures that /distance returns the great circle distance in kilometers.
+func TestDistance(t *testing.T) {
+	h := NewServer(nil, nil).Handler()
+
+	status, body := getJSON(t, h, "GET", "/distance?from=0,0&to=0,1")
+	if d, _ := body["distance"].(float64); status != http.StatusOK || d < 111.19 || d > 111.2 {
+		t.Errorf("Expected a distance of 111.19km, got %d: %v", status, body)
+	}
+
+	if status, _ := getJSON(t, h, "GET", "/distance?from=0,0"); status != http.StatusBadRequest {
+		t.Errorf("Expected a 400 without a destination, got %d", status)
+	}
+}
+
+// A HealthChecker that fails with a fixed error.
+type stubHealthChecker struct {
+	err error
+}
+
+func (s *stubHealthChecker) HealthCheck(ctx context.Context) error {
+	return s.err
+}
+
+// Ensures that /healthz reports the health of the Server's dependencies.
+func TestHealth(t *testing.T) {
+	db := &stubHealthChecker{}
+	s := NewServer(geotest.NewMockGeocoder(), nil)
+	s.HealthChecks = map[string]geo.HealthChecker{"db": db}
+	h := s.Handler()
+
+	if status, body := getJSON(t, h, "GET", "/healthz"); status != http.StatusOK || body["status"] != "ok" {
+		t.Errorf("Expected a healthy server, got %d: %v", status, body)
+	}
+
+	db.err = errors.New("connection refused")
+	if status, body := getJSON(t, h, "GET", "/healthz"); status != http.StatusServiceUnavailable || body["error"] != "db: connection refused" {
+		t.Errorf("Expected a 503 naming the failed dependency, got %d: %v", status, body)
+	}
+}
+
+// Ensures that /within returns the places within the radius, nearest first.
+func TestWithin(t *testing.T) {
+	places := geo.NewKDTree[*geo.Feature]()
+	places.Insert(geo.NewPoint(0, 0.2), geo.NewFeature(geo.NewPoint(0, 0.2)).Set("name", "far"))
+	places.Insert(geo.NewPoint(0, 0.1), geo.NewFeature(geo.NewPoint(0, 0.1)).Set("name", "near"))
+	places.Insert(geo.NewPoint(10, 10), geo.NewFeature(geo.NewPoint(10, 10)).Set("name", "away"))
+	places.Build()
+
+	s := NewServer(nil, places)
+	s.MaxRadius = 50
+	h := s.Handler()
+
+	status, body := getJSON(t, h, "GET", "/within?lat=0&lng=0&radius=30")
+	results, _ := body["results"].([]interface{})
+	if status != http.StatusOK || len(results) != 2 {
+		t.Fatalf("Expected 2 places, got %d: %v", status, body)
+	}
+
+	first := results[0].(map[string]interface{})
+	properties := first["feature"].(map[string]interface{})["properties"].(map[string]interface{})
+	if properties["name"] != "near" {
+		t.Errorf("Expected the nearest place first, got %v", first)
+	}
+
+	if status, _ := getJSON(t, h, "GET", "/within?lat=0&lng=0&radius=51"); status != http.StatusBadRequest {
+		t.Errorf("Expected a 400 for a radius over the maximum, got %d", status)
+	}
+}
+
+// Ensures that Serve stops cleanly once its context is cancelled.
+func TestServeShutdown(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- NewServer(nil, nil).Serve(ctx, l)
+	}()
+
+	res, err := http.Get("http://" + l.Addr().String() + "/distance?from=0,0&to=1,1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("Expected a 200 from the running server, got %d", res.StatusCode)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected a graceful shutdown, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected Serve to return after its context was cancelled")
+	}
+}
@@ -0,0 +1,369 @@
+// Package geostream enriches a stream of location events: each event consumed from a message broker
+// such as Kafka or NATS is reverse geocoded, checked against a GeofenceEngine and given a geohash,
+// then republished.
+//
+// geostream depends on no broker client.  A Source and a Sink adapt whichever client an application
+// already uses, usually in a few lines with SourceFunc and SinkFunc.  With NATS, for instance:
+//
+//	sub, _ := nc.SubscribeSync("locations")
+//	src := geostream.SourceFunc(func(ctx context.Context) (*geostream.Message, error) {
+//		m, err := sub.NextMsgWithContext(ctx)
+//		if err != nil {
+//			return nil, err
+//		}
+//		return &geostream.Message{Value: m.Data}, nil
+//	})
+//	dst := geostream.SinkFunc(func(ctx context.Context, m *geostream.Message) error {
+//		return nc.Publish("locations.enriched", m.Value)
+//	})
+//
+// and with Kafka, a Source whose Messages Ack by committing their offset gives at-least-once delivery.
+package geostream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/kellydunn/golang-geo"
+	"io"
+	"sync"
+	"time"
+)
+
+// The number of events a Processor enriches together unless told otherwise.
+const DEFAULT_BATCH_SIZE = 64
+
+// The longest a Processor waits to fill a batch before enriching what it has, unless told otherwise.
+const DEFAULT_BATCH_WAIT = 100 * time.Millisecond
+
+// The number of events of a batch a Processor reverse geocodes at once unless told otherwise.
+const DEFAULT_WORKERS = 8
+
+// The precision of the geohashes a Processor adds unless told otherwise.
+const DEFAULT_GEOHASH_PRECISION = 7
+
+// A Message is a single message consumed from or published to a broker.
+type Message struct {
+	// The message's key, such as a Kafka partition key.  Enriched events are published
+	// with the key of the message they were consumed from.
+	Key []byte
+
+	// The message's payload: a JSON LocationEvent when consumed, a JSON EnrichedEvent when published.
+	Value []byte
+
+	// If set, called once the message has been processed and its enriched event published,
+	// or once it has been dropped for being malformed, e.g. to commit a Kafka offset.
+	Ack func() error
+}
+
+// A Source consumes messages from a broker.
+type Source interface {
+	// Returns the next message, blocking until there is one.  Returns io.EOF once the source is exhausted.
+	Receive(ctx context.Context) (*Message, error)
+}
+
+// A Sink publishes messages to a broker.
+type Sink interface {
+	Publish(ctx context.Context, m *Message) error
+}
+
+// A SourceFunc is a function that acts as a Source.
+type SourceFunc func(ctx context.Context) (*Message, error)
+
+func (f SourceFunc) Receive(ctx context.Context) (*Message, error) {
+	return f(ctx)
+}
+
+// A SinkFunc is a function that acts as a Sink.
+type SinkFunc func(ctx context.Context, m *Message) error
+
+func (f SinkFunc) Publish(ctx context.Context, m *Message) error {
+	return f(ctx, m)
+}
+
+// A LocationEvent reports a subject, such as a vehicle or a phone, at a position.
+type LocationEvent struct {
+	Subject string    `json:"subject"`
+	Lat     float64   `json:"lat"`
+	Lng     float64   `json:"lng"`
+	Time    time.Time `json:"time"`
+}
+
+// A Crossing is a geofence boundary a subject crossed to reach the position of an event.
+type Crossing struct {
+	Type    string `json:"type"`
+	FenceID string `json:"fence_id"`
+}
+
+// An EnrichedEvent is a LocationEvent along with what a Processor found out about its position.
+type EnrichedEvent struct {
+	LocationEvent
+
+	Geohash string `json:"geohash"`
+
+	// The address of the position, unless the Processor has no Geocoder or reverse geocoding failed.
+	Address string `json:"address,omitempty"`
+
+	// Why reverse geocoding failed, if it did.
+	AddressError string `json:"address_error,omitempty"`
+
+	// The geofences the subject is inside, and those it entered or left to get there.
+	Fences    []string   `json:"fences,omitempty"`
+	Crossings []Crossing `json:"crossings,omitempty"`
+}
+
+// A Processor consumes LocationEvents from a Source, enriches them, and publishes EnrichedEvents to a Sink.
+// Events are enriched in batches: the events of a batch are reverse geocoded concurrently, each distinct
+// position once, while geofences are updated in the order events arrived.  Give the Processor a
+// geo.CachedGeocoder, or a geo.CoalescingGeocoder, to avoid reverse geocoding positions seen in earlier batches.
+type Processor struct {
+	// If set, the Geocoder events are reverse geocoded with.
+	Geocoder geo.Geocoder
+
+	// If set, the GeofenceEngine following each event's subject.
+	Fences *geo.GeofenceEngine
+
+	// The precision of the geohashes added to events.  Defaults to DEFAULT_GEOHASH_PRECISION.
+	GeohashPrecision int
+
+	// The most events enriched together.  Defaults to DEFAULT_BATCH_SIZE.
+	BatchSize int
+
+	// The longest to wait to fill a batch.  Defaults to DEFAULT_BATCH_WAIT.
+	BatchWait time.Duration
+
+	// The number of positions of a batch reverse geocoded at once.  Defaults to DEFAULT_WORKERS.
+	Workers int
+
+	// If set, called with every message dropped because it isn't a LocationEvent.
+	// Dropped messages are acknowledged, so that they aren't redelivered.
+	OnError func(m *Message, err error)
+}
+
+// Enriches the passed in events, in order.
+func (p *Processor) Enrich(events []*LocationEvent) []*EnrichedEvent {
+	enriched := make([]*EnrichedEvent, len(events))
+	for i, e := range events {
+		enriched[i] = &EnrichedEvent{LocationEvent: *e, Geohash: geo.EncodeGeohash(e.Lat, e.Lng, p.geohashPrecision())}
+	}
+
+	if p.Fences != nil {
+		for _, e := range enriched {
+			point := geo.NewPoint(e.Lat, e.Lng)
+			var crossings []geo.GeofenceEvent
+			if e.Time.IsZero() {
+				crossings, _ = p.Fences.Update(e.Subject, point)
+			} else {
+				crossings, _ = p.Fences.UpdateFix(e.Subject, &geo.TimedPoint{Point: point, Time: e.Time})
+			}
+
+			for _, c := range crossings {
+				e.Crossings = append(e.Crossings, Crossing{Type: c.Type.String(), FenceID: c.FenceID})
+			}
+			e.Fences = p.Fences.FencesOf(e.Subject)
+		}
+	}
+
+	if p.Geocoder != nil {
+		p.reverseGeocode(enriched)
+	}
+
+	return enriched
+}
+
+// The position of an event, by which events are reverse geocoded.
+type position struct{ lat, lng float64 }
+
+// Reverse geocodes each distinct position of the passed in events once, Workers at a time.
+func (p *Processor) reverseGeocode(events []*EnrichedEvent) {
+	byPosition := make(map[position][]*EnrichedEvent)
+	var positions []position
+	for _, e := range events {
+		pos := position{e.Lat, e.Lng}
+		if byPosition[pos] == nil {
+			positions = append(positions, pos)
+		}
+		byPosition[pos] = append(byPosition[pos], e)
+	}
+
+	work := make(chan position)
+	var wg sync.WaitGroup
+	for i := 0; i < min(p.workers(), len(positions)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for pos := range work {
+				address, err := p.Geocoder.ReverseGeocode(geo.NewPoint(pos.lat, pos.lng))
+				for _, e := range byPosition[pos] {
+					if err != nil {
+						e.AddressError = err.Error()
+					} else {
+						e.Address = address
+					}
+				}
+			}
+		}()
+	}
+
+	for _, pos := range positions {
+		work <- pos
+	}
+	close(work)
+	wg.Wait()
+}
+
+// Consumes, enriches and publishes events until the Source is exhausted, returning nil,
+// or until the passed in context is done or the Source or Sink fails, returning the error.
+// Each message is acknowledged once its enriched event has been published, in the order messages arrived.
+func (p *Processor) Run(ctx context.Context, src Source, dst Sink) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Receive on a goroutine of its own, so that batches can be cut short after BatchWait.
+	type received struct {
+		m   *Message
+		err error
+	}
+	incoming := make(chan received)
+	go func() {
+		defer close(incoming)
+		for {
+			m, err := src.Receive(ctx)
+			select {
+			case incoming <- received{m, err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		var batch []*Message
+		var stopErr error
+
+		// Wait for the first message of a batch for as long as it takes, then at most BatchWait for the rest.
+		var timer *time.Timer
+		var timeout <-chan time.Time
+
+	fill:
+		for len(batch) < p.batchSize() {
+			select {
+			case r, ok := <-incoming:
+				if !ok {
+					stopErr = ctx.Err()
+					break fill
+				}
+				if r.err != nil {
+					stopErr = r.err
+					break fill
+				}
+				if timer == nil {
+					timer = time.NewTimer(p.batchWait())
+					timeout = timer.C
+				}
+				batch = append(batch, r.m)
+			case <-timeout:
+				break fill
+			case <-ctx.Done():
+				stopErr = ctx.Err()
+				break fill
+			}
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+
+		if err := p.process(ctx, batch, dst); err != nil {
+			return err
+		}
+
+		if errors.Is(stopErr, io.EOF) {
+			return nil
+		}
+		if stopErr != nil {
+			return stopErr
+		}
+	}
+}
+
+// Enriches and publishes the passed in batch of messages, acknowledging each once it is published.
+func (p *Processor) process(ctx context.Context, batch []*Message, dst Sink) error {
+	var events []*LocationEvent
+	var messages []*Message
+	for _, m := range batch {
+		e := &LocationEvent{}
+		if err := json.Unmarshal(m.Value, e); err != nil {
+			if p.OnError != nil {
+				p.OnError(m, err)
+			}
+			if err := ack(m); err != nil {
+				return err
+			}
+			continue
+		}
+
+		events = append(events, e)
+		messages = append(messages, m)
+	}
+
+	for i, e := range p.Enrich(events) {
+		value, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+
+		if err := dst.Publish(ctx, &Message{Key: messages[i].Key, Value: value}); err != nil {
+			return err
+		}
+
+		if err := ack(messages[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Acknowledges the passed in message, if it can be.
+func ack(m *Message) error {
+	if m.Ack == nil {
+		return nil
+	}
+
+	return m.Ack()
+}
+
+func (p *Processor) geohashPrecision() int {
+	if p.GeohashPrecision <= 0 {
+		return DEFAULT_GEOHASH_PRECISION
+	}
+
+	return p.GeohashPrecision
+}
+
+func (p *Processor) batchSize() int {
+	if p.BatchSize <= 0 {
+		return DEFAULT_BATCH_SIZE
+	}
+
+	return p.BatchSize
+}
+
+func (p *Processor) batchWait() time.Duration {
+	if p.BatchWait <= 0 {
+		return DEFAULT_BATCH_WAIT
+	}
+
+	return p.BatchWait
+}
+
+func (p *Processor) workers() int {
+	if p.Workers <= 0 {
+		return DEFAULT_WORKERS
+	}
+
+	return p.Workers
+}
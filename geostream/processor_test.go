@@ -0,0 +1,94 @@
+package geostream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/kellydunn/golang-geo"
+	"github.com/kellydunn/golang-geo/geotest"
+	"io"
+	"testing"
+)
+
+// Returns a Source consuming the passed in payloads, then io.EOF, counting the messages acknowledged.
+func sliceSource(payloads []string, acked *int) Source {
+	i := 0
+	return SourceFunc(func(ctx context.Context) (*Message, error) {
+		if i == len(payloads) {
+			return nil, io.EOF
+		}
+
+		m := &Message{Key: []byte{byte(i)}, Value: []byte(payloads[i]), Ack: func() error { *acked++; return nil }}
+		i++
+		return m, nil
+	})
+}
+
+// Ensures that events are enriched with addresses, geofences and geohashes and republished in order,
+// and that malformed messages are dropped.
+func TestProcessorRun(t *testing.T) {
+	sfo := geo.NewPoint(37.615223, -122.389979)
+	mock := geotest.NewMockGeocoder().AddReverseGeocode(sfo, "San Francisco International Airport", nil)
+	fence := &geo.Geofence{ID: "sfo", Polygon: geo.NewPolygon([]*geo.Point{
+		geo.NewPoint(37.6, -122.4), geo.NewPoint(37.6, -122.37), geo.NewPoint(37.63, -122.37), geo.NewPoint(37.63, -122.4),
+	})}
+
+	var acked int
+	src := sliceSource([]string{
+		`{"subject":"van-1","lat":37.615223,"lng":-122.389979}`,
+		`not json`,
+		`{"subject":"van-1","lat":37.615223,"lng":-122.389979}`,
+		`{"subject":"van-1","lat":37.7,"lng":-122.4}`,
+	}, &acked)
+
+	var published []*EnrichedEvent
+	var keys []byte
+	dst := SinkFunc(func(ctx context.Context, m *Message) error {
+		e := &EnrichedEvent{}
+		if err := json.Unmarshal(m.Value, e); err != nil {
+			return err
+		}
+		published = append(published, e)
+		keys = append(keys, m.Key...)
+		return nil
+	})
+
+	var dropped int
+	p := &Processor{Geocoder: mock, Fences: geo.NewGeofenceEngine(fence), BatchSize: 3, OnError: func(m *Message, err error) { dropped++ }}
+	if err := p.Run(context.Background(), src, dst); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(published) != 3 || dropped != 1 || acked != 4 {
+		t.Fatalf("Expected 3 events published, 1 dropped and 4 acknowledged, got %d, %d and %d", len(published), dropped, acked)
+	}
+
+	if string(keys) != string([]byte{0, 2, 3}) {
+		t.Errorf("Expected the events published in order with their keys, got %v", keys)
+	}
+
+	first := published[0]
+	if first.Address != "San Francisco International Airport" || first.Geohash != sfo.Geohash(DEFAULT_GEOHASH_PRECISION) {
+		t.Errorf("Expected the address and geohash of SFO, got %+v", first)
+	}
+
+	if len(first.Crossings) != 1 || first.Crossings[0].Type != "enter" || len(first.Fences) != 1 || len(published[1].Crossings) != 0 {
+		t.Errorf("Expected van-1 to enter sfo once, got %+v and %+v", first, published[1])
+	}
+
+	if last := published[2]; len(last.Crossings) != 1 || last.Crossings[0].Type != "exit" || len(last.Fences) != 0 || last.AddressError == "" {
+		t.Errorf("Expected van-1 to leave sfo somewhere the mock can't reverse geocode, got %+v", last)
+	}
+}
+
+// Ensures that a failing Sink stops the Processor without acknowledging the message.
+func TestProcessorRunSinkError(t *testing.T) {
+	var acked int
+	src := sliceSource([]string{`{"subject":"van-1","lat":37.615223,"lng":-122.389979}`}, &acked)
+	down := errors.New("broker down")
+	dst := SinkFunc(func(ctx context.Context, m *Message) error { return down })
+
+	if err := (&Processor{}).Run(context.Background(), src, dst); !errors.Is(err, down) || acked != 0 {
+		t.Errorf("Expected %v without acknowledging, got %v after %d", down, err, acked)
+	}
+}
@@ -0,0 +1,44 @@
+package geotest
+
+import (
+	"github.com/kellydunn/golang-geo"
+	"testing"
+)
+
+// Returns whether or not the passed in points lie within the passed in
+// number of meters of each other, measured along the great circle.
+func PointsNear(a, b *geo.Point, meters float64) bool {
+	return a.GreatCircleDistance(b)*1000 <= meters
+}
+
+// Fails the test if got does not lie within the passed in number of meters of want.
+func AssertPointNear(t testing.TB, got, want *geo.Point, meters float64) {
+	t.Helper()
+
+	if got == nil || want == nil {
+		if got != want {
+			t.Errorf("Expected point %v, got %v", want, got)
+		}
+		return
+	}
+
+	if !PointsNear(got, want, meters) {
+		t.Errorf("Expected [%f, %f] to be within %gm of [%f, %f], but it is %.3fm away",
+			got.Lat(), got.Lng(), meters, want.Lat(), want.Lng(), got.GreatCircleDistance(want)*1000)
+	}
+}
+
+// Fails the test if the passed in slices differ in length, or if any point
+// in got does not lie within the passed in number of meters of its counterpart in want.
+func AssertPointsNear(t testing.TB, got, want []*geo.Point, meters float64) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Errorf("Expected %d points, got %d", len(want), len(got))
+		return
+	}
+
+	for i := range got {
+		AssertPointNear(t, got[i], want[i], meters)
+	}
+}
@@ -0,0 +1,53 @@
+package geotest
+
+import (
+	"fmt"
+	"github.com/kellydunn/golang-geo"
+	"testing"
+)
+
+// A testing.TB that records failures instead of failing the enclosing test.
+type recordingTB struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+// Ensures that points are compared by great circle distance in meters.
+func TestPointsNear(t *testing.T) {
+	p := geo.NewPoint(37.615223, -122.389979)
+	q := p.PointAtDistanceAndBearing(0.010, 90)
+
+	if !PointsNear(p, q, 10.5) {
+		t.Error("Expected points 10m apart to be within 10.5m of each other")
+	}
+
+	if PointsNear(p, q, 9.5) {
+		t.Error("Expected points 10m apart not to be within 9.5m of each other")
+	}
+}
+
+// Ensures that AssertPointNear and AssertPointsNear only fail when points are too far apart.
+func TestAssertPointNear(t *testing.T) {
+	p := geo.NewPoint(37.615223, -122.389979)
+	q := p.PointAtDistanceAndBearing(0.010, 90)
+
+	tb := &recordingTB{TB: t}
+	AssertPointNear(tb, q, p, 11)
+	AssertPointsNear(tb, []*geo.Point{p, q}, []*geo.Point{p, p}, 11)
+	if len(tb.failures) != 0 {
+		t.Errorf("Expected no failures, got %v", tb.failures)
+	}
+
+	AssertPointNear(tb, q, p, 1)
+	AssertPointNear(tb, nil, p, 1)
+	AssertPointsNear(tb, []*geo.Point{p}, []*geo.Point{p, q}, 1)
+	if len(tb.failures) != 3 {
+		t.Errorf("Expected 3 failures, got %v", tb.failures)
+	}
+}
@@ -0,0 +1,143 @@
+package geotest
+
+import (
+	"github.com/kellydunn/golang-geo"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// The seed the generated part of the corpora is drawn from, so that they're the same from run to run.
+const corpusSeed = 1
+
+// Returns coordinates, as latitude and longitude pairs, that code taking them tends to get wrong:
+// the poles, the antimeridian from both sides, negative zero, the smallest numbers and those
+// just out of range, infinities and NaN.
+func PointCorpus() [][2]float64 {
+	tiny := math.SmallestNonzeroFloat64
+	return [][2]float64{
+		{0, 0},
+		{math.Copysign(0, -1), math.Copysign(0, -1)},
+		{90, 0},
+		{-90, 0},
+		{90, 180},
+		{-90, -180},
+		{0, 180},
+		{0, -180},
+		{45, math.Nextafter(180, 0)},
+		{-45, math.Nextafter(-180, 0)},
+		{math.Nextafter(90, 0), 45},
+		{math.Nextafter(90, 100), 45},
+		{0, math.Nextafter(180, 200)},
+		{tiny, -tiny},
+		{37.615223, -122.389979},
+		{91, 181},
+		{-180, 360},
+		{1e300, -1e300},
+		{math.Inf(1), math.Inf(-1)},
+		{math.NaN(), 0},
+	}
+}
+
+// Adds PointCorpus to the seed corpus of the passed in fuzz test, whose targets take a latitude and a longitude:
+//
+//	func FuzzNormalize(f *testing.F) {
+//		geotest.AddPointCorpus(f)
+//		f.Fuzz(func(t *testing.T, lat, lng float64) { ... })
+//	}
+func AddPointCorpus(f *testing.F) {
+	for _, c := range PointCorpus() {
+		f.Add(c[0], c[1])
+	}
+}
+
+// Returns geometries for seeding corpora: points from PointCorpus that are on the earth, lines and polygons
+// across the antimeridian and around a pole, and generated Polygons, AntimeridianPolygons and PolarPoints.
+func GeometryCorpus() []geo.Geometry {
+	var geometries []geo.Geometry
+	for _, c := range PointCorpus() {
+		if math.Abs(c[0]) <= 90 && math.Abs(c[1]) <= 180 {
+			geometries = append(geometries, geo.NewPoint(c[0], c[1]))
+		}
+	}
+
+	geometries = append(geometries,
+		geo.Line{},
+		geo.Line{geo.NewPoint(10, 20)},
+		geo.Line{geo.NewPoint(10, 179), geo.NewPoint(11, -179), geo.NewPoint(12, 179)},
+		geo.Line{geo.NewPoint(89.9, 0), geo.NewPoint(89.9, 90), geo.NewPoint(89.9, 180), geo.NewPoint(89.9, -90)},
+		geo.NewPolygon([]*geo.Point{geo.NewPoint(0, 0), geo.NewPoint(0, 1), geo.NewPoint(1, 1), geo.NewPoint(0, 0)}),
+		geo.NewPolygon([]*geo.Point{geo.NewPoint(-1, 179), geo.NewPoint(-1, -179), geo.NewPoint(1, -179), geo.NewPoint(1, 179)}),
+		geo.NewPolygon([]*geo.Point{geo.NewPoint(89, -120), geo.NewPoint(89, 0), geo.NewPoint(89, 120)}),
+	)
+
+	r := rand.New(rand.NewSource(corpusSeed))
+	for i := 0; i < 4; i++ {
+		geometries = append(geometries,
+			Polygon{}.Generate(r, 8).Interface().(Polygon).Polygon,
+			AntimeridianPolygon{}.Generate(r, 8).Interface().(AntimeridianPolygon).Polygon,
+			PolarPoint{}.Generate(r, 0).Interface().(PolarPoint).Point,
+		)
+	}
+
+	return geometries
+}
+
+// Returns Well-Known Text for seeding corpora: GeometryCorpus written out, along with text that is valid
+// but unusual, such as PostGIS's Extended WKT, dimensions and empty geometries, and text that is invalid.
+func WKTCorpus() []string {
+	var corpus []string
+	for _, g := range GeometryCorpus() {
+		if wkt, err := geo.MarshalWKT(g); err == nil {
+			corpus = append(corpus, wkt)
+		}
+	}
+
+	return append(corpus,
+		"SRID=4326;POINT(-122.389979 37.615223)",
+		"point z (1 2 3)",
+		"POINTZM(1 2 3 4)",
+		"LINESTRING M (0 0 1, 1 1 2)",
+		"LINESTRING EMPTY",
+		"POLYGON EMPTY",
+		"POLYGON((0 0, 1 0, 1 1, 0 0), (0.2 0.2, 0.4 0.2, 0.4 0.4, 0.2 0.2))",
+		"POLYGON((0 0, 1 0, 1 1))",
+		"POINT EMPTY",
+		"POINT(1)",
+		"POINT(1 2",
+		"LINESTRING(0 0,, 1 1)",
+		"SRID=4326",
+		"CIRCULARSTRING(0 0, 1 1, 2 0)",
+		"",
+	)
+}
+
+// Adds WKTCorpus to the seed corpus of the passed in fuzz test, whose targets take a string.
+func AddWKTCorpus(f *testing.F) {
+	for _, wkt := range WKTCorpus() {
+		f.Add(wkt)
+	}
+}
+
+// Returns Well-Known Binary for seeding corpora: GeometryCorpus written out, in both WKB and PostGIS's
+// Extended WKB, along with each of them cut short, and nothing at all.
+func WKBCorpus() [][]byte {
+	var corpus [][]byte
+	for _, g := range GeometryCorpus() {
+		if wkb, err := geo.MarshalWKB(g); err == nil {
+			corpus = append(corpus, wkb, wkb[:len(wkb)/2])
+		}
+		if ewkb, err := geo.MarshalEWKB(g, geo.SQL_SRID); err == nil {
+			corpus = append(corpus, ewkb)
+		}
+	}
+
+	return append(corpus, []byte{})
+}
+
+// Adds WKBCorpus to the seed corpus of the passed in fuzz test, whose targets take a []byte.
+func AddWKBCorpus(f *testing.F) {
+	for _, wkb := range WKBCorpus() {
+		f.Add(wkb)
+	}
+}
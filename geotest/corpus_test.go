@@ -0,0 +1,86 @@
+package geotest
+
+import (
+	"github.com/kellydunn/golang-geo"
+	"math"
+	"testing"
+)
+
+// Ensures that normalizing any finite coordinates puts them on the earth.
+func FuzzPointNormalize(f *testing.F) {
+	AddPointCorpus(f)
+	f.Fuzz(func(t *testing.T, lat, lng float64) {
+		if math.IsNaN(lat) || math.IsNaN(lng) || math.Abs(lat) > 1e15 || math.Abs(lng) > 1e15 {
+			return
+		}
+
+		p := geo.NewPoint(lat, lng).Normalize()
+		if math.Abs(p.Lat()) > 90 || math.Abs(p.Lng()) > 180 {
+			t.Errorf("Expected [%g, %g] to normalize onto the earth, got %v", lat, lng, p)
+		}
+	})
+}
+
+// Ensures that any geometry decoded from Well-Known Text decodes alike once written back out.
+func FuzzUnmarshalWKT(f *testing.F) {
+	AddWKTCorpus(f)
+	f.Fuzz(func(t *testing.T, wkt string) {
+		g, err := geo.UnmarshalWKT(wkt)
+		if err != nil {
+			return
+		}
+
+		again, err := geo.MarshalWKT(g)
+		if err != nil {
+			return
+		}
+		if _, err := geo.UnmarshalWKT(again); err != nil {
+			t.Errorf("Expected %q, decoded from %q, to decode, got %v", again, wkt, err)
+		}
+	})
+}
+
+// Ensures that decoding Well-Known Binary never panics, and that geometries it decodes can be written back out.
+func FuzzUnmarshalWKB(f *testing.F) {
+	AddWKBCorpus(f)
+	f.Fuzz(func(t *testing.T, wkb []byte) {
+		g, _, err := geo.UnmarshalWKB(wkb)
+		if err != nil {
+			return
+		}
+
+		if _, err := geo.MarshalWKB(g); err != nil {
+			t.Errorf("Expected %v, decoded from %x, to encode, got %v", g, wkb, err)
+		}
+	})
+}
+
+// Ensures that the corpora cover each kind of geometry, and are the same from call to call.
+func TestGeometryCorpus(t *testing.T) {
+	kinds := map[string]int{}
+	for _, g := range GeometryCorpus() {
+		switch g.(type) {
+		case *geo.Point:
+			kinds["points"]++
+		case geo.Line:
+			kinds["lines"]++
+		case *geo.Polygon:
+			kinds["polygons"]++
+		}
+	}
+	for _, kind := range []string{"points", "lines", "polygons"} {
+		if kinds[kind] == 0 {
+			t.Errorf("Expected %s in the corpus, got %v", kind, kinds)
+		}
+	}
+
+	a, b := WKTCorpus(), WKTCorpus()
+	if len(a) != len(b) {
+		t.Fatalf("Expected %d WKT seeds, got %d", len(a), len(b))
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			t.Errorf("Expected seed %d to be %q, got %q", i, a[i], b[i])
+		}
+	}
+}
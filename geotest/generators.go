@@ -0,0 +1,93 @@
+package geotest
+
+import (
+	"github.com/kellydunn/golang-geo"
+	"math"
+	"math/rand"
+	"reflect"
+)
+
+// The furthest, in degrees of latitude, a PolarPoint lies from its pole: about 1.1km.
+const POLAR_POINT_MAX_DEGREES = 0.01
+
+// The largest distance, in kilometers, from the center of a generated polygon to any of its vertices.
+const GENERATED_POLYGON_MAX_RADIUS = 100.0
+
+// A Point is a *geo.Point that testing/quick generates anywhere on the earth, each part of it
+// as likely as any other of the same area, for use as an argument to functions passed to quick.Check:
+//
+//	quick.Check(func(p geotest.Point) bool { return p.Normalize().Lat() == p.Lat() }, nil)
+type Point struct {
+	*geo.Point
+}
+
+// Generates a random Point, for testing/quick.
+func (Point) Generate(r *rand.Rand, size int) reflect.Value {
+	lat := math.Asin(2*r.Float64()-1) * 180 / math.Pi
+	return reflect.ValueOf(Point{geo.NewPoint(lat, 360*r.Float64()-180)})
+}
+
+// A PolarPoint is a *geo.Point that testing/quick generates within POLAR_POINT_MAX_DEGREES of either pole,
+// where longitudes converge and bearings turn sharply.  One in eight lies on the pole itself.
+type PolarPoint struct {
+	*geo.Point
+}
+
+// Generates a random PolarPoint, for testing/quick.
+func (PolarPoint) Generate(r *rand.Rand, size int) reflect.Value {
+	lat := 90.0
+	if r.Intn(8) != 0 {
+		lat -= r.Float64() * POLAR_POINT_MAX_DEGREES
+	}
+	if r.Intn(2) == 0 {
+		lat = -lat
+	}
+
+	return reflect.ValueOf(PolarPoint{geo.NewPoint(lat, 360*r.Float64()-180)})
+}
+
+// A Polygon is a *geo.Polygon that testing/quick generates valid: a simple ring of three or more vertices
+// wound counterclockwise around its center, no more than GENERATED_POLYGON_MAX_RADIUS from it,
+// with up to size vertices more.  Generated polygons keep within 60 degrees of the equator
+// and clear of the antimeridian; see AntimeridianPolygon for those across it.
+type Polygon struct {
+	*geo.Polygon
+}
+
+// Generates a random Polygon, for testing/quick.
+func (Polygon) Generate(r *rand.Rand, size int) reflect.Value {
+	center := geo.NewPoint(120*r.Float64()-60, 354*r.Float64()-177)
+	return reflect.ValueOf(Polygon{starPolygon(r, center, size)})
+}
+
+// An AntimeridianPolygon is a *geo.Polygon that testing/quick generates as it does a Polygon,
+// but centered on the antimeridian, so that its ring crosses it.
+type AntimeridianPolygon struct {
+	*geo.Polygon
+}
+
+// Generates a random AntimeridianPolygon, for testing/quick.
+func (AntimeridianPolygon) Generate(r *rand.Rand, size int) reflect.Value {
+	center := geo.NewPoint(120*r.Float64()-60, 180)
+	return reflect.ValueOf(AntimeridianPolygon{starPolygon(r, center, size)})
+}
+
+// Returns a random polygon around the passed in center, with between 3 and 3+size vertices.
+// Each vertex lies at a random distance in its own sector of the circle, and no more than half way
+// across it, so that the center sees the whole ring, which is therefore simple, and lies within it.
+func starPolygon(r *rand.Rand, center *geo.Point, size int) *geo.Polygon {
+	n := 3
+	if size > 0 {
+		n += r.Intn(size + 1)
+	}
+
+	radius := 1 + r.Float64()*(GENERATED_POLYGON_MAX_RADIUS-1)
+	points := make([]*geo.Point, n)
+	for i := range points {
+		bearing := (float64(i) + r.Float64()/2) * 360 / float64(n)
+		p := center.PointAtDistanceAndBearing(radius*(0.2+0.8*r.Float64()), bearing)
+		points[n-1-i] = geo.NewPoint(p.Lat(), geo.NormalizeLng(p.Lng()))
+	}
+
+	return geo.NewPolygon(points)
+}
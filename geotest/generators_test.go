@@ -0,0 +1,114 @@
+package geotest
+
+import (
+	"github.com/kellydunn/golang-geo"
+	"math"
+	"testing"
+	"testing/quick"
+)
+
+// Returns the longitudes and latitudes of the passed in ring, with longitudes unwrapped across the antimeridian.
+func unwrap(points []*geo.Point) (x, y []float64) {
+	x, y = make([]float64, len(points)), make([]float64, len(points))
+	for i, p := range points {
+		x[i], y[i] = p.Lng(), p.Lat()
+		if i > 0 {
+			x[i] = x[i-1] + geo.LngDiff(points[i-1].Lng(), p.Lng())
+		}
+	}
+
+	return x, y
+}
+
+// Returns twice the signed area of the passed in ring in degrees, positive when it winds counterclockwise.
+func ringTurn(points []*geo.Point) float64 {
+	x, y := unwrap(points)
+	turn := 0.0
+	for i := range x {
+		j := (i + 1) % len(x)
+		turn += x[i]*y[j] - x[j]*y[i]
+	}
+
+	return turn
+}
+
+// Returns whether or not any two edges of the passed in ring, other than neighbours, cross.
+func selfIntersects(points []*geo.Point) bool {
+	n := len(points)
+	x, y := unwrap(points)
+	side := func(i, j, k int) float64 {
+		return (x[j]-x[i])*(y[k]-y[i]) - (y[j]-y[i])*(x[k]-x[i])
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 2; j < n; j++ {
+			if i == 0 && j == n-1 {
+				continue
+			}
+			a, b, c, d := i, i+1, j, (j+1)%n
+			if side(a, b, c)*side(a, b, d) < 0 && side(c, d, a)*side(c, d, b) < 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Ensures that generated points lie on the earth, spread evenly by area.
+func TestPointGenerate(t *testing.T) {
+	north := 0
+	inRange := func(p Point) bool {
+		if p.Lat() > 30 {
+			north++
+		}
+		return math.Abs(p.Lat()) <= 90 && math.Abs(p.Lng()) <= 180
+	}
+	if err := quick.Check(inRange, &quick.Config{MaxCount: 10000}); err != nil {
+		t.Error(err)
+	}
+
+	// A quarter of the earth lies north of 30 degrees.
+	if math.Abs(float64(north)/10000-0.25) > 0.02 {
+		t.Errorf("Expected a quarter of the points north of 30 degrees, got %d in 10000", north)
+	}
+}
+
+// Ensures that generated polar points lie near a pole.
+func TestPolarPointGenerate(t *testing.T) {
+	nearPole := func(p PolarPoint) bool {
+		return math.Abs(p.Lat()) >= 90-POLAR_POINT_MAX_DEGREES && math.Abs(p.Lat()) <= 90 && math.Abs(p.Lng()) <= 180
+	}
+	if err := quick.Check(nearPole, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+// Ensures that generated polygons are closed, simple rings wound counterclockwise.
+func TestPolygonGenerate(t *testing.T) {
+	valid := func(p Polygon) bool {
+		points := p.Points()
+		for _, point := range points {
+			if math.Abs(point.Lng()) > 179 || math.Abs(point.Lat()) > 61 {
+				return false
+			}
+		}
+		return p.IsClosed() && ringTurn(points) > 0 && !selfIntersects(points)
+	}
+	if err := quick.Check(valid, &quick.Config{MaxCount: 1000}); err != nil {
+		t.Error(err)
+	}
+}
+
+// Ensures that generated antimeridian polygons are valid, and cross it.
+func TestAntimeridianPolygonGenerate(t *testing.T) {
+	crosses := func(p AntimeridianPolygon) bool {
+		east, west := false, false
+		for _, point := range p.Points() {
+			east, west = east || point.Lng() > 90, west || point.Lng() < -90
+		}
+		return east && west && p.IsClosed() && ringTurn(p.Points()) > 0 && !selfIntersects(p.Points())
+	}
+	if err := quick.Check(crosses, &quick.Config{MaxCount: 1000}); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,169 @@
+package geotest
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/kellydunn/golang-geo"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// A fake Google Geocoding API that serves JSON in the same shape as the real one.
+// Set a GoogleGeocoder's BaseURL to URL to use it.
+type GoogleServer struct {
+	server *httptest.Server
+
+	// The base URL of the fake geocoding endpoint, in the same form as
+	// "https://maps.googleapis.com/maps/api/geocode/json".
+	URL string
+
+	mu       sync.Mutex
+	geocodes map[string]googleResult
+	reverses map[string]googleResult
+	requests []*http.Request
+	status   string
+}
+
+// A single result as it appears in a Google Geocoding API response.
+type googleResult struct {
+	FormattedAddress string `json:"formatted_address"`
+	Geometry         struct {
+		Location struct {
+			Lat float64 `json:"lat"`
+			Lng float64 `json:"lng"`
+		} `json:"location"`
+		LocationType string `json:"location_type"`
+	} `json:"geometry"`
+}
+
+// The body of a Google Geocoding API response.
+type googleResponse struct {
+	Results      []googleResult `json:"results"`
+	Status       string         `json:"status"`
+	ErrorMessage string         `json:"error_message,omitempty"`
+}
+
+// Starts and returns a pointer to a new GoogleServer with no results configured.
+// Callers should Close it when finished.
+func NewGoogleServer() *GoogleServer {
+	s := &GoogleServer{
+		geocodes: make(map[string]googleResult),
+		reverses: make(map[string]googleResult),
+	}
+
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.server.URL + "/maps/api/geocode/json"
+	return s
+}
+
+// Shuts down the server.
+func (s *GoogleServer) Close() {
+	s.server.Close()
+}
+
+// Configures the result returned for geocoding the passed in address.
+func (s *GoogleServer) AddGeocode(address string, p *geo.Point, formattedAddress string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.geocodes[geo.NormalizeQuery(address)] = newGoogleResult(p, formattedAddress)
+}
+
+// Configures the result returned for reverse geocoding the passed in Point.
+func (s *GoogleServer) AddReverseGeocode(p *geo.Point, formattedAddress string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reverses[pointKey(p)] = newGoogleResult(p, formattedAddress)
+}
+
+// Makes every subsequent request fail with the passed in status,
+// e.g. "OVER_QUERY_LIMIT" or "REQUEST_DENIED".  An empty status restores normal behaviour.
+func (s *GoogleServer) SetStatus(status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.status = status
+}
+
+// Returns every request the server has received so far.
+func (s *GoogleServer) Requests() []*http.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*http.Request(nil), s.requests...)
+}
+
+func (s *GoogleServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, r)
+	res := &googleResponse{Results: []googleResult{}}
+
+	switch {
+	case s.status != "":
+		res.Status = s.status
+		res.ErrorMessage = "forced by geotest"
+	case r.FormValue("address") != "":
+		if result, ok := s.geocodes[geo.NormalizeQuery(r.FormValue("address"))]; ok {
+			res.Results = append(res.Results, result)
+		}
+	case r.FormValue("latlng") != "":
+		p, err := parseLatLng(r.FormValue("latlng"))
+		if err != nil {
+			res.Status = "INVALID_REQUEST"
+			res.ErrorMessage = err.Error()
+			break
+		}
+
+		if result, ok := s.reverses[pointKey(p)]; ok {
+			res.Results = append(res.Results, result)
+		}
+	default:
+		res.Status = "INVALID_REQUEST"
+		res.ErrorMessage = "missing the address or latlng parameter"
+	}
+
+	if res.Status == "" {
+		res.Status = "OK"
+		if len(res.Results) == 0 {
+			res.Status = "ZERO_RESULTS"
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	json.NewEncoder(w).Encode(res)
+}
+
+// Returns a googleResult located at the passed in Point.
+func newGoogleResult(p *geo.Point, formattedAddress string) googleResult {
+	result := googleResult{FormattedAddress: formattedAddress}
+	result.Geometry.Location.Lat = p.Lat()
+	result.Geometry.Location.Lng = p.Lng()
+	result.Geometry.LocationType = "ROOFTOP"
+	return result
+}
+
+// Parses a "lat,lng" pair as sent in Google's latlng parameter.
+func parseLatLng(s string) (*geo.Point, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid latlng %q", s)
+	}
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return nil, err
+	}
+
+	lng, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return geo.NewPoint(lat, lng), nil
+}
@@ -0,0 +1,42 @@
+package geotest
+
+import (
+	"github.com/kellydunn/golang-geo"
+	"testing"
+)
+
+// Ensures that a GoogleGeocoder pointed at a GoogleServer receives the configured results.
+func TestGoogleServer(t *testing.T) {
+	s := NewGoogleServer()
+	defer s.Close()
+
+	sfo := geo.NewPoint(37.615223, -122.389979)
+	s.AddGeocode("San Francisco International Airport", sfo, "San Francisco International Airport, CA, USA")
+	s.AddReverseGeocode(sfo, "San Francisco International Airport, CA, USA")
+
+	g := geo.NewGoogleGeocoder(geo.WithBaseURL(s.URL))
+
+	p, err := g.Geocode("san francisco international airport")
+	if err != nil {
+		t.Fatal(err)
+	}
+	AssertPointNear(t, p, sfo, 0.1)
+
+	address, err := g.ReverseGeocode(sfo)
+	if err != nil || address != "San Francisco International Airport, CA, USA" {
+		t.Errorf("Expected the configured address, got %q (%v)", address, err)
+	}
+
+	if _, err := g.Geocode("nowhere"); err == nil {
+		t.Error("Expected an error for an address with no results")
+	}
+
+	s.SetStatus("OVER_QUERY_LIMIT")
+	if _, err := g.ReverseGeocode(sfo); err == nil {
+		t.Error("Expected an error when the server is over its query limit")
+	}
+
+	if len(s.Requests()) != 4 {
+		t.Errorf("Expected the server to receive 4 requests, got %d", len(s.Requests()))
+	}
+}
@@ -0,0 +1,127 @@
+// Package geotest provides fakes and assertion helpers that make it possible
+// to unit test code built on golang-geo without any network access.
+package geotest
+
+import (
+	"errors"
+	"fmt"
+	"github.com/kellydunn/golang-geo"
+	"sync"
+)
+
+// This is the error that a MockGeocoder returns for queries it has no script for.
+var ErrUnscripted = errors.New("geotest: no scripted response")
+
+// A single scripted response to a geocoding request.
+type geocodeResponse struct {
+	point *geo.Point
+	err   error
+}
+
+// A single scripted response to a reverse geocoding request.
+type reverseResponse struct {
+	address string
+	err     error
+}
+
+// A MockGeocoder is a geo.Geocoder that answers from scripted responses.
+// Responses for a query are returned in the order they were scripted, with the
+// last one repeating once the rest have been used up.  Queries are matched after
+// normalization (see geo.NormalizeQuery) and points to six decimal places.
+// A MockGeocoder is safe for concurrent use.
+type MockGeocoder struct {
+	mu       sync.Mutex
+	geocodes map[string][]geocodeResponse
+	reverses map[string][]reverseResponse
+	calls    []string
+}
+
+// Creates and returns a pointer to a new MockGeocoder with nothing scripted.
+func NewMockGeocoder() *MockGeocoder {
+	return &MockGeocoder{
+		geocodes: make(map[string][]geocodeResponse),
+		reverses: make(map[string][]reverseResponse),
+	}
+}
+
+// Scripts the next response to geocoding the passed in query.
+func (m *MockGeocoder) AddGeocode(query string, p *geo.Point, err error) *MockGeocoder {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := geo.NormalizeQuery(query)
+	m.geocodes[key] = append(m.geocodes[key], geocodeResponse{point: p, err: err})
+	return m
+}
+
+// Scripts the next response to reverse geocoding the passed in Point.
+func (m *MockGeocoder) AddReverseGeocode(p *geo.Point, address string, err error) *MockGeocoder {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := pointKey(p)
+	m.reverses[key] = append(m.reverses[key], reverseResponse{address: address, err: err})
+	return m
+}
+
+// Returns the next scripted response for the passed in query,
+// or ErrUnscripted if there is none.  QueryOptions are ignored.
+func (m *MockGeocoder) Geocode(query string, opts ...geo.QueryOption) (*geo.Point, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := geo.NormalizeQuery(query)
+	m.calls = append(m.calls, "geocode "+key)
+
+	responses := m.geocodes[key]
+	if len(responses) == 0 {
+		return nil, ErrUnscripted
+	}
+
+	res := responses[0]
+	if len(responses) > 1 {
+		m.geocodes[key] = responses[1:]
+	}
+
+	if res.err != nil || res.point == nil {
+		return nil, res.err
+	}
+
+	return geo.NewPoint(res.point.Lat(), res.point.Lng()), nil
+}
+
+// Returns the next scripted response for the passed in Point,
+// or ErrUnscripted if there is none.  QueryOptions are ignored.
+func (m *MockGeocoder) ReverseGeocode(p *geo.Point, opts ...geo.QueryOption) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := pointKey(p)
+	m.calls = append(m.calls, "reverse "+key)
+
+	responses := m.reverses[key]
+	if len(responses) == 0 {
+		return "", ErrUnscripted
+	}
+
+	res := responses[0]
+	if len(responses) > 1 {
+		m.reverses[key] = responses[1:]
+	}
+
+	return res.address, res.err
+}
+
+// Returns every request made so far, in order, as "geocode <query>"
+// or "reverse <lat>,<lng>" strings.
+func (m *MockGeocoder) Calls() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]string(nil), m.calls...)
+}
+
+// Returns the key used to match the passed in Point to scripted responses.
+func pointKey(p *geo.Point) string {
+	return fmt.Sprintf("%f,%f", p.Lat(), p.Lng())
+}
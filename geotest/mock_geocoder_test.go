@@ -0,0 +1,61 @@
+package geotest
+
+import (
+	"errors"
+	"github.com/kellydunn/golang-geo"
+	"reflect"
+	"testing"
+)
+
+// Ensures that a MockGeocoder satisfies geo.Geocoder.
+var _ geo.Geocoder = &MockGeocoder{}
+
+// Ensures that scripted responses are returned in order, with the last one repeating.
+func TestMockGeocoderGeocode(t *testing.T) {
+	sfo := geo.NewPoint(37.615223, -122.389979)
+	down := errors.New("provider down")
+
+	m := NewMockGeocoder().
+		AddGeocode("SFO", nil, down).
+		AddGeocode("SFO", sfo, nil)
+
+	if _, err := m.Geocode("SFO"); err != down {
+		t.Errorf("Expected the first scripted error, got %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		p, err := m.Geocode(" sfo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		AssertPointNear(t, p, sfo, 0)
+	}
+
+	if _, err := m.Geocode("LAX"); err != ErrUnscripted {
+		t.Errorf("Expected ErrUnscripted for an unscripted query, got %v", err)
+	}
+
+	m.AddGeocode("Nowhere", nil, nil)
+	if p, err := m.Geocode("Nowhere"); p != nil || err != nil {
+		t.Errorf("Expected the scripted nil point and error, got %v (%v)", p, err)
+	}
+
+	expected := []string{"geocode sfo", "geocode sfo", "geocode sfo", "geocode lax", "geocode nowhere"}
+	if !reflect.DeepEqual(m.Calls(), expected) {
+		t.Errorf("Expected calls %v, got %v", expected, m.Calls())
+	}
+}
+
+// Ensures that scripted reverse geocodes are matched by point.
+func TestMockGeocoderReverseGeocode(t *testing.T) {
+	m := NewMockGeocoder().AddReverseGeocode(geo.NewPoint(40.714224, -73.961452), "285 Bedford Avenue", nil)
+
+	address, err := m.ReverseGeocode(geo.NewPoint(40.714224, -73.961452))
+	if err != nil || address != "285 Bedford Avenue" {
+		t.Errorf("Expected the scripted address, got %q (%v)", address, err)
+	}
+
+	if _, err := m.ReverseGeocode(geo.NewPoint(0, 0)); err != ErrUnscripted {
+		t.Errorf("Expected ErrUnscripted for an unscripted point, got %v", err)
+	}
+}
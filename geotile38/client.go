@@ -0,0 +1,455 @@
+// Package geotile38 adapts a Tile38 geospatial database to golang-geo's interfaces, for applications
+// that keep their moving objects in Tile38: a Client stores points and geometries in Tile38 collections,
+// answers nearby and within searches as a geo.PlaceSearcher, and reports crossings of Tile38 geofence
+// channels to a geo.GeofenceNotifier, as a geo.GeofenceEngine reports its own.
+//
+//	c, err := geotile38.Dial("localhost:9851")
+//	err = c.Set("fleet", "truck1", geo.NewPoint(33.5123, -112.2693))
+//	nearby, err := c.Nearby("fleet", geo.NewPoint(33.5, -112.3), 5, 10)
+//
+// Tile38 speaks the Redis protocol, which the Client implements itself, so geotile38 depends on no Redis client.
+package geotile38
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/kellydunn/golang-geo"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The longest a Client waits for Tile38 to answer a command unless told otherwise.
+const DEFAULT_TIMEOUT = 10 * time.Second
+
+// The name Places found by a Client are attributed to.
+const PROVIDER = "tile38"
+
+// A Client issues commands to a Tile38 server over a single connection, which is opened again
+// if it breaks.  A Client is safe to use from multiple goroutines; commands are issued one at a time.
+type Client struct {
+	addr string
+
+	// The longest to wait for Tile38 to answer a command.  Defaults to DEFAULT_TIMEOUT.
+	Timeout time.Duration
+
+	mu   sync.Mutex
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// Connects to the Tile38 server at the passed in address, e.g. "localhost:9851".
+func Dial(addr string) (*Client, error) {
+	c := &Client{addr: addr}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.connect(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Closes the Client's connection.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+// Opens the connection, asking Tile38 to answer in JSON.  Called with the lock held.
+func (c *Client) connect() error {
+	conn, err := net.DialTimeout("tcp", c.addr, c.timeout())
+	if err != nil {
+		return err
+	}
+
+	c.conn, c.r = conn, bufio.NewReader(conn)
+	if _, err := c.roundTrip("OUTPUT", "json"); err != nil {
+		conn.Close()
+		c.conn = nil
+		return err
+	}
+
+	return nil
+}
+
+// Writes the passed in command and reads Tile38's reply.  Called with the lock held.
+func (c *Client) roundTrip(args ...string) (interface{}, error) {
+	c.conn.SetDeadline(time.Now().Add(c.timeout()))
+	if _, err := c.conn.Write(appendCommand(nil, args...)); err != nil {
+		return nil, err
+	}
+
+	return readReply(c.r)
+}
+
+// Issues the passed in command and returns Tile38's JSON reply, or the error it answered with.
+// Errors Tile38 answers with for missing collections and objects match geo.ErrNotFound.
+func (c *Client) do(args ...string) (json.RawMessage, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		if err := c.connect(); err != nil {
+			return nil, err
+		}
+	}
+
+	reply, err := c.roundTrip(args...)
+	if err != nil {
+		var respErr respError
+		if !errors.As(err, &respErr) {
+			// The connection is in an unknown state, so open a new one for the next command.
+			c.conn.Close()
+			c.conn = nil
+		}
+		return nil, commandError(args[0], err.Error())
+	}
+
+	data, ok := reply.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("tile38 %s: unexpected reply %v", args[0], reply)
+	}
+
+	var status struct {
+		OK  bool   `json:"ok"`
+		Err string `json:"err"`
+	}
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, fmt.Errorf("tile38 %s: %v", args[0], err)
+	}
+
+	if !status.OK {
+		return nil, commandError(args[0], status.Err)
+	}
+
+	return data, nil
+}
+
+// Returns the error Tile38 answered the passed in command with.
+func commandError(command, message string) error {
+	if strings.HasSuffix(message, "not found") {
+		return &geo.ProviderError{Provider: PROVIDER, Kind: geo.ErrNotFound, Status: command, Message: message}
+	}
+
+	return &geo.ProviderError{Provider: PROVIDER, Kind: geo.ErrBadRequest, Status: command, Message: message}
+}
+
+func (c *Client) timeout() time.Duration {
+	if c.Timeout <= 0 {
+		return DEFAULT_TIMEOUT
+	}
+
+	return c.Timeout
+}
+
+// Returns nil if Tile38 answers a PING.  Implements the geo.HealthChecker interface.
+func (c *Client) HealthCheck(ctx context.Context) error {
+	_, err := c.do("PING")
+	return err
+}
+
+// Returns the passed in coordinate as a command argument.
+func coordinate(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// Returns the passed in geometry as GeoJSON, for an OBJECT argument.
+func geoJSON(g geo.Geometry) (string, error) {
+	data, err := geo.NewFeature(g).MarshalGeoJSON(geo.FULL_PRECISION)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// Stores the passed in point as the object with the passed in id in the passed in collection,
+// replacing the object's previous position, if it had one.
+func (c *Client) Set(collection, id string, p *geo.Point) error {
+	_, err := c.do("SET", collection, id, "POINT", coordinate(p.Lat()), coordinate(p.Lng()))
+	return err
+}
+
+// Stores the passed in geometry, a *geo.Point, a geo.Line or a *geo.Polygon, as the object
+// with the passed in id in the passed in collection.
+func (c *Client) SetGeometry(collection, id string, g geo.Geometry) error {
+	object, err := geoJSON(g)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.do("SET", collection, id, "OBJECT", object)
+	return err
+}
+
+// Returns the geometry of the object with the passed in id in the passed in collection,
+// or an error matching geo.ErrNotFound if there is none.
+func (c *Client) Get(collection, id string) (geo.Geometry, error) {
+	data, err := c.do("GET", collection, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var reply struct {
+		Object json.RawMessage `json:"object"`
+	}
+	if err := json.Unmarshal(data, &reply); err != nil {
+		return nil, err
+	}
+
+	return decodeObject(reply.Object)
+}
+
+// Removes the object with the passed in id from the passed in collection.
+func (c *Client) Del(collection, id string) error {
+	_, err := c.do("DEL", collection, id)
+	return err
+}
+
+// Decodes a GeoJSON object Tile38 stores into the geometry it represents.
+func decodeObject(object json.RawMessage) (geo.Geometry, error) {
+	// Tile38 returns bare geometries, which geo.Feature reads when wrapped in a Feature.
+	var feature geo.Feature
+	if err := json.Unmarshal([]byte(`{"type":"Feature","properties":{},"geometry":`+string(object)+`}`), &feature); err != nil {
+		return nil, fmt.Errorf("tile38: %v", err)
+	}
+
+	return feature.Geometry, nil
+}
+
+// An Object is an object found by a search of a Tile38 collection.
+type Object struct {
+	ID       string
+	Geometry geo.Geometry
+
+	// The distance, in kilometers, from the point searched around; 0 for within searches.
+	Distance float64
+}
+
+// Decodes the objects of a search reply.  Objects whose geometry isn't a point, line or polygon are left out.
+func decodeObjects(data json.RawMessage) ([]*Object, error) {
+	var reply struct {
+		Objects []struct {
+			ID       string          `json:"id"`
+			Object   json.RawMessage `json:"object"`
+			Distance float64         `json:"distance"`
+		} `json:"objects"`
+	}
+	if err := json.Unmarshal(data, &reply); err != nil {
+		return nil, err
+	}
+
+	objects := make([]*Object, 0, len(reply.Objects))
+	for _, o := range reply.Objects {
+		g, err := decodeObject(o.Object)
+		if err != nil {
+			continue
+		}
+
+		objects = append(objects, &Object{ID: o.ID, Geometry: g, Distance: o.Distance / 1000})
+	}
+
+	return objects, nil
+}
+
+// Returns up to limit objects of the passed in collection within the passed in radius, in kilometers,
+// of the passed in point, nearest first.  A limit of 0 or less leaves the limit to Tile38, which returns
+// 100 objects by default.
+func (c *Client) Nearby(collection string, p *geo.Point, radius float64, limit int) ([]*Object, error) {
+	args := []string{"NEARBY", collection}
+	if limit > 0 {
+		args = append(args, "LIMIT", strconv.Itoa(limit))
+	}
+	args = append(args, "DISTANCE", "POINT", coordinate(p.Lat()), coordinate(p.Lng()), coordinate(radius*1000))
+
+	data, err := c.do(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeObjects(data)
+}
+
+// Returns the objects of the passed in collection that lie entirely within the passed in polygon.
+func (c *Client) Within(collection string, poly *geo.Polygon) ([]*Object, error) {
+	object, err := geoJSON(poly)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := c.do("WITHIN", collection, "OBJECT", object)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeObjects(data)
+}
+
+// Returns the points of the Tile38 collection named by the passed in category within the passed in radius,
+// in kilometers, of the passed in point, nearest first, as Places named after their ids.
+// Implements the geo.PlaceSearcher interface, so that a Client can stand in for a places provider.
+func (c *Client) PlaceSearch(p *geo.Point, radius float64, category string) ([]*geo.Place, error) {
+	objects, err := c.Nearby(category, p, radius, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	places := make([]*geo.Place, 0, len(objects))
+	for _, o := range objects {
+		point, ok := o.Geometry.(*geo.Point)
+		if !ok {
+			continue
+		}
+
+		places = append(places, &geo.Place{
+			ID:         o.ID,
+			Name:       o.ID,
+			Point:      point,
+			Categories: []string{category},
+			Distance:   o.Distance,
+			Provider:   PROVIDER,
+		})
+	}
+
+	return places, nil
+}
+
+// Creates, or replaces, a Tile38 geofence channel named after the passed in geofence's ID, reporting the objects
+// of the passed in collection entering or leaving its polygon.  Use Subscribe to receive the channel's events.
+func (c *Client) SetGeofence(collection string, fence *geo.Geofence) error {
+	object, err := geoJSON(fence.Polygon)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.do("SETCHAN", fence.ID, "WITHIN", collection, "FENCE", "DETECT", "enter,exit", "OBJECT", object)
+	return err
+}
+
+// Removes the Tile38 geofence channel of the geofence with the passed in ID.
+func (c *Client) DeleteGeofence(id string) error {
+	_, err := c.do("DELCHAN", id)
+	return err
+}
+
+// A message Tile38 publishes on a geofence channel.
+type fenceMessage struct {
+	Detect string          `json:"detect"`
+	Hook   string          `json:"hook"`
+	ID     string          `json:"id"`
+	Time   time.Time       `json:"time"`
+	Object json.RawMessage `json:"object"`
+}
+
+// Returns the GeofenceEvent the passed in channel message reports, and whether it reports one:
+// Tile38 also publishes the positions of objects staying inside or outside a fence.
+func fenceEvent(channel string, payload []byte) (geo.GeofenceEvent, bool) {
+	var m fenceMessage
+	if err := json.Unmarshal(payload, &m); err != nil {
+		return geo.GeofenceEvent{}, false
+	}
+
+	e := geo.GeofenceEvent{FenceID: m.Hook, Subject: m.ID, Time: m.Time}
+	switch m.Detect {
+	case "enter":
+		e.Type = geo.GeofenceEnter
+	case "exit":
+		e.Type = geo.GeofenceExit
+	default:
+		return geo.GeofenceEvent{}, false
+	}
+
+	if e.FenceID == "" {
+		e.FenceID = channel
+	}
+
+	if g, err := decodeObject(m.Object); err == nil {
+		e.Point, _ = g.(*geo.Point)
+	}
+
+	return e, true
+}
+
+// Subscribes to the geofence channels with the passed in IDs, or to every channel if none are passed in,
+// and tells the passed in notifier about each subject entering or leaving their geofences, as a
+// geo.GeofenceEngine does.  Subscriptions use a connection of their own.  Blocks until the passed in context
+// is done, returning its error, or until the connection fails.  Errors from the notifier don't end the
+// subscription, so that a notifier failing to deliver one event still receives the next.
+func (c *Client) Subscribe(ctx context.Context, n geo.GeofenceNotifier, ids ...string) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// Closing the connection unblocks the read below once the context is done.
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	command := append([]string{"SUBSCRIBE"}, ids...)
+	if len(ids) == 0 {
+		command = []string{"PSUBSCRIBE", "*"}
+	}
+	if _, err := conn.Write(appendCommand(nil, command...)); err != nil {
+		return err
+	}
+
+	r := bufio.NewReader(conn)
+	for {
+		reply, err := readReply(r)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+
+		channel, payload, ok := channelMessage(reply)
+		if !ok {
+			continue
+		}
+
+		if e, ok := fenceEvent(channel, payload); ok {
+			n.Notify(e)
+		}
+	}
+}
+
+// Returns the channel and payload of the passed in published message, and whether it is one:
+// ["message", channel, payload] or ["pmessage", pattern, channel, payload].
+func channelMessage(reply interface{}) (string, []byte, bool) {
+	parts, ok := reply.([]interface{})
+	if !ok || len(parts) < 3 {
+		return "", nil, false
+	}
+
+	kind, _ := parts[0].([]byte)
+	switch {
+	case string(kind) == "message" && len(parts) == 3:
+		channel, _ := parts[1].([]byte)
+		payload, ok := parts[2].([]byte)
+		return string(channel), payload, ok
+	case string(kind) == "pmessage" && len(parts) == 4:
+		channel, _ := parts[2].([]byte)
+		payload, ok := parts[3].([]byte)
+		return string(channel), payload, ok
+	default:
+		return "", nil, false
+	}
+}
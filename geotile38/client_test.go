@@ -0,0 +1,204 @@
+package geotile38
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"github.com/kellydunn/golang-geo"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// A fake Tile38 server answering each command with a canned reply, keyed by the command's name.
+type fakeServer struct {
+	l       net.Listener
+	mu      sync.Mutex
+	replies map[string]string
+	got     [][]string
+}
+
+// Starts a fakeServer answering with the passed in JSON replies.
+func startFakeServer(t *testing.T, replies map[string]string) *fakeServer {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	s := &fakeServer{l: l, replies: replies}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+
+	return s
+}
+
+func (s *fakeServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		reply, err := readReply(r)
+		if err != nil {
+			return
+		}
+
+		var args []string
+		for _, arg := range reply.([]interface{}) {
+			args = append(args, string(arg.([]byte)))
+		}
+
+		s.mu.Lock()
+		s.got = append(s.got, args)
+		answer, ok := s.replies[args[0]]
+		s.mu.Unlock()
+
+		if args[0] == "SUBSCRIBE" {
+			conn.Write(appendArray("subscribe", args[1], ":1"))
+			for _, message := range strings.Split(answer, "\n") {
+				conn.Write(appendArray("message", args[1], message))
+			}
+			continue
+		}
+
+		if !ok {
+			answer = `{"ok":true}`
+		}
+		conn.Write(appendBulk(nil, answer))
+	}
+}
+
+// Returns the commands the server has been given, in order.
+func (s *fakeServer) commands() [][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]string(nil), s.got...)
+}
+
+// Appends the passed in string to dst as a bulk string.
+func appendBulk(dst []byte, s string) []byte {
+	return append(dst, "$"+strconv.Itoa(len(s))+"\r\n"+s+"\r\n"...)
+}
+
+// Returns an array of bulk strings, or integers for items starting with a colon.
+func appendArray(items ...string) []byte {
+	dst := []byte("*" + strconv.Itoa(len(items)) + "\r\n")
+	for _, item := range items {
+		if strings.HasPrefix(item, ":") {
+			dst = append(dst, item+"\r\n"...)
+		} else {
+			dst = appendBulk(dst, item)
+		}
+	}
+
+	return dst
+}
+
+// Ensures that points are stored and searched for with the commands Tile38 expects.
+func TestClientCommands(t *testing.T) {
+	s := startFakeServer(t, map[string]string{
+		"NEARBY": `{"ok":true,"objects":[{"id":"truck1","object":{"type":"Point","coordinates":[-112.2693,33.5123]},"distance":1500}],"count":1}`,
+		"GET":    `{"ok":false,"err":"id not found"}`,
+	})
+
+	c, err := Dial(s.l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if err := c.Set("fleet", "truck1", geo.NewPoint(33.5123, -112.2693)); err != nil {
+		t.Fatal(err)
+	}
+
+	places, err := c.PlaceSearch(geo.NewPoint(33.5, -112.3), 5, "fleet")
+	if err != nil || len(places) != 1 || places[0].ID != "truck1" || places[0].Distance != 1.5 ||
+		*places[0].Point != *geo.NewPoint(33.5123, -112.2693) || places[0].Provider != PROVIDER {
+		t.Errorf("Expected truck1 1.5 km away, got %v (%v)", places, err)
+	}
+
+	if _, err := c.Get("fleet", "truck2"); !errors.Is(err, geo.ErrNotFound) {
+		t.Errorf("Expected geo.ErrNotFound for a missing object, got %v", err)
+	}
+
+	expected := []string{
+		"OUTPUT json",
+		"SET fleet truck1 POINT 33.5123 -112.2693",
+		"NEARBY fleet DISTANCE POINT 33.5 -112.3 5000",
+		"GET fleet truck2",
+	}
+	commands := s.commands()
+	if len(commands) != len(expected) {
+		t.Fatalf("Expected %d commands, got %v", len(expected), commands)
+	}
+	for i, command := range commands {
+		if strings.Join(command, " ") != expected[i] {
+			t.Errorf("Expected %s, got %v", expected[i], command)
+		}
+	}
+}
+
+// Ensures that geofences become channels, and that their crossings are reported to a notifier.
+func TestClientGeofence(t *testing.T) {
+	s := startFakeServer(t, map[string]string{
+		"SUBSCRIBE": `{"command":"set","detect":"inside","hook":"depot","key":"fleet","id":"truck1","object":{"type":"Point","coordinates":[1,1]}}` + "\n" +
+			`{"command":"set","detect":"enter","hook":"depot","key":"fleet","id":"truck1","time":"2024-05-01T10:00:00Z","object":{"type":"Point","coordinates":[1,1]}}`,
+	})
+
+	c, err := Dial(s.l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	fence := &geo.Geofence{ID: "depot", Polygon: geo.NewPolygon([]*geo.Point{geo.NewPoint(0, 0), geo.NewPoint(0, 2), geo.NewPoint(2, 2)})}
+	if err := c.SetGeofence("fleet", fence); err != nil {
+		t.Fatal(err)
+	}
+
+	if command := s.commands()[1]; len(command) != 9 || command[0] != "SETCHAN" || command[1] != "depot" || !strings.HasPrefix(command[8], `{"type":"Feature"`) {
+		t.Errorf("Expected a SETCHAN command with the fence as GeoJSON, got %v", command)
+	}
+
+	events := make(chan geo.GeofenceEvent, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- c.Subscribe(ctx, notifierFunc(func(e geo.GeofenceEvent) error {
+			events <- e
+			return nil
+		}), "depot")
+	}()
+
+	select {
+	case e := <-events:
+		if e.Type != geo.GeofenceEnter || e.FenceID != "depot" || e.Subject != "truck1" || *e.Point != *geo.NewPoint(1, 1) ||
+			!e.Time.Equal(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)) {
+			t.Errorf("Expected truck1 to enter the depot, got %+v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected an event")
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the subscription to end with the context, got %v", err)
+	}
+}
+
+type notifierFunc func(e geo.GeofenceEvent) error
+
+func (f notifierFunc) Notify(e geo.GeofenceEvent) error {
+	return f(e)
+}
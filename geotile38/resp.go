@@ -0,0 +1,103 @@
+package geotile38
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// The largest bulk string or array a reply may hold, to bound what a misbehaving server can make a Client allocate.
+const maxReplySize = 512 << 20
+
+// An error reply from the server.
+type respError string
+
+func (e respError) Error() string {
+	return string(e)
+}
+
+// Appends the passed in command to dst in the Redis protocol, as an array of bulk strings.
+func appendCommand(dst []byte, args ...string) []byte {
+	dst = append(dst, '*')
+	dst = strconv.AppendInt(dst, int64(len(args)), 10)
+	dst = append(dst, '\r', '\n')
+	for _, arg := range args {
+		dst = append(dst, '$')
+		dst = strconv.AppendInt(dst, int64(len(arg)), 10)
+		dst = append(dst, '\r', '\n')
+		dst = append(dst, arg...)
+		dst = append(dst, '\r', '\n')
+	}
+
+	return dst
+}
+
+// Reads a line, without its trailing CRLF.
+func readLine(r *bufio.Reader) ([]byte, error) {
+	line, err := r.ReadSlice('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return nil, errors.New("tile38: malformed reply line")
+	}
+
+	return line[:len(line)-2], nil
+}
+
+// Reads a reply in the Redis protocol: a string or bulk string as a []byte, an integer as an int64,
+// an array as an []interface{}, and a null as nil.  Error replies are returned as a respError.
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(line) == 0 {
+		return nil, errors.New("tile38: empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return append([]byte(nil), line[1:]...), nil
+	case '-':
+		return nil, respError(line[1:])
+	case ':':
+		return strconv.ParseInt(string(line[1:]), 10, 64)
+	case '$':
+		n, err := strconv.Atoi(string(line[1:]))
+		if err != nil || n > maxReplySize {
+			return nil, fmt.Errorf("tile38: malformed bulk length %q", line[1:])
+		}
+		if n < 0 {
+			return nil, nil
+		}
+
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		return data[:n], nil
+	case '*':
+		n, err := strconv.Atoi(string(line[1:]))
+		if err != nil || n > maxReplySize {
+			return nil, fmt.Errorf("tile38: malformed array length %q", line[1:])
+		}
+		if n < 0 {
+			return nil, nil
+		}
+
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], err = readReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("tile38: unknown reply type %q", line[0])
+	}
+}
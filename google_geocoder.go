@@ -4,86 +4,155 @@
 package geo
 
 import (
-	"crypto/hmac"
-	"crypto/sha1"
-	"encoding/base64"
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	//"hash"
-	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 )
 
 // This struct contains all the funcitonality
 // of interacting with the Google Maps Geocoding Service
-type GoogleGeocoder struct{}
+type GoogleGeocoder struct {
+	// If set, every request is counted against this Quota
+	// and refused once the "google" daily budget is spent.
+	Quota *Quota
+
+	// The client used to issue requests.  Defaults to a client sharing its connections
+	// with every provider, tuned with SetTransportConfig.
+	// Supply a client using a Recorder as its Transport to record or replay requests.
+	HTTPClient *http.Client
+
+	// The base URL of the Google Geocoding API.  Defaults to DEFAULT_GOOGLE_GEOCODE_URL.
+	// Each GoogleGeocoder has its own, so that differently configured geocoders
+	// can be used from different goroutines at the same time.
+	BaseURL string
+
+	apiKey      string
+	clientID    string
+	signingKey  string
+	channel     string
+	language    string
+	fallbacks   []string
+	romanize    bool
+	extraParams url.Values
+}
+
+// Creates and returns a pointer to a new GoogleGeocoder configured by the passed in options.
+// For example, to use an API key and get results in English:
+//
+//	g := geo.NewGoogleGeocoder(geo.WithAPIKey(key), geo.WithLanguage("en"))
+func NewGoogleGeocoder(opts ...Option) *GoogleGeocoder {
+	c := newGeocoderConfig(opts)
+	return &GoogleGeocoder{
+		Quota:       c.quota,
+		HTTPClient:  c.client("google"),
+		BaseURL:     c.baseURL,
+		apiKey:      c.apiKey,
+		clientID:    c.clientID,
+		signingKey:  c.signingKey,
+		channel:     c.channel,
+		language:    c.language,
+		fallbacks:   c.fallbacks,
+		romanize:    c.romanize,
+		extraParams: c.params,
+	}
+}
 
 // This struct contains selected fields from Google's Geocoding Service response
 type googleGeocodeResponse struct {
 	Error_message string
 	Status        string
 	Results       []struct {
+		AddressComponents []struct {
+			LongName  string   `json:"long_name"`
+			ShortName string   `json:"short_name"`
+			Types     []string `json:"types"`
+		} `json:"address_components"`
 		FormattedAddress string `json:"formatted_address"`
 		Geometry         struct {
 			Location struct {
 				Lat float64
 				Lng float64
 			}
+			LocationType string `json:"location_type"`
 		}
 	}
 }
 
 // This is the error that consumers receive when there
-// are no results from the geocoding request.
-var googleZeroResultsError = errors.New("ZERO_RESULTS")
+// are no results from the geocoding request.  Matches ErrNotFound.
+var googleZeroResultsError error = &ProviderError{Provider: "google", Kind: ErrNotFound, Status: "ZERO_RESULTS"}
 
-// This contains the base URL for the Google Geocoder API.
-var googleGeocodeURL = "https://maps.googleapis.com/maps/api/geocode/json"
-var googleGeocodeURLbase = "/maps/api/geocode/json"
+// Returns the error the response reports, or googleZeroResultsError if it has no results.
+func (res *googleGeocodeResponse) err() error {
+	if res.Status != "" && res.Status != "OK" && res.Status != "ZERO_RESULTS" {
+		return googleStatusError("google", res.Status, res.Error_message)
+	}
+
+	if len(res.Results) == 0 {
+		return googleZeroResultsError
+	}
+
+	return nil
+}
+
+// This contains the default base URL for the Google Geocoder API.
+const DEFAULT_GOOGLE_GEOCODE_URL = "https://maps.googleapis.com/maps/api/geocode/json"
+
+// Returns the base URL that this GoogleGeocoder issues requests to.
+func (g *GoogleGeocoder) baseURL() string {
+	if g.BaseURL == "" {
+		return DEFAULT_GOOGLE_GEOCODE_URL
+	}
 
-// Note:  In the next major revision (1.0.0), it is planned
-//        That Geocoders should adhere to the `geo.Geocoder`
-//        interface and provide versioning of APIs accordingly.
-// Sets the base URL for the Google Geocoding API.
-func SetGoogleGeocodeURL(newGeocodeURL string) {
-	googleGeocodeURL = newGeocodeURL
+	return g.BaseURL
 }
 
 // Issues a request to the google geocoding service and forwards the passed in params string
 // as a URL-encoded entity.  Returns an array of byes as a result, or an error if one occurs during the process.
 func (g *GoogleGeocoder) Request(params string) ([]byte, error) {
-	client := &http.Client{}
-
-	fullUrl := fmt.Sprintf("%s?%s", googleGeocodeURL, params)
+	return g.requestContext(context.Background(), params)
+}
 
-	// TODO Potentially refactor out from MapQuestGeocoder as well
-	req, err := http.NewRequest("GET", fullUrl, nil)
-	if err != nil {
-		return nil, err
+// Issues a request as Request does, canceled if the passed in context is done first.
+func (g *GoogleGeocoder) requestContext(ctx context.Context, params string) ([]byte, error) {
+	if g.Quota != nil {
+		if err := g.Quota.Spend(g.quotaProviders()...); err != nil {
+			return nil, err
+		}
 	}
 
-	resp, requestErr := client.Do(req)
-	if requestErr != nil {
-		return nil, requestErr
-	}
+	fullUrl := fmt.Sprintf("%s?%s", g.baseURL(), params)
+	return providerGetContext(ctx, g.HTTPClient, "google", fullUrl)
+}
 
-	data, dataReadErr := ioutil.ReadAll(resp.Body)
+// Returns nil if Google answers a canary reverse geocode, or an error saying why it didn't,
+// such as ErrAuth for an invalid API key.  Implements the HealthChecker interface.
+func (g *GoogleGeocoder) HealthCheck(ctx context.Context) error {
+	params, err := g.params(url.Values{"latlng": {latLngParam(healthCheckPoint)}})
+	if err != nil {
+		return err
+	}
 
-	if dataReadErr != nil {
-		return nil, dataReadErr
+	data, err := g.requestContext(ctx, params)
+	if err != nil {
+		return err
 	}
 
-	return data, nil
+	_, err = g.extractAddressFromResponse(data)
+	return canaryError(err)
 }
 
 // Geocodes the passed in query string and returns a pointer to a new Point struct.
 // Returns an error if the underlying request cannot complete.
-func (g *GoogleGeocoder) Geocode(query string) (*Point, error) {
-	url_safe_query := url.QueryEscape(query)
-	data, err := g.Request(fmt.Sprintf("address=%s", url_safe_query))
+// Google understands every QueryOption.
+func (g *GoogleGeocoder) Geocode(query string, opts ...QueryOption) (*Point, error) {
+	q := newQueryConfig(opts)
+	data, err := g.geocodeRequest(query, g.queryLanguage(q), q)
 	if err != nil {
 		return nil, err
 	}
@@ -98,6 +167,99 @@ func (g *GoogleGeocoder) Geocode(query string) (*Point, error) {
 	return p, nil
 }
 
+// Geocodes the passed in query string and returns every candidate Google finds.
+// Returns an error if the underlying request cannot complete, or if there are no candidates.
+// Results are romanized if the GoogleGeocoder was created WithTransliteration.
+func (g *GoogleGeocoder) GeocodeResults(query string, opts ...QueryOption) ([]*GeocodeResult, error) {
+	q := newQueryConfig(opts)
+	data, err := g.geocodeRequest(query, g.queryLanguage(q), q)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := g.extractResultsFromResponse(data)
+	if err != nil || !g.romanize {
+		return results, err
+	}
+
+	romanizeResults(results, func() ([]*GeocodeResult, error) {
+		data, err := g.geocodeRequest(query, "en", q)
+		if err != nil {
+			return nil, err
+		}
+		return g.extractResultsFromResponse(data)
+	})
+
+	return results, nil
+}
+
+// Returns the language the passed in query asks for, or the configured language if it doesn't ask for one.
+func (g *GoogleGeocoder) queryLanguage(q *queryConfig) string {
+	if q.language != "" {
+		return q.language
+	}
+
+	return g.language
+}
+
+// Issues a geocoding request for the passed in query in the passed in language,
+// or Google's default if it is empty, and returns the response body.
+func (g *GoogleGeocoder) geocodeRequest(query string, language string, q *queryConfig) ([]byte, error) {
+	values := url.Values{"address": {query}, "language": nil}
+	if language != "" {
+		values.Set("language", language)
+	}
+	if q.region != "" {
+		values.Set("region", strings.ToLower(q.region))
+	}
+	if q.bounds != nil {
+		values.Set("bounds", q.boundsParam())
+	}
+	if len(q.components) > 0 {
+		values.Set("components", q.componentsParam())
+	}
+	g.setQueryParams(values, q)
+
+	params, err := g.params(values)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.Request(params)
+}
+
+// Extracts every result from a Google Geocoder Response body.
+func (g *GoogleGeocoder) extractResultsFromResponse(data []byte) ([]*GeocodeResult, error) {
+	res := &googleGeocodeResponse{}
+	if err := json.Unmarshal(data, res); err != nil {
+		return nil, err
+	}
+
+	if err := res.err(); err != nil {
+		return nil, err
+	}
+
+	results := make([]*GeocodeResult, len(res.Results))
+	for i, r := range res.Results {
+		results[i] = &GeocodeResult{
+			Point:            NewPoint(r.Geometry.Location.Lat, r.Geometry.Location.Lng),
+			FormattedAddress: r.FormattedAddress,
+			Provider:         "google",
+			Quality:          googleMatchQuality(r.Geometry.LocationType),
+		}
+
+		for _, c := range r.AddressComponents {
+			for _, typ := range c.Types {
+				if typ == "country" {
+					results[i].CountryCode = NormalizeCountryCode(c.ShortName)
+				}
+			}
+		}
+	}
+
+	return results, nil
+}
+
 // Extracts the first lat and lng values from a Google Geocoder Response body.
 func (g *GoogleGeocoder) extractLatLngFromResponse(data []byte) (float64, float64, error) {
 	res := &googleGeocodeResponse{}
@@ -105,8 +267,8 @@ func (g *GoogleGeocoder) extractLatLngFromResponse(data []byte) (float64, float6
 	if err != nil {
 		return 0, 0, err
 	}
-	if len(res.Results) == 0 {
-		return 0, 0, googleZeroResultsError
+	if err := res.err(); err != nil {
+		return 0, 0, err
 	}
 
 	lat := res.Results[0].Geometry.Location.Lat
@@ -117,85 +279,143 @@ func (g *GoogleGeocoder) extractLatLngFromResponse(data []byte) (float64, float6
 
 // Reverse geocodes the pointer to a Point struct and returns the first address that matches
 // or returns an error if the underlying request cannot complete.
-func (g *GoogleGeocoder) ReverseGeocode(p *Point, apikey string) (string, error) {
-	var queryurl string
-	var s string
+// Requests are authenticated with the credentials the GoogleGeocoder was created with.
+// Addresses are asked for in the language the QueryOptions ask for, or the configured language,
+// then in each fallback language until one is found; without a language, Google chooses one.
+// Of the other QueryOptions, reverse geocodes understand QuerySensor and QueryParam.
+func (g *GoogleGeocoder) ReverseGeocode(p *Point, opts ...QueryOption) (string, error) {
+	q := newQueryConfig(opts)
+	return g.reverseGeocodeIn(p, append([]string{g.queryLanguage(q)}, g.fallbacks...), q)
+}
+
+// Reverse geocodes the pointer to a Point struct as ReverseGeocode does, asking for the address
+// in each of the passed in languages in turn, instead of those the GoogleGeocoder was created with,
+// until one is found.  An empty language asks for Google's default.  Only finding nothing moves
+// on to the next language; other errors are returned straight away.
+func (g *GoogleGeocoder) ReverseGeocodeIn(p *Point, languages ...string) (string, error) {
+	return g.reverseGeocodeIn(p, languages, &queryConfig{})
+}
 
-	if apikey != "" {
-		s = fmt.Sprintf("%f,%f", p.lat, p.lng)
-		queryurl = "language=ja&latlng=" + s + "&key=" + url.QueryEscape(apikey)
-	} else {
-		queryurl = fmt.Sprintf("language=ja&latlng=%f,%f", p.lat, p.lng)
+// Reverse geocodes the passed in point in each of the passed in languages in turn until an address is found.
+func (g *GoogleGeocoder) reverseGeocodeIn(p *Point, languages []string, q *queryConfig) (string, error) {
+	if len(languages) == 0 {
+		languages = []string{""}
 	}
 
-	data, err := g.Request(queryurl)
+	var err error
+	for _, language := range languages {
+		var address string
+		address, err = g.reverseGeocode(p, language, q)
+		if err != googleZeroResultsError {
+			return address, err
+		}
+	}
+
+	return "", err
+}
+
+// Reverse geocodes the passed in point in the passed in language, or Google's default if it is empty.
+func (g *GoogleGeocoder) reverseGeocode(p *Point, language string, q *queryConfig) (string, error) {
+	// An empty language is left out of the request, rather than replaced with the configured one.
+	values := url.Values{"latlng": {fmt.Sprintf("%f,%f", p.lat, p.lng)}, "language": nil}
+	if language != "" {
+		values.Set("language", language)
+	}
+	g.setQueryParams(values, q)
+
+	params, err := g.params(values)
 	if err != nil {
 		return "", err
 	}
 
-	resStr, err := g.extractAddressFromResponse(data)
+	data, err := g.Request(params)
 	if err != nil {
 		return "", err
 	}
 
-	return resStr, nil
+	return g.extractAddressFromResponse(data)
 }
 
-// Reverse geocodes the pointer to a Point struct and returns the first address that matches
-// or returns an error if the underlying request cannot complete.
-func (g *GoogleGeocoder) ReverseGeocodePremier(p *Point, username string, key string) (string, error) {
-	var queryurl string
-	var s string
+// Sets the sensor and extra parameters of the passed in query on the passed in values.
+func (g *GoogleGeocoder) setQueryParams(values url.Values, q *queryConfig) {
+	if q.sensor != nil {
+		values.Set("sensor", strconv.FormatBool(*q.sensor))
+	}
+	q.applyParams(values)
+}
 
-	s = fmt.Sprintf("%f,%f", p.lat, p.lng)
-	queryurl = "language=ja&latlng=" + s + "&client=" + username
+// Returns the passed in parameters URL-encoded, along with the configured language and extra
+// parameters, unless they have ones of the same name, and credentials.  Premier requests are signed.
+func (g *GoogleGeocoder) params(values url.Values) (string, error) {
+	if _, ok := values["language"]; !ok && g.language != "" {
+		values.Set("language", g.language)
+	}
 
-	// Calculate hash
-	decodedkeyarray, err := base64.StdEncoding.DecodeString(key)
-	if err != nil {
-		return "", err
+	addParams(values, g.extraParams)
+
+	if g.clientID != "" {
+		values.Set("client", g.clientID)
+
+		if g.channel != "" {
+			if !validGoogleChannel(g.channel) {
+				return "", fmt.Errorf("invalid channel %q", g.channel)
+			}
+			values.Set("channel", g.channel)
+		}
+
+		base, err := url.Parse(g.baseURL())
+		if err != nil {
+			return "", err
+		}
+
+		signed, err := SignGoogleURL(base.Path+"?"+values.Encode(), g.signingKey)
+		if err != nil {
+			return "", err
+		}
+
+		return signed[strings.Index(signed, "?")+1:], nil
 	}
-	s = googleGeocodeURLbase + "?" + queryurl
-	hash := hmac.New(sha1.New, decodedkeyarray)
-	hash.Write([]byte(s))
-	signaturebinary := hash.Sum(nil)
 
-	// base64.URLEncoding doesn't work, so I did a cheap workaround for now with
-	// strings.Replace. Works fine, but I'll tidy this up later.
+	if g.apiKey != "" {
+		values.Set("key", g.apiKey)
+	}
 
-	signaturebase64 := base64.URLEncoding.EncodeToString(signaturebinary)
-	signaturebase64 = strings.Replace(signaturebase64, "+", "-", -1)
-	signaturebase64 = strings.Replace(signaturebase64, "/", "_", -1)
-	signaturebase64 = strings.Replace(signaturebase64, "=", ",", -1)
-	queryurl += "&signature=" + signaturebase64
+	return values.Encode(), nil
+}
 
-	data, err := g.Request(queryurl)
-	if err != nil {
-		return "", err
+// Returns the names that requests are counted under in a Quota:
+// "google", and "google:<channel>" for Premier requests tagged with a channel.
+func (g *GoogleGeocoder) quotaProviders() []string {
+	if g.clientID != "" && g.channel != "" {
+		return []string{"google", "google:" + g.channel}
 	}
 
-	resStr, err := g.extractAddressFromResponse(data)
-	if err != nil {
-		return "", err
+	return []string{"google"}
+}
+
+// Returns whether or not the passed in channel is one Google will accept:
+// ASCII letters, digits, periods, underscores and hyphens.
+func validGoogleChannel(channel string) bool {
+	for _, r := range channel {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '_' || r == '-') {
+			return false
+		}
 	}
 
-	return resStr, nil
+	return true
 }
 
 // Returns an Address from a Google Geocoder Response body.
 func (g *GoogleGeocoder) extractAddressFromResponse(data []byte) (string, error) {
-	//var s string
-	//s = string(data[:])
-	//fmt.Println("debug: response: " + s)
 	res := &googleGeocodeResponse{}
 	err := json.Unmarshal(data, &res)
 	if err != nil {
 		return "", err
 	}
 
-	if len(res.Results) == 0 {
-		return "", errors.New("Failed: (" + res.Status + ") " + res.Error_message)
-	} else {
-		return res.Results[0].FormattedAddress, nil
+	if err := res.err(); err != nil {
+		return "", err
 	}
+
+	return res.Results[0].FormattedAddress, nil
 }
@@ -1,10 +1,15 @@
 package geo
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"path"
+	"sync"
 	"testing"
 )
 
@@ -17,7 +22,11 @@ func TestExtractAddressFromResponse(t *testing.T) {
 		t.Error("%v\n", err)
 	}
 
-	address := g.extractAddressFromResponse(data)
+	address, err := g.extractAddressFromResponse(data)
+	if err != nil {
+		t.Error(err)
+	}
+
 	if address != "285 Bedford Avenue, Brooklyn, NY 11211, USA" {
 		t.Error(fmt.Sprintf("Expected: 285 Bedford Avenue, Brooklyn, NY 11211 USA.  Got: %s", address))
 	}
@@ -57,6 +66,329 @@ func TestExtractLatLngFromRequestZeroResults(t *testing.T) {
 	}
 }
 
+// Ensures that every result in a Google response is extracted along with its address.
+func TestGoogleExtractResultsFromResponse(t *testing.T) {
+	g := &GoogleGeocoder{}
+
+	data, err := GetMockResponse("test/data/google_geocode_success.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := g.extractResultsFromResponse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+
+	r := results[0]
+	if r.Point.Lat() != 37.615223 || r.Point.Lng() != -122.389979 || r.Provider != "google" {
+		t.Errorf("Expected SFO from google, got %v from %s", r.Point, r.Provider)
+	}
+
+	if r.FormattedAddress != "San Francisco Airport (SFO), South Airport Boulevard, San Francisco, CA 94128, USA" {
+		t.Errorf("Unexpected formatted address %q", r.FormattedAddress)
+	}
+
+	if r.Quality != Approximate {
+		t.Errorf("Expected quality Approximate, got %v", r.Quality)
+	}
+
+	if r.CountryCode != "US" {
+		t.Errorf("Expected country code US, got %q", r.CountryCode)
+	}
+}
+
+// Ensures that geocoders configured with different base URLs
+// can be used concurrently without interfering with each other.
+func TestGoogleGeocoderBaseURL(t *testing.T) {
+	servers := make([]*httptest.Server, 2)
+	for i := range servers {
+		lat := float64(i)
+		servers[i] = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"status":"OK","results":[{"geometry":{"location":{"lat":%f,"lng":0}}}]}`, lat)
+		}))
+		defer servers[i].Close()
+	}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			g := &GoogleGeocoder{BaseURL: servers[i%2].URL}
+			p, err := g.Geocode("anywhere")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			if p.Lat() != float64(i%2) {
+				t.Errorf("Expected geocoder %d to use its own base URL, but got a result from another", i)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+// Ensures that a GoogleGeocoder satisfies the Geocoder interface.
+var _ Geocoder = &GoogleGeocoder{}
+
+// Starts a server that records the query of every request it receives
+// and responds with the passed in file.
+func newQueryRecordingServer(t *testing.T, file string, queries *[]url.Values) *httptest.Server {
+	data, err := GetMockResponse(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*queries = append(*queries, r.URL.Query())
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// Ensures that options passed to NewGoogleGeocoder are sent with every request.
+func TestNewGoogleGeocoder(t *testing.T) {
+	var queries []url.Values
+	server := newQueryRecordingServer(t, "test/data/google_reverse_geocode_success.json", &queries)
+
+	g := NewGoogleGeocoder(WithBaseURL(server.URL), WithAPIKey("secret"), WithLanguage("en"))
+	if _, err := g.Geocode("285 Bedford Avenue"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := g.ReverseGeocode(NewPoint(40.714224, -73.961452)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, q := range queries {
+		if q.Get("key") != "secret" || q.Get("language") != "en" {
+			t.Errorf("Expected every request to carry the API key and language, got %v", q)
+		}
+	}
+
+	if queries[0].Get("address") != "285 Bedford Avenue" || queries[1].Get("latlng") != "40.714224,-73.961452" {
+		t.Errorf("Unexpected queries: %v", queries)
+	}
+}
+
+// Ensures that QueryOptions are sent with the request they are passed to, and only that one.
+func TestGoogleGeocodeQueryOptions(t *testing.T) {
+	var queries []url.Values
+	server := newQueryRecordingServer(t, "test/data/google_reverse_geocode_success.json", &queries)
+
+	g := NewGoogleGeocoder(WithBaseURL(server.URL), WithLanguage("en"))
+	_, err := g.Geocode("Cambridge",
+		QueryLanguage("fr"),
+		QueryRegion("GB"),
+		QueryBounds(NewBounds(NewPoint(52.1, 0.0), NewPoint(52.3, 0.2))),
+		QueryComponents(map[string]string{"country": "GB"}),
+		QuerySensor(true),
+		QueryParam("result_type", "locality"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := g.ReverseGeocode(NewPoint(40.714224, -73.961452), QueryParam("result_type", "street_address")); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"language":    "fr",
+		"region":      "gb",
+		"bounds":      "52.100000,0.000000|52.300000,0.200000",
+		"components":  "country:GB",
+		"sensor":      "true",
+		"result_type": "locality",
+	}
+	for k, v := range expected {
+		if queries[0].Get(k) != v {
+			t.Errorf("Expected %s=%s, got %q", k, v, queries[0].Get(k))
+		}
+	}
+
+	if queries[1].Get("language") != "en" || queries[1].Get("result_type") != "street_address" || queries[1].Has("region") {
+		t.Errorf("Expected the reverse geocode to carry only its own options, got %v", queries[1])
+	}
+}
+
+// Ensures that parameters added WithParam are sent with every request, unless the request sets its own.
+func TestGoogleGeocoderWithParam(t *testing.T) {
+	var queries []url.Values
+	server := newQueryRecordingServer(t, "test/data/google_reverse_geocode_success.json", &queries)
+
+	g := NewGoogleGeocoder(WithBaseURL(server.URL), WithAPIKey("secret"),
+		WithParam("new_forward_geocoder", "true"), WithParam("result_type", "street_address"), WithParam("key", "other"))
+	if _, err := g.Geocode("285 Bedford Avenue"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := g.ReverseGeocode(NewPoint(40.714224, -73.961452), QueryParam("result_type", "locality")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, q := range queries {
+		if q.Get("new_forward_geocoder") != "true" || q.Get("key") != "secret" {
+			t.Errorf("Expected every request to carry the extra parameter and the API key, got %v", q)
+		}
+	}
+
+	if queries[0].Get("result_type") != "street_address" || queries[1].Get("result_type") != "locality" {
+		t.Errorf("Expected a QueryParam to take precedence, got %v", queries)
+	}
+}
+
+// Ensures that Premier clients sign their requests instead of sending an API key.
+func TestNewGoogleGeocoderPremier(t *testing.T) {
+	var queries []url.Values
+	server := newQueryRecordingServer(t, "test/data/google_reverse_geocode_success.json", &queries)
+
+	g := NewGoogleGeocoder(WithBaseURL(server.URL+"/maps/api/geocode/json"), WithClientIDAndKey("clientID", "vNIXE0xscrmjlyV-12Nj_BvUPaw="))
+	if _, err := g.ReverseGeocode(NewPoint(40.714224, -73.961452)); err != nil {
+		t.Fatal(err)
+	}
+
+	q := queries[0]
+	if q.Get("client") != "clientID" || q.Get("signature") == "" || q.Get("key") != "" {
+		t.Errorf("Expected a signed request with a client ID and no key, got %v", q)
+	}
+
+	signature := q.Get("signature")
+	q.Del("signature")
+	expected, _ := googleSignature("/maps/api/geocode/json?"+q.Encode(), "vNIXE0xscrmjlyV-12Nj_BvUPaw=")
+	if signature != expected {
+		t.Errorf("Expected signature %s, got %s", expected, signature)
+	}
+}
+
+// Ensures that Premier requests carry their channel, and that usage is counted per channel.
+func TestNewGoogleGeocoderChannel(t *testing.T) {
+	var queries []url.Values
+	server := newQueryRecordingServer(t, "test/data/google_reverse_geocode_success.json", &queries)
+
+	q := NewQuota()
+	g := NewGoogleGeocoder(
+		WithBaseURL(server.URL),
+		WithClientIDAndKey("clientID", "vNIXE0xscrmjlyV-12Nj_BvUPaw="),
+		WithChannel("checkout-web"),
+		WithQuota(q),
+	)
+
+	if _, err := g.ReverseGeocode(NewPoint(40.714224, -73.961452)); err != nil {
+		t.Fatal(err)
+	}
+
+	if queries[0].Get("channel") != "checkout-web" || queries[0].Get("signature") == "" {
+		t.Errorf("Expected a signed request tagged with the channel, got %v", queries[0])
+	}
+
+	if q.Usage("google") != 1 || q.Usage("google:checkout-web") != 1 {
+		t.Errorf("Expected usage to be counted for google and its channel, got %d and %d", q.Usage("google"), q.Usage("google:checkout-web"))
+	}
+
+	q.SetDailyBudget("google:checkout-web", 1)
+	if _, err := g.ReverseGeocode(NewPoint(40.714224, -73.961452)); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Expected the channel budget to be enforced, got %v", err)
+	}
+
+	bad := NewGoogleGeocoder(WithBaseURL(server.URL), WithClientIDAndKey("clientID", "vNIXE0xscrmjlyV-12Nj_BvUPaw="), WithChannel("not ok!"))
+	if _, err := bad.Geocode("anywhere"); err == nil {
+		t.Error("Expected an invalid channel to be rejected")
+	}
+}
+
+// Ensures that reverse geocodes fall back through their languages until an address is found.
+func TestGoogleReverseGeocodeLanguageFallback(t *testing.T) {
+	data, err := GetMockResponse("test/data/google_reverse_geocode_success.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var languages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		language, ok := q["language"]
+		if !ok {
+			language = []string{"default"}
+		}
+		languages = append(languages, language...)
+
+		if ok && language[0] != "en" {
+			w.Write([]byte(`{"results":[],"status":"ZERO_RESULTS"}`))
+			return
+		}
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	p := NewPoint(40.714224, -73.961452)
+	g := NewGoogleGeocoder(WithBaseURL(server.URL), WithLanguage("ja"), WithFallbackLanguages("ko", "en"))
+	if address, err := g.ReverseGeocode(p); err != nil || address != "285 Bedford Avenue, Brooklyn, NY 11211, USA" {
+		t.Errorf("Expected the English address, got %q (%v)", address, err)
+	}
+
+	if fmt.Sprint(languages) != "[ja ko en]" {
+		t.Errorf("Expected Japanese, then Korean, then English, got %v", languages)
+	}
+
+	languages = nil
+	if _, err := g.ReverseGeocodeIn(p, "fr", ""); err != nil || fmt.Sprint(languages) != "[fr default]" {
+		t.Errorf("Expected French, then Google's default, got %v (%v)", languages, err)
+	}
+
+	languages = nil
+	if _, err := g.ReverseGeocodeIn(p, "fr", "de"); err != googleZeroResultsError || len(languages) != 2 {
+		t.Errorf("Expected no results after trying both languages, got %v after %v", err, languages)
+	}
+
+	// Without a configured language, Google chooses one.
+	languages = nil
+	if _, err := NewGoogleGeocoder(WithBaseURL(server.URL)).ReverseGeocode(p); err != nil || fmt.Sprint(languages) != "[default]" {
+		t.Errorf("Expected no language to be asked for, got %v (%v)", languages, err)
+	}
+}
+
+// Ensures that geocoders created WithTransliteration romanize results from an English lookup.
+func TestGoogleGeocodeResultsTransliteration(t *testing.T) {
+	var languages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		language := r.URL.Query().Get("language")
+		languages = append(languages, language)
+
+		address := "日本、〒100-0005 東京都千代田区丸の内１丁目"
+		if language == "en" {
+			address = "1 Chome Marunouchi, Chiyoda City, Tokyo 100-0005, Japan"
+		}
+		fmt.Fprintf(w, `{"status": "OK", "results": [{"formatted_address": %q, "geometry": {"location": {"lat": 35.6812, "lng": 139.7671}}}]}`, address)
+	}))
+	defer server.Close()
+
+	g := NewGoogleGeocoder(WithBaseURL(server.URL), WithLanguage("ja"), WithTransliteration())
+	results, err := g.GeocodeResults("東京駅")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if results[0].RomanizedAddress != "1 Chome Marunouchi, Chiyoda City, Tokyo 100-0005, Japan" || results[0].FormattedAddress == results[0].RomanizedAddress {
+		t.Errorf("Expected the Japanese address with its English romanization, got %+v", results[0])
+	}
+
+	if fmt.Sprint(languages) != "[ja en]" {
+		t.Errorf("Expected a Japanese then an English request, got %v", languages)
+	}
+
+	if results, _ := NewGoogleGeocoder(WithBaseURL(server.URL), WithLanguage("ja")).GeocodeResults("東京駅"); results[0].RomanizedAddress != "" {
+		t.Errorf("Expected no romanization without WithTransliteration, got %q", results[0].RomanizedAddress)
+	}
+}
+
 func GetMockResponse(s string) ([]byte, error) {
 	dataPath := path.Join(s)
 	_, readErr := os.Stat(dataPath)
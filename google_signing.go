@@ -0,0 +1,98 @@
+package geo
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// This is the error that consumers can compare against with errors.Is when VerifyGoogleSignature
+// finds a URL unsigned, or signed with another key or before being modified.  Also matches ErrAuth.
+var ErrInvalidSignature = errors.New("invalid Google URL signature")
+
+// The error VerifyGoogleSignature returns for URLs whose signature doesn't hold.
+type invalidSignatureError struct{}
+
+func (invalidSignatureError) Error() string {
+	return ErrInvalidSignature.Error()
+}
+
+// Allows errors.Is(err, ErrInvalidSignature) and errors.Is(err, ErrAuth) to succeed.
+func (invalidSignatureError) Is(target error) bool {
+	return target == ErrInvalidSignature || target == ErrAuth
+}
+
+// Signs the passed in Google Maps API URL with the passed in URL-safe base64
+// encoded signing key, as required of Google Maps for Business (Premier) clients.
+// The URL may be absolute or just a path and query, and must already carry
+// the client parameter.  Returns the URL with a signature parameter appended.
+// See https://developers.google.com/maps/documentation/business/webservices/auth
+func SignGoogleURL(rawURL string, signingKey string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	signature, err := googleSignature(u.EscapedPath()+"?"+u.RawQuery, signingKey)
+	if err != nil {
+		return "", err
+	}
+
+	return rawURL + "&signature=" + signature, nil
+}
+
+// Returns the modified base64 encoded HMAC-SHA1 of the passed in
+// path and query, keyed with the decoded signing key.
+func googleSignature(pathAndQuery string, signingKey string) (string, error) {
+	key, err := base64.URLEncoding.DecodeString(signingKey)
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write([]byte(pathAndQuery))
+
+	return base64.URLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// Verifies that the passed in Google Maps API URL was signed, as SignGoogleURL does, with the passed in
+// URL-safe base64 encoded signing key, so that proxies built on golang-geo can accept pre-signed URLs
+// from their clients without holding the Premier credentials for them.  The signature must be the
+// URL's last parameter, as Google requires.  Returns nil if the signature holds, an error matching
+// ErrInvalidSignature if it doesn't, or the error found parsing the URL or decoding the key.
+func VerifyGoogleSignature(rawURL string, signingKey string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	i := strings.LastIndex("&"+u.RawQuery, "&signature=")
+	if i < 0 {
+		return invalidSignatureError{}
+	}
+
+	query, signature := u.RawQuery[:max(i-1, 0)], u.RawQuery[i+len("signature="):]
+	if strings.Contains(signature, "&") {
+		return invalidSignatureError{}
+	}
+
+	expected, err := googleSignature(u.EscapedPath()+"?"+query, signingKey)
+	if err != nil {
+		return err
+	}
+
+	// Compare the decoded signatures in constant time, so that timing doesn't reveal how much of a forgery is right.
+	got, err := base64.URLEncoding.DecodeString(signature)
+	if err != nil {
+		return invalidSignatureError{}
+	}
+	want, _ := base64.URLEncoding.DecodeString(expected)
+	if !hmac.Equal(got, want) {
+		return invalidSignatureError{}
+	}
+
+	return nil
+}
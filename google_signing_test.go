@@ -0,0 +1,50 @@
+package geo
+
+import (
+	"errors"
+	"testing"
+)
+
+// Ensures that URLs are signed as in Google's own documentation.
+func TestSignGoogleURL(t *testing.T) {
+	signed, err := SignGoogleURL("https://maps.googleapis.com/maps/api/geocode/json?address=New+York&client=clientID", "vNIXE0xscrmjlyV-12Nj_BvUPaw=")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "https://maps.googleapis.com/maps/api/geocode/json?address=New+York&client=clientID&signature=chaRF2hTJKOScPr-RQCEhZbSzIE="
+	if signed != expected {
+		t.Errorf("Expected %s, got %s", expected, signed)
+	}
+}
+
+// Ensures that a malformed signing key is reported rather than used.
+func TestSignGoogleURLBadKey(t *testing.T) {
+	if _, err := SignGoogleURL("/maps/api/geocode/json?address=New+York&client=clientID", "not base64!"); err == nil {
+		t.Error("Expected an error for a signing key that isn't base64")
+	}
+}
+
+// Ensures that signed URLs verify, and that unsigned, tampered or foreign URLs don't.
+func TestVerifyGoogleSignature(t *testing.T) {
+	key := "vNIXE0xscrmjlyV-12Nj_BvUPaw="
+	signed := "https://maps.googleapis.com/maps/api/geocode/json?address=New+York&client=clientID&signature=chaRF2hTJKOScPr-RQCEhZbSzIE="
+	if err := VerifyGoogleSignature(signed, key); err != nil {
+		t.Errorf("Expected the signature to hold, got %v", err)
+	}
+
+	for _, u := range []string{
+		"https://maps.googleapis.com/maps/api/geocode/json?address=New+York&client=clientID",
+		"https://maps.googleapis.com/maps/api/geocode/json?address=Boston&client=clientID&signature=chaRF2hTJKOScPr-RQCEhZbSzIE=",
+		"https://maps.googleapis.com/maps/api/geocode/json?address=New+York&client=clientID&signature=chaRF2hTJKOScPr-RQCEhZbSzIE=&key=x",
+		"https://maps.googleapis.com/maps/api/geocode/json?address=New+York&client=clientID&signature=not+base64",
+	} {
+		if err := VerifyGoogleSignature(u, key); !errors.Is(err, ErrInvalidSignature) || !errors.Is(err, ErrAuth) {
+			t.Errorf("Expected %v for %s, got %v", ErrInvalidSignature, u, err)
+		}
+	}
+
+	if err := VerifyGoogleSignature(signed, "c2VjcmV0"); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected %v for another key, got %v", ErrInvalidSignature, err)
+	}
+}
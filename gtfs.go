@@ -0,0 +1,251 @@
+package geo
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GTFS location types: whether a stop is a platform, a station grouping platforms,
+// or an entrance, generic node or boarding area within one.
+const (
+	GTFSStopOrPlatform = iota
+	GTFSStation
+	GTFSEntrance
+	GTFSGenericNode
+	GTFSBoardingArea
+)
+
+// A GTFSStop is a place where vehicles pick up or drop off riders, from a GTFS stops.txt.
+type GTFSStop struct {
+	ID            string
+	Code          string
+	Name          string
+	Point         *Point
+	LocationType  int
+	ParentStation string
+}
+
+// A GTFSFeed is the geography of a GTFS transit feed: its stops, and the shapes
+// vehicles trace along their routes.
+type GTFSFeed struct {
+	Stops []*GTFSStop
+
+	// The shapes of the feed's trips, by shape_id, in sequence order.
+	Shapes map[string]*Polyline
+
+	stops map[string]*GTFSStop
+	index *KDTree[*GTFSStop]
+}
+
+// Loads the GTFS feed at the passed in path, which may be a zip file as published
+// by transit agencies or a directory it has been unzipped into.
+func LoadGTFS(path string) (*GTFSFeed, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		return ReadGTFS(os.DirFS(path))
+	}
+
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return ReadGTFS(r)
+}
+
+// Reads a GTFS feed from the passed in file system, in which stops.txt is required
+// and shapes.txt is optional.  Other files of the feed are ignored.
+func ReadGTFS(fsys fs.FS) (*GTFSFeed, error) {
+	feed := &GTFSFeed{
+		Shapes: make(map[string]*Polyline),
+		stops:  make(map[string]*GTFSStop),
+		index:  NewKDTree[*GTFSStop](),
+	}
+
+	err := readGTFSFile(fsys, "stops.txt", []string{"stop_id"}, func(row gtfsRow) error {
+		stop := &GTFSStop{
+			ID:            row.get("stop_id"),
+			Code:          row.get("stop_code"),
+			Name:          row.get("stop_name"),
+			ParentStation: row.get("parent_station"),
+		}
+
+		if t := row.get("location_type"); t != "" {
+			locationType, err := strconv.Atoi(t)
+			if err != nil {
+				return err
+			}
+			stop.LocationType = locationType
+		}
+
+		// Generic nodes and boarding areas may leave out their position.
+		if row.get("stop_lat") != "" || row.get("stop_lon") != "" {
+			p, err := row.point("stop_lat", "stop_lon")
+			if err != nil {
+				return err
+			}
+			stop.Point = p
+		}
+
+		feed.Stops = append(feed.Stops, stop)
+		feed.stops[stop.ID] = stop
+		if stop.Point != nil {
+			feed.index.Insert(stop.Point, stop)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sequences := make(map[string][]int)
+	err = readGTFSFile(fsys, "shapes.txt", []string{"shape_id", "shape_pt_lat", "shape_pt_lon", "shape_pt_sequence"}, func(row gtfsRow) error {
+		p, err := row.point("shape_pt_lat", "shape_pt_lon")
+		if err != nil {
+			return err
+		}
+
+		sequence, err := strconv.Atoi(row.get("shape_pt_sequence"))
+		if err != nil {
+			return err
+		}
+
+		id := row.get("shape_id")
+		shape, ok := feed.Shapes[id]
+		if !ok {
+			shape = &Polyline{}
+			feed.Shapes[id] = shape
+		}
+
+		shape.Points = append(shape.Points, p)
+		sequences[id] = append(sequences[id], sequence)
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	// Shape points may be listed in any order.
+	for id, shape := range feed.Shapes {
+		sort.Stable(&gtfsShapeSorter{points: shape.Points, sequences: sequences[id]})
+	}
+
+	feed.index.Build()
+
+	return feed, nil
+}
+
+// Returns the stop with the passed in stop_id, and whether or not there is one.
+func (f *GTFSFeed) Stop(id string) (*GTFSStop, bool) {
+	stop, ok := f.stops[id]
+	return stop, ok
+}
+
+// Returns the stops within the passed in distance, in kilometers, of the passed in point,
+// nearest first.  Stations and stops alike are returned; filter on LocationType to
+// keep only one kind.  Safe to call from multiple goroutines once the feed is loaded.
+func (f *GTFSFeed) NearestStops(p *Point, radius float64) []KDResult[*GTFSStop] {
+	return f.index.Within(p, radius)
+}
+
+// Sorts the points of a shape by their shape_pt_sequence.
+type gtfsShapeSorter struct {
+	points    []*Point
+	sequences []int
+}
+
+func (s *gtfsShapeSorter) Len() int           { return len(s.points) }
+func (s *gtfsShapeSorter) Less(i, j int) bool { return s.sequences[i] < s.sequences[j] }
+func (s *gtfsShapeSorter) Swap(i, j int) {
+	s.points[i], s.points[j] = s.points[j], s.points[i]
+	s.sequences[i], s.sequences[j] = s.sequences[j], s.sequences[i]
+}
+
+// A row of a GTFS file, along with the columns of its header.
+type gtfsRow struct {
+	columns map[string]int
+	record  []string
+}
+
+// Returns the value of the passed in column, or an empty string if the row doesn't have it.
+func (r gtfsRow) get(column string) string {
+	i, ok := r.columns[column]
+	if !ok || i >= len(r.record) {
+		return ""
+	}
+
+	return strings.TrimSpace(r.record[i])
+}
+
+// Returns the point whose latitude and longitude are in the passed in columns.
+func (r gtfsRow) point(latColumn, lngColumn string) (*Point, error) {
+	lat, err := strconv.ParseFloat(r.get(latColumn), 64)
+	if err != nil {
+		return nil, err
+	}
+
+	lng, err := strconv.ParseFloat(r.get(lngColumn), 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPoint(lat, lng), nil
+}
+
+// Calls fn with each row of the passed in GTFS file, which must have the passed in columns.
+// Errors are annotated with the file and line they occurred on.
+func readGTFSFile(fsys fs.FS, name string, required []string, fn func(row gtfsRow) error) error {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	cr := csv.NewReader(f)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return fmt.Errorf("%s: %v", name, err)
+	}
+
+	columns := make(map[string]int)
+	for i, column := range header {
+		// Files exported from spreadsheets often start with a byte order mark.
+		columns[strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))] = i
+	}
+
+	for _, column := range required {
+		if _, ok := columns[column]; !ok {
+			return fmt.Errorf("%s is missing the %q column", name, column)
+		}
+	}
+
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+
+		if err := fn(gtfsRow{columns: columns, record: record}); err != nil {
+			return fmt.Errorf("%s line %d: %v", name, line, err)
+		}
+	}
+}
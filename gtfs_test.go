@@ -0,0 +1,129 @@
+package geo
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// Returns the path of a zip file holding the test GTFS feed.
+func zipTestGTFS(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "feed.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for _, name := range []string{"stops.txt", "shapes.txt", "routes.txt"} {
+		data, err := os.ReadFile(filepath.Join("test/data/gtfs", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entry, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry.Write(data)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+// Ensures that stops and shapes are read alike from directories and zip files.
+func TestLoadGTFS(t *testing.T) {
+	for _, path := range []string{"test/data/gtfs", zipTestGTFS(t)} {
+		feed, err := LoadGTFS(path)
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+
+		if len(feed.Stops) != 6 {
+			t.Errorf("%s: expected 6 stops, got %d", path, len(feed.Stops))
+		}
+
+		stop, ok := feed.Stop("PWL_1")
+		if !ok || stop.Name != "Powell St Platform 1" || stop.ParentStation != "PWL" || stop.LocationType != GTFSStopOrPlatform {
+			t.Errorf("%s: unexpected stop %+v", path, stop)
+		}
+
+		if stop, ok := feed.Stop("PWL"); !ok || stop.LocationType != GTFSStation {
+			t.Errorf("%s: expected PWL to be a station, got %+v", path, stop)
+		}
+
+		if stop, ok := feed.Stop("N1"); !ok || stop.Point != nil {
+			t.Errorf("%s: expected a generic node without a position, got %+v", path, stop)
+		}
+
+		shape := feed.Shapes["F_OUT"]
+		if shape == nil || len(shape.Points) != 3 {
+			t.Fatalf("%s: unexpected shape %+v", path, shape)
+		}
+
+		if shape.Points[0].Lat() != 37.784991 || shape.Points[2].Lat() != 37.779528 {
+			t.Errorf("%s: expected shape points in sequence order, got %v", path, shape.Points)
+		}
+	}
+}
+
+// Ensures that NearestStops returns the stops within the radius, nearest first.
+func TestGTFSNearestStops(t *testing.T) {
+	feed, err := LoadGTFS("test/data/gtfs")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := feed.NearestStops(NewPoint(37.7850, -122.4068), 0.5)
+	var ids []string
+	for _, r := range results {
+		ids = append(ids, r.Value.ID)
+	}
+
+	if strings.Join(ids, ",") != "PWL,PWL_1,15730" {
+		t.Errorf("Expected PWL,PWL_1,15730, got %v", ids)
+	}
+
+	if len(feed.NearestStops(NewPoint(0, 0), 10)) != 0 {
+		t.Error("Expected no stops far from the feed")
+	}
+}
+
+// Ensures that malformed feeds are rejected with the file and line at fault,
+// and that shapes.txt is optional.
+func TestReadGTFSErrors(t *testing.T) {
+	feed, err := ReadGTFS(fstest.MapFS{
+		"stops.txt": {Data: []byte("stop_id,stop_name,stop_lat,stop_lon\nA,Alpha,1,2\n")},
+	})
+	if err != nil || len(feed.Stops) != 1 || len(feed.Shapes) != 0 {
+		t.Errorf("Expected a feed without shapes to load, got %+v (%v)", feed, err)
+	}
+
+	tests := map[string]fstest.MapFS{
+		"open stops.txt": {},
+		"stops.txt is missing the \"stop_id\" column": {
+			"stops.txt": {Data: []byte("stop_name,stop_lat,stop_lon\nAlpha,1,2\n")},
+		},
+		"stops.txt line 2": {
+			"stops.txt": {Data: []byte("stop_id,stop_name,stop_lat,stop_lon\nA,Alpha,north,2\n")},
+		},
+		"shapes.txt line 3": {
+			"stops.txt":  {Data: []byte("stop_id,stop_name,stop_lat,stop_lon\nA,Alpha,1,2\n")},
+			"shapes.txt": {Data: []byte("shape_id,shape_pt_lat,shape_pt_lon,shape_pt_sequence\nS,1,2,1\nS,1,2,first\n")},
+		},
+	}
+
+	for expected, fsys := range tests {
+		if _, err := ReadGTFS(fsys); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected an error containing %q, got %v", expected, err)
+		}
+	}
+}
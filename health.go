@@ -0,0 +1,36 @@
+package geo
+
+import (
+	"context"
+	"errors"
+)
+
+// The point that canary requests are made at: Null Island, in the Gulf of Guinea.
+// Providers answer it cheaply, usually with no results, which is enough to show they are working.
+var healthCheckPoint = &Point{lat: 0, lng: 0}
+
+// A HealthChecker can tell whether it is able to answer requests, by issuing a cheap canary request
+// to its provider or pinging its database, so that services can report their readiness.
+// Canary requests count against a Quota like any other.
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// Returns the result of the passed in value's HealthCheck, or nil if it isn't a HealthChecker.
+func healthCheck(ctx context.Context, v interface{}) error {
+	if h, ok := v.(HealthChecker); ok {
+		return h.HealthCheck(ctx)
+	}
+
+	return nil
+}
+
+// Returns nil if the passed in error from a canary request shows the provider answered,
+// even if it found nothing, or the error otherwise.
+func canaryError(err error) error {
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+
+	return err
+}
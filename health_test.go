@@ -0,0 +1,110 @@
+package geo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// A HealthChecker that fails with a fixed error.
+type stubHealthChecker struct {
+	stubGeocoder
+	health error
+}
+
+func (s *stubHealthChecker) HealthCheck(ctx context.Context) error {
+	return s.health
+}
+
+// Ensures that Google is healthy when it answers the canary, even with no results, and not when it refuses it.
+func TestGoogleGeocoderHealthCheck(t *testing.T) {
+	status := "ZERO_RESULTS"
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		w.Write([]byte(`{"results":[],"status":"` + status + `"}`))
+	}))
+	defer server.Close()
+
+	g := NewGoogleGeocoder(WithBaseURL(server.URL), WithAPIKey("secret"))
+	if err := g.HealthCheck(context.Background()); err != nil {
+		t.Errorf("Expected Google to be healthy, got %v", err)
+	}
+
+	if q := queries[0]; q.Get("latlng") != "0,0" || q.Get("key") != "secret" {
+		t.Errorf("Expected an authenticated canary at Null Island, got %v", q)
+	}
+
+	status = "REQUEST_DENIED"
+	if err := g.HealthCheck(context.Background()); !errors.Is(err, ErrAuth) {
+		t.Errorf("Expected %v, got %v", ErrAuth, err)
+	}
+}
+
+// Ensures that MapQuest is unhealthy when it can't be reached in time.
+func TestMapQuestGeocoderHealthCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("key") == "slow" {
+			<-r.Context().Done()
+			return
+		}
+		w.Write([]byte(`{"error":"Unable to geocode"}`))
+	}))
+	defer server.Close()
+
+	if err := NewMapQuestGeocoder(WithBaseURL(server.URL)).HealthCheck(context.Background()); err != nil {
+		t.Errorf("Expected MapQuest to be healthy, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := NewMapQuestGeocoder(WithBaseURL(server.URL), WithAPIKey("slow")).HealthCheck(ctx)
+	if !errors.Is(err, ErrProviderDown) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the provider to be down past the deadline, got %v", err)
+	}
+}
+
+// Ensures that the distance matrix is healthy when it answers the canary.
+func TestGoogleDistanceMatrixHealthCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": "OK", "rows": [{"elements": [{"status": "ZERO_RESULTS"}]}]}`))
+	}))
+	defer server.Close()
+
+	if err := NewGoogleDistanceMatrix(WithBaseURL(server.URL)).HealthCheck(context.Background()); err != nil {
+		t.Errorf("Expected the distance matrix to be healthy, got %v", err)
+	}
+}
+
+// Ensures that wrappers report their providers' health, and a RankingGeocoder is healthy while any provider is.
+func TestWrappedHealthCheck(t *testing.T) {
+	down := &ProviderError{Provider: "google", Kind: ErrProviderDown, Status: "unreachable"}
+	sick := &stubHealthChecker{health: down}
+	well := &stubHealthChecker{}
+
+	for _, g := range []HealthChecker{
+		&CachedGeocoder{Geocoder: sick},
+		&CoalescingGeocoder{Geocoder: sick},
+		NewCircuitBreakerGeocoder(sick, 1, time.Minute),
+	} {
+		if err := g.HealthCheck(context.Background()); err != down {
+			t.Errorf("Expected %T to report %v, got %v", g, down, err)
+		}
+	}
+
+	if err := NewRankingGeocoder(nil, NewCircuitBreakerGeocoder(sick, 1, time.Minute), NewCircuitBreakerGeocoder(well, 1, time.Minute)).HealthCheck(context.Background()); err != nil {
+		t.Errorf("Expected a provider to be enough, got %v", err)
+	}
+
+	if err := NewRankingGeocoder(nil, NewCircuitBreakerGeocoder(sick, 1, time.Minute)).HealthCheck(context.Background()); !errors.Is(err, ErrProviderDown) {
+		t.Errorf("Expected %v, got %v", down, err)
+	}
+
+	if err := NewGazetteerGeocoder(nil).HealthCheck(context.Background()); err == nil {
+		t.Errorf("Expected an empty gazetteer to be unhealthy")
+	}
+}
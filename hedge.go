@@ -0,0 +1,72 @@
+package geo
+
+import (
+	"time"
+)
+
+// The outcome of one of the requests a hedge issued.
+type hedgedResult[T any] struct {
+	i     int
+	value T
+	err   error
+}
+
+// Issues the request fn makes for the first of n providers, then for each of the others in turn whenever
+// the last has taken delay without answering, or has failed, and returns the first successful answer.
+// If every request fails, returns the first provider's error, or rankingNoResultsError if there are none.
+// Requests still in flight once an answer is returned are left to finish, and their answers discarded.
+func hedge[T any](n int, delay time.Duration, fn func(i int) (T, error)) (T, error) {
+	var zero T
+	if n == 0 {
+		return zero, rankingNoResultsError
+	}
+
+	// Buffered so that requests finishing after the answer don't block forever.
+	results := make(chan hedgedResult[T], n)
+	issue := func(i int) {
+		go func() {
+			value, err := fn(i)
+			results <- hedgedResult[T]{i: i, value: value, err: err}
+		}()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	errs := make([]error, n)
+	issued, failed := 1, 0
+	issue(0)
+
+	for failed < n {
+		select {
+		case r := <-results:
+			if r.err == nil {
+				return r.value, nil
+			}
+
+			errs[r.i] = r.err
+			failed++
+
+			// Don't wait out the delay for a provider that has already failed.
+			if issued < n && failed == issued {
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+				issue(issued)
+				issued++
+				timer.Reset(delay)
+			}
+		case <-timer.C:
+			if issued < n {
+				issue(issued)
+				issued++
+				timer.Reset(delay)
+			}
+		}
+	}
+
+	return zero, errs[0]
+}
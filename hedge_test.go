@@ -0,0 +1,81 @@
+package geo
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// A ResultGeocoder that answers after a fixed delay.
+type slowGeocoder struct {
+	stubResultGeocoder
+	delay time.Duration
+}
+
+func (s *slowGeocoder) GeocodeResults(query string, opts ...QueryOption) ([]*GeocodeResult, error) {
+	time.Sleep(s.delay)
+	return s.stubResultGeocoder.GeocodeResults(query, opts...)
+}
+
+func (s *slowGeocoder) ReverseGeocode(p *Point, opts ...QueryOption) (string, error) {
+	time.Sleep(s.delay)
+	return s.stubResultGeocoder.ReverseGeocode(p, opts...)
+}
+
+// Ensures that hedged requests answer from whichever provider answers first.
+func TestHedge(t *testing.T) {
+	start := time.Now()
+	v, err := hedge(3, 10*time.Millisecond, func(i int) (int, error) {
+		if i == 0 {
+			time.Sleep(time.Second)
+		}
+		return i, nil
+	})
+	if err != nil || v != 1 {
+		t.Errorf("Expected the second provider's answer, got %v (%v)", v, err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the slow provider not to be waited for, took %v", elapsed)
+	}
+
+	// A failure moves on to the next provider without waiting out the delay.
+	start = time.Now()
+	v, err = hedge(2, time.Second, func(i int) (int, error) {
+		if i == 0 {
+			return 0, errors.New("down")
+		}
+		return i, nil
+	})
+	if err != nil || v != 1 || time.Since(start) > 500*time.Millisecond {
+		t.Errorf("Expected the second provider's answer straight away, got %v (%v) after %v", v, err, time.Since(start))
+	}
+
+	first := errors.New("first")
+	if _, err := hedge(2, time.Millisecond, func(i int) (int, error) {
+		if i == 0 {
+			return 0, first
+		}
+		return 0, errors.New("second")
+	}); err != first {
+		t.Errorf("Expected the first provider's error, got %v", err)
+	}
+}
+
+// Ensures that a RankingGeocoder with a HedgeDelay doesn't wait for a slow provider.
+func TestRankingGeocoderHedgeDelay(t *testing.T) {
+	sfo := NewPoint(37.615223, -122.389979)
+	slow := &slowGeocoder{delay: time.Second, stubResultGeocoder: stubResultGeocoder{results: []*GeocodeResult{{Point: NewPoint(0, 0), FormattedAddress: "Slow"}}}}
+	fast := &stubResultGeocoder{results: []*GeocodeResult{{Point: sfo, FormattedAddress: "San Francisco International Airport"}}}
+
+	g := NewRankingGeocoder(nil, slow, fast)
+	g.HedgeDelay = 20 * time.Millisecond
+
+	start := time.Now()
+	p, err := g.Geocode("San Francisco International Airport")
+	if err != nil || p != sfo {
+		t.Errorf("Expected the fast provider's result, got %v (%v)", p, err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the slow provider not to be waited for, took %v", elapsed)
+	}
+}
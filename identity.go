@@ -0,0 +1,91 @@
+package geo
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// The User-Agent every request is sent with unless an Identity says otherwise.
+const DEFAULT_USER_AGENT = "golang-geo"
+
+// An Identity tells providers which application is making requests, and how to reach its operators.
+// Some providers require it: the usage policies of OpenStreetMap's Nominatim, Overpass and tile servers
+// refuse requests without an application specific User-Agent, and other services ask for a Referer
+// identifying the site their results are shown on.
+type Identity struct {
+	// The name of the application, optionally with a version, e.g. "acme-dispatch/2.1".
+	AppName string
+
+	// An email address or URL providers can contact the application's operators at.
+	Contact string
+
+	// If set, sent as the Referer of every request.
+	Referer string
+}
+
+// Returns the User-Agent the current Identity sends, e.g. "acme-dispatch/2.1 (ops@acme.com) golang-geo",
+// or DEFAULT_USER_AGENT if it names neither an application nor a contact.
+func (id Identity) UserAgent() string {
+	var parts []string
+	if id.AppName != "" {
+		parts = append(parts, id.AppName)
+	}
+	if id.Contact != "" {
+		parts = append(parts, "("+id.Contact+")")
+	}
+
+	return strings.Join(append(parts, DEFAULT_USER_AGENT), " ")
+}
+
+// Sets the User-Agent and, if there is one, the Referer of the current Identity on the passed in request.
+func (id Identity) apply(req *http.Request) {
+	req.Header.Set("User-Agent", id.UserAgent())
+	if id.Referer != "" {
+		req.Header.Set("Referer", id.Referer)
+	}
+}
+
+var (
+	identityMu sync.RWMutex
+	identity   Identity
+)
+
+// Returns the package-wide Identity set with SetIdentity.
+func DefaultIdentity() Identity {
+	identityMu.RLock()
+	defer identityMu.RUnlock()
+
+	return identity
+}
+
+// Sets the Identity every provider request is sent with, unless the provider was created WithIdentity.
+// Applications should call it once at startup, before issuing requests.
+func SetIdentity(id Identity) {
+	identityMu.Lock()
+	defer identityMu.Unlock()
+
+	identity = id
+}
+
+// Sets the package-wide Identity on the passed in request, unless it already has a User-Agent.
+func identify(req *http.Request) {
+	if req.Header.Get("User-Agent") == "" {
+		DefaultIdentity().apply(req)
+	}
+}
+
+// An http.RoundTripper sending every request with an Identity.
+type identityTransport struct {
+	identity Identity
+	base     http.RoundTripper
+}
+
+func (t *identityTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A RoundTripper mustn't modify the request it is given.
+	// Replace, rather than add to, whatever the package-wide Identity set.
+	req = req.Clone(req.Context())
+	req.Header.Del("Referer")
+	t.identity.apply(req)
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,42 @@
+package geo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Ensures that User-Agents name the application and its contact, and fall back on DEFAULT_USER_AGENT.
+func TestIdentityUserAgent(t *testing.T) {
+	if ua := (Identity{}).UserAgent(); ua != DEFAULT_USER_AGENT {
+		t.Errorf("Expected %s, got %s", DEFAULT_USER_AGENT, ua)
+	}
+
+	id := Identity{AppName: "acme-dispatch/2.1", Contact: "ops@acme.com"}
+	if ua := id.UserAgent(); ua != "acme-dispatch/2.1 (ops@acme.com) golang-geo" {
+		t.Errorf("Expected acme-dispatch/2.1 (ops@acme.com) golang-geo, got %s", ua)
+	}
+}
+
+// Ensures that providers send the package-wide Identity, unless created WithIdentity.
+func TestSetIdentity(t *testing.T) {
+	var userAgent, referer string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent, referer = r.Header.Get("User-Agent"), r.Header.Get("Referer")
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	defer SetIdentity(DefaultIdentity())
+	SetIdentity(Identity{AppName: "acme", Contact: "ops@acme.com", Referer: "https://acme.com"})
+
+	NewMapQuestGeocoder(WithBaseURL(server.URL)).Geocode("SFO")
+	if userAgent != "acme (ops@acme.com) golang-geo" || referer != "https://acme.com" {
+		t.Errorf("Expected the package-wide Identity, got %q and %q", userAgent, referer)
+	}
+
+	NewMapQuestGeocoder(WithBaseURL(server.URL), WithIdentity(Identity{AppName: "dispatch"})).Geocode("SFO")
+	if userAgent != "dispatch golang-geo" || referer != "" {
+		t.Errorf("Expected the provider's Identity, got %q and %q", userAgent, referer)
+	}
+}
@@ -0,0 +1,187 @@
+package geo
+
+// An ImmutablePoint is a point held by value, with no way to change it once made.
+// Unlike a *Point, it can be shared between goroutines, stored in maps and compared with ==
+// without copying it first.  Mutable converts it back for functions taking a *Point.
+type ImmutablePoint struct {
+	lat, lng float64
+}
+
+// Returns a new ImmutablePoint at the passed in latitude and longitude.
+func NewImmutablePoint(lat float64, lng float64) ImmutablePoint {
+	return ImmutablePoint{lat: lat, lng: lng}
+}
+
+// Returns an ImmutablePoint at the current Point's position.
+func (p *Point) Immutable() ImmutablePoint {
+	return ImmutablePoint{lat: p.lat, lng: p.lng}
+}
+
+// Returns the ImmutablePoint's latitude.
+func (p ImmutablePoint) Lat() float64 {
+	return p.lat
+}
+
+// Returns the ImmutablePoint's longitude.
+func (p ImmutablePoint) Lng() float64 {
+	return p.lng
+}
+
+// Returns a new *Point at the ImmutablePoint's position, which may be changed without affecting it.
+func (p ImmutablePoint) Mutable() *Point {
+	return NewPoint(p.lat, p.lng)
+}
+
+// Returns the great circle distance, in kilometers, from the ImmutablePoint to the passed in one.
+func (p ImmutablePoint) GreatCircleDistance(q ImmutablePoint) float64 {
+	return HaversineDistance(p.lat, p.lng, q.lat, q.lng)
+}
+
+// Returns the initial bearing, in degrees, from the ImmutablePoint to the passed in one.
+func (p ImmutablePoint) BearingTo(q ImmutablePoint) float64 {
+	return InitialBearing(p.lat, p.lng, q.lat, q.lng)
+}
+
+// Renders the ImmutablePoint as a Point would be.
+func (p ImmutablePoint) MarshalJSON() ([]byte, error) {
+	return p.Mutable().MarshalJSON()
+}
+
+// An ImmutablePolygon is a Polygon with no way to change it once made.  It holds its own copy
+// of its points, so that neither the Polygon it was made from nor any Polygon made from it
+// with Mutable can change it, and so it can be shared between goroutines without copying it.
+// The zero value is an empty polygon.
+type ImmutablePolygon struct {
+	// A private copy, never handed out, that the Polygon methods are read from.
+	polygon *Polygon
+}
+
+// Returns a new ImmutablePolygon of the passed in points.
+func NewImmutablePolygon(points []ImmutablePoint) ImmutablePolygon {
+	copied := make([]*Point, len(points))
+	for i, p := range points {
+		copied[i] = p.Mutable()
+	}
+
+	return ImmutablePolygon{polygon: NewPolygon(copied)}
+}
+
+// Returns an ImmutablePolygon of a copy of the current Polygon's points.
+func (p *Polygon) Immutable() ImmutablePolygon {
+	return ImmutablePolygon{polygon: p.copy()}
+}
+
+// Returns a copy of the current Polygon, with copies of its points.
+func (p *Polygon) copy() *Polygon {
+	points := make([]*Point, len(p.points))
+	for i, point := range p.points {
+		points[i] = NewPoint(point.lat, point.lng)
+	}
+
+	return NewPolygon(points)
+}
+
+// Returns the Polygon methods are read from, which is empty for the zero value.
+func (p ImmutablePolygon) read() *Polygon {
+	if p.polygon == nil {
+		return &Polygon{}
+	}
+
+	return p.polygon
+}
+
+// Returns a new Polygon with copies of the ImmutablePolygon's points, which may be changed without affecting it.
+func (p ImmutablePolygon) Mutable() *Polygon {
+	return p.read().copy()
+}
+
+// Returns the number of points of the ImmutablePolygon.
+func (p ImmutablePolygon) Len() int {
+	return len(p.read().points)
+}
+
+// Returns the ImmutablePolygon's i'th point.
+func (p ImmutablePolygon) At(i int) ImmutablePoint {
+	return p.read().points[i].Immutable()
+}
+
+// Returns the points of the ImmutablePolygon.
+func (p ImmutablePolygon) Points() []ImmutablePoint {
+	points := make([]ImmutablePoint, p.Len())
+	for i, point := range p.read().points {
+		points[i] = point.Immutable()
+	}
+
+	return points
+}
+
+// Returns whether or not the ImmutablePolygon is closed, as Polygon.IsClosed does.
+func (p ImmutablePolygon) IsClosed() bool {
+	return p.read().IsClosed()
+}
+
+// Returns whether or not the ImmutablePolygon contains the passed in point, as Polygon.Contains does.
+func (p ImmutablePolygon) Contains(point ImmutablePoint) bool {
+	return p.read().Contains(point.Mutable())
+}
+
+// Returns the ImmutablePolygon's area, in square kilometers, as Polygon.Area does.
+func (p ImmutablePolygon) Area() float64 {
+	return p.read().Area()
+}
+
+// Returns the ImmutablePolygon's centroid, as Polygon.Centroid does,
+// and whether or not it has one: polygons that are not closed don't.
+func (p ImmutablePolygon) Centroid() (ImmutablePoint, bool) {
+	c := p.read().Centroid()
+	if c == nil {
+		return ImmutablePoint{}, false
+	}
+
+	return c.Immutable(), true
+}
+
+// Returns the distance, in kilometers, from the passed in point to the ImmutablePolygon, as Polygon.DistanceTo does.
+func (p ImmutablePolygon) DistanceTo(point ImmutablePoint) float64 {
+	return p.read().DistanceTo(point.Mutable())
+}
+
+// An ImmutableLine is a Line with no way to change it once made, holding its own copy of its points.
+type ImmutableLine struct {
+	points []ImmutablePoint
+}
+
+// Returns an ImmutableLine of a copy of the current Line's points.
+func (l Line) Immutable() ImmutableLine {
+	points := make([]ImmutablePoint, len(l))
+	for i, p := range l {
+		points[i] = p.Immutable()
+	}
+
+	return ImmutableLine{points: points}
+}
+
+// Returns a new Line with copies of the ImmutableLine's points, which may be changed without affecting it.
+func (l ImmutableLine) Mutable() Line {
+	line := make(Line, len(l.points))
+	for i, p := range l.points {
+		line[i] = p.Mutable()
+	}
+
+	return line
+}
+
+// Returns the number of points of the ImmutableLine.
+func (l ImmutableLine) Len() int {
+	return len(l.points)
+}
+
+// Returns the ImmutableLine's i'th point.
+func (l ImmutableLine) At(i int) ImmutablePoint {
+	return l.points[i]
+}
+
+// Returns the points of the ImmutableLine.
+func (l ImmutableLine) Points() []ImmutablePoint {
+	return append([]ImmutablePoint(nil), l.points...)
+}
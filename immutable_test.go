@@ -0,0 +1,62 @@
+package geo
+
+import (
+	"sync"
+	"testing"
+)
+
+// Ensures that immutable geometries are unaffected by changes to what they were made from or converted to.
+func TestImmutablePolygon(t *testing.T) {
+	points := []*Point{NewPoint(0, 0), NewPoint(0, 10), NewPoint(10, 10), NewPoint(10, 0)}
+	mutable := NewPolygon(points)
+	frozen := mutable.Immutable()
+
+	mutable.Add(NewPoint(20, 20))
+	points[0].UnmarshalJSON([]byte(`{"lat":-5,"lng":-5}`))
+	if frozen.Len() != 4 || frozen.At(0) != NewImmutablePoint(0, 0) {
+		t.Errorf("Expected the polygon to keep its 4 points, got %v", frozen.Points())
+	}
+
+	thawed := frozen.Mutable()
+	thawed.Add(NewPoint(20, 20))
+	thawed.Points()[1].UnmarshalJSON([]byte(`{"lat":-5,"lng":-5}`))
+	if frozen.Len() != 4 || frozen.At(1) != NewImmutablePoint(0, 10) {
+		t.Errorf("Expected Mutable to copy, got %v", frozen.Points())
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if !frozen.Contains(NewImmutablePoint(5, 5)) || frozen.Contains(NewImmutablePoint(15, 5)) {
+				t.Error("Expected (5, 5) inside and (15, 5) outside")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if c, ok := frozen.Centroid(); !ok || c.GreatCircleDistance(NewImmutablePoint(5, 5)) > 50 {
+		t.Errorf("Expected a centroid near (5, 5), got %v", c)
+	}
+
+	if _, ok := (ImmutablePolygon{}).Centroid(); ok || (ImmutablePolygon{}).Len() != 0 {
+		t.Error("Expected the zero value to be an empty polygon")
+	}
+}
+
+// Ensures that immutable points and lines convert to and from their mutable forms.
+func TestImmutablePointAndLine(t *testing.T) {
+	p := NewPoint(37.615223, -122.389979)
+	if frozen := p.Immutable(); frozen.Lat() != p.Lat() || frozen.Mutable() == p || frozen.Mutable().Lng() != p.Lng() {
+		t.Errorf("Expected a copy of %v, got %v", p, frozen)
+	}
+
+	line := Line{NewPoint(1, 2), NewPoint(3, 4)}
+	frozen := line.Immutable()
+	line[0] = NewPoint(9, 9)
+	frozen.Points()[1] = NewImmutablePoint(9, 9)
+	if frozen.Len() != 2 || frozen.At(0) != NewImmutablePoint(1, 2) || frozen.At(1) != NewImmutablePoint(3, 4) {
+		t.Errorf("Expected the line to keep its points, got %v", frozen.Points())
+	}
+}
@@ -0,0 +1,165 @@
+package geo
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// A JournalEntry records a single request issued to a provider.
+type JournalEntry struct {
+	// When the request was issued.
+	Time time.Time `json:"time"`
+
+	// The name of the provider, as counted by a Quota, e.g. "google" or "mapquest".
+	Provider string `json:"provider"`
+
+	Method string `json:"method"`
+
+	// The URL requested, with credentials redacted.
+	URL string `json:"url"`
+
+	// The HTTP status code of the response, or 0 if there was none.
+	Status int `json:"status"`
+
+	// Why there was no response, if there wasn't one.
+	Error string `json:"error,omitempty"`
+
+	// How long the provider took to respond, in milliseconds.
+	LatencyMs int64 `json:"latency_ms"`
+}
+
+// A Journal appends a JournalEntry, as a line of JSON, for every request issued to a provider,
+// so that paid API usage can be audited and reconciled against the provider's invoices:
+//
+//	j, err := geo.OpenJournal("/var/log/geo/requests.jsonl")
+//	g := geo.NewGoogleGeocoder(geo.WithAPIKey(key), geo.WithJournal(j))
+//
+// Only requests that were actually issued are journaled: those refused by a Quota,
+// or failed by Middleware before they were sent, are not.  A Journal is safe to share
+// between goroutines and providers.
+type Journal struct {
+	mu  sync.Mutex
+	w   io.Writer
+	err error
+
+	// Used to determine the current time.  Overridable for testing.
+	now func() time.Time
+}
+
+// Creates and returns a pointer to a new Journal appending to the passed in writer.
+func NewJournal(w io.Writer) *Journal {
+	return &Journal{w: w}
+}
+
+// Opens the journal file at the passed in path for appending, creating it if it doesn't exist,
+// and returns a Journal writing to it.  The Journal must be closed once no more requests will be issued.
+func OpenJournal(path string) (*Journal, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewJournal(f), nil
+}
+
+// Closes the file of a Journal created with OpenJournal.  Returns the first error met appending to
+// the journal, if any, since a journal missing entries can't be trusted for reconciliation.
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if c, ok := j.w.(io.Closer); ok {
+		if err := c.Close(); err != nil && j.err == nil {
+			j.err = err
+		}
+	}
+
+	return j.err
+}
+
+// Appends the passed in entry to the journal.
+func (j *Journal) append(entry *JournalEntry) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	// Write each entry with a single call, so that entries appended by several processes to the same file don't interleave.
+	if _, err := j.w.Write(append(line, '\n')); err != nil && j.err == nil {
+		j.err = err
+	}
+}
+
+func (j *Journal) clock() time.Time {
+	if j.now == nil {
+		return time.Now()
+	}
+
+	return j.now()
+}
+
+// Returns an http.RoundTripper that journals the requests of the named provider,
+// issuing them with the passed in transport, or the shared transport if it is nil.
+func (j *Journal) Transport(provider string, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = defaultTransport()
+	}
+
+	return &journalTransport{journal: j, provider: provider, base: base}
+}
+
+// An http.RoundTripper journaling the requests of a provider.
+type journalTransport struct {
+	journal  *Journal
+	provider string
+	base     http.RoundTripper
+}
+
+func (t *journalTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := t.journal.clock()
+	resp, err := t.base.RoundTrip(req)
+
+	entry := &JournalEntry{
+		Time:      start.UTC(),
+		Provider:  t.provider,
+		Method:    req.Method,
+		URL:       scrubURL(req.URL).String(),
+		LatencyMs: t.journal.clock().Sub(start).Milliseconds(),
+	}
+	if err != nil {
+		entry.Error = scrubError(err).Error()
+	} else {
+		entry.Status = resp.StatusCode
+	}
+
+	t.journal.append(entry)
+	return resp, err
+}
+
+// Reads every entry of a journal written by a Journal, in the order they were appended.
+func ReadJournal(r io.Reader) ([]*JournalEntry, error) {
+	var entries []*JournalEntry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		entry := &JournalEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), entry); err != nil {
+			return entries, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
@@ -0,0 +1,80 @@
+package geo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Ensures that every request issued is journaled, without its credentials, and read back.
+func TestJournal(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("address") == "limit" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"results":[],"status":"ZERO_RESULTS"}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "requests.jsonl")
+	j, err := OpenJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	j.now = func() time.Time { return time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC) }
+
+	q := NewQuota()
+	q.SetDailyBudget("google", 2)
+	g := NewGoogleGeocoder(WithBaseURL(server.URL), WithAPIKey("secret"), WithQuota(q), WithJournal(j))
+	g.Geocode("Atlantis")
+	g.Geocode("limit")
+	g.Geocode("refused by the quota")
+	NewMapQuestGeocoder(WithBaseURL("http://127.0.0.1:0"), WithJournal(j)).Geocode("Atlantis")
+
+	if err := j.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reopening appends rather than truncates.
+	j, _ = OpenJournal(path)
+	NewMapQuestGeocoder(WithBaseURL(server.URL), WithJournal(j)).Geocode("Atlantis")
+	j.Close()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	entries, err := ReadJournal(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 4 {
+		t.Fatalf("Expected 4 entries, got %d", len(entries))
+	}
+
+	expected := []struct {
+		provider string
+		status   int
+	}{{"google", 200}, {"google", 429}, {"mapquest", 0}, {"mapquest", 200}}
+	for i, e := range expected {
+		if entries[i].Provider != e.provider || entries[i].Status != e.status {
+			t.Errorf("Expected %s %d, got %s %d", e.provider, e.status, entries[i].Provider, entries[i].Status)
+		}
+	}
+
+	if strings.Contains(entries[0].URL, "secret") || !strings.Contains(entries[0].URL, "address=Atlantis") {
+		t.Errorf("Expected the URL without its API key, got %s", entries[0].URL)
+	}
+
+	if entries[2].Error == "" || !entries[0].Time.Equal(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected an error and a timestamp, got %+v", entries[2])
+	}
+}
@@ -0,0 +1,120 @@
+package geo
+
+import (
+	"errors"
+	"math"
+)
+
+// The factor by which the atmosphere's refraction of radio waves bends them around the earth,
+// as though it were this much larger; the standard "4/3 earth" of radio planning.
+const STANDARD_REFRACTION_FACTOR = 4.0 / 3
+
+// The spacing, in meters, of the elevations LineOfSight asks for, about that of the SRTM elevation data
+// most providers draw on.  Long paths are sampled more sparsely, at MAX_ELEVATION_SAMPLES points.
+const DEFAULT_ELEVATION_SPACING = 30
+
+// This is the error that consumers receive when an elevation profile has fewer than two samples.
+var shortElevationProfileError = errors.New("elevation profile must have at least two samples")
+
+// How far the sight line passes above the ground at one sample of an elevation profile.
+type sightSample struct {
+	distance  float64
+	clearance float64
+}
+
+// Whether or not two points can see each other over the ground between them.
+type Visibility struct {
+	Visible bool
+
+	// The least height, in meters, of the sight line above the ground between the points,
+	// negative where the ground rises above it.  Infinite if the profile has no samples between them.
+	Clearance float64
+
+	// Where the sight line passes lowest above the ground, or furthest below it,
+	// and how far that is, in kilometers, from the first point.  Nil if Clearance is infinite.
+	Critical         *Point
+	CriticalDistance float64
+
+	length  float64
+	samples []sightSample
+}
+
+// Returns the least clearance of the sight line, as a fraction of the radius of the first Fresnel zone
+// of a radio link of the passed in frequency, in megahertz, between the points.  Links with a fraction
+// of 0.6 or more are usually taken to be clear; those with less lose signal to the ground even
+// where the points can see each other.
+func (v *Visibility) FresnelClearance(frequencyMHz float64) float64 {
+	least := math.Inf(1)
+	for _, s := range v.samples {
+		if radius := FresnelRadius(s.distance, v.length-s.distance, frequencyMHz); radius > 0 {
+			least = math.Min(least, s.clearance/radius)
+		}
+	}
+
+	return least
+}
+
+// Returns the radius, in meters, of the first Fresnel zone of a radio link of the passed in frequency,
+// in megahertz, at the passed in distances, in kilometers, from either end of the link.
+func FresnelRadius(d1 float64, d2 float64, frequencyMHz float64) float64 {
+	if d1 <= 0 || d2 <= 0 || frequencyMHz <= 0 {
+		return 0
+	}
+
+	wavelength := 299792458 / (frequencyMHz * 1e6)
+	return math.Sqrt(wavelength * d1 * d2 * 1000 / (d1 + d2))
+}
+
+// Returns whether or not the passed in points can see each other from the passed in heights,
+// in meters above the ground, such as the heights of antennas on masts or of a drone and its pilot.
+// The ground between them is looked up with the passed in ElevationProvider, with a sample about
+// every DEFAULT_ELEVATION_SPACING meters, and the sight line is bent with the earth's curvature
+// as the atmosphere bends radio waves, by STANDARD_REFRACTION_FACTOR.
+func LineOfSight(from, to *Point, elevations ElevationProvider, fromHeight, toHeight float64) (*Visibility, error) {
+	samples := int(math.Ceil(from.GreatCircleDistance(to)*1000/DEFAULT_ELEVATION_SPACING)) + 1
+	profile, err := elevations.ElevationProfile(from, to, max(2, min(samples, MAX_ELEVATION_SAMPLES)))
+	if err != nil {
+		return nil, err
+	}
+
+	return ProfileLineOfSight(from, to, profile, fromHeight, toHeight)
+}
+
+// Returns whether or not the passed in points can see each other from the passed in heights,
+// in meters above the ground, over the passed in elevation profile: the elevations, in meters
+// above sea level, of points spaced evenly along the great circle between them, both included.
+// Over long distances the earth's curvature hides what flat ground would not; the sight line is bent
+// with it as the atmosphere bends radio waves, by STANDARD_REFRACTION_FACTOR.
+func ProfileLineOfSight(from, to *Point, profile []float64, fromHeight, toHeight float64) (*Visibility, error) {
+	if len(profile) < 2 {
+		return nil, shortElevationProfileError
+	}
+
+	length := from.GreatCircleDistance(to)
+	radius := EARTH_RADIUS * STANDARD_REFRACTION_FACTOR * 1000
+	start, end := profile[0]+fromHeight, profile[len(profile)-1]+toHeight
+
+	v := &Visibility{Visible: true, Clearance: math.Inf(1), length: length}
+	last := len(profile) - 1
+	for i := 1; i < last; i++ {
+		fraction := float64(i) / float64(last)
+		d := length * fraction * 1000
+
+		// The ground bulges up into a straight line between the ends by the earth's curvature.
+		bulge := d * (length*1000 - d) / (2 * radius)
+		clearance := start + (end-start)*fraction - (profile[i] + bulge)
+		v.samples = append(v.samples, sightSample{distance: d / 1000, clearance: clearance})
+
+		if clearance < v.Clearance {
+			v.Clearance = clearance
+			v.CriticalDistance = d / 1000
+		}
+	}
+
+	if len(v.samples) > 0 {
+		v.Visible = v.Clearance >= 0
+		v.Critical = from.PointAtDistanceAndBearing(v.CriticalDistance, from.BearingTo(to))
+	}
+
+	return v, nil
+}
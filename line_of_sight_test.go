@@ -0,0 +1,128 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+// An ElevationProvider returning a fixed profile, recording the samples it is asked for.
+type fixedElevations struct {
+	elevation func(fraction float64) float64
+	samples   int
+}
+
+func (f *fixedElevations) ElevationProfile(from, to *Point, samples int) ([]float64, error) {
+	f.samples = samples
+	profile := make([]float64, samples)
+	for i := range profile {
+		profile[i] = f.elevation(float64(i) / float64(samples-1))
+	}
+	return profile, nil
+}
+
+// Ensures that ProfileLineOfSight hides points beyond the radio horizon of flat ground.
+func TestProfileLineOfSightCurvature(t *testing.T) {
+	sea := make([]float64, 101)
+
+	// Masts 100m tall see each other over about 82km of sea.
+	near, err := ProfileLineOfSight(NewPoint(0, 0), NewPoint(0, 0.7), sea, 100, 100)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !near.Visible || near.Clearance <= 0 || near.Clearance > 15 {
+		t.Errorf("Expected masts 78km apart to see each other just over the sea, got %+v", near)
+	}
+
+	if math.Abs(near.CriticalDistance-near.length/2) > 1 || math.Abs(near.Critical.Lng()-0.35) > 0.01 {
+		t.Errorf("Expected the sight line to pass lowest halfway, got %v at %f", near.Critical, near.CriticalDistance)
+	}
+
+	far, err := ProfileLineOfSight(NewPoint(0, 0), NewPoint(0, 0.8), sea, 100, 100)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if far.Visible || far.Clearance >= 0 {
+		t.Errorf("Expected masts 89km apart to be hidden by the earth's curvature, got %+v", far)
+	}
+}
+
+// Ensures that ProfileLineOfSight reports the hill between two points.
+func TestProfileLineOfSightHill(t *testing.T) {
+	profile := []float64{50, 60, 80, 250, 90, 70, 60}
+	v, err := ProfileLineOfSight(NewPoint(51.5, -0.1), NewPoint(51.5, -0.04), profile, 10, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if v.Visible || math.Abs(v.Clearance-(-185)) > 1 {
+		t.Errorf("Expected the hill to block the sight line by 185m, got %+v", v)
+	}
+
+	if math.Abs(v.Critical.Lng()-(-0.07)) > 0.001 {
+		t.Errorf("Expected the obstruction at the hill, got %v", v.Critical)
+	}
+
+	if clear, err := ProfileLineOfSight(NewPoint(51.5, -0.1), NewPoint(51.5, -0.04), profile, 300, 300); err != nil || !clear.Visible {
+		t.Errorf("Expected masts taller than the hill to see each other, got %+v, %v", clear, err)
+	}
+
+	if _, err := ProfileLineOfSight(NewPoint(51.5, -0.1), NewPoint(51.5, -0.04), []float64{1}, 10, 10); err != shortElevationProfileError {
+		t.Errorf("Expected a short profile error, got %v", err)
+	}
+
+	adjacent, err := ProfileLineOfSight(NewPoint(51.5, -0.1), NewPoint(51.5, -0.04), []float64{1, 2}, 10, 10)
+	if err != nil || !adjacent.Visible || !math.IsInf(adjacent.Clearance, 1) || adjacent.Critical != nil {
+		t.Errorf("Expected a profile without samples between its ends to be visible, got %+v, %v", adjacent, err)
+	}
+}
+
+// Ensures that FresnelRadius and FresnelClearance measure the first Fresnel zone.
+func TestFresnelClearance(t *testing.T) {
+	if r := FresnelRadius(1, 1, 2400); math.Abs(r-7.9) > 0.05 {
+		t.Errorf("Expected a 7.9m radius halfway along a 2km 2.4GHz link, got %f", r)
+	}
+
+	if FresnelRadius(0, 1, 2400) != 0 {
+		t.Error("Expected no Fresnel zone at the end of a link")
+	}
+
+	// A 2km link 4m above flat ground clears its line of sight, but not its Fresnel zone.
+	v, err := ProfileLineOfSight(NewPoint(0, 0), NewPoint(0, 0.018), make([]float64, 21), 4, 4)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !v.Visible {
+		t.Fatalf("Expected the link to be visible, got %+v", v)
+	}
+
+	if f := v.FresnelClearance(2400); f <= 0 || f >= 0.6 {
+		t.Errorf("Expected the link's Fresnel zone to be obstructed, got %f", f)
+	}
+}
+
+// Ensures that LineOfSight samples the ground from its ElevationProvider.
+func TestLineOfSight(t *testing.T) {
+	ridge := &fixedElevations{elevation: func(fraction float64) float64 {
+		return 500 * (1 - math.Abs(2*fraction-1))
+	}}
+
+	v, err := LineOfSight(NewPoint(46.5, 7.9), NewPoint(46.5, 7.95), ridge, 2, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if ridge.samples != 129 {
+		t.Errorf("Expected a sample every 30m, got %d samples", ridge.samples)
+	}
+
+	if v.Visible {
+		t.Errorf("Expected the ridge to block the sight line, got %+v", v)
+	}
+
+	if _, err := LineOfSight(NewPoint(0, 0), NewPoint(0, 90), ridge, 2, 2); err != nil || ridge.samples != MAX_ELEVATION_SAMPLES {
+		t.Errorf("Expected long paths to be sampled %d times, got %d, %v", MAX_ELEVATION_SAMPLES, ridge.samples, err)
+	}
+}
@@ -0,0 +1,256 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// This is the error that consumers receive when a Locator is asked to locate
+// a kind of input it has no provider for, such as an IP address without an IPLocator.
+var locatorUnconfiguredError = errors.New("no provider configured")
+
+// The kinds of input a Locator recognizes.
+type LocationKind int
+
+const (
+	LocationCoordinates LocationKind = iota
+	LocationIP
+	LocationPlusCode
+	LocationGeohash
+	LocationAddress
+)
+
+// Returns "coordinates", "IP address", "plus code", "geohash" or "address".
+func (k LocationKind) String() string {
+	switch k {
+	case LocationCoordinates:
+		return "coordinates"
+	case LocationIP:
+		return "IP address"
+	case LocationPlusCode:
+		return "plus code"
+	case LocationGeohash:
+		return "geohash"
+	case LocationAddress:
+		return "address"
+	}
+
+	return fmt.Sprintf("LocationKind(%d)", int(k))
+}
+
+// The place a Locator found for its input, whatever kind of input it was.
+type Location struct {
+	Input string
+	Kind  LocationKind
+	Point *Point
+
+	// The area the input names, for plus codes and geohashes, or nil.
+	Bounds *Bounds
+
+	// The address found for the input, e.g. a geocoder's formatted address
+	// or the city, region and country of an IP address, if there is one.
+	Address string
+
+	// The ISO 3166-1 alpha-2 code of the country the point lies in, if it is known.
+	CountryCode string
+}
+
+// A Locator turns whatever a user typed into a place: coordinates in any format ParsePoint
+// accepts, such as "37.42,-122.08" or "17T 630084 4833438", an IP address, a plus code
+// such as "849VCWC8+R9" or "CWC8+R9 Mountain View", a geohash such as "9q9hvu", or a street address.  Coordinates, full plus codes and geohashes
+// are decoded locally; IP addresses, addresses and the localities of short plus codes
+// are looked up with the Locator's providers.
+type Locator struct {
+	// Looks up street addresses, and the localities short plus codes are relative to.
+	// If it is a ResultGeocoder, Locations carry its formatted address and country.
+	Geocoder Geocoder
+
+	// Looks up IP addresses.
+	IPLocator IPLocator
+}
+
+// Creates and returns a pointer to a new Locator using the passed in providers, either of which may be nil.
+func NewLocator(geocoder Geocoder, ipLocator IPLocator) *Locator {
+	return &Locator{Geocoder: geocoder, IPLocator: ipLocator}
+}
+
+// Returns the place the passed in input names, detecting what kind of input it is.
+// Returns an *AmbiguousPointError for coordinates that could name more than one point.
+// Strings that could be a geohash or an address, such as "cheese", are taken to be
+// geohashes only if they mix letters and digits, as almost all geohashes do.
+func (l *Locator) Locate(input string) (*Location, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, errors.New("nothing to locate")
+	}
+
+	reading, err := readPoint(input)
+	if errors.Is(err, ErrAmbiguousPoint) {
+		return nil, err
+	}
+
+	if err == nil {
+		location := &Location{Input: input, Kind: LocationCoordinates, Point: reading.point, Bounds: reading.bounds}
+		switch reading.format {
+		case "geohash":
+			location.Kind = LocationGeohash
+		case "plus code":
+			location.Kind = LocationPlusCode
+		}
+
+		return location, nil
+	}
+
+	if ip := net.ParseIP(input); ip != nil {
+		return l.locateIP(input, ip)
+	}
+
+	code, locality, _ := strings.Cut(input, " ")
+	code = strings.TrimSuffix(code, ",")
+	if valid, _ := checkPlusCode(code); valid {
+		return l.locatePlusCode(input, code, strings.TrimSpace(locality))
+	}
+
+	return l.locateAddress(input)
+}
+
+// Returns the location of the passed in IP address.
+func (l *Locator) locateIP(input string, ip net.IP) (*Location, error) {
+	if l.IPLocator == nil {
+		return nil, fmt.Errorf("%w to locate IP address %q", locatorUnconfiguredError, input)
+	}
+
+	res, err := l.IPLocator.LocateIP(ip)
+	if err != nil {
+		return nil, err
+	}
+
+	var parts []string
+	for _, part := range []string{res.City, res.Region, res.CountryCode} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return &Location{
+		Input:       input,
+		Kind:        LocationIP,
+		Point:       res.Point,
+		Address:     strings.Join(parts, ", "),
+		CountryCode: res.CountryCode,
+	}, nil
+}
+
+// Returns the location of the passed in plus code.  Short codes are recovered
+// near the passed in locality, which is geocoded to find it.
+func (l *Locator) locatePlusCode(input, code, locality string) (*Location, error) {
+	if !IsPlusCode(code) {
+		if locality == "" {
+			return nil, shortPlusCodeError
+		}
+
+		if l.Geocoder == nil {
+			return nil, fmt.Errorf("%w to locate the locality of plus code %q", locatorUnconfiguredError, input)
+		}
+
+		reference, err := l.Geocoder.Geocode(locality)
+		if err != nil {
+			return nil, err
+		}
+
+		if code, err = RecoverPlusCode(code, reference); err != nil {
+			return nil, err
+		}
+	}
+
+	bounds, err := PlusCodeBounds(code)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Location{Input: input, Kind: LocationPlusCode, Point: boundsCenter(bounds), Bounds: bounds}, nil
+}
+
+// Returns the location of the passed in street address.
+func (l *Locator) locateAddress(input string) (*Location, error) {
+	if l.Geocoder == nil {
+		return nil, fmt.Errorf("%w to locate address %q", locatorUnconfiguredError, input)
+	}
+
+	if g, ok := l.Geocoder.(ResultGeocoder); ok {
+		results, err := g.GeocodeResults(input)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(results) > 0 {
+			return &Location{
+				Input:       input,
+				Kind:        LocationAddress,
+				Point:       results[0].Point,
+				Address:     results[0].FormattedAddress,
+				CountryCode: results[0].CountryCode,
+			}, nil
+		}
+	}
+
+	p, err := l.Geocoder.Geocode(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Location{Input: input, Kind: LocationAddress, Point: p}, nil
+}
+
+// Returns the point at the passed in decimal latitude and longitude,
+// or an error if they aren't numbers or lie outside their valid ranges.
+func parseLatLng(lat, lng string) (*Point, error) {
+	latitude, err := strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	if err != nil {
+		return nil, err
+	}
+
+	longitude, err := strconv.ParseFloat(strings.TrimSpace(lng), 64)
+	if err != nil {
+		return nil, err
+	}
+
+	if math.Abs(latitude) > 90 || math.Abs(longitude) > 180 || math.IsNaN(latitude) || math.IsNaN(longitude) {
+		return nil, fmt.Errorf("coordinates out of range: %s,%s", lat, lng)
+	}
+
+	return NewPoint(latitude, longitude), nil
+}
+
+// Returns whether or not the passed in string is probably a geohash rather than a word or a number:
+// written in the geohash alphabet, no longer than MAX_GEOHASH_PRECISION, and mixing letters and digits.
+func looksLikeGeohash(s string) bool {
+	if len(s) < 2 || len(s) > MAX_GEOHASH_PRECISION {
+		return false
+	}
+
+	s = strings.ToLower(s)
+	letters, digits := false, false
+	for i := 0; i < len(s); i++ {
+		if geohashValues[s[i]] < 0 {
+			return false
+		}
+
+		if s[i] >= '0' && s[i] <= '9' {
+			digits = true
+		} else {
+			letters = true
+		}
+	}
+
+	return letters && digits
+}
+
+// Returns the point midway between the corners of the passed in bounds.
+func boundsCenter(b *Bounds) *Point {
+	return NewPoint((b.sw.lat+b.ne.lat)/2, (b.sw.lng+b.ne.lng)/2)
+}
@@ -0,0 +1,112 @@
+package geo
+
+import (
+	"errors"
+	"net"
+	"testing"
+)
+
+// An IPLocator that places every address at a fixed location.
+type stubIPLocator struct {
+	location *IPLocation
+}
+
+func (s *stubIPLocator) LocateIP(ip net.IP) (*IPLocation, error) {
+	location := *s.location
+	location.IP = ip
+	return &location, nil
+}
+
+// Ensures that each kind of input is detected and located.
+func TestLocate(t *testing.T) {
+	l := NewLocator(
+		&stubGeocoder{points: map[string]*Point{
+			"1600 Amphitheatre Parkway": NewPoint(37.4224, -122.0842),
+			"Mountain View":             NewPoint(37.3861, -122.0839),
+		}},
+		&stubIPLocator{&IPLocation{Point: NewPoint(37.4056, -122.0775), City: "Mountain View", Region: "California", CountryCode: "US"}},
+	)
+
+	tests := []struct {
+		input    string
+		kind     LocationKind
+		lat, lng float64
+	}{
+		{"37.42, -122.08", LocationCoordinates, 37.42, -122.08},
+		{"-33.8568 151.2153", LocationCoordinates, -33.8568, 151.2153},
+		{"17T 630084 4833438", LocationCoordinates, 43.6426, -79.3871},
+		{"8.8.8.8", LocationIP, 37.4056, -122.0775},
+		{"2001:4860:4860::8888", LocationIP, 37.4056, -122.0775},
+		{"849VCWC8+R9", LocationPlusCode, 37.4220625, -122.0840625},
+		{"CWC8+R9 Mountain View", LocationPlusCode, 37.4220625, -122.0840625},
+		{"CWC8+R9, Mountain View", LocationPlusCode, 37.4220625, -122.0840625},
+		{"9q9hvu", LocationGeohash, 37.421, -122.085},
+		{"1600 Amphitheatre Parkway", LocationAddress, 37.4224, -122.0842},
+	}
+
+	for _, test := range tests {
+		location, err := l.Locate(test.input)
+		if err != nil {
+			t.Errorf("%s: %v", test.input, err)
+			continue
+		}
+
+		if location.Kind != test.kind {
+			t.Errorf("Expected %s to be located as %s, got %s", test.input, test.kind, location.Kind)
+		}
+
+		if location.Point.GreatCircleDistance(NewPoint(test.lat, test.lng)) > 0.5 {
+			t.Errorf("Expected %s near %f, %f, got %v", test.input, test.lat, test.lng, location.Point)
+		}
+	}
+
+	location, _ := l.Locate("8.8.8.8")
+	if location.Address != "Mountain View, California, US" || location.CountryCode != "US" {
+		t.Errorf("Unexpected IP location: %+v", location)
+	}
+
+	location, _ = l.Locate("9q9hvu")
+	if location.Bounds == nil || !location.Bounds.Contains(location.Point) {
+		t.Errorf("Expected a geohash location to carry its cell, got %+v", location)
+	}
+}
+
+// Ensures that words and numbers made of geohash characters are taken to be addresses.
+func TestLocateAmbiguousGeohash(t *testing.T) {
+	g := &stubGeocoder{points: map[string]*Point{"cheese": NewPoint(1, 1), "94043": NewPoint(2, 2)}}
+	l := NewLocator(g, nil)
+
+	for _, input := range []string{"cheese", "94043"} {
+		location, err := l.Locate(input)
+		if err != nil || location.Kind != LocationAddress {
+			t.Errorf("Expected %s to be located as an address, got %+v (%v)", input, location, err)
+		}
+	}
+}
+
+// Ensures that inputs without a provider to locate them are reported as such.
+func TestLocateUnconfigured(t *testing.T) {
+	l := NewLocator(nil, nil)
+
+	for _, input := range []string{"8.8.8.8", "1600 Amphitheatre Parkway", "CWC8+R9 Mountain View"} {
+		if _, err := l.Locate(input); !errors.Is(err, locatorUnconfiguredError) {
+			t.Errorf("Expected locatorUnconfiguredError for %s, got %v", input, err)
+		}
+	}
+
+	if _, err := l.Locate("CWC8+R9"); err != shortPlusCodeError {
+		t.Errorf("Expected shortPlusCodeError, got %v", err)
+	}
+
+	if location, err := l.Locate("37.42,-122.08"); err != nil || location.Kind != LocationCoordinates {
+		t.Errorf("Expected coordinates to be located without providers, got %+v (%v)", location, err)
+	}
+
+	if _, err := l.Locate("10 20"); !errors.Is(err, ErrAmbiguousPoint) {
+		t.Errorf("Expected ErrAmbiguousPoint, got %v", err)
+	}
+
+	if _, err := l.Locate("  "); err == nil {
+		t.Error("Expected an error for empty input")
+	}
+}
@@ -0,0 +1,64 @@
+package geo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Returns a short hash of the passed in request's method and URL, without credentials,
+// so that log records of the same request, such as retries or repeated lookups, can be grouped.
+func requestHash(req *http.Request) string {
+	sum := sha256.Sum256([]byte(RecordingKey(req)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// An http.RoundTripper logging each request of a provider.
+type loggingTransport struct {
+	logger   *slog.Logger
+	provider string
+	base     http.RoundTripper
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+
+	attrs := []slog.Attr{
+		slog.String("provider", t.provider),
+		slog.String("method", req.Method),
+		slog.String("url", scrubURL(req.URL).String()),
+		slog.String("query_hash", requestHash(req)),
+		slog.Duration("latency", time.Since(start)),
+	}
+
+	level := slog.LevelInfo
+	switch {
+	case err != nil:
+		level = slog.LevelWarn
+		attrs = append(attrs, slog.String("error", scrubError(err).Error()))
+	case resp.StatusCode >= 400:
+		level = slog.LevelWarn
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	default:
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	}
+
+	// Logging with the request's context lets handlers add request-scoped attributes of their own, such as a trace id.
+	t.logger.LogAttrs(req.Context(), level, "geo provider request", attrs...)
+	return resp, err
+}
+
+// Returns an http.RoundTripper that logs every request of the named provider to the passed in logger,
+// issuing requests with the passed in transport, or the shared transport if it is nil.
+// Each request is logged, once it completes, with its provider, method, URL with credentials redacted,
+// query_hash, latency, and status or error: at Info, or at Warn if it failed or its status is 4xx or 5xx.
+func LoggingTransport(logger *slog.Logger, provider string, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = defaultTransport()
+	}
+
+	return &loggingTransport{logger: logger, provider: provider, base: base}
+}
@@ -0,0 +1,67 @@
+package geo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// Ensures that provider requests are logged with their attributes, and without their credentials.
+func TestWithLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("address") == "denied" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{"results":[],"status":"ZERO_RESULTS"}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	g := NewGoogleGeocoder(WithBaseURL(server.URL), WithAPIKey("secret"), WithLogger(logger))
+	g.Geocode("Atlantis")
+	g.Geocode("denied")
+
+	if strings.Contains(buf.String(), "secret") {
+		t.Errorf("Expected the API key to be redacted, got %s", buf.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 records, got %d: %s", len(lines), buf.String())
+	}
+
+	var first, second map[string]interface{}
+	json.Unmarshal([]byte(lines[0]), &first)
+	json.Unmarshal([]byte(lines[1]), &second)
+
+	if first["level"] != "INFO" || first["provider"] != "google" || first["status"] != 200.0 || first["query_hash"] == "" {
+		t.Errorf("Unexpected record: %v", first)
+	}
+	if _, ok := first["latency"]; !ok {
+		t.Errorf("Expected the latency to be logged, got %v", first)
+	}
+	if u, _ := first["url"].(string); !strings.Contains(u, "key=REDACTED") {
+		t.Errorf("Expected the URL with its key redacted, got %q", u)
+	}
+
+	if second["level"] != "WARN" || second["status"] != 403.0 || second["query_hash"] == first["query_hash"] {
+		t.Errorf("Unexpected record: %v", second)
+	}
+}
+
+// Ensures that URLs quoted in network errors lose their credentials.
+func TestScrubError(t *testing.T) {
+	err := &url.Error{Op: "Get", URL: "https://maps.googleapis.com/maps/api/geocode/json?address=SFO&key=secret", Err: errors.New("connection refused")}
+	scrubbed := scrubError(err)
+	if strings.Contains(scrubbed.Error(), "secret") || !strings.Contains(scrubbed.Error(), "connection refused") {
+		t.Errorf("Expected the key to be redacted, got %v", scrubbed)
+	}
+}
@@ -0,0 +1,342 @@
+package geo
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The radius, in kilometers, of the reference sphere of the World Magnetic Model.
+const wmmReferenceRadius = 6371.2
+
+// The number of years from its epoch for which a World Magnetic Model is valid.
+const WMM_VALIDITY_YEARS = 5
+
+// This is the error that consumers receive when parsing
+// World Magnetic Model coefficients that are malformed.
+var invalidMagneticModelError = errors.New("invalid magnetic model coefficients")
+
+// A MagneticModel is a spherical harmonic model of the earth's main magnetic field
+// and its secular variation, such as the World Magnetic Model.
+type MagneticModel struct {
+	Name  string
+	Epoch float64
+
+	degree int
+
+	// Gauss coefficients and their yearly rates of change, indexed [n][m], in nanotesla.
+	g, h, gDot, hDot [][]float64
+}
+
+// Parses a MagneticModel from coefficients in the format of NOAA's WMM.COF files:
+// a header line holding the epoch and model name, then one line per coefficient
+// holding n, m, g, h, and the rates of change of g and h.  Parsing stops at a line of 9s.
+func ParseMagneticModel(r io.Reader) (*MagneticModel, error) {
+	scanner := bufio.NewScanner(r)
+	if !scanner.Scan() {
+		return nil, invalidMagneticModelError
+	}
+
+	header := strings.Fields(scanner.Text())
+	if len(header) < 2 {
+		return nil, invalidMagneticModelError
+	}
+
+	epoch, err := strconv.ParseFloat(header[0], 64)
+	if err != nil {
+		return nil, invalidMagneticModelError
+	}
+
+	type coefficient struct {
+		n, m             int
+		g, h, gDot, hDot float64
+	}
+
+	var coefficients []coefficient
+	degree := 0
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		if strings.HasPrefix(fields[0], "9999") {
+			break
+		}
+
+		if len(fields) < 6 {
+			return nil, fmt.Errorf("%w: %q", invalidMagneticModelError, scanner.Text())
+		}
+
+		var c coefficient
+		var errs [6]error
+		c.n, errs[0] = strconv.Atoi(fields[0])
+		c.m, errs[1] = strconv.Atoi(fields[1])
+		c.g, errs[2] = strconv.ParseFloat(fields[2], 64)
+		c.h, errs[3] = strconv.ParseFloat(fields[3], 64)
+		c.gDot, errs[4] = strconv.ParseFloat(fields[4], 64)
+		c.hDot, errs[5] = strconv.ParseFloat(fields[5], 64)
+		for _, err := range errs {
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q", invalidMagneticModelError, scanner.Text())
+			}
+		}
+
+		if c.n < 1 || c.m < 0 || c.m > c.n {
+			return nil, fmt.Errorf("%w: %q", invalidMagneticModelError, scanner.Text())
+		}
+
+		coefficients = append(coefficients, c)
+		degree = max(degree, c.n)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if degree == 0 {
+		return nil, invalidMagneticModelError
+	}
+
+	model := &MagneticModel{Name: header[1], Epoch: epoch, degree: degree}
+	for _, table := range []*[][]float64{&model.g, &model.h, &model.gDot, &model.hDot} {
+		*table = make([][]float64, degree+1)
+		for n := range *table {
+			(*table)[n] = make([]float64, n+1)
+		}
+	}
+
+	for _, c := range coefficients {
+		model.g[c.n][c.m], model.h[c.n][c.m] = c.g, c.h
+		model.gDot[c.n][c.m], model.hDot[c.n][c.m] = c.gDot, c.hDot
+	}
+
+	return model, nil
+}
+
+// Returns the passed in time as a decimal year, e.g. 2021.5 for the start of July 2021.
+func decimalYear(t time.Time) float64 {
+	t = t.UTC()
+	start := time.Date(t.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(1, 0, 0)
+
+	return float64(t.Year()) + float64(t.Sub(start))/float64(end.Sub(start))
+}
+
+// Returns whether or not the passed in time falls within the years the model is valid for.
+func (m *MagneticModel) ValidAt(t time.Time) bool {
+	year := decimalYear(t)
+	return year >= m.Epoch && year < m.Epoch+WMM_VALIDITY_YEARS
+}
+
+// Returns the north, east and down components, in nanotesla, of the magnetic field
+// at the passed in point, at sea level, at the passed in time.
+func (m *MagneticModel) Field(p *Point, t time.Time) (north float64, east float64, down float64) {
+	rad := math.Pi / 180
+	dt := decimalYear(t) - m.Epoch
+
+	// Geodetic to geocentric coordinates on the WGS84 ellipsoid.
+	lat := math.Max(-89.99999, math.Min(89.99999, p.lat)) * rad
+	lng := NormalizeLng(p.lng) * rad
+	e2 := wgs84.e2
+	rc := WGS84_SEMI_MAJOR_AXIS / math.Sqrt(1-e2*math.Sin(lat)*math.Sin(lat))
+	px, pz := rc*math.Cos(lat), rc*(1-e2)*math.Sin(lat)
+	r := math.Hypot(px, pz)
+	latC := math.Asin(pz / r)
+
+	// Schmidt semi-normalized associated Legendre functions of the geocentric
+	// colatitude, and their derivatives with respect to it.
+	cosT, sinT := math.Sin(latC), math.Cos(latC)
+	P := make([][]float64, m.degree+1)
+	dP := make([][]float64, m.degree+1)
+	for n := 0; n <= m.degree; n++ {
+		P[n] = make([]float64, n+1)
+		dP[n] = make([]float64, n+1)
+	}
+
+	P[0][0] = 1
+	for n := 1; n <= m.degree; n++ {
+		for k := 0; k <= n; k++ {
+			switch {
+			case n == k && n == 1:
+				P[1][1], dP[1][1] = sinT, cosT
+			case n == k:
+				f := math.Sqrt(float64(2*n-1) / float64(2*n))
+				P[n][n] = f * sinT * P[n-1][n-1]
+				dP[n][n] = f * (sinT*dP[n-1][n-1] + cosT*P[n-1][n-1])
+			default:
+				a := float64(2*n - 1)
+				b := 0.0
+				var prev, dPrev float64
+				if n-2 >= k {
+					b = math.Sqrt(float64((n-1)*(n-1) - k*k))
+					prev, dPrev = P[n-2][k], dP[n-2][k]
+				}
+				d := math.Sqrt(float64(n*n - k*k))
+				P[n][k] = (a*cosT*P[n-1][k] - b*prev) / d
+				dP[n][k] = (a*(cosT*dP[n-1][k]-sinT*P[n-1][k]) - b*dPrev) / d
+			}
+		}
+	}
+
+	var x, y, z float64
+	ratio := wmmReferenceRadius / r
+	for n := 1; n <= m.degree; n++ {
+		scale := math.Pow(ratio, float64(n+2))
+		for k := 0; k <= n; k++ {
+			g := m.g[n][k] + dt*m.gDot[n][k]
+			h := m.h[n][k] + dt*m.hDot[n][k]
+			cosM, sinM := math.Cos(float64(k)*lng), math.Sin(float64(k)*lng)
+
+			x += scale * (g*cosM + h*sinM) * dP[n][k]
+			y += scale * float64(k) * (g*sinM - h*cosM) * P[n][k]
+			z -= scale * float64(n+1) * (g*cosM + h*sinM) * P[n][k]
+		}
+	}
+	y /= math.Cos(latC)
+
+	// Rotate from geocentric back to geodetic axes.
+	psi := latC - lat
+	return x*math.Cos(psi) - z*math.Sin(psi), y, x*math.Sin(psi) + z*math.Cos(psi)
+}
+
+// Returns the magnetic declination, in degrees east of true north, at the passed in point
+// at sea level at the passed in time.
+func (m *MagneticModel) Declination(p *Point, t time.Time) float64 {
+	north, east, _ := m.Field(p, t)
+	return math.Atan2(east, north) * 180 / math.Pi
+}
+
+var (
+	wmmOnce  sync.Once
+	wmmModel *MagneticModel
+)
+
+// Returns the built in World Magnetic Model, WMM2025, valid from 2025 to 2030.
+func WorldMagneticModel() *MagneticModel {
+	wmmOnce.Do(func() {
+		model, err := ParseMagneticModel(strings.NewReader(wmm2025Coefficients))
+		if err != nil {
+			panic(err)
+		}
+		wmmModel = model
+	})
+
+	return wmmModel
+}
+
+// Returns the magnetic declination, in degrees east of true north, at the passed in point
+// on the passed in date, according to the built in World Magnetic Model.  Add it to a
+// compass bearing to get a true bearing.  Accuracy falls off outside the model's
+// validity period; see MagneticModel.ValidAt, and ParseMagneticModel for loading newer coefficients.
+func MagneticDeclination(p *Point, date time.Time) float64 {
+	return WorldMagneticModel().Declination(p, date)
+}
+
+// Returns the true bearing, in degrees from 0 to 360, of the passed in compass bearing
+// taken at the passed in point on the passed in date.
+func MagneticToTrueBearing(bearing float64, p *Point, date time.Time) float64 {
+	return math.Mod(math.Mod(bearing+MagneticDeclination(p, date), 360)+360, 360)
+}
+
+// The coefficients of WMM2025, from NOAA's WMM.COF.
+const wmm2025Coefficients = `    2025.0            WMM-2025     11/13/2024
+  1  0  -29351.8       0.0       12.0        0.0
+  1  1   -1410.8    4545.4        9.7      -21.5
+  2  0   -2556.6       0.0      -11.6        0.0
+  2  1    2951.1   -3133.6       -5.2      -27.7
+  2  2    1649.3    -815.1       -8.0      -12.1
+  3  0    1361.0       0.0       -1.3        0.0
+  3  1   -2404.1     -56.6       -4.2        4.0
+  3  2    1243.8     237.5        0.4       -0.3
+  3  3     453.6    -549.5      -15.6       -4.1
+  4  0     895.0       0.0       -1.6        0.0
+  4  1     799.5     278.6       -2.4       -1.1
+  4  2      55.7    -133.9       -6.0        4.1
+  4  3    -281.1     212.0        5.6        1.6
+  4  4      12.1    -375.6       -7.0       -4.4
+  5  0    -233.2       0.0        0.6        0.0
+  5  1     368.9      45.4        1.4       -0.5
+  5  2     187.2     220.2        0.0        2.2
+  5  3    -138.7    -122.9        0.6        0.4
+  5  4    -142.0      43.0        2.2        1.7
+  5  5      20.9     106.1        0.9        1.9
+  6  0      64.4       0.0       -0.2        0.0
+  6  1      63.8     -18.4       -0.4        0.3
+  6  2      76.9      16.8        0.9       -1.6
+  6  3    -115.7      48.8        1.2       -0.4
+  6  4     -40.9     -59.8       -0.9        0.9
+  6  5      14.9      10.9        0.3        0.7
+  6  6     -60.7      72.7        0.9        0.9
+  7  0      79.5       0.0       -0.0        0.0
+  7  1     -77.0     -48.9       -0.1        0.6
+  7  2      -8.8     -14.4       -0.1        0.5
+  7  3      59.3      -1.0        0.5       -0.8
+  7  4      15.8      23.4       -0.1        0.0
+  7  5       2.5      -7.4       -0.8       -1.0
+  7  6     -11.1     -25.1       -0.8        0.6
+  7  7      14.2      -2.3        0.8       -0.2
+  8  0      23.2       0.0       -0.1        0.0
+  8  1      10.8       7.1        0.2       -0.2
+  8  2     -17.5     -12.6        0.0        0.5
+  8  3       2.0      11.4        0.5       -0.4
+  8  4     -21.7      -9.7       -0.1        0.4
+  8  5      16.9      12.7        0.3       -0.5
+  8  6      15.0       0.7        0.2       -0.6
+  8  7     -16.8      -5.2       -0.0        0.3
+  8  8       0.9       3.9        0.2        0.2
+  9  0       4.6       0.0       -0.0        0.0
+  9  1       7.8     -24.8       -0.1       -0.3
+  9  2       3.0      12.2        0.1        0.3
+  9  3      -0.2       8.3        0.3       -0.3
+  9  4      -2.5      -3.3       -0.3        0.3
+  9  5     -13.1      -5.2        0.0        0.2
+  9  6       2.4       7.2        0.3       -0.1
+  9  7       8.6      -0.6       -0.1       -0.2
+  9  8      -8.7       0.8        0.1        0.4
+  9  9     -12.9      10.0       -0.1        0.1
+ 10  0      -1.3       0.0        0.1        0.0
+ 10  1      -6.4       3.3        0.0        0.0
+ 10  2       0.2       0.0        0.1       -0.0
+ 10  3       2.0       2.4        0.1       -0.2
+ 10  4      -1.0       5.3       -0.0        0.1
+ 10  5      -0.6      -9.1       -0.3       -0.1
+ 10  6      -0.9       0.4        0.0        0.1
+ 10  7       1.5      -4.2       -0.1        0.0
+ 10  8       0.9      -3.8       -0.1       -0.1
+ 10  9      -2.7       0.9       -0.0        0.2
+ 10 10      -3.9      -9.1       -0.0       -0.0
+ 11  0       2.9       0.0        0.0        0.0
+ 11  1      -1.5       0.0       -0.0       -0.0
+ 11  2      -2.5       2.9        0.0        0.1
+ 11  3       2.4      -0.6        0.0       -0.0
+ 11  4      -0.6       0.2        0.0        0.1
+ 11  5      -0.1       0.5       -0.1       -0.0
+ 11  6      -0.6      -0.3        0.0       -0.0
+ 11  7      -0.1      -1.2       -0.0        0.1
+ 11  8       1.1      -1.7       -0.1       -0.0
+ 11  9      -1.0      -2.9       -0.1        0.0
+ 11 10      -0.2      -1.8       -0.1        0.0
+ 11 11       2.6      -2.3       -0.1        0.0
+ 12  0      -2.0       0.0        0.0        0.0
+ 12  1      -0.2      -1.3        0.0       -0.0
+ 12  2       0.3       0.7       -0.0        0.0
+ 12  3       1.2       1.0       -0.0       -0.1
+ 12  4      -1.3      -1.4       -0.0        0.1
+ 12  5       0.6      -0.0       -0.0       -0.0
+ 12  6       0.6       0.6        0.1       -0.0
+ 12  7       0.5      -0.1       -0.0       -0.0
+ 12  8      -0.1       0.8        0.0        0.0
+ 12  9      -0.4       0.1        0.0       -0.0
+ 12 10      -0.2      -1.0       -0.1       -0.0
+ 12 11      -1.3       0.1       -0.0        0.0
+ 12 12      -0.7       0.2       -0.1       -0.1
+999999999999999999999999999999999999999999999999
+999999999999999999999999999999999999999999999999
+`
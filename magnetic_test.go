@@ -0,0 +1,100 @@
+package geo
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Ensures that MagneticDeclination agrees with NOAA's published declinations to within a degree.
+func TestMagneticDeclination(t *testing.T) {
+	date := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		p        *Point
+		expected float64
+	}{
+		{"Boulder", NewPoint(40.015, -105.27), 7.7},
+		{"London", NewPoint(51.5074, -0.1278), 1.1},
+		{"Sydney", NewPoint(-33.8688, 151.2093), 12.8},
+		{"Tokyo", NewPoint(35.6762, 139.6503), -7.9},
+	}
+
+	for _, test := range tests {
+		d := MagneticDeclination(test.p, date)
+		if math.Abs(d-test.expected) > 1 {
+			t.Errorf("%s: Expected a declination of about %v, got %v", test.name, test.expected, d)
+		}
+	}
+}
+
+// Ensures that the declination changes over the years as the secular variation says it should.
+func TestMagneticDeclinationSecularVariation(t *testing.T) {
+	p := NewPoint(40.015, -105.27)
+	then := MagneticDeclination(p, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	now := MagneticDeclination(p, time.Date(2029, 1, 1, 0, 0, 0, 0, time.UTC))
+	if now >= then || then-now > 1 {
+		t.Errorf("Expected declination at Boulder to drift slightly west, got %v then %v", then, now)
+	}
+}
+
+// Ensures that MagneticToTrueBearing adds the declination and wraps around north.
+func TestMagneticToTrueBearing(t *testing.T) {
+	date := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tokyo := NewPoint(35.6762, 139.6503)
+	d := MagneticDeclination(tokyo, date)
+
+	bearing := MagneticToTrueBearing(2, tokyo, date)
+	if math.Abs(bearing-(362+d)) > 1e-9 {
+		t.Errorf("Expected %v, got %v", 362+d, bearing)
+	}
+
+	bearing = MagneticToTrueBearing(90, tokyo, date)
+	if math.Abs(bearing-(90+d)) > 1e-9 {
+		t.Errorf("Expected %v, got %v", 90+d, bearing)
+	}
+}
+
+// Ensures that the built in model knows the years it is valid for.
+func TestMagneticModelValidAt(t *testing.T) {
+	m := WorldMagneticModel()
+	if m.Epoch != 2025 || m.Name != "WMM-2025" {
+		t.Errorf("Expected WMM-2025 with epoch 2025, got %s with epoch %v", m.Name, m.Epoch)
+	}
+
+	if !m.ValidAt(time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Error("Expected the model to be valid in 2026")
+	}
+
+	if m.ValidAt(time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)) || m.ValidAt(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Error("Expected the model to be valid from 2025 to 2030 only")
+	}
+}
+
+// Ensures that ParseMagneticModel loads a truncated model and rejects malformed ones.
+func TestParseMagneticModel(t *testing.T) {
+	m, err := ParseMagneticModel(strings.NewReader("2020.0 DIPOLE\n1 0 -29404.5 0.0 0 0\n1 1 -1450.7 4652.9 0 0\n9999\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// A tilted dipole puts the magnetic pole in the Canadian arctic, so declination at Boulder is east.
+	if d := m.Declination(NewPoint(40.015, -105.27), time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)); d <= 0 || d > 20 {
+		t.Errorf("Expected a small easterly declination from a dipole, got %v", d)
+	}
+
+	for _, input := range []string{
+		"",
+		"2020.0\n",
+		"2020.0 WMM\n",
+		"2020.0 WMM\n1 0 abc 0 0 0\n",
+		"2020.0 WMM\n1 2 1 0 0 0\n",
+		"2020.0 WMM\n1 0 1\n",
+	} {
+		if _, err := ParseMagneticModel(strings.NewReader(input)); !errors.Is(err, invalidMagneticModelError) {
+			t.Errorf("Expected invalidMagneticModelError for %q, got %v", input, err)
+		}
+	}
+}
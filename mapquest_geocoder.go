@@ -1,64 +1,105 @@
 package geo
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 )
 
 // This struct contains all the funcitonality
 // of interacting with the MapQuest Geocoding Service
-type MapQuestGeocoder struct{}
+type MapQuestGeocoder struct {
+	// If set, every request is counted against this Quota
+	// and refused once the "mapquest" daily budget is spent.
+	Quota *Quota
+
+	// The client used to issue requests.  Defaults to a client sharing its connections
+	// with every provider, tuned with SetTransportConfig.
+	// Supply a client using a Recorder as its Transport to record or replay requests.
+	HTTPClient *http.Client
+
+	// The base URL of the MapQuest Nominatim API.  Defaults to DEFAULT_MAPQUEST_GEOCODE_URL.
+	BaseURL string
+
+	apiKey      string
+	language    string
+	romanize    bool
+	extraParams url.Values
+}
+
+// Creates and returns a pointer to a new MapQuestGeocoder configured by the passed in options.
+// MapQuest makes use of WithAPIKey, WithLanguage, WithTransliteration, WithParam, WithHTTPClient, WithBaseURL and WithQuota.
+func NewMapQuestGeocoder(opts ...Option) *MapQuestGeocoder {
+	c := newGeocoderConfig(opts)
+	return &MapQuestGeocoder{
+		Quota:       c.quota,
+		HTTPClient:  c.client("mapquest"),
+		BaseURL:     c.baseURL,
+		apiKey:      c.apiKey,
+		language:    c.language,
+		romanize:    c.romanize,
+		extraParams: c.params,
+	}
+}
 
 // This is the error that consumers receive when there
-// are no results from the geocoding request.
-var mapquestZeroResultsError = errors.New("ZERO_RESULTS")
+// are no results from the geocoding request.  Matches ErrNotFound.
+var mapquestZeroResultsError error = &ProviderError{Provider: "mapquest", Kind: ErrNotFound, Status: "ZERO_RESULTS"}
 
-// This contains the base URL for the Mapquest Geocoder APII.
-var mapquestGeocodeURL = "http://open.mapquestapi.com/nominatim/v1"
+// This contains the default base URL for the Mapquest Geocoder API.
+const DEFAULT_MAPQUEST_GEOCODE_URL = "http://open.mapquestapi.com/nominatim/v1"
 
-// Note:  In the next major revision (1.0.0), it is planned
-//        That Geocoders should adhere to the `geo.Geocoder`
-//        interface and provide versioning of APIs accordingly.
-// Sets the base URL for the Google Geocoding API.
-func SetMapquestGeocodeURL(newGeocodeURL string) {
-	mapquestGeocodeURL = newGeocodeURL
+// Returns the base URL that this MapQuestGeocoder issues requests to.
+func (g *MapQuestGeocoder) baseURL() string {
+	if g.BaseURL == "" {
+		return DEFAULT_MAPQUEST_GEOCODE_URL
+	}
+
+	return g.BaseURL
 }
 
 // Issues a request to the open mapquest api geocoding services using the passed in url query.
 // Returns an array of bytes as the result of the api call or an error if one occurs during the process.
 func (g *MapQuestGeocoder) Request(url string) ([]byte, error) {
-	client := &http.Client{}
-	fullUrl := fmt.Sprintf("%s/%s", mapquestGeocodeURL, url)
-
-	// TODO Refactor into an api driver of some sort
-	//      It seems odd that golang-geo should be responsible of versioning of APIs, etc.
-	req, _ := http.NewRequest("GET", fullUrl, nil)
-	resp, requestErr := client.Do(req)
+	return g.requestContext(context.Background(), url)
+}
 
-	if requestErr != nil {
-		panic(requestErr)
+// Issues a request as Request does, canceled if the passed in context is done first.
+func (g *MapQuestGeocoder) requestContext(ctx context.Context, url string) ([]byte, error) {
+	if g.Quota != nil {
+		if err := g.Quota.Spend("mapquest"); err != nil {
+			return nil, err
+		}
 	}
 
-	// TODO figure out a better typing for response
-	data, dataReadErr := ioutil.ReadAll(resp.Body)
+	// TODO Refactor into an api driver of some sort
+	//      It seems odd that golang-geo should be responsible of versioning of APIs, etc.
+	fullUrl := fmt.Sprintf("%s/%s", g.baseURL(), url)
+	return providerGetContext(ctx, g.HTTPClient, "mapquest", fullUrl)
+}
 
-	if dataReadErr != nil {
-		return nil, dataReadErr
+// Returns nil if MapQuest answers a canary reverse geocode, or an error saying why it didn't,
+// such as ErrAuth for an invalid API key.  Implements the HealthChecker interface.
+func (g *MapQuestGeocoder) HealthCheck(ctx context.Context) error {
+	values := url.Values{
+		"lat": {fmt.Sprintf("%f", healthCheckPoint.lat)},
+		"lon": {fmt.Sprintf("%f", healthCheckPoint.lng)},
 	}
 
-	return data, nil
+	_, err := g.requestContext(ctx, "reverse.php?"+g.params(values))
+	return err
 }
 
 // Returns the first point returned by MapQuest's geocoding service or an error
 // if one occurs during the geocoding request.
-func (g *MapQuestGeocoder) Geocode(query string) (*Point, error) {
-	url_safe_query := url.QueryEscape(query)
-	data, err := g.Request(fmt.Sprintf("search.php?q=%s&format=json", url_safe_query))
+// MapQuest understands QueryLanguage, QueryRegion, QueryBounds and QueryParam.
+func (g *MapQuestGeocoder) Geocode(query string, opts ...QueryOption) (*Point, error) {
+	q := newQueryConfig(opts)
+	data, err := g.geocodeRequest(query, g.queryLanguage(q), q)
 	if err != nil {
 		return nil, err
 	}
@@ -73,6 +114,107 @@ func (g *MapQuestGeocoder) Geocode(query string) (*Point, error) {
 	return p, nil
 }
 
+// Returns every candidate MapQuest finds for the passed in query, or an error
+// if one occurs during the geocoding request or there are no candidates.
+// Results are romanized if the MapQuestGeocoder was created WithTransliteration.
+func (g *MapQuestGeocoder) GeocodeResults(query string, opts ...QueryOption) ([]*GeocodeResult, error) {
+	q := newQueryConfig(opts)
+	data, err := g.geocodeRequest(query, g.queryLanguage(q), q)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := g.extractResultsFromResponse(data)
+	if err != nil || !g.romanize {
+		return results, err
+	}
+
+	romanizeResults(results, func() ([]*GeocodeResult, error) {
+		data, err := g.geocodeRequest(query, "en", q)
+		if err != nil {
+			return nil, err
+		}
+		return g.extractResultsFromResponse(data)
+	})
+
+	return results, nil
+}
+
+// Returns the language the passed in query asks for, or the configured language if it doesn't ask for one.
+func (g *MapQuestGeocoder) queryLanguage(q *queryConfig) string {
+	if q.language != "" {
+		return q.language
+	}
+
+	return g.language
+}
+
+// Issues a search request for the passed in query in the passed in language and returns the response body.
+// Address details are asked for so that each result's country is known.
+func (g *MapQuestGeocoder) geocodeRequest(query string, language string, q *queryConfig) ([]byte, error) {
+	values := url.Values{"q": {query}, "addressdetails": {"1"}, "accept-language": nil}
+	if language != "" {
+		values.Set("accept-language", language)
+	}
+	if q.region != "" {
+		values.Set("countrycodes", strings.ToLower(q.region))
+	}
+	if q.bounds != nil {
+		sw, ne := q.bounds.SouthWest(), q.bounds.NorthEast()
+		values.Set("viewbox", fmt.Sprintf("%f,%f,%f,%f", sw.lng, ne.lat, ne.lng, sw.lat))
+	}
+	q.applyParams(values)
+
+	return g.Request("search.php?" + g.params(values))
+}
+
+// The fields of a single MapQuest search result that golang-geo uses.
+type mapquestSearchResult struct {
+	Lat         string `json:"lat"`
+	Lon         string `json:"lon"`
+	DisplayName string `json:"display_name"`
+	Class       string `json:"class"`
+	Type        string `json:"type"`
+	Address     struct {
+		CountryCode string `json:"country_code"`
+	} `json:"address"`
+}
+
+// Extracts every result from a MapQuest response body.
+func (g *MapQuestGeocoder) extractResultsFromResponse(data []byte) ([]*GeocodeResult, error) {
+	var res []mapquestSearchResult
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, mapquestZeroResultsError
+	}
+
+	results := make([]*GeocodeResult, 0, len(res))
+	for _, r := range res {
+		lat, err := strconv.ParseFloat(r.Lat, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		lng, err := strconv.ParseFloat(r.Lon, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, &GeocodeResult{
+			Point:            NewPoint(lat, lng),
+			FormattedAddress: r.DisplayName,
+			Provider:         "mapquest",
+			CountryCode:      NormalizeCountryCode(r.Address.CountryCode),
+			Quality:          mapquestMatchQuality(r.Class, r.Type),
+		})
+	}
+
+	return results, nil
+}
+
 // Extracts the first lat and lng values from a MapQuest response body.
 func (g *MapQuestGeocoder) extractLatLngFromResponse(data []byte) (float64, float64, error) {
 	res := make([]map[string]interface{}, 0)
@@ -90,8 +232,19 @@ func (g *MapQuestGeocoder) extractLatLngFromResponse(data []byte) (float64, floa
 
 // Returns the first most available address that corresponds to the passed in point.
 // It may also return an error if one occurs during execution.
-func (g *MapQuestGeocoder) ReverseGeocode(p *Point) (string, error) {
-	data, err := g.Request(fmt.Sprintf("reverse.php?lat=%f&lon=%f&format=json", p.lat, p.lng))
+// Reverse geocodes understand QueryLanguage and QueryParam.
+func (g *MapQuestGeocoder) ReverseGeocode(p *Point, opts ...QueryOption) (string, error) {
+	q := newQueryConfig(opts)
+	values := url.Values{
+		"lat": {fmt.Sprintf("%f", p.lat)},
+		"lon": {fmt.Sprintf("%f", p.lng)},
+	}
+	if q.language != "" {
+		values.Set("accept-language", q.language)
+	}
+	q.applyParams(values)
+
+	data, err := g.Request("reverse.php?" + g.params(values))
 	if err != nil {
 		return "", err
 	}
@@ -101,6 +254,23 @@ func (g *MapQuestGeocoder) ReverseGeocode(p *Point) (string, error) {
 	return resStr, nil
 }
 
+// Returns the passed in parameters URL-encoded, along with the response format, the configured
+// language and extra parameters, unless they have ones of the same name, and API key.
+func (g *MapQuestGeocoder) params(values url.Values) string {
+	if _, ok := values["accept-language"]; !ok && g.language != "" {
+		values.Set("accept-language", g.language)
+	}
+
+	addParams(values, g.extraParams)
+	values.Set("format", "json")
+
+	if g.apiKey != "" {
+		values.Set("key", g.apiKey)
+	}
+
+	return values.Encode()
+}
+
 // Return sthe first address in the passed in byte array.
 func (g *MapQuestGeocoder) extractAddressFromResponse(data []byte) string {
 	res := make(map[string]map[string]string)
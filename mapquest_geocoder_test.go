@@ -2,6 +2,7 @@ package geo
 
 import (
 	"fmt"
+	"net/url"
 	"testing"
 )
 
@@ -38,3 +39,121 @@ func TestMapQuestExtractLatLngFromRequestZeroResults(t *testing.T) {
 		t.Error(fmt.Sprintf("Expected error: %v, Got: %v"), mapquestZeroResultsError, err)
 	}
 }
+
+// Ensures that a MapQuestGeocoder satisfies the Geocoder interface.
+var _ Geocoder = &MapQuestGeocoder{}
+
+// Ensures that every result in a MapQuest response is extracted, in order.
+func TestMapQuestExtractResultsFromResponse(t *testing.T) {
+	g := &MapQuestGeocoder{}
+
+	data, err := GetMockResponse("test/data/mapquest_geocode_success.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := g.extractResultsFromResponse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+
+	if results[0].Point.Lat() != 37.62181845 || results[0].Point.Lng() != -122.383992092462 {
+		t.Errorf("Expected [37.62181845, -122.383992092462], got %v", results[0].Point)
+	}
+
+	if results[1].FormattedAddress != "San Francisco Airport, Pichari, La Convención, Department of Cusco, Peru" {
+		t.Errorf("Unexpected formatted address %q", results[1].FormattedAddress)
+	}
+
+	if results[0].CountryCode != "US" || results[1].CountryCode != "PE" {
+		t.Errorf("Expected country codes US and PE, got %q and %q", results[0].CountryCode, results[1].CountryCode)
+	}
+
+	for _, r := range results {
+		if r.Provider != "mapquest" {
+			t.Errorf("Expected provider mapquest, got %s", r.Provider)
+		}
+
+		if r.Quality != Centroid {
+			t.Errorf("Expected an aerodrome to have quality Centroid, got %v", r.Quality)
+		}
+	}
+
+	data, err = GetMockResponse("test/data/mapquest_geocode_zero_results.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := g.extractResultsFromResponse(data); err != mapquestZeroResultsError {
+		t.Errorf("Expected %v, got %v", mapquestZeroResultsError, err)
+	}
+}
+
+// Ensures that options passed to NewMapQuestGeocoder are sent with every request.
+func TestNewMapQuestGeocoder(t *testing.T) {
+	var queries []url.Values
+	server := newQueryRecordingServer(t, "test/data/mapquest_geocode_success.json", &queries)
+
+	g := NewMapQuestGeocoder(WithBaseURL(server.URL), WithAPIKey("secret"), WithLanguage("en"))
+	if _, err := g.Geocode("San Francisco International Airport"); err != nil {
+		t.Fatal(err)
+	}
+
+	q := queries[0]
+	if q.Get("key") != "secret" || q.Get("accept-language") != "en" || q.Get("format") != "json" {
+		t.Errorf("Expected the request to carry the API key, language and format, got %v", q)
+	}
+
+	if q.Get("q") != "San Francisco International Airport" {
+		t.Errorf("Expected the query to be sent as q, got %v", q)
+	}
+}
+
+// Ensures that QueryOptions MapQuest understands are sent as its parameters, and the others are ignored.
+func TestMapQuestGeocodeQueryOptions(t *testing.T) {
+	var queries []url.Values
+	server := newQueryRecordingServer(t, "test/data/mapquest_geocode_success.json", &queries)
+
+	g := NewMapQuestGeocoder(WithBaseURL(server.URL))
+	_, err := g.Geocode("San Francisco International Airport",
+		QueryLanguage("de"),
+		QueryRegion("US"),
+		QueryBounds(NewBounds(NewPoint(37.5, -122.5), NewPoint(37.7, -122.3))),
+		QueryComponents(map[string]string{"country": "US"}),
+		QueryParam("extratags", "1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := queries[0]
+	if q.Get("accept-language") != "de" || q.Get("countrycodes") != "us" || q.Get("extratags") != "1" {
+		t.Errorf("Expected the language, country code and extra param to be sent, got %v", q)
+	}
+
+	if q.Get("viewbox") != "-122.500000,37.700000,-122.300000,37.500000" {
+		t.Errorf("Unexpected viewbox: %q", q.Get("viewbox"))
+	}
+
+	if q.Has("components") {
+		t.Errorf("Expected components to be ignored, got %v", q)
+	}
+}
+
+// Ensures that parameters added WithParam are sent with every request, without replacing the response format.
+func TestMapQuestGeocoderWithParam(t *testing.T) {
+	var queries []url.Values
+	server := newQueryRecordingServer(t, "test/data/mapquest_geocode_success.json", &queries)
+
+	g := NewMapQuestGeocoder(WithBaseURL(server.URL), WithParam("extratags", "1"), WithParam("format", "xml"))
+	if _, err := g.Geocode("San Francisco International Airport"); err != nil {
+		t.Fatal(err)
+	}
+
+	if q := queries[0]; q.Get("extratags") != "1" || q.Get("format") != "json" {
+		t.Errorf("Expected the extra parameter and the json format, got %v", q)
+	}
+}
@@ -0,0 +1,84 @@
+package geo
+
+// A MatchQuality describes how precisely a geocoded point locates what was asked for,
+// normalized across providers.  Better qualities compare greater than worse ones,
+// so that results can be filtered with a single comparison, e.g. q >= Interpolated.
+type MatchQuality int
+
+const (
+	// The provider gave no indication of the result's precision.
+	UnknownMatchQuality MatchQuality = iota
+
+	// The point lies somewhere within a large area, such as a city or postal code.
+	Approximate
+
+	// The point is the center of a feature, such as a street or a park.
+	Centroid
+
+	// The point was interpolated between two precise points, such as along a street's house numbers.
+	Interpolated
+
+	// The point is the precise location of a building or address.
+	Rooftop
+)
+
+// Returns the name of the MatchQuality.
+func (q MatchQuality) String() string {
+	switch q {
+	case Approximate:
+		return "Approximate"
+	case Centroid:
+		return "Centroid"
+	case Interpolated:
+		return "Interpolated"
+	case Rooftop:
+		return "Rooftop"
+	default:
+		return "Unknown"
+	}
+}
+
+// Returns the MatchQuality of a Google result with the passed in geometry.location_type.
+func googleMatchQuality(locationType string) MatchQuality {
+	switch locationType {
+	case "ROOFTOP":
+		return Rooftop
+	case "RANGE_INTERPOLATED":
+		return Interpolated
+	case "GEOMETRIC_CENTER":
+		return Centroid
+	case "APPROXIMATE":
+		return Approximate
+	default:
+		return UnknownMatchQuality
+	}
+}
+
+// Returns the MatchQuality of a MapQuest (Nominatim) result with the passed in OSM class and type.
+func mapquestMatchQuality(class, typ string) MatchQuality {
+	switch {
+	case class == "place" && typ == "house":
+		// Nominatim reports house numbers interpolated along a street as place=house.
+		return Interpolated
+	case class == "building" || typ == "house" || typ == "building":
+		return Rooftop
+	case class == "place" || class == "boundary":
+		return Approximate
+	case class == "":
+		return UnknownMatchQuality
+	default:
+		return Centroid
+	}
+}
+
+// Returns the results whose Quality is at least the passed in MatchQuality, in their original order.
+func FilterByQuality(results []*GeocodeResult, min MatchQuality) []*GeocodeResult {
+	filtered := make([]*GeocodeResult, 0, len(results))
+	for _, r := range results {
+		if r.Quality >= min {
+			filtered = append(filtered, r)
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,76 @@
+package geo
+
+import (
+	"testing"
+)
+
+// Ensures that better match qualities compare greater than worse ones.
+func TestMatchQualityOrder(t *testing.T) {
+	order := []MatchQuality{UnknownMatchQuality, Approximate, Centroid, Interpolated, Rooftop}
+	for i := 1; i < len(order); i++ {
+		if order[i] <= order[i-1] {
+			t.Errorf("Expected %v to be better than %v", order[i], order[i-1])
+		}
+	}
+}
+
+// Ensures that each provider's accuracy fields are mapped to the expected MatchQuality.
+func TestProviderMatchQuality(t *testing.T) {
+	google := map[string]MatchQuality{
+		"ROOFTOP":            Rooftop,
+		"RANGE_INTERPOLATED": Interpolated,
+		"GEOMETRIC_CENTER":   Centroid,
+		"APPROXIMATE":        Approximate,
+		"":                   UnknownMatchQuality,
+	}
+	for locationType, want := range google {
+		if got := googleMatchQuality(locationType); got != want {
+			t.Errorf("Expected %q to map to %v, got %v", locationType, want, got)
+		}
+	}
+
+	mapquest := []struct {
+		class, typ string
+		want       MatchQuality
+	}{
+		{"building", "yes", Rooftop},
+		{"amenity", "house", Rooftop},
+		{"place", "house", Interpolated},
+		{"highway", "residential", Centroid},
+		{"aeroway", "aerodrome", Centroid},
+		{"place", "city", Approximate},
+		{"boundary", "administrative", Approximate},
+		{"", "", UnknownMatchQuality},
+	}
+	for _, c := range mapquest {
+		if got := mapquestMatchQuality(c.class, c.typ); got != c.want {
+			t.Errorf("Expected %s=%s to map to %v, got %v", c.class, c.typ, c.want, got)
+		}
+	}
+}
+
+// Ensures that FilterByQuality drops worse results and keeps the order of the rest.
+func TestFilterByQuality(t *testing.T) {
+	rooftop := &GeocodeResult{Quality: Rooftop}
+	city := &GeocodeResult{Quality: Approximate}
+	street := &GeocodeResult{Quality: Centroid}
+
+	filtered := FilterByQuality([]*GeocodeResult{rooftop, city, street}, Centroid)
+	if len(filtered) != 2 || filtered[0] != rooftop || filtered[1] != street {
+		t.Errorf("Expected the rooftop and street results, got %v", filtered)
+	}
+}
+
+// Ensures that a RankingGeocoder discards candidates below its MinQuality.
+func TestRankingGeocoderMinQuality(t *testing.T) {
+	provider := &stubResultGeocoder{results: []*GeocodeResult{
+		{Point: NewPoint(37.77, -122.42), FormattedAddress: "San Francisco, CA, USA", Quality: Approximate},
+	}}
+
+	g := NewRankingGeocoder(nil, provider)
+	g.MinQuality = Interpolated
+
+	if _, err := g.Geocode("San Francisco"); err != rankingNoResultsError {
+		t.Errorf("Expected %v, got %v", rankingNoResultsError, err)
+	}
+}
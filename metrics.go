@@ -0,0 +1,279 @@
+package geo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// The response headers providers report their remaining rate limit in, most specific first.
+var rateLimitRemainingHeaders = []string{"X-RateLimit-Remaining", "RateLimit-Remaining", "X-Quota-Remaining"}
+
+// Metrics counts cache hits and misses, the responses of each provider by HTTP status code,
+// and each provider's remaining quota, and exposes them for Prometheus to scrape:
+//
+//	m := geo.NewMetrics()
+//	g := geo.NewGoogleGeocoder(geo.WithAPIKey(key), geo.WithMetrics(m))
+//	cache.Metrics = m
+//	http.Handle("/metrics", m.Handler())
+//
+// A Metrics is safe to share between goroutines, providers and caches.
+type Metrics struct {
+	mu sync.Mutex
+
+	hits   map[string]int64
+	misses map[string]int64
+
+	// Responses by provider, then by HTTP status code, or 0 for requests that got no response.
+	responses map[string]map[int]int64
+
+	// The remaining quota each provider last reported in its response headers.
+	remaining map[string]int64
+
+	quotas []*Quota
+}
+
+// Creates and returns a pointer to a new, empty Metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		hits:      make(map[string]int64),
+		misses:    make(map[string]int64),
+		responses: make(map[string]map[int]int64),
+		remaining: make(map[string]int64),
+	}
+}
+
+// Reports the remaining budgets of the passed in Quota alongside those providers report themselves.
+func (m *Metrics) WatchQuota(q *Quota) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.quotas = append(m.quotas, q)
+}
+
+// Records a lookup in the named cache, and whether or not it was a hit.
+// Safe to call on a nil Metrics, which records nothing.
+func (m *Metrics) recordCache(cache string, hit bool) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if hit {
+		m.hits[cache]++
+	} else {
+		m.misses[cache]++
+	}
+}
+
+// Records a response from the named provider, or a request that got none if resp is nil.
+func (m *Metrics) recordResponse(provider string, resp *http.Response) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	code := 0
+	if resp != nil {
+		code = resp.StatusCode
+		for _, header := range rateLimitRemainingHeaders {
+			if remaining, err := strconv.ParseInt(resp.Header.Get(header), 10, 64); err == nil {
+				m.remaining[provider] = remaining
+				break
+			}
+		}
+	}
+
+	if m.responses[provider] == nil {
+		m.responses[provider] = make(map[int]int64)
+	}
+	m.responses[provider][code]++
+}
+
+// Returns the fraction of lookups in the named cache that were hits, or 0 if there have been none.
+func (m *Metrics) CacheHitRatio(cache string) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	total := m.hits[cache] + m.misses[cache]
+	if total == 0 {
+		return 0
+	}
+
+	return float64(m.hits[cache]) / float64(total)
+}
+
+// Returns the number of responses from the named provider with the passed in HTTP status code,
+// or with a code of 0, the number of its requests that got no response at all.
+func (m *Metrics) Responses(provider string, code int) int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.responses[provider][code]
+}
+
+// Returns the remaining quota the named provider last reported in its response headers,
+// and whether or not it has reported one.
+func (m *Metrics) QuotaRemaining(provider string) (int64, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	remaining, ok := m.remaining[provider]
+	return remaining, ok
+}
+
+// Returns an http.RoundTripper that records the responses of the named provider,
+// issuing requests with the passed in transport, or the shared transport if it is nil.
+func (m *Metrics) Transport(provider string, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = defaultTransport()
+	}
+
+	return &metricsTransport{metrics: m, provider: provider, base: base}
+}
+
+// An http.RoundTripper recording the responses of a provider.
+type metricsTransport struct {
+	metrics  *Metrics
+	provider string
+	base     http.RoundTripper
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	t.metrics.recordResponse(t.provider, resp)
+	return resp, err
+}
+
+// Writes every metric in the Prometheus text exposition format.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	quotas := append([]*Quota(nil), m.quotas...)
+	m.mu.Unlock()
+
+	// Read the Quotas before taking the lock again, since each takes its own.
+	budgets := make(map[string]int64)
+	for _, q := range quotas {
+		for _, provider := range q.providers() {
+			if remaining, ok := q.Remaining(provider); ok {
+				budgets[provider] = int64(remaining)
+			}
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	cw := &countingWriter{w: bw}
+
+	caches := sortedKeys(m.hits, m.misses)
+	writeMetricHeader(cw, "geo_cache_hits_total", "counter", "Lookups that found an entry in the cache.")
+	for _, cache := range caches {
+		fmt.Fprintf(cw, "geo_cache_hits_total{cache=%s} %d\n", metricLabel(cache), m.hits[cache])
+	}
+	writeMetricHeader(cw, "geo_cache_misses_total", "counter", "Lookups that found no entry in the cache.")
+	for _, cache := range caches {
+		fmt.Fprintf(cw, "geo_cache_misses_total{cache=%s} %d\n", metricLabel(cache), m.misses[cache])
+	}
+	writeMetricHeader(cw, "geo_cache_hit_ratio", "gauge", "The fraction of lookups that found an entry in the cache.")
+	for _, cache := range caches {
+		ratio := float64(m.hits[cache]) / float64(m.hits[cache]+m.misses[cache])
+		fmt.Fprintf(cw, "geo_cache_hit_ratio{cache=%s} %s\n", metricLabel(cache), strconv.FormatFloat(ratio, 'g', -1, 64))
+	}
+
+	providers := make([]string, 0, len(m.responses))
+	for provider := range m.responses {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+
+	writeMetricHeader(cw, "geo_provider_responses_total", "counter", "Provider responses by HTTP status code, or 0 for requests that got none.")
+	for _, provider := range providers {
+		codes := make([]int, 0, len(m.responses[provider]))
+		for code := range m.responses[provider] {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+
+		for _, code := range codes {
+			fmt.Fprintf(cw, "geo_provider_responses_total{provider=%s,code=\"%d\"} %d\n", metricLabel(provider), code, m.responses[provider][code])
+		}
+	}
+
+	writeMetricHeader(cw, "geo_provider_quota_remaining", "gauge", "Requests each provider may still issue, as it reports or as its Quota budget allows.")
+	for _, provider := range sortedKeys(m.remaining) {
+		fmt.Fprintf(cw, "geo_provider_quota_remaining{provider=%s,source=\"provider\"} %d\n", metricLabel(provider), m.remaining[provider])
+	}
+	for _, provider := range sortedKeys(budgets) {
+		fmt.Fprintf(cw, "geo_provider_quota_remaining{provider=%s,source=\"quota\"} %d\n", metricLabel(provider), budgets[provider])
+	}
+
+	if err := bw.Flush(); err != nil {
+		return cw.n, err
+	}
+
+	return cw.n, cw.err
+}
+
+// Returns an http.Handler serving every metric in the Prometheus text exposition format,
+// for Prometheus to scrape.
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		m.WriteTo(w)
+	})
+}
+
+// Writes the HELP and TYPE lines that precede a metric.
+func writeMetricHeader(w io.Writer, name, typ, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// Returns the keys of the passed in maps, sorted and without duplicates.
+func sortedKeys(maps ...map[string]int64) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, m := range maps {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// An io.Writer that counts the bytes written through it and keeps the first error.
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
+
+// Escapes label values as the Prometheus text format requires.
+var metricLabelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+// Returns the passed in label value quoted and escaped as the Prometheus text format requires.
+func metricLabel(value string) string {
+	return `"` + metricLabelEscaper.Replace(value) + `"`
+}
@@ -0,0 +1,95 @@
+package geo
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Ensures that provider responses are counted by status code, along with the quota they report.
+func TestMetricsProviderResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("address") == "limit" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("X-RateLimit-Remaining", "41")
+		w.Write([]byte(`{"results":[],"status":"ZERO_RESULTS"}`))
+	}))
+	defer server.Close()
+
+	m := NewMetrics()
+	g := NewGoogleGeocoder(WithBaseURL(server.URL), WithMetrics(m))
+	g.Geocode("Atlantis")
+	g.Geocode("Atlantis")
+	g.Geocode("limit")
+
+	if m.Responses("google", http.StatusOK) != 2 || m.Responses("google", http.StatusTooManyRequests) != 1 {
+		t.Errorf("Expected 2 OK and 1 Too Many Requests, got %d and %d", m.Responses("google", 200), m.Responses("google", 429))
+	}
+
+	if remaining, ok := m.QuotaRemaining("google"); !ok || remaining != 41 {
+		t.Errorf("Expected 41 remaining, got %d (%v)", remaining, ok)
+	}
+
+	NewMapQuestGeocoder(WithBaseURL("http://127.0.0.1:0"), WithMetrics(m)).Geocode("Atlantis")
+	if m.Responses("mapquest", 0) != 1 {
+		t.Errorf("Expected a request without a response, got %d", m.Responses("mapquest", 0))
+	}
+}
+
+// Ensures that cache lookups are counted as hits and misses.
+func TestMetricsCacheHitRatio(t *testing.T) {
+	m := NewMetrics()
+	c := openTestCache(t, 0, 0)
+	c.Metrics = m
+
+	sfo := NewPoint(37.615223, -122.389979)
+	g := &CachedGeocoder{Geocoder: &stubGeocoder{points: map[string]*Point{"SFO": sfo}}, Cache: c}
+	for i := 0; i < 4; i++ {
+		g.Geocode("SFO")
+	}
+
+	if ratio := m.CacheHitRatio("geocode"); ratio != 0.75 {
+		t.Errorf("Expected a hit ratio of 0.75, got %v", ratio)
+	}
+}
+
+// Ensures that metrics are written in the Prometheus text format, with the budgets of watched Quotas.
+func TestMetricsWriteTo(t *testing.T) {
+	m := NewMetrics()
+	m.recordCache("geocode", true)
+	m.recordCache("geocode", false)
+	m.recordResponse("google", &http.Response{StatusCode: 200, Header: http.Header{}})
+
+	q := NewQuota()
+	q.SetDailyBudget("google", 100)
+	q.Spend("google")
+	m.WatchQuota(q)
+
+	var buf bytes.Buffer
+	if _, err := m.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{
+		"# TYPE geo_cache_hits_total counter",
+		`geo_cache_hits_total{cache="geocode"} 1`,
+		`geo_cache_misses_total{cache="geocode"} 1`,
+		`geo_cache_hit_ratio{cache="geocode"} 0.5`,
+		`geo_provider_responses_total{provider="google",code="200"} 1`,
+		`geo_provider_quota_remaining{provider="google",source="quota"} 99`,
+	} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Errorf("Expected %q in:\n%s", line, buf.String())
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain") || rec.Body.String() != buf.String() {
+		t.Errorf("Expected the handler to serve the metrics as text, got %q", rec.Body.String())
+	}
+}
@@ -0,0 +1,47 @@
+package geo
+
+import (
+	"net/http"
+)
+
+// A RequestFunc issues an HTTP request to a provider and returns its response.
+type RequestFunc func(req *http.Request) (*http.Response, error)
+
+// A Middleware wraps the RequestFunc that issues a provider's requests, letting it change requests
+// before they are sent, such as to add authentication headers, and observe or replace their responses,
+// such as to log them or to inject failures for chaos testing:
+//
+//	auth := func(next geo.RequestFunc) geo.RequestFunc {
+//		return func(req *http.Request) (*http.Response, error) {
+//			req = req.Clone(req.Context())
+//			req.Header.Set("Authorization", "Bearer "+token)
+//			return next(req)
+//		}
+//	}
+//	g := geo.NewGoogleGeocoder(geo.WithAPIKey(key), geo.WithMiddleware(auth))
+//
+// Like an http.RoundTripper, a Middleware should clone a request rather than modify it,
+// and must close the body of any response it replaces.
+type Middleware func(next RequestFunc) RequestFunc
+
+// Returns an http.RoundTripper that passes every request through the passed in middleware,
+// the first outermost, issuing them with the passed in transport, or the shared transport if it is nil.
+func MiddlewareTransport(base http.RoundTripper, middleware ...Middleware) http.RoundTripper {
+	if base == nil {
+		base = defaultTransport()
+	}
+
+	next := RequestFunc(base.RoundTrip)
+	for i := len(middleware) - 1; i >= 0; i-- {
+		next = middleware[i](next)
+	}
+
+	return middlewareTransport(next)
+}
+
+// An http.RoundTripper issuing requests with a chain of middleware.
+type middlewareTransport RequestFunc
+
+func (t middlewareTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t(req)
+}
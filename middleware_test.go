@@ -0,0 +1,57 @@
+package geo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Ensures that middleware runs in order around every request, and can change requests or fail them.
+func TestWithMiddleware(t *testing.T) {
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next RequestFunc) RequestFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				return next(req)
+			}
+		}
+	}
+	withAuth := func(next RequestFunc) RequestFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			req.Header.Set("Authorization", "Bearer token")
+			return next(req)
+		}
+	}
+
+	g := NewMapQuestGeocoder(WithBaseURL(server.URL), WithMiddleware(trace("first"), trace("second")), WithMiddleware(withAuth))
+	if _, err := g.Geocode("SFO"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected %v, got %v", ErrNotFound, err)
+	}
+
+	if strings.Join(order, ",") != "first,second" || auth != "Bearer token" {
+		t.Errorf("Expected both middleware in order and the Authorization header, got %v and %q", order, auth)
+	}
+
+	chaos := errors.New("chaos")
+	fail := func(next RequestFunc) RequestFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			return nil, chaos
+		}
+	}
+
+	g = NewMapQuestGeocoder(WithBaseURL(server.URL), WithMiddleware(fail))
+	if _, err := g.Geocode("SFO"); !errors.Is(err, chaos) || !errors.Is(err, ErrProviderDown) {
+		t.Errorf("Expected the injected failure, got %v", err)
+	}
+}
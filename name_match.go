@@ -0,0 +1,125 @@
+package geo
+
+import (
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Common abbreviations found in place names and addresses, and the words they stand for.
+// Names are compared after expansion, so that "Main St" matches "Main Street".
+var nameAbbreviations = map[string]string{
+	"apt":    "apartment",
+	"arpt":   "airport",
+	"av":     "avenue",
+	"ave":    "avenue",
+	"bldg":   "building",
+	"blvd":   "boulevard",
+	"centre": "center",
+	"cres":   "crescent",
+	"ct":     "court",
+	"ctr":    "center",
+	"dr":     "drive",
+	"e":      "east",
+	"ft":     "fort",
+	"hwy":    "highway",
+	"intl":   "international",
+	"ln":     "lane",
+	"mt":     "mount",
+	"n":      "north",
+	"natl":   "national",
+	"ne":     "northeast",
+	"nw":     "northwest",
+	"pkwy":   "parkway",
+	"pl":     "place",
+	"rd":     "road",
+	"s":      "south",
+	"se":     "southeast",
+	"sq":     "square",
+	"st":     "street",
+	"sta":    "station",
+	"ste":    "suite",
+	"stn":    "station",
+	"sw":     "southwest",
+	"ter":    "terrace",
+	"univ":   "university",
+	"w":      "west",
+}
+
+// Letters that don't decompose into a base letter and a combining mark,
+// and so need folding by hand.
+var nameLigatures = strings.NewReplacer(
+	"ß", "ss", "æ", "ae", "œ", "oe", "ø", "o", "ł", "l", "đ", "d", "ð", "d", "þ", "th", "ı", "i",
+	"Æ", "Ae", "Œ", "Oe", "Ø", "O", "Ł", "L", "Đ", "D", "Ð", "D", "Þ", "Th",
+)
+
+// Returns the passed in string with diacritics removed, e.g. "Zürich" becomes "Zurich".
+func FoldDiacritics(s string) string {
+	// Strip combining marks after canonical decomposition, turning "é" into "e".
+	// Chained transformers keep state, so each call needs its own.
+	folder := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(folder, s)
+	if err != nil {
+		folded = s
+	}
+
+	return nameLigatures.Replace(folded)
+}
+
+// Returns the normalized form of a place name used for comparisons: diacritics
+// folded, lower case, punctuation removed and abbreviations expanded.
+func NormalizeName(name string) string {
+	return strings.Join(nameTokens(name), " ")
+}
+
+// Returns the normalized words of the passed in name.
+func nameTokens(name string) []string {
+	folded := strings.ToLower(FoldDiacritics(name))
+	tokens := strings.FieldsFunc(folded, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '\''
+	})
+
+	expanded := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		token = strings.Replace(token, "'", "", -1)
+		if token == "" {
+			continue
+		}
+
+		if word, ok := nameAbbreviations[token]; ok {
+			token = word
+		}
+		expanded = append(expanded, token)
+	}
+
+	return expanded
+}
+
+// Returns the normalized words of the passed in name in sorted order,
+// so that word order doesn't affect comparisons.
+func nameKey(name string) string {
+	tokens := nameTokens(name)
+	sort.Strings(tokens)
+	return strings.Join(tokens, " ")
+}
+
+// Returns the similarity of two name keys from 0 to 1.
+func nameKeySimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+
+	return math.Max(levenshteinSimilarity(a, b), trigramSimilarity(trigrams(a), trigrams(b)))
+}
+
+// Returns how similar two place names are, from 0 (nothing alike) to 1 (the same name).
+// Names are normalized (see NormalizeName) and their words sorted before they are
+// compared, so "Zürich Hauptbahnhof" matches "hauptbahnhof zurich" and
+// "123 N Main St." matches "123 North Main Street" perfectly.
+func MatchName(a, b string) float64 {
+	return nameKeySimilarity(nameKey(a), nameKey(b))
+}
@@ -0,0 +1,66 @@
+package geo
+
+import (
+	"testing"
+)
+
+// Ensures that diacritics and ligatures are folded to plain letters.
+func TestFoldDiacritics(t *testing.T) {
+	cases := map[string]string{
+		"Zürich":        "Zurich",
+		"São Paulo":     "Sao Paulo",
+		"Kraków":        "Krakow",
+		"Łódź":          "Lodz",
+		"Straße":        "Strasse",
+		"Ærøskøbing":    "Aeroskobing",
+		"San Francisco": "San Francisco",
+	}
+
+	for in, expected := range cases {
+		if out := FoldDiacritics(in); out != expected {
+			t.Errorf("Expected FoldDiacritics(%q) to be %q, got %q", in, expected, out)
+		}
+	}
+}
+
+// Ensures that names are lower cased, stripped of punctuation, and have abbreviations expanded.
+func TestNormalizeName(t *testing.T) {
+	cases := map[string]string{
+		"123 N. Main St.":           "123 north main street",
+		"St. Mary's Ave":            "street marys avenue",
+		"  Zürich   Hauptbahnhof ":  "zurich hauptbahnhof",
+		"San Francisco Int'l Arpt.": "san francisco international airport",
+	}
+
+	for in, expected := range cases {
+		if out := NormalizeName(in); out != expected {
+			t.Errorf("Expected NormalizeName(%q) to be %q, got %q", in, expected, out)
+		}
+	}
+}
+
+// Ensures that equivalent names match perfectly and unrelated names poorly.
+func TestMatchName(t *testing.T) {
+	perfect := [][2]string{
+		{"123 N Main St.", "123 North Main Street"},
+		{"Zürich Hauptbahnhof", "hauptbahnhof zurich"},
+		{"São Paulo", "Sao Paulo"},
+	}
+
+	for _, pair := range perfect {
+		if score := MatchName(pair[0], pair[1]); score != 1 {
+			t.Errorf("Expected %q and %q to match perfectly, got %f", pair[0], pair[1], score)
+		}
+	}
+
+	typo := MatchName("San Francisco International Airport", "San Fransisco Intl Airport")
+	unrelated := MatchName("San Francisco International Airport", "Brooklyn Bridge")
+
+	if typo < 0.9 {
+		t.Errorf("Expected a typo to still match well, got %f", typo)
+	}
+
+	if unrelated > 0.4 {
+		t.Errorf("Expected unrelated names to match poorly, got %f", unrelated)
+	}
+}
@@ -0,0 +1,43 @@
+package geo
+
+// Repairs the current Polygon's ring in place: nil points and points repeating the one before them
+// are dropped, along with a last point repeating the first, since the edge back to the first point
+// is implied and serializers close the ring as their formats require, and the ring is wound
+// counterclockwise, seen from above, as RFC 7946 requires of GeoJSON's exterior rings.
+func (p *Polygon) Normalize() {
+	var ring []*Point
+	for _, point := range p.points {
+		if point != nil && (len(ring) == 0 || *point != *ring[len(ring)-1]) {
+			ring = append(ring, point)
+		}
+	}
+
+	if n := len(ring); n > 1 && *ring[0] == *ring[n-1] {
+		ring = ring[:n-1]
+	}
+
+	if ringTurn(ring) < 0 {
+		for i, j := 0, len(ring)-1; i < j; i, j = i+1, j-1 {
+			ring[i], ring[j] = ring[j], ring[i]
+		}
+	}
+
+	p.points = ring
+}
+
+// Returns twice the signed area, in square degrees, of the ring of the passed in points, with longitude
+// as x and latitude as y: positive when it is wound counterclockwise and negative when clockwise.
+// Rings crossing the antimeridian are measured with their longitudes made continuous.
+func ringTurn(points []*Point) float64 {
+	if crossesAntimeridian(points, true) {
+		points = unwrapLngs(points)
+	}
+
+	turn := 0.0
+	for i, a := range points {
+		b := points[(i+1)%len(points)]
+		turn += a.lng*b.lat - b.lng*a.lat
+	}
+
+	return turn
+}
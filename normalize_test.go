@@ -0,0 +1,90 @@
+package geo
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// Ensures that rings are wound counterclockwise, without repeated points, once normalized.
+func TestPolygonNormalize(t *testing.T) {
+	cases := []struct {
+		name     string
+		ring     []*Point
+		expected []*Point
+	}{
+		{
+			"clockwise",
+			[]*Point{NewPoint(0, 0), NewPoint(1, 0), NewPoint(1, 1), NewPoint(0, 1)},
+			[]*Point{NewPoint(0, 1), NewPoint(1, 1), NewPoint(1, 0), NewPoint(0, 0)},
+		},
+		{
+			"counterclockwise",
+			[]*Point{NewPoint(0, 0), NewPoint(0, 1), NewPoint(1, 1), NewPoint(1, 0)},
+			[]*Point{NewPoint(0, 0), NewPoint(0, 1), NewPoint(1, 1), NewPoint(1, 0)},
+		},
+		{
+			"repeated points",
+			[]*Point{NewPoint(0, 0), NewPoint(0, 0), NewPoint(0, 1), nil, NewPoint(1, 1), NewPoint(1, 1), NewPoint(0, 0)},
+			[]*Point{NewPoint(0, 0), NewPoint(0, 1), NewPoint(1, 1)},
+		},
+		{
+			"clockwise across the antimeridian",
+			[]*Point{NewPoint(0, 179), NewPoint(1, 179), NewPoint(1, -179), NewPoint(0, -179)},
+			[]*Point{NewPoint(0, -179), NewPoint(1, -179), NewPoint(1, 179), NewPoint(0, 179)},
+		},
+	}
+
+	for _, c := range cases {
+		polygon := NewPolygon(c.ring)
+		polygon.Normalize()
+
+		points := polygon.Points()
+		if len(points) != len(c.expected) {
+			t.Errorf("Expected the %s ring to become %v, got %v", c.name, c.expected, points)
+			continue
+		}
+		for i, p := range points {
+			if *p != *c.expected[i] {
+				t.Errorf("Expected the %s ring to become %v, got %v", c.name, c.expected, points)
+				break
+			}
+		}
+	}
+
+	empty := NewPolygon(nil)
+	if empty.Normalize(); len(empty.Points()) != 0 {
+		t.Errorf("Expected an empty polygon to stay empty, got %v", empty.Points())
+	}
+}
+
+// Ensures that polygons are normalized on import only when the caller asks for it.
+func TestNormalizePolygonsOnImport(t *testing.T) {
+	geoJSON := `{"type":"Feature","geometry":{"type":"Polygon","coordinates":[[[0,0],[0,1],[0,1],[1,1],[1,0],[0,0]]]},"properties":{}}`
+	wkt := "POLYGON((0 0, 0 1, 1 1, 1 0))"
+
+	var f Feature
+	if err := json.Unmarshal([]byte(geoJSON), &f); err != nil || len(f.Geometry.(*Polygon).Points()) != 5 {
+		t.Errorf("Expected the polygon to be read as written, got %v (%v)", f.Geometry, err)
+	}
+	if _, err := UnmarshalWKT(wkt); err == nil {
+		t.Error("Expected an unclosed WKT ring to be refused")
+	}
+
+	if err := f.UnmarshalGeoJSON([]byte(geoJSON), true); err != nil || len(f.Geometry.(*Polygon).Points()) != 4 || ringTurn(f.Geometry.(*Polygon).Points()) <= 0 {
+		t.Errorf("Expected the polygon to be normalized, got %v (%v)", f.Geometry, err)
+	}
+
+	var c FeatureCollection
+	collection := `{"type":"FeatureCollection","features":[` + geoJSON + `,null]}`
+	if err := c.UnmarshalGeoJSON([]byte(collection), true); err != nil || len(c.Features) != 2 || c.Features[1] != nil || len(c.Features[0].Geometry.(*Polygon).Points()) != 4 {
+		t.Errorf("Expected the collection's polygon to be normalized, got %v (%v)", c.Features, err)
+	}
+	if err := json.Unmarshal([]byte(collection), &c); err != nil || len(c.Features) != 2 || len(c.Features[0].Geometry.(*Polygon).Points()) != 5 {
+		t.Errorf("Expected the collection's polygon to be read as written, got %v (%v)", c.Features, err)
+	}
+
+	g, err := UnmarshalNormalizedWKT(wkt)
+	if err != nil || len(g.(*Polygon).Points()) != 4 || ringTurn(g.(*Polygon).Points()) <= 0 {
+		t.Errorf("Expected the unclosed WKT ring to be closed and normalized, got %v (%v)", g, err)
+	}
+}
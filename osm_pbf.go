@@ -0,0 +1,551 @@
+package geo
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"google.golang.org/protobuf/encoding/protowire"
+	"io"
+)
+
+// The largest blob header and blob that an OpenStreetMap PBF file may contain.
+const (
+	osmMaxBlobHeaderSize = 64 * 1024
+	osmMaxBlobSize       = 32 * 1024 * 1024
+)
+
+// The features an OpenStreetMap PBF file may require of its readers
+// that OSMReader supports.
+var osmSupportedFeatures = map[string]bool{
+	"OsmSchema-V0.6": true,
+	"DenseNodes":     true,
+}
+
+// This is the error that consumers receive when a PBF file is truncated or malformed.
+var invalidOSMError = errors.New("invalid OpenStreetMap PBF data")
+
+// An OSMFilter decides from an OpenStreetMap node or way's tags whether
+// it should be read as a Feature.
+type OSMFilter func(tags map[string]string) bool
+
+// Returns an OSMFilter that matches elements with the passed in tag key, such as "amenity".
+// If any values are passed in, the tag must also have one of them, such as "cafe".
+func OSMTagFilter(key string, values ...string) OSMFilter {
+	return func(tags map[string]string) bool {
+		value, ok := tags[key]
+		if !ok || len(values) == 0 {
+			return ok
+		}
+
+		for _, v := range values {
+			if value == v {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// An OSMReader streams the nodes and ways of an OpenStreetMap PBF extract,
+// such as those published by Geofabrik, that match its filter as Features.
+// Nodes become Points and ways become Lines, or Polygons if they are closed.
+// Features are identified as "node/<id>" or "way/<id>" and their properties are their tags.
+//
+// To place the points of ways, the reader remembers the position of every node it reads,
+// which takes some tens of bytes per node; city and regional extracts fit comfortably
+// in memory, the whole planet does not.  Set SkipWays to read only nodes without doing so.
+type OSMReader struct {
+	SkipWays bool
+
+	r       io.Reader
+	filter  OSMFilter
+	nodes   map[int64][2]int32
+	pending []*Feature
+}
+
+// Creates and returns a pointer to a new OSMReader reading the PBF data from the passed in reader,
+// returning the elements that match the passed in filter.  A nil filter matches every
+// element with at least one tag.
+func NewOSMReader(r io.Reader, filter OSMFilter) *OSMReader {
+	if filter == nil {
+		filter = func(tags map[string]string) bool { return len(tags) > 0 }
+	}
+
+	return &OSMReader{r: r, filter: filter, nodes: make(map[int64][2]int32)}
+}
+
+// Returns the next matching element, or io.EOF once the whole file has been read.
+func (r *OSMReader) Next() (*Feature, error) {
+	for len(r.pending) == 0 {
+		if err := r.readBlock(); err != nil {
+			return nil, err
+		}
+	}
+
+	f := r.pending[0]
+	r.pending = r.pending[1:]
+	return f, nil
+}
+
+// Reads every element of the passed in PBF data that matches the passed in filter
+// into a FeatureCollection.
+func ReadOSMFeatures(r io.Reader, filter OSMFilter) (*FeatureCollection, error) {
+	reader := NewOSMReader(r, filter)
+	fc := &FeatureCollection{}
+	for {
+		f, err := reader.Next()
+		if err == io.EOF {
+			return fc, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		fc.Features = append(fc.Features, f)
+	}
+}
+
+// Reads the next blob of the file, and queues the features it holds.
+func (r *OSMReader) readBlock() error {
+	var size [4]byte
+	if _, err := io.ReadFull(r.r, size[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return invalidOSMError
+		}
+		return err
+	}
+
+	headerSize := binary.BigEndian.Uint32(size[:])
+	if headerSize > osmMaxBlobHeaderSize {
+		return invalidOSMError
+	}
+
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(r.r, header); err != nil {
+		return invalidOSMError
+	}
+
+	var blobType string
+	var blobSize int64
+	err := osmFields(header, func(num protowire.Number, typ protowire.Type, v []byte, n uint64) error {
+		switch num {
+		case 1:
+			blobType = string(v)
+		case 3:
+			blobSize = int64(n)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if blobSize < 0 || blobSize > osmMaxBlobSize {
+		return invalidOSMError
+	}
+
+	blob := make([]byte, blobSize)
+	if _, err := io.ReadFull(r.r, blob); err != nil {
+		return invalidOSMError
+	}
+
+	data, err := osmBlobData(blob)
+	if err != nil {
+		return err
+	}
+
+	switch blobType {
+	case "OSMHeader":
+		return osmCheckHeader(data)
+	case "OSMData":
+		return r.readPrimitiveBlock(data)
+	}
+
+	// Readers are to skip blob types they don't know.
+	return nil
+}
+
+// Returns the uncompressed contents of the passed in blob.
+func osmBlobData(blob []byte) ([]byte, error) {
+	var raw, compressed []byte
+	var rawSize int64
+	var unsupported bool
+	err := osmFields(blob, func(num protowire.Number, typ protowire.Type, v []byte, n uint64) error {
+		switch num {
+		case 1:
+			raw = v
+		case 2:
+			rawSize = int64(n)
+		case 3:
+			compressed = v
+		default:
+			unsupported = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if raw != nil {
+		return raw, nil
+	}
+
+	if compressed == nil {
+		if unsupported {
+			return nil, fmt.Errorf("OpenStreetMap PBF blob uses an unsupported compression")
+		}
+		return nil, nil
+	}
+
+	if rawSize < 0 || rawSize > osmMaxBlobSize {
+		return nil, invalidOSMError
+	}
+
+	zr, err := zlib.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	data := bytes.NewBuffer(make([]byte, 0, rawSize))
+	if _, err := io.Copy(data, io.LimitReader(zr, osmMaxBlobSize)); err != nil {
+		return nil, err
+	}
+
+	return data.Bytes(), nil
+}
+
+// Returns an error if the passed in header block requires features OSMReader doesn't support.
+func osmCheckHeader(data []byte) error {
+	return osmFields(data, func(num protowire.Number, typ protowire.Type, v []byte, n uint64) error {
+		if num == 4 && !osmSupportedFeatures[string(v)] {
+			return fmt.Errorf("OpenStreetMap PBF file requires unsupported feature %q", v)
+		}
+		return nil
+	})
+}
+
+// The string table and coordinate encoding of a primitive block.
+type osmBlock struct {
+	strings     [][]byte
+	granularity int64
+	latOffset   int64
+	lngOffset   int64
+}
+
+// Returns the latitude and longitude, in 1e-7 degrees, of the passed in encoded coordinates.
+func (b *osmBlock) location(lat, lng int64) [2]int32 {
+	return [2]int32{
+		int32((b.latOffset + b.granularity*lat) / 100),
+		int32((b.lngOffset + b.granularity*lng) / 100),
+	}
+}
+
+// Returns the tags with the passed in string table indexes.
+func (b *osmBlock) tags(keys, values []uint64) (map[string]string, error) {
+	if len(keys) != len(values) {
+		return nil, invalidOSMError
+	}
+
+	tags := make(map[string]string, len(keys))
+	for i := range keys {
+		if keys[i] >= uint64(len(b.strings)) || values[i] >= uint64(len(b.strings)) {
+			return nil, invalidOSMError
+		}
+		tags[string(b.strings[keys[i]])] = string(b.strings[values[i]])
+	}
+
+	return tags, nil
+}
+
+// Reads the nodes and ways of the passed in primitive block.
+func (r *OSMReader) readPrimitiveBlock(data []byte) error {
+	block := &osmBlock{granularity: 100}
+	var groups [][]byte
+	err := osmFields(data, func(num protowire.Number, typ protowire.Type, v []byte, n uint64) error {
+		switch num {
+		case 1:
+			return osmFields(v, func(num protowire.Number, typ protowire.Type, v []byte, n uint64) error {
+				if num == 1 {
+					block.strings = append(block.strings, v)
+				}
+				return nil
+			})
+		case 2:
+			groups = append(groups, v)
+		case 17:
+			block.granularity = int64(n)
+		case 19:
+			block.latOffset = int64(n)
+		case 20:
+			block.lngOffset = int64(n)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// The string table may follow the groups, so they are read once the block has been.
+	for _, group := range groups {
+		err := osmFields(group, func(num protowire.Number, typ protowire.Type, v []byte, n uint64) error {
+			switch num {
+			case 1:
+				return r.readNode(block, v)
+			case 2:
+				return r.readDenseNodes(block, v)
+			case 3:
+				if !r.SkipWays {
+					return r.readWay(block, v)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Reads a single node.
+func (r *OSMReader) readNode(block *osmBlock, data []byte) error {
+	var id, lat, lng int64
+	var keys, values []uint64
+	err := osmFields(data, func(num protowire.Number, typ protowire.Type, v []byte, n uint64) error {
+		var err error
+		switch num {
+		case 1:
+			id = protowire.DecodeZigZag(n)
+		case 2:
+			keys, err = osmPacked(keys, typ, v, n)
+		case 3:
+			values, err = osmPacked(values, typ, v, n)
+		case 8:
+			lat = protowire.DecodeZigZag(n)
+		case 9:
+			lng = protowire.DecodeZigZag(n)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	tags, err := block.tags(keys, values)
+	if err != nil {
+		return err
+	}
+
+	r.addNode(id, block.location(lat, lng), tags)
+	return nil
+}
+
+// Reads a group of densely packed nodes, whose ids and coordinates are delta coded
+// and whose tags are interleaved key and value indexes, each node's ending with a 0.
+func (r *OSMReader) readDenseNodes(block *osmBlock, data []byte) error {
+	var ids, lats, lngs, keysValues []uint64
+	err := osmFields(data, func(num protowire.Number, typ protowire.Type, v []byte, n uint64) error {
+		var err error
+		switch num {
+		case 1:
+			ids, err = osmPacked(ids, typ, v, n)
+		case 8:
+			lats, err = osmPacked(lats, typ, v, n)
+		case 9:
+			lngs, err = osmPacked(lngs, typ, v, n)
+		case 10:
+			keysValues, err = osmPacked(keysValues, typ, v, n)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(lats) != len(ids) || len(lngs) != len(ids) {
+		return invalidOSMError
+	}
+
+	var id, lat, lng int64
+	for i := range ids {
+		id += protowire.DecodeZigZag(ids[i])
+		lat += protowire.DecodeZigZag(lats[i])
+		lng += protowire.DecodeZigZag(lngs[i])
+
+		var keys, values []uint64
+		for len(keysValues) > 0 && keysValues[0] != 0 {
+			if len(keysValues) < 2 {
+				return invalidOSMError
+			}
+			keys = append(keys, keysValues[0])
+			values = append(values, keysValues[1])
+			keysValues = keysValues[2:]
+		}
+		if len(keysValues) > 0 {
+			keysValues = keysValues[1:]
+		}
+
+		tags, err := block.tags(keys, values)
+		if err != nil {
+			return err
+		}
+
+		r.addNode(id, block.location(lat, lng), tags)
+	}
+
+	return nil
+}
+
+// Remembers the passed in node's position, and queues it if it matches the filter.
+func (r *OSMReader) addNode(id int64, location [2]int32, tags map[string]string) {
+	if !r.SkipWays {
+		r.nodes[id] = location
+	}
+
+	if len(tags) == 0 || !r.filter(tags) {
+		return
+	}
+
+	f := NewFeature(osmPoint(location))
+	f.ID = fmt.Sprintf("node/%d", id)
+	for k, v := range tags {
+		f.Properties[k] = v
+	}
+
+	r.pending = append(r.pending, f)
+}
+
+// Reads a single way, and queues it if it matches the filter.
+func (r *OSMReader) readWay(block *osmBlock, data []byte) error {
+	var id int64
+	var keys, values, refs []uint64
+	err := osmFields(data, func(num protowire.Number, typ protowire.Type, v []byte, n uint64) error {
+		var err error
+		switch num {
+		case 1:
+			id = int64(n)
+		case 2:
+			keys, err = osmPacked(keys, typ, v, n)
+		case 3:
+			values, err = osmPacked(values, typ, v, n)
+		case 8:
+			refs, err = osmPacked(refs, typ, v, n)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	tags, err := block.tags(keys, values)
+	if err != nil {
+		return err
+	}
+
+	if len(tags) == 0 || !r.filter(tags) {
+		return nil
+	}
+
+	// Nodes outside of the extract are left out, as are the points of ways that end up with fewer than two.
+	var points []*Point
+	var ref, first int64
+	for i, delta := range refs {
+		ref += protowire.DecodeZigZag(delta)
+		if i == 0 {
+			first = ref
+		}
+
+		if location, ok := r.nodes[ref]; ok {
+			points = append(points, osmPoint(location))
+		}
+	}
+
+	if len(points) < 2 {
+		return nil
+	}
+
+	// Closed ways become polygons, which close themselves, when none of their nodes are missing.
+	var f *Feature
+	if len(refs) >= 4 && ref == first && len(points) == len(refs) {
+		f = NewFeature(NewPolygon(points[:len(points)-1]))
+	} else {
+		f = NewFeature(Line(points))
+	}
+
+	f.ID = fmt.Sprintf("way/%d", id)
+	for k, v := range tags {
+		f.Properties[k] = v
+	}
+
+	r.pending = append(r.pending, f)
+	return nil
+}
+
+// Returns the point at the passed in position, in 1e-7 degrees.
+func osmPoint(location [2]int32) *Point {
+	return NewPoint(float64(location[0])/1e7, float64(location[1])/1e7)
+}
+
+// Calls fn with the number, wire type and value of each field of the passed in message.
+// Length delimited values are passed in v, and all others in n.
+func osmFields(data []byte, fn func(num protowire.Number, typ protowire.Type, v []byte, n uint64) error) error {
+	for len(data) > 0 {
+		num, typ, length := protowire.ConsumeTag(data)
+		if length < 0 {
+			return invalidOSMError
+		}
+		data = data[length:]
+
+		var v []byte
+		var n uint64
+		switch typ {
+		case protowire.VarintType:
+			n, length = protowire.ConsumeVarint(data)
+		case protowire.Fixed32Type:
+			var n32 uint32
+			n32, length = protowire.ConsumeFixed32(data)
+			n = uint64(n32)
+		case protowire.Fixed64Type:
+			n, length = protowire.ConsumeFixed64(data)
+		case protowire.BytesType:
+			v, length = protowire.ConsumeBytes(data)
+		default:
+			length = protowire.ConsumeFieldValue(num, typ, data)
+		}
+		if length < 0 {
+			return invalidOSMError
+		}
+		data = data[length:]
+
+		if err := fn(num, typ, v, n); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Appends the varints of a repeated field to the passed in slice, whether they are
+// packed into the passed in bytes or are the single value n.
+func osmPacked(values []uint64, typ protowire.Type, v []byte, n uint64) ([]uint64, error) {
+	if typ != protowire.BytesType {
+		return append(values, n), nil
+	}
+
+	for len(v) > 0 {
+		n, length := protowire.ConsumeVarint(v)
+		if length < 0 {
+			return nil, invalidOSMError
+		}
+		values = append(values, n)
+		v = v[length:]
+	}
+
+	return values, nil
+}
@@ -0,0 +1,246 @@
+package geo
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"google.golang.org/protobuf/encoding/protowire"
+	"io"
+	"strings"
+	"testing"
+)
+
+// Appends a length delimited field to the passed in message.
+func appendOSMBytes(b []byte, num protowire.Number, v []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+// Appends a varint field to the passed in message.
+func appendOSMVarint(b []byte, num protowire.Number, v uint64) []byte {
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+// Appends a packed repeated field of varints to the passed in message.
+func appendOSMPacked(b []byte, num protowire.Number, values ...uint64) []byte {
+	var packed []byte
+	for _, v := range values {
+		packed = protowire.AppendVarint(packed, v)
+	}
+	return appendOSMBytes(b, num, packed)
+}
+
+// Appends a packed repeated field of delta coded sint64s to the passed in message.
+func appendOSMDeltas(b []byte, num protowire.Number, values ...int64) []byte {
+	deltas := make([]uint64, len(values))
+	var last int64
+	for i, v := range values {
+		deltas[i] = protowire.EncodeZigZag(v - last)
+		last = v
+	}
+	return appendOSMPacked(b, num, deltas...)
+}
+
+// Returns the passed in block framed as a PBF blob, compressed with zlib if asked to.
+func osmTestBlob(blobType string, block []byte, compress bool) []byte {
+	var blob []byte
+	if compress {
+		var buf bytes.Buffer
+		w := zlib.NewWriter(&buf)
+		w.Write(block)
+		w.Close()
+		blob = appendOSMVarint(blob, 2, uint64(len(block)))
+		blob = appendOSMBytes(blob, 3, buf.Bytes())
+	} else {
+		blob = appendOSMBytes(blob, 1, block)
+	}
+
+	header := appendOSMBytes(nil, 1, []byte(blobType))
+	header = appendOSMVarint(header, 3, uint64(len(blob)))
+
+	var framed []byte
+	framed = binary.BigEndian.AppendUint32(framed, uint32(len(header)))
+	framed = append(framed, header...)
+	return append(framed, blob...)
+}
+
+// Returns a PBF file of a few cafés and streets around Amsterdam's Dam square:
+// dense nodes in a compressed block, followed by a plain node and ways in an uncompressed one.
+func osmTestPBF(requiredFeatures ...string) []byte {
+	header := appendOSMBytes(nil, 4, []byte("OsmSchema-V0.6"))
+	header = appendOSMBytes(header, 4, []byte("DenseNodes"))
+	for _, feature := range requiredFeatures {
+		header = appendOSMBytes(header, 4, []byte(feature))
+	}
+
+	// Dense nodes, with a granularity of 1000 nanodegrees.
+	table := []string{"", "amenity", "cafe", "name", "Café de Jaren", "highway", "residential", "building", "yes", "bank"}
+	var stringTable []byte
+	for _, s := range table {
+		stringTable = appendOSMBytes(stringTable, 1, []byte(s))
+	}
+
+	var dense []byte
+	dense = appendOSMDeltas(dense, 1, 1, 2, 3, 4, 5)
+	dense = appendOSMDeltas(dense, 8, 52370000, 52371000, 52372000, 52372000, 52371000)
+	dense = appendOSMDeltas(dense, 9, 4890000, 4891000, 4892000, 4893000, 4893000)
+	dense = appendOSMPacked(dense, 10, 1, 2, 3, 4, 0, 0, 1, 9, 0, 0, 0)
+
+	var group []byte
+	group = appendOSMBytes(group, 2, dense)
+
+	var block []byte
+	block = appendOSMBytes(block, 1, stringTable)
+	block = appendOSMBytes(block, 2, group)
+	block = appendOSMVarint(block, 17, 1000)
+
+	// A plain node, a street, a closed building and a street running out of the extract.
+	// The string table follows the groups, as the format allows.
+	var node []byte
+	node = appendOSMVarint(node, 1, protowire.EncodeZigZag(6))
+	node = appendOSMPacked(node, 2, 1)
+	node = appendOSMPacked(node, 3, 2)
+	node = appendOSMVarint(node, 8, protowire.EncodeZigZag(523730000))
+	node = appendOSMVarint(node, 9, protowire.EncodeZigZag(48940000))
+
+	var street []byte
+	street = appendOSMVarint(street, 1, 100)
+	street = appendOSMPacked(street, 2, 5)
+	street = appendOSMPacked(street, 3, 6)
+	street = appendOSMDeltas(street, 8, 1, 2, 3)
+
+	var building []byte
+	building = appendOSMVarint(building, 1, 101)
+	building = appendOSMPacked(building, 2, 7)
+	building = appendOSMPacked(building, 3, 8)
+	building = appendOSMDeltas(building, 8, 2, 3, 4, 5, 2)
+
+	var outside []byte
+	outside = appendOSMVarint(outside, 1, 102)
+	outside = appendOSMPacked(outside, 2, 5)
+	outside = appendOSMPacked(outside, 3, 6)
+	outside = appendOSMDeltas(outside, 8, 5, 999)
+
+	var ways []byte
+	ways = appendOSMBytes(ways, 1, node)
+	ways = appendOSMBytes(ways, 3, street)
+	ways = appendOSMBytes(ways, 3, building)
+	ways = appendOSMBytes(ways, 3, outside)
+
+	var block2 []byte
+	block2 = appendOSMBytes(block2, 2, ways)
+	block2 = appendOSMBytes(block2, 1, stringTable)
+
+	var pbf []byte
+	pbf = append(pbf, osmTestBlob("OSMHeader", header, true)...)
+	pbf = append(pbf, osmTestBlob("OSMData", block, true)...)
+	pbf = append(pbf, osmTestBlob("OSMIndex", []byte("ignored"), false)...)
+	return append(pbf, osmTestBlob("OSMData", block2, false)...)
+}
+
+// Ensures that nodes matching a tag filter are read as points with their tags.
+func TestOSMReaderNodes(t *testing.T) {
+	fc, err := ReadOSMFeatures(bytes.NewReader(osmTestPBF()), OSMTagFilter("amenity", "cafe"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fc.Features) != 2 {
+		t.Fatalf("Expected 2 cafés, got %d", len(fc.Features))
+	}
+
+	f := fc.Features[0]
+	if f.ID != "node/1" {
+		t.Errorf("Expected node/1, got %v", f.ID)
+	}
+
+	if name, _ := f.PropertyString("name"); name != "Café de Jaren" {
+		t.Errorf("Expected Café de Jaren, got %q", name)
+	}
+
+	p := f.Geometry.(*Point)
+	if p.Lat() != 52.37 || p.Lng() != 4.89 {
+		t.Errorf("Expected 52.37, 4.89, got %v", p)
+	}
+
+	p = fc.Features[1].Geometry.(*Point)
+	if fc.Features[1].ID != "node/6" || p.Lat() != 52.373 || p.Lng() != 4.894 {
+		t.Errorf("Expected node/6 at 52.373, 4.894, got %v at %v", fc.Features[1].ID, p)
+	}
+}
+
+// Ensures that ways are read as lines, or polygons if closed,
+// and that ways left with too few points in the extract are skipped.
+func TestOSMReaderWays(t *testing.T) {
+	r := NewOSMReader(bytes.NewReader(osmTestPBF()), func(tags map[string]string) bool {
+		return tags["highway"] != "" || tags["building"] != ""
+	})
+
+	street, err := r.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	line, ok := street.Geometry.(Line)
+	if street.ID != "way/100" || !ok || len(line) != 3 || line[2].Lat() != 52.372 {
+		t.Errorf("Expected way/100 as a line of 3 points, got %v %v", street.ID, street.Geometry)
+	}
+
+	building, err := r.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	polygon, ok := building.Geometry.(*Polygon)
+	if building.ID != "way/101" || !ok || len(polygon.Points()) != 4 {
+		t.Fatalf("Expected way/101 as a polygon of 4 points, got %v %v", building.ID, building.Geometry)
+	}
+
+	if !polygon.Contains(NewPoint(52.3715, 4.8925)) {
+		t.Error("Expected the building to contain its center")
+	}
+
+	if f, err := r.Next(); err != io.EOF {
+		t.Errorf("Expected io.EOF, got %v, %v", f, err)
+	}
+}
+
+// Ensures that a reader skipping ways still returns the nodes matching its filter.
+func TestOSMReaderSkipWays(t *testing.T) {
+	r := NewOSMReader(bytes.NewReader(osmTestPBF()), nil)
+	r.SkipWays = true
+
+	var ids []string
+	for {
+		f, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, f.ID.(string))
+	}
+
+	if strings.Join(ids, ",") != "node/1,node/3,node/6" {
+		t.Errorf("Expected node/1,node/3,node/6, got %v", ids)
+	}
+
+	if len(r.nodes) != 0 {
+		t.Errorf("Expected no node positions to be kept, got %d", len(r.nodes))
+	}
+}
+
+// Ensures that truncated files and unsupported features are reported.
+func TestOSMReaderErrors(t *testing.T) {
+	pbf := osmTestPBF()
+	if _, err := ReadOSMFeatures(bytes.NewReader(pbf[:len(pbf)-10]), nil); err != invalidOSMError {
+		t.Errorf("Expected invalidOSMError, got %v", err)
+	}
+
+	_, err := ReadOSMFeatures(bytes.NewReader(osmTestPBF("HistoricalInformation")), nil)
+	if err == nil || !strings.Contains(err.Error(), "HistoricalInformation") {
+		t.Errorf("Expected an unsupported feature error, got %v", err)
+	}
+}
@@ -0,0 +1,230 @@
+package geo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// This contains the default base URL for the Overpass API interpreter.
+const DEFAULT_OVERPASS_URL = "https://overpass-api.de/api/interpreter"
+
+// How long the Overpass server may spend on a query unless told otherwise.
+const DEFAULT_OVERPASS_TIMEOUT = 25 * time.Second
+
+// An OverpassTag selects OpenStreetMap elements by one of their tags:
+// those that have Key at all if Values is empty, and otherwise those whose
+// Key has one of Values, e.g. {"amenity", []string{"cafe", "restaurant"}}.
+type OverpassTag struct {
+	Key    string
+	Values []string
+}
+
+// Returns the Overpass QL filter for the tag, e.g. ["amenity"="cafe"].
+func (t OverpassTag) filter() string {
+	switch len(t.Values) {
+	case 0:
+		return fmt.Sprintf("[%s]", overpassString(t.Key))
+	case 1:
+		return fmt.Sprintf("[%s=%s]", overpassString(t.Key), overpassString(t.Values[0]))
+	}
+
+	values := make([]string, len(t.Values))
+	for i, v := range t.Values {
+		values[i] = regexp.QuoteMeta(v)
+	}
+
+	return fmt.Sprintf("[%s~%s]", overpassString(t.Key), overpassString("^("+strings.Join(values, "|")+")$"))
+}
+
+// Returns the passed in string as an Overpass QL string literal.
+func overpassString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// Returns an Overpass QL query for the nodes and ways within the passed in bounds
+// that have all of the passed in tags, asking for their tags and geometry as JSON.
+// The server gives up on the query after the passed in timeout.
+func OverpassQL(bounds *Bounds, timeout time.Duration, tags ...OverpassTag) string {
+	var filters strings.Builder
+	for _, t := range tags {
+		filters.WriteString(t.filter())
+	}
+
+	bbox := fmt.Sprintf("(%g,%g,%g,%g)", bounds.sw.lat, bounds.sw.lng, bounds.ne.lat, bounds.ne.lng)
+	return fmt.Sprintf("[out:json][timeout:%d];nw%s%s;out geom;", int(timeout.Seconds()), filters.String(), bbox)
+}
+
+// This struct contains all the functionality of querying
+// OpenStreetMap data through the Overpass API.
+type OverpassClient struct {
+	// If set, every request is counted against this Quota
+	// and refused once the "overpass" daily budget is spent.
+	Quota *Quota
+
+	// If set, responses are cached here, keyed by their query.  Once the Quota refuses
+	// a request, queries that aren't cached fail with ErrCacheMiss, as with a CachedGeocoder.
+	Cache *GeocodeCache
+
+	// The client used to issue requests.  Defaults to a client sharing its connections
+	// with every provider, tuned with SetTransportConfig.
+	HTTPClient *http.Client
+
+	// The base URL of the API.  Defaults to DEFAULT_OVERPASS_URL.
+	BaseURL string
+
+	// How long the server may spend on each query.  Defaults to DEFAULT_OVERPASS_TIMEOUT.
+	Timeout time.Duration
+}
+
+// Creates and returns a pointer to a new OverpassClient configured by the passed in options.
+// The Overpass API makes use of WithHTTPClient, WithBaseURL and WithQuota.
+func NewOverpassClient(opts ...Option) *OverpassClient {
+	c := newGeocoderConfig(opts)
+	return &OverpassClient{
+		Quota:      c.quota,
+		HTTPClient: c.client("overpass"),
+		BaseURL:    c.baseURL,
+	}
+}
+
+// This struct contains selected fields from the Overpass API's JSON response.
+type overpassResponse struct {
+	Remark   string `json:"remark"`
+	Elements []struct {
+		Type     string            `json:"type"`
+		ID       int64             `json:"id"`
+		Lat      float64           `json:"lat"`
+		Lon      float64           `json:"lon"`
+		Tags     map[string]string `json:"tags"`
+		Nodes    []int64           `json:"nodes"`
+		Geometry []*struct {
+			Lat float64 `json:"lat"`
+			Lon float64 `json:"lon"`
+		} `json:"geometry"`
+	} `json:"elements"`
+}
+
+// Returns the features within the passed in bounds that have all of the passed in tags,
+// such as every café in a city.  Nodes become Points and ways become Lines,
+// or Polygons if they are closed.  Features are identified as "node/<id>" or "way/<id>"
+// and their properties are their tags, just as with an OSMReader.
+func (c *OverpassClient) Features(bounds *Bounds, tags ...OverpassTag) (*FeatureCollection, error) {
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = DEFAULT_OVERPASS_TIMEOUT
+	}
+
+	return c.Query(OverpassQL(bounds, timeout, tags...))
+}
+
+// Runs the passed in Overpass QL query, which must ask for JSON output
+// and should ask for geometry with "out geom", and returns the elements it finds as features.
+// Relations and elements without a position are skipped.
+func (c *OverpassClient) Query(ql string) (*FeatureCollection, error) {
+	res, err := c.request(ql)
+	if err != nil {
+		return nil, err
+	}
+
+	fc := &FeatureCollection{}
+	for _, e := range res.Elements {
+		var g Geometry
+		switch e.Type {
+		case "node":
+			g = NewPoint(e.Lat, e.Lon)
+		case "way":
+			var points []*Point
+			for _, p := range e.Geometry {
+				// Nodes outside of the query's bounds may be null.
+				if p != nil {
+					points = append(points, NewPoint(p.Lat, p.Lon))
+				}
+			}
+
+			closed := len(e.Nodes) >= 4 && e.Nodes[0] == e.Nodes[len(e.Nodes)-1]
+			if closed && len(points) == len(e.Nodes) {
+				g = NewPolygon(points[:len(points)-1])
+			} else if len(points) >= 2 {
+				g = Line(points)
+			}
+		}
+
+		if g == nil {
+			continue
+		}
+
+		f := NewFeature(g)
+		f.ID = fmt.Sprintf("%s/%d", e.Type, e.ID)
+		for k, v := range e.Tags {
+			f.Properties[k] = v
+		}
+
+		fc.Features = append(fc.Features, f)
+	}
+
+	return fc, nil
+}
+
+// Returns the response to the passed in query, from the cache if it is there.
+// Responses are only cached once they have been checked to be complete.
+func (c *OverpassClient) request(ql string) (*overpassResponse, error) {
+	key := "overpass:" + ql
+	if c.Cache != nil {
+		if data, ok := c.Cache.GetResponse(key); ok {
+			res := &overpassResponse{}
+			if json.Unmarshal(data, res) == nil {
+				return res, nil
+			}
+		}
+	}
+
+	if c.Quota != nil {
+		if err := c.Quota.Spend("overpass"); err != nil {
+			if c.Cache != nil && errors.Is(err, ErrBudgetExceeded) {
+				return nil, fmt.Errorf("%w: %w", ErrCacheMiss, err)
+			}
+			return nil, err
+		}
+	}
+
+	baseURL := c.BaseURL
+	if baseURL == "" {
+		baseURL = DEFAULT_OVERPASS_URL
+	}
+
+	req, err := http.NewRequest("POST", baseURL, strings.NewReader(url.Values{"data": {ql}}.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	data, err := httpDo(c.HTTPClient, req)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &overpassResponse{}
+	if err := json.Unmarshal(data, res); err != nil {
+		return nil, fmt.Errorf("Failed to parse Overpass response: %v", err)
+	}
+
+	// Overpass reports queries that time out or run out of memory
+	// in a remark alongside whatever elements it found in time.
+	if strings.Contains(res.Remark, "error") {
+		return nil, errors.New("Failed: " + res.Remark)
+	}
+
+	if c.Cache != nil {
+		if err := c.Cache.PutResponse(key, data); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,131 @@
+package geo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Ensures that queries select nodes and ways by all of their tags within the bounds,
+// escaping the tags' keys and values.
+func TestOverpassQL(t *testing.T) {
+	bounds := NewBounds(NewPoint(52.35, 4.85), NewPoint(52.4, 4.95))
+	ql := OverpassQL(bounds, 30*time.Second,
+		OverpassTag{Key: "amenity", Values: []string{"cafe", "ice.cream"}},
+		OverpassTag{Key: "wheelchair", Values: []string{"yes"}},
+		OverpassTag{Key: `name"`},
+	)
+
+	expected := `[out:json][timeout:30];nw["amenity"~"^(cafe|ice\\.cream)$"]["wheelchair"="yes"]["name\""](52.35,4.85,52.4,4.95);out geom;`
+	if ql != expected {
+		t.Errorf("Expected %s, got %s", expected, ql)
+	}
+}
+
+// Ensures that nodes and ways are returned as features, closed ways as polygons.
+func TestOverpassClientFeatures(t *testing.T) {
+	data, err := GetMockResponse("test/data/overpass_cafes.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.PostFormValue("data"))
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	c := NewOverpassClient(WithBaseURL(server.URL))
+	fc, err := c.Features(NewBounds(NewPoint(52.35, 4.85), NewPoint(52.4, 4.95)), OverpassTag{Key: "amenity", Values: []string{"cafe"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(queries) != 1 || queries[0] != `[out:json][timeout:25];nw["amenity"="cafe"](52.35,4.85,52.4,4.95);out geom;` {
+		t.Errorf("Unexpected queries: %v", queries)
+	}
+
+	if len(fc.Features) != 3 {
+		t.Fatalf("Expected 3 features, got %d", len(fc.Features))
+	}
+
+	if p, ok := fc.Features[0].Geometry.(*Point); !ok || fc.Features[0].ID != "node/1234567" || p.Lat() != 52.3699 {
+		t.Errorf("Unexpected node feature: %v %v", fc.Features[0].ID, fc.Features[0].Geometry)
+	}
+
+	if name, _ := fc.Features[0].PropertyString("name"); name != "Café de Jaren" {
+		t.Errorf("Expected Café de Jaren, got %q", name)
+	}
+
+	if polygon, ok := fc.Features[1].Geometry.(*Polygon); !ok || len(polygon.Points()) != 4 {
+		t.Errorf("Expected a closed way as a polygon of 4 points, got %v", fc.Features[1].Geometry)
+	}
+
+	if line, ok := fc.Features[2].Geometry.(Line); !ok || len(line) != 2 || fc.Features[2].ID != "way/3456789" {
+		t.Errorf("Expected an open way as a line of its 2 known points, got %v", fc.Features[2].Geometry)
+	}
+}
+
+// Ensures that cached responses are served without spending the quota,
+// and that uncached queries fail with ErrCacheMiss once it is spent.
+func TestOverpassClientCacheAndQuota(t *testing.T) {
+	data, err := GetMockResponse("test/data/overpass_cafes.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	q := NewQuota()
+	q.SetDailyBudget("overpass", 1)
+
+	c := NewOverpassClient(WithBaseURL(server.URL), WithQuota(q))
+	c.Cache = openTestCache(t, 0, 0)
+	amsterdam := NewBounds(NewPoint(52.35, 4.85), NewPoint(52.4, 4.95))
+
+	for i := 0; i < 3; i++ {
+		fc, err := c.Features(amsterdam, OverpassTag{Key: "amenity", Values: []string{"cafe"}})
+		if err != nil || len(fc.Features) != 3 {
+			t.Fatalf("Expected 3 features, got %v (%v)", fc, err)
+		}
+	}
+
+	if requests != 1 || q.Usage("overpass") != 1 {
+		t.Errorf("Expected 1 request, got %d (usage %d)", requests, q.Usage("overpass"))
+	}
+
+	_, err = c.Features(amsterdam, OverpassTag{Key: "amenity", Values: []string{"bar"}})
+	if !errors.Is(err, ErrCacheMiss) || !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Expected a cache miss caused by the budget, got %v", err)
+	}
+}
+
+// Ensures that queries the server gave up on are reported and not cached.
+func TestOverpassClientRemark(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"elements": [], "remark": "runtime error: Query timed out in \"query\" at line 1 after 26 seconds."}`))
+	}))
+	defer server.Close()
+
+	c := NewOverpassClient(WithBaseURL(server.URL))
+	c.Cache = openTestCache(t, 0, 0)
+	for i := 0; i < 2; i++ {
+		if _, err := c.Query(`[out:json];node["amenity"];out;`); err == nil {
+			t.Error("Expected an error for a query that timed out")
+		}
+	}
+
+	if requests != 2 {
+		t.Errorf("Expected the failed response not to be cached, got %d requests", requests)
+	}
+}
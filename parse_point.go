@@ -0,0 +1,343 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// This is the error that consumers can compare against with errors.Is
+// when ParsePoint is passed a string that could name more than one point.
+var ErrAmbiguousPoint = errors.New("ambiguous point")
+
+// This is the error that consumers receive when ParsePoint
+// doesn't recognize the passed in string in any format.
+var unrecognizedPointError = errors.New("unrecognized point format")
+
+// Describes the readings of a string that could name more than one point,
+// such as "10 20", which could be "lat lng" or "lng lat".
+// Matches ErrAmbiguousPoint when used with errors.Is.
+type AmbiguousPointError struct {
+	Input string
+
+	// The formats the string could be read in, e.g. "lat lng", and the points each names.
+	Formats []string
+	Points  []*Point
+}
+
+func (e *AmbiguousPointError) Error() string {
+	return fmt.Sprintf("ambiguous point %q: could be %s", e.Input, strings.Join(e.Formats, " or "))
+}
+
+// Allows errors.Is(err, ErrAmbiguousPoint) to succeed.
+func (e *AmbiguousPointError) Is(target error) bool {
+	return target == ErrAmbiguousPoint
+}
+
+// A reading of a string as a point, in one of the formats ParsePoint accepts.
+type pointReading struct {
+	format string
+	point  *Point
+	bounds *Bounds
+}
+
+// Matches decimal numbers, such as "-122.08" or "+37".
+var decimalPattern = regexp.MustCompile(`^[-+]?(\d+(\.\d*)?|\.\d+)$`)
+
+// Matches UTM coordinates, such as "17T 630084 4833438" or "33N 500000mE 4649776mN".
+var utmPattern = regexp.MustCompile(`^(\d{1,2})\s*([C-HJ-NP-X])\s+(\d+(?:\.\d+)?)\s*(?:ME)?\s+(\d+(?:\.\d+)?)\s*(?:MN)?$`)
+
+// Matches MGRS coordinates, such as "17TPJ3008433438" or "18S UJ 23487 06483".
+var mgrsPattern = regexp.MustCompile(`^(\d{1,2})\s*([C-HJ-NP-X])\s*([A-HJ-NP-Z])([A-HJ-NP-V])\s*(\d{0,10})\s*(\d{0,5})$`)
+
+// Matches the numbers of a degrees, minutes and seconds coordinate.
+var dmsNumberPattern = regexp.MustCompile(`\d+(?:\.\d+)?`)
+
+// Returns the point named by the passed in string, which may be in any of these formats:
+//
+//   - decimal degrees separated by a comma, as "lat,lng": "37.42,-122.08"
+//   - decimal degrees separated by whitespace, in either order: "-122.08 37.42"
+//   - degrees, minutes and seconds with hemispheres: 43°38'33.24"N 79°23'13.7"W, 37.42N 122.08W
+//   - UTM, with a latitude band or hemisphere letter: "17T 630084 4833438"
+//   - MGRS, to any precision: "17TPJ3008433438" or "17T PJ 30084 33438"
+//   - geohashes mixing letters and digits: "9q9hvu"
+//   - full plus codes: "849VCWC8+R9"
+//
+// Geohashes and plus codes name the center of their cell.  Returns an *AmbiguousPointError
+// if the string could be read in more than one format as different points, such as "10 20",
+// or "33S 500000 4000000", which could be in UTM band S or the southern hemisphere.
+// If strict validation is on, points that fail Validate, such as "0,0", are refused with a *CoordinateError.
+func ParsePoint(s string) (*Point, error) {
+	reading, err := readPoint(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkStrict(reading.point); err != nil {
+		return nil, err
+	}
+
+	return reading.point, nil
+}
+
+// Returns the single reading of the passed in string as a point.
+func readPoint(s string) (*pointReading, error) {
+	s = strings.TrimSpace(s)
+	readings := readDecimalPoint(s)
+	for _, read := range []func(string) []*pointReading{readDMSPoint, readUTMPoint, readMGRSPoint, readGeohashPoint, readPlusCodePoint} {
+		readings = append(readings, read(s)...)
+	}
+
+	switch len(readings) {
+	case 0:
+		return nil, fmt.Errorf("%w: %q", unrecognizedPointError, s)
+	case 1:
+		return readings[0], nil
+	}
+
+	err := &AmbiguousPointError{Input: s}
+	for _, r := range readings {
+		err.Formats = append(err.Formats, r.format)
+		err.Points = append(err.Points, r.point)
+	}
+
+	return nil, err
+}
+
+// Reads a pair of decimal numbers, as "lat,lng" if separated by a comma,
+// and as either "lat lng" or "lng lat" if only whitespace separates them.
+func readDecimalPoint(s string) []*pointReading {
+	if first, second, ok := strings.Cut(s, ","); ok {
+		first, second = strings.TrimSpace(first), strings.TrimSpace(second)
+		if !decimalPattern.MatchString(first) || !decimalPattern.MatchString(second) {
+			return nil
+		}
+
+		if p, err := parseLatLng(first, second); err == nil {
+			return []*pointReading{{format: "lat,lng", point: p}}
+		}
+		return nil
+	}
+
+	fields := strings.Fields(s)
+	if len(fields) != 2 || !decimalPattern.MatchString(fields[0]) || !decimalPattern.MatchString(fields[1]) {
+		return nil
+	}
+
+	var readings []*pointReading
+	if p, err := parseLatLng(fields[0], fields[1]); err == nil {
+		readings = append(readings, &pointReading{format: "lat lng", point: p})
+	}
+
+	// The same number twice names the same point in either order.
+	if p, err := parseLatLng(fields[1], fields[0]); err == nil && fields[0] != fields[1] {
+		readings = append(readings, &pointReading{format: "lng lat", point: p})
+	}
+
+	return readings
+}
+
+// Reads degrees, minutes and seconds, or decimal degrees, each marked with its hemisphere
+// before or after it, e.g. 43°38'33.24"N 79°23'13.7"W or N43 38.554 W79 23.228.
+// Without hemispheres, signed coordinates with degree signs are read as "lat,lng".
+func readDMSPoint(s string) []*pointReading {
+	upper := strings.ToUpper(s)
+	var letters []int
+	for i := 0; i < len(upper); i++ {
+		switch c := upper[i]; {
+		case strings.IndexByte("NSEW", c) >= 0:
+			letters = append(letters, i)
+		case c >= 'A' && c <= 'Z':
+			return nil
+		}
+	}
+
+	var first, second string
+	switch {
+	case len(letters) == 2 && letters[0] == 0:
+		first, second = upper[:letters[1]], upper[letters[1]:]
+	case len(letters) == 2 && letters[1] == len(upper)-1:
+		first, second = upper[:letters[0]+1], upper[letters[0]+1:]
+	case len(letters) == 0 && strings.ContainsAny(s, "°º"):
+		var ok bool
+		if first, second, ok = strings.Cut(upper, ","); !ok {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	lat, latHemisphere, ok := parseDMS(first)
+	if !ok {
+		return nil
+	}
+
+	lng, lngHemisphere, ok := parseDMS(second)
+	if !ok {
+		return nil
+	}
+
+	// Hemispheres may come in either order, but must name one latitude and one longitude.
+	if latHemisphere == 'E' || latHemisphere == 'W' {
+		lat, lng, latHemisphere, lngHemisphere = lng, lat, lngHemisphere, latHemisphere
+	}
+
+	if latHemisphere == 'E' || latHemisphere == 'W' || lngHemisphere == 'N' || lngHemisphere == 'S' {
+		return nil
+	}
+
+	if math.Abs(lat) > 90 || math.Abs(lng) > 180 {
+		return nil
+	}
+
+	return []*pointReading{{format: "degrees, minutes and seconds", point: NewPoint(lat, lng)}}
+}
+
+// Returns the signed degrees of the passed in degrees, minutes and seconds coordinate,
+// the hemisphere letter it was marked with, if any, and whether or not it is one.
+func parseDMS(s string) (float64, byte, bool) {
+	s = strings.TrimSpace(strings.Trim(strings.TrimSpace(s), ","))
+	var hemisphere byte
+	if s != "" && strings.IndexByte("NSEW", s[0]) >= 0 {
+		hemisphere, s = s[0], s[1:]
+	} else if s != "" && strings.IndexByte("NSEW", s[len(s)-1]) >= 0 {
+		hemisphere, s = s[len(s)-1], s[:len(s)-1]
+	}
+
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	if negative && hemisphere != 0 {
+		return 0, 0, false
+	}
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+
+	// Everything but the numbers must be separators or degree, minute and second marks.
+	if strings.Trim(dmsNumberPattern.ReplaceAllString(s, ""), " \t°º'′\"″") != "" {
+		return 0, 0, false
+	}
+
+	numbers := dmsNumberPattern.FindAllString(s, -1)
+	if len(numbers) == 0 || len(numbers) > 3 {
+		return 0, 0, false
+	}
+
+	degrees := 0.0
+	for i, n := range numbers {
+		value, err := strconv.ParseFloat(n, 64)
+		if err != nil || i > 0 && value >= 60 || i < len(numbers)-1 && strings.Contains(n, ".") {
+			return 0, 0, false
+		}
+
+		degrees += value / math.Pow(60, float64(i))
+	}
+
+	if negative || hemisphere == 'S' || hemisphere == 'W' {
+		degrees = -degrees
+	}
+
+	return degrees, hemisphere, true
+}
+
+// Reads UTM coordinates: a zone, a latitude band or hemisphere letter, an easting and a northing.
+// "S" may be a band in the northern hemisphere or stand for the southern hemisphere, so is
+// read both ways if the northing lies in band S; "N", as a band or hemisphere, is always north.
+func readUTMPoint(s string) []*pointReading {
+	m := utmPattern.FindStringSubmatch(strings.ToUpper(s))
+	if m == nil {
+		return nil
+	}
+
+	zone, _ := strconv.Atoi(m[1])
+	easting, _ := strconv.ParseFloat(m[3], 64)
+	northing, _ := strconv.ParseFloat(m[4], 64)
+	if zone < 1 || zone > 60 || easting < 100000 || easting > 900000 || northing > utmSouthernFalseNorthing {
+		return nil
+	}
+
+	band := m[2][0]
+	var readings []*pointReading
+	if band >= 'N' {
+		lat, lng := utmToLatLng(zone, true, easting, northing)
+		if band != 'S' || lat >= 32 && lat < 40 {
+			readings = append(readings, &pointReading{format: "UTM", point: NewPoint(lat, lng)})
+		}
+	}
+
+	if band < 'N' || band == 'S' {
+		lat, lng := utmToLatLng(zone, false, easting, northing)
+		format := "UTM"
+		if band == 'S' {
+			format = "UTM southern hemisphere"
+			if len(readings) > 0 {
+				readings[0].format = "UTM band S"
+			}
+		}
+		readings = append(readings, &pointReading{format: format, point: NewPoint(lat, lng)})
+	}
+
+	return readings
+}
+
+// Reads MGRS coordinates: a zone, a latitude band, the letters of a 100km square
+// and an easting and northing within it of equal precision.
+func readMGRSPoint(s string) []*pointReading {
+	m := mgrsPattern.FindStringSubmatch(strings.ToUpper(s))
+	if m == nil {
+		return nil
+	}
+
+	digits := m[5] + m[6]
+	if m[6] != "" && len(m[5]) != len(m[6]) || len(digits)%2 != 0 {
+		return nil
+	}
+
+	zone, _ := strconv.Atoi(m[1])
+	if zone < 1 || zone > 60 {
+		return nil
+	}
+
+	easting, northing, err := mgrsSquareToUTM(zone, m[2][0], m[3][0], m[4][0])
+	if err != nil {
+		return nil
+	}
+
+	// Place the point in the middle of the square the digits name.
+	precision := len(digits) / 2
+	size := math.Pow(10, float64(5-precision))
+	if precision > 0 {
+		e, _ := strconv.Atoi(digits[:precision])
+		n, _ := strconv.Atoi(digits[precision:])
+		easting += float64(e) * size
+		northing += float64(n) * size
+	}
+
+	lat, lng := utmToLatLng(zone, m[2][0] >= 'N', easting+size/2, northing+size/2)
+	return []*pointReading{{format: "MGRS", point: NewPoint(lat, lng)}}
+}
+
+// Reads a geohash, if the passed in string looks like one.
+func readGeohashPoint(s string) []*pointReading {
+	if !looksLikeGeohash(s) {
+		return nil
+	}
+
+	bounds, err := GeohashBounds(strings.ToLower(s))
+	if err != nil {
+		return nil
+	}
+
+	return []*pointReading{{format: "geohash", point: boundsCenter(bounds), bounds: bounds}}
+}
+
+// Reads a full plus code.
+func readPlusCodePoint(s string) []*pointReading {
+	bounds, err := PlusCodeBounds(s)
+	if err != nil {
+		return nil
+	}
+
+	return []*pointReading{{format: "plus code", point: boundsCenter(bounds), bounds: bounds}}
+}
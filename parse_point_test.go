@@ -0,0 +1,113 @@
+package geo
+
+import (
+	"errors"
+	"testing"
+)
+
+// Ensures that points are parsed in each of the formats ParsePoint accepts.
+func TestParsePoint(t *testing.T) {
+	// The CN Tower, written in each format.
+	tests := []struct {
+		input    string
+		lat, lng float64
+	}{
+		{"43.6426,-79.3871", 43.6426, -79.3871},
+		{" 43.6426 , -79.3871 ", 43.6426, -79.3871},
+		{`43°38′33.24″N 79°23′13.7″W`, 43.6426, -79.3871},
+		{`43°38'33.24"N, 79°23'13.7"W`, 43.6426, -79.3871},
+		{`79°23'13.7"W 43°38'33.24"N`, 43.6426, -79.3871},
+		{"N43 38.556 W79 23.228", 43.6426, -79.3871},
+		{"43.6426n 79.3871w", 43.6426, -79.3871},
+		{"43.6426°, -79.3871°", 43.6426, -79.3871},
+		{"17T 630084 4833438", 43.6426, -79.3871},
+		{"17N 630084mE 4833438mN", 43.6426, -79.3871},
+		{"17TPJ3008433438", 43.6426, -79.3871},
+		{"17T PJ 30084 33438", 43.6426, -79.3871},
+		{"17tpj30083343", 43.6426, -79.3871},
+		{"dpz838bhd", 43.6426, -79.3871},
+		{"87M2JJV7+25", 43.6426, -79.3871},
+		// The Sydney Opera House, in the southern hemisphere.
+		{`33°51′24.5″S 151°12′55.1″E`, -33.8568, 151.2153},
+		{"-33.8568 151.2153", -33.8568, 151.2153},
+		{"151.2153 -33.8568", -33.8568, 151.2153},
+		{"56H 334901 6252289", -33.8568, 151.2153},
+		{"56HLH3490152289", -33.8568, 151.2153},
+	}
+
+	for _, test := range tests {
+		p, err := ParsePoint(test.input)
+		if err != nil {
+			t.Errorf("%s: %v", test.input, err)
+			continue
+		}
+
+		if d := p.GreatCircleDistance(NewPoint(test.lat, test.lng)); d > 0.01 {
+			t.Errorf("Expected %s to be parsed as %f, %f, got %v, %f km away", test.input, test.lat, test.lng, p, d)
+		}
+	}
+}
+
+// Ensures that strings that could name more than one point are reported as ambiguous,
+// along with the formats they could be in.
+func TestParsePointAmbiguous(t *testing.T) {
+	tests := []struct {
+		input   string
+		formats []string
+	}{
+		{"10 20", []string{"lat lng", "lng lat"}},
+		{"33S 500000 4000000", []string{"UTM band S", "UTM southern hemisphere"}},
+	}
+
+	for _, test := range tests {
+		_, err := ParsePoint(test.input)
+		if !errors.Is(err, ErrAmbiguousPoint) {
+			t.Errorf("Expected %s to be ambiguous, got %v", test.input, err)
+			continue
+		}
+
+		var ambiguous *AmbiguousPointError
+		if !errors.As(err, &ambiguous) || len(ambiguous.Formats) != len(test.formats) || len(ambiguous.Points) != len(test.formats) {
+			t.Errorf("Expected %s to be ambiguous between %v, got %v", test.input, test.formats, err)
+			continue
+		}
+
+		for i, format := range test.formats {
+			if ambiguous.Formats[i] != format {
+				t.Errorf("Expected %s to be ambiguous between %v, got %v", test.input, test.formats, ambiguous.Formats)
+			}
+		}
+	}
+
+	// The same number twice, and a northing outside band S, aren't ambiguous.
+	for _, input := range []string{"45 45", "33S 500000 6000000"} {
+		if _, err := ParsePoint(input); err != nil {
+			t.Errorf("Expected %s not to be ambiguous, got %v", input, err)
+		}
+	}
+}
+
+// Ensures that strings that aren't points in any format are refused.
+func TestParsePointInvalid(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"cheese",
+		"91,0",
+		"0,181",
+		"10,20,30",
+		"1600 Amphitheatre Parkway",
+		`43°61'N 79°W`,
+		`43°38'N 79°23'N`,
+		"43.6426N",
+		"61X 630084 4833438",
+		"17T 630084",
+		"17TPJ300843343",
+		"17TPJ30084 3343",
+		"17TIJ30083343",
+		"CWC8+R9",
+	} {
+		if p, err := ParsePoint(input); err == nil {
+			t.Errorf("Expected %q to be refused, got %v", input, p)
+		}
+	}
+}
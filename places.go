@@ -0,0 +1,432 @@
+package geo
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// This contains the default base URL for the Google Places Nearby Search API.
+const DEFAULT_GOOGLE_PLACES_URL = "https://maps.googleapis.com/maps/api/place/nearbysearch/json"
+
+// This contains the default base URL for the Foursquare Places search API.
+const DEFAULT_FOURSQUARE_URL = "https://api.foursquare.com/v3/places/search"
+
+// The largest radius, in kilometers, that Google and Foursquare search within.
+const MAX_PLACE_SEARCH_RADIUS = 50.0
+
+// Categories of place that every PlaceSearcher understands.  Other categories
+// are passed to the provider as they are, so Google types such as "book_store",
+// or Overpass tags such as "shop=books", can be used with their own provider.
+const (
+	PlaceCafe       = "cafe"
+	PlaceRestaurant = "restaurant"
+	PlaceBar        = "bar"
+	PlaceHotel      = "hotel"
+	PlaceFuel       = "fuel"
+	PlacePharmacy   = "pharmacy"
+	PlaceATM        = "atm"
+	PlaceParking    = "parking"
+)
+
+// The Google place types of each category, where they differ from the category.
+var googlePlaceTypes = map[string]string{
+	PlaceHotel: "lodging",
+	PlaceFuel:  "gas_station",
+}
+
+// The Foursquare category ids of each category.
+// Categories without one are searched for by name.
+var foursquareCategories = map[string]string{
+	PlaceCafe:       "13032",
+	PlaceRestaurant: "13065",
+	PlaceBar:        "13003",
+	PlaceHotel:      "19014",
+}
+
+// The OpenStreetMap tags of each category, where they aren't amenity=<category>.
+var overpassPlaceTags = map[string]OverpassTag{
+	PlaceBar:   {Key: "amenity", Values: []string{"bar", "pub"}},
+	PlaceHotel: {Key: "tourism", Values: []string{"hotel"}},
+}
+
+// A Place is a point of interest, such as a café or a hotel, found by a PlaceSearcher.
+type Place struct {
+	// The provider's identifier for the place, e.g. a Google place_id or "node/123" for OpenStreetMap.
+	ID string
+
+	Name  string
+	Point *Point
+
+	// The provider's categories for the place, e.g. "cafe" or "Coffee Shop".
+	Categories []string
+
+	// The place's address, as much of it as the provider gave.
+	Address string
+
+	// The place's rating out of 5, or 0 if it has none.
+	Rating float64
+
+	// The distance, in kilometers, from the point that was searched around.
+	Distance float64
+
+	// The name of the provider that found the place, e.g. "google".
+	Provider string
+}
+
+// Returns the place as a point Feature, with its ID and its other fields as properties:
+// "name", "categories", "address", "rating", "distance" and "provider".
+func (pl *Place) Feature() *Feature {
+	f := NewFeature(pl.Point)
+	f.ID = pl.ID
+	f.Set("name", pl.Name).Set("categories", pl.Categories).Set("distance", pl.Distance).Set("provider", pl.Provider)
+	if pl.Address != "" {
+		f.Set("address", pl.Address)
+	}
+
+	if pl.Rating > 0 {
+		f.Set("rating", pl.Rating)
+	}
+
+	return f
+}
+
+// Returns the passed in places as a FeatureCollection, in order.
+func PlacesToFeatures(places []*Place) *FeatureCollection {
+	fc := &FeatureCollection{Features: make([]*Feature, len(places))}
+	for i, pl := range places {
+		fc.Features[i] = pl.Feature()
+	}
+
+	return fc
+}
+
+// A PlaceSearcher finds the places of the passed in category within the passed in radius,
+// in kilometers, of a point, nearest first.
+type PlaceSearcher interface {
+	PlaceSearch(p *Point, radius float64, category string) ([]*Place, error)
+}
+
+// Fills in the distance of each of the passed in places from the passed in point, drops those
+// beyond the passed in radius and returns the rest, nearest first.  Places equally far away
+// keep the provider's order.
+func rankPlaces(p *Point, radius float64, places []*Place) []*Place {
+	nearby := places[:0]
+	for _, pl := range places {
+		pl.Distance = p.GreatCircleDistance(pl.Point)
+		if pl.Distance <= radius {
+			nearby = append(nearby, pl)
+		}
+	}
+
+	sort.SliceStable(nearby, func(i, j int) bool { return nearby[i].Distance < nearby[j].Distance })
+	return nearby
+}
+
+// Returns the passed in radius, in kilometers, in whole meters no larger than the providers allow.
+func placeSearchMeters(radius float64) string {
+	return strconv.Itoa(int(math.Round(math.Min(radius, MAX_PLACE_SEARCH_RADIUS) * 1000)))
+}
+
+// This struct contains all the functionality
+// of interacting with the Google Places Nearby Search API.
+type GooglePlacesProvider struct {
+	// If set, every request is counted against this Quota
+	// and refused once the "google-places" daily budget is spent.
+	Quota *Quota
+
+	// The client used to issue requests.  Defaults to a client sharing its connections
+	// with every provider, tuned with SetTransportConfig.
+	HTTPClient *http.Client
+
+	// The URL of the API.  Defaults to DEFAULT_GOOGLE_PLACES_URL.
+	BaseURL string
+
+	apiKey   string
+	language string
+}
+
+// Creates and returns a pointer to a new GooglePlacesProvider configured by the passed in options.
+// Google Places makes use of WithAPIKey, WithLanguage, WithHTTPClient, WithBaseURL and WithQuota.
+func NewGooglePlacesProvider(opts ...Option) *GooglePlacesProvider {
+	c := newGeocoderConfig(opts)
+	return &GooglePlacesProvider{
+		Quota:      c.quota,
+		HTTPClient: c.client("google-places"),
+		BaseURL:    c.baseURL,
+		apiKey:     c.apiKey,
+		language:   c.language,
+	}
+}
+
+// This struct contains selected fields from Google's Nearby Search response.
+type googlePlacesResponse struct {
+	Status        string `json:"status"`
+	Error_message string `json:"error_message"`
+	Results       []struct {
+		PlaceID  string `json:"place_id"`
+		Name     string `json:"name"`
+		Geometry struct {
+			Location struct {
+				Lat float64 `json:"lat"`
+				Lng float64 `json:"lng"`
+			} `json:"location"`
+		} `json:"geometry"`
+		Types    []string `json:"types"`
+		Vicinity string   `json:"vicinity"`
+		Rating   float64  `json:"rating"`
+	} `json:"results"`
+}
+
+// Returns the places of the passed in category, a Google place type or one of
+// the Place categories, within the passed in radius of the passed in point.
+// Implements the PlaceSearcher Interface.
+func (g *GooglePlacesProvider) PlaceSearch(p *Point, radius float64, category string) ([]*Place, error) {
+	if g.Quota != nil {
+		if err := g.Quota.Spend("google-places"); err != nil {
+			return nil, err
+		}
+	}
+
+	placeType := category
+	if t, ok := googlePlaceTypes[category]; ok {
+		placeType = t
+	}
+
+	params := url.Values{
+		"location": {fmt.Sprintf("%f,%f", p.lat, p.lng)},
+		"radius":   {placeSearchMeters(radius)},
+		"type":     {placeType},
+		"key":      {g.apiKey},
+	}
+	if g.language != "" {
+		params.Set("language", g.language)
+	}
+
+	base := g.BaseURL
+	if base == "" {
+		base = DEFAULT_GOOGLE_PLACES_URL
+	}
+
+	data, err := httpGet(g.HTTPClient, base+"?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	res := &googlePlacesResponse{}
+	if err := json.Unmarshal(data, res); err != nil {
+		return nil, err
+	}
+
+	if res.Status != "OK" && res.Status != "ZERO_RESULTS" {
+		return nil, googleStatusError("google places", res.Status, res.Error_message)
+	}
+
+	places := make([]*Place, len(res.Results))
+	for i, r := range res.Results {
+		places[i] = &Place{
+			ID:         r.PlaceID,
+			Name:       r.Name,
+			Point:      NewPoint(r.Geometry.Location.Lat, r.Geometry.Location.Lng),
+			Categories: r.Types,
+			Address:    r.Vicinity,
+			Rating:     r.Rating,
+			Provider:   "google",
+		}
+	}
+
+	return rankPlaces(p, radius, places), nil
+}
+
+// This struct contains all the functionality
+// of interacting with the Foursquare Places API.
+type FoursquareProvider struct {
+	// If set, every request is counted against this Quota
+	// and refused once the "foursquare" daily budget is spent.
+	Quota *Quota
+
+	// The client used to issue requests.  Defaults to a client sharing its connections
+	// with every provider, tuned with SetTransportConfig.
+	HTTPClient *http.Client
+
+	// The URL of the API.  Defaults to DEFAULT_FOURSQUARE_URL.
+	BaseURL string
+
+	apiKey   string
+	language string
+}
+
+// Creates and returns a pointer to a new FoursquareProvider configured by the passed in options.
+// Foursquare makes use of WithAPIKey, WithLanguage, WithHTTPClient, WithBaseURL and WithQuota.
+func NewFoursquareProvider(opts ...Option) *FoursquareProvider {
+	c := newGeocoderConfig(opts)
+	return &FoursquareProvider{
+		Quota:      c.quota,
+		HTTPClient: c.client("foursquare"),
+		BaseURL:    c.baseURL,
+		apiKey:     c.apiKey,
+		language:   c.language,
+	}
+}
+
+// This struct contains selected fields from Foursquare's place search response.
+type foursquareResponse struct {
+	Message string `json:"message"`
+	Results []struct {
+		FsqID    string `json:"fsq_id"`
+		Name     string `json:"name"`
+		Geocodes struct {
+			Main struct {
+				Latitude  float64 `json:"latitude"`
+				Longitude float64 `json:"longitude"`
+			} `json:"main"`
+		} `json:"geocodes"`
+		Categories []struct {
+			Name string `json:"name"`
+		} `json:"categories"`
+		Location struct {
+			FormattedAddress string `json:"formatted_address"`
+		} `json:"location"`
+		Rating float64 `json:"rating"`
+	} `json:"results"`
+}
+
+// Returns the places of the passed in category, one of the Place categories or
+// a Foursquare category id, within the passed in radius of the passed in point.
+// Other categories are searched for by name.  Implements the PlaceSearcher Interface.
+func (f *FoursquareProvider) PlaceSearch(p *Point, radius float64, category string) ([]*Place, error) {
+	if f.Quota != nil {
+		if err := f.Quota.Spend("foursquare"); err != nil {
+			return nil, err
+		}
+	}
+
+	params := url.Values{
+		"ll":     {fmt.Sprintf("%f,%f", p.lat, p.lng)},
+		"radius": {placeSearchMeters(radius)},
+		"fields": {"fsq_id,name,geocodes,categories,location,rating"},
+		"limit":  {"50"},
+	}
+
+	if id, ok := foursquareCategories[category]; ok {
+		params.Set("categories", id)
+	} else if _, err := strconv.Atoi(category); err == nil {
+		params.Set("categories", category)
+	} else {
+		params.Set("query", category)
+	}
+
+	base := f.BaseURL
+	if base == "" {
+		base = DEFAULT_FOURSQUARE_URL
+	}
+
+	req, err := http.NewRequest("GET", base+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", f.apiKey)
+	if f.language != "" {
+		req.Header.Set("Accept-Language", f.language)
+	}
+
+	data, err := httpDo(f.HTTPClient, req)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &foursquareResponse{}
+	if err := json.Unmarshal(data, res); err != nil {
+		return nil, err
+	}
+
+	if res.Message != "" {
+		return nil, fmt.Errorf("foursquare: %s", res.Message)
+	}
+
+	places := make([]*Place, len(res.Results))
+	for i, r := range res.Results {
+		pl := &Place{
+			ID:       r.FsqID,
+			Name:     r.Name,
+			Point:    NewPoint(r.Geocodes.Main.Latitude, r.Geocodes.Main.Longitude),
+			Address:  r.Location.FormattedAddress,
+			Rating:   r.Rating / 2, // Foursquare rates out of 10.
+			Provider: "foursquare",
+		}
+
+		for _, c := range r.Categories {
+			pl.Categories = append(pl.Categories, c.Name)
+		}
+
+		places[i] = pl
+	}
+
+	return rankPlaces(p, radius, places), nil
+}
+
+// Returns the places of the passed in category, one of the Place categories,
+// an OpenStreetMap amenity such as "library", or a tag such as "shop=books",
+// within the passed in radius of the passed in point.  Unnamed elements are left out,
+// and ways, such as buildings, are placed at their centroid.  Implements the PlaceSearcher Interface.
+func (c *OverpassClient) PlaceSearch(p *Point, radius float64, category string) ([]*Place, error) {
+	tag, ok := overpassPlaceTags[category]
+	if !ok {
+		tag = OverpassTag{Key: "amenity", Values: []string{category}}
+		if key, value, found := strings.Cut(category, "="); found {
+			tag = OverpassTag{Key: key, Values: []string{value}}
+		}
+	}
+
+	// The square around the circle searched within.
+	diagonal := radius * math.Sqrt2
+	bounds := NewBounds(p.PointAtDistanceAndBearing(diagonal, 225), p.PointAtDistanceAndBearing(diagonal, 45))
+	fc, err := c.Features(bounds, tag, OverpassTag{Key: "name"})
+	if err != nil {
+		return nil, err
+	}
+
+	var places []*Place
+	for _, f := range fc.Features {
+		value, _ := f.PropertyString(tag.Key)
+		pl := &Place{ID: fmt.Sprint(f.ID), Categories: []string{tag.Key + "=" + value}, Provider: "overpass"}
+		pl.Name, _ = f.PropertyString("name")
+
+		switch g := f.Geometry.(type) {
+		case *Point:
+			pl.Point = g
+		case *Polygon:
+			pl.Point = g.Centroid()
+		case Line:
+			pl.Point = g[len(g)/2]
+		}
+
+		pl.Address = osmAddress(f)
+		places = append(places, pl)
+	}
+
+	return rankPlaces(p, radius, places), nil
+}
+
+// Returns the street address in the OpenStreetMap "addr:" tags of the passed in feature,
+// e.g. "Nieuwe Doelenstraat 20, Amsterdam".
+func osmAddress(f *Feature) string {
+	street, _ := f.PropertyString("addr:street")
+	number, _ := f.PropertyString("addr:housenumber")
+	city, _ := f.PropertyString("addr:city")
+
+	var parts []string
+	for _, part := range []string{strings.TrimSpace(street + " " + number), city} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
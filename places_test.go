@@ -0,0 +1,160 @@
+package geo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// Returns the names of the passed in places, in order.
+func placeNames(places []*Place) string {
+	names := make([]string, len(places))
+	for i, pl := range places {
+		names[i] = pl.Name
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// Ensures that Google's results are ranked by distance, dropping those beyond the radius,
+// and that place categories are translated to Google place types.
+func TestGooglePlacesProvider(t *testing.T) {
+	var queries []url.Values
+	server := newQueryRecordingServer(t, "test/data/google_places_nearby.json", &queries)
+
+	g := NewGooglePlacesProvider(WithBaseURL(server.URL), WithAPIKey("secret"))
+	dam := NewPoint(52.3700, 4.8914)
+	places, err := g.PlaceSearch(dam, 1, PlaceCafe)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if placeNames(places) != "Café de Jaren, Koffiehuis" {
+		t.Errorf("Expected Café de Jaren, Koffiehuis, got %s", placeNames(places))
+	}
+
+	pl := places[0]
+	if pl.ID != "ChIJ6SWlH8AJxkcRGn9XjF5Yy8A" || pl.Rating != 4.4 || pl.Provider != "google" || pl.Address != "Nieuwe Doelenstraat 20-22, Amsterdam" {
+		t.Errorf("Unexpected place: %+v", pl)
+	}
+
+	if pl.Distance <= 0 || pl.Distance > places[1].Distance {
+		t.Errorf("Expected distances in ascending order, got %f and %f", pl.Distance, places[1].Distance)
+	}
+
+	q := queries[0]
+	if q.Get("type") != "cafe" || q.Get("radius") != "1000" || q.Get("location") != "52.370000,4.891400" || q.Get("key") != "secret" {
+		t.Errorf("Unexpected query: %v", q)
+	}
+
+	g.PlaceSearch(dam, 100, PlaceHotel)
+	if q := queries[1]; q.Get("type") != "lodging" || q.Get("radius") != "50000" {
+		t.Errorf("Expected a lodging search capped at 50km, got %v", q)
+	}
+}
+
+// Ensures that Foursquare's results are read with their categories and ratings out of 5,
+// and that categories without a Foursquare id are searched for by name.
+func TestFoursquareProvider(t *testing.T) {
+	data, err := GetMockResponse("test/data/foursquare_places.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	f := NewFoursquareProvider(WithBaseURL(server.URL), WithAPIKey("fsq-key"))
+	places, err := f.PlaceSearch(NewPoint(52.3700, 4.8914), 1, PlaceCafe)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if placeNames(places) != "Café de Jaren, Rokin Koffie" {
+		t.Errorf("Expected Café de Jaren, Rokin Koffie, got %s", placeNames(places))
+	}
+
+	if pl := places[0]; pl.Rating != 4.4 || strings.Join(pl.Categories, ",") != "Café,Bar" || pl.Provider != "foursquare" {
+		t.Errorf("Unexpected place: %+v", pl)
+	}
+
+	r := requests[0]
+	if r.Header.Get("Authorization") != "fsq-key" || r.URL.Query().Get("categories") != "13032" {
+		t.Errorf("Unexpected request: %v %v", r.Header, r.URL)
+	}
+
+	f.PlaceSearch(NewPoint(52.3700, 4.8914), 1, "ramen")
+	if q := requests[1].URL.Query(); q.Get("query") != "ramen" || q.Get("categories") != "" {
+		t.Errorf("Expected a search by name, got %v", q)
+	}
+}
+
+// Ensures that OpenStreetMap elements are returned as places, with ways at their centroid.
+func TestOverpassPlaceSearch(t *testing.T) {
+	data, err := GetMockResponse("test/data/overpass_cafes.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.PostFormValue("data"))
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	c := NewOverpassClient(WithBaseURL(server.URL))
+	places, err := c.PlaceSearch(NewPoint(52.3700, 4.8914), 0.5, PlaceCafe)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if placeNames(places) != "Café de Jaren, Terras, Koffiehuis" {
+		t.Errorf("Expected Café de Jaren, Terras, Koffiehuis, got %s", placeNames(places))
+	}
+
+	if pl := places[2]; pl.ID != "way/2345678" || pl.Point.Lat() < 52.372 || pl.Point.Lat() > 52.3722 || pl.Categories[0] != "amenity=cafe" {
+		t.Errorf("Unexpected place: %+v", pl)
+	}
+
+	if !strings.Contains(queries[0], `nw["amenity"="cafe"]["name"](`) {
+		t.Errorf("Unexpected query: %s", queries[0])
+	}
+
+	c.PlaceSearch(NewPoint(52.3700, 4.8914), 0.5, "shop=books")
+	if !strings.Contains(queries[1], `nw["shop"="books"]["name"](`) {
+		t.Errorf("Expected a search by tag, got %s", queries[1])
+	}
+}
+
+// Ensures that places become point features carrying their fields as properties.
+func TestPlaceFeature(t *testing.T) {
+	pl := &Place{ID: "node/1", Name: "Café de Jaren", Point: NewPoint(52.3701, 4.8915), Categories: []string{"cafe"}, Distance: 0.02, Provider: "overpass"}
+	fc := PlacesToFeatures([]*Place{pl})
+	f := fc.Features[0]
+
+	if f.ID != "node/1" || f.Geometry.(*Point) != pl.Point {
+		t.Errorf("Unexpected feature: %+v", f)
+	}
+
+	if name, _ := f.PropertyString("name"); name != "Café de Jaren" {
+		t.Errorf("Expected Café de Jaren, got %q", name)
+	}
+
+	if _, ok := f.Properties["rating"]; ok {
+		t.Error("Expected no rating property for an unrated place")
+	}
+
+	if _, err := fc.MarshalJSON(); err != nil {
+		t.Errorf("Expected the features to marshal, got %v", err)
+	}
+}
+
+// Ensures that every provider implements PlaceSearcher.
+var _ = []PlaceSearcher{&GooglePlacesProvider{}, &FoursquareProvider{}, &OverpassClient{}}
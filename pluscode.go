@@ -0,0 +1,226 @@
+package geo
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+// The alphabet plus codes are written in, its separator and its padding character.
+const (
+	plusCodeAlphabet  = "23456789CFGHJMPQRVWX"
+	plusCodeSeparator = '+'
+	plusCodePadding   = '0'
+)
+
+// The number of characters before the separator of a full plus code,
+// and the number of characters encoded as pairs of latitude and longitude digits.
+const (
+	plusCodeSeparatorPosition = 8
+	plusCodePairLength        = 10
+	MAX_PLUS_CODE_LENGTH      = 15
+)
+
+// The units, in fractions of a degree, that plus codes of the longest length locate points to:
+// pairs of base 20 digits down to 1/8000 of a degree, then grid digits dividing each cell
+// into 5 rows and 4 columns.
+const (
+	plusCodeLatUnits = 8000 * 3125
+	plusCodeLngUnits = 8000 * 1024
+)
+
+// This is the error that consumers receive when decoding a string that isn't a plus code.
+var invalidPlusCodeError = errors.New("invalid plus code")
+
+// This is the error that consumers receive when decoding a short plus code,
+// such as "CWC8+R9", without a reference point to recover it with.
+var shortPlusCodeError = errors.New("plus code is short and needs a reference point")
+
+// Returns the Open Location Code ("plus code") of the passed in coordinates,
+// of the passed in number of digits, e.g. "849VCWC8+R9" for 10 digits.
+// Lengths below 10 are rounded down to an even number and padded, e.g. "849V0000+";
+// lengths are clamped to between 2 and MAX_PLUS_CODE_LENGTH.
+func EncodePlusCode(lat float64, lng float64, length int) string {
+	length = max(2, min(length, MAX_PLUS_CODE_LENGTH))
+	if length < plusCodePairLength {
+		length -= length % 2
+	}
+
+	latValue := int64(math.Floor((math.Max(-90, math.Min(90, lat)) + 90) * plusCodeLatUnits))
+	lngValue := int64(math.Floor((NormalizeLng(lng) + 180) * plusCodeLngUnits))
+	// Points on the north pole or the antimeridian fall in the cells below or to the west of them.
+	latValue = min(latValue, 180*plusCodeLatUnits-1)
+	lngValue = lngValue % (360 * plusCodeLngUnits)
+
+	var code [MAX_PLUS_CODE_LENGTH]byte
+	for i := MAX_PLUS_CODE_LENGTH - 1; i >= plusCodePairLength; i-- {
+		code[i] = plusCodeAlphabet[(latValue%5)*4+lngValue%4]
+		latValue /= 5
+		lngValue /= 4
+	}
+
+	for i := plusCodePairLength - 2; i >= 0; i -= 2 {
+		code[i] = plusCodeAlphabet[latValue%20]
+		code[i+1] = plusCodeAlphabet[lngValue%20]
+		latValue /= 20
+		lngValue /= 20
+	}
+
+	var b strings.Builder
+	b.Write(code[:min(length, plusCodeSeparatorPosition)])
+	for i := length; i < plusCodeSeparatorPosition; i++ {
+		b.WriteByte(plusCodePadding)
+	}
+	b.WriteByte(plusCodeSeparator)
+	if length > plusCodeSeparatorPosition {
+		b.Write(code[plusCodeSeparatorPosition:length])
+	}
+
+	return b.String()
+}
+
+// Returns the plus code of the point, of the passed in number of digits.
+func (p *Point) PlusCode(length int) string {
+	return EncodePlusCode(p.lat, p.lng, length)
+}
+
+// Returns whether or not the passed in string is a valid plus code,
+// and whether it is a full code rather than a short one.  Case is ignored.
+func checkPlusCode(code string) (valid bool, full bool) {
+	code = strings.ToUpper(code)
+	sep := strings.IndexByte(code, plusCodeSeparator)
+	if sep < 0 || sep != strings.LastIndexByte(code, plusCodeSeparator) || sep > plusCodeSeparatorPosition || sep%2 != 0 {
+		return false, false
+	}
+
+	// A single digit after the separator is not allowed.
+	if len(code)-sep-1 == 1 {
+		return false, false
+	}
+
+	padded := false
+	if pad := strings.IndexByte(code, plusCodePadding); pad >= 0 {
+		// Padding only shortens full codes, comes in pairs and can't be followed by more digits.
+		if sep < plusCodeSeparatorPosition || pad == 0 || pad%2 != 0 || sep != len(code)-1 {
+			return false, false
+		}
+
+		if strings.Trim(code[pad:sep], string(plusCodePadding)) != "" {
+			return false, false
+		}
+
+		code, sep, padded = code[:pad]+code[sep:], pad, true
+	}
+
+	for i := 0; i < len(code); i++ {
+		if i != sep && strings.IndexByte(plusCodeAlphabet, code[i]) < 0 {
+			return false, false
+		}
+	}
+
+	if sep < plusCodeSeparatorPosition && !padded {
+		return true, false
+	}
+
+	// The first digits of full codes can't lie beyond 90 degrees latitude or 180 degrees longitude.
+	if strings.IndexByte(plusCodeAlphabet, code[0]) >= 9 || len(code) > 1 && strings.IndexByte(plusCodeAlphabet, code[1]) >= 18 {
+		return false, false
+	}
+
+	return true, true
+}
+
+// Returns whether or not the passed in string is a full plus code, such as "849VCWC8+R9".
+func IsPlusCode(code string) bool {
+	valid, full := checkPlusCode(code)
+	return valid && full
+}
+
+// Returns the Bounds of the area the passed in full plus code names.
+// Returns an error if the code is short; recover it with RecoverPlusCode first.
+func PlusCodeBounds(code string) (*Bounds, error) {
+	valid, full := checkPlusCode(code)
+	if !valid {
+		return nil, invalidPlusCodeError
+	}
+
+	if !full {
+		return nil, shortPlusCodeError
+	}
+
+	digits := strings.ToUpper(strings.NewReplacer(string(plusCodeSeparator), "", string(plusCodePadding), "").Replace(code))
+	digits = digits[:min(len(digits), MAX_PLUS_CODE_LENGTH)]
+
+	var lat, lng int64
+	latPlace, lngPlace := int64(400*plusCodeLatUnits), int64(400*plusCodeLngUnits)
+	for i := 0; i < len(digits); i++ {
+		value := int64(strings.IndexByte(plusCodeAlphabet, digits[i]))
+		switch {
+		case i >= plusCodePairLength:
+			latPlace, lngPlace = latPlace/5, lngPlace/4
+			lat += value / 4 * latPlace
+			lng += value % 4 * lngPlace
+		case i%2 == 0:
+			latPlace /= 20
+			lat += value * latPlace
+		default:
+			lngPlace /= 20
+			lng += value * lngPlace
+		}
+	}
+
+	sw := NewPoint(float64(lat)/plusCodeLatUnits-90, float64(lng)/plusCodeLngUnits-180)
+	ne := NewPoint(float64(lat+latPlace)/plusCodeLatUnits-90, float64(lng+lngPlace)/plusCodeLngUnits-180)
+	return NewBounds(sw, ne), nil
+}
+
+// Returns the center of the area the passed in full plus code names.
+func DecodePlusCode(code string) (*Point, error) {
+	bounds, err := PlusCodeBounds(code)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPoint((bounds.sw.lat+bounds.ne.lat)/2, (bounds.sw.lng+bounds.ne.lng)/2), nil
+}
+
+// Returns the full plus code that the passed in short code, such as "CWC8+R9", names
+// near the passed in reference point, which should be within a few tens of kilometers
+// of the area.  Full codes are returned unchanged, in upper case.
+func RecoverPlusCode(code string, reference *Point) (string, error) {
+	valid, full := checkPlusCode(code)
+	if !valid {
+		return "", invalidPlusCodeError
+	}
+
+	code = strings.ToUpper(code)
+	if full {
+		return code, nil
+	}
+
+	// Borrow the digits the short code is missing from the reference's code,
+	// then move the area by a whole cell if that brings it nearer the reference.
+	missing := plusCodeSeparatorPosition - strings.IndexByte(code, plusCodeSeparator)
+	resolution := math.Pow(20, float64(2-missing/2))
+	refLat, refLng := math.Max(-90, math.Min(90, reference.lat)), NormalizeLng(reference.lng)
+
+	center, err := DecodePlusCode(EncodePlusCode(refLat, refLng, MAX_PLUS_CODE_LENGTH)[:missing] + code)
+	if err != nil {
+		return "", err
+	}
+
+	lat, lng := center.lat, center.lng
+	if refLat+resolution/2 < lat && lat-resolution >= -90 {
+		lat -= resolution
+	} else if refLat-resolution/2 > lat && lat+resolution <= 90 {
+		lat += resolution
+	}
+
+	if refLng+resolution/2 < lng {
+		lng -= resolution
+	} else if refLng-resolution/2 > lng {
+		lng += resolution
+	}
+
+	return EncodePlusCode(lat, lng, len(code)-1+missing), nil
+}
@@ -0,0 +1,110 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+// Ensures that points are encoded to the plus codes Google Maps shows for them.
+func TestEncodePlusCode(t *testing.T) {
+	tests := []struct {
+		lat, lng float64
+		length   int
+		expected string
+	}{
+		{37.4220625, -122.0840625, 10, "849VCWC8+R9"},
+		{37.4220625, -122.0840625, 11, "849VCWC8+R9G"},
+		{37.4220625, -122.0840625, 4, "849V0000+"},
+		{37.4220625, -122.0840625, 7, "849VCW00+"},
+		{-41.273125, 174.785781, 10, "4VCPPQGP+Q8"},
+		{90, 1, 4, "CFX30000+"},
+		{1, 180, 4, "62H20000+"},
+	}
+
+	for _, test := range tests {
+		if code := EncodePlusCode(test.lat, test.lng, test.length); code != test.expected {
+			t.Errorf("Expected %s for %f, %f, got %s", test.expected, test.lat, test.lng, code)
+		}
+	}
+}
+
+// Ensures that plus codes decode to the center of their area, whatever their case.
+func TestDecodePlusCode(t *testing.T) {
+	p, err := DecodePlusCode("849vcwc8+r9")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if math.Abs(p.Lat()-37.4220625) > 1e-9 || math.Abs(p.Lng()-(-122.0840625)) > 1e-9 {
+		t.Errorf("Expected 37.4220625, -122.0840625, got %v", p)
+	}
+
+	bounds, err := PlusCodeBounds("849V0000+")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bounds.SouthWest().Lat() != 37 || bounds.SouthWest().Lng() != -123 || bounds.NorthEast().Lat() != 38 || bounds.NorthEast().Lng() != -122 {
+		t.Errorf("Unexpected bounds: %v %v", bounds.SouthWest(), bounds.NorthEast())
+	}
+
+	// Round trip every grid digit.
+	for _, length := range []int{10, 11, 12, 13, 14, 15} {
+		code := EncodePlusCode(-33.856784, 151.215297, length)
+		bounds, err := PlusCodeBounds(code)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bounds.Contains(NewPoint(-33.856784, 151.215297)) {
+			t.Errorf("Expected %s to contain the point it encodes", code)
+		}
+	}
+}
+
+// Ensures that malformed codes are rejected and short codes can't be decoded alone.
+func TestPlusCodeValidation(t *testing.T) {
+	for _, code := range []string{"849VCWC8+R9", "849V0000+", "8FVC2222+22GCCCC"} {
+		if !IsPlusCode(code) {
+			t.Errorf("Expected %s to be a full plus code", code)
+		}
+	}
+
+	for _, code := range []string{"", "849VCWC8R9", "849VCWC8+R", "849VCWC8++R9", "849V00C8+", "849V0000+R9", "8490000+", "F49VCWC8+R9", "849ACWC8+R9", "CWC8+R9", "WC8+R9", "X2VX+"} {
+		if IsPlusCode(code) {
+			t.Errorf("Expected %q not to be a full plus code", code)
+		}
+	}
+
+	if _, err := DecodePlusCode("CWC8+R9"); err != shortPlusCodeError {
+		t.Errorf("Expected shortPlusCodeError, got %v", err)
+	}
+
+	if _, err := DecodePlusCode("849VCWC8-R9"); err != invalidPlusCodeError {
+		t.Errorf("Expected invalidPlusCodeError, got %v", err)
+	}
+}
+
+// Ensures that short codes are recovered to the full code nearest the reference,
+// including across the boundaries of the reference's own cell.
+func TestRecoverPlusCode(t *testing.T) {
+	tests := []struct {
+		code      string
+		reference *Point
+		expected  string
+	}{
+		{"CWC8+R9", NewPoint(37.4, -122.1), "849VCWC8+R9"},
+		{"cwc8+r9", NewPoint(37.4, -122.1), "849VCWC8+R9"},
+		{"849VCWC8+R9", NewPoint(0, 0), "849VCWC8+R9"},
+		{"9G8F+6W", NewPoint(47.4, 8.6), "8FVC9G8F+6W"},
+		{"X2VX+", NewPoint(38.9, -76.9), "87C5X2VX+"},
+		{"2222+22", NewPoint(1.9999, 1.9999), "6FJ42222+22"},
+	}
+
+	for _, test := range tests {
+		code, err := RecoverPlusCode(test.code, test.reference)
+		if err != nil || code != test.expected {
+			t.Errorf("Expected %s for %s near %v, got %s (%v)", test.expected, test.code, test.reference, code, err)
+		}
+	}
+}
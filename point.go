@@ -1,10 +1,6 @@
 package geo
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"log"
 	"math"
 )
 
@@ -37,15 +33,33 @@ func (p *Point) Lng() float64 {
 // Returns a Point populated with the lat and lng coordinates
 // by transposing the origin point the passed in distance (in kilometers)
 // by the passed in compass bearing (in degrees).
-// Original Implementation from: http://www.movable-type.co.uk/scripts/latlong.html
 func (p *Point) PointAtDistanceAndBearing(dist float64, bearing float64) *Point {
+	lat, lng := Destination(p.lat, p.lng, dist, bearing)
+	return &Point{lat: lat, lng: lng}
+}
+
+// Calculates the Haversine distance between two points.
+func (p *Point) GreatCircleDistance(p2 *Point) float64 {
+	return HaversineDistance(p.lat, p.lng, p2.lat, p2.lng)
+}
+
+// Calculates the initial bearing (sometimes referred to as forward azimuth)
+func (p *Point) BearingTo(p2 *Point) float64 {
+	return InitialBearing(p.lat, p.lng, p2.lat, p2.lng)
+}
+
+// Returns the latitude and longitude found by travelling the passed in distance
+// (in kilometers) from the passed in coordinates along the passed in compass bearing (in degrees).
+// Unlike PointAtDistanceAndBearing, never allocates.
+// Original Implementation from: http://www.movable-type.co.uk/scripts/latlong.html
+func Destination(lat float64, lng float64, dist float64, bearing float64) (float64, float64) {
 
 	dr := dist / EARTH_RADIUS
 
 	bearing = (bearing * (math.Pi / 180.0))
 
-	lat1 := (p.lat * (math.Pi / 180.0))
-	lng1 := (p.lng * (math.Pi / 180.0))
+	lat1 := (lat * (math.Pi / 180.0))
+	lng1 := (lng * (math.Pi / 180.0))
 
 	lat2_part1 := math.Sin(lat1) * math.Cos(dr)
 	lat2_part2 := math.Cos(lat1) * math.Sin(dr) * math.Cos(bearing)
@@ -61,17 +75,18 @@ func (p *Point) PointAtDistanceAndBearing(dist float64, bearing float64) *Point
 	lat2 = lat2 * (180.0 / math.Pi)
 	lng2 = lng2 * (180.0 / math.Pi)
 
-	return &Point{lat: lat2, lng: lng2}
+	return lat2, lng2
 }
 
-// Calculates the Haversine distance between two points.
+// Calculates the Haversine distance, in kilometers, between two pairs of coordinates.
+// Never allocates.
 // Original Implementation from: http://www.movable-type.co.uk/scripts/latlong.html
-func (p *Point) GreatCircleDistance(p2 *Point) float64 {
-	dLat := (p2.lat - p.lat) * (math.Pi / 180.0)
-	dLon := (p2.lng - p.lng) * (math.Pi / 180.0)
+func HaversineDistance(lat1 float64, lng1 float64, lat2 float64, lng2 float64) float64 {
+	dLat := (lat2 - lat1) * (math.Pi / 180.0)
+	dLon := LngDiff(lng1, lng2) * (math.Pi / 180.0)
 
-	lat1 := p.lat * (math.Pi / 180.0)
-	lat2 := p2.lat * (math.Pi / 180.0)
+	lat1 = lat1 * (math.Pi / 180.0)
+	lat2 = lat2 * (math.Pi / 180.0)
 
 	a1 := math.Sin(dLat/2) * math.Sin(dLat/2)
 	a2 := math.Sin(dLon/2) * math.Sin(dLon/2) * math.Cos(lat1) * math.Cos(lat2)
@@ -83,14 +98,15 @@ func (p *Point) GreatCircleDistance(p2 *Point) float64 {
 	return EARTH_RADIUS * c
 }
 
-// Calculates the initial bearing (sometimes referred to as forward azimuth)
+// Calculates the initial bearing (sometimes referred to as forward azimuth), in degrees,
+// from the first pair of coordinates to the second.  Never allocates.
 // Original Implementation from: http://www.movable-type.co.uk/scripts/latlong.html
-func (p *Point) BearingTo(p2 *Point) float64 {
+func InitialBearing(lat1 float64, lng1 float64, lat2 float64, lng2 float64) float64 {
 
-	dLon := (p2.lng - p.lng) * math.Pi / 180.0
+	dLon := LngDiff(lng1, lng2) * math.Pi / 180.0
 
-	lat1 := p.lat * math.Pi / 180.0
-	lat2 := p2.lat * math.Pi / 180.0
+	lat1 = lat1 * math.Pi / 180.0
+	lat2 = lat2 * math.Pi / 180.0
 
 	y := math.Sin(dLon) * math.Cos(lat2)
 	x := math.Cos(lat1)*math.Sin(lat2) -
@@ -100,27 +116,35 @@ func (p *Point) BearingTo(p2 *Point) float64 {
 	return brng
 }
 
-// Renders the current Point to valid JSON.
+// Renders the current Point to valid JSON, as an object, {"lat":...,"lng":...},
+// with CoordinatePrecision decimal places; wrap it in a LngLat or a LatLng for an array.
 // Implements the json.Marshaller Interface.
+// Fails with a *CoordinateError for invalid points if strict validation is on,
+// and for NaN or infinite coordinates, which JSON can't represent, regardless.
 func (p *Point) MarshalJSON() ([]byte, error) {
-	res := fmt.Sprintf(`{"lat":%v, "lng":%v}`, p.Lat(), p.Lng())
-	return []byte(res), nil
+	if err := checkPointJSON(p); err != nil {
+		return nil, err
+	}
+
+	return appendPointObjectJSON(nil, p), nil
 }
 
-// Decodes the current Point from a JSON body.
-// Throws an error if the body of the point cannot be interpreted by the JSON body
+// Decodes the current Point from a JSON body: an object with "lat" and "lng" members, or a GeoJSON Point
+// geometry.  Arrays of coordinates are refused, since their order can't be told; decode them into a
+// LngLat or a LatLng.
+// Throws an error if the body of the point cannot be interpreted by the JSON body,
+// or, if strict validation is on, a *CoordinateError if it holds an invalid point.
 func (p *Point) UnmarshalJSON(data []byte) error {
-	// TODO throw an error if there is an issue parsing the body.
-	dec := json.NewDecoder(bytes.NewReader(data))
-	var values map[string]float64
-	err := dec.Decode(&values)
-
+	decoded, err := decodePointJSON(data, false, false)
 	if err != nil {
-		log.Print(err)
 		return err
 	}
 
-	*p = *NewPoint(values["lat"], values["lng"])
+	if err := checkStrict(decoded); err != nil {
+		return err
+	}
+
+	*p = *decoded
 
 	return nil
 }
@@ -0,0 +1,193 @@
+package geo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+)
+
+// The error returned when decoding JSON that isn't a point.
+var pointJSONError = errors.New("point: expected {\"lat\":..., \"lng\":...}, an array of two coordinates, or a GeoJSON Point")
+
+// A LngLat is a Point that encodes as a JSON array with longitude first, as GeoJSON coordinates are:
+// [-122.389979,37.615223].  Wrap points in one, as in geo.LngLat{p}, where an API expects them so;
+// a Point itself always encodes as an object, so that JSON written by one program reads the same in any other.
+type LngLat struct {
+	*Point
+}
+
+// A LatLng is a Point that encodes as a JSON array with latitude first: [37.615223,-122.389979].
+// Wrap points in one, as in geo.LatLng{p}, where an API expects them so.
+type LatLng struct {
+	*Point
+}
+
+// Renders the wrapped Point as a JSON array, [lng, lat], with CoordinatePrecision decimal places.
+// Implements the json.Marshaller Interface.
+func (p LngLat) MarshalJSON() ([]byte, error) {
+	if err := checkPointJSON(p.Point); err != nil {
+		return nil, err
+	}
+
+	return appendPointJSON(nil, p.Point, false), nil
+}
+
+// Decodes the wrapped Point from a JSON array, [lng, lat], or anything else Point's UnmarshalJSON reads.
+// Implements the json.Unmarshaler Interface.
+func (p *LngLat) UnmarshalJSON(data []byte) error {
+	return unmarshalPointJSON(&p.Point, data, false)
+}
+
+// Renders the wrapped Point as a JSON array, [lat, lng], with CoordinatePrecision decimal places.
+// Implements the json.Marshaller Interface.
+func (p LatLng) MarshalJSON() ([]byte, error) {
+	if err := checkPointJSON(p.Point); err != nil {
+		return nil, err
+	}
+
+	return appendPointJSON(nil, p.Point, true), nil
+}
+
+// Decodes the wrapped Point from a JSON array, [lat, lng], or anything else Point's UnmarshalJSON reads.
+// Implements the json.Unmarshaler Interface.
+func (p *LatLng) UnmarshalJSON(data []byte) error {
+	return unmarshalPointJSON(&p.Point, data, true)
+}
+
+// Returns an error if the passed in point can't be encoded as JSON: a *CoordinateError for invalid points
+// if strict validation is on, and for NaN or infinite coordinates, which JSON can't represent, regardless.
+func checkPointJSON(p *Point) error {
+	if err := checkStrict(p); err != nil {
+		return err
+	}
+
+	if math.IsNaN(p.lat) || math.IsNaN(p.lng) || math.IsInf(p.lat, 0) || math.IsInf(p.lng, 0) {
+		return p.Validate()
+	}
+
+	return nil
+}
+
+// Decodes a point from JSON into *dst, as decodePointJSON does, allocating it if need be.
+func unmarshalPointJSON(dst **Point, data []byte, latFirst bool) error {
+	decoded, err := decodePointJSON(data, true, latFirst)
+	if err != nil {
+		return err
+	}
+
+	if err := checkStrict(decoded); err != nil {
+		return err
+	}
+
+	if *dst == nil {
+		*dst = decoded
+	} else {
+		**dst = *decoded
+	}
+	return nil
+}
+
+// Appends the passed in point to dst as a JSON array, [lng, lat] or, if latFirst, [lat, lng].
+func appendPointJSON(dst []byte, p *Point, latFirst bool) []byte {
+	first, second := p.lng, p.lat
+	if latFirst {
+		first, second = p.lat, p.lng
+	}
+
+	dst = append(dst, '[')
+	dst = appendCoordinate(dst, first)
+	dst = append(dst, ',')
+	dst = appendCoordinate(dst, second)
+	return append(dst, ']')
+}
+
+// Appends the passed in point to dst as a JSON object, {"lat":...,"lng":...}.
+func appendPointObjectJSON(dst []byte, p *Point) []byte {
+	dst = append(dst, `{"lat":`...)
+	dst = appendCoordinate(dst, p.lat)
+	dst = append(dst, `,"lng":`...)
+	dst = appendCoordinate(dst, p.lng)
+	return append(dst, '}')
+}
+
+// Decodes a point from a JSON object with "lat" and "lng" members or a GeoJSON Point geometry and, if arrays
+// is set, from an array of coordinates, [lng, lat] or, if latFirst, [lat, lng].  The order of an array
+// is only ever known from the type it is decoded into, never guessed.
+func decodePointJSON(data []byte, arrays, latFirst bool) (*Point, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		if !arrays {
+			return nil, pointJSONError
+		}
+
+		var coordinates []float64
+		if err := json.Unmarshal(data, &coordinates); err != nil {
+			return nil, err
+		}
+
+		return pointFromCoordinates(coordinates, latFirst)
+	}
+
+	var object struct {
+		Lat         *float64  `json:"lat"`
+		Lng         *float64  `json:"lng"`
+		Type        string    `json:"type"`
+		Coordinates []float64 `json:"coordinates"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case object.Lat != nil && object.Lng != nil:
+		return NewPoint(*object.Lat, *object.Lng), nil
+	case object.Type == "Point":
+		return pointFromCoordinates(object.Coordinates, false)
+	default:
+		return nil, pointJSONError
+	}
+}
+
+// Returns the point at the passed in coordinates, [lng, lat] or, if latFirst, [lat, lng].
+// A third coordinate, such as a GeoJSON altitude, is ignored.
+func pointFromCoordinates(coordinates []float64, latFirst bool) (*Point, error) {
+	if len(coordinates) < 2 || len(coordinates) > 3 {
+		return nil, pointJSONError
+	}
+
+	if latFirst {
+		return NewPoint(coordinates[0], coordinates[1]), nil
+	}
+
+	return NewPoint(coordinates[1], coordinates[0]), nil
+}
+
+// Renders the current Point as text, "lat,lng", e.g. "37.615223,-122.389979",
+// with CoordinatePrecision decimal places, for formats such as YAML, TOML and CSV.  Implements the encoding.TextMarshaler interface.
+// Fails with a *CoordinateError for invalid points if strict validation is on.
+func (p *Point) MarshalText() ([]byte, error) {
+	if err := checkStrict(p); err != nil {
+		return nil, err
+	}
+
+	if math.IsNaN(p.lat) || math.IsNaN(p.lng) || math.IsInf(p.lat, 0) || math.IsInf(p.lng, 0) {
+		return nil, p.Validate()
+	}
+
+	text := appendCoordinate(nil, p.lat)
+	text = append(text, ',')
+	return appendCoordinate(text, p.lng), nil
+}
+
+// Decodes the current Point from text in any of the formats ParsePoint reads, "lat,lng" among them.
+// Implements the encoding.TextUnmarshaler interface.
+func (p *Point) UnmarshalText(text []byte) error {
+	decoded, err := ParsePoint(string(text))
+	if err != nil {
+		return err
+	}
+
+	*p = *decoded
+	return nil
+}
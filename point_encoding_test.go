@@ -0,0 +1,82 @@
+package geo
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+// Ensures that points encode as an object, and that LngLat and LatLng encode and decode arrays in their own order.
+func TestPointEncoding(t *testing.T) {
+	p := NewPoint(37.615223, -122.389979)
+	expected := map[string]interface{}{
+		`{"lat":37.615223,"lng":-122.389979}`: p,
+		`[-122.389979,37.615223]`:             LngLat{p},
+		`[37.615223,-122.389979]`:             LatLng{p},
+	}
+
+	for s, v := range expected {
+		data, err := json.Marshal(v)
+		if err != nil || string(data) != s {
+			t.Errorf("Expected %s, got %s (%v)", s, data, err)
+		}
+	}
+
+	decoded := &Point{}
+	if err := json.Unmarshal([]byte(`{"lat":37.615223,"lng":-122.389979}`), decoded); err != nil || *decoded != *p {
+		t.Errorf("Expected the object to decode to %v, got %v (%v)", p, decoded, err)
+	}
+
+	lngLat := LngLat{}
+	if err := json.Unmarshal([]byte(`[-122.389979,37.615223,4]`), &lngLat); err != nil || *lngLat.Point != *p {
+		t.Errorf("Expected [lng, lat] to decode to %v, got %v (%v)", p, lngLat.Point, err)
+	}
+
+	latLng := LatLng{}
+	if err := json.Unmarshal([]byte(`[37.615223,-122.389979]`), &latLng); err != nil || *latLng.Point != *p {
+		t.Errorf("Expected [lat, lng] to decode to %v, got %v (%v)", p, latLng.Point, err)
+	}
+
+	for _, v := range []interface{}{&Point{}, &LngLat{}, &LatLng{}} {
+		s := `{"type":"Point","coordinates":[-122.389979,37.615223]}`
+		if err := json.Unmarshal([]byte(s), v); err != nil {
+			t.Errorf("Expected %s to decode into %T, got %v", s, v, err)
+		}
+	}
+
+	for _, s := range []string{`{}`, `{"lat":1}`, `[1,2]`, `"1,2"`} {
+		if err := json.Unmarshal([]byte(s), &Point{}); err == nil {
+			t.Errorf("Expected an error decoding %s into a Point", s)
+		}
+	}
+
+	if err := json.Unmarshal([]byte(`[1]`), &LngLat{}); err == nil {
+		t.Error("Expected an error decoding [1] into a LngLat")
+	}
+
+	if _, err := json.Marshal(NewPoint(math.NaN(), 0)); err == nil {
+		t.Error("Expected an error encoding NaN")
+	}
+
+	if _, err := json.Marshal(LngLat{NewPoint(math.NaN(), 0)}); err == nil {
+		t.Error("Expected an error encoding NaN in a LngLat")
+	}
+}
+
+// Ensures that points encode as "lat,lng" text and decode from it.
+func TestPointText(t *testing.T) {
+	p := NewPoint(37.615223, -122.389979)
+	text, err := p.MarshalText()
+	if err != nil || string(text) != "37.615223,-122.389979" {
+		t.Errorf("Expected 37.615223,-122.389979, got %s (%v)", text, err)
+	}
+
+	decoded := &Point{}
+	if err := decoded.UnmarshalText(text); err != nil || *decoded != *p {
+		t.Errorf("Expected %s to decode to %v, got %v (%v)", text, p, decoded, err)
+	}
+
+	if err := decoded.UnmarshalText([]byte("north")); err == nil {
+		t.Error("Expected an error decoding text that isn't a point")
+	}
+}
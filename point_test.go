@@ -119,3 +119,28 @@ func TestUnmarshalJSON(t *testing.T) {
 		t.Errorf("Point has mismatched data after Unmarshalling from JSON")
 	}
 }
+
+func BenchmarkGreatCircleDistance(b *testing.B) {
+	sea := &Point{lat: 47.4489, lng: -122.3094}
+	sfo := &Point{lat: 37.6160933, lng: -122.3924223}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sea.GreatCircleDistance(sfo)
+	}
+}
+
+func BenchmarkBearingTo(b *testing.B) {
+	sea := &Point{lat: 47.4489, lng: -122.3094}
+	sfo := &Point{lat: 37.6160933, lng: -122.3924223}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sea.BearingTo(sfo)
+	}
+}
+
+func BenchmarkDestination(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Destination(47.4489, -122.3094, 100, 90)
+	}
+}
@@ -43,18 +43,36 @@ func (p *Polygon) IsClosed() bool {
 }
 
 // Returns whether or not the current Polygon contains the passed in Point.
+// Polygons whose edges cross the antimeridian are handled by making their
+// longitudes continuous first.
 func (p *Polygon) Contains(point *Point) bool {
 	if !p.IsClosed() {
 		return false
 	}
 
-	start := len(p.points) - 1
+	if !crossesAntimeridian(p.points, true) {
+		return p.contains(p.points, point)
+	}
+
+	points := unwrapLngs(p.points)
+	for _, shift := range []float64{0, 360, -360} {
+		if p.contains(points, NewPoint(point.lat, point.lng+shift)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns whether or not the ring formed by the passed in points contains the passed in Point.
+func (p *Polygon) contains(points []*Point, point *Point) bool {
+	start := len(points) - 1
 	end := 0
 
-	contains := p.intersectsWithRaycast(point, p.points[start], p.points[end])
+	contains := p.intersectsWithRaycast(point, points[start], points[end])
 
-	for i := 1; i < len(p.points); i++ {
-		if p.intersectsWithRaycast(point, p.points[i-1], p.points[i]) {
+	for i := 1; i < len(points); i++ {
+		if p.intersectsWithRaycast(point, points[i-1], points[i]) {
 			contains = !contains
 		}
 	}
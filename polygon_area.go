@@ -0,0 +1,116 @@
+package geo
+
+import (
+	"math"
+)
+
+const (
+	// The semi-major axis (equatorial radius) of the WGS84 ellipsoid, in kilometers.
+	WGS84_SEMI_MAJOR_AXIS = 6378.137
+
+	// The flattening of the WGS84 ellipsoid.
+	WGS84_FLATTENING = 1 / 298.257223563
+)
+
+// Returns the area of the current Polygon on the WGS84 ellipsoid, in square kilometers, its edges
+// taken to be geodesics, the shortest paths between their points.  Each edge's contribution, the area
+// between it and the equator, is computed by Karney's method ("Algorithms for geodesics", 2013), as
+// GeographicLib's Planimeter computes it, so that it remains exact for large and high-latitude polygons.
+// Polygons encircling a pole are taken to cover that pole, and of the two regions any ring divides the
+// earth into, the smaller is measured, whichever way the ring is wound.
+func (p *Polygon) Area() float64 {
+	if !p.IsClosed() {
+		return 0
+	}
+
+	area, crossings := 0.0, 0
+	for i, a := range p.points {
+		b := p.points[(i+1)%len(p.points)]
+		area += wgs84.edgeArea(a.lat, a.lng, b.lat, b.lng)
+		crossings += primeMeridianCrossing(a.lng, b.lng)
+	}
+
+	// Rings crossing the prime meridian an odd number of times encircle a pole, so the areas between
+	// their edges and the equator sum to the area between the ring and the equator, not the ring's own.
+	earth := 4 * math.Pi * wgs84.c2
+	area = math.Remainder(area, earth)
+	if crossings%2 != 0 {
+		if area < 0 {
+			area += earth / 2
+		} else {
+			area -= earth / 2
+		}
+	}
+
+	if area > earth/2 {
+		area -= earth
+	} else if area <= -earth/2 {
+		area += earth
+	}
+
+	return math.Abs(area)
+}
+
+// Returns 1 if the edge between the passed in longitudes crosses the prime meridian eastward,
+// -1 if it crosses westward, and 0 if it doesn't cross it.
+func primeMeridianCrossing(from, to float64) int {
+	from, to = NormalizeLng(from), NormalizeLng(to)
+	d := LngDiff(from, to)
+	switch {
+	case from <= 0 && to > 0 && d > 0:
+		return 1
+	case to <= 0 && from > 0 && d < 0:
+		return -1
+	}
+
+	return 0
+}
+
+// Returns the unit vector pointing to the passed in Point from the center of a unit sphere.
+func unitVector(p *Point) [3]float64 {
+	lat, lng := p.lat*math.Pi/180, p.lng*math.Pi/180
+	return [3]float64{math.Cos(lat) * math.Cos(lng), math.Cos(lat) * math.Sin(lng), math.Sin(lat)}
+}
+
+// Returns the centroid of the surface of the current Polygon, treating the earth as a sphere.
+// Unlike the average of its points, the centroid is not skewed by unevenly spaced points,
+// and lies where the polygon's area balances.  Returns nil for polygons that are not closed.
+func (p *Polygon) Centroid() *Point {
+	if !p.IsClosed() {
+		return nil
+	}
+
+	// The first moment of a spherical polygon is half the sum of each edge's
+	// unit normal weighted by the edge's length in radians.
+	var sum, mean [3]float64
+	for i, a := range p.points {
+		va, vb := unitVector(a), unitVector(p.points[(i+1)%len(p.points)])
+		n := [3]float64{
+			va[1]*vb[2] - va[2]*vb[1],
+			va[2]*vb[0] - va[0]*vb[2],
+			va[0]*vb[1] - va[1]*vb[0],
+		}
+		sinTheta := math.Sqrt(n[0]*n[0] + n[1]*n[1] + n[2]*n[2])
+		if sinTheta == 0 {
+			continue
+		}
+
+		theta := math.Atan2(sinTheta, va[0]*vb[0]+va[1]*vb[1]+va[2]*vb[2])
+		for j := range sum {
+			sum[j] += n[j] * theta / sinTheta
+			mean[j] += va[j]
+		}
+	}
+
+	// The moment points into or out of the polygon depending on its winding.
+	if sum[0]*mean[0]+sum[1]*mean[1]+sum[2]*mean[2] < 0 {
+		for j := range sum {
+			sum[j] = -sum[j]
+		}
+	}
+
+	lat := math.Atan2(sum[2], math.Hypot(sum[0], sum[1])) * 180 / math.Pi
+	lng := math.Atan2(sum[1], sum[0]) * 180 / math.Pi
+
+	return NewPoint(lat, lng)
+}
@@ -0,0 +1,79 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+// Ensures that the area of a one degree square at the equator matches its geodesic area.
+func TestPolygonAreaEquator(t *testing.T) {
+	square := NewPolygon([]*Point{NewPoint(0, 0), NewPoint(0, 1), NewPoint(1, 1), NewPoint(1, 0)})
+
+	// As computed by GeographicLib's Planimeter.
+	want := 12308.778361469
+	if got := square.Area(); math.Abs(got-want)/want > 1e-9 {
+		t.Errorf("Expected an area of about %f km², got %f", want, got)
+	}
+
+	reversed := NewPolygon([]*Point{NewPoint(1, 0), NewPoint(1, 1), NewPoint(0, 1), NewPoint(0, 0)})
+	if math.Abs(reversed.Area()-square.Area()) > 1e-6 {
+		t.Errorf("Expected the area not to depend on winding, got %f and %f", reversed.Area(), square.Area())
+	}
+}
+
+// Ensures that a triangle covering an octant of the earth has an eighth of its area,
+// which planar formulas get badly wrong.
+func TestPolygonAreaOctant(t *testing.T) {
+	octant := NewPolygon([]*Point{NewPoint(0, 0), NewPoint(0, 90), NewPoint(90, 0)})
+
+	want := 510065621.7 / 8
+	if got := octant.Area(); math.Abs(got-want)/want > 1e-6 {
+		t.Errorf("Expected an area of %f km², got %f", want, got)
+	}
+}
+
+// Ensures that a polygon encircling a pole covers it, whichever pole and whichever way it is wound.
+func TestPolygonAreaPole(t *testing.T) {
+	// As computed by GeographicLib's Planimeter.
+	want := 24952.305678
+	for _, lat := range []float64{89, -89} {
+		ring := []*Point{NewPoint(lat, 0), NewPoint(lat, 90), NewPoint(lat, 180), NewPoint(lat, 270)}
+		reversed := []*Point{ring[3], ring[2], ring[1], ring[0]}
+		for _, points := range [][]*Point{ring, reversed} {
+			if got := NewPolygon(points).Area(); math.Abs(got-want)/want > 1e-9 {
+				t.Errorf("Expected an area of %f km² around the pole at latitude %v, got %f", want, lat, got)
+			}
+		}
+	}
+}
+
+// Ensures that areas match GeographicLib's for polygons with edges that aren't
+// meridians or the equator, along which the ellipsoid bends geodesics away from great circles.
+func TestPolygonAreaGeodesic(t *testing.T) {
+	diamond := NewPolygon([]*Point{NewPoint(0, -1), NewPoint(-1, 0), NewPoint(0, 1), NewPoint(1, 0)})
+
+	// As computed by GeographicLib's Planimeter.
+	want := 24619.419146
+	if got := diamond.Area(); math.Abs(got-want)/want > 1e-9 {
+		t.Errorf("Expected an area of %f km², got %f", want, got)
+	}
+}
+
+// Ensures that centroids lie where a polygon's area balances.
+func TestPolygonCentroid(t *testing.T) {
+	square := NewPolygon([]*Point{NewPoint(-1, 19), NewPoint(-1, 21), NewPoint(1, 21), NewPoint(1, 19)})
+	c := square.Centroid()
+	if math.Abs(c.Lat()) > 1e-9 || math.Abs(c.Lng()-20) > 1e-9 {
+		t.Errorf("Expected [0, 20], got [%f, %f]", c.Lat(), c.Lng())
+	}
+
+	octant := NewPolygon([]*Point{NewPoint(0, 0), NewPoint(90, 0), NewPoint(0, 90)})
+	c = octant.Centroid()
+	if math.Abs(c.Lat()-math.Asin(1/math.Sqrt(3))*180/math.Pi) > 1e-9 || math.Abs(c.Lng()-45) > 1e-9 {
+		t.Errorf("Expected [35.264390, 45], got [%f, %f]", c.Lat(), c.Lng())
+	}
+
+	if NewPolygon(nil).Centroid() != nil {
+		t.Error("Expected an open polygon to have no centroid")
+	}
+}
@@ -0,0 +1,58 @@
+package geo
+
+// Returns the point on the great circle arc from a to b nearest to the passed in point.
+func closestPointOnArc(p, a, b *Point) *Point {
+	vp, va, vb := unitVector(p), unitVector(a), unitVector(b)
+	n := cross3(va, vb)
+	if length := norm3(n); length >= parallelTolerance {
+		n = [3]float64{n[0] / length, n[1] / length, n[2] / length}
+
+		// Drop the point onto the arc's great circle; if it lands between the ends, that is the nearest point.
+		h := dot3(vp, n)
+		x := [3]float64{vp[0] - h*n[0], vp[1] - h*n[1], vp[2] - h*n[2]}
+		if norm3(x) >= parallelTolerance && onArc(x, va, vb, n) {
+			return vectorPoint(x)
+		}
+	}
+
+	if p.GreatCircleDistance(a) <= p.GreatCircleDistance(b) {
+		return a
+	}
+
+	return b
+}
+
+// Returns the point on the current Polygon's boundary nearest to the passed in point,
+// whether the point lies inside the Polygon or out.  Edges are taken to be great circle arcs.
+// Returns nil for polygons that are not closed.
+func (p *Polygon) ClosestBoundaryPoint(point *Point) *Point {
+	if !p.IsClosed() {
+		return nil
+	}
+
+	var closest *Point
+	best := 0.0
+	for i, a := range p.points {
+		candidate := closestPointOnArc(point, a, p.points[(i+1)%len(p.points)])
+		if d := point.GreatCircleDistance(candidate); closest == nil || d < best {
+			closest, best = candidate, d
+		}
+	}
+
+	return closest
+}
+
+// Returns the great circle distance, in kilometers, from the passed in point to the current Polygon:
+// 0 if the Polygon contains the point, and otherwise the distance to the nearest point of its boundary,
+// answering how far outside a geofence something is.  Returns -1 for polygons that are not closed.
+func (p *Polygon) DistanceTo(point *Point) float64 {
+	if !p.IsClosed() {
+		return -1
+	}
+
+	if p.Contains(point) {
+		return 0
+	}
+
+	return point.GreatCircleDistance(p.ClosestBoundaryPoint(point))
+}
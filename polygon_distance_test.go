@@ -0,0 +1,49 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+// Ensures that the distance to a polygon is measured to the nearest point of its boundary, and is 0 inside it.
+func TestPolygonDistanceTo(t *testing.T) {
+	square := boxPolygon(0, 0, 1, 1)
+
+	if d := square.DistanceTo(NewPoint(0.5, 0.5)); d != 0 {
+		t.Errorf("Expected a point inside to be 0km away, got %f", d)
+	}
+
+	// Due east of the east edge, the nearest point is level with the point.
+	p := NewPoint(0.5, 2)
+	if d, expected := square.DistanceTo(p), p.GreatCircleDistance(NewPoint(0.5, 1)); math.Abs(d-expected) > 0.01 {
+		t.Errorf("Expected %fkm, got %f", expected, d)
+	}
+
+	// Beyond a corner, the corner is nearest.
+	p = NewPoint(-1, -1)
+	if closest := square.ClosestBoundaryPoint(p); closest.Lat() != 0 || closest.Lng() != 0 {
+		t.Errorf("Expected the corner to be nearest, got %v", closest)
+	}
+
+	// Inside, the nearest boundary point is still found.
+	if closest := square.ClosestBoundaryPoint(NewPoint(0.9, 0.5)); math.Abs(closest.Lat()-1) > 1e-4 || math.Abs(closest.Lng()-0.5) > 1e-4 {
+		t.Errorf("Expected the north edge to be nearest, got %v", closest)
+	}
+
+	if d := NewPolygon([]*Point{NewPoint(0, 0)}).DistanceTo(p); d != -1 {
+		t.Errorf("Expected -1 for an open polygon, got %f", d)
+	}
+}
+
+// Ensures that polygons crossing the antimeridian are measured across it.
+func TestPolygonDistanceToAntimeridian(t *testing.T) {
+	fiji := boxPolygon(-21, 177, -12, -178)
+	if d := fiji.DistanceTo(NewPoint(-16.5, -179.9)); d != 0 {
+		t.Errorf("Expected a point inside to be 0km away, got %f", d)
+	}
+
+	p := NewPoint(-16.5, -177)
+	if d, expected := fiji.DistanceTo(p), p.GreatCircleDistance(NewPoint(-16.5, -178)); math.Abs(d-expected) > 0.5 {
+		t.Errorf("Expected about %fkm, got %f", expected, d)
+	}
+}
@@ -0,0 +1,177 @@
+package geo
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// This is the error that consumers receive when a PostalCodeIndex
+// holds no postal code matching the request.
+var ErrUnknownPostalCode = errors.New("unknown postal code")
+
+// A PostalCode is a postal code and the place it is centered on,
+// as published in GeoNames' postal code dumps.
+type PostalCode struct {
+	CountryCode string
+	Code        string
+	PlaceName   string
+	State       string
+	StateCode   string
+	County      string
+	Point       *Point
+
+	// GeoNames' accuracy of Point: 1 for an estimate, 4 for a geonameid, 6 for a centroid
+	// of addresses or shape.  Zero if unknown.
+	Accuracy int
+}
+
+// A PostalCodeIndex answers postal code lookups from data loaded into it,
+// so that ZIP level geocoding needs no API calls.
+// A PostalCodeIndex is safe for concurrent use once loaded.
+type PostalCodeIndex struct {
+	mu    sync.Mutex
+	codes map[string]*PostalCode
+	tree  *KDTree[*PostalCode]
+}
+
+// Creates and returns a pointer to a new, empty PostalCodeIndex.
+func NewPostalCodeIndex() *PostalCodeIndex {
+	return &PostalCodeIndex{
+		codes: make(map[string]*PostalCode),
+		tree:  NewKDTree[*PostalCode](),
+	}
+}
+
+// Returns the key the passed in postal code is indexed under.  Letters are
+// case insensitive, and spaces and hyphens are ignored, so "sw1a 1aa" finds "SW1A 1AA".
+func postalCodeKey(country, code string) string {
+	code = strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, code)
+
+	return strings.ToUpper(strings.TrimSpace(country)) + "|" + strings.ToUpper(code)
+}
+
+// Adds the passed in postal code to the index.  GeoNames lists some postal codes
+// once per place they serve; the first one added is returned by lookups.
+func (idx *PostalCodeIndex) Add(pc *PostalCode) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	key := postalCodeKey(pc.CountryCode, pc.Code)
+	if _, ok := idx.codes[key]; !ok {
+		idx.codes[key] = pc
+	}
+
+	idx.tree.Insert(pc.Point, pc)
+}
+
+// Returns the number of postal codes added to the index, counting duplicates.
+func (idx *PostalCodeIndex) Len() int {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	return idx.tree.Len()
+}
+
+// Reads postal codes in GeoNames' tab separated format, as found in the files
+// at https://download.geonames.org/export/zip/, into a new PostalCodeIndex.
+// Rows without a valid latitude and longitude are rejected.
+func LoadGeoNamesPostalCodes(r io.Reader) (*PostalCodeIndex, error) {
+	idx := NewPostalCodeIndex()
+	if err := idx.LoadGeoNames(r); err != nil {
+		return nil, err
+	}
+
+	return idx, nil
+}
+
+// Reads postal codes in GeoNames' tab separated format into the current PostalCodeIndex,
+// so that files for several countries can be combined.
+func (idx *PostalCodeIndex) LoadGeoNames(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		// country code, postal code, place name, admin name1, admin code1,
+		// admin name2, admin code2, admin name3, admin code3, latitude, longitude, accuracy
+		fields := strings.Split(text, "\t")
+		if len(fields) < 11 {
+			return fmt.Errorf("postal codes line %d: expected at least 11 fields, got %d", line, len(fields))
+		}
+
+		lat, err := strconv.ParseFloat(fields[9], 64)
+		if err != nil {
+			return fmt.Errorf("postal codes line %d: %v", line, err)
+		}
+
+		lng, err := strconv.ParseFloat(fields[10], 64)
+		if err != nil {
+			return fmt.Errorf("postal codes line %d: %v", line, err)
+		}
+
+		pc := &PostalCode{
+			CountryCode: fields[0],
+			Code:        fields[1],
+			PlaceName:   fields[2],
+			State:       fields[3],
+			StateCode:   fields[4],
+			County:      fields[5],
+			Point:       NewPoint(lat, lng),
+		}
+		if len(fields) > 11 {
+			pc.Accuracy, _ = strconv.Atoi(fields[11])
+		}
+
+		idx.Add(pc)
+	}
+
+	idx.tree.Build()
+	return scanner.Err()
+}
+
+// Returns the postal code matching the passed in ISO 3166-1 alpha-2 country code and postal code,
+// and whether or not there was one.
+func (idx *PostalCodeIndex) Lookup(country, code string) (*PostalCode, bool) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	pc, ok := idx.codes[postalCodeKey(country, code)]
+	return pc, ok
+}
+
+// Returns the center of the passed in postal code in the passed in country,
+// or ErrUnknownPostalCode if the index doesn't hold it.
+func (idx *PostalCodeIndex) PostalCodeToPoint(country, code string) (*Point, error) {
+	pc, ok := idx.Lookup(country, code)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s %s", ErrUnknownPostalCode, country, code)
+	}
+
+	return pc.Point, nil
+}
+
+// Returns the postal code whose center is nearest the passed in point,
+// or ErrUnknownPostalCode if the index is empty.
+func (idx *PostalCodeIndex) NearestPostalCode(p *Point) (*PostalCode, error) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	result, ok := idx.tree.Nearest(p)
+	if !ok {
+		return nil, ErrUnknownPostalCode
+	}
+
+	return result.Value, nil
+}
@@ -0,0 +1,95 @@
+package geo
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// Returns a PostalCodeIndex loaded from the test postal codes.
+func loadTestPostalCodes(t *testing.T) *PostalCodeIndex {
+	f, err := os.Open("test/data/postal_codes.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	idx, err := LoadGeoNamesPostalCodes(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return idx
+}
+
+// Ensures that postal codes are looked up by country and code, ignoring case and spacing.
+func TestPostalCodeToPoint(t *testing.T) {
+	idx := loadTestPostalCodes(t)
+	if idx.Len() != 5 {
+		t.Errorf("Expected 5 postal codes, got %d", idx.Len())
+	}
+
+	p, err := idx.PostalCodeToPoint("us", "80302")
+	if err != nil || p.Lat() != 40.0172 || p.Lng() != -105.2851 {
+		t.Errorf("Expected the first 80302 to be returned, got %v (%v)", p, err)
+	}
+
+	pc, ok := idx.Lookup("GB", "sw1a1aa")
+	if !ok || pc.PlaceName != "London" || pc.County != "Greater London" || pc.Accuracy != 6 {
+		t.Errorf("Unexpected postal code: %+v", pc)
+	}
+
+	if _, err := idx.PostalCodeToPoint("US", "00000"); !errors.Is(err, ErrUnknownPostalCode) {
+		t.Errorf("Expected ErrUnknownPostalCode, got %v", err)
+	}
+
+	if _, err := idx.PostalCodeToPoint("GB", "80302"); !errors.Is(err, ErrUnknownPostalCode) {
+		t.Errorf("Expected postal codes to be looked up within their country, got %v", err)
+	}
+}
+
+// Ensures that files for several countries can be combined in one index.
+func TestPostalCodeIndexLoadGeoNames(t *testing.T) {
+	idx := loadTestPostalCodes(t)
+
+	f, err := os.Open("test/data/postal_codes_nl.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if err := idx.LoadGeoNames(f); err != nil {
+		t.Fatal(err)
+	}
+
+	if pc, ok := idx.Lookup("NL", "1012-JS"); !ok || pc.PlaceName != "Amsterdam" {
+		t.Errorf("Expected to find Amsterdam, got %+v", pc)
+	}
+}
+
+// Ensures that the nearest postal code center is found.
+func TestNearestPostalCode(t *testing.T) {
+	idx := loadTestPostalCodes(t)
+
+	pc, err := idx.NearestPostalCode(NewPoint(37.78, -122.41))
+	if err != nil || pc.Code != "94103" {
+		t.Errorf("Expected 94103, got %+v (%v)", pc, err)
+	}
+
+	if _, err := NewPostalCodeIndex().NearestPostalCode(NewPoint(0, 0)); !errors.Is(err, ErrUnknownPostalCode) {
+		t.Errorf("Expected ErrUnknownPostalCode from an empty index, got %v", err)
+	}
+}
+
+// Ensures that malformed rows are rejected with their line number.
+func TestLoadGeoNamesPostalCodesErrors(t *testing.T) {
+	for _, input := range []string{
+		"US\t80302\tBoulder\n",
+		"US\t80302\tBoulder\tColorado\tCO\tBoulder\t013\t\t\tnorth\t-105.2851\t4\n",
+	} {
+		if _, err := LoadGeoNamesPostalCodes(strings.NewReader(input)); err == nil || !strings.Contains(err.Error(), "line 1") {
+			t.Errorf("Expected an error on line 1 for %q, got %v", input, err)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package geo
+
+import (
+	"bytes"
+	"strconv"
+	"sync/atomic"
+)
+
+// The number of decimal places coordinates are written with unless told otherwise.
+// Seven decimal places of a degree are about a centimeter, finer than any GPS fix.
+const DEFAULT_COORDINATE_PRECISION = 7
+
+// The precision that writes every coordinate with as many decimal places as represent it exactly.
+const FULL_PRECISION = -1
+
+// The number of decimal places set with SetCoordinatePrecision, offset by DEFAULT_COORDINATE_PRECISION
+// so that the zero value is the default.
+var coordinatePrecision atomic.Int32
+
+// Sets the number of decimal places coordinates are written with, for the whole package: in the JSON
+// and text of Points, in GeoJSON, and in CSV.  Fewer decimal places make smaller payloads, at the cost
+// of accuracy: five are about a meter, three about a hundred meters.  FULL_PRECISION, or any negative
+// number, writes coordinates exactly.  Serializers that take a precision of their own override it.
+// Applications should call it once at startup, before writing coordinates.
+func SetCoordinatePrecision(decimals int) {
+	if decimals < 0 {
+		decimals = FULL_PRECISION
+	}
+
+	coordinatePrecision.Store(int32(decimals - DEFAULT_COORDINATE_PRECISION))
+}
+
+// Returns the number of decimal places coordinates are written with, as set with SetCoordinatePrecision,
+// or FULL_PRECISION if they are written exactly.
+func CoordinatePrecision() int {
+	return int(coordinatePrecision.Load()) + DEFAULT_COORDINATE_PRECISION
+}
+
+// Returns the passed in coordinate rounded to the passed in number of decimal places, or written exactly
+// if it is negative, without trailing zeros: FormatCoordinate(-122.38997912, 5) is "-122.38998".
+func FormatCoordinate(f float64, decimals int) string {
+	return string(appendCoordinatePrecision(nil, f, decimals))
+}
+
+// Appends the passed in coordinate to dst, as FormatCoordinate writes it.
+func appendCoordinatePrecision(dst []byte, f float64, decimals int) []byte {
+	if decimals < 0 {
+		return strconv.AppendFloat(dst, f, 'f', -1, 64)
+	}
+
+	start := len(dst)
+	dst = strconv.AppendFloat(dst, f, 'f', decimals, 64)
+	if decimals > 0 {
+		dst = bytes.TrimRight(dst, "0")
+		dst = bytes.TrimSuffix(dst, []byte("."))
+	}
+
+	// Coordinates that round to zero from below would otherwise be written as "-0".
+	if string(dst[start:]) == "-0" {
+		dst = append(dst[:start], '0')
+	}
+
+	return dst
+}
+
+// Appends the passed in coordinate to dst with the package-wide precision.
+func appendCoordinate(dst []byte, f float64) []byte {
+	return appendCoordinatePrecision(dst, f, CoordinatePrecision())
+}
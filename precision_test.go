@@ -0,0 +1,67 @@
+package geo
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+// Ensures that coordinates are rounded to the requested number of decimal places without trailing zeros.
+func TestFormatCoordinate(t *testing.T) {
+	cases := []struct {
+		f        float64
+		decimals int
+		expected string
+	}{
+		{-122.38997912, 5, "-122.38998"},
+		{-122.38997912, FULL_PRECISION, "-122.38997912"},
+		{37.615223456789, DEFAULT_COORDINATE_PRECISION, "37.6152235"},
+		{40.5, 7, "40.5"},
+		{12, 3, "12"},
+		{-0.000000001, 7, "0"},
+		{12.6, 0, "13"},
+	}
+
+	for _, c := range cases {
+		if s := FormatCoordinate(c.f, c.decimals); s != c.expected {
+			t.Errorf("Expected %v at %d decimals to be %s, got %s", c.f, c.decimals, c.expected, s)
+		}
+	}
+}
+
+// Ensures that the package-wide precision applies to points, GeoJSON and CSV.
+func TestCoordinatePrecision(t *testing.T) {
+	defer SetCoordinatePrecision(DEFAULT_COORDINATE_PRECISION)
+
+	if p := CoordinatePrecision(); p != DEFAULT_COORDINATE_PRECISION {
+		t.Errorf("Expected a default precision of %d, got %d", DEFAULT_COORDINATE_PRECISION, p)
+	}
+
+	p := NewPoint(37.615223456789, -122.389979123456)
+	if data, _ := json.Marshal(p); string(data) != `{"lat":37.6152235,"lng":-122.3899791}` {
+		t.Errorf("Expected 7 decimal places by default, got %s", data)
+	}
+
+	SetCoordinatePrecision(3)
+	if data, _ := json.Marshal(NewFeature(p)); !bytes.Contains(data, []byte(`"coordinates":[-122.39,37.615]`)) {
+		t.Errorf("Expected GeoJSON with 3 decimal places, got %s", data)
+	}
+
+	var buf bytes.Buffer
+	sink := NewCSVPointSink(&buf)
+	sink.Write(p)
+	sink.Flush()
+	if buf.String() != "lat,lng\n37.615,-122.39\n" {
+		t.Errorf("Expected CSV with 3 decimal places, got %q", buf.String())
+	}
+
+	SetCoordinatePrecision(FULL_PRECISION)
+	if data, _ := json.Marshal(p); string(data) != `{"lat":37.615223456789,"lng":-122.389979123456}` {
+		t.Errorf("Expected every digit at full precision, got %s", data)
+	}
+
+	data, _ := (&FeatureCollection{Features: []*Feature{NewFeature(p)}}).MarshalGeoJSON(1)
+	if !bytes.Contains(data, []byte(`"coordinates":[-122.4,37.6]`)) {
+		t.Errorf("Expected the collection's own precision to override the package's, got %s", data)
+	}
+}
@@ -0,0 +1,111 @@
+package geo
+
+import (
+	"math"
+)
+
+// A PreparedPolygon is a Polygon indexed for answering many Contains calls quickly, such as for
+// testing millions of points against a country's outline.  Its edges are sorted into bands of longitude,
+// so that a point is only tested against the edges of its own band, rather than every edge.
+// It holds its own copy of the Polygon's points, so that later changes to the Polygon aren't seen,
+// and is safe for use by multiple goroutines.
+type PreparedPolygon struct {
+	polygon *Polygon
+
+	// The polygon's points, with longitudes made continuous if it crosses the antimeridian.
+	points       []*Point
+	antimeridian bool
+
+	// The edges, by the index of their first point, overlapping each band of longitude from west eastwards.
+	west, east, width float64
+	bands             [][]int
+}
+
+// Creates and returns a pointer to a new PreparedPolygon of the passed in Polygon.
+func NewPreparedPolygon(p *Polygon) *PreparedPolygon {
+	copied := p.copy()
+	prepared := &PreparedPolygon{polygon: copied, points: copied.points}
+	if !copied.IsClosed() {
+		return prepared
+	}
+
+	if crossesAntimeridian(copied.points, true) {
+		prepared.points, prepared.antimeridian = unwrapLngs(copied.points), true
+	}
+
+	west, east := math.Inf(1), math.Inf(-1)
+	for _, point := range prepared.points {
+		west, east = math.Min(west, point.lng), math.Max(east, point.lng)
+	}
+
+	// As many bands as there are edges keeps each band to a few edges, for all but the most uneven outlines.
+	n := len(prepared.points)
+	prepared.west, prepared.east, prepared.width = west, east, (east-west)/float64(n)
+	if prepared.width == 0 {
+		prepared.width = 1
+	}
+
+	prepared.bands = make([][]int, n)
+	for i, start := range prepared.points {
+		end := prepared.points[(i+1)%n]
+		first, last := prepared.band(math.Min(start.lng, end.lng)), prepared.band(math.Max(start.lng, end.lng))
+		for b := first; b <= last; b++ {
+			prepared.bands[b] = append(prepared.bands[b], i)
+		}
+	}
+
+	return prepared
+}
+
+// Returns the band of the passed in longitude, which must lie within the polygon's longitudes.
+func (p *PreparedPolygon) band(lng float64) int {
+	b := int((lng - p.west) / p.width)
+	if b >= len(p.bands) {
+		return len(p.bands) - 1
+	}
+
+	return int(math.Max(float64(b), 0))
+}
+
+// Returns the Polygon the current PreparedPolygon was prepared from, which is a copy of the one passed
+// to NewPreparedPolygon.  It must not be changed.
+func (p *PreparedPolygon) Polygon() *Polygon {
+	return p.polygon
+}
+
+// Returns whether or not the current PreparedPolygon contains the passed in point, giving the same answers
+// as Polygon.Contains but in time proportional to the number of edges around the point's longitude.
+func (p *PreparedPolygon) Contains(point *Point) bool {
+	if p.bands == nil {
+		return false
+	}
+
+	if !p.antimeridian {
+		return p.contains(point)
+	}
+
+	for _, shift := range []float64{0, 360, -360} {
+		if p.contains(NewPoint(point.lat, point.lng+shift)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns whether or not the polygon's ring contains the passed in point, casting a ray across the edges
+// of its band as Polygon.contains does across all of them.
+func (p *PreparedPolygon) contains(point *Point) bool {
+	if point.lng < p.west || point.lng > p.east {
+		return false
+	}
+
+	contains := false
+	for _, i := range p.bands[p.band(point.lng)] {
+		if p.polygon.intersectsWithRaycast(point, p.points[i], p.points[(i+1)%len(p.points)]) {
+			contains = !contains
+		}
+	}
+
+	return contains
+}
@@ -0,0 +1,72 @@
+package geo
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// Ensures that a PreparedPolygon contains exactly the points its Polygon does.
+func TestPreparedPolygonContains(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	polygons := []*Polygon{
+		starPolygon(10, 20, 500, 5, 1),
+		boxPolygon(-10, 170, 10, -170),
+		boxPolygon(0, 0, 1, 1),
+		NewPolygon([]*Point{NewPoint(0, 0), NewPoint(0, 0), NewPoint(1, 0)}),
+	}
+
+	for _, polygon := range polygons {
+		prepared := NewPreparedPolygon(polygon)
+		for i := 0; i < 10000; i++ {
+			p := NewPoint(r.Float64()*40-10, r.Float64()*360-180)
+			if i%2 == 0 {
+				p = NewPoint(10+r.Float64()*12-6, 20+r.Float64()*12-6)
+			}
+			if prepared.Contains(p) != polygon.Contains(p) {
+				t.Fatalf("Expected the prepared polygon to contain %v as the polygon does: %t", p, polygon.Contains(p))
+			}
+		}
+
+		// Points on the vertices and at the extremes are answered alike too.
+		for _, p := range polygon.Points() {
+			if prepared.Contains(p) != polygon.Contains(p) {
+				t.Errorf("Expected the prepared polygon to contain its vertex %v as the polygon does", p)
+			}
+		}
+	}
+
+	open := NewPolygon([]*Point{NewPoint(0, 0), NewPoint(1, 1)})
+	if NewPreparedPolygon(open).Contains(NewPoint(0.5, 0.5)) {
+		t.Error("Expected an open prepared polygon to contain nothing")
+	}
+}
+
+// Ensures that a PreparedPolygon doesn't see later changes to its Polygon.
+func TestPreparedPolygonCopies(t *testing.T) {
+	square := boxPolygon(0, 0, 1, 1)
+	prepared := NewPreparedPolygon(square)
+	square.Points()[2].lat = 5
+
+	if prepared.Contains(NewPoint(3, 0.9)) || prepared.Polygon().Points()[2].lat != 1 {
+		t.Error("Expected the prepared polygon to keep its own copy of the points")
+	}
+}
+
+// A ragged outline of 4000 short edges, like a country's.
+func BenchmarkPolygonContains(b *testing.B) {
+	star := starPolygon(10, 20, 2000, 5, 4.9)
+	p := NewPoint(11, 21)
+	for i := 0; i < b.N; i++ {
+		star.Contains(p)
+	}
+}
+
+// The outline of BenchmarkPolygonContains, prepared.
+func BenchmarkPreparedPolygonContains(b *testing.B) {
+	prepared := NewPreparedPolygon(starPolygon(10, 20, 2000, 5, 4.9))
+	p := NewPoint(11, 21)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		prepared.Contains(p)
+	}
+}
@@ -0,0 +1,37 @@
+package geo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// Ensures that requests go through the configured proxy, and that a nil proxy connects directly.
+func TestWithProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		w.Write([]byte(`[]`))
+	}))
+	defer proxy.Close()
+
+	proxyURL, _ := url.Parse(proxy.URL)
+	NewMapQuestGeocoder(WithBaseURL("http://nominatim.invalid"), WithProxy(proxyURL)).Geocode("SFO")
+	if !strings.HasPrefix(proxied, "http://nominatim.invalid/") {
+		t.Errorf("Expected the request to go through the proxy, got %q", proxied)
+	}
+
+	g := NewMapQuestGeocoder(WithProxy(nil))
+	if transport, ok := g.HTTPClient.Transport.(*http.Transport); !ok || transport.Proxy != nil {
+		t.Errorf("Expected a direct transport, got %v", g.HTTPClient.Transport)
+	}
+
+	socks, _ := url.Parse("socks5://proxy.corp:1080")
+	g = NewMapQuestGeocoder(WithProxy(socks))
+	req, _ := http.NewRequest("GET", "https://example.com", nil)
+	if got, _ := g.HTTPClient.Transport.(*http.Transport).Proxy(req); got.String() != socks.String() {
+		t.Errorf("Expected %s, got %v", socks, got)
+	}
+}
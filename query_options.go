@@ -0,0 +1,148 @@
+package geo
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The settings a QueryOption may change for a single geocoding request.
+type queryConfig struct {
+	language   string
+	region     string
+	bounds     *Bounds
+	components map[string]string
+	sensor     *bool
+	params     url.Values
+}
+
+// A QueryOption changes a single request to a Geocoder, such as asking for results in another language
+// or preferring results in a region.  Options a provider has no use for are ignored, so the same options
+// can be passed to any Geocoder.
+type QueryOption func(q *queryConfig)
+
+// Returns a queryConfig with the passed in options applied.
+func newQueryConfig(opts []QueryOption) *queryConfig {
+	q := &queryConfig{}
+	for _, opt := range opts {
+		opt(q)
+	}
+
+	return q
+}
+
+// Returns the passed in options as a string, the same for options asking for the same request
+// in any order, or "" for no options, for telling apart the cached results of different requests.
+func queryKey(opts []QueryOption) string {
+	if len(opts) == 0 {
+		return ""
+	}
+
+	q := newQueryConfig(opts)
+	values := url.Values{}
+	for k, v := range q.params {
+		values["param:"+k] = v
+	}
+	if q.language != "" {
+		values.Set("language", q.language)
+	}
+	if q.region != "" {
+		values.Set("region", q.region)
+	}
+	if q.bounds != nil {
+		values.Set("bounds", q.boundsParam())
+	}
+	if len(q.components) > 0 {
+		values.Set("components", q.componentsParam())
+	}
+	if q.sensor != nil {
+		values.Set("sensor", strconv.FormatBool(*q.sensor))
+	}
+
+	return values.Encode()
+}
+
+// Returns the bounds as Google takes them: "south,west|north,east".
+func (q *queryConfig) boundsParam() string {
+	sw, ne := q.bounds.SouthWest(), q.bounds.NorthEast()
+	return fmt.Sprintf("%f,%f|%f,%f", sw.lat, sw.lng, ne.lat, ne.lng)
+}
+
+// Returns the component filters as Google takes them, in sorted order: "country:US|postal_code:11211".
+func (q *queryConfig) componentsParam() string {
+	filters := make([]string, 0, len(q.components))
+	for k, v := range q.components {
+		filters = append(filters, k+":"+v)
+	}
+	sort.Strings(filters)
+
+	return strings.Join(filters, "|")
+}
+
+// Sets the extra parameters of the query on the passed in values, replacing any of the same name.
+func (q *queryConfig) applyParams(values url.Values) {
+	for k, v := range q.params {
+		values[k] = v
+	}
+}
+
+// Adds each of the passed in extra parameters to the passed in values, unless they have one of the same name.
+func addParams(values, extra url.Values) {
+	for k, v := range extra {
+		if _, ok := values[k]; !ok {
+			values[k] = v
+		}
+	}
+}
+
+// Asks for results in the passed in language, e.g. "en" or "ja",
+// instead of the language the Geocoder was created with.
+func QueryLanguage(language string) QueryOption {
+	return func(q *queryConfig) {
+		q.language = language
+	}
+}
+
+// Prefers results in the passed in region, an ISO 3166-1 alpha-2 country code such as "GB",
+// so that "Cambridge" finds the one in England rather than the one in Massachusetts.
+func QueryRegion(region string) QueryOption {
+	return func(q *queryConfig) {
+		q.region = region
+	}
+}
+
+// Prefers results within the passed in bounds, such as the area shown on a map.
+func QueryBounds(b *Bounds) QueryOption {
+	return func(q *queryConfig) {
+		q.bounds = b
+	}
+}
+
+// Restricts results to those whose address components have the passed in values,
+// e.g. {"country": "US", "postal_code": "11211"}.  Understood by Google.
+func QueryComponents(components map[string]string) QueryOption {
+	return func(q *queryConfig) {
+		q.components = components
+	}
+}
+
+// Tells the provider whether or not the request's location came from a sensor, such as a GPS.
+// Understood by Google, which once required it and now ignores it, for servers that still expect it.
+func QuerySensor(sensor bool) QueryOption {
+	return func(q *queryConfig) {
+		q.sensor = &sensor
+	}
+}
+
+// Adds the passed in parameter to the request as it is, for provider features golang-geo
+// has no option for, e.g. Google's "result_type" or Nominatim's "extratags".
+func QueryParam(key, value string) QueryOption {
+	return func(q *queryConfig) {
+		if q.params == nil {
+			q.params = url.Values{}
+		}
+		q.params.Add(key, value)
+	}
+}
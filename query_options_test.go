@@ -0,0 +1,58 @@
+package geo
+
+import (
+	"testing"
+)
+
+// Ensures that the same options passed in any order make the same key, and different options different keys.
+func TestQueryKey(t *testing.T) {
+	if queryKey(nil) != "" {
+		t.Errorf("Expected no key for no options, got %q", queryKey(nil))
+	}
+
+	a := queryKey([]QueryOption{QueryLanguage("ja"), QueryComponents(map[string]string{"country": "JP", "locality": "Tokyo"})})
+	b := queryKey([]QueryOption{QueryComponents(map[string]string{"locality": "Tokyo", "country": "JP"}), QueryLanguage("ja")})
+	if a != b {
+		t.Errorf("Expected %q, got %q", a, b)
+	}
+
+	if c := queryKey([]QueryOption{QueryLanguage("en")}); c == a {
+		t.Errorf("Expected different options to make different keys, got %q for both", c)
+	}
+
+	if c := queryKey([]QueryOption{QueryParam("language", "ja")}); c == queryKey([]QueryOption{QueryLanguage("ja")}) {
+		t.Errorf("Expected extra params to be kept apart from options of the same name, got %q for both", c)
+	}
+}
+
+// Ensures that a later option replaces an earlier one of the same kind, and extra params accumulate.
+func TestNewQueryConfig(t *testing.T) {
+	q := newQueryConfig([]QueryOption{QueryRegion("US"), QueryRegion("GB"), QueryParam("a", "1"), QueryParam("a", "2"), QuerySensor(false)})
+	if q.region != "GB" {
+		t.Errorf("Expected region GB, got %q", q.region)
+	}
+
+	if len(q.params["a"]) != 2 {
+		t.Errorf("Expected 2 values of a, got %v", q.params["a"])
+	}
+
+	if q.sensor == nil || *q.sensor {
+		t.Errorf("Expected sensor false, got %v", q.sensor)
+	}
+}
+
+// Ensures that bounds and components are formatted as Google takes them.
+func TestQueryConfigParams(t *testing.T) {
+	q := newQueryConfig([]QueryOption{
+		QueryBounds(NewBounds(NewPoint(34.172684, -118.604794), NewPoint(34.236144, -118.500938))),
+		QueryComponents(map[string]string{"postal_code": "91364", "country": "US"}),
+	})
+
+	if q.boundsParam() != "34.172684,-118.604794|34.236144,-118.500938" {
+		t.Errorf("Unexpected bounds: %q", q.boundsParam())
+	}
+
+	if q.componentsParam() != "country:US|postal_code:91364" {
+		t.Errorf("Unexpected components: %q", q.componentsParam())
+	}
+}
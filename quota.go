@@ -0,0 +1,139 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// This is the error that consumers can compare against with errors.Is
+// when a geocoder refuses to issue a request because its daily budget is spent.
+var ErrBudgetExceeded = errors.New("daily budget exceeded")
+
+// Describes which provider ran out of budget and what that budget was.
+// Matches ErrBudgetExceeded and ErrQuota when used with errors.Is.
+type BudgetExceededError struct {
+	Provider string
+	Budget   int
+}
+
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("%s: daily budget of %d requests exceeded", e.Provider, e.Budget)
+}
+
+// Allows errors.Is(err, ErrBudgetExceeded) and errors.Is(err, ErrQuota) to succeed.
+func (e *BudgetExceededError) Is(target error) bool {
+	return target == ErrBudgetExceeded || target == ErrQuota
+}
+
+// A Quota keeps a count of the requests issued to each provider over the
+// current (UTC) day, and optionally caps that count with a daily budget.
+// A Quota is safe to share between goroutines and between geocoders.
+type Quota struct {
+	mu      sync.Mutex
+	day     string
+	usage   map[string]int
+	budgets map[string]int
+
+	// Used to determine the current day.  Overridable for testing.
+	now func() time.Time
+}
+
+// Creates and returns a pointer to a new Quota with no budgets set.
+func NewQuota() *Quota {
+	return &Quota{
+		usage:   make(map[string]int),
+		budgets: make(map[string]int),
+		now:     time.Now,
+	}
+}
+
+// Sets the maximum number of requests the passed in provider may issue per day.
+// A budget of zero or less removes any budget for the provider.
+func (q *Quota) SetDailyBudget(provider string, budget int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if budget <= 0 {
+		delete(q.budgets, provider)
+		return
+	}
+
+	q.budgets[provider] = budget
+}
+
+// Returns the number of requests the passed in provider has issued today.
+func (q *Quota) Usage(provider string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.rollover()
+	return q.usage[provider]
+}
+
+// Returns the number of requests the passed in provider may still issue today,
+// and whether or not the provider has a budget at all.
+func (q *Quota) Remaining(provider string) (int, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.rollover()
+	budget, ok := q.budgets[provider]
+	if !ok {
+		return 0, false
+	}
+
+	remaining := budget - q.usage[provider]
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return remaining, true
+}
+
+// Returns the names of the providers with a daily budget, sorted.
+func (q *Quota) providers() []string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	providers := make([]string, 0, len(q.budgets))
+	for provider := range q.budgets {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+
+	return providers
+}
+
+// Records a single request against each of the passed in providers.
+// Returns a *BudgetExceededError without recording anything if any of the
+// providers has already spent its daily budget.
+func (q *Quota) Spend(providers ...string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.rollover()
+	for _, provider := range providers {
+		if budget, ok := q.budgets[provider]; ok && q.usage[provider] >= budget {
+			return &BudgetExceededError{Provider: provider, Budget: budget}
+		}
+	}
+
+	for _, provider := range providers {
+		q.usage[provider]++
+	}
+
+	return nil
+}
+
+// Resets all usage counters if the day has changed since the last request.
+// Callers must hold q.mu.
+func (q *Quota) rollover() {
+	today := q.now().UTC().Format("2006-01-02")
+	if q.day != today {
+		q.day = today
+		q.usage = make(map[string]int)
+	}
+}
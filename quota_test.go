@@ -0,0 +1,96 @@
+package geo
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// Ensures that usage is counted per provider and that spending past the budget is refused.
+func TestQuotaSpend(t *testing.T) {
+	q := NewQuota()
+	q.SetDailyBudget("google", 2)
+
+	for i := 0; i < 2; i++ {
+		if err := q.Spend("google"); err != nil {
+			t.Errorf("Expected request %d to be within budget, but got %v", i+1, err)
+		}
+	}
+
+	err := q.Spend("google")
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Expected ErrBudgetExceeded once the budget was spent, but got %v", err)
+	}
+
+	budgetErr, ok := err.(*BudgetExceededError)
+	if !ok || budgetErr.Provider != "google" || budgetErr.Budget != 2 {
+		t.Errorf("Expected a *BudgetExceededError describing the google budget, but got %#v", err)
+	}
+
+	if q.Usage("google") != 2 {
+		t.Errorf("Expected refused requests not to be counted, but usage is %d", q.Usage("google"))
+	}
+
+	// Providers without a budget are counted but never refused.
+	for i := 0; i < 5; i++ {
+		if err := q.Spend("mapquest"); err != nil {
+			t.Errorf("Expected a provider without a budget to never be refused, but got %v", err)
+		}
+	}
+
+	if q.Usage("mapquest") != 5 {
+		t.Errorf("Expected mapquest usage to be 5, but got %d", q.Usage("mapquest"))
+	}
+}
+
+// Ensures that remaining budget is reported, and only for providers that have one.
+func TestQuotaRemaining(t *testing.T) {
+	q := NewQuota()
+	q.SetDailyBudget("google", 3)
+	q.Spend("google")
+
+	remaining, ok := q.Remaining("google")
+	if !ok || remaining != 2 {
+		t.Errorf("Expected 2 remaining google requests, but got %d (%v)", remaining, ok)
+	}
+
+	if _, ok := q.Remaining("mapquest"); ok {
+		t.Error("Expected mapquest to have no budget")
+	}
+
+	q.SetDailyBudget("google", 0)
+	if _, ok := q.Remaining("google"); ok {
+		t.Error("Expected a budget of 0 to remove the google budget")
+	}
+}
+
+// Ensures that usage counters reset when the UTC day changes.
+func TestQuotaRollover(t *testing.T) {
+	now := time.Date(2014, 10, 8, 23, 59, 0, 0, time.UTC)
+	q := NewQuota()
+	q.now = func() time.Time { return now }
+	q.SetDailyBudget("google", 1)
+
+	q.Spend("google")
+	if err := q.Spend("google"); err == nil {
+		t.Error("Expected the second request of the day to be refused")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if err := q.Spend("google"); err != nil {
+		t.Errorf("Expected the budget to reset on a new day, but got %v", err)
+	}
+}
+
+// Ensures that a geocoder with a spent budget refuses to issue any requests.
+func TestGeocoderRespectsQuota(t *testing.T) {
+	q := NewQuota()
+	q.SetDailyBudget("google", 1)
+	q.Spend("google")
+
+	g := &GoogleGeocoder{Quota: q}
+	_, err := g.Geocode("San Francisco International Airport")
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Expected GoogleGeocoder to return ErrBudgetExceeded, but got %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package geo
+
+import (
+	"math"
+	"math/rand"
+)
+
+// The number of points drawn from a polygon's bounding box, looking for one inside it,
+// before RandomPointInPolygon gives up.
+const RANDOM_POINT_ATTEMPTS = 10000
+
+// A PointSampler draws random points spread evenly over the surface of the earth within the areas it is given,
+// for load testing and simulating fleets.  Drawing latitudes evenly instead would crowd points towards the poles,
+// where each degree of latitude covers less of the earth.
+type PointSampler struct {
+	float64 func() float64
+}
+
+// Creates and returns a pointer to a new PointSampler drawing from a source of its own seeded with the passed in seed,
+// so that simulations seeded alike draw the same points.  Unlike the package's Random functions,
+// which share math/rand's source, it is not safe for use by multiple goroutines.
+func NewPointSampler(seed int64) *PointSampler {
+	return &PointSampler{float64: rand.New(rand.NewSource(seed)).Float64}
+}
+
+// The PointSampler the package's Random functions draw from.
+var defaultPointSampler = &PointSampler{float64: rand.Float64}
+
+// Returns a random point within the passed in Bounds, drawn from math/rand's shared source.
+// See PointSampler's InBounds.
+func RandomPointInBBox(b *Bounds) *Point {
+	return defaultPointSampler.InBounds(b)
+}
+
+// Returns a random point within the passed in Polygon, drawn from math/rand's shared source.
+// See PointSampler's InPolygon.
+func RandomPointInPolygon(p *Polygon) *Point {
+	return defaultPointSampler.InPolygon(p)
+}
+
+// Returns the passed in number of random points within the passed in radius, in kilometers, of the passed in point,
+// drawn from math/rand's shared source.  See PointSampler's Around.
+func RandomPointsAround(p *Point, radius float64, n int) []*Point {
+	return defaultPointSampler.Around(p, radius, n)
+}
+
+// Returns a random point within the passed in Bounds, each part of them as likely as any other of the same area.
+// Bounds whose west edge is east of their east edge are taken to cross the antimeridian.
+func (s *PointSampler) InBounds(b *Bounds) *Point {
+	west, east := NormalizeLng(b.sw.lng), NormalizeLng(b.ne.lng)
+	if west > east {
+		east += 360
+	}
+
+	return s.inBox(b.sw.lat, b.ne.lat, west, east)
+}
+
+// Returns a random point between the passed in latitudes and longitudes, which may run past 180.
+// The sine of the latitude, rather than the latitude itself, is drawn evenly, since bands of latitude
+// have areas in proportion to the difference of their sines.
+func (s *PointSampler) inBox(south, north, west, east float64) *Point {
+	sinSouth, sinNorth := math.Sin(south*math.Pi/180), math.Sin(north*math.Pi/180)
+	lat := math.Asin(sinSouth+s.float64()*(sinNorth-sinSouth)) * 180 / math.Pi
+	lng := west + s.float64()*(east-west)
+
+	return NewPoint(lat, NormalizeLng(lng))
+}
+
+// Returns a random point within the passed in Polygon, each part of it as likely as any other of the same area,
+// found by drawing points from its bounding box until one lies inside it, as Contains decides.
+// Returns nil for polygons that are not closed, or that cover so little of their bounding box
+// that none of RANDOM_POINT_ATTEMPTS points lies inside them.
+func (s *PointSampler) InPolygon(p *Polygon) *Point {
+	if !p.IsClosed() {
+		return nil
+	}
+
+	points := p.points
+	if crossesAntimeridian(points, true) {
+		points = unwrapLngs(points)
+	}
+
+	south, north, west, east := math.Inf(1), math.Inf(-1), math.Inf(1), math.Inf(-1)
+	for _, point := range points {
+		south, north = math.Min(south, point.lat), math.Max(north, point.lat)
+		west, east = math.Min(west, point.lng), math.Max(east, point.lng)
+	}
+
+	for i := 0; i < RANDOM_POINT_ATTEMPTS; i++ {
+		if point := s.inBox(south, north, west, east); p.Contains(point) {
+			return point
+		}
+	}
+
+	return nil
+}
+
+// Returns the passed in number of random points within the passed in radius, in kilometers, of the passed in point,
+// measured along great circles, each part of the circle as likely as any other of the same area.
+// The cosine of the angle between a point and the center, rather than the distance between them, is drawn evenly,
+// since the areas of caps around the center are in proportion to one less the cosines of their angles.
+func (s *PointSampler) Around(p *Point, radius float64, n int) []*Point {
+	cosRadius := math.Cos(math.Min(radius/EARTH_RADIUS, math.Pi))
+
+	points := make([]*Point, n)
+	for i := range points {
+		angle := math.Acos(1 - s.float64()*(1-cosRadius))
+		q := p.PointAtDistanceAndBearing(angle*EARTH_RADIUS, s.float64()*360)
+		points[i] = NewPoint(q.lat, NormalizeLng(q.lng))
+	}
+
+	return points
+}
@@ -0,0 +1,93 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+// Ensures that points drawn from bounds lie within them, spread evenly by area rather than by latitude.
+func TestRandomPointInBBox(t *testing.T) {
+	s := NewPointSampler(1)
+	northern := NewBounds(NewPoint(0, -180), NewPoint(90, 180))
+
+	// Half the northern hemisphere lies north of 30 degrees, though only a third of its latitudes do.
+	north := 0
+	for i := 0; i < 10000; i++ {
+		p := s.InBounds(northern)
+		if !northern.Contains(p) {
+			t.Fatalf("Expected %v to lie within the bounds", p)
+		}
+		if p.lat > 30 {
+			north++
+		}
+	}
+	if math.Abs(float64(north)/10000-0.5) > 0.02 {
+		t.Errorf("Expected half the points north of 30 degrees, got %d in 10000", north)
+	}
+
+	pacific := NewBounds(NewPoint(-10, 170), NewPoint(10, -170))
+	for i := 0; i < 1000; i++ {
+		if p := RandomPointInBBox(pacific); !pacific.Contains(p) {
+			t.Fatalf("Expected %v to lie within the bounds across the antimeridian", p)
+		}
+	}
+}
+
+// Ensures that samplers seeded alike draw the same points.
+func TestPointSamplerSeed(t *testing.T) {
+	a, b := NewPointSampler(42), NewPointSampler(42)
+	center := NewPoint(37.615223, -122.389979)
+	for i, p := range a.Around(center, 10, 100) {
+		if q := b.Around(center, 10, 1)[0]; *p != *q {
+			t.Fatalf("Expected point %d to be %v, got %v", i, p, q)
+		}
+	}
+}
+
+// Ensures that points drawn from a polygon lie within it, and that none are drawn from an open one.
+func TestRandomPointInPolygon(t *testing.T) {
+	s := NewPointSampler(1)
+	for _, polygon := range []*Polygon{starPolygon(10, 20, 5, 2, 0.5), boxPolygon(-21, 177, -12, -178)} {
+		for i := 0; i < 1000; i++ {
+			if p := s.InPolygon(polygon); p == nil || !polygon.Contains(p) {
+				t.Fatalf("Expected a point within the polygon, got %v", p)
+			}
+		}
+	}
+
+	if p := RandomPointInPolygon(boxPolygon(0, 0, 1, 1)); p == nil || p.lat < 0 || p.lat > 1 || p.lng < 0 || p.lng > 1 {
+		t.Errorf("Expected a point within the square, got %v", p)
+	}
+
+	if p := s.InPolygon(NewPolygon([]*Point{NewPoint(0, 0), NewPoint(1, 1)})); p != nil {
+		t.Errorf("Expected no point within an open polygon, got %v", p)
+	}
+}
+
+// Ensures that points drawn around a point lie within the radius, spread evenly by area.
+func TestRandomPointsAround(t *testing.T) {
+	center := NewPoint(60, 179.99)
+	points := NewPointSampler(1).Around(center, 50, 10000)
+	if len(points) != 10000 {
+		t.Fatalf("Expected 10000 points, got %d", len(points))
+	}
+
+	// A quarter of a circle's area lies within half its radius.
+	inner := 0
+	for _, p := range points {
+		d := center.GreatCircleDistance(p)
+		if d > 50+1e-9 || p.lng < -180 || p.lng > 180 {
+			t.Fatalf("Expected %v within 50km of the center, got %fkm", p, d)
+		}
+		if d < 25 {
+			inner++
+		}
+	}
+	if math.Abs(float64(inner)/10000-0.25) > 0.02 {
+		t.Errorf("Expected a quarter of the points within half the radius, got %d in 10000", inner)
+	}
+
+	if points := RandomPointsAround(center, 1, 0); len(points) != 0 {
+		t.Errorf("Expected no points, got %v", points)
+	}
+}
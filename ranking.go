@@ -0,0 +1,233 @@
+package geo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// The default distance, in kilometers, at which a candidate's proximity score halves.
+const DEFAULT_RANKING_DISTANCE_SCALE = 50.0
+
+// This is the error that consumers receive when none
+// of a RankingGeocoder's providers returned a candidate.  Matches ErrNotFound.
+var rankingNoResultsError = fmt.Errorf("no provider returned a result: %w", ErrNotFound)
+
+// This is the error that a RankingGeocoder with no providers fails its health check with.
+var rankingNoProvidersError = errors.New("no providers")
+
+// A Ranker re-scores geocoding candidates, possibly from several providers,
+// by how close they are to a focus point and how closely their address
+// resembles the query.  The zero value ranks on similarity alone.
+type Ranker struct {
+	// If set, candidates nearer to this point are preferred.
+	Focus *Point
+
+	// The distance, in kilometers, from Focus at which a candidate's proximity
+	// score halves.  Defaults to DEFAULT_RANKING_DISTANCE_SCALE.
+	DistanceScale float64
+
+	// The relative weights given to proximity and to string similarity.
+	// If both are zero, they are weighted equally.
+	DistanceWeight   float64
+	SimilarityWeight float64
+}
+
+// Sets the Confidence of each of the passed in results, and sorts them
+// from most to least confident.  Candidates with equal confidence keep
+// their original order, so each provider's own preferences break ties.
+func (r *Ranker) Rank(query string, results []*GeocodeResult) {
+	for _, res := range results {
+		res.Confidence = r.score(query, res)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Confidence > results[j].Confidence
+	})
+}
+
+// Returns the unified confidence score of the passed in result, from 0 to 1.
+func (r *Ranker) score(query string, res *GeocodeResult) float64 {
+	similarity := querySimilarity(query, res.FormattedAddress)
+	if r.Focus == nil || res.Point == nil {
+		return similarity
+	}
+
+	scale := r.DistanceScale
+	if scale <= 0 {
+		scale = DEFAULT_RANKING_DISTANCE_SCALE
+	}
+	proximity := 1 / (1 + r.Focus.GreatCircleDistance(res.Point)/scale)
+
+	dw, sw := r.DistanceWeight, r.SimilarityWeight
+	if dw <= 0 && sw <= 0 {
+		dw, sw = 1, 1
+	}
+
+	return (dw*proximity + sw*similarity) / (dw + sw)
+}
+
+// Returns how closely the passed in address resembles the query, from 0 to 1.
+// Providers usually return far more of an address than was asked for, so
+// an address that contains every word of the query scores highly even
+// if the address as a whole is quite different.
+func querySimilarity(query, address string) float64 {
+	similarity := MatchName(query, address)
+
+	queryTokens := nameTokens(NormalizeName(query))
+	if len(queryTokens) == 0 {
+		return similarity
+	}
+
+	addressTokens := make(map[string]bool)
+	for _, token := range nameTokens(NormalizeName(address)) {
+		addressTokens[token] = true
+	}
+
+	found := 0
+	for _, token := range queryTokens {
+		if addressTokens[token] {
+			found++
+		}
+	}
+
+	return max(similarity, float64(found)/float64(len(queryTokens)))
+}
+
+// A RankingGeocoder asks each of its providers for candidates at the same time,
+// and ranks all of them together with its Ranker.
+type RankingGeocoder struct {
+	Providers []ResultGeocoder
+	Ranker    Ranker
+
+	// If set, candidates of a worse quality are discarded before ranking.
+	MinQuality MatchQuality
+
+	// If set, Geocode and ReverseGeocode hedge their requests for latency-sensitive lookups:
+	// rather than waiting for every provider, or for each to fail in turn, they ask the first provider,
+	// ask the next whenever the last has taken HedgeDelay without answering, or has failed,
+	// and return the first answer.  Slower providers' answers are discarded.
+	HedgeDelay time.Duration
+}
+
+// Creates and returns a pointer to a new RankingGeocoder over the passed in providers,
+// preferring candidates near to the passed in focus point.  The focus may be nil.
+func NewRankingGeocoder(focus *Point, providers ...ResultGeocoder) *RankingGeocoder {
+	return &RankingGeocoder{Providers: providers, Ranker: Ranker{Focus: focus}}
+}
+
+// Returns every provider's candidates for the passed in query, ranked from most
+// to least confident.  Providers that fail are skipped; an error is only returned
+// if no provider returned a good enough candidate, in which case it is the first provider's error.
+// QueryOptions are passed on to every provider.
+func (g *RankingGeocoder) GeocodeResults(query string, opts ...QueryOption) ([]*GeocodeResult, error) {
+	found := make([][]*GeocodeResult, len(g.Providers))
+	errs := make([]error, len(g.Providers))
+
+	var wg sync.WaitGroup
+	for i, provider := range g.Providers {
+		wg.Add(1)
+		go func(i int, provider ResultGeocoder) {
+			defer wg.Done()
+			found[i], errs[i] = provider.GeocodeResults(query, opts...)
+		}(i, provider)
+	}
+	wg.Wait()
+
+	var results []*GeocodeResult
+	for _, r := range found {
+		results = append(results, FilterByQuality(r, g.MinQuality)...)
+	}
+
+	if len(results) == 0 {
+		for _, err := range errs {
+			if err != nil {
+				return nil, err
+			}
+		}
+		return nil, rankingNoResultsError
+	}
+
+	g.Ranker.Rank(query, results)
+	return results, nil
+}
+
+// Returns the location of the most confident candidate for the passed in query.
+// With a HedgeDelay, it is the most confident candidate of the first provider to answer.
+func (g *RankingGeocoder) Geocode(query string, opts ...QueryOption) (*Point, error) {
+	if g.HedgeDelay > 0 {
+		return hedge(len(g.Providers), g.HedgeDelay, func(i int) (*Point, error) {
+			results, err := g.Providers[i].GeocodeResults(query, opts...)
+			if err != nil {
+				return nil, err
+			}
+
+			results = FilterByQuality(results, g.MinQuality)
+			if len(results) == 0 {
+				return nil, rankingNoResultsError
+			}
+
+			g.Ranker.Rank(query, results)
+			return results[0].Point, nil
+		})
+	}
+
+	results, err := g.GeocodeResults(query, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return results[0].Point, nil
+}
+
+// Returns nil if any of the providers is healthy, since the others are skipped while they fail,
+// or every provider's HealthCheck error otherwise.  Providers that aren't HealthCheckers count as healthy.
+func (g *RankingGeocoder) HealthCheck(ctx context.Context) error {
+	if len(g.Providers) == 0 {
+		return rankingNoProvidersError
+	}
+
+	errs := make([]error, len(g.Providers))
+
+	var wg sync.WaitGroup
+	for i, provider := range g.Providers {
+		wg.Add(1)
+		go func(i int, provider ResultGeocoder) {
+			defer wg.Done()
+			errs[i] = healthCheck(ctx, provider)
+		}(i, provider)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err == nil {
+			return nil
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Returns the address of the passed in point from the first provider able to
+// reverse geocode it, trying providers in order, or with a HedgeDelay, from the first to answer.
+func (g *RankingGeocoder) ReverseGeocode(p *Point, opts ...QueryOption) (string, error) {
+	if g.HedgeDelay > 0 {
+		return hedge(len(g.Providers), g.HedgeDelay, func(i int) (string, error) {
+			return g.Providers[i].ReverseGeocode(p, opts...)
+		})
+	}
+
+	err := rankingNoResultsError
+	for _, provider := range g.Providers {
+		var address string
+		address, err = provider.ReverseGeocode(p, opts...)
+		if err == nil {
+			return address, nil
+		}
+	}
+
+	return "", err
+}
@@ -0,0 +1,151 @@
+package geo
+
+import (
+	"errors"
+	"testing"
+)
+
+// A ResultGeocoder that returns a fixed set of candidates or a fixed error.
+type stubResultGeocoder struct {
+	stubGeocoder
+	results []*GeocodeResult
+}
+
+func (s *stubResultGeocoder) GeocodeResults(query string, opts ...QueryOption) ([]*GeocodeResult, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return s.results, nil
+}
+
+// Returns the candidates in the passed in MapQuest fixture.
+func mapquestFixtureResults(t *testing.T, file string) []*GeocodeResult {
+	data, err := GetMockResponse(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := (&MapQuestGeocoder{}).extractResultsFromResponse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return results
+}
+
+// Ensures that candidates nearer the focus point are preferred
+// when their addresses resemble the query equally well.
+func TestRankerPrefersFocus(t *testing.T) {
+	pichari := &GeocodeResult{Point: NewPoint(-12.52, -73.83), FormattedAddress: "San Francisco Airport, Pichari, Peru"}
+	sfo := &GeocodeResult{Point: NewPoint(37.6152, -122.3899), FormattedAddress: "San Francisco Airport, Millbrae, California"}
+	results := []*GeocodeResult{pichari, sfo}
+
+	r := &Ranker{Focus: NewPoint(37.7749, -122.4194)}
+	r.Rank("San Francisco Airport", results)
+
+	if results[0] != sfo {
+		t.Errorf("Expected the candidate near the focus first, got %s", results[0].FormattedAddress)
+	}
+
+	if results[0].Confidence <= results[1].Confidence {
+		t.Errorf("Expected descending confidence, got %f then %f", results[0].Confidence, results[1].Confidence)
+	}
+
+	for _, res := range results {
+		if res.Confidence < 0 || res.Confidence > 1 {
+			t.Errorf("Expected a confidence between 0 and 1, got %f", res.Confidence)
+		}
+	}
+}
+
+// Ensures that without a focus point, candidates are ranked on similarity to the query alone.
+func TestRankerSimilarity(t *testing.T) {
+	bedford := &GeocodeResult{Point: NewPoint(40.71, -73.96), FormattedAddress: "285 Bedford Avenue, Brooklyn, NY 11211, USA"}
+	other := &GeocodeResult{Point: NewPoint(40.71, -73.96), FormattedAddress: "Williamsburg, Brooklyn, NY, USA"}
+	results := []*GeocodeResult{other, bedford}
+
+	r := &Ranker{}
+	r.Rank("285 Bedford Ave", results)
+
+	if results[0] != bedford {
+		t.Errorf("Expected the closest matching address first, got %s", results[0].FormattedAddress)
+	}
+
+	if results[0].Confidence != 1 {
+		t.Errorf("Expected an address containing the whole query to score 1, got %f", results[0].Confidence)
+	}
+}
+
+// Ensures that a RankingGeocoder merges every provider's candidates,
+// skips failing providers, and returns the most confident candidate.
+func TestRankingGeocoder(t *testing.T) {
+	mapquest := &stubResultGeocoder{results: mapquestFixtureResults(t, "test/data/mapquest_geocode_success.json")}
+	failing := &stubResultGeocoder{stubGeocoder: stubGeocoder{err: errors.New("unavailable")}}
+	gazetteer := NewGazetteerGeocoder([]GazetteerEntry{{Name: "Warehouse SFO-01", Lat: 37.62, Lng: -122.38}})
+
+	g := NewRankingGeocoder(NewPoint(37.7749, -122.4194), failing, mapquest, gazetteer)
+	results, err := g.GeocodeResults("San Francisco Airport")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("Expected the 2 MapQuest candidates, got %d", len(results))
+	}
+
+	if results[0].Provider != "mapquest" || results[0].Point.Lat() != 37.62181845 {
+		t.Errorf("Expected SFO first, got %s", results[0].FormattedAddress)
+	}
+
+	p, err := g.Geocode("Warehouse SFO 01")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p.Lat() != 37.62 || p.Lng() != -122.38 {
+		t.Errorf("Expected the gazetteer entry, got %v", p)
+	}
+}
+
+// Ensures that a RankingGeocoder returns the first provider's error
+// when no provider returns a candidate.
+func TestRankingGeocoderNoResults(t *testing.T) {
+	unavailable := errors.New("unavailable")
+	g := NewRankingGeocoder(nil,
+		&stubResultGeocoder{stubGeocoder: stubGeocoder{err: unavailable}},
+		&stubResultGeocoder{},
+	)
+
+	if _, err := g.Geocode("anything"); err != unavailable {
+		t.Errorf("Expected %v, got %v", unavailable, err)
+	}
+
+	if _, err := NewRankingGeocoder(nil).Geocode("anything"); err != rankingNoResultsError {
+		t.Errorf("Expected %v, got %v", rankingNoResultsError, err)
+	}
+}
+
+// Ensures that a RankingGeocoder reverse geocodes with the first provider that succeeds.
+func TestRankingGeocoderReverseGeocode(t *testing.T) {
+	sfo := NewPoint(37.6152, -122.3899)
+	failing := &stubResultGeocoder{stubGeocoder: stubGeocoder{err: errors.New("unavailable")}}
+	working := &stubResultGeocoder{stubGeocoder: stubGeocoder{addresses: map[string]string{reverseCacheKey(sfo): "SFO"}}}
+
+	address, err := NewRankingGeocoder(nil, failing, working).ReverseGeocode(sfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if address != "SFO" {
+		t.Errorf("Expected SFO, got %s", address)
+	}
+}
+
+// Ensures that the providers satisfy the ResultGeocoder interface.
+var (
+	_ ResultGeocoder = &GoogleGeocoder{}
+	_ ResultGeocoder = &MapQuestGeocoder{}
+	_ ResultGeocoder = &GazetteerGeocoder{}
+	_ ResultGeocoder = &RankingGeocoder{}
+)
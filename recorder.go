@@ -0,0 +1,208 @@
+package geo
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Describes whether a Recorder talks to the network, to disk, or both.
+type RecorderMode int
+
+const (
+	// Replays a recorded interaction if there is one, otherwise
+	// issues the request and records the interaction.
+	ReplayOrRecord RecorderMode = iota
+
+	// Only replays recorded interactions, never touching the network.
+	// Requests that were never recorded fail with ErrNotRecorded.
+	Replay
+
+	// Always issues requests, recording (and overwriting) every interaction.
+	Record
+)
+
+// This is the error that consumers receive when a Recorder in Replay mode
+// is asked for a request that it has no recording of.
+var ErrNotRecorded = errors.New("no recorded interaction for request")
+
+// A Recorder is an http.RoundTripper that records provider responses to disk
+// and replays them later, so that tests and CI runs need neither network access
+// nor API keys.  Interactions are keyed by the request method and its URL with
+// sorted query parameters and without credentials.
+//
+// To use it, give a geocoder an HTTPClient whose Transport is a Recorder:
+//
+//	g := &geo.GoogleGeocoder{HTTPClient: geo.NewRecorder("test/fixtures", geo.ReplayOrRecord).Client()}
+type Recorder struct {
+	// The directory in which interactions are stored, one JSON file each.
+	Dir string
+
+	Mode RecorderMode
+
+	// The transport used to issue requests that aren't replayed.
+	// Defaults to the transport shared by every provider.
+	Transport http.RoundTripper
+}
+
+// A single recorded request and its response, as stored on disk.
+type recordedInteraction struct {
+	Request struct {
+		Method string `json:"method"`
+		URL    string `json:"url"`
+	} `json:"request"`
+	Response struct {
+		StatusCode int         `json:"status_code"`
+		Header     http.Header `json:"header"`
+		Body       string      `json:"body"`
+	} `json:"response"`
+}
+
+// Creates and returns a pointer to a new Recorder storing interactions in dir.
+func NewRecorder(dir string, mode RecorderMode) *Recorder {
+	return &Recorder{Dir: dir, Mode: mode}
+}
+
+// Returns an http.Client that issues every request through the Recorder.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Replays or records the passed in request according to the Recorder's mode.
+// Implements the http.RoundTripper interface.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := RecordingKey(req)
+	path := filepath.Join(r.Dir, recordingFilename(key))
+
+	if r.Mode != Record {
+		interaction, err := r.load(path)
+		if err == nil {
+			return interaction.response(req), nil
+		}
+
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		if r.Mode == Replay {
+			return nil, fmt.Errorf("%w: %s", ErrNotRecorded, key)
+		}
+	}
+
+	transport := r.Transport
+	if transport == nil {
+		transport = defaultTransport()
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Store bodies decompressed, so that recordings stay readable and replay without their Content-Encoding.
+	decoded, err := decodeBody(resp.Header, resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	header := resp.Header.Clone()
+	if header.Get("Content-Encoding") != "" {
+		header.Del("Content-Encoding")
+		header.Del("Content-Length")
+	}
+
+	interaction := &recordedInteraction{}
+	interaction.Request.Method = req.Method
+	interaction.Request.URL = scrubURL(req.URL).String()
+	interaction.Response.StatusCode = resp.StatusCode
+	interaction.Response.Header = header
+	interaction.Response.Body = string(body)
+
+	if err := r.save(path, interaction); err != nil {
+		return nil, err
+	}
+
+	return interaction.response(req), nil
+}
+
+// Returns the normalized form of a request used to identify its recording:
+// the method and URL, with query parameters sorted and credentials removed.
+func RecordingKey(req *http.Request) string {
+	u := scrubURL(req.URL)
+	query := u.Query()
+	for _, param := range credentialParams {
+		query.Del(param)
+	}
+
+	// url.Values.Encode sorts by key.
+	u.RawQuery = query.Encode()
+	return req.Method + " " + u.String()
+}
+
+// Returns the name of the file that stores the interaction with the passed in key.
+func recordingFilename(key string) string {
+	sum := sha1.Sum([]byte(key))
+	return hex.EncodeToString(sum[:]) + ".json"
+}
+
+// Reads the interaction stored at path.
+func (r *Recorder) load(path string) (*recordedInteraction, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := &recordedInteraction{}
+	if err := json.Unmarshal(data, interaction); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	return interaction, nil
+}
+
+// Writes the passed in interaction to path, creating the Recorder's directory if necessary.
+func (r *Recorder) save(path string, interaction *recordedInteraction) error {
+	if err := os.MkdirAll(r.Dir, 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(interaction, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// Returns an http.Response to the passed in request built from the recorded response.
+func (i *recordedInteraction) response(req *http.Request) *http.Response {
+	header := i.Response.Header
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", i.Response.StatusCode, http.StatusText(i.Response.StatusCode)),
+		StatusCode:    i.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(i.Response.Body)),
+		ContentLength: int64(len(i.Response.Body)),
+		Request:       req,
+	}
+}
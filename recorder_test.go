@@ -0,0 +1,106 @@
+package geo
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// A RoundTripper that fails every request, standing in for a missing network.
+type offlineTransport struct{}
+
+func (offlineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, errors.New("offline")
+}
+
+// Ensures that recording keys ignore parameter order and credentials.
+func TestRecordingKey(t *testing.T) {
+	a, _ := http.NewRequest("GET", "http://example.com/geocode?b=2&a=1&key=secret", nil)
+	b, _ := http.NewRequest("GET", "http://example.com/geocode?a=1&signature=abc&b=2&client=me", nil)
+
+	if RecordingKey(a) != RecordingKey(b) {
+		t.Errorf("Expected equivalent requests to share a key, got %q and %q", RecordingKey(a), RecordingKey(b))
+	}
+
+	if strings.Contains(RecordingKey(a), "secret") {
+		t.Errorf("Expected credentials to be left out of the key, got %q", RecordingKey(a))
+	}
+}
+
+// Ensures that interactions are recorded once, replayed without the network,
+// and stored without credentials.
+func TestRecorderRecordAndReplay(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		data, _ := GetMockResponse("test/data/google_geocode_success.json")
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	g := &GoogleGeocoder{HTTPClient: NewRecorder(dir, ReplayOrRecord).Client(), BaseURL: server.URL}
+
+	for i := 0; i < 2; i++ {
+		p, err := g.Geocode("San Francisco International Airport")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if p.Lat() != 37.615223 {
+			t.Errorf("Expected the recorded latitude, got %f", p.Lat())
+		}
+	}
+
+	if hits != 1 {
+		t.Errorf("Expected the second request to be replayed, but the server was hit %d times", hits)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("Expected 1 recording, found %d", len(files))
+	}
+
+	// Replaying must work without the network.
+	replayer := NewRecorder(dir, Replay)
+	replayer.Transport = offlineTransport{}
+	g = &GoogleGeocoder{HTTPClient: replayer.Client(), BaseURL: server.URL}
+
+	if _, err := g.Geocode("San Francisco International Airport"); err != nil {
+		t.Errorf("Expected the recording to be replayed offline, got %v", err)
+	}
+
+	if _, err := g.Geocode("Los Angeles International Airport"); !errors.Is(err, ErrNotRecorded) {
+		t.Errorf("Expected ErrNotRecorded for an unrecorded request, got %v", err)
+	}
+}
+
+// Ensures that credentials never make it into recordings.
+func TestRecorderScrubsCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"results":[],"status":"ZERO_RESULTS"}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	client := NewRecorder(dir, Record).Client()
+	resp, err := client.Get(server.URL + "?latlng=1,2&key=supersecret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("Expected 1 recording, found %d", len(files))
+	}
+
+	data, _ := ioutil.ReadFile(files[0])
+	if strings.Contains(string(data), "supersecret") {
+		t.Errorf("Expected the API key to be scrubbed from the recording, got %s", data)
+	}
+}
@@ -0,0 +1,74 @@
+package geo
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// The query parameters that carry credentials: Google's API key, Premier client id and signature,
+// IPinfo's token, OpenWeatherMap's app id, Open-Meteo's commercial API key, and the access tokens
+// and API keys of XYZ tile servers such as Mapbox, Thunderforest and Stadia.  They are left out of
+// recording keys and scrubbed from recordings, logs, errors and debug dumps, so that none of them
+// reveal the credentials they were made with.
+var credentialParams = []string{"key", "signature", "client", "token", "appid", "apikey", "api_key", "access_token"}
+
+// The request headers that carry credentials, scrubbed from debug dumps.
+var credentialHeaders = []string{"Authorization", "X-API-Key", "X-Goog-Api-Key", "Proxy-Authorization"}
+
+// Returns a copy of the passed in URL with the values of any credential parameters,
+// and any password, redacted.
+func scrubURL(u *url.URL) *url.URL {
+	scrubbed := *u
+	query := scrubbed.Query()
+	for _, param := range credentialParams {
+		if _, ok := query[param]; ok {
+			query.Set(param, "REDACTED")
+		}
+	}
+	scrubbed.RawQuery = query.Encode()
+
+	if _, ok := u.User.Password(); ok {
+		scrubbed.User = url.UserPassword(u.User.Username(), "REDACTED")
+	}
+
+	return &scrubbed
+}
+
+// Returns the passed in URL with the values of any credential parameters redacted, for logging URLs
+// such as those SignGoogleURL returns.  Strings that aren't URLs are returned as they are.
+func RedactURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil {
+		return s
+	}
+
+	return scrubURL(u).String()
+}
+
+// Returns a copy of the passed in header with the values of any credential headers redacted.
+func scrubHeader(h http.Header) http.Header {
+	scrubbed := h.Clone()
+	for _, name := range credentialHeaders {
+		if scrubbed.Get(name) != "" {
+			scrubbed.Set(name, "REDACTED")
+		}
+	}
+
+	return scrubbed
+}
+
+// Returns the passed in error with the credentials redacted from its URL if it is a *url.Error,
+// as the errors http.Client returns are, since they quote the URL they failed to fetch.
+func scrubError(err error) error {
+	ue, ok := err.(*url.Error)
+	if !ok {
+		return err
+	}
+
+	u, parseErr := url.Parse(ue.URL)
+	if parseErr != nil {
+		return err
+	}
+
+	return &url.Error{Op: ue.Op, URL: scrubURL(u).String(), Err: ue.Err}
+}
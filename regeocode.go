@@ -0,0 +1,97 @@
+package geo
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A Drift is a cached geocode whose coordinates a provider no longer agrees with.
+type Drift struct {
+	// The normalized query, as cached.
+	Query string
+
+	// The cached Point, and where the provider now puts the query.
+	Cached  *Point
+	Current *Point
+
+	// The distance between Cached and Current, in kilometers.
+	Distance float64
+
+	// When Cached was stored.
+	Stored time.Time
+}
+
+// A DriftReport lists the cached geocodes whose coordinates drifted when Regeocode asked the provider again.
+type DriftReport struct {
+	// The number of cached geocodes asked for again.
+	Checked int
+
+	// The geocodes that moved further than the threshold, in query order.
+	Drifted []*Drift
+
+	// The errors of the queries the provider could no longer geocode, by normalized query.
+	Failed map[string]error
+}
+
+// Geocodes every cached query again with the passed in Geocoder, which should not itself be
+// a CachedGeocoder of this cache, and reports those whose result moved more than the passed in
+// number of kilometers, because the provider's data changed.  The cache is left as it is;
+// callers wanting the new coordinates can Put the Current Point of each Drift.
+// Geocodes cached with QueryOptions aren't checked, since their options aren't kept.
+func (c *GeocodeCache) Regeocode(g Geocoder, threshold float64) (*DriftReport, error) {
+	// Collect the entries first, so that the cache isn't held open for the duration of the requests.
+	var entries []*CacheEntry
+	err := c.each(func(entry *CacheEntry) error {
+		if strings.HasPrefix(entry.Key, "geocode:") && !strings.Contains(entry.Key, "?") {
+			entries = append(entries, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DriftReport{Failed: make(map[string]error)}
+	for _, entry := range entries {
+		query := strings.TrimPrefix(entry.Key, "geocode:")
+		report.Checked++
+
+		current, err := g.Geocode(query)
+		if err != nil {
+			report.Failed[query] = err
+			continue
+		}
+
+		cached := NewPoint(entry.Lat, entry.Lng)
+		if distance := cached.GreatCircleDistance(current); distance > threshold {
+			report.Drifted = append(report.Drifted, &Drift{Query: query, Cached: cached, Current: current, Distance: distance, Stored: entry.Stored})
+		}
+	}
+
+	return report, nil
+}
+
+// Writes the drifted geocodes to w as CSV, with a header row of
+// query, cached_lat, cached_lng, current_lat, current_lng, distance_km, stored.
+// Coordinates are written with CoordinatePrecision decimal places.
+func (r *DriftReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"query", "cached_lat", "cached_lng", "current_lat", "current_lng", "distance_km", "stored"})
+
+	format := func(f float64) string { return FormatCoordinate(f, CoordinatePrecision()) }
+	for _, d := range r.Drifted {
+		cw.Write([]string{
+			d.Query,
+			format(d.Cached.lat), format(d.Cached.lng),
+			format(d.Current.lat), format(d.Current.lng),
+			strconv.FormatFloat(d.Distance, 'f', 3, 64),
+			d.Stored.Format(time.RFC3339),
+		})
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
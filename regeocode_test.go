@@ -0,0 +1,53 @@
+package geo
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// Ensures that cached geocodes the provider has since moved are reported, and the rest aren't.
+func TestRegeocode(t *testing.T) {
+	c := openTestCache(t, 0, 0)
+	c.Put("SFO", NewPoint(37.615223, -122.389979))
+	c.Put("JFK", NewPoint(40.641311, -73.778139))
+	c.Put("Atlantis", NewPoint(0, 0))
+	c.PutAddress(NewPoint(37.615223, -122.389979), "San Francisco International Airport")
+
+	g := &stubGeocoder{points: map[string]*Point{
+		"sfo": NewPoint(37.615300, -122.389900),
+		"jfk": NewPoint(40.6, -73.7),
+	}}
+
+	report, err := c.Regeocode(g, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Checked != 3 || len(report.Drifted) != 1 || len(report.Failed) != 1 {
+		t.Fatalf("Expected 3 checked, 1 drifted and 1 failed, got %d, %d and %d", report.Checked, len(report.Drifted), len(report.Failed))
+	}
+
+	if d := report.Drifted[0]; d.Query != "jfk" || d.Distance < 5 || d.Distance > 10 {
+		t.Errorf("Expected jfk to drift between 5 and 10 km, got %s by %f", d.Query, d.Distance)
+	}
+
+	if !errors.Is(report.Failed["atlantis"], ErrNotFound) {
+		t.Errorf("Expected atlantis to fail with %v, got %v", ErrNotFound, report.Failed["atlantis"])
+	}
+
+	if p, _ := c.Get("JFK"); p.Lat() != 40.641311 {
+		t.Errorf("Expected the cache to be left as it was, got %v", p)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[1], "jfk,40.641311,-73.778139,40.6,-73.7,") {
+		t.Errorf("Expected a header and jfk, got %q", buf.String())
+	}
+}
@@ -0,0 +1,410 @@
+package geo
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// The locations of a point relative to a geometry, which are also the rows and columns
+// of a DE-9IM intersection matrix.
+const (
+	relateInterior = iota
+	relateBoundary
+	relateExterior
+)
+
+// The distance, in degrees, within which points are taken to coincide, so that rounding
+// in computed intersections doesn't separate them.  It is about a hundredth of a millimeter.
+const RELATE_TOLERANCE = 1e-10
+
+// The distance, in degrees, from a polygon's edge at which the areas on either side of it are sampled.
+const relateSampleOffset = 1e-8
+
+// A geometry reduced to its planar linework, with longitude as x and latitude as y.
+type relateGeometry struct {
+	// 0 for a point, 1 for a line and 2 for a polygon, or -1 if the geometry is empty.
+	dim    int
+	points []*Point
+
+	// Whether a line ends where it starts, and so has no boundary.
+	closed bool
+}
+
+// Returns the passed in *Point, Line or *Polygon as a relateGeometry.  Nil points and repeated
+// points are dropped, and geometries left with too few points for their type are demoted,
+// so that a polygon of two points is a line and a line of one point is a point.
+// Other geometries, and nil ones, are empty.
+func newRelateGeometry(g Geometry) *relateGeometry {
+	var points []*Point
+	closed := false
+	switch g := g.(type) {
+	case *Point:
+		points = []*Point{g}
+	case Line:
+		points = g
+	case *Polygon:
+		if g != nil {
+			points, closed = g.points, true
+		}
+	}
+
+	var distinct []*Point
+	for _, p := range points {
+		if p != nil && (len(distinct) == 0 || !coincide(p, distinct[len(distinct)-1])) {
+			distinct = append(distinct, p)
+		}
+	}
+
+	ends := len(distinct) > 1 && coincide(distinct[0], distinct[len(distinct)-1])
+	if closed && ends {
+		distinct = distinct[:len(distinct)-1]
+	}
+
+	r := &relateGeometry{points: distinct}
+	switch {
+	case len(distinct) == 0:
+		r.dim = -1
+		return r
+	case len(distinct) == 1:
+		r.dim = 0
+	case closed && len(distinct) > 2:
+		r.dim = 2
+	default:
+		r.dim, r.closed = 1, ends && len(distinct) > 2
+	}
+
+	if crossesAntimeridian(distinct, r.dim == 2) {
+		r.points = unwrapLngs(distinct)
+	}
+
+	return r
+}
+
+// Returns whether or not the passed in points lie within RELATE_TOLERANCE of each other.
+func coincide(p, q *Point) bool {
+	return math.Hypot(p.lng-q.lng, p.lat-q.lat) <= RELATE_TOLERANCE
+}
+
+// Returns the westernmost and easternmost longitudes of the current geometry.
+func (r *relateGeometry) lngRange() (float64, float64) {
+	west, east := math.Inf(1), math.Inf(-1)
+	for _, p := range r.points {
+		west, east = math.Min(west, p.lng), math.Max(east, p.lng)
+	}
+
+	return west, east
+}
+
+// Moves the current geometry by whole turns of longitude so that it lies as near as it can to the passed in one,
+// for geometries on either side of the antimeridian whose longitudes were unwrapped differently.
+func (r *relateGeometry) alignTo(other *relateGeometry) {
+	if r.dim < 0 || other.dim < 0 {
+		return
+	}
+
+	west, east := r.lngRange()
+	otherWest, otherEast := other.lngRange()
+	shift := 360 * math.Round(((otherWest+otherEast)-(west+east))/2/360)
+	if shift == 0 {
+		return
+	}
+
+	shifted := make([]*Point, len(r.points))
+	for i, p := range r.points {
+		shifted[i] = &Point{lat: p.lat, lng: p.lng + shift}
+	}
+	r.points = shifted
+}
+
+// Returns the edges of the current geometry as pairs of points, including the edge closing a polygon.
+func (r *relateGeometry) segments() [][2]*Point {
+	var segments [][2]*Point
+	for i := 1; i < len(r.points); i++ {
+		segments = append(segments, [2]*Point{r.points[i-1], r.points[i]})
+	}
+	if r.dim == 2 {
+		segments = append(segments, [2]*Point{r.points[len(r.points)-1], r.points[0]})
+	}
+
+	return segments
+}
+
+// Returns whether the passed in point lies in the interior, on the boundary or in the exterior
+// of the current geometry.  A line's boundary is its two ends, unless it is closed,
+// and a polygon's is its ring.
+func (r *relateGeometry) locate(p *Point) int {
+	switch r.dim {
+	case 0:
+		if coincide(p, r.points[0]) {
+			return relateInterior
+		}
+	case 1:
+		if !r.closed && (coincide(p, r.points[0]) || coincide(p, r.points[len(r.points)-1])) {
+			return relateBoundary
+		}
+		for _, s := range r.segments() {
+			if planarSegmentDistance(p, s[0], s[1]) <= RELATE_TOLERANCE {
+				return relateInterior
+			}
+		}
+	case 2:
+		for _, s := range r.segments() {
+			if planarSegmentDistance(p, s[0], s[1]) <= RELATE_TOLERANCE {
+				return relateBoundary
+			}
+		}
+		if NewPolygon(r.points).contains(r.points, p) {
+			return relateInterior
+		}
+	}
+
+	return relateExterior
+}
+
+// Returns the fraction of the way along the segment from a to b of the point on it nearest to p.
+func segmentFraction(p, a, b *Point) float64 {
+	dx, dy := b.lng-a.lng, b.lat-a.lat
+	length := dx*dx + dy*dy
+	if length == 0 {
+		return 0
+	}
+
+	return math.Max(0, math.Min(1, ((p.lng-a.lng)*dx+(p.lat-a.lat)*dy)/length))
+}
+
+// Returns the planar distance, in degrees, from p to the segment from a to b.
+func planarSegmentDistance(p, a, b *Point) float64 {
+	t := segmentFraction(p, a, b)
+	return math.Hypot(p.lng-(a.lng+t*(b.lng-a.lng)), p.lat-(a.lat+t*(b.lat-a.lat)))
+}
+
+// Returns the points at which the segment from a to b meets the segment from c to d:
+// none, the single point they cross or touch at, or the ends of the stretch along which they overlap.
+func segmentIntersections(a, b, c, d *Point) []*Point {
+	// Segments that touch or overlap have an end lying on the other.
+	var points []*Point
+	for _, p := range []*Point{c, d} {
+		if planarSegmentDistance(p, a, b) <= RELATE_TOLERANCE {
+			points = append(points, p)
+		}
+	}
+	for _, p := range []*Point{a, b} {
+		if planarSegmentDistance(p, c, d) <= RELATE_TOLERANCE {
+			points = append(points, p)
+		}
+	}
+	if len(points) > 0 || !segmentsIntersect(a.lng, a.lat, b.lng, b.lat, c.lng, c.lat, d.lng, d.lat) {
+		return points
+	}
+
+	rx, ry := b.lng-a.lng, b.lat-a.lat
+	sx, sy := d.lng-c.lng, d.lat-c.lat
+	t := ((c.lng-a.lng)*sy - (c.lat-a.lat)*sx) / (rx*sy - ry*sx)
+	return []*Point{{lat: a.lat + t*ry, lng: a.lng + t*rx}}
+}
+
+// A DE-9IM intersection matrix, holding the dimension of the intersection of the interior,
+// boundary and exterior of one geometry with each of those of another, or -1 where they don't meet.
+type intersectionMatrix struct {
+	cells      [3][3]int
+	dimA, dimB int
+}
+
+// Raises the dimension of the passed in cell to d, if it is lower.
+func (m *intersectionMatrix) set(row, col, d int) {
+	if d > m.cells[row][col] {
+		m.cells[row][col] = d
+	}
+}
+
+// Returns whether or not the passed in cell holds an intersection of any dimension.
+func (m *intersectionMatrix) meets(row, col int) bool {
+	return m.cells[row][col] >= 0
+}
+
+// Returns the current matrix in the usual form of nine characters, row by row,
+// each a dimension or F where the parts don't meet.
+func (m *intersectionMatrix) String() string {
+	var b strings.Builder
+	for _, row := range m.cells {
+		for _, d := range row {
+			if d < 0 {
+				b.WriteByte('F')
+			} else {
+				b.WriteByte(byte('0' + d))
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// Returns the intersection matrix of the passed in geometries.
+func relate(ga, gb Geometry) *intersectionMatrix {
+	a, b := newRelateGeometry(ga), newRelateGeometry(gb)
+	b.alignTo(a)
+
+	m := &intersectionMatrix{dimA: a.dim, dimB: b.dim}
+	for i := range m.cells {
+		for j := range m.cells[i] {
+			m.cells[i][j] = -1
+		}
+	}
+	m.cells[relateExterior][relateExterior] = 2
+
+	// Every vertex, and every point where the geometries' edges meet, is where the
+	// intersections of dimension 0 are, and where the edges are split.
+	nodes := append(append([]*Point(nil), a.points...), b.points...)
+	for _, sa := range a.segments() {
+		for _, sb := range b.segments() {
+			nodes = append(nodes, segmentIntersections(sa[0], sa[1], sb[0], sb[1])...)
+		}
+	}
+	for _, n := range nodes {
+		m.set(a.locate(n), b.locate(n), 0)
+	}
+
+	m.addLinework(a, b, a, b, nodes)
+	m.addLinework(b, a, a, b, nodes)
+	return m
+}
+
+// Records the intersections of the passed in geometry's edges with the other geometry.
+// Each edge is split at the nodes lying on it, so that each piece lies wholly in the interior,
+// on the boundary or in the exterior of the other geometry, as its midpoint does.
+// The areas on either side of a polygon's pieces are sampled likewise.
+func (m *intersectionMatrix) addLinework(g, other, a, b *relateGeometry, nodes []*Point) {
+	row := relateInterior
+	if g.dim == 2 {
+		row = relateBoundary
+	}
+
+	// A point on a polygon's boundary may be next to an area of it, but a point on a line
+	// is next to no area of the line.
+	areal := func(r *relateGeometry, loc int) bool {
+		return loc == relateExterior || (loc == relateInterior && r.dim == 2)
+	}
+
+	for _, s := range g.segments() {
+		fractions := []float64{0, 1}
+		for _, n := range nodes {
+			if planarSegmentDistance(n, s[0], s[1]) <= RELATE_TOLERANCE {
+				fractions = append(fractions, segmentFraction(n, s[0], s[1]))
+			}
+		}
+		sort.Float64s(fractions)
+
+		dx, dy := s[1].lng-s[0].lng, s[1].lat-s[0].lat
+		length := math.Hypot(dx, dy)
+		for i := 1; i < len(fractions); i++ {
+			if (fractions[i]-fractions[i-1])*length <= RELATE_TOLERANCE {
+				continue
+			}
+
+			t := (fractions[i-1] + fractions[i]) / 2
+			mid := &Point{lat: s[0].lat + t*dy, lng: s[0].lng + t*dx}
+			if g == a {
+				m.set(row, other.locate(mid), 1)
+			} else {
+				m.set(other.locate(mid), row, 1)
+			}
+
+			if g.dim != 2 {
+				continue
+			}
+			for _, side := range []float64{-1, 1} {
+				offset := side * relateSampleOffset / length
+				sample := &Point{lat: mid.lat + offset*dx, lng: mid.lng - offset*dy}
+				la, lb := a.locate(sample), b.locate(sample)
+				if areal(a, la) && areal(b, lb) {
+					m.set(la, lb, 2)
+				}
+			}
+		}
+	}
+}
+
+// Returns the DE-9IM intersection matrix of the passed in geometries, each a *Point, a Line or a *Polygon,
+// as nine characters: the dimension of the intersection of the interior, boundary and exterior of a
+// with the interior, boundary and exterior of b in turn, or F where they don't meet.
+// For example, a polygon and a point inside it relate as "0F2FF1FF2".
+//
+// Edges are taken to be straight in longitude and latitude, as Polygon's Contains takes them,
+// and may cross the antimeridian.  Points within RELATE_TOLERANCE of each other coincide.
+// A line's boundary is its two ends, or nothing if it ends where it starts,
+// and a polygon's is its ring.  Geometries of other types, and nil ones, are empty.
+// Each edge of a is compared with each edge of b, so that this takes time in proportion to the product
+// of their sizes; the predicates are best narrowed with a spatial index first when there are many geometries.
+func Relate(a, b Geometry) string {
+	return relate(a, b).String()
+}
+
+// Returns whether or not the passed in geometries share any point.
+func Intersects(a, b Geometry) bool {
+	return relate(a, b).intersects()
+}
+
+func (m *intersectionMatrix) intersects() bool {
+	return m.meets(relateInterior, relateInterior) || m.meets(relateInterior, relateBoundary) ||
+		m.meets(relateBoundary, relateInterior) || m.meets(relateBoundary, relateBoundary)
+}
+
+// Returns whether or not the passed in geometries share no point.  It is the opposite of Intersects.
+func Disjoint(a, b Geometry) bool {
+	return !Intersects(a, b)
+}
+
+// Returns whether or not no point of b lies outside a, and some point of b's interior lies
+// in a's interior.  A polygon doesn't contain a line running along its ring, for example,
+// nor a point on its ring.
+func Contains(a, b Geometry) bool {
+	m := relate(a, b)
+	return m.meets(relateInterior, relateInterior) &&
+		!m.meets(relateExterior, relateInterior) && !m.meets(relateExterior, relateBoundary)
+}
+
+// Returns whether or not a lies within b, which is whether or not b contains a.
+func Within(a, b Geometry) bool {
+	return Contains(b, a)
+}
+
+// Returns whether or not the passed in geometries meet only at their boundaries: they intersect,
+// but their interiors don't.  Two points never touch, since a point has no boundary.
+func Touches(a, b Geometry) bool {
+	m := relate(a, b)
+	return m.intersects() && !m.meets(relateInterior, relateInterior)
+}
+
+// Returns whether or not the interiors of the passed in geometries meet, in fewer dimensions
+// than the larger of them has, with each geometry reaching outside the other: a line crosses
+// a polygon if it passes through the polygon's interior and out of it, and two lines cross
+// if they meet at isolated points of their interiors.  Points and polygons of the same type never cross.
+func Crosses(a, b Geometry) bool {
+	m := relate(a, b)
+	switch {
+	case m.dimA < 0 || m.dimB < 0:
+		return false
+	case m.dimA == 1 && m.dimB == 1:
+		return m.cells[relateInterior][relateInterior] == 0
+	case m.dimA < m.dimB:
+		return m.meets(relateInterior, relateInterior) && m.meets(relateInterior, relateExterior)
+	case m.dimA > m.dimB:
+		return m.meets(relateInterior, relateInterior) && m.meets(relateExterior, relateInterior)
+	default:
+		return false
+	}
+}
+
+// Returns whether or not the passed in geometries, of the same dimension, share part of their interiors
+// of that dimension while each has part outside the other: two polygons overlap if they share some area
+// and neither contains the other, and two lines if they run together for a stretch.
+func Overlaps(a, b Geometry) bool {
+	m := relate(a, b)
+	if m.dimA != m.dimB || m.dimA < 0 {
+		return false
+	}
+
+	return m.cells[relateInterior][relateInterior] == m.dimA &&
+		m.meets(relateInterior, relateExterior) && m.meets(relateExterior, relateInterior)
+}
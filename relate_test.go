@@ -0,0 +1,163 @@
+package geo
+
+import (
+	"testing"
+)
+
+// Returns a line through the passed in coordinates, given as latitude and longitude pairs.
+func lineOf(coords ...float64) Line {
+	var line Line
+	for i := 0; i+1 < len(coords); i += 2 {
+		line = append(line, NewPoint(coords[i], coords[i+1]))
+	}
+
+	return line
+}
+
+// Ensures that intersection matrices match those of the usual reference implementations.
+func TestRelate(t *testing.T) {
+	square := boxPolygon(0, 0, 10, 10)
+	cases := []struct {
+		name     string
+		a, b     Geometry
+		expected string
+	}{
+		{"point in polygon", square, NewPoint(5, 5), "0F2FF1FF2"},
+		{"point on ring", square, NewPoint(0, 5), "FF20F1FF2"},
+		{"equal points", NewPoint(1, 1), NewPoint(1, 1), "0FFFFFFF2"},
+		{"distinct points", NewPoint(1, 1), NewPoint(2, 2), "FF0FFF0F2"},
+		{"point at line end", lineOf(0, 0, 0, 10), NewPoint(0, 10), "FF10F0FF2"},
+		{"overlapping polygons", square, boxPolygon(5, 5, 15, 15), "212101212"},
+		{"polygons sharing an edge", square, boxPolygon(0, 10, 10, 20), "FF2F11212"},
+		{"polygons sharing a corner", square, boxPolygon(10, 10, 20, 20), "FF2F01212"},
+		{"equal polygons", square, boxPolygon(0, 0, 10, 10), "2FFF1FFF2"},
+		{"line through polygon", lineOf(5, -5, 5, 15), square, "101FF0212"},
+		{"crossing lines", lineOf(-5, 0, 5, 0), lineOf(0, -5, 0, 5), "0F1FF0102"},
+		{"lines overlapping", lineOf(0, 0, 0, 10), lineOf(0, 5, 0, 15), "1010F0102"},
+		{"empty", Line{}, square, "FFFFFF212"},
+	}
+
+	for _, c := range cases {
+		if m := Relate(c.a, c.b); m != c.expected {
+			t.Errorf("Expected %s to relate as %s, got %s", c.name, c.expected, m)
+		}
+	}
+}
+
+// Ensures that each predicate holds for exactly the pairs of geometries it should, for every pair of types.
+func TestPredicates(t *testing.T) {
+	square := boxPolygon(0, 0, 10, 10)
+	cases := []struct {
+		name string
+		a, b Geometry
+
+		// Whether the pair intersects, a contains b, b contains a, and the pair touches, crosses and overlaps.
+		intersects, contains, within, touches, crosses, overlaps bool
+	}{
+		{"equal points", NewPoint(1, 1), NewPoint(1, 1), true, true, true, false, false, false},
+		{"distinct points", NewPoint(1, 1), NewPoint(1, 2), false, false, false, false, false, false},
+		{"point along line", lineOf(0, 0, 0, 10), NewPoint(0, 5), true, true, false, false, false, false},
+		{"point at line vertex", lineOf(0, 0, 5, 5, 0, 10), NewPoint(5, 5), true, true, false, false, false, false},
+		{"point at line end", lineOf(0, 0, 0, 10), NewPoint(0, 0), true, false, false, true, false, false},
+		{"point at closed line's ends", lineOf(0, 0, 0, 10, 10, 10, 0, 0), NewPoint(0, 0), true, true, false, false, false, false},
+		{"point off line", lineOf(0, 0, 0, 10), NewPoint(1, 5), false, false, false, false, false, false},
+		{"point in polygon", square, NewPoint(5, 5), true, true, false, false, false, false},
+		{"point on ring", square, NewPoint(0, 5), true, false, false, true, false, false},
+		{"point at corner", square, NewPoint(10, 10), true, false, false, true, false, false},
+		{"point outside polygon", square, NewPoint(15, 5), false, false, false, false, false, false},
+		{"crossing lines", lineOf(-5, 0, 5, 0), lineOf(0, -5, 0, 5), true, false, false, false, true, false},
+		{"lines meeting at ends", lineOf(0, 0, 0, 10), lineOf(0, 10, 5, 10), true, false, false, true, false, false},
+		{"line ending on another", lineOf(0, 0, 0, 10), lineOf(0, 5, 5, 5), true, false, false, true, false, false},
+		{"lines overlapping", lineOf(0, 0, 0, 10), lineOf(0, 5, 0, 15), true, false, false, false, false, true},
+		{"line within line", lineOf(0, 0, 0, 10), lineOf(0, 2, 0, 8), true, true, false, false, false, false},
+		{"equal lines", lineOf(0, 0, 0, 10), lineOf(0, 10, 0, 0), true, true, true, false, false, false},
+		{"parallel lines", lineOf(0, 0, 0, 10), lineOf(1, 0, 1, 10), false, false, false, false, false, false},
+		{"line in polygon", square, lineOf(2, 2, 8, 8), true, true, false, false, false, false},
+		{"line to ring", square, lineOf(5, 5, 10, 5), true, true, false, false, false, false},
+		{"line along ring", square, lineOf(0, 2, 0, 8), true, false, false, true, false, false},
+		{"line through polygon", square, lineOf(5, -5, 5, 15), true, false, false, false, true, false},
+		{"line leaving polygon", square, lineOf(5, 5, 5, 15), true, false, false, false, true, false},
+		{"line touching corner", square, lineOf(10, 10, 15, 15), true, false, false, true, false, false},
+		{"line outside polygon", square, lineOf(15, 0, 15, 10), false, false, false, false, false, false},
+		{"overlapping polygons", square, boxPolygon(5, 5, 15, 15), true, false, false, false, false, true},
+		{"nested polygons", square, boxPolygon(2, 2, 8, 8), true, true, false, false, false, false},
+		{"nested polygons sharing an edge", square, boxPolygon(0, 0, 5, 10), true, true, false, false, false, false},
+		{"equal polygons", square, NewPolygon([]*Point{NewPoint(10, 10), NewPoint(0, 10), NewPoint(0, 0), NewPoint(10, 0)}), true, true, true, false, false, false},
+		{"polygons sharing an edge", square, boxPolygon(0, 10, 10, 20), true, false, false, true, false, false},
+		{"polygons sharing part of an edge", square, boxPolygon(5, 10, 15, 20), true, false, false, true, false, false},
+		{"polygons sharing a corner", square, boxPolygon(10, 10, 20, 20), true, false, false, true, false, false},
+		{"distant polygons", square, boxPolygon(20, 20, 30, 30), false, false, false, false, false, false},
+		{"empty", square, Line{}, false, false, false, false, false, false},
+		{"nil", (*Polygon)(nil), NewPoint(5, 5), false, false, false, false, false, false},
+	}
+
+	for _, c := range cases {
+		if got := Intersects(c.a, c.b); got != c.intersects {
+			t.Errorf("Expected Intersects to be %t for %s, got %t", c.intersects, c.name, got)
+		}
+		if got := Disjoint(c.a, c.b); got == c.intersects {
+			t.Errorf("Expected Disjoint to be %t for %s, got %t", !c.intersects, c.name, got)
+		}
+		if got := Contains(c.a, c.b); got != c.contains {
+			t.Errorf("Expected Contains to be %t for %s, got %t", c.contains, c.name, got)
+		}
+		if got := Within(c.b, c.a); got != c.contains {
+			t.Errorf("Expected Within to be %t for %s, got %t", c.contains, c.name, got)
+		}
+		if got := Contains(c.b, c.a); got != c.within {
+			t.Errorf("Expected the reverse Contains to be %t for %s, got %t", c.within, c.name, got)
+		}
+
+		// The remaining predicates are symmetric.
+		for _, pair := range [][2]Geometry{{c.a, c.b}, {c.b, c.a}} {
+			if got := Touches(pair[0], pair[1]); got != c.touches {
+				t.Errorf("Expected Touches to be %t for %s, got %t", c.touches, c.name, got)
+			}
+			if got := Crosses(pair[0], pair[1]); got != c.crosses {
+				t.Errorf("Expected Crosses to be %t for %s, got %t", c.crosses, c.name, got)
+			}
+			if got := Overlaps(pair[0], pair[1]); got != c.overlaps {
+				t.Errorf("Expected Overlaps to be %t for %s, got %t", c.overlaps, c.name, got)
+			}
+		}
+	}
+}
+
+// Ensures that geometries on either side of the antimeridian are related as the neighbours they are.
+func TestPredicatesAcrossAntimeridian(t *testing.T) {
+	pacific := boxPolygon(-10, 170, 10, -170)
+	for _, lng := range []float64{175, -175, 180} {
+		if !Contains(pacific, NewPoint(0, lng)) {
+			t.Errorf("Expected the polygon across the antimeridian to contain longitude %f", lng)
+		}
+	}
+
+	if Intersects(pacific, NewPoint(0, 0)) {
+		t.Error("Expected the polygon across the antimeridian not to reach the prime meridian")
+	}
+
+	if !Crosses(lineOf(0, 160, 0, -160), pacific) || !Within(lineOf(0, 175, 0, -175), pacific) {
+		t.Error("Expected lines across the antimeridian to cross into and lie within the polygon")
+	}
+
+	if !Touches(pacific, boxPolygon(-10, -170, 10, -160)) {
+		t.Error("Expected the polygon to touch its eastern neighbour")
+	}
+
+	if !Overlaps(pacific, boxPolygon(0, 175, 20, 178)) || !Overlaps(pacific, boxPolygon(0, -178, 20, -175)) {
+		t.Error("Expected the polygon to overlap polygons on both sides of the antimeridian")
+	}
+}
+
+// Ensures that computed intersections a rounding error away from an edge still meet it.
+func TestRelateTolerance(t *testing.T) {
+	// The lines cross at a point that can't be represented exactly.
+	a, b := lineOf(0, 0, 1, 3), lineOf(1, 0, 0, 3)
+	if m := Relate(a, b); m != "0F1FF0102" {
+		t.Errorf("Expected the lines to cross at a single point, got %s", m)
+	}
+
+	if !Touches(NewPoint(1e-11, 5), boxPolygon(0, 0, 10, 10)) {
+		t.Error("Expected a point within the tolerance of the ring to touch it")
+	}
+}
@@ -0,0 +1,57 @@
+package geo
+
+import (
+	"math"
+)
+
+// Returns whether or not the current Polygon contains the passed in point, give or take the passed in
+// number of meters: with a positive tolerance, points outside the Polygon but within that distance
+// of its boundary are contained too, and with a negative one, only points at least that far inside are.
+// GPS noise makes Contains flap for a point near the boundary; checking with a negative tolerance
+// before taking a point to have entered, and with a positive one before taking it to have left,
+// keeps it steady.  Returns false for polygons that are not closed.
+func (p *Polygon) ContainsWithin(point *Point, meters float64) bool {
+	if !p.IsClosed() {
+		return false
+	}
+
+	inside := p.Contains(point)
+	d := point.GreatCircleDistance(p.ClosestBoundaryPoint(point)) * 1000
+	if meters >= 0 {
+		return inside || d <= meters
+	}
+
+	return inside && d >= -meters
+}
+
+// Returns whether or not the passed in geometries, each a *Point, a Line or a *Polygon, intersect
+// or come within the passed in number of meters of each other, as if either were buffered by that distance.
+// With a distance of zero, or less, this is Intersects.  Distances are measured along great circles,
+// to edges taken to be great circle arcs.
+func IntersectsBuffer(a, b Geometry, meters float64) bool {
+	if Intersects(a, b) {
+		return true
+	}
+
+	return meters > 0 && geometryDistance(newRelateGeometry(a), newRelateGeometry(b))*1000 <= meters
+}
+
+// Returns the great circle distance, in kilometers, between the nearest points of the passed in geometries,
+// which are taken not to intersect, or infinity if either is empty.  Geometries that don't intersect
+// are nearest at a vertex of one of them.
+func geometryDistance(a, b *relateGeometry) float64 {
+	best := math.Inf(1)
+	for _, pair := range [][2]*relateGeometry{{a, b}, {b, a}} {
+		from, to := pair[0], pair[1]
+		for _, p := range from.points {
+			if to.dim == 0 {
+				best = math.Min(best, p.GreatCircleDistance(to.points[0]))
+			}
+			for _, s := range to.segments() {
+				best = math.Min(best, p.GreatCircleDistance(closestPointOnArc(p, s[0], s[1])))
+			}
+		}
+	}
+
+	return best
+}
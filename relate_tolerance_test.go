@@ -0,0 +1,58 @@
+package geo
+
+import (
+	"testing"
+)
+
+// Ensures that points near a polygon's boundary are contained or not according to the tolerance.
+func TestPolygonContainsWithin(t *testing.T) {
+	square := boxPolygon(0, 0, 0.01, 0.01)
+
+	// About 55 meters outside the east edge, and as far inside the west edge.
+	outside, inside := NewPoint(0.005, 0.0105), NewPoint(0.005, 0.0005)
+
+	if square.ContainsWithin(outside, 50) || !square.ContainsWithin(outside, 60) {
+		t.Error("Expected a point 55m outside to be contained within 60m, but not 50m")
+	}
+
+	if !square.ContainsWithin(inside, 0) || !square.ContainsWithin(inside, -50) || square.ContainsWithin(inside, -60) {
+		t.Error("Expected a point 55m inside to be contained with a margin of 50m, but not 60m")
+	}
+
+	if square.ContainsWithin(outside, -50) {
+		t.Error("Expected a point outside not to be contained with a negative tolerance")
+	}
+
+	if NewPolygon([]*Point{NewPoint(0, 0), NewPoint(1, 1)}).ContainsWithin(NewPoint(0, 0), 1000) {
+		t.Error("Expected an open polygon to contain nothing")
+	}
+}
+
+// Ensures that geometries intersect their buffers when they come within the distance of each other.
+func TestIntersectsBuffer(t *testing.T) {
+	square := boxPolygon(0, 0, 0.01, 0.01)
+
+	// The road runs about 111 meters north of the square.
+	road := lineOf(0.011, -1, 0.011, 1)
+	if Intersects(square, road) || IntersectsBuffer(square, road, 100) || !IntersectsBuffer(square, road, 120) {
+		t.Error("Expected the road to be within 120m of the square, but not 100m")
+	}
+
+	if !IntersectsBuffer(square, NewPoint(0.005, 0.005), 0) {
+		t.Error("Expected geometries that intersect to intersect any buffer")
+	}
+
+	a, b := NewPoint(0, 0), NewPoint(0, 0.001)
+	if IntersectsBuffer(a, b, -1000) || IntersectsBuffer(a, b, 100) || !IntersectsBuffer(b, a, 112) {
+		t.Error("Expected points 111m apart to intersect a buffer of 112m only")
+	}
+
+	if IntersectsBuffer(square, Line{}, 1e9) {
+		t.Error("Expected an empty geometry to intersect nothing")
+	}
+
+	// Across the antimeridian, the geometries are neighbours.
+	if !IntersectsBuffer(NewPoint(0, 179.9999), boxPolygon(-1, -180, 1, -179), 20) {
+		t.Error("Expected a point just west of the antimeridian to be near a polygon just east of it")
+	}
+}
@@ -0,0 +1,245 @@
+package geo
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"sort"
+)
+
+// The XYZ URL template of the OpenStreetMap standard tile layer.  Heavy users
+// should run their own tile server; see https://operations.osmfoundation.org/policies/tiles/
+const OSM_TILE_TEMPLATE = "https://tile.openstreetmap.org/{z}/{x}/{y}.png"
+
+// An Overlay is something drawn on top of a map by RenderMap: a Marker, a Polyline or a PolygonOverlay.
+type Overlay interface {
+	drawOn(c *mapCanvas)
+}
+
+// A Marker is a filled circle centered on a point.
+type Marker struct {
+	Point  *Point
+	Color  color.Color
+	Radius float64
+}
+
+// A Polyline is a line through a sequence of points.
+type Polyline struct {
+	Points []*Point
+	Color  color.Color
+	Width  float64
+}
+
+// A PolygonOverlay is a polygon filled with one color and outlined with another.
+// Either color may be nil to skip the fill or the outline.
+type PolygonOverlay struct {
+	Polygon *Polygon
+	Fill    color.Color
+	Stroke  color.Color
+	Width   float64
+}
+
+// The image a map is drawn on, and how to find points on it.
+type mapCanvas struct {
+	img    *image.RGBA
+	origin image.Point
+	zoom   int
+
+	// The width of the world in pixels, and whether the map crosses the antimeridian.
+	worldSize float64
+	wraps     bool
+}
+
+// Returns the pixel position of the passed in coordinates on the canvas.
+func (c *mapCanvas) project(lat, lng float64) (float64, float64) {
+	x, y := tileCoordinates(lat, NormalizeLng(lng), c.zoom)
+	x, y = x*TILE_SIZE-float64(c.origin.X), y*TILE_SIZE-float64(c.origin.Y)
+	if c.wraps && x < 0 {
+		x += c.worldSize
+	}
+
+	return x, y
+}
+
+// Returns the pixel positions of the passed in points, keeping each within half a world
+// of the last so that lines crossing the antimeridian stay continuous.
+func (c *mapCanvas) projectAll(points []*Point) [][2]float64 {
+	projected := make([][2]float64, len(points))
+	for i, p := range points {
+		x, y := c.project(p.lat, p.lng)
+		if i > 0 {
+			prev := projected[i-1][0]
+			for x-prev > c.worldSize/2 {
+				x -= c.worldSize
+			}
+			for prev-x > c.worldSize/2 {
+				x += c.worldSize
+			}
+		}
+		projected[i] = [2]float64{x, y}
+	}
+
+	return projected
+}
+
+// Paints the passed in color through the passed in mask.
+func (c *mapCanvas) paint(mask *image.Alpha, col color.Color) {
+	draw.DrawMask(c.img, mask.Rect, image.NewUniform(col), image.Point{}, mask, mask.Rect.Min, draw.Over)
+}
+
+// Returns a mask covering the pixels within width/2 of the passed in path.
+func (c *mapCanvas) strokeMask(path [][2]float64, width float64, closed bool) *image.Alpha {
+	mask := image.NewAlpha(c.img.Bounds())
+	half := math.Max(width, 1) / 2
+
+	segments := len(path) - 1
+	if closed {
+		segments = len(path)
+	}
+
+	for i := 0; i < segments; i++ {
+		a, b := path[i], path[(i+1)%len(path)]
+		minX := int(math.Floor(math.Min(a[0], b[0]) - half))
+		maxX := int(math.Ceil(math.Max(a[0], b[0]) + half))
+		minY := int(math.Floor(math.Min(a[1], b[1]) - half))
+		maxY := int(math.Ceil(math.Max(a[1], b[1]) + half))
+		r := image.Rect(minX, minY, maxX+1, maxY+1).Intersect(mask.Rect)
+
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				if pixelSegmentDistance(float64(x)+0.5, float64(y)+0.5, a, b) <= half {
+					mask.SetAlpha(x, y, color.Alpha{255})
+				}
+			}
+		}
+	}
+
+	return mask
+}
+
+// Returns the distance from the pixel at (x, y) to the segment from a to b.
+func pixelSegmentDistance(x, y float64, a, b [2]float64) float64 {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	t := 0.0
+	if l := dx*dx + dy*dy; l > 0 {
+		t = math.Max(0, math.Min(1, ((x-a[0])*dx+(y-a[1])*dy)/l))
+	}
+
+	return math.Hypot(x-(a[0]+t*dx), y-(a[1]+t*dy))
+}
+
+// Returns a mask covering the pixels inside the passed in ring, by the even-odd rule.
+func (c *mapCanvas) fillMask(ring [][2]float64) *image.Alpha {
+	mask := image.NewAlpha(c.img.Bounds())
+
+	for y := mask.Rect.Min.Y; y < mask.Rect.Max.Y; y++ {
+		cy := float64(y) + 0.5
+		var crossings []float64
+		for i := range ring {
+			a, b := ring[i], ring[(i+1)%len(ring)]
+			if (a[1] <= cy) != (b[1] <= cy) {
+				crossings = append(crossings, a[0]+(cy-a[1])/(b[1]-a[1])*(b[0]-a[0]))
+			}
+		}
+		sort.Float64s(crossings)
+
+		for i := 0; i+1 < len(crossings); i += 2 {
+			from := max(mask.Rect.Min.X, int(math.Ceil(crossings[i]-0.5)))
+			to := min(mask.Rect.Max.X-1, int(math.Floor(crossings[i+1]-0.5)))
+			for x := from; x <= to; x++ {
+				mask.SetAlpha(x, y, color.Alpha{255})
+			}
+		}
+	}
+
+	return mask
+}
+
+func (m Marker) drawOn(c *mapCanvas) {
+	if m.Point == nil {
+		return
+	}
+
+	col, radius := m.Color, m.Radius
+	if col == nil {
+		col = color.RGBA{220, 30, 30, 255}
+	}
+	if radius <= 0 {
+		radius = 6
+	}
+
+	x, y := c.project(m.Point.lat, m.Point.lng)
+	c.paint(c.strokeMask([][2]float64{{x, y}, {x, y}}, 2*radius, false), col)
+}
+
+func (l Polyline) drawOn(c *mapCanvas) {
+	if len(l.Points) == 0 {
+		return
+	}
+
+	col, width := l.Color, l.Width
+	if col == nil {
+		col = color.RGBA{30, 30, 220, 255}
+	}
+	if width <= 0 {
+		width = 3
+	}
+
+	c.paint(c.strokeMask(c.projectAll(l.Points), width, false), col)
+}
+
+func (p PolygonOverlay) drawOn(c *mapCanvas) {
+	if p.Polygon == nil || !p.Polygon.IsClosed() {
+		return
+	}
+
+	ring := c.projectAll(p.Polygon.Points())
+	if p.Fill != nil {
+		c.paint(c.fillMask(ring), p.Fill)
+	}
+
+	if p.Stroke != nil {
+		width := p.Width
+		if width <= 0 {
+			width = 2
+		}
+		c.paint(c.strokeMask(ring, width, true), p.Stroke)
+	}
+}
+
+// Renders a map of the passed in Bounds at the passed in zoom from OpenStreetMap tiles,
+// draws the passed in overlays on it in order, and returns it as PNG bytes.
+func RenderMap(bbox *Bounds, zoom int, overlays ...Overlay) ([]byte, error) {
+	return NewTileFetcher(OSM_TILE_TEMPLATE, nil).RenderMap(bbox, zoom, overlays...)
+}
+
+// Renders a map of the passed in Bounds at the passed in zoom from the current TileFetcher's tiles,
+// draws the passed in overlays on it in order, and returns it as PNG bytes.
+func (f *TileFetcher) RenderMap(bbox *Bounds, zoom int, overlays ...Overlay) ([]byte, error) {
+	img, origin, err := f.stitch(bbox, zoom)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &mapCanvas{
+		img:       img,
+		origin:    origin,
+		zoom:      zoom,
+		worldSize: TILE_SIZE * math.Exp2(float64(zoom)),
+		wraps:     NormalizeLng(bbox.SouthWest().lng) > NormalizeLng(bbox.NorthEast().lng),
+	}
+
+	for _, o := range overlays {
+		o.drawOn(c)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
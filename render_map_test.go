@@ -0,0 +1,98 @@
+package geo
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+// Ensures that overlays are drawn at the right place on the stitched map.
+func TestRenderMap(t *testing.T) {
+	server, _, _ := newTileServer(t)
+	f := NewTileFetcher(server.URL+"/{z}/{x}/{y}.png", nil)
+
+	red := color.RGBA{255, 0, 0, 255}
+	green := color.RGBA{0, 255, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+
+	// The northern hemisphere at zoom 1: 512 by 256 pixels, with the equator along the bottom.
+	data, err := f.RenderMap(NewBounds(NewPoint(0, -180), NewPoint(MAX_MERCATOR_LAT, 180)), 1,
+		PolygonOverlay{Polygon: squarePolygon(10, 10, 40), Fill: green},
+		Polyline{Points: []*Point{NewPoint(60, -170), NewPoint(60, -10)}, Color: blue, Width: 4},
+		Marker{Point: NewPoint(30, 30), Color: red, Radius: 5},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, ok := decoded.(*image.RGBA)
+	if !ok {
+		img = image.NewRGBA(decoded.Bounds())
+		for y := 0; y < decoded.Bounds().Dy(); y++ {
+			for x := 0; x < decoded.Bounds().Dx(); x++ {
+				img.Set(x, y, decoded.At(x, y))
+			}
+		}
+	}
+
+	if img.Bounds().Dx() != 512 || img.Bounds().Dy() != 256 {
+		t.Fatalf("Expected a 512x256 map, got %v", img.Bounds())
+	}
+
+	pixel := func(lat, lng float64) color.RGBA {
+		x, y := tileCoordinates(lat, lng, 1)
+		return img.RGBAAt(int(x*TILE_SIZE), int(y*TILE_SIZE))
+	}
+
+	if got := pixel(30, 30); got != red {
+		t.Errorf("Expected the marker at its point, got %v", got)
+	}
+
+	if got := pixel(20, 45); got != green {
+		t.Errorf("Expected the polygon to be filled, got %v", got)
+	}
+
+	if got := pixel(60, -90); got != blue {
+		t.Errorf("Expected the polyline along its path, got %v", got)
+	}
+
+	if got := pixel(70, 100); got != (color.RGBA{40, 0, 200, 255}) {
+		t.Errorf("Expected the tile to show where nothing is drawn, got %v", got)
+	}
+}
+
+// Ensures that lines crossing the antimeridian are drawn across it, not around the world.
+func TestRenderMapAntimeridian(t *testing.T) {
+	server, _, _ := newTileServer(t)
+	f := NewTileFetcher(server.URL+"/{z}/{x}/{y}.png", nil)
+	blue := color.RGBA{0, 0, 255, 255}
+
+	data, err := f.RenderMap(NewBounds(NewPoint(0, 90), NewPoint(MAX_MERCATOR_LAT, -90)), 2,
+		Polyline{Points: []*Point{NewPoint(40, 170), NewPoint(40, -170)}, Color: blue, Width: 4},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The antimeridian runs down the middle of the map.
+	x, y := tileCoordinates(40, 180, 2)
+	if r, g, b, _ := img.At(TILE_SIZE, int(y*TILE_SIZE)).RGBA(); r != 0 || g != 0 || b != 0xffff {
+		t.Errorf("Expected the line to cross the antimeridian, got %v at %v", img.At(TILE_SIZE, int(y*TILE_SIZE)), x)
+	}
+
+	if r, g, b, _ := img.At(10, int(y*TILE_SIZE)).RGBA(); r == 0 && g == 0 && b == 0xffff {
+		t.Error("Expected the line not to wrap around the world")
+	}
+}
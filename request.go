@@ -0,0 +1,96 @@
+package geo
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Issues a GET request for the passed in URL with the passed in client,
+// or with the shared client if client is nil.
+// Returns the body of the response, or an error if one occurs during the process.
+func httpGet(client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return httpDo(client, req)
+}
+
+// Issues the passed in request with the passed in client, or with the shared client if client is nil,
+// sent with the package-wide Identity unless it has a User-Agent of its own.  Asks for a compressed response,
+// and reads no more of it than MaxResponseSize.
+// Returns the body of the response, or an error if one occurs during the process.
+func httpDo(client *http.Client, req *http.Request) ([]byte, error) {
+	if client == nil {
+		client = defaultClient()
+	}
+
+	prepareRequest(req)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, scrubError(err)
+	}
+	defer resp.Body.Close()
+
+	return readBody(resp)
+}
+
+// Issues a GET request for the passed in URL to the named provider with the passed in client,
+// or with the shared client if client is nil.  Returns the body of the response, or a ProviderError
+// if the provider can't be reached or answers with an unsuccessful status.
+func providerGet(client *http.Client, provider, url string) ([]byte, error) {
+	return providerGetContext(context.Background(), client, provider, url)
+}
+
+// Issues a GET request as providerGet does, canceled if the passed in context is done first.
+func providerGetContext(ctx context.Context, client *http.Client, provider, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if client == nil {
+		client = defaultClient()
+	}
+
+	prepareRequest(req)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, &ProviderError{Provider: provider, Kind: ErrProviderDown, Status: "unreachable", Err: scrubError(err)}
+	}
+	defer resp.Body.Close()
+
+	data, err := readBody(resp)
+	if err != nil {
+		return nil, &ProviderError{Provider: provider, Kind: ErrProviderDown, Status: "unreadable response", Err: err}
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, httpStatusError(provider, resp.StatusCode, data)
+	}
+
+	return data, nil
+}
+
+// Returns a copy of the passed in transport, or of the shared transport if it is nil, that connects through
+// the passed in proxy, or directly if it is nil.  Transports that aren't an *http.Transport are returned as they are.
+func proxyTransport(base http.RoundTripper, proxy *url.URL) http.RoundTripper {
+	if base == nil {
+		base = defaultTransport()
+	}
+
+	t, ok := base.(*http.Transport)
+	if !ok {
+		return base
+	}
+
+	t = t.Clone()
+	t.Proxy = nil
+	if proxy != nil {
+		t.Proxy = http.ProxyURL(proxy)
+	}
+
+	return t
+}
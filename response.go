@@ -0,0 +1,99 @@
+package geo
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// The most bytes of a response body, once decompressed, that is read from a provider unless told otherwise.
+const DEFAULT_MAX_RESPONSE_SIZE = 32 << 20
+
+// The encodings providers are asked to compress their responses with.
+const acceptEncoding = "gzip, deflate"
+
+// This is the error that consumers can compare against with errors.Is when a provider's
+// response is larger than the maximum set with SetMaxResponseSize.
+var ErrResponseTooLarge = errors.New("response too large")
+
+// The maximum set with SetMaxResponseSize, or 0 for DEFAULT_MAX_RESPONSE_SIZE.
+var maxResponseSize atomic.Int64
+
+// Sets the most bytes of a response body, once decompressed, that is read from any provider,
+// guarding against runaway responses.  Larger responses fail with an error matching ErrResponseTooLarge.
+// A maximum of zero or less restores DEFAULT_MAX_RESPONSE_SIZE.
+func SetMaxResponseSize(n int64) {
+	if n < 0 {
+		n = 0
+	}
+
+	maxResponseSize.Store(n)
+}
+
+// Returns the most bytes of a response body that is read from any provider.
+func MaxResponseSize() int64 {
+	if n := maxResponseSize.Load(); n > 0 {
+		return n
+	}
+
+	return DEFAULT_MAX_RESPONSE_SIZE
+}
+
+// Prepares the passed in request to be issued to a provider: sent with the package-wide Identity
+// unless it has a User-Agent of its own, and asking for a compressed response unless it says otherwise.
+func prepareRequest(req *http.Request) {
+	identify(req)
+	if req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+}
+
+// Returns a reader of the passed in response's body, decompressed according to its Content-Encoding.
+// Bodies that say they are deflated are read as the zlib stream the HTTP specification calls for,
+// or as raw deflate data, which some servers send instead.
+func decodeBody(header http.Header, body io.Reader) (io.Reader, error) {
+	switch encoding := strings.ToLower(strings.TrimSpace(header.Get("Content-Encoding"))); encoding {
+	case "", "identity":
+		return body, nil
+	case "gzip", "x-gzip":
+		return gzip.NewReader(body)
+	case "deflate":
+		br := bufio.NewReader(body)
+		head, err := br.Peek(2)
+		if err == nil && head[0]&0x0f == 8 && (uint16(head[0])<<8|uint16(head[1]))%31 == 0 {
+			return zlib.NewReader(br)
+		}
+		return flate.NewReader(br), nil
+	default:
+		return nil, fmt.Errorf("unsupported Content-Encoding %q", encoding)
+	}
+}
+
+// Reads the whole body of the passed in response, decompressed, failing with an error matching
+// ErrResponseTooLarge rather than reading more than MaxResponseSize bytes.
+func readBody(resp *http.Response) ([]byte, error) {
+	body, err := decodeBody(resp.Header, resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := MaxResponseSize()
+	data, err := ioutil.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%w: more than %d bytes", ErrResponseTooLarge, limit)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,82 @@
+package geo
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Ensures that providers are asked for compressed responses, and that gzip and deflate bodies are decompressed.
+func TestCompressedResponses(t *testing.T) {
+	body := `{"results":[],"status":"ZERO_RESULTS"}`
+	compress := map[string]func(w io.Writer) io.WriteCloser{
+		"gzip":    func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) },
+		"deflate": func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) },
+		"raw":     func(w io.Writer) io.WriteCloser { fw, _ := flate.NewWriter(w, flate.DefaultCompression); return fw },
+	}
+
+	for name, newWriter := range compress {
+		var accepted string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			accepted = r.Header.Get("Accept-Encoding")
+
+			var buf bytes.Buffer
+			cw := newWriter(&buf)
+			cw.Write([]byte(body))
+			cw.Close()
+
+			encoding := name
+			if name == "raw" {
+				encoding = "deflate"
+			}
+			w.Header().Set("Content-Encoding", encoding)
+			w.Write(buf.Bytes())
+		}))
+
+		data, err := providerGet(nil, "google", server.URL)
+		server.Close()
+		if err != nil || string(data) != body {
+			t.Errorf("Expected the %s body decompressed, got %q (%v)", name, data, err)
+		}
+
+		if accepted != "gzip, deflate" {
+			t.Errorf("Expected gzip and deflate to be accepted, got %q", accepted)
+		}
+	}
+}
+
+// Ensures that responses larger than the maximum are refused.
+func TestSetMaxResponseSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("x", 100)))
+	}))
+	defer server.Close()
+
+	defer SetMaxResponseSize(0)
+	SetMaxResponseSize(99)
+
+	if _, err := providerGet(nil, "google", server.URL); !errors.Is(err, ErrResponseTooLarge) || !errors.Is(err, ErrProviderDown) {
+		t.Errorf("Expected %v, got %v", ErrResponseTooLarge, err)
+	}
+
+	if _, err := httpGet(nil, server.URL); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("Expected %v, got %v", ErrResponseTooLarge, err)
+	}
+
+	SetMaxResponseSize(100)
+	if data, err := httpGet(nil, server.URL); err != nil || len(data) != 100 {
+		t.Errorf("Expected the whole body, got %d bytes (%v)", len(data), err)
+	}
+
+	SetMaxResponseSize(0)
+	if MaxResponseSize() != DEFAULT_MAX_RESPONSE_SIZE {
+		t.Errorf("Expected %d, got %d", DEFAULT_MAX_RESPONSE_SIZE, MaxResponseSize())
+	}
+}
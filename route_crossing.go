@@ -0,0 +1,130 @@
+package geo
+
+import (
+	"math"
+)
+
+// Returns the cross product of the passed in vectors.
+func cross3(a, b [3]float64) [3]float64 {
+	return [3]float64{
+		a[1]*b[2] - a[2]*b[1],
+		a[2]*b[0] - a[0]*b[2],
+		a[0]*b[1] - a[1]*b[0],
+	}
+}
+
+// Returns the dot product of the passed in vectors.
+func dot3(a, b [3]float64) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+// Returns the length of the passed in vector.
+func norm3(a [3]float64) float64 {
+	return math.Sqrt(dot3(a, a))
+}
+
+// Returns the Point the passed in vector points to from the center of the earth.
+func vectorPoint(v [3]float64) *Point {
+	lat := math.Atan2(v[2], math.Hypot(v[0], v[1])) * 180 / math.Pi
+	lng := math.Atan2(v[1], v[0]) * 180 / math.Pi
+	return NewPoint(lat, lng)
+}
+
+// The sine of the angle below which vectors are taken to be parallel,
+// about a millimeter on the surface of the earth.
+const parallelTolerance = 1e-10
+
+// Returns whether or not the unit vector x, on the great circle through the unit vectors a and b
+// with normal n, lies on the shorter arc between them.
+func onArc(x, a, b, n [3]float64) bool {
+	return dot3(cross3(a, x), n) >= -parallelTolerance && dot3(cross3(x, b), n) >= -parallelTolerance
+}
+
+// Returns the point where the great circle arcs from a to b and from c to d cross, and whether they do.
+// Arcs along the same great circle that overlap cross at the first of c and d found on the arc
+// from a to b, or at a if that lies on the arc from c to d.
+func arcIntersection(a, b, c, d *Point) (*Point, bool) {
+	va, vb, vc, vd := unitVector(a), unitVector(b), unitVector(c), unitVector(d)
+	n1, n2 := cross3(va, vb), cross3(vc, vd)
+	if norm3(n1) < parallelTolerance || norm3(n2) < parallelTolerance {
+		return nil, false
+	}
+
+	line := cross3(n1, n2)
+	if length := norm3(line); length >= parallelTolerance*norm3(n1)*norm3(n2) {
+		for _, sign := range []float64{1, -1} {
+			x := [3]float64{sign * line[0] / length, sign * line[1] / length, sign * line[2] / length}
+			if onArc(x, va, vb, n1) && onArc(x, vc, vd, n2) {
+				return vectorPoint(x), true
+			}
+		}
+		return nil, false
+	}
+
+	// The arcs lie on the same great circle, so cross where they overlap, if they do.
+	if dot3(n1, n2) < 0 {
+		vc, vd, n2 = vd, vc, cross3(vd, vc)
+	}
+
+	for _, x := range [][3]float64{vc, vd} {
+		if onArc(x, va, vb, n1) {
+			return vectorPoint(x), true
+		}
+	}
+
+	if onArc(va, vc, vd, n2) {
+		return a, true
+	}
+
+	return nil, false
+}
+
+// Returns the points where the passed in route, followed along great circles between its points,
+// crosses the edges of the passed in polygon, also taken to be great circle arcs, in the order
+// the route meets them.  Unlike lines drawn straight on a map, great circles bow toward the poles,
+// so a long flight can cross a zone its map line misses, or miss one it seems to cross.
+func RouteCrossings(route *Polyline, poly *Polygon) []*Point {
+	if !poly.IsClosed() {
+		return nil
+	}
+
+	ring := poly.Points()
+	var crossings []*Point
+	for i := 1; i < len(route.Points); i++ {
+		a, b := route.Points[i-1], route.Points[i]
+
+		var segment []*Point
+		for j := range ring {
+			if x, ok := arcIntersection(a, b, ring[j], ring[(j+1)%len(ring)]); ok {
+				segment = append(segment, x)
+			}
+		}
+
+		// Order the crossings of each leg of the route from its start.
+		for j := 1; j < len(segment); j++ {
+			for k := j; k > 0 && a.GreatCircleDistance(segment[k]) < a.GreatCircleDistance(segment[k-1]); k-- {
+				segment[k], segment[k-1] = segment[k-1], segment[k]
+			}
+		}
+		crossings = append(crossings, segment...)
+	}
+
+	return crossings
+}
+
+// Returns whether or not the passed in route, followed along great circles between its points,
+// enters the passed in polygon: whether it crosses one of its edges or has a point within it.
+// Useful for checking flight and shipping routes against restricted zones.
+func RouteIntersectsPolygon(route *Polyline, poly *Polygon) bool {
+	if !poly.IsClosed() {
+		return false
+	}
+
+	for _, p := range route.Points {
+		if poly.Contains(p) {
+			return true
+		}
+	}
+
+	return len(RouteCrossings(route, poly)) > 0
+}
@@ -0,0 +1,68 @@
+package geo
+
+import (
+	"testing"
+)
+
+// Returns a closed polygon around the passed in corners, crossing the antimeridian if west is east of east.
+func boxPolygon(south, west, north, east float64) *Polygon {
+	return NewPolygon([]*Point{NewPoint(south, west), NewPoint(north, west), NewPoint(north, east), NewPoint(south, east)})
+}
+
+// Ensures that routes are followed along great circles rather than straight lines on the map.
+func TestRouteIntersectsPolygon(t *testing.T) {
+	// The great circle between these bows up to about 81.7 degrees north as it crosses the antimeridian.
+	route := &Polyline{Points: []*Point{NewPoint(50, -100), NewPoint(50, 100)}}
+
+	arctic := boxPolygon(78, 175, 84, -175)
+	if !RouteIntersectsPolygon(route, arctic) {
+		t.Error("Expected the route to cross the arctic zone")
+	}
+
+	crossings := RouteCrossings(route, arctic)
+	if len(crossings) != 2 {
+		t.Fatalf("Expected the route to enter and leave the arctic zone, got %v", crossings)
+	}
+
+	for i, lng := range []float64{-175, 175} {
+		if d := LngDiff(crossings[i].Lng(), lng); d > 1e-6 || d < -1e-6 {
+			t.Errorf("Expected crossing %d at longitude %f, got %v", i, lng, crossings[i])
+		}
+	}
+
+	// The route's line on the map runs along the 50th parallel, but the route doesn't.
+	if RouteIntersectsPolygon(route, boxPolygon(49, 170, 51, -170)) {
+		t.Error("Expected the route to miss the zone under its line on the map")
+	}
+
+	// Routes that start inside a zone intersect it without crossing its edges.
+	inside := &Polyline{Points: []*Point{NewPoint(80, 178), NewPoint(81, 179)}}
+	if !RouteIntersectsPolygon(inside, arctic) || len(RouteCrossings(inside, arctic)) != 0 {
+		t.Error("Expected a route within the zone to intersect it without crossings")
+	}
+
+	if RouteIntersectsPolygon(route, NewPolygon([]*Point{NewPoint(80, 180), NewPoint(81, 180)})) {
+		t.Error("Expected an open polygon not to be intersected")
+	}
+}
+
+// Ensures that great circle arcs cross where expected, and that arcs along the same great circle overlap.
+func TestArcIntersection(t *testing.T) {
+	x, ok := arcIntersection(NewPoint(-10, 0), NewPoint(10, 0), NewPoint(0, -10), NewPoint(0, 10))
+	if !ok || x.GreatCircleDistance(NewPoint(0, 0)) > 1e-6 {
+		t.Errorf("Expected the arcs to cross at 0, 0, got %v", x)
+	}
+
+	if _, ok := arcIntersection(NewPoint(-10, 0), NewPoint(10, 0), NewPoint(0, 1), NewPoint(0, 10)); ok {
+		t.Error("Expected arcs that don't reach each other not to cross")
+	}
+
+	x, ok = arcIntersection(NewPoint(0, 0), NewPoint(0, 10), NewPoint(0, 20), NewPoint(0, 5))
+	if !ok || x.GreatCircleDistance(NewPoint(0, 5)) > 1e-6 {
+		t.Errorf("Expected overlapping arcs to cross where they meet, got %v", x)
+	}
+
+	if _, ok := arcIntersection(NewPoint(0, 0), NewPoint(0, 10), NewPoint(0, 20), NewPoint(0, 30)); ok {
+		t.Error("Expected arcs along the same great circle without overlap not to cross")
+	}
+}
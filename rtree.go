@@ -0,0 +1,209 @@
+package geo
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// The maximum number of children of each node of an rtree.
+const rtreeNodeSize = 16
+
+// An axis-aligned rectangle in degrees of latitude and longitude.
+type rect struct {
+	minLat, minLng, maxLat, maxLng float64
+}
+
+// Returns the smallest rect covering the passed in points.
+func rectOf(points []*Point) rect {
+	r := rect{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+	for _, p := range points {
+		r = r.extend(rect{p.lat, p.lng, p.lat, p.lng})
+	}
+
+	return r
+}
+
+// Returns the smallest rect covering both r and o.
+func (r rect) extend(o rect) rect {
+	return rect{
+		math.Min(r.minLat, o.minLat), math.Min(r.minLng, o.minLng),
+		math.Max(r.maxLat, o.maxLat), math.Max(r.maxLng, o.maxLng),
+	}
+}
+
+// Returns whether or not r and o overlap, edges included.
+func (r rect) intersects(o rect) bool {
+	return r.minLat <= o.maxLat && o.minLat <= r.maxLat && r.minLng <= o.maxLng && o.minLng <= r.maxLng
+}
+
+// An entry or node of an rtree: a rect, and either the id of
+// the item it bounds or the children it bounds.
+type rtreeNode struct {
+	bounds   rect
+	id       int
+	children []*rtreeNode
+}
+
+// An rtree is a static R-tree over rects, bulk loaded with the Sort-Tile-Recursive
+// algorithm, that finds the ids of the rects containing a point without
+// testing each rect in turn.
+type rtree struct {
+	root *rtreeNode
+}
+
+// Creates and returns a pointer to a new rtree holding the passed in rects,
+// each identified by its index in the slice.
+func newRTree(rects []rect) *rtree {
+	if len(rects) == 0 {
+		return &rtree{}
+	}
+
+	nodes := make([]*rtreeNode, len(rects))
+	for i, r := range rects {
+		nodes[i] = &rtreeNode{bounds: r, id: i}
+	}
+
+	for len(nodes) > 1 {
+		nodes = packRTreeLevel(nodes)
+	}
+
+	return &rtree{root: nodes[0]}
+}
+
+// Groups the passed in nodes into parents of up to rtreeNodeSize children,
+// tiling them into vertical slices by longitude, then by latitude within each slice.
+func packRTreeLevel(nodes []*rtreeNode) []*rtreeNode {
+	center := func(n *rtreeNode, lat bool) float64 {
+		if lat {
+			return n.bounds.minLat + n.bounds.maxLat
+		}
+		return n.bounds.minLng + n.bounds.maxLng
+	}
+
+	parents := int(math.Ceil(float64(len(nodes)) / rtreeNodeSize))
+	sliceSize := int(math.Ceil(math.Sqrt(float64(parents)))) * rtreeNodeSize
+
+	sort.Slice(nodes, func(i, j int) bool { return center(nodes[i], false) < center(nodes[j], false) })
+
+	var packed []*rtreeNode
+	for start := 0; start < len(nodes); start += sliceSize {
+		slice := nodes[start:min(start+sliceSize, len(nodes))]
+		sort.Slice(slice, func(i, j int) bool { return center(slice[i], true) < center(slice[j], true) })
+
+		for i := 0; i < len(slice); i += rtreeNodeSize {
+			children := slice[i:min(i+rtreeNodeSize, len(slice))]
+			parent := &rtreeNode{bounds: children[0].bounds, children: children}
+			for _, c := range children[1:] {
+				parent.bounds = parent.bounds.extend(c.bounds)
+			}
+			packed = append(packed, parent)
+		}
+	}
+
+	return packed
+}
+
+// Calls the passed in function with the id of every rect containing the passed in point.
+func (t *rtree) search(p *Point, fn func(id int)) {
+	t.searchRect(rect{p.lat, p.lng, p.lat, p.lng}, fn)
+}
+
+// Calls the passed in function with the id of every rect overlapping the passed in rect.
+func (t *rtree) searchRect(r rect, fn func(id int)) {
+	if t.root == nil {
+		return
+	}
+
+	stack := []*rtreeNode{t.root}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if !n.bounds.intersects(r) {
+			continue
+		}
+
+		if n.children == nil {
+			fn(n.id)
+			continue
+		}
+
+		stack = append(stack, n.children...)
+	}
+}
+
+// Returns the great circle distance, in kilometers, from the passed in point to the nearest
+// point of r, or 0 if r contains it.  r's edges of longitude are meridians, so the nearest
+// point on one is where the great circle through the point crossing it at a right angle meets it.
+func (r rect) distance(p *Point) float64 {
+	if p.lng >= r.minLng && p.lng <= r.maxLng {
+		switch {
+		case p.lat < r.minLat:
+			return (r.minLat - p.lat) * math.Pi / 180 * EARTH_RADIUS
+		case p.lat > r.maxLat:
+			return (p.lat - r.maxLat) * math.Pi / 180 * EARTH_RADIUS
+		}
+		return 0
+	}
+
+	d := math.Inf(1)
+	for _, lng := range []float64{r.minLng, r.maxLng} {
+		d = math.Min(d, p.GreatCircleDistance(NewPoint(r.minLat, lng)))
+		d = math.Min(d, p.GreatCircleDistance(NewPoint(r.maxLat, lng)))
+
+		dLng := (lng - p.lng) * math.Pi / 180
+		if math.Cos(dLng) > 0 {
+			lat := math.Atan(math.Tan(p.lat*math.Pi/180)/math.Cos(dLng)) * 180 / math.Pi
+			if lat > r.minLat && lat < r.maxLat {
+				d = math.Min(d, p.GreatCircleDistance(NewPoint(lat, lng)))
+			}
+		}
+	}
+
+	return d
+}
+
+// Calls the passed in function with the id and distance, in kilometers, of each rect in turn,
+// nearest to the passed in point first, until the function returns false.
+func (t *rtree) nearest(p *Point, fn func(id int, distance float64) bool) {
+	if t.root == nil {
+		return
+	}
+
+	queue := &rtreeQueue{{t.root, t.root.bounds.distance(p)}}
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(rtreeQueueItem)
+		if item.node.children == nil {
+			if !fn(item.node.id, item.distance) {
+				return
+			}
+			continue
+		}
+
+		for _, c := range item.node.children {
+			heap.Push(queue, rtreeQueueItem{c, c.bounds.distance(p)})
+		}
+	}
+}
+
+// A node of an rtree waiting to be visited, with its distance from the point searched for.
+type rtreeQueueItem struct {
+	node     *rtreeNode
+	distance float64
+}
+
+// A priority queue of rtree nodes, nearest first, implementing heap.Interface.
+type rtreeQueue []rtreeQueueItem
+
+func (q rtreeQueue) Len() int           { return len(q) }
+func (q rtreeQueue) Less(i, j int) bool { return q[i].distance < q[j].distance }
+func (q rtreeQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *rtreeQueue) Push(x any)        { *q = append(*q, x.(rtreeQueueItem)) }
+
+func (q *rtreeQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
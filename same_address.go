@@ -0,0 +1,156 @@
+package geo
+
+import (
+	"strings"
+	"unicode"
+)
+
+// How similar, from 0 to 1, two components of an address must be to be taken for the same component.
+const sameAddressSimilarity = 0.8
+
+// Returns the normalized components of the passed in address, split at its commas.
+func addressComponents(address string) []string {
+	var components []string
+	for _, part := range strings.Split(address, ",") {
+		if key := nameKey(part); key != "" {
+			components = append(components, key)
+		}
+	}
+
+	return components
+}
+
+// Returns the numbers in the passed in normalized address component, such as house numbers and postcodes.
+func componentNumbers(component string) string {
+	var numbers []string
+	for _, token := range strings.Fields(component) {
+		if strings.IndexFunc(token, unicode.IsDigit) >= 0 {
+			numbers = append(numbers, token)
+		}
+	}
+
+	return strings.Join(numbers, " ")
+}
+
+// Returns whether or not every word of the passed in normalized address component is one of the other's,
+// such as a town and the same town with its postcode.
+func componentWithin(a, b string) bool {
+	words := make(map[string]bool)
+	for _, word := range strings.Fields(b) {
+		words[word] = true
+	}
+
+	for _, word := range strings.Fields(a) {
+		if !words[word] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Returns whether or not the passed in normalized address components are the same: alike in their words,
+// or with every word of one in the other, and with the same numbers if both have any.
+func sameComponent(a, b string) bool {
+	if na, nb := componentNumbers(a), componentNumbers(b); na != "" && nb != "" && na != nb {
+		return false
+	}
+
+	return nameKeySimilarity(a, b) >= sameAddressSimilarity || componentWithin(a, b) || componentWithin(b, a)
+}
+
+// Returns whether or not the passed in results are probably the same address, such as the same customer
+// entered twice with different spellings: whether their points lie within the passed in distance, in meters,
+// of each other, they aren't in different countries, and each component of the shorter address, between its
+// commas, matches one of the other's.  Components are normalized as MatchName does, so "285 Bedford Ave, Brooklyn"
+// matches "285 Bedford Avenue, Brooklyn, NY 11211, USA", but components with different numbers never match,
+// so neighbouring houses, or flats in the same building, are kept apart.  Results without addresses
+// are compared by their points alone.
+func SameAddress(a, b GeocodeResult, toleranceMeters float64) bool {
+	if a.Point == nil || b.Point == nil || a.Point.GreatCircleDistance(b.Point)*1000 > toleranceMeters {
+		return false
+	}
+
+	if a.CountryCode != "" && b.CountryCode != "" && a.CountryCode != b.CountryCode {
+		return false
+	}
+
+	shorter, longer := addressComponents(a.FormattedAddress), addressComponents(b.FormattedAddress)
+	if len(shorter) > len(longer) {
+		shorter, longer = longer, shorter
+	}
+
+	for _, component := range shorter {
+		found := false
+		for _, other := range longer {
+			if sameComponent(component, other) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Groups the passed in results into sets of the same address, as SameAddress finds them, such as
+// the records of a customer database after bulk geocoding, so that each group can be merged into one.
+// Addresses are grouped with any address they are the same as, so a group may hold addresses that are
+// only the same through others between them.  Groups, and the results in each, are in the order
+// their first result was passed in; results with no duplicates are groups of one.
+func DedupeAddresses(results []*GeocodeResult, toleranceMeters float64) [][]*GeocodeResult {
+	index := NewKDTree[int]()
+	for i, r := range results {
+		if r.Point != nil {
+			index.Insert(r.Point, i)
+		}
+	}
+	index.Build()
+
+	// Join each result's group to those of the same addresses near it.
+	parent := make([]int, len(results))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i, r := range results {
+		if r.Point == nil {
+			continue
+		}
+
+		for _, near := range index.Within(r.Point, toleranceMeters/1000) {
+			j := near.Value
+			if j > i && SameAddress(*r, *results[j], toleranceMeters) {
+				if a, b := find(i), find(j); a != b {
+					parent[max(a, b)] = min(a, b)
+				}
+			}
+		}
+	}
+
+	var groups [][]*GeocodeResult
+	group := make(map[int]int)
+	for i, r := range results {
+		root := find(i)
+		g, ok := group[root]
+		if !ok {
+			g = len(groups)
+			group[root] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], r)
+	}
+
+	return groups
+}
@@ -0,0 +1,81 @@
+package geo
+
+import (
+	"testing"
+)
+
+// Ensures that SameAddress matches differently written addresses of the same place, and only those.
+func TestSameAddress(t *testing.T) {
+	full := GeocodeResult{Point: NewPoint(40.714224, -73.961452), FormattedAddress: "285 Bedford Avenue, Brooklyn, NY 11211, USA", CountryCode: "US"}
+	short := GeocodeResult{Point: NewPoint(40.71425, -73.96140), FormattedAddress: "285 Bedford Ave., Brooklyn"}
+
+	if !SameAddress(full, short, 25) || !SameAddress(short, full, 25) {
+		t.Error("Expected an abbreviated address to be the same")
+	}
+
+	if SameAddress(full, short, 1) {
+		t.Error("Expected addresses further apart than the tolerance to differ")
+	}
+
+	neighbour := GeocodeResult{Point: NewPoint(40.71415, -73.96150), FormattedAddress: "283 Bedford Avenue, Brooklyn, NY 11211, USA"}
+	if SameAddress(full, neighbour, 25) {
+		t.Error("Expected the house next door to differ")
+	}
+
+	flat1 := GeocodeResult{Point: full.Point, FormattedAddress: "Apt 4, 285 Bedford Avenue, Brooklyn"}
+	flat2 := GeocodeResult{Point: full.Point, FormattedAddress: "Apartment 5, 285 Bedford Avenue, Brooklyn"}
+	if SameAddress(flat1, flat2, 25) {
+		t.Error("Expected different flats in the same building to differ")
+	}
+
+	typo := GeocodeResult{Point: full.Point, FormattedAddress: "285 Bedferd Avenue, Brooklin, NY 11211"}
+	if !SameAddress(full, typo, 25) {
+		t.Error("Expected a misspelt address to be the same")
+	}
+
+	other := GeocodeResult{Point: full.Point, FormattedAddress: "Peter Luger Steak House, Broadway, Brooklyn"}
+	if SameAddress(full, other, 25) {
+		t.Error("Expected a different address at the same point to differ")
+	}
+
+	abroad := full
+	abroad.CountryCode = "CA"
+	if SameAddress(full, abroad, 25) {
+		t.Error("Expected addresses in different countries to differ")
+	}
+
+	if SameAddress(full, GeocodeResult{FormattedAddress: full.FormattedAddress}, 25) {
+		t.Error("Expected a result without a point to differ")
+	}
+}
+
+// Ensures that DedupeAddresses groups the same addresses in the order they were passed in.
+func TestDedupeAddresses(t *testing.T) {
+	results := []*GeocodeResult{
+		{Point: NewPoint(40.714224, -73.961452), FormattedAddress: "285 Bedford Avenue, Brooklyn, NY 11211, USA"},
+		{Point: NewPoint(51.5034, -0.1276), FormattedAddress: "10 Downing Street, London"},
+		{Point: NewPoint(40.71415, -73.96150), FormattedAddress: "283 Bedford Avenue, Brooklyn, NY 11211, USA"},
+		{Point: NewPoint(40.71425, -73.96140), FormattedAddress: "285 Bedford Ave, Brooklyn"},
+		{Point: NewPoint(51.50345, -0.12765), FormattedAddress: "10 Downing St, Westminster, London SW1A 2AA"},
+		{FormattedAddress: "Nowhere"},
+	}
+
+	groups := DedupeAddresses(results, 25)
+	if len(groups) != 4 {
+		t.Fatalf("Expected 4 groups, got %d: %v", len(groups), groups)
+	}
+
+	expected := [][]int{{0, 3}, {1, 4}, {2}, {5}}
+	for i, indexes := range expected {
+		if len(groups[i]) != len(indexes) {
+			t.Errorf("Expected group %d to have %d results, got %d", i, len(indexes), len(groups[i]))
+			continue
+		}
+
+		for j, index := range indexes {
+			if groups[i][j] != results[index] {
+				t.Errorf("Expected result %d in group %d, got %q", index, i, groups[i][j].FormattedAddress)
+			}
+		}
+	}
+}
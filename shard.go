@@ -0,0 +1,166 @@
+package geo
+
+import (
+	"github.com/golang/geo/s2"
+	"hash/fnv"
+	"io"
+)
+
+// The shard keys of points CountrySharder can't place in a country, such as those out at sea.
+const DEFAULT_COUNTRY_SHARD = "ZZ"
+
+// A Sharder partitions points by region, assigning each a shard key shared by the points
+// around it.  A Sharder always assigns the same point the same key.
+type Sharder interface {
+	Shard(p *Point) string
+}
+
+// A Sharder that keys points by their geohash, to the passed in number of characters:
+// each extra character divides every shard into 32.
+type GeohashSharder struct {
+	Precision int
+}
+
+// Returns the geohash of the passed in point, of the GeohashSharder's precision,
+// which is clamped to between 1 and MAX_GEOHASH_PRECISION.
+func (g GeohashSharder) Shard(p *Point) string {
+	return p.Geohash(max(1, min(g.Precision, MAX_GEOHASH_PRECISION)))
+}
+
+// A Sharder that keys points by the S2 cell containing them at the passed in level,
+// from 0, which divides the earth into 6 cells, to 30; each level divides every cell into 4.
+// Unlike geohash cells, S2 cells at a level are of similar area everywhere.
+type S2Sharder struct {
+	Level int
+}
+
+// Returns the token of the S2 cell containing the passed in point, at the S2Sharder's level,
+// which is clamped to between 0 and 30.
+func (s S2Sharder) Shard(p *Point) string {
+	level := max(0, min(s.Level, s2.MaxLevel))
+	return s2.CellIDFromLatLng(s2.LatLngFromDegrees(p.lat, p.lng)).Parent(level).ToToken()
+}
+
+// A Sharder that keys points by the ISO 3166-1 alpha-2 code of the country they lie in,
+// as found by its Locator, such as AdminBoundaries loaded with country borders.
+type CountrySharder struct {
+	Locator AdminAreaLocator
+
+	// The key of points in no country, or whose country the Locator can't find.
+	// Defaults to DEFAULT_COUNTRY_SHARD.
+	Default string
+}
+
+// Returns the code of the country the passed in point lies in, or the CountrySharder's Default.
+// Countries the Locator knows only by name are keyed by their code if it is a known country.
+func (c CountrySharder) Shard(p *Point) string {
+	def := c.Default
+	if def == "" {
+		def = DEFAULT_COUNTRY_SHARD
+	}
+
+	areas, err := c.Locator.AdminAreasOf(p)
+	if err != nil || len(areas) == 0 || areas[0].Level != AdminCountry {
+		return def
+	}
+
+	if code := NormalizeCountryCode(areas[0].Code); code != "" {
+		return code
+	}
+
+	if country, ok := CountryByName(areas[0].Name); ok {
+		return country.Alpha2
+	}
+
+	return def
+}
+
+// A ShardMap places the shards of a Sharder on a set of named nodes, such as databases,
+// with rendezvous hashing: every key is placed on the node it hashes highest with.
+// Adding a node moves only the keys that now hash highest with it, about 1/n of them,
+// and removing one moves only its own keys.
+type ShardMap struct {
+	Sharder Sharder
+	Nodes   []string
+}
+
+// Creates and returns a pointer to a new ShardMap placing the shards
+// of the passed in Sharder on the passed in nodes.
+func NewShardMap(sharder Sharder, nodes ...string) *ShardMap {
+	return &ShardMap{Sharder: sharder, Nodes: nodes}
+}
+
+// Returns the node the passed in shard key is placed on, or "" if the ShardMap has no nodes.
+func (m *ShardMap) NodeForKey(key string) string {
+	var best string
+	var bestScore uint64
+	for _, node := range m.Nodes {
+		h := fnv.New64a()
+		io.WriteString(h, node)
+		h.Write([]byte{0})
+		io.WriteString(h, key)
+
+		if score := mixHash(h.Sum64()); best == "" || score > bestScore || score == bestScore && node < best {
+			best, bestScore = node, score
+		}
+	}
+
+	return best
+}
+
+// Returns the passed in hash with its bits mixed by the finalizer of SplitMix64, so that
+// keys or nodes differing in their last character don't hash to neighbouring values.
+func mixHash(h uint64) uint64 {
+	h = (h ^ h>>30) * 0xbf58476d1ce4e5b9
+	h = (h ^ h>>27) * 0x94d049bb133111eb
+	return h ^ h>>31
+}
+
+// Returns the node the passed in point is placed on, or "" if the ShardMap has no nodes.
+func (m *ShardMap) Node(p *Point) string {
+	return m.NodeForKey(m.Sharder.Shard(p))
+}
+
+// A shard key that moves between nodes when a ShardMap changes.
+type ShardMove struct {
+	Key      string
+	From, To string
+}
+
+// Returns the moves the passed in shard keys make from their nodes in the ShardMap to their nodes
+// in the passed in ShardMap, which should share its Sharder; keys that stay on their node are left out.
+func (m *ShardMap) Moves(keys []string, to *ShardMap) []ShardMove {
+	var moves []ShardMove
+	for _, key := range keys {
+		if from, dest := m.NodeForKey(key), to.NodeForKey(key); from != dest {
+			moves = append(moves, ShardMove{Key: key, From: from, To: dest})
+		}
+	}
+
+	return moves
+}
+
+// Reads every point from the passed in source, calling the passed in function with each point
+// whose node differs between the from and to ShardMaps, along with both nodes, so that it can be
+// copied to its new node.  The ShardMaps may have different Sharders, such as when moving to
+// longer geohashes.  Returns the number of points moved, and the first error from the source,
+// other than io.EOF, or from the function.
+func Reshard(src PointSource, from *ShardMap, to *ShardMap, move func(p *Point, fromNode, toNode string) error) (int, error) {
+	moved := 0
+	for {
+		p, err := src.Next()
+		if err == io.EOF {
+			return moved, nil
+		}
+		if err != nil {
+			return moved, err
+		}
+
+		if fromNode, toNode := from.Node(p), to.Node(p); fromNode != toNode {
+			if err := move(p, fromNode, toNode); err != nil {
+				return moved, err
+			}
+			moved++
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package geo
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Ensures that each Sharder keys nearby points alike and distant points apart.
+func TestSharders(t *testing.T) {
+	sf, dalyCity, sydney := NewPoint(37.7749, -122.4194), NewPoint(37.6879, -122.4702), NewPoint(-33.8688, 151.2093)
+
+	tests := []struct {
+		sharder Sharder
+		sf      string
+	}{
+		{GeohashSharder{Precision: 3}, "9q8"},
+		{GeohashSharder{Precision: 0}, "9"},
+		{S2Sharder{Level: 4}, "809"},
+	}
+
+	for _, test := range tests {
+		if key := test.sharder.Shard(sf); key != test.sf {
+			t.Errorf("Expected %T to key San Francisco %s, got %s", test.sharder, test.sf, key)
+		}
+
+		if test.sharder.Shard(dalyCity) != test.sharder.Shard(sf) {
+			t.Errorf("Expected %T to key Daly City with San Francisco", test.sharder)
+		}
+
+		if test.sharder.Shard(sydney) == test.sharder.Shard(sf) {
+			t.Errorf("Expected %T to key Sydney apart from San Francisco", test.sharder)
+		}
+	}
+}
+
+// Ensures that a CountrySharder keys points by their country's code, even if only its name is known.
+func TestCountrySharder(t *testing.T) {
+	boundaries := NewAdminBoundaries()
+	boundaries.Add(&AdminArea{Level: AdminCountry, Name: "Netherlands"}, NewPolygon([]*Point{
+		NewPoint(50.75, 3.36), NewPoint(53.55, 3.36), NewPoint(53.55, 7.23), NewPoint(50.75, 7.23), NewPoint(50.75, 3.36),
+	}))
+	boundaries.Add(&AdminArea{Level: AdminCountry, Name: "Belgium", Code: "be"}, NewPolygon([]*Point{
+		NewPoint(49.5, 2.5), NewPoint(50.7, 2.5), NewPoint(50.7, 6.4), NewPoint(49.5, 6.4), NewPoint(49.5, 2.5),
+	}))
+
+	sharder := CountrySharder{Locator: boundaries}
+	for p, expected := range map[*Point]string{
+		NewPoint(52.37, 4.89): "NL",
+		NewPoint(50.85, 4.35): "NL",
+		NewPoint(50.5, 4.35):  "BE",
+		NewPoint(0, 0):        DEFAULT_COUNTRY_SHARD,
+	} {
+		if key := sharder.Shard(p); key != expected {
+			t.Errorf("Expected %v to be keyed %s, got %s", p, expected, key)
+		}
+	}
+
+	if key := (CountrySharder{Locator: boundaries, Default: "sea"}).Shard(NewPoint(0, 0)); key != "sea" {
+		t.Errorf("Expected the default key, got %s", key)
+	}
+}
+
+// Ensures that a ShardMap places keys consistently, and that adding a node moves only the keys it takes.
+func TestShardMap(t *testing.T) {
+	m := NewShardMap(GeohashSharder{Precision: 4}, "db1", "db2", "db3")
+	if node := m.Node(NewPoint(37.7749, -122.4194)); node != m.NodeForKey("9q8y") {
+		t.Errorf("Expected a point to be placed with its key, got %s", node)
+	}
+
+	if node := NewShardMap(GeohashSharder{}).NodeForKey("9q8y"); node != "" {
+		t.Errorf("Expected no node without nodes, got %s", node)
+	}
+
+	var keys []string
+	counts := make(map[string]int)
+	for i := 0; i < 3000; i++ {
+		key := fmt.Sprintf("key%d", i)
+		keys = append(keys, key)
+		counts[m.NodeForKey(key)]++
+	}
+
+	for node, n := range counts {
+		if n < 800 || n > 1200 {
+			t.Errorf("Expected about 1000 keys on %s, got %d", node, n)
+		}
+	}
+
+	grown := NewShardMap(m.Sharder, "db1", "db2", "db3", "db4")
+	moves := m.Moves(keys, grown)
+	if len(moves) < 600 || len(moves) > 900 {
+		t.Errorf("Expected about a quarter of the keys to move, got %d", len(moves))
+	}
+
+	for _, move := range moves {
+		if move.To != "db4" || move.From != m.NodeForKey(move.Key) {
+			t.Fatalf("Expected keys to move only to the new node, got %+v", move)
+		}
+	}
+}
+
+// Ensures that resharding reports the points whose node changes, even when the Sharder changes.
+func TestReshard(t *testing.T) {
+	var points []*Point
+	for i := 0; i < 200; i++ {
+		points = append(points, NewPoint(float64(i%20)*4-40, float64(i/20)*30-150))
+	}
+
+	from := NewShardMap(GeohashSharder{Precision: 2}, "a", "b")
+	to := NewShardMap(S2Sharder{Level: 8}, "a", "b", "c")
+
+	expected := 0
+	for _, p := range points {
+		if from.Node(p) != to.Node(p) {
+			expected++
+		}
+	}
+
+	moved, err := Reshard(NewSlicePointSource(points), from, to, func(p *Point, fromNode, toNode string) error {
+		if fromNode != from.Node(p) || toNode != to.Node(p) || fromNode == toNode {
+			t.Errorf("Unexpected move of %v from %s to %s", p, fromNode, toNode)
+		}
+		return nil
+	})
+
+	if err != nil || moved != expected || moved == 0 {
+		t.Errorf("Expected %d points to move, got %d (%v)", expected, moved, err)
+	}
+}
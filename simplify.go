@@ -0,0 +1,268 @@
+package geo
+
+import (
+	"container/heap"
+	"math"
+)
+
+// Returns the position, in meters east and north, of the passed in point relative
+// to the passed in origin, using an equirectangular projection centered on the origin.
+// Accurate for the short distances between neighbouring points of a line.
+func localXY(origin *Point, p *Point) (float64, float64) {
+	r := EARTH_RADIUS * 1000.0
+	x := LngDiff(origin.lng, p.lng) * math.Pi / 180 * r * math.Cos(origin.lat*math.Pi/180)
+	y := (p.lat - origin.lat) * math.Pi / 180 * r
+	return x, y
+}
+
+// Returns the point the passed in fraction of the way from a to b along the great circle between them.
+// Original Implementation from: http://www.movable-type.co.uk/scripts/latlong.html
+func intermediatePoint(a *Point, b *Point, fraction float64) *Point {
+	va, vb := unitVector(a), unitVector(b)
+	delta := math.Acos(math.Max(-1, math.Min(1, va[0]*vb[0]+va[1]*vb[1]+va[2]*vb[2])))
+	if delta == 0 {
+		return NewPoint(a.lat, a.lng)
+	}
+
+	wa := math.Sin((1-fraction)*delta) / math.Sin(delta)
+	wb := math.Sin(fraction*delta) / math.Sin(delta)
+
+	x := wa*va[0] + wb*vb[0]
+	y := wa*va[1] + wb*vb[1]
+	z := wa*va[2] + wb*vb[2]
+
+	return NewPoint(math.Atan2(z, math.Hypot(x, y))*180/math.Pi, math.Atan2(y, x)*180/math.Pi)
+}
+
+// Returns a copy of the passed in line with points added along each segment,
+// following the great circle between its ends, so that no segment is longer
+// than the passed in number of meters.  Useful before projecting or rendering
+// long segments, which would otherwise be drawn as straight lines.
+func Densify(line []*Point, maxSegmentMeters float64) []*Point {
+	if len(line) < 2 || maxSegmentMeters <= 0 {
+		return line
+	}
+
+	dense := []*Point{line[0]}
+	for i := 1; i < len(line); i++ {
+		a, b := line[i-1], line[i]
+		pieces := int(math.Ceil(a.GreatCircleDistance(b) * 1000 / maxSegmentMeters))
+		for j := 1; j < pieces; j++ {
+			dense = append(dense, intermediatePoint(a, b, float64(j)/float64(pieces)))
+		}
+		dense = append(dense, b)
+	}
+
+	return dense
+}
+
+// Options for SimplifyDouglasPeucker.
+type DouglasPeuckerOptions struct {
+	// The furthest, in meters, any removed point may lie from the simplified line.
+	Tolerance float64
+}
+
+// Returns a simplified copy of the passed in line using the Douglas-Peucker algorithm,
+// keeping only the points needed for every removed point to lie within the
+// Tolerance of the simplified line.  The first and last points are always kept.
+func SimplifyDouglasPeucker(line []*Point, opts DouglasPeuckerOptions) []*Point {
+	if len(line) < 3 {
+		return line
+	}
+
+	keep := make([]bool, len(line))
+	keep[0], keep[len(line)-1] = true, true
+	douglasPeucker(line, 0, len(line)-1, opts.Tolerance, keep)
+
+	simplified := make([]*Point, 0, len(line))
+	for i, p := range line {
+		if keep[i] {
+			simplified = append(simplified, p)
+		}
+	}
+
+	return simplified
+}
+
+// Marks the points between first and last that must be kept to stay within the passed in tolerance.
+func douglasPeucker(line []*Point, first int, last int, tolerance float64, keep []bool) {
+	if last-first < 2 {
+		return
+	}
+
+	furthest, maxDist := 0, -1.0
+	for i := first + 1; i < last; i++ {
+		if d := segmentDistance(line[i], line[first], line[last]); d > maxDist {
+			furthest, maxDist = i, d
+		}
+	}
+
+	if maxDist <= tolerance {
+		return
+	}
+
+	keep[furthest] = true
+	douglasPeucker(line, first, furthest, tolerance, keep)
+	douglasPeucker(line, furthest, last, tolerance, keep)
+}
+
+// Returns the distance, in meters, from the passed in point to the segment between a and b.
+func segmentDistance(p *Point, a *Point, b *Point) float64 {
+	px, py := localXY(a, p)
+	bx, by := localXY(a, b)
+
+	t := 0.0
+	if l := bx*bx + by*by; l > 0 {
+		t = math.Max(0, math.Min(1, (px*bx+py*by)/l))
+	}
+
+	return math.Hypot(px-t*bx, py-t*by)
+}
+
+// Options for SimplifyVisvalingam.
+type VisvalingamOptions struct {
+	// Points whose triangle with their neighbours is smaller than this many
+	// square meters are removed, smallest first.
+	MinArea float64
+
+	// If set, points are kept whenever removing them would make the line cross itself.
+	PreserveTopology bool
+}
+
+// A point of a line being simplified, linked to its current neighbours.
+type vwVertex struct {
+	index      int
+	area       float64
+	prev, next *vwVertex
+	heapIndex  int
+	removed    bool
+}
+
+// A min-heap of vertices ordered by the area of their triangles.
+type vwHeap []*vwVertex
+
+func (h vwHeap) Len() int           { return len(h) }
+func (h vwHeap) Less(i, j int) bool { return h[i].area < h[j].area }
+func (h vwHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].heapIndex = i
+	h[j].heapIndex = j
+}
+
+func (h *vwHeap) Push(x interface{}) {
+	v := x.(*vwVertex)
+	v.heapIndex = len(*h)
+	*h = append(*h, v)
+}
+
+func (h *vwHeap) Pop() interface{} {
+	old := *h
+	v := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return v
+}
+
+// Returns a simplified copy of the passed in line using the Visvalingam-Whyatt algorithm,
+// repeatedly removing the point that forms the smallest triangle with its neighbours
+// until every remaining triangle is at least MinArea.  The first and last points are always kept.
+func SimplifyVisvalingam(line []*Point, opts VisvalingamOptions) []*Point {
+	if len(line) < 3 {
+		return line
+	}
+
+	vertices := make([]*vwVertex, len(line))
+	for i := range line {
+		vertices[i] = &vwVertex{index: i}
+		if i > 0 {
+			vertices[i].prev = vertices[i-1]
+			vertices[i-1].next = vertices[i]
+		}
+	}
+
+	area := func(v *vwVertex) float64 {
+		return triangleArea(line[v.prev.index], line[v.index], line[v.next.index])
+	}
+
+	h := make(vwHeap, 0, len(line)-2)
+	for _, v := range vertices[1 : len(line)-1] {
+		v.area = area(v)
+		heap.Push(&h, v)
+	}
+
+	for h.Len() > 0 {
+		v := heap.Pop(&h).(*vwVertex)
+		v.heapIndex = -1
+		if v.area >= opts.MinArea {
+			break
+		}
+
+		if opts.PreserveTopology && vwWouldIntersect(line, vertices[0], v) {
+			continue
+		}
+
+		v.removed = true
+		v.prev.next, v.next.prev = v.next, v.prev
+
+		// A neighbour's area may not drop below the removed point's,
+		// so that points are still removed in order of significance.
+		for _, n := range []*vwVertex{v.prev, v.next} {
+			if n.prev == nil || n.next == nil || n.heapIndex < 0 {
+				continue
+			}
+			n.area = math.Max(area(n), v.area)
+			heap.Fix(&h, n.heapIndex)
+		}
+	}
+
+	simplified := make([]*Point, 0, len(line))
+	for _, v := range vertices {
+		if !v.removed {
+			simplified = append(simplified, line[v.index])
+		}
+	}
+
+	return simplified
+}
+
+// Returns whether or not joining v's neighbours directly would cross any other remaining segment of the line.
+func vwWouldIntersect(line []*Point, first *vwVertex, v *vwVertex) bool {
+	origin := line[v.prev.index]
+	ax, ay := 0.0, 0.0
+	bx, by := localXY(origin, line[v.next.index])
+
+	for s := first; s.next != nil; s = s.next {
+		// The segments either side of v are the ones being replaced.
+		if s == v.prev || s == v {
+			continue
+		}
+
+		cx, cy := localXY(origin, line[s.index])
+		dx, dy := localXY(origin, line[s.next.index])
+		if segmentsIntersect(ax, ay, bx, by, cx, cy, dx, dy) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns whether or not the segment from (ax, ay) to (bx, by) properly crosses the segment from (cx, cy) to (dx, dy).
+func segmentsIntersect(ax, ay, bx, by, cx, cy, dx, dy float64) bool {
+	cross := func(ox, oy, px, py, qx, qy float64) float64 {
+		return (px-ox)*(qy-oy) - (py-oy)*(qx-ox)
+	}
+
+	d1 := cross(cx, cy, dx, dy, ax, ay)
+	d2 := cross(cx, cy, dx, dy, bx, by)
+	d3 := cross(ax, ay, bx, by, cx, cy)
+	d4 := cross(ax, ay, bx, by, dx, dy)
+
+	return ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0))
+}
+
+// Returns the area, in square meters, of the triangle formed by the passed in points.
+func triangleArea(a *Point, b *Point, c *Point) float64 {
+	bx, by := localXY(a, b)
+	cx, cy := localXY(a, c)
+	return math.Abs(bx*cy-by*cx) / 2
+}
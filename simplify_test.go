@@ -0,0 +1,168 @@
+package geo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// Returns a zig-zagging line of the passed in number of points heading east from the origin,
+// with points about 111m apart that stray the passed in number of meters either side of the equator.
+func zigZag(n int, strayMeters float64) []*Point {
+	line := make([]*Point, n)
+	stray := strayMeters / 1000 / EARTH_RADIUS * 180 / math.Pi
+	for i := range line {
+		lat := stray
+		if i%2 == 1 {
+			lat = -stray
+		}
+		line[i] = NewPoint(lat, float64(i)*0.001)
+	}
+
+	return line
+}
+
+// Returns a line of the passed in number of points wandering about 100m at a time,
+// like a recorded track.  The same line is returned every time.
+func randomWalk(n int) []*Point {
+	r := rand.New(rand.NewSource(1))
+	line := []*Point{NewPoint(37.7749, -122.4194)}
+	bearing := 0.0
+	for len(line) < n {
+		bearing += r.NormFloat64() * 20
+		line = append(line, line[len(line)-1].PointAtDistanceAndBearing(0.1, bearing))
+	}
+
+	return line
+}
+
+// Ensures that Densify splits long segments along the great circle, keeping the original points.
+func TestDensify(t *testing.T) {
+	line := []*Point{NewPoint(0, 0), NewPoint(0, 1), NewPoint(0, 1.0001)}
+
+	dense := Densify(line, 10000)
+	if len(dense) != 14 {
+		t.Fatalf("Expected 14 points, got %d", len(dense))
+	}
+
+	if dense[0] != line[0] || dense[12] != line[1] || dense[13] != line[2] {
+		t.Error("Expected the original points to be kept")
+	}
+
+	for i := 1; i < len(dense); i++ {
+		if d := dense[i-1].GreatCircleDistance(dense[i]) * 1000; d > 10000 {
+			t.Errorf("Expected no segment longer than 10000m, got %f", d)
+		}
+	}
+
+	// The great circle from Seattle to Tokyo passes well north of both.
+	route := Densify([]*Point{NewPoint(47.6, -122.3), NewPoint(35.7, 139.7)}, 500000)
+	if north := route[len(route)/2].Lat(); north < 50 {
+		t.Errorf("Expected the midpoint to lie north of 50°, got %f", north)
+	}
+}
+
+// Ensures that Douglas-Peucker removes only the points within the tolerance of the simplified line.
+func TestSimplifyDouglasPeucker(t *testing.T) {
+	line := zigZag(50, 1)
+	line = append(line, NewPoint(0.01, 0.05))
+
+	simplified := SimplifyDouglasPeucker(line, DouglasPeuckerOptions{Tolerance: 5})
+	if len(simplified) != 3 {
+		t.Fatalf("Expected 3 points, got %d", len(simplified))
+	}
+
+	if simplified[0] != line[0] || simplified[2] != line[len(line)-1] {
+		t.Error("Expected the first and last points to be kept")
+	}
+
+	if got := SimplifyDouglasPeucker(line, DouglasPeuckerOptions{Tolerance: 0.5}); len(got) != len(line) {
+		t.Errorf("Expected every point to be kept, got %d of %d", len(got), len(line))
+	}
+}
+
+// Ensures that Visvalingam removes small triangles first and keeps significant points.
+func TestSimplifyVisvalingam(t *testing.T) {
+	line := zigZag(50, 1)
+	line = append(line, NewPoint(0.01, 0.05))
+
+	simplified := SimplifyVisvalingam(line, VisvalingamOptions{MinArea: 100000})
+	if len(simplified) != 3 {
+		t.Fatalf("Expected 3 points, got %d", len(simplified))
+	}
+
+	if simplified[1] != line[len(line)-2] {
+		t.Errorf("Expected the corner to be kept, got %v", simplified[1])
+	}
+}
+
+// Ensures that topology-preserving Visvalingam never makes a line cross itself.
+func TestSimplifyVisvalingamPreserveTopology(t *testing.T) {
+	// A small bump, with the end of the line tucked up beneath it from the south.
+	// Flattening the bump would cut across the end of the line.
+	line := []*Point{
+		NewPoint(0, 0),
+		NewPoint(0.0002, 0.005),
+		NewPoint(0, 0.01),
+		NewPoint(-0.01, 0.01),
+		NewPoint(-0.01, 0.006),
+		NewPoint(0.0001, 0.006),
+	}
+
+	loose := SimplifyVisvalingam(line, VisvalingamOptions{MinArea: 50000})
+	strict := SimplifyVisvalingam(line, VisvalingamOptions{MinArea: 50000, PreserveTopology: true})
+
+	if !selfIntersects(loose) {
+		t.Fatalf("Expected the unconstrained simplification to cross itself, got %v", loose)
+	}
+
+	if selfIntersects(strict) {
+		t.Errorf("Expected the topology-preserving simplification not to cross itself, got %v", strict)
+	}
+}
+
+// Returns whether or not any two non-adjacent segments of the passed in line cross.
+func selfIntersects(line []*Point) bool {
+	origin := line[0]
+	for i := 1; i < len(line); i++ {
+		for j := i + 2; j < len(line); j++ {
+			ax, ay := localXY(origin, line[i-1])
+			bx, by := localXY(origin, line[i])
+			cx, cy := localXY(origin, line[j-1])
+			dx, dy := localXY(origin, line[j])
+			if segmentsIntersect(ax, ay, bx, by, cx, cy, dx, dy) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func BenchmarkDensify(b *testing.B) {
+	line := randomWalk(1000)
+	for i := 0; i < b.N; i++ {
+		Densify(line, 10)
+	}
+}
+
+func BenchmarkSimplifyDouglasPeucker(b *testing.B) {
+	line := randomWalk(10000)
+	for i := 0; i < b.N; i++ {
+		SimplifyDouglasPeucker(line, DouglasPeuckerOptions{Tolerance: 5})
+	}
+}
+
+func BenchmarkSimplifyVisvalingam(b *testing.B) {
+	line := randomWalk(10000)
+	for i := 0; i < b.N; i++ {
+		SimplifyVisvalingam(line, VisvalingamOptions{MinArea: 1000})
+	}
+}
+
+func BenchmarkSimplifyVisvalingamPreserveTopology(b *testing.B) {
+	line := randomWalk(1000)
+	for i := 0; i < b.N; i++ {
+		SimplifyVisvalingam(line, VisvalingamOptions{MinArea: 1000, PreserveTopology: true})
+	}
+}
@@ -0,0 +1,94 @@
+package geo
+
+import (
+	"math"
+)
+
+// Returns the Point on a grid of cells roughly the passed in number of meters
+// across that lies nearest to the passed in Point.  Rows of the grid are evenly
+// spaced in latitude; within each row, cells are as wide in meters as they are tall,
+// so nearby points snap to the same Point wherever they are on the earth.
+// Useful for reducing coordinate noise before caching or deduplicating points.
+func Snap(p *Point, gridMeters float64) *Point {
+	if gridMeters <= 0 {
+		return NewPoint(p.lat, p.lng)
+	}
+
+	metersPerDegree := EARTH_RADIUS * 1000 * math.Pi / 180
+
+	latStep := gridMeters / metersPerDegree
+	lat := math.Round(p.lat/latStep) * latStep
+	lat = math.Max(-90, math.Min(90, lat))
+
+	// Cells are widened towards the poles, where degrees of longitude shrink,
+	// and a row at a pole is a single cell.  Rows have an even number of cells,
+	// so that both the prime meridian and the antimeridian run through cell centers.
+	cosLat := math.Cos(lat * math.Pi / 180)
+	lngStep := 360.0
+	if cells := 2 * math.Floor(180*metersPerDegree*cosLat/gridMeters); cells >= 2 {
+		lngStep = 360 / cells
+	}
+	lng := NormalizeLng(math.Round(p.lng/lngStep) * lngStep)
+	if lng == 180 {
+		// The same meridian as -180, which points just east of the antimeridian snap to.
+		lng = -180
+	}
+
+	return NewPoint(lat, lng)
+}
+
+// Returns copies of the passed in points with their coordinates rounded to the passed
+// in number of decimal places.  Five decimal places is roughly a meter, four is roughly
+// eleven meters.  Nil points are kept as nil.
+func Quantize(points []*Point, precisionDecimals int) []*Point {
+	scale := math.Pow(10, float64(precisionDecimals))
+
+	quantized := make([]*Point, len(points))
+	for i, p := range points {
+		if p == nil {
+			continue
+		}
+
+		quantized[i] = NewPoint(math.Round(p.lat*scale)/scale, math.Round(p.lng*scale)/scale)
+	}
+
+	return quantized
+}
+
+// Returns the passed in points thinned so that no cell of a grid roughly the passed in number of meters
+// across, as Snap uses, holds more than maxPerCell of them.  Dense clusters are thinned while points
+// in sparse regions are all kept, so the result still covers everywhere the input did.
+// The points kept from each crowded cell are spread evenly through its points in input order,
+// rather than being its first, and the result keeps the input order.  Nil points are dropped.
+func SamplePoints(points []*Point, maxPerCell int, cellMeters float64) []*Point {
+	if maxPerCell <= 0 {
+		return nil
+	}
+
+	cells := make([]Point, len(points))
+	counts := make(map[Point]int)
+	for i, p := range points {
+		if p != nil {
+			cells[i] = *Snap(p, cellMeters)
+			counts[cells[i]]++
+		}
+	}
+
+	var sample []*Point
+	seen := make(map[Point]int)
+	for i, p := range points {
+		if p == nil {
+			continue
+		}
+
+		// Keep the points at maxPerCell evenly spaced places among the cell's points.
+		cell := cells[i]
+		n, count := seen[cell], counts[cell]
+		seen[cell]++
+		if count <= maxPerCell || n*maxPerCell/count != (n+1)*maxPerCell/count {
+			sample = append(sample, p)
+		}
+	}
+
+	return sample
+}
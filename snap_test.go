@@ -0,0 +1,98 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+// Ensures that nearby points snap to the same grid point, and that snapping moves points less than a cell.
+func TestSnap(t *testing.T) {
+	a := NewPoint(37.774901, -122.419402)
+	b := NewPoint(37.774912, -122.419388)
+
+	sa, sb := Snap(a, 100), Snap(b, 100)
+	if sa.Lat() != sb.Lat() || sa.Lng() != sb.Lng() {
+		t.Errorf("Expected nearby points to snap together, got %v and %v", sa, sb)
+	}
+
+	if d := a.GreatCircleDistance(sa) * 1000; d > 100 {
+		t.Errorf("Expected the point to move less than a cell, moved %fm", d)
+	}
+
+	if same := Snap(sa, 100); same.Lat() != sa.Lat() || same.Lng() != sa.Lng() {
+		t.Errorf("Expected snapping to be idempotent, got %v then %v", sa, same)
+	}
+}
+
+// Ensures that grid cells stay roughly square away from the equator, and that the poles snap sensibly.
+func TestSnapHighLatitude(t *testing.T) {
+	p := Snap(NewPoint(70, 20.0004), 100)
+	if d := NewPoint(70, 20.0004).GreatCircleDistance(p) * 1000; d > 71 {
+		t.Errorf("Expected the point to move no more than half a cell's diagonal, moved %fm", d)
+	}
+
+	pole := Snap(NewPoint(89.99999, 123), 1000)
+	if pole.Lat() != 90 || pole.Lng() != 0 {
+		t.Errorf("Expected points near the pole to snap to it, got [%f, %f]", pole.Lat(), pole.Lng())
+	}
+}
+
+// Ensures that points either side of the antimeridian snap to the same point on it, as do points either
+// side of the prime meridian, whatever the number of cells in their row.
+func TestSnapAntimeridian(t *testing.T) {
+	for _, lat := range []float64{0, 37.3, 61.7} {
+		west, east := Snap(NewPoint(lat, 179.99999), 1000), Snap(NewPoint(lat, -179.99999), 1000)
+		if west.Lat() != east.Lat() || west.Lng() != -180 || east.Lng() != -180 {
+			t.Errorf("Expected points either side of the antimeridian to snap to it, got %v and %v", west, east)
+		}
+
+		if p := Snap(NewPoint(lat, 0.00001), 1000); p.Lng() != 0 {
+			t.Errorf("Expected a point beside the prime meridian to snap to it, got %v", p)
+		}
+	}
+}
+
+// Ensures that Quantize rounds each coordinate to the passed in precision.
+func TestQuantize(t *testing.T) {
+	points := Quantize([]*Point{NewPoint(37.7749295, -122.4194155), nil}, 4)
+
+	if math.Abs(points[0].Lat()-37.7749) > 1e-12 || math.Abs(points[0].Lng()+122.4194) > 1e-12 {
+		t.Errorf("Expected [37.7749, -122.4194], got [%v, %v]", points[0].Lat(), points[0].Lng())
+	}
+
+	if points[1] != nil {
+		t.Errorf("Expected a nil point to stay nil, got %v", points[1])
+	}
+}
+
+// Ensures that SamplePoints thins crowded cells to maxPerCell points while keeping sparse points.
+func TestSamplePoints(t *testing.T) {
+	var points []*Point
+	for i := 0; i < 1000; i++ {
+		points = append(points, NewPoint(51.5007+float64(i%10)*1e-5, -0.1246+float64(i/10)*1e-5))
+	}
+	lonely := []*Point{NewPoint(48.8584, 2.2945), NewPoint(40.6892, -74.0445)}
+	points = append(points, lonely[0], nil, lonely[1])
+
+	sample := SamplePoints(points, 10, 1000)
+	if len(sample) != 12 {
+		t.Fatalf("Expected 10 points from the cluster and both lonely points, got %d", len(sample))
+	}
+
+	if sample[10] != lonely[0] || sample[11] != lonely[1] {
+		t.Errorf("Expected the lonely points to be kept in order, got %v and %v", sample[10], sample[11])
+	}
+
+	// The points kept from the cluster should be spread through it, ending with its last.
+	if sample[9] != points[999] || sample[0] == points[0] && sample[1] == points[1] {
+		t.Errorf("Expected the cluster's points to be sampled evenly, got %v", sample[:10])
+	}
+
+	if sample := SamplePoints(points, 2000, 1000); len(sample) != 1002 {
+		t.Errorf("Expected every point when no cell is crowded, got %d", len(sample))
+	}
+
+	if sample := SamplePoints(points, 0, 1000); len(sample) != 0 {
+		t.Errorf("Expected no points for a maxPerCell of 0, got %d", len(sample))
+	}
+}
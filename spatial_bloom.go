@@ -0,0 +1,136 @@
+package geo
+
+import (
+	"hash/fnv"
+	"math"
+	"sync/atomic"
+)
+
+// A SpatialBloomFilter remembers, in a fixed amount of memory, which geohash cells points have been
+// seen in, so that streams of events too large to remember in full can be deduplicated by place.
+// Like any Bloom filter, it may answer that a cell has been seen when it hasn't, at the false positive
+// rate it was created with, but never that a cell hasn't been seen when it has.
+// A SpatialBloomFilter is safe for concurrent use.
+type SpatialBloomFilter struct {
+	precision int
+	bits      []atomic.Uint64
+	m         uint64
+	k         int
+}
+
+// Creates and returns a pointer to a new SpatialBloomFilter remembering cells of geohashes of the passed in
+// precision, which is clamped to between 1 and MAX_GEOHASH_PRECISION, and sized so that once the expected
+// number of cells have been added, cells that haven't are taken to have been seen at the passed in rate.
+// Precision 7 cells are about 150m across; 8, about 40m.
+func NewSpatialBloomFilter(precision int, expected int, falsePositiveRate float64) *SpatialBloomFilter {
+	n := float64(max(expected, 1))
+	rate := math.Max(math.Min(falsePositiveRate, 0.5), 1e-12)
+
+	// The optimal number of bits for n items at the rate, and of hashes for that many bits.
+	m := uint64(math.Ceil(-n * math.Log(rate) / (math.Ln2 * math.Ln2)))
+	m = (m + 63) / 64 * 64
+	k := max(1, int(math.Round(float64(m)/n*math.Ln2)))
+
+	return &SpatialBloomFilter{
+		precision: max(1, min(precision, MAX_GEOHASH_PRECISION)),
+		bits:      make([]atomic.Uint64, m/64),
+		m:         m,
+		k:         k,
+	}
+}
+
+// Returns the two hashes of the passed in cell whose combinations choose its bits.
+func (f *SpatialBloomFilter) hashes(cell string) (uint64, uint64) {
+	h := fnv.New64a()
+	h.Write([]byte(cell))
+	h1 := h.Sum64()
+	return h1, mixHash(h1) | 1
+}
+
+// Returns whether or not every bit of the passed in cell is set.
+func (f *SpatialBloomFilter) has(cell string) bool {
+	h1, h2 := f.hashes(cell)
+	for i := 0; i < f.k; i++ {
+		bit := (h1 + uint64(i)*h2) % f.m
+		if f.bits[bit/64].Load()&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Sets every bit of the passed in cell, returning whether or not they were all set already.
+func (f *SpatialBloomFilter) set(cell string) bool {
+	seen := true
+	h1, h2 := f.hashes(cell)
+	for i := 0; i < f.k; i++ {
+		bit := (h1 + uint64(i)*h2) % f.m
+		mask := uint64(1) << (bit % 64)
+		if f.bits[bit/64].Or(mask)&mask == 0 {
+			seen = false
+		}
+	}
+
+	return seen
+}
+
+// Returns the geohash cell of the passed in point, at the filter's precision.
+func (f *SpatialBloomFilter) cell(p *Point) string {
+	return p.Geohash(f.precision)
+}
+
+// Remembers the cell the passed in point lies in.
+func (f *SpatialBloomFilter) Add(p *Point) {
+	f.set(f.cell(p))
+}
+
+// Returns whether or not a point in the same cell as the passed in point has probably been added.
+func (f *SpatialBloomFilter) Contains(p *Point) bool {
+	return f.has(f.cell(p))
+}
+
+// Returns whether or not a point in the same cell as the passed in point, or in one of the eight cells
+// around it, has probably been added.  Unlike Contains, this finds points just across a cell's edge,
+// so every added point within a cell's width of the passed in point is found.
+func (f *SpatialBloomFilter) ContainsNear(p *Point) bool {
+	for _, cell := range geohashNeighbourhood(f.cell(p)) {
+		if f.has(cell) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Remembers the cell the passed in point lies in, returning whether or not it had probably
+// been seen before, so that a stream can be deduplicated with one call per point.
+func (f *SpatialBloomFilter) TestAndAdd(p *Point) bool {
+	return f.set(f.cell(p))
+}
+
+// Returns the passed in geohash and the geohashes of the same precision around it,
+// wrapping across the antimeridian and leaving out cells beyond the poles.
+func geohashNeighbourhood(hash string) []string {
+	minLat, minLng, maxLat, maxLng, err := DecodeGeohashBounds(hash)
+	if err != nil {
+		return nil
+	}
+
+	height, width := maxLat-minLat, maxLng-minLng
+	lat, lng := (minLat+maxLat)/2, (minLng+maxLng)/2
+
+	cells := []string{hash}
+	for _, dLat := range []float64{-1, 0, 1} {
+		for _, dLng := range []float64{-1, 0, 1} {
+			neighbourLat := lat + dLat*height
+			if dLat == 0 && dLng == 0 || math.Abs(neighbourLat) > 90 {
+				continue
+			}
+
+			cells = append(cells, EncodeGeohash(neighbourLat, NormalizeLng(lng+dLng*width), len(hash)))
+		}
+	}
+
+	return cells
+}
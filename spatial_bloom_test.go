@@ -0,0 +1,71 @@
+package geo
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// Ensures that added cells are always found, and that cells that weren't are found at about the chosen rate.
+func TestSpatialBloomFilter(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	f := NewSpatialBloomFilter(7, 10000, 0.01)
+
+	var added []*Point
+	for i := 0; i < 10000; i++ {
+		p := NewPoint(r.Float64()*180-90, r.Float64()*360-180)
+		f.Add(p)
+		added = append(added, p)
+	}
+
+	for _, p := range added {
+		if !f.Contains(p) {
+			t.Fatalf("Expected %v to have been seen", p)
+		}
+	}
+
+	falsePositives := 0
+	for i := 0; i < 10000; i++ {
+		if f.Contains(NewPoint(r.Float64()*180-90, r.Float64()*360-180)) {
+			falsePositives++
+		}
+	}
+
+	if falsePositives > 200 {
+		t.Errorf("Expected about 1%% false positives, got %d in 10000", falsePositives)
+	}
+}
+
+// Ensures that TestAndAdd reports whether a cell was new, and ContainsNear finds points in neighbouring cells.
+func TestSpatialBloomFilterNear(t *testing.T) {
+	f := NewSpatialBloomFilter(7, 1000, 0.001)
+
+	p := NewPoint(51.50073, -0.12462)
+	if f.TestAndAdd(p) {
+		t.Error("Expected the first point to be new")
+	}
+
+	if !f.TestAndAdd(NewPoint(51.50074, -0.12463)) {
+		t.Error("Expected a point in the same cell to have been seen")
+	}
+
+	// Step just across the cell's eastern edge.
+	_, _, _, maxLng, _ := DecodeGeohashBounds(p.Geohash(7))
+	across := NewPoint(51.50073, maxLng+0.0001)
+	if f.Contains(across) {
+		t.Error("Expected a point in the next cell not to have been seen")
+	}
+
+	if !f.ContainsNear(across) {
+		t.Error("Expected a point in the next cell to be near one that has been seen")
+	}
+
+	if f.ContainsNear(NewPoint(48.8584, 2.2945)) {
+		t.Error("Expected a distant point not to be near any seen")
+	}
+
+	// Neighbours wrap around the antimeridian.
+	f.Add(NewPoint(0, 179.9999))
+	if !f.ContainsNear(NewPoint(0, -179.9999)) {
+		t.Error("Expected neighbouring cells across the antimeridian")
+	}
+}
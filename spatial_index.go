@@ -0,0 +1,480 @@
+package geo
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// Returns the rects covering the passed in Bounds: one, or one for
+// each side of the antimeridian if the Bounds cross it.
+func boundsRects(b *Bounds) []rect {
+	sw, ne := b.SouthWest(), b.NorthEast()
+	west, east := NormalizeLng(sw.lng), NormalizeLng(ne.lng)
+	if west <= east {
+		return []rect{{sw.lat, west, ne.lat, east}}
+	}
+
+	return []rect{{sw.lat, west, ne.lat, 180}, {sw.lat, -180, ne.lat, east}}
+}
+
+// An RTree associates values of any type with Bounds, and finds the values whose
+// Bounds contain a point or overlap an area without testing each in turn.
+// Values may be added at any time, and are found by searches straight away, but only those added
+// before the last call to Build are indexed; those added since are tested in turn, so call Build
+// once values have been added.  Searches only read the RTree, and an RTree is safe for concurrent use.
+type RTree[T any] struct {
+	mu     sync.RWMutex
+	values []T
+	rects  []rect
+	owners []int
+	index  *rtree
+
+	// The number of rects the index holds; those after them have been inserted since it was built.
+	indexed int
+}
+
+// Creates and returns a pointer to a new, empty RTree.
+func NewRTree[T any]() *RTree[T] {
+	return &RTree[T]{}
+}
+
+// Adds the passed in value to the RTree, covering the passed in Bounds.
+// Bounds whose west edge is east of their east edge are taken to cross the antimeridian.
+func (t *RTree[T]) Insert(b *Bounds, value T) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	id := len(t.values)
+	t.values = append(t.values, value)
+	for _, r := range boundsRects(b) {
+		t.rects = append(t.rects, r)
+		t.owners = append(t.owners, id)
+	}
+}
+
+// Indexes the values added since the RTree was last built, so that searches find them without testing each.
+func (t *RTree[T]) Build() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.index == nil || t.indexed < len(t.rects) {
+		t.index, t.indexed = newRTree(t.rects), len(t.rects)
+	}
+}
+
+// Returns the number of values in the RTree.
+func (t *RTree[T]) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return len(t.values)
+}
+
+// Returns the values whose Bounds contain the passed in point, in the order they were inserted.
+func (t *RTree[T]) Search(p *Point) []T {
+	p = p.Normalize()
+	return t.searchRects([]rect{{p.lat, p.lng, p.lat, p.lng}})
+}
+
+// Returns the values whose Bounds overlap the passed in Bounds, in the order they were inserted.
+func (t *RTree[T]) SearchBounds(b *Bounds) []T {
+	return t.searchRects(boundsRects(b))
+}
+
+// Returns the values with a rect overlapping any of the passed in rects, each only once.
+func (t *RTree[T]) searchRects(rects []rect) []T {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var ids []int
+	seen := make(map[int]bool)
+	found := func(id int) {
+		if owner := t.owners[id]; !seen[owner] {
+			seen[owner] = true
+			ids = append(ids, owner)
+		}
+	}
+	for _, r := range rects {
+		if t.index != nil {
+			t.index.searchRect(r, found)
+		}
+		for id := t.indexed; id < len(t.rects); id++ {
+			if t.rects[id].intersects(r) {
+				found(id)
+			}
+		}
+	}
+	sort.Ints(ids)
+
+	values := make([]T, len(ids))
+	for i, id := range ids {
+		values[i] = t.values[id]
+	}
+
+	return values
+}
+
+// A value found in an RTree, along with its Bounds and their distance from the search point.
+type RTreeResult[T any] struct {
+	Bounds *Bounds
+	Value  T
+
+	// The great circle distance, in kilometers, from the point searched for
+	// to the nearest point of the Bounds; 0 if they contain it.
+	Distance float64
+}
+
+// Returns up to k values whose Bounds are nearest to the passed in point, nearest first,
+// with their distances.  Values whose Bounds contain the point are at a distance of 0.
+// If maxRadius is positive, values further than maxRadius kilometers away are left out.
+func (t *RTree[T]) KNearest(p *Point, k int, maxRadius float64) []RTreeResult[T] {
+	if k <= 0 {
+		return nil
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	p = p.Normalize()
+	var results []RTreeResult[T]
+	seen := make(map[int]bool)
+	found := func(id int, distance float64) bool {
+		if maxRadius > 0 && distance > maxRadius {
+			return false
+		}
+
+		// Values crossing the antimeridian have two rects; the first found is the nearest.
+		if owner := t.owners[id]; !seen[owner] {
+			seen[owner] = true
+			results = append(results, RTreeResult[T]{Bounds: t.bounds(owner), Value: t.values[owner], Distance: distance})
+		}
+
+		return len(results) < k
+	}
+
+	// Rects inserted since the index was built are measured in turn, and merged in by distance.
+	pending := make([]rectDistance, 0, len(t.rects)-t.indexed)
+	for id := t.indexed; id < len(t.rects); id++ {
+		pending = append(pending, rectDistance{id, t.rects[id].distance(p)})
+	}
+	sort.SliceStable(pending, func(i, j int) bool { return pending[i].distance < pending[j].distance })
+
+	more := true
+	if t.index != nil {
+		t.index.nearest(p, func(id int, distance float64) bool {
+			for ; len(pending) > 0 && pending[0].distance <= distance; pending = pending[1:] {
+				if more = found(pending[0].id, pending[0].distance); !more {
+					return false
+				}
+			}
+
+			more = found(id, distance)
+			return more
+		})
+	}
+	for ; more && len(pending) > 0; pending = pending[1:] {
+		more = found(pending[0].id, pending[0].distance)
+	}
+
+	return results
+}
+
+// The id of a rect and its distance, in kilometers, from a point searched for.
+type rectDistance struct {
+	id       int
+	distance float64
+}
+
+// Returns the Bounds the value with the passed in id was inserted with.
+func (t *RTree[T]) bounds(id int) *Bounds {
+	var b *Bounds
+	for i, owner := range t.owners {
+		if owner != id {
+			continue
+		}
+
+		r := t.rects[i]
+		if b == nil {
+			b = NewBounds(NewPoint(r.minLat, r.minLng), NewPoint(r.maxLat, r.maxLng))
+		} else {
+			// The east half of Bounds split at the antimeridian.
+			b = NewBounds(b.SouthWest(), NewPoint(r.maxLat, r.maxLng))
+		}
+	}
+
+	return b
+}
+
+// A point held in a KDTree, with its value and position on the unit sphere.
+type kdEntry[T any] struct {
+	point *Point
+	value T
+	v     [3]float64
+}
+
+// A node of a KDTree, splitting its entries on one axis of the unit sphere.
+type kdNode[T any] struct {
+	entry       *kdEntry[T]
+	axis        int
+	left, right *kdNode[T]
+}
+
+// A KDTree associates values of any type with points, and finds the values nearest
+// to a point without measuring the distance to each.  Points are indexed by their
+// position on a sphere, so searches are unaffected by the antimeridian and the poles.
+// Values may be added at any time, and are found by searches straight away, but only those added
+// before the last call to Build are indexed; those added since are measured in turn, so call Build
+// once values have been added.  Searches only read the KDTree, and a KDTree is safe for concurrent use.
+type KDTree[T any] struct {
+	mu      sync.RWMutex
+	entries []*kdEntry[T]
+	root    *kdNode[T]
+
+	// The number of entries the tree holds; those after them have been inserted since it was built.
+	indexed int
+}
+
+// A value found in a KDTree, along with its point and that point's distance from the search point.
+type KDResult[T any] struct {
+	Point *Point
+	Value T
+
+	// The great circle distance, in kilometers, from the point searched for.
+	Distance float64
+}
+
+// Creates and returns a pointer to a new, empty KDTree.
+func NewKDTree[T any]() *KDTree[T] {
+	return &KDTree[T]{}
+}
+
+// Adds the passed in value to the KDTree at the passed in point.
+func (t *KDTree[T]) Insert(p *Point, value T) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.entries = append(t.entries, &kdEntry[T]{point: p, value: value, v: unitVector(p)})
+}
+
+// Returns the number of values in the KDTree.
+func (t *KDTree[T]) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return len(t.entries)
+}
+
+// Indexes the values added since the KDTree was last built, so that searches find them without measuring each.
+func (t *KDTree[T]) Build() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.indexed == len(t.entries) {
+		return
+	}
+
+	entries := make([]*kdEntry[T], len(t.entries))
+	copy(entries, t.entries)
+	t.root, t.indexed = buildKDNode(entries, 0), len(entries)
+}
+
+// Returns a balanced tree over the passed in entries, splitting on the passed in axis first.
+func buildKDNode[T any](entries []*kdEntry[T], axis int) *kdNode[T] {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].v[axis] < entries[j].v[axis] })
+	mid := len(entries) / 2
+
+	return &kdNode[T]{
+		entry: entries[mid],
+		axis:  axis,
+		left:  buildKDNode(entries[:mid], (axis+1)%3),
+		right: buildKDNode(entries[mid+1:], (axis+1)%3),
+	}
+}
+
+// Returns the squared straight-line distance between two points on the unit sphere,
+// which orders points the same way as the great circle distance.
+func chordDistance2(a, b [3]float64) float64 {
+	dx, dy, dz := a[0]-b[0], a[1]-b[1], a[2]-b[2]
+	return dx*dx + dy*dy + dz*dz
+}
+
+// Returns the squared chord length on the unit sphere for the passed in great circle distance in kilometers.
+func chordForDistance2(km float64) float64 {
+	angle := math.Min(km/EARTH_RADIUS, math.Pi)
+	chord := 2 * math.Sin(angle/2)
+	return chord * chord
+}
+
+// Returns the value nearest to the passed in point, and false if the KDTree is empty.
+func (t *KDTree[T]) Nearest(p *Point) (KDResult[T], bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if len(t.entries) == 0 {
+		return KDResult[T]{}, false
+	}
+
+	target := unitVector(p)
+	var best *kdEntry[T]
+	bestDist := math.Inf(1)
+
+	var visit func(n *kdNode[T])
+	visit = func(n *kdNode[T]) {
+		if n == nil {
+			return
+		}
+
+		if d := chordDistance2(target, n.entry.v); d < bestDist {
+			best, bestDist = n.entry, d
+		}
+
+		diff := target[n.axis] - n.entry.v[n.axis]
+		near, far := n.left, n.right
+		if diff >= 0 {
+			near, far = n.right, n.left
+		}
+
+		visit(near)
+		if diff*diff < bestDist {
+			visit(far)
+		}
+	}
+	visit(t.root)
+
+	for _, e := range t.entries[t.indexed:] {
+		if d := chordDistance2(target, e.v); d < bestDist {
+			best, bestDist = e, d
+		}
+	}
+
+	return KDResult[T]{Point: best.point, Value: best.value, Distance: p.GreatCircleDistance(best.point)}, true
+}
+
+// Returns the values within the passed in distance, in kilometers, of the passed in point,
+// nearest first.
+func (t *KDTree[T]) Within(p *Point, km float64) []KDResult[T] {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	target := unitVector(p)
+	limit := chordForDistance2(km)
+
+	var results []KDResult[T]
+	consider := func(e *kdEntry[T]) {
+		if chordDistance2(target, e.v) <= limit {
+			if d := p.GreatCircleDistance(e.point); d <= km {
+				results = append(results, KDResult[T]{Point: e.point, Value: e.value, Distance: d})
+			}
+		}
+	}
+
+	var visit func(n *kdNode[T])
+	visit = func(n *kdNode[T]) {
+		if n == nil {
+			return
+		}
+
+		consider(n.entry)
+
+		diff := target[n.axis] - n.entry.v[n.axis]
+		if diff <= 0 || diff*diff <= limit {
+			visit(n.left)
+		}
+		if diff >= 0 || diff*diff <= limit {
+			visit(n.right)
+		}
+	}
+	visit(t.root)
+	for _, e := range t.entries[t.indexed:] {
+		consider(e)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Distance < results[j].Distance })
+	return results
+}
+
+// Returns up to k values nearest to the passed in point, nearest first, with their distances.
+// If maxRadius is positive, values further than maxRadius kilometers away are left out.
+func (t *KDTree[T]) KNearest(p *Point, k int, maxRadius float64) []KDResult[T] {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if k <= 0 || len(t.entries) == 0 {
+		return nil
+	}
+
+	target := unitVector(p)
+	limit := math.Inf(1)
+	if maxRadius > 0 {
+		// Allow for rounding at the edge; the great circle distance is checked exactly below.
+		limit = chordForDistance2(maxRadius) * (1 + 1e-9)
+	}
+
+	// The nearest entries found so far, nearest first, and their chord distances.
+	var best []*kdEntry[T]
+	var bestDists []float64
+
+	consider := func(e *kdEntry[T]) {
+		if d := chordDistance2(target, e.v); d <= limit && (len(best) < k || d < bestDists[len(best)-1]) {
+			i := sort.SearchFloat64s(bestDists, d)
+			for i < len(bestDists) && bestDists[i] == d {
+				i++
+			}
+
+			best = append(best[:i], append([]*kdEntry[T]{e}, best[i:]...)...)
+			bestDists = append(bestDists[:i], append([]float64{d}, bestDists[i:]...)...)
+			if len(best) > k {
+				best, bestDists = best[:k], bestDists[:k]
+			}
+		}
+	}
+
+	// Entries inserted since the tree was built are measured first, so that they narrow the search of it.
+	for _, e := range t.entries[t.indexed:] {
+		consider(e)
+	}
+
+	var visit func(n *kdNode[T])
+	visit = func(n *kdNode[T]) {
+		if n == nil {
+			return
+		}
+
+		consider(n.entry)
+
+		diff := target[n.axis] - n.entry.v[n.axis]
+		near, far := n.left, n.right
+		if diff >= 0 {
+			near, far = n.right, n.left
+		}
+
+		visit(near)
+
+		// The far side can only hold nearer entries if the splitting plane is nearer than the kth.
+		bound := limit
+		if len(best) == k {
+			bound = math.Min(bound, bestDists[k-1])
+		}
+		if diff*diff <= bound {
+			visit(far)
+		}
+	}
+	visit(t.root)
+
+	results := make([]KDResult[T], 0, len(best))
+	for _, e := range best {
+		d := p.GreatCircleDistance(e.point)
+		if maxRadius > 0 && d > maxRadius {
+			continue
+		}
+
+		results = append(results, KDResult[T]{Point: e.point, Value: e.value, Distance: d})
+	}
+
+	return results
+}
@@ -0,0 +1,277 @@
+package geo
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+)
+
+// A payload of the kind users associate with geometries.
+type deliveryZone struct {
+	name string
+	fee  int
+}
+
+// Ensures that an RTree returns the values whose bounds contain a point or overlap an area.
+func TestRTree(t *testing.T) {
+	tree := NewRTree[deliveryZone]()
+	tree.Insert(NewBounds(NewPoint(37.70, -122.52), NewPoint(37.81, -122.35)), deliveryZone{"San Francisco", 5})
+	tree.Insert(NewBounds(NewPoint(37.75, -122.30), NewPoint(37.90, -122.10)), deliveryZone{"Oakland", 7})
+	tree.Insert(NewBounds(NewPoint(-21, 177), NewPoint(-12, -178)), deliveryZone{"Fiji", 20})
+
+	if tree.Len() != 3 {
+		t.Errorf("Expected 3 values, got %d", tree.Len())
+	}
+
+	found := tree.Search(NewPoint(37.7749, -122.4194))
+	if len(found) != 1 || found[0].name != "San Francisco" || found[0].fee != 5 {
+		t.Errorf("Expected San Francisco, got %v", found)
+	}
+
+	if found := tree.Search(NewPoint(-16.5, -179.9)); len(found) != 1 || found[0].name != "Fiji" {
+		t.Errorf("Expected Fiji either side of the antimeridian, got %v", found)
+	}
+
+	if found := tree.Search(NewPoint(47.6, -122.3)); len(found) != 0 {
+		t.Errorf("Expected nothing in Seattle, got %v", found)
+	}
+
+	bay := NewBounds(NewPoint(37.5, -122.6), NewPoint(38, -122))
+	if found := tree.SearchBounds(bay); len(found) != 2 || found[0].name != "San Francisco" || found[1].name != "Oakland" {
+		t.Errorf("Expected San Francisco and Oakland in insertion order, got %v", found)
+	}
+
+	if found := tree.SearchBounds(NewBounds(NewPoint(-20, 170), NewPoint(-10, -170))); len(found) != 1 {
+		t.Errorf("Expected Fiji to be found once by a search crossing the antimeridian, got %v", found)
+	}
+}
+
+// Ensures that a KDTree finds the same nearest values as measuring the distance to each.
+func TestKDTree(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	tree := NewKDTree[int]()
+	var points []*Point
+	for i := 0; i < 1000; i++ {
+		p := NewPoint(r.Float64()*180-90, r.Float64()*360-180)
+		points = append(points, p)
+		tree.Insert(p, i)
+	}
+
+	for i := 0; i < 100; i++ {
+		q := NewPoint(r.Float64()*180-90, r.Float64()*360-180)
+
+		want, wantDist := -1, 0.0
+		var within []int
+		for j, p := range points {
+			d := q.GreatCircleDistance(p)
+			if want < 0 || d < wantDist {
+				want, wantDist = j, d
+			}
+			if d <= 1000 {
+				within = append(within, j)
+			}
+		}
+
+		got, ok := tree.Nearest(q)
+		if !ok || got.Value != want || got.Distance != wantDist {
+			t.Fatalf("Expected %d at %fkm to be nearest, got %d at %fkm", want, wantDist, got.Value, got.Distance)
+		}
+
+		results := tree.Within(q, 1000)
+		if len(results) != len(within) {
+			t.Fatalf("Expected %d values within 1000km, got %d", len(within), len(results))
+		}
+
+		for j := 1; j < len(results); j++ {
+			if results[j].Distance < results[j-1].Distance {
+				t.Fatalf("Expected results nearest first, got %f then %f", results[j-1].Distance, results[j].Distance)
+			}
+		}
+	}
+}
+
+// Ensures that an empty KDTree finds nothing, and that values added later are found.
+func TestKDTreeEmpty(t *testing.T) {
+	tree := NewKDTree[string]()
+	if _, ok := tree.Nearest(NewPoint(0, 0)); ok {
+		t.Error("Expected an empty tree to have no nearest value")
+	}
+
+	tree.Insert(NewPoint(0, 179.9), "east")
+	tree.Nearest(NewPoint(0, 0))
+	tree.Insert(NewPoint(0, -179.9), "west")
+
+	if got, _ := tree.Nearest(NewPoint(0, -179.95)); got.Value != "west" {
+		t.Errorf("Expected a value added after a search to be found, got %s", got.Value)
+	}
+
+	if results := tree.Within(NewPoint(0, 180), 20); len(results) != 2 {
+		t.Errorf("Expected both values near the antimeridian, got %d", len(results))
+	}
+}
+
+// Ensures that a KDTree finds the same k nearest values as measuring the distance to each.
+func TestKDTreeKNearest(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	tree := NewKDTree[int]()
+	var points []*Point
+	for i := 0; i < 1000; i++ {
+		p := NewPoint(r.Float64()*180-90, r.Float64()*360-180)
+		points = append(points, p)
+		tree.Insert(p, i)
+	}
+
+	for i := 0; i < 100; i++ {
+		q := NewPoint(r.Float64()*180-90, r.Float64()*360-180)
+		distances := make([]float64, len(points))
+		for j, p := range points {
+			distances[j] = q.GreatCircleDistance(p)
+		}
+		sort.Float64s(distances)
+
+		results := tree.KNearest(q, 10, 0)
+		if len(results) != 10 {
+			t.Fatalf("Expected 10 values, got %d", len(results))
+		}
+
+		for j, result := range results {
+			if result.Distance != distances[j] || q.GreatCircleDistance(points[result.Value]) != result.Distance {
+				t.Fatalf("Expected value %d at %fkm, got %d at %fkm", j, distances[j], result.Value, result.Distance)
+			}
+		}
+
+		limited := tree.KNearest(q, 10, distances[4])
+		if len(limited) != 5 || limited[4].Distance != distances[4] {
+			t.Fatalf("Expected the 5 values within %fkm, got %d", distances[4], len(limited))
+		}
+	}
+
+	if results := tree.KNearest(NewPoint(0, 0), 0, 0); len(results) != 0 {
+		t.Errorf("Expected no values for k of 0, got %d", len(results))
+	}
+
+	if results := tree.KNearest(NewPoint(0, 0), 2000, 0); len(results) != 1000 {
+		t.Errorf("Expected every value for k beyond the size of the tree, got %d", len(results))
+	}
+}
+
+// Ensures that an RTree finds the values whose bounds are nearest to a point, containing bounds first.
+func TestRTreeKNearest(t *testing.T) {
+	tree := NewRTree[deliveryZone]()
+	tree.Insert(NewBounds(NewPoint(37.70, -122.52), NewPoint(37.81, -122.35)), deliveryZone{"San Francisco", 5})
+	tree.Insert(NewBounds(NewPoint(37.75, -122.30), NewPoint(37.90, -122.10)), deliveryZone{"Oakland", 7})
+	tree.Insert(NewBounds(NewPoint(-21, 177), NewPoint(-12, -178)), deliveryZone{"Fiji", 20})
+	for i := 0; i < 100; i++ {
+		lat := float64(i%10) * 5
+		lng := float64(i/10)*5 + 10
+		tree.Insert(NewBounds(NewPoint(lat, lng), NewPoint(lat+1, lng+1)), deliveryZone{"grid", i})
+	}
+
+	results := tree.KNearest(NewPoint(37.7749, -122.4194), 2, 0)
+	if len(results) != 2 || results[0].Value.name != "San Francisco" || results[0].Distance != 0 || results[1].Value.name != "Oakland" {
+		t.Fatalf("Expected San Francisco then Oakland, got %v", results)
+	}
+
+	// Oakland's west edge is at -122.30, about 10.5km east.
+	if d := results[1].Distance; math.Abs(d-10.5) > 0.2 {
+		t.Errorf("Expected Oakland about 10.5km away, got %f", d)
+	}
+
+	if results := tree.KNearest(NewPoint(-16.5, 179.9), 5, 100); len(results) != 1 || results[0].Value.name != "Fiji" {
+		t.Errorf("Expected only Fiji within 100km, found once, got %v", results)
+	} else if sw, ne := results[0].Bounds.SouthWest(), results[0].Bounds.NorthEast(); sw.lng != 177 || ne.lng != -178 {
+		t.Errorf("Expected Fiji's bounds to cross the antimeridian, got %v", results[0].Bounds)
+	}
+
+	// Compare against measuring the distance to each grid square.
+	q := NewPoint(12.3, 31.7)
+	var distances []float64
+	for i := 0; i < 100; i++ {
+		lat := float64(i%10) * 5
+		lng := float64(i/10)*5 + 10
+		distances = append(distances, rect{lat, lng, lat + 1, lng + 1}.distance(q))
+	}
+	sort.Float64s(distances)
+
+	for i, result := range tree.KNearest(q, 20, 0) {
+		if result.Distance != distances[i] {
+			t.Errorf("Expected value %d at %fkm, got %fkm", i, distances[i], result.Distance)
+		}
+	}
+}
+
+// Ensures that values inserted since a tree was last built are found alongside those indexed.
+func TestSpatialIndexBuild(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	kd, partial := NewKDTree[int](), NewKDTree[int]()
+	rt, partialRT := NewRTree[int](), NewRTree[int]()
+	for i := 0; i < 500; i++ {
+		p := NewPoint(r.Float64()*40, r.Float64()*40)
+		b := NewBounds(p, NewPoint(p.lat+r.Float64(), p.lng+r.Float64()))
+		kd.Insert(p, i)
+		partial.Insert(p, i)
+		rt.Insert(b, i)
+		partialRT.Insert(b, i)
+		if i == 300 {
+			partial.Build()
+			partialRT.Build()
+		}
+	}
+	kd.Build()
+	rt.Build()
+
+	for i := 0; i < 50; i++ {
+		q := NewPoint(r.Float64()*40, r.Float64()*40)
+		if a, b := kd.KNearest(q, 10, 0), partial.KNearest(q, 10, 0); len(a) != len(b) || a[9].Value != b[9].Value {
+			t.Errorf("Expected the same nearest values, got %v and %v", a, b)
+		}
+		if a, b := kd.Within(q, 300), partial.Within(q, 300); len(a) != len(b) {
+			t.Errorf("Expected the same values within 300km, got %d and %d", len(a), len(b))
+		}
+		if a, b := rt.KNearest(q, 10, 0), partialRT.KNearest(q, 10, 0); len(a) != len(b) || a[9].Distance != b[9].Distance {
+			t.Errorf("Expected the same nearest bounds, got %v and %v", a, b)
+		}
+		if a, b := rt.Search(q), partialRT.Search(q); len(a) != len(b) {
+			t.Errorf("Expected the same bounds containing %v, got %v and %v", q, a, b)
+		}
+	}
+}
+
+// Ensures that trees may be searched and added to concurrently; run with -race.
+func TestSpatialIndexConcurrency(t *testing.T) {
+	kd, rt := NewKDTree[int](), NewRTree[int]()
+	for i := 0; i < 100; i++ {
+		p := NewPoint(float64(i%10), float64(i/10))
+		kd.Insert(p, i)
+		rt.Insert(NewBounds(p, NewPoint(p.lat+1, p.lng+1)), i)
+	}
+	kd.Build()
+	rt.Build()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				p := NewPoint(float64(i%10)+0.5, float64(g))
+				kd.Within(p, 100)
+				kd.Nearest(p)
+				rt.Search(p)
+				rt.KNearest(p, 3, 0)
+				if g == 0 && i%10 == 0 {
+					kd.Insert(p, i)
+					rt.Insert(NewBounds(p, p), i)
+					kd.Build()
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if kd.Len() != 110 || rt.Len() != 110 {
+		t.Errorf("Expected 110 values in each tree, got %d and %d", kd.Len(), rt.Len())
+	}
+}
@@ -0,0 +1,43 @@
+package geo
+
+// Returns, for each of the passed in points, the index of the polygon containing it,
+// or -1 if none do.  Where polygons overlap, the one earliest in the slice wins.
+// For example, with orders as points and delivery zones as polygons, the result
+// says which zone each order is in.
+//
+// Polygons are indexed by their bounding boxes in an R-tree, so that each point is
+// only tested against the few polygons that might contain it.
+func AssignPointsToPolygons(points []*Point, polygons []*Polygon) []int {
+	// Polygons crossing the antimeridian are indexed as one box for each side.
+	var rects []rect
+	var owners []int
+	for i, polygon := range polygons {
+		if !polygon.IsClosed() {
+			continue
+		}
+
+		for _, part := range polygon.SplitAtAntimeridian() {
+			rects = append(rects, rectOf(part.Points()))
+			owners = append(owners, i)
+		}
+	}
+
+	index := newRTree(rects)
+
+	assignments := make([]int, len(points))
+	for i, p := range points {
+		assignments[i] = -1
+		index.search(p, func(id int) {
+			owner := owners[id]
+			if assignments[i] != -1 && owner > assignments[i] {
+				return
+			}
+
+			if polygons[owner].Contains(p) {
+				assignments[i] = owner
+			}
+		})
+	}
+
+	return assignments
+}
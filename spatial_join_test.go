@@ -0,0 +1,96 @@
+package geo
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// Returns a square polygon of the passed in size, in degrees, with its south west corner at the passed in point.
+func squarePolygon(lat, lng, size float64) *Polygon {
+	return NewPolygon([]*Point{
+		NewPoint(lat, lng),
+		NewPoint(lat, lng+size),
+		NewPoint(lat+size, lng+size),
+		NewPoint(lat+size, lng),
+	})
+}
+
+// Ensures that points are assigned to the polygon containing them, preferring earlier polygons.
+func TestAssignPointsToPolygons(t *testing.T) {
+	brunei, err := polygonFromFile("test/data/brunei.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	polygons := []*Polygon{
+		squarePolygon(37, -123, 1),
+		brunei,
+		squarePolygon(37.5, -122.5, 1),
+		NewPolygon([]*Point{NewPoint(-21, 177), NewPoint(-21, -178), NewPoint(-12, -178), NewPoint(-12, 177)}),
+	}
+
+	points := []*Point{
+		NewPoint(37.7749, -122.4194),
+		NewPoint(4.9402900, 114.9480600),
+		NewPoint(38.2, -121.8),
+		NewPoint(47.6097, -122.3331),
+		NewPoint(-16.5, -179.9),
+	}
+
+	want := []int{0, 1, 2, -1, 3}
+	got := AssignPointsToPolygons(points, polygons)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected point %d to be assigned to polygon %d, got %d", i, want[i], got[i])
+		}
+	}
+}
+
+// Ensures that the R-tree finds the same polygons as testing each polygon in turn.
+func TestAssignPointsToPolygonsMatchesBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	var polygons []*Polygon
+	for i := 0; i < 500; i++ {
+		polygons = append(polygons, squarePolygon(r.Float64()*100-50, r.Float64()*300-150, r.Float64()*5))
+	}
+
+	var points []*Point
+	for i := 0; i < 2000; i++ {
+		points = append(points, NewPoint(r.Float64()*100-50, r.Float64()*300-150))
+	}
+
+	got := AssignPointsToPolygons(points, polygons)
+	for i, p := range points {
+		want := -1
+		for j, polygon := range polygons {
+			if polygon.Contains(p) {
+				want = j
+				break
+			}
+		}
+
+		if got[i] != want {
+			t.Errorf("Expected point %d to be assigned to polygon %d, got %d", i, want, got[i])
+		}
+	}
+}
+
+func BenchmarkAssignPointsToPolygons(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+
+	var polygons []*Polygon
+	for i := 0; i < 1000; i++ {
+		polygons = append(polygons, squarePolygon(r.Float64()*100-50, r.Float64()*300-150, r.Float64()*2))
+	}
+
+	var points []*Point
+	for i := 0; i < 10000; i++ {
+		points = append(points, NewPoint(r.Float64()*100-50, r.Float64()*300-150))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		AssignPointsToPolygons(points, polygons)
+	}
+}
@@ -14,6 +14,8 @@ type SQLConf struct {
 	table   string
 	latCol  string
 	lngCol  string
+	idCol   string
+	geomCol string
 }
 
 const (
@@ -29,11 +31,11 @@ func sqlConfFromEnv() *SQLConf {
 
 	switch dbEnv {
 	case "mysql":
-		return &SQLConf{driver: "mymysql", openStr: DEFAULT_MYSQL_OPEN_STR, table: "points", latCol: "lat", lngCol: "lng"}
+		return &SQLConf{driver: "mymysql", openStr: DEFAULT_MYSQL_OPEN_STR, table: "points", latCol: "lat", lngCol: "lng", idCol: "id", geomCol: "geom"}
 	case "mock":
-		return &SQLConf{driver: "testdb", openStr: DEFAULT_TEST_OPEN_STR, table: "points", latCol: "lat", lngCol: "lng"}
+		return &SQLConf{driver: "testdb", openStr: DEFAULT_TEST_OPEN_STR, table: "points", latCol: "lat", lngCol: "lng", idCol: "id", geomCol: "geom"}
 	default:
-		return &SQLConf{driver: "postgres", openStr: DEFAULT_PGSQL_OPEN_STR, table: "points", latCol: "lat", lngCol: "lng"}
+		return &SQLConf{driver: "postgres", openStr: DEFAULT_PGSQL_OPEN_STR, table: "points", latCol: "lat", lngCol: "lng", idCol: "id", geomCol: "geom"}
 	}
 }
 
@@ -115,7 +117,19 @@ func confFromYamlFile(config *yaml.File, goEnv string) (*SQLConf, error) {
 		return nil, lngColError
 	}
 
-	sqlConf := &SQLConf{driver: driver, openStr: openStr, table: table, latCol: latCol, lngCol: lngCol}
+	// Get idCol, which older configurations don't have
+	idCol, idColError := config.Get(fmt.Sprintf("%s.idCol", goEnv))
+	if idColError != nil {
+		idCol = "id"
+	}
+
+	// Get geomCol, which older configurations don't have
+	geomCol, geomColError := config.Get(fmt.Sprintf("%s.geomCol", goEnv))
+	if geomColError != nil {
+		geomCol = "geom"
+	}
+
+	sqlConf := &SQLConf{driver: driver, openStr: openStr, table: table, latCol: latCol, lngCol: lngCol, idCol: idCol, geomCol: geomCol}
 	return sqlConf, nil
 
 }
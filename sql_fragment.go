@@ -0,0 +1,183 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The SQL dialects a SQLBuilder writes.
+type SQLDialect int
+
+const (
+	// PostgreSQL, whose placeholders are numbered: $1, $2...
+	PostgresDialect SQLDialect = iota
+
+	// MySQL 8.0 or later, whose placeholders are question marks.
+	MySQLDialect
+
+	// SQLite 3.35 or later built with its math functions, as the common drivers are,
+	// whose placeholders are question marks.
+	SQLiteDialect
+)
+
+// Returns the dialect of the passed in database/sql driver name, e.g. "pgx", "mysql" or "sqlite3".
+// Drivers that are neither MySQL nor SQLite are taken to be PostgreSQL.
+func DialectForDriver(driver string) SQLDialect {
+	switch {
+	case strings.Contains(driver, "mysql"):
+		return MySQLDialect
+	case strings.Contains(driver, "sqlite"):
+		return SQLiteDialect
+	default:
+		return PostgresDialect
+	}
+}
+
+// A SQLFragment is a piece of SQL, such as an expression or a condition, along with the values of its placeholders.
+// Pass its SQL and Args to an ORM's raw query methods, e.g. GORM's db.Where(f.SQL, f.Args...) or sqlx's
+// db.Select(&rows, "SELECT * FROM stores WHERE "+f.SQL, f.Args...).
+type SQLFragment struct {
+	SQL  string
+	Args []interface{}
+}
+
+// Matches a numbered PostgreSQL placeholder.
+var postgresPlaceholder = regexp.MustCompile(`\$(\d+)`)
+
+// Returns the current fragment with its numbered PostgreSQL placeholders shifted by the passed in offset,
+// for use after offset other arguments: with an offset of 2, $1 becomes $3.  Other dialects' fragments
+// are returned as they are.
+func (f SQLFragment) Shift(offset int) SQLFragment {
+	sql := postgresPlaceholder.ReplaceAllStringFunc(f.SQL, func(placeholder string) string {
+		n, _ := strconv.Atoi(placeholder[1:])
+		return "$" + strconv.Itoa(n+offset)
+	})
+
+	return SQLFragment{SQL: sql, Args: f.Args}
+}
+
+// Returns the passed in fragments joined by the passed in separator, such as " AND ",
+// with their arguments in order and their placeholders numbered to match.
+func JoinSQL(sep string, fragments ...SQLFragment) SQLFragment {
+	var joined SQLFragment
+	sqls := make([]string, len(fragments))
+	for i, f := range fragments {
+		sqls[i] = f.Shift(len(joined.Args)).SQL
+		joined.Args = append(joined.Args, f.Args...)
+	}
+
+	joined.SQL = strings.Join(sqls, sep)
+	return joined
+}
+
+// Matches a column name, optionally qualified by its table: "lat" or "stores.lat".
+var sqlColumn = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// A SQLBuilder writes parametrized SQL for the great circle distance to, and the proximity of,
+// the points stored in a pair of latitude and longitude columns of any table, so that applications
+// using an ORM rather than a SQLMapper can reuse the math.  Every value is passed as an argument,
+// never written into the SQL.
+type SQLBuilder struct {
+	dialect SQLDialect
+	latCol  string
+	lngCol  string
+}
+
+// Creates and returns a pointer to a new SQLBuilder for the passed in dialect and latitude and longitude columns,
+// which may be qualified by their table.  Returns an error if a column name isn't a plain identifier,
+// since column names can't be passed as arguments.
+func NewSQLBuilder(dialect SQLDialect, latCol, lngCol string) (*SQLBuilder, error) {
+	for _, col := range []string{latCol, lngCol} {
+		if !sqlColumn.MatchString(col) {
+			return nil, fmt.Errorf("invalid SQL column name %q", col)
+		}
+	}
+
+	return &SQLBuilder{dialect: dialect, latCol: latCol, lngCol: lngCol}, nil
+}
+
+// Returns a SQLBuilder for the SQLMapper's database and columns, e.g. for queries of its own on SqlDbConn.
+func (s *SQLMapper) SQLBuilder() *SQLBuilder {
+	return &SQLBuilder{dialect: DialectForDriver(s.conf.driver), latCol: s.conf.latCol, lngCol: s.conf.lngCol}
+}
+
+// Returns the SQL fragment whose placeholders are the passed in values, numbered from $1 in PostgreSQL.
+func (b *SQLBuilder) fragment(format string, args ...interface{}) SQLFragment {
+	placeholders := make([]interface{}, len(args))
+	for i := range args {
+		placeholders[i] = "?"
+		if b.dialect == PostgresDialect {
+			placeholders[i] = "$" + strconv.Itoa(i+1)
+		}
+	}
+
+	return SQLFragment{SQL: fmt.Sprintf(format, placeholders...), Args: args}
+}
+
+// Returns the name of the function returning the lesser of two values.
+func (b *SQLBuilder) least() string {
+	if b.dialect == SQLiteDialect {
+		return "min"
+	}
+
+	return "least"
+}
+
+// Returns the expression for the great circle distance, in kilometers, from the passed in point to each row's,
+// computed with the haversine formula, e.g. for a SELECT list or an ORDER BY.
+func (b *SQLBuilder) Distance(p *Point) SQLFragment {
+	// Rounding can push the haversine of antipodal points past 1, out of asin's domain.
+	format := fmt.Sprintf("2 * %s * asin(sqrt(%s(1, "+
+		"power(sin(radians(%s - %%s) / 2), 2) + "+
+		"cos(radians(%%s)) * cos(radians(%s)) * power(sin(radians(%s - %%s) / 2), 2))))",
+		sqlFloat(EARTH_RADIUS), b.least(), b.latCol, b.latCol, b.lngCol)
+
+	return b.fragment(format, p.lat, p.lat, p.lng)
+}
+
+// Returns the condition selecting rows whose point lies within the passed in Bounds.
+// Bounds whose west edge is east of their east edge are taken to cross the antimeridian.
+func (b *SQLBuilder) InBounds(bounds *Bounds) SQLFragment {
+	sw, ne := bounds.SouthWest(), bounds.NorthEast()
+	return b.boundsCondition(sw.lat, ne.lat, sw.lng, ne.lng)
+}
+
+// Returns the condition selecting rows whose latitude lies between south and north
+// and whose longitude lies between west and east.
+func (b *SQLBuilder) boundsCondition(south, north, west, east float64) SQLFragment {
+	format := fmt.Sprintf("%s BETWEEN %%s AND %%s AND %s BETWEEN %%s AND %%s", b.latCol, b.lngCol)
+	if west > east {
+		format = fmt.Sprintf("%s BETWEEN %%s AND %%s AND (%s >= %%s OR %s <= %%s)", b.latCol, b.lngCol, b.lngCol)
+	}
+
+	return b.fragment(format, south, north, west, east)
+}
+
+// Returns the condition selecting rows whose point is within the passed in radius, in kilometers,
+// of the passed in point.  The condition first checks that the point lies within the bounding box
+// of the circle, so that an index on the latitude and longitude columns can narrow the rows
+// whose distance is computed.
+func (b *SQLBuilder) WithinRadius(p *Point, km float64) SQLFragment {
+	within := JoinSQL(" <= ", b.Distance(p), b.fragment("%s", km))
+	return JoinSQL(" AND ", b.radiusBounds(p, km), within)
+}
+
+// Returns the condition selecting rows within the bounding box of the circle of the passed in radius,
+// in kilometers, around the passed in point.
+func (b *SQLBuilder) radiusBounds(p *Point, km float64) SQLFragment {
+	angle := km / EARTH_RADIUS
+	dLat := angle * 180 / math.Pi
+	south, north := math.Max(p.lat-dLat, -90), math.Min(p.lat+dLat, 90)
+
+	// Circles reaching a pole, or wider than the parallel they are centered on, span every longitude.
+	cosLat := math.Cos(p.lat * math.Pi / 180)
+	if south == -90 || north == 90 || math.Sin(angle) >= cosLat {
+		return b.fragment(fmt.Sprintf("%s BETWEEN %%s AND %%s", b.latCol), south, north)
+	}
+
+	dLng := math.Asin(math.Sin(angle)/cosLat) * 180 / math.Pi
+	return b.boundsCondition(south, north, NormalizeLng(p.lng-dLng), NormalizeLng(p.lng+dLng))
+}
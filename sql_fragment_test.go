@@ -0,0 +1,94 @@
+package geo
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Ensures that fragments number their PostgreSQL placeholders in the order of their arguments.
+func TestSQLBuilderPostgres(t *testing.T) {
+	b, err := NewSQLBuilder(PostgresDialect, "stores.lat", "stores.lng")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := b.WithinRadius(NewPoint(37.6, -122.4), 10)
+	if !strings.HasPrefix(f.SQL, "stores.lat BETWEEN $1 AND $2 AND stores.lng BETWEEN $3 AND $4 AND 2 * 6371 * asin(") {
+		t.Errorf("Expected a bounding box condition before the distance, got %s", f.SQL)
+	}
+
+	if !strings.HasSuffix(f.SQL, "<= $8") || len(f.Args) != 8 {
+		t.Errorf("Expected 8 numbered arguments, got %s with %v", f.SQL, f.Args)
+	}
+
+	if f.Args[7] != 10.0 || f.Args[4] != 37.6 || f.Args[6] != -122.4 {
+		t.Errorf("Expected the radius last and the point's coordinates in the distance, got %v", f.Args)
+	}
+}
+
+// Ensures that MySQL and SQLite fragments use question marks and their own functions.
+func TestSQLBuilderDialects(t *testing.T) {
+	mysql, _ := NewSQLBuilder(MySQLDialect, "lat", "lng")
+	sqlite, _ := NewSQLBuilder(SQLiteDialect, "lat", "lng")
+
+	for _, b := range []*SQLBuilder{mysql, sqlite} {
+		f := b.Distance(NewPoint(1, 2))
+		if strings.Contains(f.SQL, "$") || strings.Count(f.SQL, "?") != len(f.Args) {
+			t.Errorf("Expected a placeholder per argument, got %s with %v", f.SQL, f.Args)
+		}
+	}
+
+	if !strings.Contains(sqlite.Distance(NewPoint(1, 2)).SQL, "min(1, ") {
+		t.Error("Expected SQLite to bound the haversine with min")
+	}
+
+	if !strings.Contains(mysql.Distance(NewPoint(1, 2)).SQL, "least(1, ") {
+		t.Error("Expected MySQL to bound the haversine with least")
+	}
+
+	if DialectForDriver("sqlite3") != SQLiteDialect || DialectForDriver("mymysql") != MySQLDialect || DialectForDriver("pgx") != PostgresDialect {
+		t.Error("Expected dialects to be told from their driver names")
+	}
+}
+
+// Ensures that bounding boxes crossing the antimeridian or reaching a pole select the right longitudes.
+func TestSQLBuilderBounds(t *testing.T) {
+	b, _ := NewSQLBuilder(MySQLDialect, "lat", "lng")
+
+	f := b.WithinRadius(NewPoint(0, 179.99), 10)
+	if !strings.HasPrefix(f.SQL, "lat BETWEEN ? AND ? AND (lng >= ? OR lng <= ?)") {
+		t.Errorf("Expected the bounding box to wrap around the antimeridian, got %s", f.SQL)
+	}
+	if west, east := f.Args[2].(float64), f.Args[3].(float64); west < 179 || east > -179 {
+		t.Errorf("Expected the bounding box to span the antimeridian, got %v to %v", west, east)
+	}
+
+	f = b.WithinRadius(NewPoint(89.99, 0), 10)
+	if strings.Contains(f.SQL, "lng BETWEEN") || f.Args[1] != 90.0 {
+		t.Errorf("Expected a circle around the pole to span every longitude, got %s with %v", f.SQL, f.Args)
+	}
+
+	f = b.InBounds(NewBounds(NewPoint(-10, 170), NewPoint(10, -170)))
+	if !reflect.DeepEqual(f.Args, []interface{}{-10.0, 10.0, 170.0, -170.0}) {
+		t.Errorf("Expected the bounds' edges as arguments, got %v", f.Args)
+	}
+}
+
+// Ensures that joined fragments renumber their placeholders.
+func TestJoinSQL(t *testing.T) {
+	a := SQLFragment{SQL: "a = $1", Args: []interface{}{1}}
+	b := SQLFragment{SQL: "b BETWEEN $1 AND $2", Args: []interface{}{2, 3}}
+
+	joined := JoinSQL(" AND ", a, b)
+	if joined.SQL != "a = $1 AND b BETWEEN $2 AND $3" || !reflect.DeepEqual(joined.Args, []interface{}{1, 2, 3}) {
+		t.Errorf("Expected the placeholders renumbered, got %s with %v", joined.SQL, joined.Args)
+	}
+}
+
+// Ensures that column names that aren't identifiers are refused.
+func TestNewSQLBuilderInvalidColumn(t *testing.T) {
+	if _, err := NewSQLBuilder(PostgresDialect, "lat; DROP TABLE points", "lng"); err == nil {
+		t.Error("Expected an error for a column name that isn't an identifier")
+	}
+}
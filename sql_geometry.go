@@ -0,0 +1,217 @@
+package geo
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// The dialect set with SetSQLGeometryDialect.
+var sqlGeometryDialect atomic.Int32
+
+// Sets the database geometries are written to as SQL parameters, for the whole package, since a driver.Valuer
+// can't tell which database it is writing to: as hexadecimal EWKB for PostgreSQL with PostGIS, the default,
+// in MySQL's internal geometry format for MySQL, and as WKB for SQLite, which has no geometry types.
+// Geometries are read from any of them regardless.
+// Applications should call it once at startup, before writing geometries.
+func SetSQLGeometryDialect(d SQLDialect) {
+	sqlGeometryDialect.Store(int32(d))
+}
+
+// Returns the database geometries are written to as SQL parameters, as set with SetSQLGeometryDialect.
+func SQLGeometryDialect() SQLDialect {
+	return SQLDialect(sqlGeometryDialect.Load())
+}
+
+// Returns the passed in geometry as a SQL parameter for the database set with SetSQLGeometryDialect.
+func geometryValue(g Geometry) (driver.Value, error) {
+	switch SQLGeometryDialect() {
+	case MySQLDialect:
+		// MySQL stores geometries as their SRID followed by their WKB.
+		return appendWKB(binary.LittleEndian.AppendUint32(nil, SQL_SRID), g, 0)
+	case SQLiteDialect:
+		return MarshalWKB(g)
+	default:
+		data, err := MarshalEWKB(g, SQL_SRID)
+		if err != nil {
+			return nil, err
+		}
+		return hex.EncodeToString(data), nil
+	}
+}
+
+// Decodes a geometry read from a database: hexadecimal or binary EWKB from PostGIS,
+// MySQL's internal geometry format, or WKB.  Geometries must be in SQL_SRID, if they have an SRID.
+func scanGeometry(src interface{}) (Geometry, error) {
+	var data []byte
+	switch src := src.(type) {
+	case []byte:
+		data = src
+	case string:
+		data = []byte(src)
+	case nil:
+		return nil, errors.New("geo: can't scan NULL into a geometry; scan nullable columns into a pointer to a pointer")
+	default:
+		return nil, fmt.Errorf("geo: can't scan a %T into a geometry", src)
+	}
+
+	// PostGIS sends geometries as hexadecimal EWKB text.
+	if decoded, err := hex.DecodeString(string(data)); err == nil && len(decoded) > 0 {
+		data = decoded
+	}
+
+	g, srid, err := UnmarshalWKB(data)
+	if err != nil && len(data) > 4 {
+		// MySQL's internal format prefixes the WKB with its SRID.
+		var mysqlErr error
+		if g, _, mysqlErr = UnmarshalWKB(data[4:]); mysqlErr == nil {
+			srid, err = int(binary.LittleEndian.Uint32(data)), nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if srid != 0 && srid != SQL_SRID {
+		return nil, fmt.Errorf("geo: geometry has SRID %d, not %d", srid, SQL_SRID)
+	}
+
+	return g, nil
+}
+
+// Decodes the current Point from a geometry column, in any of the formats databases send geometries in.
+// Scan nullable columns into a **Point, which is set to nil for NULL.
+// Implements the sql.Scanner interface, for use with database/sql, sqlx and GORM.
+func (p *Point) Scan(src interface{}) error {
+	g, err := scanGeometry(src)
+	if err != nil {
+		return err
+	}
+
+	point, ok := g.(*Point)
+	if !ok {
+		return fmt.Errorf("geo: can't scan a %s into a Point", g.GeometryType())
+	}
+
+	*p = *point
+	return nil
+}
+
+// Returns the current Point as a geometry parameter for the database set with SetSQLGeometryDialect,
+// or an error if it is invalid and strict validation is on.
+// Implements the driver.Valuer interface, for use with database/sql, sqlx and GORM.
+func (p *Point) Value() (driver.Value, error) {
+	if err := checkStrict(p); err != nil {
+		return nil, err
+	}
+
+	return geometryValue(p)
+}
+
+// Returns "geometry", the type GORM's migrator creates columns of Point fields with.
+func (p *Point) GormDataType() string {
+	return "geometry"
+}
+
+// Decodes the current Line from a geometry column, in any of the formats databases send geometries in.
+// Implements the sql.Scanner interface.
+func (l *Line) Scan(src interface{}) error {
+	g, err := scanGeometry(src)
+	if err != nil {
+		return err
+	}
+
+	line, ok := g.(Line)
+	if !ok {
+		return fmt.Errorf("geo: can't scan a %s into a Line", g.GeometryType())
+	}
+
+	*l = line
+	return nil
+}
+
+// Returns the current Line as a geometry parameter for the database set with SetSQLGeometryDialect.
+// Implements the driver.Valuer interface.
+func (l Line) Value() (driver.Value, error) {
+	return geometryValue(l)
+}
+
+// Returns "geometry", the type GORM's migrator creates columns of Line fields with.
+func (l Line) GormDataType() string {
+	return "geometry"
+}
+
+// Decodes the current Polygon from a geometry column, in any of the formats databases send geometries in.
+// Implements the sql.Scanner interface.
+func (p *Polygon) Scan(src interface{}) error {
+	g, err := scanGeometry(src)
+	if err != nil {
+		return err
+	}
+
+	polygon, ok := g.(*Polygon)
+	if !ok {
+		return fmt.Errorf("geo: can't scan a %s into a Polygon", g.GeometryType())
+	}
+
+	p.points = polygon.points
+	return nil
+}
+
+// Returns the current Polygon as a geometry parameter for the database set with SetSQLGeometryDialect.
+// Implements the driver.Valuer interface.
+func (p *Polygon) Value() (driver.Value, error) {
+	return geometryValue(p)
+}
+
+// Returns "geometry", the type GORM's migrator creates columns of Polygon fields with.
+func (p *Polygon) GormDataType() string {
+	return "geometry"
+}
+
+// A LatLngColumns holds a row's latitude and longitude along with a geometry column derived from them,
+// named as in a SQLConf's defaults.  Embed it in GORM models and sqlx structs, and call SyncGeometry
+// before saving, so that the geometry column, and any spatial index on it, follows the coordinates.
+// With GORM, that is a BeforeSave hook:
+//
+//	type Store struct {
+//		ID uint
+//		geo.LatLngColumns
+//	}
+//
+//	func (s *Store) BeforeSave(tx *gorm.DB) error {
+//		return s.SyncGeometry()
+//	}
+//
+// Tables created by a SQLMapper's Migrate generate their geometry column themselves, and need no Geom field.
+type LatLngColumns struct {
+	Lat  float64 `db:"lat" gorm:"column:lat"`
+	Lng  float64 `db:"lng" gorm:"column:lng"`
+	Geom *Point  `db:"geom" gorm:"column:geom"`
+}
+
+// Sets the geometry column from the latitude and longitude columns.  Returns an error,
+// leaving the geometry as it was, if they are invalid and strict validation is on.
+func (c *LatLngColumns) SyncGeometry() error {
+	p := NewPoint(c.Lat, c.Lng)
+	if err := checkStrict(p); err != nil {
+		return err
+	}
+
+	c.Geom = p
+	return nil
+}
+
+// Returns the point of the latitude and longitude columns.
+func (c *LatLngColumns) Point() *Point {
+	return NewPoint(c.Lat, c.Lng)
+}
+
+// Sets the latitude, longitude and geometry columns to the passed in point.
+func (c *LatLngColumns) SetPoint(p *Point) {
+	c.Lat, c.Lng = p.lat, p.lng
+	c.Geom = NewPoint(p.lat, p.lng)
+}
@@ -0,0 +1,79 @@
+package geo
+
+import (
+	"encoding/hex"
+	"reflect"
+	"testing"
+)
+
+// Ensures that geometries are written as each database reads them, and read back from any of them.
+func TestSQLGeometryRoundTrip(t *testing.T) {
+	defer SetSQLGeometryDialect(PostgresDialect)
+
+	p := NewPoint(37.6, -122.4)
+	for _, dialect := range []SQLDialect{PostgresDialect, MySQLDialect, SQLiteDialect} {
+		SetSQLGeometryDialect(dialect)
+		value, err := p.Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		scanned := &Point{}
+		if err := scanned.Scan(value); err != nil || *scanned != *p {
+			t.Errorf("Expected %v to scan back in dialect %d, got %v (%v)", p, dialect, scanned, err)
+		}
+	}
+
+	SetSQLGeometryDialect(PostgresDialect)
+	polygon := NewPolygon([]*Point{NewPoint(0, 0), NewPoint(0, 1), NewPoint(1, 1)})
+	value, _ := polygon.Value()
+	scanned := &Polygon{}
+	if err := scanned.Scan([]byte(value.(string))); err != nil || !reflect.DeepEqual(scanned, polygon) {
+		t.Errorf("Expected %v to scan back, got %v (%v)", polygon, scanned, err)
+	}
+}
+
+// Ensures that PostGIS's hexadecimal EWKB scans, and that other geometries and SRIDs don't.
+func TestPointScan(t *testing.T) {
+	p := &Point{}
+	// SELECT ST_SetSRID(ST_MakePoint(1, 2), 4326)
+	if err := p.Scan("0101000020E6100000000000000000F03F0000000000000040"); err != nil || *p != *NewPoint(2, 1) {
+		t.Errorf("Expected POINT (1 2), got %v (%v)", p, err)
+	}
+
+	line, _ := Line{NewPoint(1, 2), NewPoint(3, 4)}.Value()
+	if err := p.Scan(line); err == nil {
+		t.Error("Expected an error scanning a line into a point")
+	}
+
+	mercator, _ := MarshalEWKB(NewPoint(1, 2), 3857)
+	if err := p.Scan(hex.EncodeToString(mercator)); err == nil {
+		t.Error("Expected an error scanning a point in another SRID")
+	}
+
+	if err := p.Scan(nil); err == nil {
+		t.Error("Expected an error scanning NULL into a point")
+	}
+
+}
+
+// Ensures that LatLngColumns keeps its geometry in step with its coordinates.
+func TestLatLngColumns(t *testing.T) {
+	defer SetStrictValidation(false)
+
+	c := &LatLngColumns{Lat: 37.6, Lng: -122.4}
+	if err := c.SyncGeometry(); err != nil || *c.Geom != *NewPoint(37.6, -122.4) {
+		t.Errorf("Expected the geometry to follow the coordinates, got %v (%v)", c.Geom, err)
+	}
+
+	c.SetPoint(NewPoint(1, 2))
+	if c.Lat != 1 || c.Lng != 2 || *c.Geom != *c.Point() {
+		t.Errorf("Expected every column to be set, got %+v", c)
+	}
+
+	SetStrictValidation(true)
+	c.Lat, c.Lng = 0, 0
+	if err := c.SyncGeometry(); err == nil || *c.Geom != *NewPoint(1, 2) {
+		t.Errorf("Expected Null Island to be refused in strict mode, got %v", err)
+	}
+}
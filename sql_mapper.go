@@ -1,10 +1,30 @@
 package geo
 
 import (
+	"context"
 	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 )
 
+// The number of results in each page of a paginated query, unless a limit is passed in,
+// and the most rows written by each statement of a bulk insert or upsert.
+const (
+	DEFAULT_SQL_PAGE_SIZE = 1000
+	MAX_SQL_BATCH_SIZE    = 500
+)
+
+// This is the error that consumers receive when updating or deleting a point whose id isn't in the table.
+var ErrPointNotFound = errors.New("point not found")
+
+// This is the error that consumers receive when passing a cursor
+// that wasn't returned by the same kind of query.
+var invalidCursorError = errors.New("invalid pagination cursor")
+
 // A Mapper that uses Standard SQL Syntax to perform mapping functions and queries
 type SQLMapper struct {
 	conf    *SQLConf
@@ -21,6 +41,12 @@ func NewSQLMapper(filename string, conn *sql.DB) (*SQLMapper, error) {
 	return &SQLMapper{conf: conf, sqlConn: conn}, nil
 }
 
+// Returns nil if the SQLMapper's database answers a ping, or the error it fails with.
+// Implements the HealthChecker interface.
+func (s *SQLMapper) HealthCheck(ctx context.Context) error {
+	return s.sqlConn.PingContext(ctx)
+}
+
 // Returns a pointer to the SQLMapper's SQL Database Connection.
 func (s *SQLMapper) SqlDbConn() *sql.DB {
 	return s.sqlConn
@@ -31,10 +57,12 @@ func (s *SQLMapper) SqlDbConn() *sql.DB {
 // Original implemenation from : http://www.movable-type.co.uk/scripts/latlong-db.html
 // Returns a pointer to a sql.Rows as a result, or an error if one occurs during the query.
 func (s *SQLMapper) PointsWithinRadius(p *Point, radius float64) (*sql.Rows, error) {
+	if err := checkStrict(p); err != nil {
+		return nil, err
+	}
+
 	select_str := fmt.Sprintf("SELECT * FROM %v a", s.conf.table)
-	lat1 := fmt.Sprintf("sin(radians(%f)) * sin(radians(a.lat))", p.lat)
-	lng1 := fmt.Sprintf("cos(radians(%f)) * cos(radians(a.lat)) * cos(radians(a.lng) - radians(%f))", p.lat, p.lng)
-	where_str := fmt.Sprintf("WHERE acos(%s + %s) * %f <= %f", lat1, lng1, float64(EARTH_RADIUS), radius)
+	where_str := fmt.Sprintf("WHERE %s <= %f", s.distanceExpr(p), radius)
 	query := fmt.Sprintf("%s %s", select_str, where_str)
 
 	res, err := s.sqlConn.Query(query)
@@ -44,3 +72,429 @@ func (s *SQLMapper) PointsWithinRadius(p *Point, radius float64) (*sql.Rows, err
 
 	return res, err
 }
+
+// Returns the SQL expression for the great circle distance, in kilometers,
+// from the passed in point to the point in each row of the table aliased as a.
+func (s *SQLMapper) distanceExpr(p *Point) string {
+	lat1 := fmt.Sprintf("sin(radians(%f)) * sin(radians(a.%s))", p.lat, s.conf.latCol)
+	lng1 := fmt.Sprintf("cos(radians(%f)) * cos(radians(a.%s)) * cos(radians(a.%s) - radians(%f))", p.lat, s.conf.latCol, s.conf.lngCol, p.lng)
+	return fmt.Sprintf("acos(%s + %s) * %f", lat1, lng1, float64(EARTH_RADIUS))
+}
+
+// Uses SQL to retrieve up to k points nearest to the passed in point, nearest first.
+// If maxRadius is positive, points further than maxRadius kilometers away are left out.
+// Each row holds the columns of the table, followed by a "distance" column
+// with the great circle distance, in kilometers, of the row's point.
+// Returns a pointer to a sql.Rows as a result, or an error if one occurs during the query.
+func (s *SQLMapper) KNearest(p *Point, k int, maxRadius float64) (*sql.Rows, error) {
+	if err := checkStrict(p); err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT * FROM (SELECT a.*, %s AS distance FROM %v a) d", s.distanceExpr(p), s.conf.table)
+	if maxRadius > 0 {
+		query += fmt.Sprintf(" WHERE d.distance <= %f", maxRadius)
+	}
+	query += fmt.Sprintf(" ORDER BY d.distance LIMIT %d", max(k, 0))
+
+	return s.sqlConn.Query(query)
+}
+
+// A point found by one of the SQLMapper's paginated or streaming queries.
+type SQLResult struct {
+	// The value of the row's id column, as text.
+	ID    string
+	Point *Point
+
+	// The great circle distance, in kilometers, from the origin of a radius query; 0 for bounds queries.
+	Distance float64
+}
+
+// A page of results from one of the SQLMapper's paginated queries.
+type SQLPage struct {
+	Results []*SQLResult
+
+	// Passed to the same query to fetch the following page; empty after the last page.
+	Cursor string
+}
+
+// The position of the last result of a page, encoded opaquely in its Cursor.
+type sqlCursor struct {
+	Distance *float64 `json:"d,omitempty"`
+	ID       string   `json:"id"`
+}
+
+// Returns the passed in number written in full as a SQL literal.
+func sqlFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// Returns the SQL expression selecting the id, latitude, longitude and distance from
+// the passed in point, which may be nil for queries without one, of each row as d.
+func (s *SQLMapper) resultsQuery(p *Point) string {
+	distance := "0"
+	if p != nil {
+		distance = s.distanceExpr(p)
+	}
+
+	return fmt.Sprintf("SELECT d.id, d.lat, d.lng, d.distance FROM (SELECT a.%s AS id, a.%s AS lat, a.%s AS lng, %s AS distance FROM %v a) d",
+		s.conf.idCol, s.conf.latCol, s.conf.lngCol, distance, s.conf.table)
+}
+
+// Returns the SQL condition selecting rows whose point lies within the passed in Bounds.
+// Bounds whose west edge is east of their east edge are taken to cross the antimeridian.
+func boundsCondition(b *Bounds) string {
+	sw, ne := b.SouthWest(), b.NorthEast()
+	lng := fmt.Sprintf("d.lng BETWEEN %f AND %f", sw.lng, ne.lng)
+	if sw.lng > ne.lng {
+		lng = fmt.Sprintf("(d.lng >= %f OR d.lng <= %f)", sw.lng, ne.lng)
+	}
+
+	return fmt.Sprintf("d.lat BETWEEN %f AND %f AND %s", sw.lat, ne.lat, lng)
+}
+
+// Runs the passed in query with the passed in arguments, reading each row into a SQLResult.
+func (s *SQLMapper) queryResults(query string, args ...interface{}) ([]*SQLResult, error) {
+	rows, err := s.sqlConn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*SQLResult
+	for rows.Next() {
+		r, err := scanSQLResult(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
+
+	return results, rows.Err()
+}
+
+// Returns the SQLResult held by the current row of the passed in rows.
+func scanSQLResult(rows *sql.Rows) (*SQLResult, error) {
+	var id sql.RawBytes
+	var lat, lng, distance float64
+	if err := rows.Scan(&id, &lat, &lng, &distance); err != nil {
+		return nil, err
+	}
+
+	return &SQLResult{ID: string(id), Point: NewPoint(lat, lng), Distance: distance}, nil
+}
+
+// Runs the passed in query, with the passed in arguments, for a page of up to limit results, nearest first
+// if byDistance and otherwise by id, returning it with the cursor to fetch the following page with.
+func (s *SQLMapper) queryPage(query string, args []interface{}, limit int, byDistance bool) (*SQLPage, error) {
+	if limit <= 0 {
+		limit = DEFAULT_SQL_PAGE_SIZE
+	}
+
+	order := "d.id"
+	if byDistance {
+		order = "d.distance, d.id"
+	}
+
+	// Fetch one more result than the page holds, to learn whether another page follows.
+	results, err := s.queryResults(fmt.Sprintf("%s ORDER BY %s LIMIT %d", query, order, limit+1), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &SQLPage{Results: results}
+	if len(results) > limit {
+		page.Results = results[:limit]
+		last := page.Results[limit-1]
+		cursor := sqlCursor{ID: last.ID}
+		if byDistance {
+			cursor.Distance = &last.Distance
+		}
+
+		data, _ := json.Marshal(cursor)
+		page.Cursor = base64.RawURLEncoding.EncodeToString(data)
+	}
+
+	return page, nil
+}
+
+// Returns the SQL condition selecting rows after the passed in cursor, with the cursor's position
+// as its arguments, or an empty fragment for an empty cursor.  Cursors come back from clients,
+// so nothing in them is ever written into the SQL.
+func (s *SQLMapper) cursorCondition(cursor string, byDistance bool) (SQLFragment, error) {
+	if cursor == "" {
+		return SQLFragment{}, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return SQLFragment{}, invalidCursorError
+	}
+
+	var c sqlCursor
+	if err := json.Unmarshal(data, &c); err != nil || (c.Distance != nil) != byDistance {
+		return SQLFragment{}, invalidCursorError
+	}
+
+	b := s.SQLBuilder()
+	if !byDistance {
+		return b.fragment("d.id > %s", c.ID), nil
+	}
+
+	// Distances are passed in full, so that rows at exactly the cursor's distance compare equal.
+	return b.fragment("(d.distance > %s OR (d.distance = %s AND d.id > %s))", *c.Distance, *c.Distance, c.ID), nil
+}
+
+// Returns the passed in conditions joined into a WHERE clause, leaving out empty ones.
+func whereClause(conditions ...string) string {
+	var parts []string
+	for _, c := range conditions {
+		if c != "" {
+			parts = append(parts, c)
+		}
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+
+	return " WHERE " + strings.Join(parts, " AND ")
+}
+
+// Uses SQL to retrieve a page of up to limit points within the passed in radius, in kilometers,
+// of the passed in point, nearest first, with their distances.  Pass an empty cursor for the
+// first page, and the Cursor of each page for the one following it.  Pages are found by
+// their position rather than an offset, so each is as quick to fetch as the first.
+// A limit of 0 or less fetches DEFAULT_SQL_PAGE_SIZE points.
+func (s *SQLMapper) PointsWithinRadiusPage(p *Point, radius float64, cursor string, limit int) (*SQLPage, error) {
+	if err := checkStrict(p); err != nil {
+		return nil, err
+	}
+
+	after, err := s.cursorCondition(cursor, true)
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.resultsQuery(p) + whereClause(fmt.Sprintf("d.distance <= %f", radius), after.SQL)
+	return s.queryPage(query, after.Args, limit, true)
+}
+
+// Uses SQL to retrieve a page of up to limit points within the passed in Bounds, ordered by id.
+// Pass an empty cursor for the first page, and the Cursor of each page for the one following it.
+// A limit of 0 or less fetches DEFAULT_SQL_PAGE_SIZE points.
+func (s *SQLMapper) PointsWithinBoundsPage(b *Bounds, cursor string, limit int) (*SQLPage, error) {
+	after, err := s.cursorCondition(cursor, false)
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.resultsQuery(nil) + whereClause(boundsCondition(b), after.SQL)
+	return s.queryPage(query, after.Args, limit, false)
+}
+
+// Uses SQL to retrieve the points within the passed in radius, in kilometers, of the passed in point,
+// nearest first, sending each on the returned channel as it is read, so that result sets of any size
+// can be processed without holding them in memory.  The channel is closed once every point has been sent,
+// or the passed in context is done; then the error channel receives the error that stopped the query, if any.
+func (s *SQLMapper) StreamPointsWithinRadius(ctx context.Context, p *Point, radius float64) (<-chan *SQLResult, <-chan error) {
+	query := s.resultsQuery(p) + whereClause(fmt.Sprintf("d.distance <= %f", radius)) + " ORDER BY d.distance, d.id"
+	return s.streamResults(ctx, query)
+}
+
+// Uses SQL to retrieve the points within the passed in Bounds, sending each on the returned channel
+// as it is read.  The channels behave as those of StreamPointsWithinRadius.
+func (s *SQLMapper) StreamPointsWithinBounds(ctx context.Context, b *Bounds) (<-chan *SQLResult, <-chan error) {
+	return s.streamResults(ctx, s.resultsQuery(nil)+whereClause(boundsCondition(b)))
+}
+
+// Runs the passed in query, sending each row as a SQLResult on the first returned channel,
+// then the error that stopped it, if any, on the second.
+func (s *SQLMapper) streamResults(ctx context.Context, query string) (<-chan *SQLResult, <-chan error) {
+	results := make(chan *SQLResult)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		err := func() error {
+			defer close(results)
+			rows, err := s.sqlConn.QueryContext(ctx, query)
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+
+			for rows.Next() {
+				r, err := scanSQLResult(rows)
+				if err != nil {
+					return err
+				}
+
+				select {
+				case results <- r:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+
+			return rows.Err()
+		}()
+
+		if err != nil {
+			errs <- err
+		}
+	}()
+
+	return results, errs
+}
+
+// Returns whether or not the SQLMapper's database is MySQL, whose syntax for
+// returning inserted ids and for upserts differs from PostgreSQL's and SQLite's.
+func (s *SQLMapper) mysql() bool {
+	return strings.Contains(s.conf.driver, "mysql")
+}
+
+// Returns the SQLMapper's placeholder for the nth argument of a statement, counting from 1:
+// $n in PostgreSQL, and a question mark in MySQL and SQLite.
+func (s *SQLMapper) placeholder(n int) string {
+	if DialectForDriver(s.conf.driver) != PostgresDialect {
+		return "?"
+	}
+
+	return "$" + strconv.Itoa(n)
+}
+
+// Returns a SQL tuple of placeholders for n arguments, following on from the passed in number of earlier ones.
+func (s *SQLMapper) placeholderTuple(n, offset int) string {
+	placeholders := make([]string, n)
+	for i := range placeholders {
+		placeholders[i] = s.placeholder(offset + i + 1)
+	}
+
+	return "(" + strings.Join(placeholders, ", ") + ")"
+}
+
+// Uses SQL to insert the passed in point as a new row, returning the id the database gave it.
+func (s *SQLMapper) InsertPoint(p *Point) (int64, error) {
+	if err := checkStrict(p); err != nil {
+		return 0, err
+	}
+
+	query := fmt.Sprintf("INSERT INTO %v (%s, %s) VALUES %s", s.conf.table, s.conf.latCol, s.conf.lngCol, s.placeholderTuple(2, 0))
+	if s.mysql() {
+		res, err := s.sqlConn.Exec(query, p.lat, p.lng)
+		if err != nil {
+			return 0, err
+		}
+
+		return res.LastInsertId()
+	}
+
+	var id int64
+	err := s.sqlConn.QueryRow(fmt.Sprintf("%s RETURNING %s", query, s.conf.idCol), p.lat, p.lng).Scan(&id)
+	return id, err
+}
+
+// Uses SQL to move the point with the passed in id to the passed in point.
+// Returns ErrPointNotFound if there is no point with that id.
+func (s *SQLMapper) UpdatePoint(id string, p *Point) error {
+	if err := checkStrict(p); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("UPDATE %v SET %s = %s, %s = %s WHERE %s = %s",
+		s.conf.table, s.conf.latCol, s.placeholder(1), s.conf.lngCol, s.placeholder(2), s.conf.idCol, s.placeholder(3))
+	return s.execOne(query, p.lat, p.lng, id)
+}
+
+// Uses SQL to delete the point with the passed in id.
+// Returns ErrPointNotFound if there is no point with that id.
+func (s *SQLMapper) DeletePoint(id string) error {
+	return s.execOne(fmt.Sprintf("DELETE FROM %v WHERE %s = %s", s.conf.table, s.conf.idCol, s.placeholder(1)), id)
+}
+
+// Runs the passed in statement with the passed in arguments, returning ErrPointNotFound if it changed no rows.
+func (s *SQLMapper) execOne(query string, args ...interface{}) error {
+	res, err := s.sqlConn.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return ErrPointNotFound
+	}
+
+	return nil
+}
+
+// Uses SQL to insert the passed in points as new rows, MAX_SQL_BATCH_SIZE to a statement,
+// in a single transaction, so that either every point is inserted or none are.
+func (s *SQLMapper) InsertPoints(points []*Point) error {
+	values := make([][]interface{}, len(points))
+	for i, p := range points {
+		if err := checkStrict(p); err != nil {
+			return err
+		}
+		values[i] = []interface{}{p.lat, p.lng}
+	}
+
+	prefix := fmt.Sprintf("INSERT INTO %v (%s, %s) VALUES ", s.conf.table, s.conf.latCol, s.conf.lngCol)
+	return s.execBatches(prefix, values, "")
+}
+
+// Uses SQL to write the passed in points under their IDs, inserting those whose IDs aren't
+// in the table and moving those whose IDs are, MAX_SQL_BATCH_SIZE to a statement,
+// in a single transaction.  The Distance of each SQLResult is ignored.
+func (s *SQLMapper) UpsertPoints(points []*SQLResult) error {
+	values := make([][]interface{}, len(points))
+	for i, r := range points {
+		if err := checkStrict(r.Point); err != nil {
+			return err
+		}
+		values[i] = []interface{}{r.ID, r.Point.lat, r.Point.lng}
+	}
+
+	c := s.conf
+	prefix := fmt.Sprintf("INSERT INTO %v (%s, %s, %s) VALUES ", c.table, c.idCol, c.latCol, c.lngCol)
+	suffix := fmt.Sprintf(" ON CONFLICT (%s) DO UPDATE SET %s = EXCLUDED.%s, %s = EXCLUDED.%s", c.idCol, c.latCol, c.latCol, c.lngCol, c.lngCol)
+	if s.mysql() {
+		suffix = fmt.Sprintf(" ON DUPLICATE KEY UPDATE %s = VALUES(%s), %s = VALUES(%s)", c.latCol, c.latCol, c.lngCol, c.lngCol)
+	}
+
+	return s.execBatches(prefix, values, suffix)
+}
+
+// Runs a statement for each batch of up to MAX_SQL_BATCH_SIZE of the passed in rows of values,
+// each bound to a tuple of placeholders between the passed in prefix and suffix, in a single transaction.
+func (s *SQLMapper) execBatches(prefix string, values [][]interface{}, suffix string) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	tx, err := s.sqlConn.Begin()
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(values); start += MAX_SQL_BATCH_SIZE {
+		batch := values[start:min(start+MAX_SQL_BATCH_SIZE, len(values))]
+		tuples := make([]string, len(batch))
+		var args []interface{}
+		for i, row := range batch {
+			tuples[i] = s.placeholderTuple(len(row), len(args))
+			args = append(args, row...)
+		}
+
+		if _, err := tx.Exec(prefix+strings.Join(tuples, ", ")+suffix, args...); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
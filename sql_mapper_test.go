@@ -1,9 +1,19 @@
 package geo
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 )
 
@@ -40,3 +50,427 @@ func TestSqlDbConn(t *testing.T) {
 		t.Error("Expected db connections are mismatched.")
 	}
 }
+
+// A database/sql driver that records the statements it is given and answers every
+// query with the same rows, for testing the SQL mappers without a database.
+type recordingDriver struct {
+	mu      sync.Mutex
+	queries []string
+	args    [][]driver.Value
+	columns []string
+	rows    [][]driver.Value
+
+	// The number of rows every statement reports changing, and the id it reports inserting.
+	affected int64
+	lastID   int64
+}
+
+// The number of recordingDrivers registered, each under a name of its own.
+var recordingDrivers atomic.Int64
+
+// Returns a *sql.DB backed by a new recordingDriver answering queries with the passed in rows.
+func openRecordingDB(t *testing.T, columns []string, rows ...[]driver.Value) (*sql.DB, *recordingDriver) {
+	d := &recordingDriver{columns: columns, rows: rows, affected: 1}
+	name := fmt.Sprintf("recording-%d", recordingDrivers.Add(1))
+	sql.Register(name, d)
+
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db, d
+}
+
+// Returns the statements the driver has been given, in order.
+func (d *recordingDriver) Queries() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.queries...)
+}
+
+// Returns the arguments of each statement the driver has been given, in order.
+func (d *recordingDriver) Args() [][]driver.Value {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([][]driver.Value(nil), d.args...)
+}
+
+func (d *recordingDriver) Open(name string) (driver.Conn, error) {
+	return &recordingConn{d}, nil
+}
+
+type recordingConn struct {
+	d *recordingDriver
+}
+
+func (c *recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return &recordingStmt{c.d, query}, nil
+}
+
+func (c *recordingConn) Close() error {
+	return nil
+}
+
+func (c *recordingConn) Begin() (driver.Tx, error) {
+	return c, nil
+}
+
+func (c *recordingConn) Commit() error {
+	return nil
+}
+
+func (c *recordingConn) Rollback() error {
+	return nil
+}
+
+type recordingStmt struct {
+	d     *recordingDriver
+	query string
+}
+
+func (s *recordingStmt) Close() error {
+	return nil
+}
+
+func (s *recordingStmt) NumInput() int {
+	return -1
+}
+
+func (s *recordingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	s.d.queries = append(s.d.queries, s.query)
+	s.d.args = append(s.d.args, args)
+	return recordingResult{s.d.affected, s.d.lastID}, nil
+}
+
+type recordingResult struct {
+	affected, lastID int64
+}
+
+func (r recordingResult) LastInsertId() (int64, error) {
+	return r.lastID, nil
+}
+
+func (r recordingResult) RowsAffected() (int64, error) {
+	return r.affected, nil
+}
+
+func (s *recordingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	s.d.queries = append(s.d.queries, s.query)
+	s.d.args = append(s.d.args, args)
+	return &recordingRows{columns: s.d.columns, rows: s.d.rows}, nil
+}
+
+type recordingRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *recordingRows) Columns() []string {
+	return r.columns
+}
+
+func (r *recordingRows) Close() error {
+	return nil
+}
+
+func (r *recordingRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+// Ensures that the k nearest points are queried nearest first, with their distances.
+func TestSQLMapperKNearest(t *testing.T) {
+	db, d := openRecordingDB(t, []string{"id", "lat", "lng", "distance"}, []driver.Value{int64(1), 37.42, -122.08, 0.5})
+	s, err := NewSQLMapper("nonexistent.yml", db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := s.KNearest(NewPoint(37.42, -122.08), 5, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var id int
+	var lat, lng, distance float64
+	if !rows.Next() || rows.Scan(&id, &lat, &lng, &distance) != nil || distance != 0.5 {
+		t.Errorf("Expected a row with its distance, got %d, %f", id, distance)
+	}
+
+	query := d.Queries()[0]
+	for _, part := range []string{"AS distance FROM points a", "WHERE d.distance <= 10.000000", "ORDER BY d.distance LIMIT 5"} {
+		if !strings.Contains(query, part) {
+			t.Errorf("Expected the query to contain %q, got %s", part, query)
+		}
+	}
+
+	if _, err := s.KNearest(NewPoint(37.42, -122.08), 5, 0); err != nil || strings.Contains(d.Queries()[1], "WHERE") {
+		t.Errorf("Expected no radius to be applied without one, got %s (%v)", d.Queries()[1], err)
+	}
+}
+
+// Ensures that pages of a radius query follow on from the cursor of the page before.
+func TestSQLMapperPointsWithinRadiusPage(t *testing.T) {
+	db, d := openRecordingDB(t, []string{"id", "lat", "lng", "distance"},
+		[]driver.Value{int64(1), 37.42, -122.08, 0.0},
+		[]driver.Value{int64(7), 37.43, -122.08, 1.1119492664455877},
+		[]driver.Value{int64(3), 37.44, -122.08, 2.2238985328911755},
+	)
+	s, _ := NewSQLMapper("nonexistent.yml", db)
+
+	page, err := s.PointsWithinRadiusPage(NewPoint(37.42, -122.08), 5, "", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(page.Results) != 2 || page.Results[1].ID != "7" || page.Results[1].Distance != 1.1119492664455877 || page.Cursor == "" {
+		t.Fatalf("Expected a full first page with a cursor, got %+v", page)
+	}
+
+	if !strings.Contains(d.Queries()[0], "ORDER BY d.distance, d.id LIMIT 3") {
+		t.Errorf("Expected one more row than the page holds to be queried, got %s", d.Queries()[0])
+	}
+
+	if _, err := s.PointsWithinRadiusPage(NewPoint(37.42, -122.08), 5, page.Cursor, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	after := "(d.distance > $1 OR (d.distance = $2 AND d.id > $3))"
+	if query := d.Queries()[1]; !strings.Contains(query, after) {
+		t.Errorf("Expected the second page to start after the cursor, got %s", query)
+	}
+
+	expected := []driver.Value{1.1119492664455877, 1.1119492664455877, "7"}
+	if args := d.Args()[1]; !reflect.DeepEqual(args, expected) {
+		t.Errorf("Expected the cursor's position as arguments, got %v", args)
+	}
+
+	if _, err := s.PointsWithinRadiusPage(NewPoint(37.42, -122.08), 5, "not a cursor", 2); err != invalidCursorError {
+		t.Errorf("Expected invalidCursorError, got %v", err)
+	}
+}
+
+// Ensures that the last page of a bounds query has no cursor, and that cursors aren't shared between kinds of query.
+func TestSQLMapperPointsWithinBoundsPage(t *testing.T) {
+	db, d := openRecordingDB(t, []string{"id", "lat", "lng", "distance"}, []driver.Value{"a'b", -16.5, 179.9, 0.0})
+	s, _ := NewSQLMapper("nonexistent.yml", db)
+
+	fiji := NewBounds(NewPoint(-21, 177), NewPoint(-12, -178))
+	page, err := s.PointsWithinBoundsPage(fiji, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(page.Results) != 1 || page.Results[0].ID != "a'b" || page.Cursor != "" {
+		t.Fatalf("Expected a last page without a cursor, got %+v", page)
+	}
+
+	query := d.Queries()[0]
+	for _, part := range []string{"(d.lng >= 177.000000 OR d.lng <= -178.000000)", "ORDER BY d.id LIMIT 1001"} {
+		if !strings.Contains(query, part) {
+			t.Errorf("Expected the query to contain %q, got %s", part, query)
+		}
+	}
+
+	cursor := base64.RawURLEncoding.EncodeToString([]byte(`{"d":1,"id":"7"}`))
+	if _, err := s.PointsWithinBoundsPage(fiji, cursor, 0); err != invalidCursorError {
+		t.Errorf("Expected a radius query's cursor to be refused, got %v", err)
+	}
+
+	hostile := `\' OR 1=1 -- `
+	cursor = base64.RawURLEncoding.EncodeToString([]byte(`{"id":"` + strings.ReplaceAll(hostile, `\`, `\\`) + `"}`))
+	if _, err := s.PointsWithinBoundsPage(fiji, cursor, 0); err != nil || !strings.Contains(d.Queries()[1], "d.id > $1") || strings.Contains(d.Queries()[1], "1=1") {
+		t.Errorf("Expected the cursor's id to be left out of the query, got %s (%v)", d.Queries()[1], err)
+	}
+
+	if args := d.Args()[1]; len(args) != 1 || args[0] != hostile {
+		t.Errorf("Expected the cursor's id as the argument, got %v", args)
+	}
+}
+
+// Ensures that streamed results arrive in order, and that cancelling the context stops the stream.
+func TestSQLMapperStreamPointsWithinRadius(t *testing.T) {
+	var rows [][]driver.Value
+	for i := 0; i < 100; i++ {
+		rows = append(rows, []driver.Value{int64(i), 37.42, -122.08, float64(i)})
+	}
+	db, _ := openRecordingDB(t, []string{"id", "lat", "lng", "distance"}, rows...)
+	s, _ := NewSQLMapper("nonexistent.yml", db)
+
+	results, errs := s.StreamPointsWithinRadius(context.Background(), NewPoint(37.42, -122.08), 500)
+	n := 0
+	for r := range results {
+		if r.Distance != float64(n) {
+			t.Fatalf("Expected result %d at %dkm, got %f", n, n, r.Distance)
+		}
+		n++
+	}
+
+	if err := <-errs; err != nil || n != 100 {
+		t.Errorf("Expected 100 results, got %d (%v)", n, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results, errs = s.StreamPointsWithinBounds(ctx, NewBounds(NewPoint(37, -123), NewPoint(38, -122)))
+	<-results
+	cancel()
+	for range results {
+	}
+
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the stream to stop when cancelled, got %v", err)
+	}
+}
+
+// Ensures that points are inserted, updated and deleted, and that missing points are reported.
+func TestSQLMapperWrites(t *testing.T) {
+	db, d := openRecordingDB(t, []string{"id"}, []driver.Value{int64(42)})
+	s, _ := NewSQLMapper("nonexistent.yml", db)
+
+	id, err := s.InsertPoint(NewPoint(37.4224764, -122.0842499))
+	if err != nil || id != 42 {
+		t.Errorf("Expected the inserted point's id, got %d (%v)", id, err)
+	}
+
+	if err := s.UpdatePoint("42", NewPoint(1.5, 2)); err != nil {
+		t.Error(err)
+	}
+
+	if err := s.DeletePoint("42"); err != nil {
+		t.Error(err)
+	}
+
+	expected := []string{
+		"INSERT INTO points (lat, lng) VALUES ($1, $2) RETURNING id",
+		"UPDATE points SET lat = $1, lng = $2 WHERE id = $3",
+		"DELETE FROM points WHERE id = $1",
+	}
+	for i, query := range d.Queries() {
+		if query != expected[i] {
+			t.Errorf("Expected %s, got %s", expected[i], query)
+		}
+	}
+
+	expectedArgs := [][]driver.Value{{37.4224764, -122.0842499}, {1.5, 2.0, "42"}, {"42"}}
+	if args := d.Args(); !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("Expected the arguments %v, got %v", expectedArgs, args)
+	}
+
+	d.affected = 0
+	if err := s.UpdatePoint("43", NewPoint(1, 2)); err != ErrPointNotFound {
+		t.Errorf("Expected ErrPointNotFound, got %v", err)
+	}
+
+	if err := s.DeletePoint("43"); err != ErrPointNotFound {
+		t.Errorf("Expected ErrPointNotFound, got %v", err)
+	}
+}
+
+// Ensures that MySQL's inserted ids and upsert syntax are used with its driver.
+func TestSQLMapperWritesMySQL(t *testing.T) {
+	db, d := openRecordingDB(t, nil)
+	d.lastID = 7
+	s := &SQLMapper{conf: &SQLConf{driver: "mymysql", table: "points", latCol: "lat", lngCol: "lng", idCol: "id"}, sqlConn: db}
+
+	if id, err := s.InsertPoint(NewPoint(1, 2)); err != nil || id != 7 {
+		t.Errorf("Expected the inserted point's id, got %d (%v)", id, err)
+	}
+
+	if err := s.UpsertPoints([]*SQLResult{{ID: "7", Point: NewPoint(3, 4)}}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "INSERT INTO points (id, lat, lng) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE lat = VALUES(lat), lng = VALUES(lng)"
+	if query := d.Queries()[1]; query != expected {
+		t.Errorf("Expected %s, got %s", expected, query)
+	}
+}
+
+// Ensures that ids are passed as arguments, so that no id can change the statement it's used in,
+// even on MySQL, where a backslash escapes a quote.
+func TestSQLMapperHostileID(t *testing.T) {
+	db, d := openRecordingDB(t, nil)
+	s := &SQLMapper{conf: &SQLConf{driver: "mysql", table: "points", latCol: "lat", lngCol: "lng", idCol: "id"}, sqlConn: db}
+
+	hostile := `\' OR 1=1 -- `
+	s.DeletePoint(hostile)
+	s.UpdatePoint(hostile, NewPoint(1, 2))
+	s.UpsertPoints([]*SQLResult{{ID: hostile, Point: NewPoint(1, 2)}})
+
+	if len(d.Queries()) != 3 {
+		t.Fatalf("Expected 3 statements, got %v", d.Queries())
+	}
+
+	for i, query := range d.Queries() {
+		if strings.Contains(query, "OR 1=1") {
+			t.Errorf("Expected the id to be left out of the statement, got %s", query)
+		}
+
+		found := false
+		for _, arg := range d.Args()[i] {
+			found = found || arg == hostile
+		}
+		if !found {
+			t.Errorf("Expected the id to be passed as an argument, got %v", d.Args()[i])
+		}
+	}
+}
+
+// Ensures that bulk writes are split into batches of MAX_SQL_BATCH_SIZE rows.
+func TestSQLMapperBulkWrites(t *testing.T) {
+	db, d := openRecordingDB(t, nil)
+	s, _ := NewSQLMapper("nonexistent.yml", db)
+
+	points := make([]*Point, MAX_SQL_BATCH_SIZE+1)
+	upserts := make([]*SQLResult, MAX_SQL_BATCH_SIZE+1)
+	for i := range points {
+		points[i] = NewPoint(float64(i)/10, 0)
+		upserts[i] = &SQLResult{ID: strconv.Itoa(i), Point: points[i]}
+	}
+
+	if err := s.InsertPoints(points); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.UpsertPoints(upserts); err != nil {
+		t.Fatal(err)
+	}
+
+	queries := d.Queries()
+	if len(queries) != 4 {
+		t.Fatalf("Expected 4 statements, got %d", len(queries))
+	}
+
+	if n := strings.Count(queries[0], "), ("); n != MAX_SQL_BATCH_SIZE-1 || !strings.HasSuffix(queries[1], "VALUES ($1, $2)") {
+		t.Errorf("Expected a full batch and then the rest, got %d rows then %s", n+1, queries[1])
+	}
+
+	suffix := " ON CONFLICT (id) DO UPDATE SET lat = EXCLUDED.lat, lng = EXCLUDED.lng"
+	if !strings.HasSuffix(queries[3], "VALUES ($1, $2, $3)"+suffix) {
+		t.Errorf("Expected an upsert, got %s", queries[3])
+	}
+
+	if args := d.Args()[3]; !reflect.DeepEqual(args, []driver.Value{"500", 50.0, 0.0}) {
+		t.Errorf("Expected the upserted point as arguments, got %v", args)
+	}
+
+	if !strings.HasSuffix(queries[0], "($997, $998), ($999, $1000)") {
+		t.Errorf("Expected placeholders numbered across the batch, got %s", queries[0][len(queries[0])-40:])
+	}
+
+	if err := s.InsertPoints(nil); err != nil || len(d.Queries()) != 4 {
+		t.Errorf("Expected no statements for no points, got %v", err)
+	}
+}
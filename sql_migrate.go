@@ -0,0 +1,123 @@
+package geo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The spatial reference system of the geometry columns Migrate creates: WGS84 latitude and longitude.
+const SQL_SRID = 4326
+
+// Returns whether or not the SQLMapper's database is SQLite, which has no geometry types
+// without the SpatiaLite extension.
+func (s *SQLMapper) sqlite() bool {
+	return strings.Contains(s.conf.driver, "sqlite")
+}
+
+// Creates the SQLMapper's table if it doesn't exist, with its id, latitude and longitude columns,
+// a geometry column kept in step with the latitude and longitude, constrained to SQL_SRID,
+// and a spatial index on the geometry.  Tables that already exist are given the geometry column
+// and index if they are missing, so Migrate is safe to run every time an application starts.
+//
+// On PostgreSQL the PostGIS extension is created if it isn't already, and must be available.
+// MySQL needs version 8.0 or later.  SQLite tables have no geometry column,
+// and are given an index on their latitude and longitude instead.
+func (s *SQLMapper) Migrate() error {
+	var statements []string
+	switch {
+	case s.mysql():
+		statements = s.mysqlMigration()
+	case s.sqlite():
+		statements = s.sqliteMigration()
+	default:
+		statements = s.postgresMigration()
+	}
+
+	if err := s.execMigration(statements); err != nil {
+		return err
+	}
+
+	if !s.mysql() {
+		return nil
+	}
+
+	// MySQL can't add a column only if it is missing, so look for it first.
+	missing, err := s.mysqlGeometryMissing()
+	if err != nil || !missing {
+		return err
+	}
+
+	return s.execMigration(s.mysqlGeometryMigration())
+}
+
+// Runs each of the passed in statements in turn.
+func (s *SQLMapper) execMigration(statements []string) error {
+	for _, statement := range statements {
+		if _, err := s.sqlConn.Exec(statement); err != nil {
+			return fmt.Errorf("migrating %s: %v", s.conf.table, err)
+		}
+	}
+
+	return nil
+}
+
+// Returns the statements creating the SQLMapper's table, geometry column and spatial index in PostgreSQL.
+func (s *SQLMapper) postgresMigration() []string {
+	c := s.conf
+	geom := fmt.Sprintf("geometry(Point, %d) GENERATED ALWAYS AS (ST_SetSRID(ST_MakePoint(%s, %s), %d)) STORED",
+		SQL_SRID, c.lngCol, c.latCol, SQL_SRID)
+
+	return []string{
+		"CREATE EXTENSION IF NOT EXISTS postgis",
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s serial PRIMARY KEY, %s double precision NOT NULL, %s double precision NOT NULL)",
+			c.table, c.idCol, c.latCol, c.lngCol),
+		fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s", c.table, c.geomCol, geom),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s_%s_idx ON %s USING GIST (%s)", c.table, c.geomCol, c.table, c.geomCol),
+	}
+}
+
+// Returns whether or not the SQLMapper's MySQL table lacks its geometry column.
+func (s *SQLMapper) mysqlGeometryMissing() (bool, error) {
+	query := "SELECT COUNT(*) FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ? AND column_name = ?"
+
+	var n int
+	if err := s.sqlConn.QueryRow(query, s.conf.table, s.conf.geomCol).Scan(&n); err != nil {
+		return false, err
+	}
+
+	return n == 0, nil
+}
+
+// Returns the definitions of the SQLMapper's geometry column and spatial index in MySQL.
+func (s *SQLMapper) mysqlGeometry() (string, string) {
+	c := s.conf
+	geom := fmt.Sprintf("%s POINT SRID %d AS (ST_SRID(POINT(%s, %s), %d)) STORED NOT NULL", c.geomCol, SQL_SRID, c.lngCol, c.latCol, SQL_SRID)
+	index := fmt.Sprintf("SPATIAL INDEX %s_%s_idx (%s)", c.table, c.geomCol, c.geomCol)
+	return geom, index
+}
+
+// Returns the statement creating the SQLMapper's table, geometry column and spatial index in MySQL.
+func (s *SQLMapper) mysqlMigration() []string {
+	c := s.conf
+	geom, index := s.mysqlGeometry()
+	return []string{
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s int NOT NULL AUTO_INCREMENT PRIMARY KEY, %s double NOT NULL, %s double NOT NULL, %s, %s)",
+			c.table, c.idCol, c.latCol, c.lngCol, geom, index),
+	}
+}
+
+// Returns the statement adding the geometry column and spatial index to an existing MySQL table.
+func (s *SQLMapper) mysqlGeometryMigration() []string {
+	geom, index := s.mysqlGeometry()
+	return []string{fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s, ADD %s", s.conf.table, geom, index)}
+}
+
+// Returns the statements creating the SQLMapper's table and an index on its latitude and longitude in SQLite.
+func (s *SQLMapper) sqliteMigration() []string {
+	c := s.conf
+	return []string{
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s INTEGER PRIMARY KEY, %s REAL NOT NULL, %s REAL NOT NULL)",
+			c.table, c.idCol, c.latCol, c.lngCol),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s_%s_%s_idx ON %s (%s, %s)", c.table, c.latCol, c.lngCol, c.table, c.latCol, c.lngCol),
+	}
+}
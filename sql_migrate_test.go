@@ -0,0 +1,78 @@
+package geo
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+)
+
+// Ensures that PostgreSQL tables are created with a PostGIS geometry column and spatial index, idempotently.
+func TestMigratePostgres(t *testing.T) {
+	db, d := openRecordingDB(t, nil)
+	s, _ := NewSQLMapper("nonexistent.yml", db)
+	s.conf.driver = "postgres"
+
+	if err := s.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"CREATE EXTENSION IF NOT EXISTS postgis",
+		"CREATE TABLE IF NOT EXISTS points (id serial PRIMARY KEY, lat double precision NOT NULL, lng double precision NOT NULL)",
+		"ALTER TABLE points ADD COLUMN IF NOT EXISTS geom geometry(Point, 4326) GENERATED ALWAYS AS (ST_SetSRID(ST_MakePoint(lng, lat), 4326)) STORED",
+		"CREATE INDEX IF NOT EXISTS points_geom_idx ON points USING GIST (geom)",
+	}
+
+	queries := d.Queries()
+	if len(queries) != len(expected) {
+		t.Fatalf("Expected %d statements, got %v", len(expected), queries)
+	}
+
+	for i, query := range queries {
+		if query != expected[i] {
+			t.Errorf("Expected %s, got %s", expected[i], query)
+		}
+	}
+}
+
+// Ensures that MySQL tables are given a geometry column only if they are missing one.
+func TestMigrateMySQL(t *testing.T) {
+	for _, existing := range []int64{0, 1} {
+		db, d := openRecordingDB(t, []string{"count"}, []driver.Value{existing})
+		s := &SQLMapper{conf: &SQLConf{driver: "mymysql", table: "points", latCol: "lat", lngCol: "lng", idCol: "id", geomCol: "geom"}, sqlConn: db}
+
+		if err := s.Migrate(); err != nil {
+			t.Fatal(err)
+		}
+
+		queries := d.Queries()
+		if !strings.HasPrefix(queries[0], "CREATE TABLE IF NOT EXISTS points") || !strings.Contains(queries[0], "geom POINT SRID 4326") ||
+			!strings.Contains(queries[0], "SPATIAL INDEX points_geom_idx (geom)") {
+			t.Errorf("Expected the table to be created with a geometry column and spatial index, got %s", queries[0])
+		}
+
+		if args := d.Args()[1]; !strings.HasSuffix(queries[1], "table_name = ? AND column_name = ?") || len(args) != 2 || args[0] != "points" || args[1] != "geom" {
+			t.Errorf("Expected the table and column names as arguments, got %s %v", queries[1], args)
+		}
+
+		altered := len(queries) == 3 && strings.HasPrefix(queries[2], "ALTER TABLE points ADD COLUMN geom")
+		if altered != (existing == 0) {
+			t.Errorf("Expected the geometry column to be added only if missing, got %v", queries)
+		}
+	}
+}
+
+// Ensures that SQLite tables are given an index on their latitude and longitude.
+func TestMigrateSQLite(t *testing.T) {
+	db, d := openRecordingDB(t, nil)
+	s, _ := NewSQLMapper("nonexistent.yml", db)
+	s.conf.driver = "sqlite3"
+
+	if err := s.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if queries := d.Queries(); len(queries) != 2 || queries[1] != "CREATE INDEX IF NOT EXISTS points_lat_lng_idx ON points (lat, lng)" {
+		t.Errorf("Expected a table and index, got %v", queries)
+	}
+}
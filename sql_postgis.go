@@ -0,0 +1,45 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+)
+
+// This is the error that consumers receive when running a query that needs PostGIS
+// on a SQLMapper whose database isn't PostgreSQL.
+var ErrPostGISRequired = errors.New("query requires PostgreSQL with PostGIS")
+
+// Uses PostGIS to retrieve the points inside the passed in Polygon, ordered by id, with ST_Within:
+// points on the polygon's edges are left out.  The polygon is sent as an EWKB parameter, and the query
+// is answered from the spatial index on the table's geometry column that Migrate creates.
+// Suits zone membership queries, such as finding the stores in a delivery zone.
+func (s *SQLMapper) PointsWithinPolygon(poly *Polygon) ([]*SQLResult, error) {
+	return s.polygonQuery("ST_Within", poly)
+}
+
+// Uses PostGIS to retrieve the points inside or on the edges of the passed in Polygon, ordered by id,
+// with ST_Intersects.  Otherwise behaves as PointsWithinPolygon.
+func (s *SQLMapper) PointsIntersectingPolygon(poly *Polygon) ([]*SQLResult, error) {
+	return s.polygonQuery("ST_Intersects", poly)
+}
+
+// Retrieves the points whose geometry and the passed in Polygon satisfy the passed in PostGIS predicate.
+func (s *SQLMapper) polygonQuery(predicate string, poly *Polygon) ([]*SQLResult, error) {
+	if s.mysql() || s.sqlite() {
+		return nil, ErrPostGISRequired
+	}
+
+	if !poly.IsClosed() {
+		return nil, fmt.Errorf("polygon has %d points, fewer than 3", len(poly.Points()))
+	}
+
+	ewkb, err := MarshalEWKB(poly, SQL_SRID)
+	if err != nil {
+		return nil, err
+	}
+
+	c := s.conf
+	query := fmt.Sprintf("SELECT a.%s, a.%s, a.%s, 0 FROM %s a WHERE %s(a.%s, ST_GeomFromEWKB($1)) ORDER BY a.%s",
+		c.idCol, c.latCol, c.lngCol, c.table, predicate, c.geomCol, c.idCol)
+	return s.queryResults(query, ewkb)
+}
@@ -0,0 +1,50 @@
+package geo
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+// Ensures that polygon queries send the polygon as EWKB to the PostGIS predicate.
+func TestSQLMapperPolygonQueries(t *testing.T) {
+	zone := NewPolygon([]*Point{NewPoint(37.7, -122.5), NewPoint(37.8, -122.5), NewPoint(37.8, -122.4)})
+	ewkb, _ := MarshalEWKB(zone, SQL_SRID)
+
+	cases := []struct {
+		query    func(s *SQLMapper, poly *Polygon) ([]*SQLResult, error)
+		expected string
+	}{
+		{(*SQLMapper).PointsWithinPolygon, "SELECT a.id, a.lat, a.lng, 0 FROM points a WHERE ST_Within(a.geom, ST_GeomFromEWKB($1)) ORDER BY a.id"},
+		{(*SQLMapper).PointsIntersectingPolygon, "SELECT a.id, a.lat, a.lng, 0 FROM points a WHERE ST_Intersects(a.geom, ST_GeomFromEWKB($1)) ORDER BY a.id"},
+	}
+
+	for _, c := range cases {
+		db, d := openRecordingDB(t, []string{"id", "lat", "lng", "distance"}, []driver.Value{"7", 37.75, -122.45, 0.0})
+		s := &SQLMapper{conf: &SQLConf{driver: "postgres", table: "points", latCol: "lat", lngCol: "lng", idCol: "id", geomCol: "geom"}, sqlConn: db}
+
+		results, err := c.query(s, zone)
+		if err != nil || len(results) != 1 || results[0].ID != "7" || *results[0].Point != *NewPoint(37.75, -122.45) {
+			t.Errorf("Expected the point in the zone, got %v (%v)", results, err)
+		}
+
+		if queries := d.Queries(); len(queries) != 1 || queries[0] != c.expected {
+			t.Errorf("Expected %s, got %v", c.expected, queries)
+		}
+
+		if len(d.args) != 1 || len(d.args[0]) != 1 || !bytes.Equal(d.args[0][0].([]byte), ewkb) {
+			t.Errorf("Expected the zone as an EWKB argument, got %v", d.args)
+		}
+	}
+}
+
+// Ensures that polygon queries are refused without PostGIS.
+func TestSQLMapperPolygonQueriesMySQL(t *testing.T) {
+	s := &SQLMapper{conf: &SQLConf{driver: "mymysql"}}
+	zone := NewPolygon([]*Point{NewPoint(0, 0), NewPoint(0, 1), NewPoint(1, 1)})
+
+	if _, err := s.PointsWithinPolygon(zone); !errors.Is(err, ErrPostGISRequired) {
+		t.Errorf("Expected ErrPostGISRequired, got %v", err)
+	}
+}
@@ -0,0 +1,320 @@
+package geo
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// A PointSource yields points one at a time, so that datasets too large to hold
+// in memory can be processed as they are read.  Next returns io.EOF once every
+// point has been read.
+type PointSource interface {
+	Next() (*Point, error)
+}
+
+// A PointSink accepts points one at a time.
+type PointSink interface {
+	Write(p *Point) error
+}
+
+// Writes every point from the passed in source to the passed in sink,
+// returning the number of points copied and the first error encountered other than io.EOF.
+func CopyPoints(dst PointSink, src PointSource) (int, error) {
+	n := 0
+	for {
+		p, err := src.Next()
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+
+		if err := dst.Write(p); err != nil {
+			return n, err
+		}
+		n++
+	}
+}
+
+// A PointSource that yields the points of a slice, in order.
+type SlicePointSource struct {
+	points []*Point
+}
+
+// Creates and returns a pointer to a new SlicePointSource over the passed in points.
+func NewSlicePointSource(points []*Point) *SlicePointSource {
+	return &SlicePointSource{points: points}
+}
+
+// Returns the next point of the slice, or io.EOF once they have all been returned.
+func (s *SlicePointSource) Next() (*Point, error) {
+	if len(s.points) == 0 {
+		return nil, io.EOF
+	}
+
+	p := s.points[0]
+	s.points = s.points[1:]
+	return p, nil
+}
+
+// A PointSink that collects points into a slice.
+type SlicePointSink struct {
+	Points []*Point
+}
+
+// Appends the passed in point to the sink's Points.
+func (s *SlicePointSink) Write(p *Point) error {
+	s.Points = append(s.Points, p)
+	return nil
+}
+
+// A PointSource that reads points from CSV, one row at a time.
+type CSVPointSource struct {
+	reader *csv.Reader
+	lat    int
+	lng    int
+	line   int
+}
+
+// Creates and returns a pointer to a new CSVPointSource reading from the passed in reader.
+// The first row must be a header naming the "lat" and "lng" columns; any other columns are ignored.
+func NewCSVPointSource(r io.Reader) (*CSVPointSource, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &CSVPointSource{reader: cr, lat: -1, lng: -1, line: 1}
+	for i, column := range header {
+		switch strings.ToLower(strings.TrimSpace(column)) {
+		case "lat":
+			s.lat = i
+		case "lng":
+			s.lng = i
+		}
+	}
+
+	if s.lat < 0 || s.lng < 0 {
+		return nil, fmt.Errorf("point CSV must have both a \"lat\" and a \"lng\" column")
+	}
+
+	return s, nil
+}
+
+// Returns the point on the next row, or io.EOF once every row has been read.
+func (s *CSVPointSource) Next() (*Point, error) {
+	record, err := s.reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	s.line++
+
+	if s.lat >= len(record) || s.lng >= len(record) {
+		return nil, fmt.Errorf("point CSV line %d: too few columns", s.line)
+	}
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(record[s.lat]), 64)
+	if err != nil {
+		return nil, fmt.Errorf("point CSV line %d: %v", s.line, err)
+	}
+
+	lng, err := strconv.ParseFloat(strings.TrimSpace(record[s.lng]), 64)
+	if err != nil {
+		return nil, fmt.Errorf("point CSV line %d: %v", s.line, err)
+	}
+
+	return NewPoint(lat, lng), nil
+}
+
+// A PointSink that writes points as CSV rows with a "lat,lng" header.
+// Call Flush once every point has been written.
+type CSVPointSink struct {
+	writer      *csv.Writer
+	wroteHeader bool
+	precision   int
+}
+
+// Creates and returns a pointer to a new CSVPointSink writing to the passed in writer,
+// with as many decimal places as CoordinatePrecision returns.
+func NewCSVPointSink(w io.Writer) *CSVPointSink {
+	return &CSVPointSink{writer: csv.NewWriter(w), precision: CoordinatePrecision()}
+}
+
+// Sets the number of decimal places the current CSVPointSink rounds coordinates to,
+// or FULL_PRECISION to write them exactly.
+func (s *CSVPointSink) SetPrecision(decimals int) {
+	s.precision = decimals
+}
+
+// Writes the passed in point as a CSV row.
+func (s *CSVPointSink) Write(p *Point) error {
+	if !s.wroteHeader {
+		if err := s.writer.Write([]string{"lat", "lng"}); err != nil {
+			return err
+		}
+		s.wroteHeader = true
+	}
+
+	return s.writer.Write([]string{FormatCoordinate(p.lat, s.precision), FormatCoordinate(p.lng, s.precision)})
+}
+
+// Writes any buffered rows to the underlying writer.
+func (s *CSVPointSink) Flush() error {
+	s.writer.Flush()
+	return s.writer.Error()
+}
+
+// A PointSource that reads the Point features of a GeoJSON FeatureCollection one at a time,
+// without reading the whole document into memory.  Features with other geometries are skipped.
+type GeoJSONPointSource struct {
+	decoder *json.Decoder
+	started bool
+}
+
+// Creates and returns a pointer to a new GeoJSONPointSource reading from the passed in reader.
+func NewGeoJSONPointSource(r io.Reader) *GeoJSONPointSource {
+	return &GeoJSONPointSource{decoder: json.NewDecoder(r)}
+}
+
+// The fields of a GeoJSON feature that a GeoJSONPointSource reads.
+type geoJSONPointFeature struct {
+	Geometry *struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	} `json:"geometry"`
+}
+
+// Returns the next Point feature's location, or io.EOF once every feature has been read.
+func (s *GeoJSONPointSource) Next() (*Point, error) {
+	if !s.started {
+		if err := s.seekFeatures(); err != nil {
+			return nil, err
+		}
+		s.started = true
+	}
+
+	for s.decoder.More() {
+		var feature geoJSONPointFeature
+		if err := s.decoder.Decode(&feature); err != nil {
+			return nil, err
+		}
+
+		g := feature.Geometry
+		if g == nil || g.Type != "Point" {
+			continue
+		}
+
+		var coordinates []float64
+		if err := json.Unmarshal(g.Coordinates, &coordinates); err != nil {
+			return nil, err
+		}
+
+		if len(coordinates) < 2 {
+			return nil, fmt.Errorf("GeoJSON point has %d coordinates", len(coordinates))
+		}
+
+		// GeoJSON orders coordinates as [lng, lat].
+		return NewPoint(coordinates[1], coordinates[0]), nil
+	}
+
+	return nil, io.EOF
+}
+
+// Advances the decoder to the first element of the top-level "features" array.
+func (s *GeoJSONPointSource) seekFeatures() error {
+	if t, err := s.decoder.Token(); err != nil {
+		return err
+	} else if t != json.Delim('{') {
+		return fmt.Errorf("GeoJSON must be a FeatureCollection object")
+	}
+
+	for s.decoder.More() {
+		t, err := s.decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		if t == "features" {
+			if t, err := s.decoder.Token(); err != nil {
+				return err
+			} else if t != json.Delim('[') {
+				return fmt.Errorf("GeoJSON features must be an array")
+			}
+			return nil
+		}
+
+		// Skip the value of any other member.
+		var skip json.RawMessage
+		if err := s.decoder.Decode(&skip); err != nil {
+			return err
+		}
+	}
+
+	return io.EOF
+}
+
+// A cell of the grid built by AggregateGrid.
+type GridCell struct {
+	// The point the cell's points snap to.
+	Center *Point
+
+	// The number of points in the cell.
+	Count int
+
+	// The mean location of the points in the cell.
+	Centroid *Point
+}
+
+// Reads every point from the passed in source and groups them into a grid of cells
+// roughly the passed in number of meters across, as Snap does.  Only one running total
+// is kept per cell, so memory grows with the area covered rather than the number of points.
+// Returns the occupied cells in the order they were first seen.
+func AggregateGrid(src PointSource, gridMeters float64) ([]*GridCell, error) {
+	type total struct {
+		cell     *GridCell
+		lat, lng float64
+	}
+
+	var order []*total
+	totals := make(map[Point]*total)
+	for {
+		p, err := src.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		center := Snap(p, gridMeters)
+		t, ok := totals[*center]
+		if !ok {
+			t = &total{cell: &GridCell{Center: center}}
+			totals[*center] = t
+			order = append(order, t)
+		}
+
+		t.cell.Count++
+		t.lat += p.lat
+		// Sum longitudes relative to the cell, so that cells on the antimeridian average correctly.
+		t.lng += LngDiff(center.lng, p.lng)
+	}
+
+	cells := make([]*GridCell, len(order))
+	for i, t := range order {
+		n := float64(t.cell.Count)
+		t.cell.Centroid = NewPoint(t.lat/n, NormalizeLng(t.cell.Center.lng+t.lng/n))
+		cells[i] = t.cell
+	}
+
+	return cells, nil
+}
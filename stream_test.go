@@ -0,0 +1,115 @@
+package geo
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"testing"
+)
+
+// Ensures that points can be read from CSV, with extra columns ignored, and written back out.
+func TestCSVPointSourceAndSink(t *testing.T) {
+	src, err := NewCSVPointSource(strings.NewReader("id,lng,lat\n1,-122.4194,37.7749\n2,-73.9855,40.758\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	sink := NewCSVPointSink(&buf)
+	n, err := CopyPoints(sink, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sink.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if n != 2 {
+		t.Errorf("Expected 2 points to be copied, got %d", n)
+	}
+
+	if buf.String() != "lat,lng\n37.7749,-122.4194\n40.758,-73.9855\n" {
+		t.Errorf("Unexpected CSV output %q", buf.String())
+	}
+}
+
+// Ensures that malformed CSV is reported with its line number.
+func TestCSVPointSourceErrors(t *testing.T) {
+	if _, err := NewCSVPointSource(strings.NewReader("name,lat\n")); err == nil {
+		t.Error("Expected an error for a header without a lng column")
+	}
+
+	src, err := NewCSVPointSource(strings.NewReader("lat,lng\n1,2\nnorth,2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := src.Next(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := src.Next(); err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("Expected an error naming line 3, got %v", err)
+	}
+}
+
+// Ensures that Point features are streamed from a GeoJSON FeatureCollection, skipping other geometries.
+func TestGeoJSONPointSource(t *testing.T) {
+	f, err := os.Open("test/data/points.geojson")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	sink := &SlicePointSink{}
+	if _, err := CopyPoints(sink, NewGeoJSONPointSource(f)); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sink.Points) != 2 {
+		t.Fatalf("Expected 2 points, got %d", len(sink.Points))
+	}
+
+	if sink.Points[0].Lat() != 37.7749 || sink.Points[0].Lng() != -122.4194 {
+		t.Errorf("Expected [37.7749, -122.4194], got %v", sink.Points[0])
+	}
+
+	if _, err := NewGeoJSONPointSource(strings.NewReader("[]")).Next(); err == nil || err == io.EOF {
+		t.Errorf("Expected an error for GeoJSON that isn't a FeatureCollection, got %v", err)
+	}
+}
+
+// Ensures that AggregateGrid counts and averages the points in each cell.
+func TestAggregateGrid(t *testing.T) {
+	points := []*Point{
+		NewPoint(37.77490, -122.41940),
+		NewPoint(37.77492, -122.41942),
+		NewPoint(40.75800, -73.98550),
+		NewPoint(-16.50000, 179.99999),
+		NewPoint(-16.50000, -179.99999),
+	}
+
+	cells, err := AggregateGrid(NewSlicePointSource(points), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cells) != 3 {
+		t.Fatalf("Expected 3 cells, got %d", len(cells))
+	}
+
+	if cells[0].Count != 2 || cells[1].Count != 1 || cells[2].Count != 2 {
+		t.Errorf("Expected counts of 2, 1 and 2, got %d, %d and %d", cells[0].Count, cells[1].Count, cells[2].Count)
+	}
+
+	if c := cells[0].Centroid; math.Abs(c.Lat()-37.77491) > 1e-9 || c.Lng() > -122.41940 || c.Lng() < -122.41942 {
+		t.Errorf("Expected the centroid to be the mean of its points, got %v", c)
+	}
+
+	if lng := cells[2].Centroid.Lng(); lng > -179.9999 && lng < 179.9999 {
+		t.Errorf("Expected a cell on the antimeridian to average across it, got %f", lng)
+	}
+}
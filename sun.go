@@ -0,0 +1,137 @@
+package geo
+
+import (
+	"math"
+	"time"
+)
+
+// The elevations of the sun's center, in degrees, that mark each solar event.
+// Sunrise and sunset allow for refraction and the radius of the sun's disc.
+const (
+	SUNRISE_ELEVATION     = -0.833
+	CIVIL_TWILIGHT        = -6.0
+	NAUTICAL_TWILIGHT     = -12.0
+	ASTRONOMICAL_TWILIGHT = -18.0
+)
+
+// The Julian days of the Unix epoch and of the J2000 epoch.
+const (
+	julianDayOfUnixEpoch = 2440587.5
+	julianDayOfJ2000     = 2451545.0
+)
+
+// The tilt of the earth's axis and the ecliptic longitude of its perihelion, in degrees.
+const (
+	earthAxialTilt      = 23.4397
+	perihelionLongitude = 102.9372
+)
+
+// The times of the sun's daily events at a place.  Events that don't happen on
+// the day, such as sunset during the polar summer, are the zero time.
+type SolarTimes struct {
+	AstronomicalDawn time.Time
+	NauticalDawn     time.Time
+	CivilDawn        time.Time
+	Sunrise          time.Time
+	SolarNoon        time.Time
+	Sunset           time.Time
+	CivilDusk        time.Time
+	NauticalDusk     time.Time
+	AstronomicalDusk time.Time
+}
+
+// Returns the time the passed in Julian day falls at, in the passed in location.
+func julianDayToTime(jd float64, loc *time.Location) time.Time {
+	seconds := (jd - julianDayOfUnixEpoch) * 86400
+	return time.Unix(0, int64(math.Round(seconds))*int64(time.Second)).In(loc)
+}
+
+// Returns the Julian day of the passed in time.
+func timeToJulianDay(t time.Time) float64 {
+	return float64(t.UnixNano())/float64(time.Second)/86400 + julianDayOfUnixEpoch
+}
+
+// Returns the times of the sun's events at the passed in point on the calendar day
+// of the passed in date, in the date's location.  Uses the sunrise equation, which is
+// accurate to within a minute or two away from the poles.
+func SunTimes(p *Point, date time.Time) SolarTimes {
+	loc := date.Location()
+	noonUTC := time.Date(date.Year(), date.Month(), date.Day(), 12, 0, 0, 0, time.UTC)
+	n := math.Round(timeToJulianDay(noonUTC) - julianDayOfJ2000)
+
+	// The mean solar noon at the point, the sun's mean anomaly and its ecliptic longitude.
+	meanNoon := n - NormalizeLng(p.lng)/360
+	m := math.Mod(357.5291+0.98560028*meanNoon, 360) * math.Pi / 180
+	center := 1.9148*math.Sin(m) + 0.02*math.Sin(2*m) + 0.0003*math.Sin(3*m)
+	lambda := math.Mod(m*180/math.Pi+center+180+perihelionLongitude, 360) * math.Pi / 180
+
+	transit := julianDayOfJ2000 + meanNoon + 0.0053*math.Sin(m) - 0.0069*math.Sin(2*lambda)
+	sinDecl := math.Sin(lambda) * math.Sin(earthAxialTilt*math.Pi/180)
+	cosDecl := math.Cos(math.Asin(sinDecl))
+	lat := p.lat * math.Pi / 180
+
+	// Returns the times the sun passes the passed in elevation, or zero times if it doesn't.
+	crossing := func(elevation float64) (time.Time, time.Time) {
+		cosHourAngle := (math.Sin(elevation*math.Pi/180) - math.Sin(lat)*sinDecl) / (math.Cos(lat) * cosDecl)
+		if cosHourAngle < -1 || cosHourAngle > 1 || math.IsNaN(cosHourAngle) {
+			return time.Time{}, time.Time{}
+		}
+
+		hourAngle := math.Acos(cosHourAngle) * 180 / math.Pi
+		return julianDayToTime(transit-hourAngle/360, loc), julianDayToTime(transit+hourAngle/360, loc)
+	}
+
+	var times SolarTimes
+	times.SolarNoon = julianDayToTime(transit, loc)
+	times.Sunrise, times.Sunset = crossing(SUNRISE_ELEVATION)
+	times.CivilDawn, times.CivilDusk = crossing(CIVIL_TWILIGHT)
+	times.NauticalDawn, times.NauticalDusk = crossing(NAUTICAL_TWILIGHT)
+	times.AstronomicalDawn, times.AstronomicalDusk = crossing(ASTRONOMICAL_TWILIGHT)
+
+	return times
+}
+
+// Returns the position of the sun as seen from the passed in point at the passed in time:
+// its azimuth in degrees clockwise from north, and its elevation in degrees above the
+// horizon, not allowing for refraction.  Follows NOAA's solar position calculations.
+func SolarPosition(p *Point, t time.Time) (azimuth float64, elevation float64) {
+	rad := math.Pi / 180
+	century := (timeToJulianDay(t) - julianDayOfJ2000) / 36525
+
+	meanLng := math.Mod(280.46646+century*(36000.76983+century*0.0003032), 360)
+	meanAnomaly := 357.52911 + century*(35999.05029-0.0001537*century)
+	eccentricity := 0.016708634 - century*(0.000042037+0.0000001267*century)
+	center := math.Sin(meanAnomaly*rad)*(1.914602-century*(0.004817+0.000014*century)) +
+		math.Sin(2*meanAnomaly*rad)*(0.019993-0.000101*century) +
+		math.Sin(3*meanAnomaly*rad)*0.000289
+
+	omega := 125.04 - 1934.136*century
+	apparentLng := meanLng + center - 0.00569 - 0.00478*math.Sin(omega*rad)
+	meanObliquity := 23 + (26+(21.448-century*(46.815+century*(0.00059-century*0.001813)))/60)/60
+	obliquity := meanObliquity + 0.00256*math.Cos(omega*rad)
+	declination := math.Asin(math.Sin(obliquity*rad) * math.Sin(apparentLng*rad))
+
+	// The equation of time, in minutes.
+	y := math.Pow(math.Tan(obliquity*rad/2), 2)
+	eqTime := 4 / rad * (y*math.Sin(2*meanLng*rad) -
+		2*eccentricity*math.Sin(meanAnomaly*rad) +
+		4*eccentricity*y*math.Sin(meanAnomaly*rad)*math.Cos(2*meanLng*rad) -
+		0.5*y*y*math.Sin(4*meanLng*rad) -
+		1.25*eccentricity*eccentricity*math.Sin(2*meanAnomaly*rad))
+
+	utc := t.UTC()
+	minutes := float64(utc.Hour()*60+utc.Minute()) + (float64(utc.Second())+float64(utc.Nanosecond())/1e9)/60
+	trueSolarTime := math.Mod(minutes+eqTime+4*NormalizeLng(p.lng)+1440, 1440)
+	hourAngle := trueSolarTime/4 - 180
+
+	lat := p.lat * rad
+	cosZenith := math.Sin(lat)*math.Sin(declination) + math.Cos(lat)*math.Cos(declination)*math.Cos(hourAngle*rad)
+	zenith := math.Acos(math.Max(-1, math.Min(1, cosZenith)))
+
+	azimuth = math.Atan2(
+		math.Sin(hourAngle*rad),
+		math.Cos(hourAngle*rad)*math.Sin(lat)-math.Tan(declination)*math.Cos(lat),
+	)/rad + 180
+
+	return math.Mod(azimuth, 360), 90 - zenith/rad
+}
@@ -0,0 +1,88 @@
+package geo
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// Returns whether or not the passed in times are within the passed in duration of each other.
+func timesNear(a, b time.Time, within time.Duration) bool {
+	d := a.Sub(b)
+	return d < within && d > -within
+}
+
+// Ensures that sunrise, sunset and twilight match published times.
+func TestSunTimes(t *testing.T) {
+	london := NewPoint(51.5074, -0.1278)
+	times := SunTimes(london, time.Date(2024, 6, 21, 0, 0, 0, 0, time.UTC))
+
+	cases := []struct {
+		name      string
+		got, want time.Time
+	}{
+		{"sunrise", times.Sunrise, time.Date(2024, 6, 21, 3, 43, 0, 0, time.UTC)},
+		{"solar noon", times.SolarNoon, time.Date(2024, 6, 21, 12, 2, 0, 0, time.UTC)},
+		{"sunset", times.Sunset, time.Date(2024, 6, 21, 20, 21, 0, 0, time.UTC)},
+		{"civil dawn", times.CivilDawn, time.Date(2024, 6, 21, 2, 55, 0, 0, time.UTC)},
+		{"civil dusk", times.CivilDusk, time.Date(2024, 6, 21, 21, 9, 0, 0, time.UTC)},
+	}
+
+	for _, c := range cases {
+		if !timesNear(c.got, c.want, 3*time.Minute) {
+			t.Errorf("Expected %s in London near %v, got %v", c.name, c.want, c.got)
+		}
+	}
+
+	// London doesn't get astronomically dark at midsummer.
+	if !times.AstronomicalDusk.IsZero() {
+		t.Errorf("Expected no astronomical dusk in London at midsummer, got %v", times.AstronomicalDusk)
+	}
+
+	// Times are returned in the date's location.
+	sydney, _ := time.LoadLocation("Australia/Sydney")
+	if sydney != nil {
+		times := SunTimes(NewPoint(-33.8688, 151.2093), time.Date(2024, 12, 21, 0, 0, 0, 0, sydney))
+		want := time.Date(2024, 12, 21, 5, 41, 0, 0, sydney)
+		if !timesNear(times.Sunrise, want, 3*time.Minute) || times.Sunrise.Location() != sydney {
+			t.Errorf("Expected sunrise in Sydney near %v, got %v", want, times.Sunrise)
+		}
+	}
+}
+
+// Ensures that days without a sunrise or sunset report them as zero.
+func TestSunTimesPolar(t *testing.T) {
+	tromso := NewPoint(69.6492, 18.9553)
+
+	summer := SunTimes(tromso, time.Date(2024, 6, 21, 0, 0, 0, 0, time.UTC))
+	if !summer.Sunrise.IsZero() || !summer.Sunset.IsZero() {
+		t.Errorf("Expected the midnight sun in Tromsø, got %v and %v", summer.Sunrise, summer.Sunset)
+	}
+
+	winter := SunTimes(tromso, time.Date(2024, 12, 21, 0, 0, 0, 0, time.UTC))
+	if !winter.Sunrise.IsZero() || winter.CivilDawn.IsZero() {
+		t.Errorf("Expected polar night with civil twilight in Tromsø, got %+v", winter)
+	}
+}
+
+// Ensures that the sun's position matches the times it rises and culminates.
+func TestSolarPosition(t *testing.T) {
+	london := NewPoint(51.5074, -0.1278)
+	times := SunTimes(london, time.Date(2024, 6, 21, 0, 0, 0, 0, time.UTC))
+
+	azimuth, elevation := SolarPosition(london, times.SolarNoon)
+	if math.Abs(azimuth-180) > 1 || math.Abs(elevation-61.95) > 0.2 {
+		t.Errorf("Expected the sun due south at 61.95 degrees at noon, got %v at %v", azimuth, elevation)
+	}
+
+	azimuth, elevation = SolarPosition(london, times.Sunrise)
+	if math.Abs(azimuth-49) > 2 || math.Abs(elevation-SUNRISE_ELEVATION) > 0.3 {
+		t.Errorf("Expected the sun on the horizon to the north east at sunrise, got %v at %v", azimuth, elevation)
+	}
+
+	// South of the tropics, the noon sun is to the north.
+	azimuth, _ = SolarPosition(NewPoint(-33.8688, 151.2093), SunTimes(NewPoint(-33.8688, 151.2093), time.Date(2024, 6, 21, 0, 0, 0, 0, time.UTC)).SolarNoon)
+	if azimuth > 1 && azimuth < 359 {
+		t.Errorf("Expected the noon sun due north in Sydney, got %v", azimuth)
+	}
+}
@@ -0,0 +1,278 @@
+package geo
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// The width and height, in pixels, of a map tile.
+const TILE_SIZE = 256
+
+// The latitude beyond which web mercator tiles do not reach.
+const MAX_MERCATOR_LAT = 85.0511287798066
+
+// The number of tiles a TileFetcher downloads at once, unless told otherwise.
+const DEFAULT_TILE_CONCURRENCY = 4
+
+// The largest image, in pixels along either side, that Stitch will produce.
+const MAX_STITCH_SIZE = 8192
+
+// The User-Agent a TileFetcher sends unless told otherwise.  Tile servers
+// such as OpenStreetMap's refuse requests that do not identify themselves.
+//
+// Deprecated: TileFetchers without a UserAgent send that of the package-wide Identity set with SetIdentity,
+// which is DEFAULT_USER_AGENT unless told otherwise.
+const DEFAULT_TILE_USER_AGENT = DEFAULT_USER_AGENT
+
+// A Tile is a single web mercator ("slippy map") tile, addressed as in XYZ tile URLs.
+type Tile struct {
+	X, Y, Z int
+}
+
+// Returns the fractional position of the passed in point in the world of tiles at the passed in zoom.
+func tileCoordinates(lat, lng float64, zoom int) (float64, float64) {
+	n := math.Exp2(float64(zoom))
+	lat = math.Max(-MAX_MERCATOR_LAT, math.Min(MAX_MERCATOR_LAT, lat))
+	latR := lat * math.Pi / 180
+
+	x := (NormalizeLng(lng) + 180) / 360 * n
+	y := (1 - math.Log(math.Tan(latR)+1/math.Cos(latR))/math.Pi) / 2 * n
+	return x, y
+}
+
+// Returns the tile at the passed in zoom containing the passed in point.
+func TileAt(p *Point, zoom int) Tile {
+	x, y := tileCoordinates(p.lat, p.lng, zoom)
+	n := 1 << uint(zoom)
+	return Tile{X: min(int(x), n-1), Y: min(int(y), n-1), Z: zoom}
+}
+
+// Returns the Bounds covered by the current Tile.
+func (t Tile) Bounds() *Bounds {
+	n := math.Exp2(float64(t.Z))
+	lng := func(x int) float64 { return float64(x)/n*360 - 180 }
+	lat := func(y int) float64 { return math.Atan(math.Sinh(math.Pi*(1-2*float64(y)/n))) * 180 / math.Pi }
+
+	return NewBounds(NewPoint(lat(t.Y+1), lng(t.X)), NewPoint(lat(t.Y), lng(t.X+1)))
+}
+
+// Returns the tiles at the passed in zoom that cover the passed in Bounds, row by row.
+// Bounds whose west edge is east of their east edge are taken to cross the antimeridian.
+func TilesCovering(b *Bounds, zoom int) []Tile {
+	minX, maxX, minY, maxY := tileRange(b, zoom)
+	n := 1 << uint(zoom)
+
+	var tiles []Tile
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			tiles = append(tiles, Tile{X: (x%n + n) % n, Y: y, Z: zoom})
+		}
+	}
+
+	return tiles
+}
+
+// Returns the range of tiles covering the passed in Bounds.  The X range may run
+// past the last column when the Bounds cross the antimeridian.
+func tileRange(b *Bounds, zoom int) (minX, maxX, minY, maxY int) {
+	sw, ne := TileAt(b.SouthWest(), zoom), TileAt(b.NorthEast(), zoom)
+	minX, maxX = sw.X, ne.X
+	if NormalizeLng(b.SouthWest().lng) > NormalizeLng(b.NorthEast().lng) {
+		maxX += 1 << uint(zoom)
+	}
+
+	return minX, maxX, ne.Y, sw.Y
+}
+
+// A TileFetcher downloads map tiles from a tile server, optionally keeping them in a TileCache.
+// Template is an XYZ tile URL such as "https://{s}.tile.openstreetmap.org/{z}/{x}/{y}.png",
+// where {s} is replaced by one of Subdomains.  A TileFetcher is safe for concurrent use.
+type TileFetcher struct {
+	Template   string
+	Subdomains []string
+	Cache      *TileCache
+	Client     *http.Client
+	UserAgent  string
+
+	// The most tiles downloaded at once across all calls.  Defaults to DEFAULT_TILE_CONCURRENCY.
+	MaxConcurrent int
+
+	once sync.Once
+	sem  chan struct{}
+}
+
+// Creates and returns a pointer to a new TileFetcher for the passed in XYZ URL template,
+// keeping tiles in the passed in cache unless it is nil.
+func NewTileFetcher(template string, cache *TileCache) *TileFetcher {
+	return &TileFetcher{
+		Template:   template,
+		Subdomains: []string{"a", "b", "c"},
+		Cache:      cache,
+	}
+}
+
+// Returns the URL of the passed in tile.
+func (f *TileFetcher) URL(t Tile) string {
+	s := ""
+	if len(f.Subdomains) > 0 {
+		s = f.Subdomains[(t.X+t.Y)%len(f.Subdomains)]
+	}
+
+	return strings.NewReplacer(
+		"{s}", s,
+		"{z}", strconv.Itoa(t.Z),
+		"{x}", strconv.Itoa(t.X),
+		"{y}", strconv.Itoa(t.Y),
+	).Replace(f.Template)
+}
+
+// Returns the image bytes of the passed in tile, from the cache if it holds them.
+func (f *TileFetcher) Fetch(t Tile) ([]byte, error) {
+	// Key on the template without its subdomain, which doesn't change the tile.
+	key := strings.Replace(f.Template, "{s}", "", -1) + "|" + strconv.Itoa(t.Z) + "/" + strconv.Itoa(t.X) + "/" + strconv.Itoa(t.Y)
+	if f.Cache != nil {
+		if data, ok := f.Cache.Get(key); ok {
+			return data, nil
+		}
+	}
+
+	f.once.Do(func() {
+		n := f.MaxConcurrent
+		if n <= 0 {
+			n = DEFAULT_TILE_CONCURRENCY
+		}
+		f.sem = make(chan struct{}, n)
+	})
+
+	f.sem <- struct{}{}
+	data, err := f.download(t)
+	<-f.sem
+	if err != nil {
+		return nil, err
+	}
+
+	if f.Cache != nil {
+		if err := f.Cache.Put(key, data); err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
+
+// Downloads the passed in tile from the tile server.
+func (f *TileFetcher) download(t Tile) ([]byte, error) {
+	client := f.Client
+	if client == nil {
+		client = defaultClient()
+	}
+
+	req, err := http.NewRequest("GET", f.URL(t), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if f.UserAgent != "" {
+		req.Header.Set("User-Agent", f.UserAgent)
+	}
+	prepareRequest(req)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, scrubError(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("tile %d/%d/%d: server responded %d", t.Z, t.X, t.Y, resp.StatusCode)
+	}
+
+	return readBody(resp)
+}
+
+// Returns the image bytes of each of the passed in tiles, downloading them concurrently.
+// Returns the first error encountered, if any.
+func (f *TileFetcher) FetchAll(tiles []Tile) ([][]byte, error) {
+	results := make([][]byte, len(tiles))
+	errs := make([]error, len(tiles))
+
+	var wg sync.WaitGroup
+	for i, t := range tiles {
+		wg.Add(1)
+		go func(i int, t Tile) {
+			defer wg.Done()
+			results[i], errs[i] = f.Fetch(t)
+		}(i, t)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+// Returns an image of the passed in Bounds at the passed in zoom, stitched together
+// from the tiles covering it and cropped to its edges.
+func (f *TileFetcher) Stitch(b *Bounds, zoom int) (*image.RGBA, error) {
+	img, _, err := f.stitch(b, zoom)
+	return img, err
+}
+
+// Stitches the tiles covering the passed in Bounds, returning the image and the position,
+// in pixels of the world at the passed in zoom, of its top left corner.
+func (f *TileFetcher) stitch(b *Bounds, zoom int) (*image.RGBA, image.Point, error) {
+	west, north := tileCoordinates(b.NorthEast().lat, b.SouthWest().lng, zoom)
+	east, south := tileCoordinates(b.SouthWest().lat, b.NorthEast().lng, zoom)
+	if NormalizeLng(b.SouthWest().lng) > NormalizeLng(b.NorthEast().lng) {
+		east += math.Exp2(float64(zoom))
+	}
+
+	// Round away floating point noise, so that bounds on tile edges don't gain a row of pixels.
+	pixel := func(v float64, round func(float64) float64) int {
+		return int(round(math.Round(v*TILE_SIZE*1e6) / 1e6))
+	}
+
+	worldSize := TILE_SIZE << uint(zoom)
+	origin := image.Pt(pixel(west, math.Floor), max(0, pixel(north, math.Floor)))
+	width := pixel(east, math.Ceil) - origin.X
+	height := min(worldSize, pixel(south, math.Ceil)) - origin.Y
+	if width > MAX_STITCH_SIZE || height > MAX_STITCH_SIZE {
+		return nil, origin, fmt.Errorf("stitched image would be %dx%d pixels; lower the zoom", width, height)
+	}
+
+	minX, maxX, minY, maxY := tileRange(b, zoom)
+	data, err := f.FetchAll(TilesCovering(b, zoom))
+	if err != nil {
+		return nil, origin, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, max(width, 1), max(height, 1)))
+	i := 0
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			tile, _, err := image.Decode(bytes.NewReader(data[i]))
+			if err != nil {
+				return nil, origin, fmt.Errorf("tile %d/%d/%d: %v", zoom, x, y, err)
+			}
+			i++
+
+			at := image.Pt(x*TILE_SIZE-origin.X, y*TILE_SIZE-origin.Y)
+			draw.Draw(img, tile.Bounds().Sub(tile.Bounds().Min).Add(at), tile, tile.Bounds().Min, draw.Src)
+		}
+	}
+
+	return img, origin, nil
+}
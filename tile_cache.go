@@ -0,0 +1,204 @@
+package geo
+
+import (
+	"container/list"
+	"crypto/sha1"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The file extension of tiles stored by a TileCache.
+const tileCacheExt = ".tile"
+
+// A TileCache keeps map tiles in a directory on disk, evicting the least
+// recently used tiles once their total size passes a limit.  Recency survives
+// restarts, since it is recorded in each file's modification time.
+// A TileCache is safe for concurrent use, but not for sharing a directory
+// between processes.
+type TileCache struct {
+	dir string
+
+	// When the tiles total more than MaxBytes, the least recently used are removed.
+	// A MaxBytes of zero places no limit on the size of the cache.
+	MaxBytes int64
+
+	// If set, every Get is counted as a hit or a miss of the "tile" cache.
+	Metrics *Metrics
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+	size    int64
+
+	// Used to record when tiles were last used.  Overridable for testing.
+	now func() time.Time
+}
+
+// A tile held in a TileCache: its file name and size in bytes.
+type tileCacheEntry struct {
+	name string
+	size int64
+}
+
+// Opens (creating if necessary) the passed in directory and returns a pointer
+// to a TileCache backed by it, limited to the passed in number of bytes.
+func OpenTileCache(dir string, maxBytes int64) (*TileCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &TileCache{
+		dir:      dir,
+		MaxBytes: maxBytes,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+		now:      time.Now,
+	}
+
+	// Most recently used at the front.
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ModTime().After(infos[j].ModTime()) })
+	for _, info := range infos {
+		if info.IsDir() || !strings.HasSuffix(info.Name(), tileCacheExt) {
+			continue
+		}
+
+		entry := &tileCacheEntry{name: info.Name(), size: info.Size()}
+		c.entries[entry.name] = c.order.PushBack(entry)
+		c.size += entry.size
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c, c.evict()
+}
+
+// Returns the file name the passed in key is stored under.
+func tileCacheName(key string) string {
+	sum := sha1.Sum([]byte(key))
+	return hex.EncodeToString(sum[:]) + tileCacheExt
+}
+
+// Returns the tile stored under the passed in key, and whether or not there was one.
+func (c *TileCache) Get(key string) ([]byte, bool) {
+	name := tileCacheName(key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[name]
+	if !ok {
+		c.Metrics.recordCache("tile", false)
+		return nil, false
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(c.dir, name))
+	if err != nil {
+		c.remove(el)
+		c.Metrics.recordCache("tile", false)
+		return nil, false
+	}
+
+	c.Metrics.recordCache("tile", true)
+
+	now := c.now()
+	os.Chtimes(filepath.Join(c.dir, name), now, now)
+	c.order.MoveToFront(el)
+
+	return data, true
+}
+
+// Stores the passed in tile under the passed in key, evicting other tiles if the cache is full.
+func (c *TileCache) Put(key string, data []byte) error {
+	name := tileCacheName(key)
+	path := filepath.Join(c.dir, name)
+
+	// Write to a temporary file first, so that a crash never leaves a partial tile behind.
+	tmp, err := ioutil.TempFile(c.dir, "put-*.tmp")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	now := c.now()
+	os.Chtimes(path, now, now)
+
+	if el, ok := c.entries[name]; ok {
+		entry := el.Value.(*tileCacheEntry)
+		c.size += int64(len(data)) - entry.size
+		entry.size = int64(len(data))
+		c.order.MoveToFront(el)
+	} else {
+		entry := &tileCacheEntry{name: name, size: int64(len(data))}
+		c.entries[name] = c.order.PushFront(entry)
+		c.size += entry.size
+	}
+
+	return c.evict()
+}
+
+// Returns the number of tiles in the cache.
+func (c *TileCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.entries)
+}
+
+// Returns the total size, in bytes, of the tiles in the cache.
+func (c *TileCache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.size
+}
+
+// Removes the least recently used tiles until the cache is within MaxBytes.
+// Callers must hold c.mu.
+func (c *TileCache) evict() error {
+	for c.MaxBytes > 0 && c.size > c.MaxBytes && c.order.Len() > 0 {
+		el := c.order.Back()
+		if err := os.Remove(filepath.Join(c.dir, el.Value.(*tileCacheEntry).name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		c.remove(el)
+	}
+
+	return nil
+}
+
+// Forgets the passed in tile.  Callers must hold c.mu.
+func (c *TileCache) remove(el *list.Element) {
+	entry := c.order.Remove(el).(*tileCacheEntry)
+	delete(c.entries, entry.name)
+	c.size -= entry.size
+}
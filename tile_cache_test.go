@@ -0,0 +1,74 @@
+package geo
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// Ensures that tiles are stored and returned, and that the least recently used are evicted.
+func TestTileCacheLRU(t *testing.T) {
+	c, err := OpenTileCache(t.TempDir(), 25)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { clock = clock.Add(time.Second); return clock }
+
+	for _, key := range []string{"a", "b"} {
+		if err := c.Put(key, bytes.Repeat([]byte(key), 10)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if data, ok := c.Get("a"); !ok || string(data) != "aaaaaaaaaa" {
+		t.Errorf("Expected the tile stored under a, got %q (%v)", data, ok)
+	}
+
+	// Storing c pushes the cache past its limit, evicting b, the least recently used.
+	if err := c.Put("c", bytes.Repeat([]byte("c"), 10)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("Expected b to have been evicted")
+	}
+
+	if c.Len() != 2 || c.Size() != 20 {
+		t.Errorf("Expected 2 tiles totalling 20 bytes, got %d totalling %d", c.Len(), c.Size())
+	}
+
+	if _, ok := c.Get("missing"); ok {
+		t.Error("Expected a miss for a key never stored")
+	}
+}
+
+// Ensures that tiles, and the order they were used in, survive reopening the cache.
+func TestTileCacheReopen(t *testing.T) {
+	dir := t.TempDir()
+	c, err := OpenTileCache(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { clock = clock.Add(time.Second); return clock }
+
+	c.Put("old", []byte("0123456789"))
+	c.Put("new", []byte("0123456789"))
+	c.Get("old")
+
+	reopened, err := OpenTileCache(dir, 15)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := reopened.Get("old"); !ok {
+		t.Error("Expected the most recently used tile to survive reopening")
+	}
+
+	if _, ok := reopened.Get("new"); ok {
+		t.Error("Expected the least recently used tile to be evicted on reopening with a smaller limit")
+	}
+}
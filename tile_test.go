@@ -0,0 +1,158 @@
+package geo
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Ensures that points are placed in the right tiles, and tiles cover the right areas.
+func TestTileAt(t *testing.T) {
+	cases := []struct {
+		p    *Point
+		zoom int
+		want Tile
+	}{
+		{NewPoint(0, 0), 0, Tile{0, 0, 0}},
+		{NewPoint(51.5074, -0.1278), 10, Tile{511, 340, 10}},
+		{NewPoint(-33.8688, 151.2093), 12, Tile{3768, 2457, 12}},
+		{NewPoint(84, 180), 3, Tile{7, 0, 3}},
+	}
+
+	for _, c := range cases {
+		if got := TileAt(c.p, c.zoom); got != c.want {
+			t.Errorf("Expected %v to be in tile %v, got %v", c.p, c.want, got)
+		}
+
+		if !TileAt(c.p, c.zoom).Bounds().Contains(c.p) {
+			t.Errorf("Expected the bounds of tile %v to contain %v", c.want, c.p)
+		}
+	}
+}
+
+// Ensures that the tiles covering bounds wrap across the antimeridian.
+func TestTilesCovering(t *testing.T) {
+	tiles := TilesCovering(NewBounds(NewPoint(-10, 170), NewPoint(10, -170)), 2)
+	want := []Tile{{3, 1, 2}, {0, 1, 2}, {3, 2, 2}, {0, 2, 2}}
+	if fmt.Sprint(tiles) != fmt.Sprint(want) {
+		t.Errorf("Expected %v, got %v", want, tiles)
+	}
+}
+
+// Returns a PNG tile filled with a color derived from its position.
+func solidTile(x, y int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, TILE_SIZE, TILE_SIZE))
+	c := color.RGBA{uint8(x * 40), uint8(y * 40), 200, 255}
+	for i := 0; i < TILE_SIZE; i++ {
+		for j := 0; j < TILE_SIZE; j++ {
+			img.Set(i, j, c)
+		}
+	}
+
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	return buf.Bytes()
+}
+
+// Starts a tile server that serves solidTile for every tile, counting requests
+// and the most served at once.
+func newTileServer(t *testing.T) (*httptest.Server, *int32, *int32) {
+	var requests, inFlight, peak int32
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		n := atomic.AddInt32(&inFlight, 1)
+		mu.Lock()
+		if n > peak {
+			peak = n
+		}
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+
+		if r.Header.Get("User-Agent") == "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		var z, x, y int
+		fmt.Sscanf(r.URL.Path, "/%d/%d/%d.png", &z, &x, &y)
+		w.Write(solidTile(x, y))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests, &peak
+}
+
+// Ensures that tiles are fetched concurrently up to the limit, and cached.
+func TestTileFetcherFetchAll(t *testing.T) {
+	server, requests, peak := newTileServer(t)
+
+	cache, err := OpenTileCache(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := NewTileFetcher(server.URL+"/{z}/{x}/{y}.png", cache)
+	f.MaxConcurrent = 2
+
+	tiles := TilesCovering(NewBounds(NewPoint(-60, -170), NewPoint(60, 170)), 3)
+	data, err := f.FetchAll(tiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(data) != len(tiles) || !bytes.Equal(data[0], solidTile(tiles[0].X, tiles[0].Y)) {
+		t.Errorf("Expected the image of every tile, got %d", len(data))
+	}
+
+	if *peak > 2 {
+		t.Errorf("Expected at most 2 downloads at once, got %d", *peak)
+	}
+
+	if _, err := f.FetchAll(tiles); err != nil || int(*requests) != len(tiles) {
+		t.Errorf("Expected the second fetch to be served from the cache, got %d requests (%v)", *requests, err)
+	}
+}
+
+// Ensures that stitched images are cropped to the bounds and composed of the right tiles.
+func TestTileFetcherStitch(t *testing.T) {
+	server, _, _ := newTileServer(t)
+	f := NewTileFetcher(server.URL+"/{z}/{x}/{y}.png", nil)
+
+	// Two tiles wide and one tall at zoom 1: the whole northern hemisphere.
+	img, err := f.Stitch(NewBounds(NewPoint(0, -180), NewPoint(MAX_MERCATOR_LAT, 180)), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if img.Bounds().Dx() != 2*TILE_SIZE || img.Bounds().Dy() != TILE_SIZE {
+		t.Fatalf("Expected a %dx%d image, got %v", 2*TILE_SIZE, TILE_SIZE, img.Bounds())
+	}
+
+	if got := img.RGBAAt(TILE_SIZE+10, 10); got.R != 40 || got.G != 0 {
+		t.Errorf("Expected the right half to come from tile 1/1/0, got %v", got)
+	}
+
+	// Crossing the antimeridian puts the easternmost column on the left.
+	img, err = f.Stitch(NewBounds(NewPoint(0, 90), NewPoint(MAX_MERCATOR_LAT, -90)), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if img.Bounds().Dx() != 2*TILE_SIZE || img.RGBAAt(10, 10).R != 120 || img.RGBAAt(TILE_SIZE+10, 10).R != 0 {
+		t.Errorf("Expected tiles 3 and 0 side by side, got %v wide with %v and %v", img.Bounds().Dx(), img.RGBAAt(10, 10), img.RGBAAt(TILE_SIZE+10, 10))
+	}
+
+	if _, err := f.Stitch(NewBounds(NewPoint(-80, -180), NewPoint(80, 180)), 10); err == nil {
+		t.Error("Expected an error for an image too large to stitch")
+	}
+}
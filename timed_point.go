@@ -0,0 +1,125 @@
+package geo
+
+import (
+	"math"
+	"time"
+)
+
+// A TimedPoint is a fix: where something, such as a vehicle or a phone, was at a moment in time.
+type TimedPoint struct {
+	Point *Point
+	Time  time.Time
+}
+
+// Creates and returns a pointer to a new TimedPoint at the passed in latitude and longitude and time.
+func NewTimedPoint(lat float64, lng float64, t time.Time) *TimedPoint {
+	return &TimedPoint{Point: NewPoint(lat, lng), Time: t}
+}
+
+// Returns the average speed, in kilometers per hour, at which the passed in fix was reached from this one
+// along the great circle between them.  Returns 0 if the fixes were taken at the same time,
+// and a negative speed if the passed in fix was taken first.
+func (t *TimedPoint) SpeedTo(next *TimedPoint) float64 {
+	hours := next.Time.Sub(t.Time).Hours()
+	if hours == 0 {
+		return 0
+	}
+
+	return t.Point.GreatCircleDistance(next.Point) / hours
+}
+
+// Returns the initial compass bearing, in degrees from 0 to 360, from this fix to the passed in one.
+func (t *TimedPoint) HeadingTo(next *TimedPoint) float64 {
+	return math.Mod(t.Point.BearingTo(next.Point)+360, 360)
+}
+
+// Returns the speed, in kilometers per hour, of the passed in track at each of its fixes:
+// the average speed from the fix before it to the fix after it, which smooths out
+// the jitter of each fix.  The first and last fixes take the speed of their one leg.
+// Tracks of fewer than two fixes have no speeds.
+func InstantaneousSpeeds(track []*TimedPoint) []float64 {
+	if len(track) < 2 {
+		return nil
+	}
+
+	speeds := make([]float64, len(track))
+	for i := range track {
+		before, after := max(i-1, 0), min(i+1, len(track)-1)
+		hours := track[after].Time.Sub(track[before].Time).Hours()
+		if hours == 0 {
+			continue
+		}
+
+		distance := 0.0
+		for j := before; j < after; j++ {
+			distance += track[j].Point.GreatCircleDistance(track[j+1].Point)
+		}
+		speeds[i] = distance / hours
+	}
+
+	return speeds
+}
+
+// Returns the average speed, in kilometers per hour, of the passed in track from its first fix to its last:
+// the distance along it divided by the time it took.  Returns 0 for tracks of fewer than two fixes,
+// or whose fixes were all taken at the same time.
+func AverageSpeed(track []*TimedPoint) float64 {
+	if len(track) < 2 {
+		return 0
+	}
+
+	hours := track[len(track)-1].Time.Sub(track[0].Time).Hours()
+	if hours == 0 {
+		return 0
+	}
+
+	distance := 0.0
+	for i := 1; i < len(track); i++ {
+		distance += track[i-1].Point.GreatCircleDistance(track[i].Point)
+	}
+
+	return distance / hours
+}
+
+// Returns how far, in degrees, the heading turns at the passed in fix b, between the leg from a to it
+// and the leg from it to c: positive turning clockwise, negative counterclockwise, from -180 to 180.
+func HeadingChange(a, b, c *TimedPoint) float64 {
+	turn := math.Mod(b.HeadingTo(c)-a.HeadingTo(b)+540, 360) - 180
+	if turn == -180 {
+		return 180
+	}
+
+	return turn
+}
+
+// Returns the heading change at each fix of the passed in track between its first and last,
+// as HeadingChange does, skipping fixes that haven't moved since the one before, whose heading is unknown.
+func HeadingChanges(track []*TimedPoint) []float64 {
+	var moving []*TimedPoint
+	for _, fix := range track {
+		if len(moving) == 0 || moving[len(moving)-1].Point.GreatCircleDistance(fix.Point) > 0 {
+			moving = append(moving, fix)
+		}
+	}
+
+	var changes []float64
+	for i := 2; i < len(moving); i++ {
+		changes = append(changes, HeadingChange(moving[i-2], moving[i-1], moving[i]))
+	}
+
+	return changes
+}
+
+// Returns the acceleration, in meters per second squared, between the leg from a to b and the leg
+// from b to c: the change in their average speeds over the time between their midpoints.
+// Returns 0 if the legs' midpoints are at the same time.
+func Acceleration(a, b, c *TimedPoint) float64 {
+	seconds := c.Time.Sub(a.Time).Seconds() / 2
+	if seconds == 0 {
+		return 0
+	}
+
+	// Convert from kilometers per hour to meters per second.
+	change := (b.SpeedTo(c) - a.SpeedTo(b)) / 3.6
+	return change / seconds
+}
@@ -0,0 +1,82 @@
+package geo
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// Ensures that speeds are measured along a track of fixes.
+func TestTimedPointSpeeds(t *testing.T) {
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	track := []*TimedPoint{
+		NewTimedPoint(0, 0, start),
+		NewTimedPoint(0, 1, start.Add(time.Hour)),
+		NewTimedPoint(0, 3, start.Add(2*time.Hour)),
+	}
+	degree := NewPoint(0, 0).GreatCircleDistance(NewPoint(0, 1))
+
+	if s := track[0].SpeedTo(track[1]); math.Abs(s-degree) > 1e-9 {
+		t.Errorf("Expected %f km/h, got %f", degree, s)
+	}
+
+	if s := track[1].SpeedTo(track[0]); math.Abs(s+degree) > 1e-9 {
+		t.Errorf("Expected a negative speed backwards in time, got %f", s)
+	}
+
+	if s := track[0].SpeedTo(NewTimedPoint(0, 1, start)); s != 0 {
+		t.Errorf("Expected no speed between simultaneous fixes, got %f", s)
+	}
+
+	speeds := InstantaneousSpeeds(track)
+	expected := []float64{degree, 1.5 * degree, 2 * degree}
+	for i := range expected {
+		if math.Abs(speeds[i]-expected[i]) > 1e-9 {
+			t.Errorf("Expected speeds %v, got %v", expected, speeds)
+			break
+		}
+	}
+
+	if s := AverageSpeed(track); math.Abs(s-1.5*degree) > 1e-9 {
+		t.Errorf("Expected an average of %f km/h, got %f", 1.5*degree, s)
+	}
+
+	if InstantaneousSpeeds(track[:1]) != nil || AverageSpeed(track[:1]) != 0 {
+		t.Error("Expected a single fix to have no speed")
+	}
+
+	// From 111 km/h to 222 km/h, over the hour between the legs' midpoints.
+	if a := Acceleration(track[0], track[1], track[2]); math.Abs(a-degree/3.6/3600) > 1e-9 {
+		t.Errorf("Expected an acceleration of %f m/s², got %f", degree/3.6/3600, a)
+	}
+}
+
+// Ensures that heading changes are signed by the direction of the turn.
+func TestTimedPointHeadingChanges(t *testing.T) {
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	at := func(lat, lng float64, minutes int) *TimedPoint {
+		return NewTimedPoint(lat, lng, start.Add(time.Duration(minutes)*time.Minute))
+	}
+
+	if h := at(0, 0, 0).HeadingTo(at(0, -1, 1)); math.Abs(h-270) > 1e-9 {
+		t.Errorf("Expected a heading of 270 to the west, got %f", h)
+	}
+
+	if turn := HeadingChange(at(0, 0, 0), at(0, 1, 1), at(1, 1, 2)); math.Abs(turn+90) > 1e-9 {
+		t.Errorf("Expected a left turn of -90, got %f", turn)
+	}
+
+	if turn := HeadingChange(at(0, 0, 0), at(0, 1, 1), at(-1, 1, 2)); math.Abs(turn-90) > 1e-9 {
+		t.Errorf("Expected a right turn of 90, got %f", turn)
+	}
+
+	if turn := HeadingChange(at(0, 0, 0), at(0, 1, 1), at(0, 0, 2)); turn != 180 {
+		t.Errorf("Expected a u-turn of 180, got %f", turn)
+	}
+
+	// The stop at the corner has no heading, so is skipped.
+	changes := HeadingChanges([]*TimedPoint{at(0, 0, 0), at(0, 1, 1), at(0, 1, 2), at(1, 1, 3), at(2, 1, 4)})
+	if len(changes) != 2 || math.Abs(changes[0]+90) > 1e-9 || math.Abs(changes[1]) > 1e-9 {
+		t.Errorf("Expected a left turn then straight on, got %v", changes)
+	}
+}
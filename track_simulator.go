@@ -0,0 +1,161 @@
+package geo
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// The defaults of a new TrackSimulator.
+const (
+	// The speed, in kilometers per hour, a simulated vehicle cruises at.
+	DEFAULT_SIMULATED_SPEED = 50.0
+
+	// The rate, in meters per second squared, at which a simulated vehicle speeds up and slows down.
+	DEFAULT_SIMULATED_ACCELERATION = 2.0
+
+	// The time between simulated fixes.
+	DEFAULT_SIMULATED_INTERVAL = time.Second
+
+	// The typical error, in meters, of a simulated fix, about that of a phone's GPS under an open sky.
+	DEFAULT_SIMULATED_NOISE = 5.0
+)
+
+// The slowest a simulated vehicle takes a bend, or drives a leg, as a fraction of its cruising speed.
+const simulatedMinBendSpeed = 0.1
+
+// A TrackSimulator generates synthetic, but realistic, GPS tracks of a vehicle driving along a route,
+// for testing geofencing and tracking end to end: it sets off from a standstill, cruises at a speed that
+// wanders from leg to leg, slows for bends in proportion to how sharp they are, and comes to a stop at the end,
+// while its fixes are scattered by noise and lost in dropouts, as a real receiver's are.
+// Set its fields after creating it to change the defaults.  A TrackSimulator is not safe for use
+// by multiple goroutines.
+type TrackSimulator struct {
+	// The speed, in kilometers per hour, cruised at.
+	Speed float64
+
+	// The fraction by which the cruising speed of each leg of the route may differ from Speed, e.g. 0.1 for
+	// legs driven at anywhere between 90% and 110% of it.
+	SpeedJitter float64
+
+	// The rate, in meters per second squared, of speeding up and slowing down.
+	Acceleration float64
+
+	// The time between fixes.
+	Interval time.Duration
+
+	// The standard deviation, in meters, of the error of each fix in each direction, or 0 for exact fixes.
+	Noise float64
+
+	// The chance that any fix starts a dropout, during which no fixes are reported, as in tunnels and urban canyons,
+	// and the average length of dropouts, which vary at random.
+	DropoutRate     float64
+	DropoutDuration time.Duration
+
+	rand *rand.Rand
+}
+
+// Creates and returns a pointer to a new TrackSimulator with the default speed, acceleration, interval and noise,
+// and no jitter or dropouts, drawing from a source seeded with the passed in seed, so that simulations seeded alike
+// generate the same tracks.
+func NewTrackSimulator(seed int64) *TrackSimulator {
+	return &TrackSimulator{
+		Speed:        DEFAULT_SIMULATED_SPEED,
+		Acceleration: DEFAULT_SIMULATED_ACCELERATION,
+		Interval:     DEFAULT_SIMULATED_INTERVAL,
+		Noise:        DEFAULT_SIMULATED_NOISE,
+		rand:         rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Returns the fixes of a vehicle driving the passed in route, starting at the passed in time, one every Interval
+// until it stops at the end of the route, less those lost in dropouts.  Edges of the route are driven along
+// great circles.  Returns nil for routes of fewer than two points.
+func (s *TrackSimulator) Simulate(route Line, start time.Time) []*TimedPoint {
+	if len(route) < 2 || s.Speed <= 0 || s.Acceleration <= 0 || s.Interval <= 0 {
+		return nil
+	}
+
+	// The distance, in meters, from the start of the route to each of its points, and the fastest, in meters
+	// per second, each of them may be passed at: the end at a standstill, and bends more slowly the sharper they are.
+	n := len(route)
+	along, limits, cruise := make([]float64, n), make([]float64, n), make([]float64, n-1)
+	for i := 1; i < n; i++ {
+		along[i] = along[i-1] + route[i-1].GreatCircleDistance(route[i])*1000
+		cruise[i-1] = s.Speed / 3.6 * math.Max(1+s.SpeedJitter*(2*s.rand.Float64()-1), simulatedMinBendSpeed)
+	}
+	for i := 1; i < n-1; i++ {
+		turn := math.Abs(math.Mod(route[i].BearingTo(route[i+1])-route[i-1].BearingTo(route[i])+540, 360) - 180)
+		limits[i] = math.Min(cruise[i-1], cruise[i]) * math.Max(simulatedMinBendSpeed, 1-turn/180)
+	}
+
+	var track []*TimedPoint
+	dt := s.Interval.Seconds()
+	dropout := 0.0
+	leg, position, speed := 0, 0.0, 0.0
+	for step := 0; ; step++ {
+		if dropout > 0 {
+			dropout -= dt
+		} else if s.DropoutRate > 0 && s.rand.Float64() < s.DropoutRate {
+			dropout = s.rand.ExpFloat64() * s.DropoutDuration.Seconds()
+		} else {
+			track = append(track, &TimedPoint{
+				Point: s.scatter(s.pointAlong(route, along, leg, position)),
+				Time:  start.Add(time.Duration(step) * s.Interval),
+			})
+		}
+
+		if position >= along[n-1] {
+			return track
+		}
+
+		// Speed up towards the leg's cruising speed, unless a slower bend, or the end, is too near to slow for
+		// after this step, braking no harder than Acceleration.  The fastest the step may end at, and still
+		// leave room to slow to a point's limit, solves next²/2a + (speed+next)/2·dt = ahead less limit²/2a.
+		next := math.Min(speed+s.Acceleration*dt, cruise[leg])
+		fastest := s.Speed / 3.6 * (1 + s.SpeedJitter)
+		for i := leg + 1; i < n; i++ {
+			ahead := along[i] - position
+			if ahead > fastest*fastest/(2*s.Acceleration)+fastest*dt {
+				break
+			}
+
+			brake := speed - s.Acceleration*dt
+			c := speed*dt/2 - limits[i]*limits[i]/(2*s.Acceleration) - ahead
+			if discriminant := dt*dt/4 - 2*c/s.Acceleration; discriminant >= 0 {
+				brake = math.Max(brake, s.Acceleration*(math.Sqrt(discriminant)-dt/2))
+			}
+			next = math.Min(next, math.Max(brake, 0))
+		}
+
+		// Braking to the end only approaches it, ever more closely; a centimeter short is there.
+		position, speed = math.Min(position+(speed+next)/2*dt, along[n-1]), next
+		if along[n-1]-position < 0.01 {
+			position = along[n-1]
+		}
+		for leg < n-2 && position >= along[leg+1] {
+			leg++
+		}
+	}
+}
+
+// Returns the point the passed in distance, in meters, from the start of the route, along the passed in leg.
+func (s *TrackSimulator) pointAlong(route Line, along []float64, leg int, position float64) *Point {
+	length := along[leg+1] - along[leg]
+	if length == 0 {
+		return route[leg]
+	}
+
+	return intermediatePoint(route[leg], route[leg+1], math.Min((position-along[leg])/length, 1))
+}
+
+// Returns the passed in point moved by a random error of Noise meters' standard deviation in each direction.
+func (s *TrackSimulator) scatter(p *Point) *Point {
+	if s.Noise == 0 {
+		return NewPoint(p.lat, p.lng)
+	}
+
+	north, east := s.rand.NormFloat64()*s.Noise, s.rand.NormFloat64()*s.Noise
+	q := p.PointAtDistanceAndBearing(math.Hypot(north, east)/1000, math.Atan2(east, north)*180/math.Pi)
+	return NewPoint(q.lat, NormalizeLng(q.lng))
+}
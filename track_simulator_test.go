@@ -0,0 +1,126 @@
+package geo
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// Ensures that an exact track sets off from a standstill, keeps to the route and its speed, and stops at the end.
+func TestTrackSimulatorSimulate(t *testing.T) {
+	s := NewTrackSimulator(1)
+	s.Noise = 0
+
+	// About 2.2km east, then 1.1km north around a right angle.
+	route := lineOf(0, 0, 0, 0.02, 0.01, 0.02)
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	track := s.Simulate(route, start)
+	if len(track) < 2 {
+		t.Fatalf("Expected a track, got %v", track)
+	}
+
+	if *track[0].Point != *route[0] || !track[0].Time.Equal(start) {
+		t.Errorf("Expected the track to start at the start of the route, got %v", track[0])
+	}
+	if last := track[len(track)-1]; last.Point.GreatCircleDistance(route[2]) > 1e-6 {
+		t.Errorf("Expected the track to end at the end of the route, got %v", last.Point)
+	}
+
+	cruise := 0.0
+	for i, fix := range track {
+		if i > 0 {
+			if fix.Time.Sub(track[i-1].Time) != time.Second {
+				t.Fatalf("Expected a fix every second, got %v after %v", fix.Time, track[i-1].Time)
+			}
+			cruise = math.Max(cruise, track[i-1].SpeedTo(fix))
+		}
+		if d := geometryDistance(newRelateGeometry(fix.Point), newRelateGeometry(route)) * 1000; d > 0.01 {
+			t.Errorf("Expected fix %d to lie on the route, got %v, %fm off it", i, fix.Point, d)
+		}
+	}
+
+	if cruise > DEFAULT_SIMULATED_SPEED+1e-6 || cruise < DEFAULT_SIMULATED_SPEED-1 {
+		t.Errorf("Expected to cruise at %fkm/h, got %f", DEFAULT_SIMULATED_SPEED, cruise)
+	}
+
+	// Speeding up, slowing down and taking the bend, the trip takes a little longer than cruising all the way.
+	length := (route[0].GreatCircleDistance(route[1]) + route[1].GreatCircleDistance(route[2])) * 1000
+	expected := length / (DEFAULT_SIMULATED_SPEED / 3.6)
+	if d := track[len(track)-1].Time.Sub(start).Seconds(); d < expected+5 || d > expected+20 {
+		t.Errorf("Expected the trip to take a little over %.0f seconds, got %.0f", expected, d)
+	}
+
+	// The vehicle slows for the bend.
+	for i := 1; i < len(track); i++ {
+		if track[i].Point.lng >= 0.02 {
+			if speed := track[i-1].SpeedTo(track[i]); speed > DEFAULT_SIMULATED_SPEED*0.6 {
+				t.Errorf("Expected the vehicle to slow for the bend, got %fkm/h", speed)
+			}
+			break
+		}
+	}
+
+	if track := s.Simulate(route[:1], start); track != nil {
+		t.Errorf("Expected no track along a single point, got %v", track)
+	}
+}
+
+// Ensures that noise scatters fixes by about the standard deviation, and that dropouts leave gaps.
+func TestTrackSimulatorNoiseAndDropouts(t *testing.T) {
+	route := lineOf(0, 0, 0, 0.5)
+	exact := NewTrackSimulator(7)
+	exact.Noise = 0
+
+	noisy := NewTrackSimulator(7)
+	noisy.Noise = 10
+
+	// The same seed drives the same speeds, so the fixes pair up.
+	truth, fixes := exact.Simulate(route, time.Time{}), noisy.Simulate(route, time.Time{})
+	if len(truth) != len(fixes) {
+		t.Fatalf("Expected %d fixes, got %d", len(truth), len(fixes))
+	}
+
+	sumSquares := 0.0
+	for i := range fixes {
+		d := truth[i].Point.GreatCircleDistance(fixes[i].Point) * 1000
+		sumSquares += d * d
+	}
+	if rms := math.Sqrt(sumSquares / float64(len(fixes))); math.Abs(rms-10*math.Sqrt2) > 1 {
+		t.Errorf("Expected fixes about %fm off the route, got %fm", 10*math.Sqrt2, rms)
+	}
+
+	lossy := NewTrackSimulator(7)
+	lossy.Noise = 0
+	lossy.DropoutRate = 0.02
+	lossy.DropoutDuration = 20 * time.Second
+	lost := lossy.Simulate(route, time.Time{})
+
+	gaps := 0
+	for i := 1; i < len(lost); i++ {
+		if lost[i].Time.Sub(lost[i-1].Time) > time.Second {
+			gaps++
+		}
+	}
+	if gaps == 0 || len(lost) >= len(truth)*9/10 {
+		t.Errorf("Expected dropouts to lose fixes, got %d of %d fixes in %d gaps", len(lost), len(truth), gaps)
+	}
+}
+
+// Ensures that simulated tracks drive a GeofenceEngine as real ones would.
+func TestTrackSimulatorGeofence(t *testing.T) {
+	engine := NewGeofenceEngine(&Geofence{ID: "depot", Polygon: boxPolygon(-0.001, 0.009, 0.001, 0.011)})
+	s := NewTrackSimulator(3)
+
+	var events []GeofenceEvent
+	for _, fix := range s.Simulate(lineOf(0, 0, 0, 0.02), time.Time{}) {
+		e, err := engine.UpdateFix("truck", fix)
+		if err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, e...)
+	}
+
+	if len(events) < 2 || events[0].Type != GeofenceEnter || events[len(events)-1].Type != GeofenceExit {
+		t.Errorf("Expected the truck to enter and leave the depot, got %v", events)
+	}
+}
@@ -0,0 +1,189 @@
+package geo
+
+import (
+	"golang.org/x/text/unicode/norm"
+	"strings"
+	"unicode"
+)
+
+// The distance, in kilometers, within which a result of a romanized lookup
+// is taken to be the same place as a result of the original.
+const romanizedMatchDistance = 0.05
+
+// The romanizations of Cyrillic letters, following the BGN/PCGN system for Russian,
+// with the letters Ukrainian adds.
+var cyrillicLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh", 'з': "z", 'и': "i",
+	'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t",
+	'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "",
+	'э': "e", 'ю': "yu", 'я': "ya", 'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g",
+}
+
+// The romanizations of Arabic letters and vowel marks.  Arabic is usually written without its short vowels,
+// so romanized words lack them too: "شارع" becomes "shar'" rather than "shari'".
+var arabicLatin = map[rune]string{
+	'ا': "a", 'أ': "a", 'إ': "i", 'آ': "a", 'ب': "b", 'ت': "t", 'ث': "th", 'ج': "j", 'ح': "h", 'خ': "kh",
+	'د': "d", 'ذ': "dh", 'ر': "r", 'ز': "z", 'س': "s", 'ش': "sh", 'ص': "s", 'ض': "d", 'ط': "t", 'ظ': "z",
+	'ع': "'", 'غ': "gh", 'ف': "f", 'ق': "q", 'ك': "k", 'ل': "l", 'م': "m", 'ن': "n", 'ه': "h", 'و': "w",
+	'ي': "y", 'ى': "a", 'ة': "a", 'ء': "'", 'ئ': "'", 'ؤ': "'", 'پ': "p", 'چ': "ch", 'ژ': "zh", 'گ': "g",
+	'ک': "k", 'ی': "y", 'َ': "a", 'ِ': "i", 'ُ': "u", 'ً': "", 'ٍ': "", 'ٌ': "", 'ْ': "", 'ّ': "",
+	'،': ",", '؛': ";", '؟': "?",
+}
+
+// The Hepburn romanizations of hiragana; katakana are romanized as the matching hiragana.
+var kanaLatin = map[rune]string{
+	'あ': "a", 'い': "i", 'う': "u", 'え': "e", 'お': "o",
+	'か': "ka", 'き': "ki", 'く': "ku", 'け': "ke", 'こ': "ko", 'が': "ga", 'ぎ': "gi", 'ぐ': "gu", 'げ': "ge", 'ご': "go",
+	'さ': "sa", 'し': "shi", 'す': "su", 'せ': "se", 'そ': "so", 'ざ': "za", 'じ': "ji", 'ず': "zu", 'ぜ': "ze", 'ぞ': "zo",
+	'た': "ta", 'ち': "chi", 'つ': "tsu", 'て': "te", 'と': "to", 'だ': "da", 'ぢ': "ji", 'づ': "zu", 'で': "de", 'ど': "do",
+	'な': "na", 'に': "ni", 'ぬ': "nu", 'ね': "ne", 'の': "no",
+	'は': "ha", 'ひ': "hi", 'ふ': "fu", 'へ': "he", 'ほ': "ho", 'ば': "ba", 'び': "bi", 'ぶ': "bu", 'べ': "be", 'ぼ': "bo",
+	'ぱ': "pa", 'ぴ': "pi", 'ぷ': "pu", 'ぺ': "pe", 'ぽ': "po",
+	'ま': "ma", 'み': "mi", 'む': "mu", 'め': "me", 'も': "mo", 'や': "ya", 'ゆ': "yu", 'よ': "yo",
+	'ら': "ra", 'り': "ri", 'る': "ru", 'れ': "re", 'ろ': "ro", 'わ': "wa", 'ゐ': "i", 'ゑ': "e", 'を': "o", 'ん': "n",
+	'ゔ': "vu", 'ぁ': "a", 'ぃ': "i", 'ぅ': "u", 'ぇ': "e", 'ぉ': "o",
+}
+
+// Japanese punctuation, and its Latin equivalents.
+var japanesePunctuation = strings.NewReplacer("、", ", ", "。", ". ", "・", " ", "「", "\"", "」", "\"", "〒", "")
+
+// Returns whether or not every letter of the passed in string is a Latin letter.
+func isLatin(s string) bool {
+	for _, r := range s {
+		if unicode.IsLetter(r) && !unicode.Is(unicode.Latin, r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Returns the passed in string with its Cyrillic, Arabic and Japanese kana romanized, e.g. "Москва" becomes
+// "Moskva" and "とうきょう" becomes "toukyou", and full width digits and letters narrowed.
+// Transliteration works letter by letter, without a dictionary, so it can't romanize kanji or
+// Chinese characters, which are left as they are, and romanizes words as they are written rather than
+// as they are said: it is a fallback for when a provider can't return an address in Latin letters.
+func Transliterate(s string) string {
+	s = japanesePunctuation.Replace(norm.NFKC.String(s))
+
+	var b strings.Builder
+	runes := []rune(s)
+	double := false
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		if latin, ok := cyrillicLatin[unicode.ToLower(r)]; ok {
+			if unicode.IsUpper(r) && latin != "" {
+				latin = strings.ToUpper(latin[:1]) + latin[1:]
+			}
+			b.WriteString(latin)
+			continue
+		}
+
+		if latin, ok := arabicLatin[r]; ok {
+			b.WriteString(latin)
+			continue
+		}
+
+		if r >= '٠' && r <= '٩' {
+			b.WriteRune('0' + r - '٠')
+			continue
+		}
+
+		// Katakana sit a fixed distance after the matching hiragana.
+		if r >= 'ァ' && r <= 'ヶ' {
+			r -= 'ァ' - 'ぁ'
+		}
+
+		// A small tsu doubles the consonant after it; a long vowel mark is dropped.
+		if r == 'っ' {
+			double = true
+			continue
+		}
+		if r == 'ー' {
+			continue
+		}
+
+		latin, ok := kanaLatin[r]
+		if !ok {
+			b.WriteRune(runes[i])
+			double = false
+			continue
+		}
+
+		// A small ya, yu or yo, or a small vowel, joins the kana before it: "きゃ" is "kya", "ファ" is "fa".
+		if i+1 < len(runes) {
+			next := runes[i+1]
+			if next >= 'ァ' && next <= 'ヶ' {
+				next -= 'ァ' - 'ぁ'
+			}
+
+			switch {
+			case (next == 'ゃ' || next == 'ゅ' || next == 'ょ') && strings.HasSuffix(latin, "i") && len(latin) > 1:
+				vowel := kanaLatin[next+1][1:]
+				stem := latin[:len(latin)-1]
+				if stem == "sh" || stem == "ch" || stem == "j" {
+					latin = stem + vowel
+				} else {
+					latin = stem + "y" + vowel
+				}
+				i++
+			case next == 'ぁ' || next == 'ぃ' || next == 'ぅ' || next == 'ぇ' || next == 'ぉ':
+				if len(latin) > 1 {
+					latin = latin[:len(latin)-1] + kanaLatin[next]
+					i++
+				}
+			}
+		}
+
+		if double {
+			if latin[0] == 'c' {
+				b.WriteByte('t')
+			} else if !strings.ContainsRune("aiueon", rune(latin[0])) {
+				b.WriteByte(latin[0])
+			}
+			double = false
+		}
+		b.WriteString(latin)
+	}
+
+	return b.String()
+}
+
+// Fills in the RomanizedAddress of each of the passed in results: its FormattedAddress if that is
+// already in Latin letters, otherwise the Latin address of the same place among the results of
+// the passed in lookup, such as the same query asked for in English, or else its FormattedAddress
+// transliterated.  The lookup, which may be nil, is only made if some address needs romanizing.
+func romanizeResults(results []*GeocodeResult, lookup func() ([]*GeocodeResult, error)) {
+	var pending []*GeocodeResult
+	for _, r := range results {
+		if isLatin(r.FormattedAddress) {
+			r.RomanizedAddress = r.FormattedAddress
+		} else {
+			pending = append(pending, r)
+		}
+	}
+
+	if len(pending) == 0 {
+		return
+	}
+
+	var romanized []*GeocodeResult
+	if lookup != nil {
+		// A failed lookup leaves every address to be transliterated.
+		romanized, _ = lookup()
+	}
+
+	for _, r := range pending {
+		for _, candidate := range romanized {
+			if isLatin(candidate.FormattedAddress) && r.Point.GreatCircleDistance(candidate.Point) <= romanizedMatchDistance {
+				r.RomanizedAddress = candidate.FormattedAddress
+				break
+			}
+		}
+
+		if r.RomanizedAddress == "" {
+			r.RomanizedAddress = Transliterate(r.FormattedAddress)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package geo
+
+import (
+	"testing"
+)
+
+// Ensures that Cyrillic, Arabic and kana are romanized, and other scripts left alone.
+func TestTransliterate(t *testing.T) {
+	tests := map[string]string{
+		"Москва, улица Щорса":    "Moskva, ulitsa Shchorsa",
+		"Львів":                  "Lviv",
+		"شارع":                   "shar'",
+		"٢٠٢٤":                   "2024",
+		"とうきょう":                  "toukyou",
+		"ほっかいどう":                 "hokkaidou",
+		"マッチ":                    "matchi",
+		"しゃしん、きょうと":              "shashin, kyouto",
+		"ファミリーマート":               "famirimato",
+		"〒１００－０００５ 丸の内":          "100-0005 丸no内",
+		"Zürich":                 "Zürich",
+		"285 Bedford Avenue, NY": "285 Bedford Avenue, NY",
+	}
+
+	for in, expected := range tests {
+		if out := Transliterate(in); out != expected {
+			t.Errorf("Expected %q to become %q, got %q", in, expected, out)
+		}
+	}
+}
+
+// Ensures that results are romanized from a lookup where it has the same place, and transliterated otherwise.
+func TestRomanizeResults(t *testing.T) {
+	results := []*GeocodeResult{
+		{Point: NewPoint(55.7558, 37.6173), FormattedAddress: "Москва, Россия"},
+		{Point: NewPoint(59.9343, 30.3351), FormattedAddress: "Санкт-Петербург, Россия"},
+		{Point: NewPoint(51.5, -0.12), FormattedAddress: "London, UK"},
+	}
+
+	lookups := 0
+	romanizeResults(results, func() ([]*GeocodeResult, error) {
+		lookups++
+		return []*GeocodeResult{{Point: NewPoint(55.7558, 37.6173), FormattedAddress: "Moscow, Russia"}}, nil
+	})
+
+	if lookups != 1 {
+		t.Errorf("Expected a single lookup, got %d", lookups)
+	}
+
+	expected := []string{"Moscow, Russia", "Sankt-Peterburg, Rossiya", "London, UK"}
+	for i, r := range results {
+		if r.RomanizedAddress != expected[i] {
+			t.Errorf("Expected %q, got %q", expected[i], r.RomanizedAddress)
+		}
+	}
+
+	latin := []*GeocodeResult{{Point: NewPoint(0, 0), FormattedAddress: "Null Island"}}
+	romanizeResults(latin, func() ([]*GeocodeResult, error) {
+		t.Error("Expected no lookup for Latin addresses")
+		return nil, nil
+	})
+}
@@ -0,0 +1,104 @@
+package geo
+
+import (
+	"crypto/tls"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// The most idle connections kept open to each provider unless told otherwise.  http.DefaultTransport keeps 2,
+// so that batch geocoding with more workers than that reconnects for nearly every request.
+const DEFAULT_MAX_IDLE_CONNS_PER_HOST = 64
+
+// The most idle connections kept open across all providers unless told otherwise.
+const DEFAULT_MAX_IDLE_CONNS = 256
+
+// How long an idle connection is kept open unless told otherwise.
+const DEFAULT_IDLE_CONN_TIMEOUT = 90 * time.Second
+
+// A TransportConfig tunes the connection pool providers issue requests over.
+// Zero values take the defaults.
+type TransportConfig struct {
+	// The most idle connections kept open to each provider.  Defaults to DEFAULT_MAX_IDLE_CONNS_PER_HOST.
+	MaxIdleConnsPerHost int
+
+	// The most idle connections kept open across all providers.  Defaults to DEFAULT_MAX_IDLE_CONNS.
+	MaxIdleConns int
+
+	// If set, the most connections, idle or not, opened to each provider;
+	// further requests wait for one to be free.
+	MaxConnsPerHost int
+
+	// How long an idle connection is kept open.  Defaults to DEFAULT_IDLE_CONN_TIMEOUT.
+	IdleConnTimeout time.Duration
+
+	// Speaks HTTP/1.1 to every provider rather than HTTP/2 to those that support it.
+	// HTTP/2 multiplexes every request to a provider over a single connection.
+	DisableHTTP2 bool
+}
+
+// Returns a new http.Transport configured as the passed in TransportConfig says,
+// with the proxy, dialing and TLS settings of http.DefaultTransport.
+func NewTransport(c TransportConfig) *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+
+	t.MaxIdleConnsPerHost = c.MaxIdleConnsPerHost
+	if t.MaxIdleConnsPerHost <= 0 {
+		t.MaxIdleConnsPerHost = DEFAULT_MAX_IDLE_CONNS_PER_HOST
+	}
+
+	t.MaxIdleConns = c.MaxIdleConns
+	if t.MaxIdleConns <= 0 {
+		t.MaxIdleConns = DEFAULT_MAX_IDLE_CONNS
+	}
+
+	t.IdleConnTimeout = c.IdleConnTimeout
+	if t.IdleConnTimeout <= 0 {
+		t.IdleConnTimeout = DEFAULT_IDLE_CONN_TIMEOUT
+	}
+
+	t.MaxConnsPerHost = c.MaxConnsPerHost
+
+	if c.DisableHTTP2 {
+		t.ForceAttemptHTTP2 = false
+		t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+
+	return t
+}
+
+var (
+	sharedMu        sync.RWMutex
+	sharedTransport http.RoundTripper = NewTransport(TransportConfig{})
+	sharedClient                      = &http.Client{Transport: sharedTransport}
+)
+
+// Replaces the transport shared by every provider that isn't given a client of its own,
+// e.g. SetTransportConfig(geo.TransportConfig{MaxIdleConnsPerHost: 256}) for batch geocoding
+// with hundreds of workers.  Applications should call it once at startup, before issuing requests.
+func SetTransportConfig(c TransportConfig) {
+	t := NewTransport(c)
+
+	sharedMu.Lock()
+	defer sharedMu.Unlock()
+
+	sharedTransport = t
+	sharedClient = &http.Client{Transport: t}
+}
+
+// Returns the transport shared by every provider that isn't given a client of its own.
+func defaultTransport() http.RoundTripper {
+	sharedMu.RLock()
+	defer sharedMu.RUnlock()
+
+	return sharedTransport
+}
+
+// Returns the client used by every provider that isn't given one of its own.
+func defaultClient() *http.Client {
+	sharedMu.RLock()
+	defer sharedMu.RUnlock()
+
+	return sharedClient
+}
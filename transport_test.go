@@ -0,0 +1,63 @@
+package geo
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Ensures that transports are tuned as configured, with defaults for what isn't.
+func TestNewTransport(t *testing.T) {
+	transport := NewTransport(TransportConfig{MaxConnsPerHost: 8, DisableHTTP2: true})
+	if transport.MaxIdleConnsPerHost != DEFAULT_MAX_IDLE_CONNS_PER_HOST || transport.MaxIdleConns != DEFAULT_MAX_IDLE_CONNS {
+		t.Errorf("Expected the default pool sizes, got %d and %d", transport.MaxIdleConnsPerHost, transport.MaxIdleConns)
+	}
+
+	if transport.MaxConnsPerHost != 8 || transport.ForceAttemptHTTP2 || transport.TLSNextProto == nil {
+		t.Errorf("Expected 8 connections per host over HTTP/1.1, got %d (%v)", transport.MaxConnsPerHost, transport.ForceAttemptHTTP2)
+	}
+
+	if transport.Proxy == nil {
+		t.Error("Expected the environment's proxy to be respected")
+	}
+}
+
+// Ensures that providers without a client of their own share the configured transport.
+func TestSetTransportConfig(t *testing.T) {
+	defer SetTransportConfig(TransportConfig{})
+	SetTransportConfig(TransportConfig{MaxIdleConnsPerHost: 256})
+
+	if defaultClient().Transport.(*http.Transport).MaxIdleConnsPerHost != 256 || defaultTransport() != defaultClient().Transport {
+		t.Errorf("Expected the shared client to use the configured transport")
+	}
+}
+
+// Measures batch geocoding with many workers over the shared transport and over http.DefaultTransport,
+// which keeps only 2 idle connections per host and so reconnects for most requests.
+func BenchmarkBatchGeocode(b *testing.B) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"results":[{"geometry":{"location":{"lat":37.615223,"lng":-122.389979}}}],"status":"OK"}`))
+	}))
+	defer server.Close()
+
+	for _, transport := range []struct {
+		name   string
+		client *http.Client
+	}{
+		{"shared", nil},
+		{"default", &http.Client{Transport: http.DefaultTransport}},
+	} {
+		b.Run(transport.name, func(b *testing.B) {
+			g := NewGoogleGeocoder(WithBaseURL(server.URL), WithHTTPClient(transport.client))
+			b.SetParallelism(16)
+			b.RunParallel(func(pb *testing.PB) {
+				for i := 0; pb.Next(); i++ {
+					if _, err := g.Geocode(fmt.Sprint(i)); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		})
+	}
+}
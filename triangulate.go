@@ -0,0 +1,87 @@
+package geo
+
+// Returns the indexes of the current Polygon's points making up triangles that together cover it exactly,
+// wound counterclockwise seen from above, e.g. for drawing it with a GPU or picking random points in it.
+// They are found by clipping ears, with edges taken to be straight in longitude and latitude, as Contains
+// takes them; a polygon of n points has n-2 triangles, fewer if some of its points lie in a straight line.
+// Polygons crossing the antimeridian are handled, and either winding is accepted.  Self-intersecting
+// polygons get a best-effort triangulation.  Returns nil for polygons that are not closed.
+func (p *Polygon) Triangulate() [][3]int {
+	if !p.IsClosed() {
+		return nil
+	}
+
+	points := p.points
+	if crossesAntimeridian(points, true) {
+		points = unwrapLngs(points)
+	}
+
+	// Twice the signed area of a triangle, positive when it is wound counterclockwise.
+	turn := func(a, b, c int) float64 {
+		pa, pb, pc := points[a], points[b], points[c]
+		return (pb.lng-pa.lng)*(pc.lat-pa.lat) - (pb.lat-pa.lat)*(pc.lng-pa.lng)
+	}
+
+	remaining := make([]int, len(points))
+	for i := range points {
+		remaining[i] = i
+	}
+	if ringTurn(points) < 0 {
+		for i, j := 0, len(remaining)-1; i < j; i, j = i+1, j-1 {
+			remaining[i], remaining[j] = remaining[j], remaining[i]
+		}
+	}
+
+	var triangles [][3]int
+	for i, misses := 0, 0; len(remaining) > 3; {
+		n := len(remaining)
+		i %= n
+		prev, cur, next := remaining[(i+n-1)%n], remaining[i], remaining[(i+1)%n]
+
+		switch t := turn(prev, cur, next); {
+		case t == 0:
+			// A point in a straight line between its neighbours cuts off no triangle.
+		case misses >= n && t > 0, t > 0 && isEar(points, remaining, prev, cur, next):
+			// Self-intersecting rings may have no ears; any convex point lets the clipping go on.
+			triangles = append(triangles, [3]int{prev, cur, next})
+		case misses >= 2*n:
+			// Every point left is reflex, which only a self-intersecting ring can leave.
+			return triangles
+		default:
+			i++
+			misses++
+			continue
+		}
+
+		remaining = append(remaining[:i], remaining[i+1:]...)
+		misses = 0
+	}
+
+	if len(remaining) == 3 && turn(remaining[0], remaining[1], remaining[2]) > 0 {
+		triangles = append(triangles, [3]int{remaining[0], remaining[1], remaining[2]})
+	}
+
+	return triangles
+}
+
+// Returns whether or not the counterclockwise triangle of the passed in points, at indexes
+// prev, cur and next, is an ear of the remaining ring: whether no other point lies in it.
+func isEar(points []*Point, remaining []int, prev, cur, next int) bool {
+	a, b, c := points[prev], points[cur], points[next]
+	side := func(p, q, r *Point) float64 {
+		return (q.lng-p.lng)*(r.lat-p.lat) - (q.lat-p.lat)*(r.lng-p.lng)
+	}
+
+	for _, i := range remaining {
+		r := points[i]
+		if i == prev || i == cur || i == next || *r == *a || *r == *b || *r == *c {
+			continue
+		}
+
+		if side(a, b, r) >= 0 && side(b, c, r) >= 0 && side(c, a, r) >= 0 {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,87 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+// Returns a star of the passed in number of spikes around the passed in center, alternating between
+// points the passed in outer and inner radius, in degrees, away.
+func starPolygon(lat, lng float64, spikes int, outer, inner float64) *Polygon {
+	var points []*Point
+	for i := 0; i < 2*spikes; i++ {
+		r := outer
+		if i%2 == 1 {
+			r = inner
+		}
+		angle := float64(i) * math.Pi / float64(spikes)
+		points = append(points, NewPoint(lat+r*math.Sin(angle), lng+r*math.Cos(angle)))
+	}
+
+	return NewPolygon(points)
+}
+
+// Returns twice the signed area, in square degrees, of the passed in triangle of the passed in points,
+// with longitudes made continuous.
+func triangleTurn(points []*Point, t [3]int) float64 {
+	a, b, c := points[t[0]], points[t[1]], points[t[2]]
+	return LngDiff(a.lng, b.lng)*(c.lat-a.lat) - (b.lat-a.lat)*LngDiff(a.lng, c.lng)
+}
+
+// Ensures that polygons are cut into counterclockwise triangles covering exactly their area.
+func TestPolygonTriangulate(t *testing.T) {
+	cases := []struct {
+		name      string
+		polygon   *Polygon
+		area      float64
+		triangles int
+	}{
+		{"square", boxPolygon(0, 0, 2, 2), 4, 2},
+		{"clockwise square", NewPolygon([]*Point{NewPoint(0, 0), NewPoint(2, 0), NewPoint(2, 2), NewPoint(0, 2)}), 4, 2},
+		{"L", NewPolygon([]*Point{NewPoint(0, 0), NewPoint(0, 2), NewPoint(1, 2), NewPoint(1, 1), NewPoint(2, 1), NewPoint(2, 0)}), 3, 4},
+		{"star", starPolygon(0, 0, 5, 2, 1), 10 * math.Sin(math.Pi/5), 8},
+		{"straight edge", NewPolygon([]*Point{NewPoint(0, 0), NewPoint(0, 1), NewPoint(0, 2), NewPoint(2, 2), NewPoint(2, 0)}), 4, 3},
+		{"across the antimeridian", boxPolygon(0, 179, 2, -179), 4, 2},
+	}
+
+	for _, c := range cases {
+		triangles := c.polygon.Triangulate()
+		if len(triangles) != c.triangles {
+			t.Errorf("Expected %d triangles for the %s, got %v", c.triangles, c.name, triangles)
+		}
+
+		area := 0.0
+		for _, triangle := range triangles {
+			turn := triangleTurn(c.polygon.Points(), triangle)
+			if turn <= 0 {
+				t.Errorf("Expected the %s's triangle %v to be counterclockwise", c.name, triangle)
+			}
+			area += turn / 2
+		}
+
+		if math.Abs(area-c.area) > 1e-9 {
+			t.Errorf("Expected the %s's triangles to cover %f square degrees, got %f", c.name, c.area, area)
+		}
+	}
+
+	if triangles := NewPolygon([]*Point{NewPoint(0, 0), NewPoint(1, 1)}).Triangulate(); triangles != nil {
+		t.Errorf("Expected no triangles for an open polygon, got %v", triangles)
+	}
+}
+
+// Ensures that no triangle of a concave polygon reaches outside it, across its edges.
+func TestPolygonTriangulateConcave(t *testing.T) {
+	star := starPolygon(10, 20, 12, 3, 1)
+	points := star.Points()
+	for _, triangle := range star.Triangulate() {
+		for k := range triangle {
+			a, b := points[triangle[k]], points[triangle[(k+1)%3]]
+			for i, c := range points {
+				d := points[(i+1)%len(points)]
+				if segmentsIntersect(a.lng, a.lat, b.lng, b.lat, c.lng, c.lat, d.lng, d.lat) {
+					t.Errorf("Expected triangle %v not to cross the edge from point %d", triangle, i)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package geo
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+// The scale factor on the central meridian of every UTM zone, and the false
+// eastings and northings, in meters, that keep grid coordinates positive.
+const (
+	utmScale                 = 0.9996
+	utmFalseEasting          = 500000.0
+	utmSouthernFalseNorthing = 10000000.0
+)
+
+// The latitude bands of UTM and MGRS, each 8 degrees tall from 80S, except for X which is 12.
+const utmBands = "CDEFGHJKLMNPQRSTUVWX"
+
+// The letters of MGRS 100km squares: columns cycle through three sets of eight
+// from zone to zone, and rows through twenty, starting five later in even zones.
+const (
+	mgrsColumnLetters = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	mgrsRowLetters    = "ABCDEFGHJKLMNPQRSTUV"
+)
+
+// This is the error that consumers receive when UTM or MGRS coordinates lie outside their zone.
+var invalidGridReferenceError = errors.New("invalid UTM or MGRS grid reference")
+
+// The coefficients of Krüger's series for the transverse Mercator projection of
+// the WGS84 ellipsoid, to the fourth order of its third flattening n, along with
+// the radius A of the circle of the same circumference as its meridians.
+var utmA, utmAlpha, utmBeta, utmDelta = func() (float64, [4]float64, [4]float64, [4]float64) {
+	n := WGS84_FLATTENING / (2 - WGS84_FLATTENING)
+	n2, n3, n4 := n*n, n*n*n, n*n*n*n
+
+	a := WGS84_SEMI_MAJOR_AXIS * 1000 / (1 + n) * (1 + n2/4 + n4/64)
+	alpha := [4]float64{
+		n/2 - 2*n2/3 + 5*n3/16 + 41*n4/180,
+		13*n2/48 - 3*n3/5 + 557*n4/1440,
+		61*n3/240 - 103*n4/140,
+		49561 * n4 / 161280,
+	}
+	beta := [4]float64{
+		n/2 - 2*n2/3 + 37*n3/96 - n4/360,
+		n2/48 + n3/15 - 437*n4/1440,
+		17*n3/480 - 37*n4/840,
+		4397 * n4 / 161280,
+	}
+	delta := [4]float64{
+		2*n - 2*n2/3 - 2*n3 + 116*n4/45,
+		7*n2/3 - 8*n3/5 - 227*n4/45,
+		56*n3/15 - 136*n4/35,
+		4279 * n4 / 630,
+	}
+
+	return a, alpha, beta, delta
+}()
+
+// Returns the longitude of the central meridian of the passed in UTM zone.
+func utmCentralMeridian(zone int) float64 {
+	return float64(zone)*6 - 183
+}
+
+// Returns the UTM easting and northing, in meters, of the passed in coordinates
+// projected onto the passed in zone, which needn't be the zone they lie in.
+func latLngToUTM(lat float64, lng float64, zone int) (easting float64, northing float64) {
+	n := WGS84_FLATTENING / (2 - WGS84_FLATTENING)
+	c := 2 * math.Sqrt(n) / (1 + n)
+	phi := lat * math.Pi / 180
+	dLambda := (lng - utmCentralMeridian(zone)) * math.Pi / 180
+
+	t := math.Sinh(math.Atanh(math.Sin(phi)) - c*math.Atanh(c*math.Sin(phi)))
+	xi := math.Atan2(t, math.Cos(dLambda))
+	eta := math.Atanh(math.Sin(dLambda) / math.Sqrt(1+t*t))
+
+	x, y := eta, xi
+	for j, alpha := range utmAlpha {
+		k := float64(2 * (j + 1))
+		x += alpha * math.Cos(k*xi) * math.Sinh(k*eta)
+		y += alpha * math.Sin(k*xi) * math.Cosh(k*eta)
+	}
+
+	easting = utmFalseEasting + utmScale*utmA*x
+	northing = utmScale * utmA * y
+	if lat < 0 {
+		northing += utmSouthernFalseNorthing
+	}
+
+	return easting, northing
+}
+
+// Returns the coordinates of the passed in UTM easting and northing, in meters,
+// in the passed in zone and hemisphere.
+func utmToLatLng(zone int, north bool, easting float64, northing float64) (lat float64, lng float64) {
+	if !north {
+		northing -= utmSouthernFalseNorthing
+	}
+
+	xi := northing / (utmScale * utmA)
+	eta := (easting - utmFalseEasting) / (utmScale * utmA)
+
+	xiPrime, etaPrime := xi, eta
+	for j, beta := range utmBeta {
+		k := float64(2 * (j + 1))
+		xiPrime -= beta * math.Sin(k*xi) * math.Cosh(k*eta)
+		etaPrime -= beta * math.Cos(k*xi) * math.Sinh(k*eta)
+	}
+
+	chi := math.Asin(math.Sin(xiPrime) / math.Cosh(etaPrime))
+	phi := chi
+	for j, delta := range utmDelta {
+		phi += delta * math.Sin(float64(2*(j+1))*chi)
+	}
+
+	lng = utmCentralMeridian(zone) + math.Atan2(math.Sinh(etaPrime), math.Cos(xiPrime))*180/math.Pi
+	return phi * 180 / math.Pi, NormalizeLng(lng)
+}
+
+// Returns the southernmost latitude of the passed in band letter, and whether or not it is one.
+func utmBandLatitude(band byte) (float64, bool) {
+	i := strings.IndexByte(utmBands, band)
+	if i < 0 {
+		return 0, false
+	}
+
+	return float64(-80 + 8*i), true
+}
+
+// Returns the UTM easting and northing, in meters, of the south west corner of the passed in
+// MGRS 100km square, identified by its column and row letters, in the passed in zone and band.
+func mgrsSquareToUTM(zone int, band byte, column byte, row byte) (easting float64, northing float64, err error) {
+	set := (zone - 1) % 3
+	col := strings.IndexByte(mgrsColumnLetters[set*8:set*8+8], column)
+	r := strings.IndexByte(mgrsRowLetters, row)
+	bandLat, ok := utmBandLatitude(band)
+	if col < 0 || r < 0 || !ok {
+		return 0, 0, invalidGridReferenceError
+	}
+
+	if zone%2 == 0 {
+		r = (r + 15) % 20
+	}
+
+	easting = float64(col+1) * 100000
+	northing = float64(r) * 100000
+
+	// Row letters repeat every 2000km; the band says which repetition is meant.
+	_, bandNorthing := latLngToUTM(bandLat, utmCentralMeridian(zone), zone)
+	for northing+100000 < bandNorthing {
+		northing += 2000000
+	}
+
+	return easting, northing, nil
+}
@@ -0,0 +1,54 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+// Ensures that coordinates are projected onto their UTM zone and back.
+func TestUTMRoundTrip(t *testing.T) {
+	easting, northing := latLngToUTM(43+38.0/60+33.24/3600, -(79 + 23.0/60 + 13.7/3600), 17)
+	if math.Abs(easting-630084) > 1 || math.Abs(northing-4833438) > 1 {
+		t.Errorf("Expected the CN Tower at 630084, 4833438, got %f, %f", easting, northing)
+	}
+
+	for _, p := range []*Point{NewPoint(0, 3), NewPoint(43.6426, -79.3871), NewPoint(-33.8568, 151.2153), NewPoint(-79.9, -179.9), NewPoint(83.9, 177)} {
+		zone := int((p.lng+180)/6) + 1
+		easting, northing := latLngToUTM(p.lat, p.lng, zone)
+		lat, lng := utmToLatLng(zone, p.lat >= 0, easting, northing)
+		if math.Abs(lat-p.lat) > 1e-8 || math.Abs(lng-p.lng) > 1e-8 {
+			t.Errorf("Expected %v to round trip through zone %d, got %f, %f", p, zone, lat, lng)
+		}
+	}
+}
+
+// Ensures that MGRS 100km squares are placed in the right zone, band and repetition of rows.
+func TestMGRSSquareToUTM(t *testing.T) {
+	tests := []struct {
+		zone              int
+		band, column, row byte
+		easting, northing float64
+	}{
+		{17, 'T', 'P', 'J', 600000, 4800000},
+		{56, 'H', 'L', 'H', 300000, 6200000},
+		{18, 'S', 'U', 'J', 300000, 4300000},
+		{31, 'N', 'D', 'A', 400000, 0},
+	}
+
+	for _, test := range tests {
+		easting, northing, err := mgrsSquareToUTM(test.zone, test.band, test.column, test.row)
+		if err != nil {
+			t.Errorf("%d%c%c%c: %v", test.zone, test.band, test.column, test.row, err)
+			continue
+		}
+
+		if easting != test.easting || northing != test.northing {
+			t.Errorf("Expected %d%c%c%c at %f, %f, got %f, %f", test.zone, test.band, test.column, test.row,
+				test.easting, test.northing, easting, northing)
+		}
+	}
+
+	if _, _, err := mgrsSquareToUTM(17, 'T', 'A', 'J'); err == nil {
+		t.Error("Expected column A to be refused in zone 17")
+	}
+}
@@ -0,0 +1,93 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// This is the error that consumers can compare against with errors.Is
+// when no geocoding result lies where it was expected to.
+var ErrLocationMismatch = errors.New("geocode result is not where it was expected")
+
+// An Expectation describes where the result of a geocoding request should lie,
+// so that a request for "Paris" does not silently resolve to Paris, Texas.
+// Either field may be left unset to skip that check.
+type Expectation struct {
+	// The ISO 3166-1 alpha-2 code of the country the result must lie in, e.g. "FR".
+	// Results whose provider does not report a country never satisfy it.
+	CountryCode string
+
+	// The area the result must lie within.
+	Bounds *Bounds
+}
+
+// Describes a geocoding result that did not lie where it was expected to.
+// Matches ErrLocationMismatch when used with errors.Is.
+type LocationMismatchError struct {
+	Query    string
+	Result   *GeocodeResult
+	Expected Expectation
+	Reason   string
+}
+
+func (e *LocationMismatchError) Error() string {
+	return fmt.Sprintf("%q resolved to %q: %s", e.Query, e.Result.FormattedAddress, e.Reason)
+}
+
+// Allows errors.Is(err, ErrLocationMismatch) to succeed.
+func (e *LocationMismatchError) Is(target error) bool {
+	return target == ErrLocationMismatch
+}
+
+// Returns a reason the passed in result does not meet the Expectation,
+// or an empty string if it does.
+func (e Expectation) check(r *GeocodeResult) string {
+	if e.CountryCode != "" {
+		if r.CountryCode == "" {
+			return fmt.Sprintf("expected country %s, but the country is unknown", e.CountryCode)
+		}
+
+		if !strings.EqualFold(r.CountryCode, e.CountryCode) {
+			return fmt.Sprintf("expected country %s, got %s", e.CountryCode, r.CountryCode)
+		}
+	}
+
+	if e.Bounds != nil && (r.Point == nil || !e.Bounds.Contains(r.Point)) {
+		return "result lies outside the expected bounds"
+	}
+
+	return ""
+}
+
+// Returns a *LocationMismatchError if the passed in result for the passed in
+// query does not meet the Expectation, or nil if it does.
+func (e Expectation) Validate(query string, r *GeocodeResult) error {
+	if reason := e.check(r); reason != "" {
+		return &LocationMismatchError{Query: query, Result: r, Expected: e, Reason: reason}
+	}
+
+	return nil
+}
+
+// Geocodes the passed in query with the passed in geocoder, and returns the first
+// candidate that meets the passed in Expectation.  If none do, a *LocationMismatchError
+// describing the first candidate is returned.
+func GeocodeExpecting(g ResultGeocoder, query string, expected Expectation) (*GeocodeResult, error) {
+	results, err := g.GeocodeResults(query)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range results {
+		if expected.check(r) == "" {
+			return r, nil
+		}
+	}
+
+	if len(results) == 0 {
+		return nil, ErrLocationMismatch
+	}
+
+	return nil, expected.Validate(query, results[0])
+}
@@ -0,0 +1,60 @@
+package geo
+
+import (
+	"errors"
+	"testing"
+)
+
+// Ensures that results outside the expected country or bounds are reported as mismatches.
+func TestExpectationValidate(t *testing.T) {
+	parisTexas := &GeocodeResult{Point: NewPoint(33.6609, -95.5555), FormattedAddress: "Paris, TX, USA", CountryCode: "US"}
+	parisFrance := &GeocodeResult{Point: NewPoint(48.8566, 2.3522), FormattedAddress: "Paris, France", CountryCode: "FR"}
+	france := NewBounds(NewPoint(41.3, -5.2), NewPoint(51.1, 9.6))
+
+	if err := (Expectation{CountryCode: "fr"}).Validate("Paris", parisFrance); err != nil {
+		t.Errorf("Expected country codes to be compared case insensitively, got %v", err)
+	}
+
+	err := Expectation{CountryCode: "FR"}.Validate("Paris", parisTexas)
+	if !errors.Is(err, ErrLocationMismatch) {
+		t.Fatalf("Expected a location mismatch, got %v", err)
+	}
+
+	var mismatch *LocationMismatchError
+	if !errors.As(err, &mismatch) || mismatch.Result != parisTexas || mismatch.Query != "Paris" {
+		t.Errorf("Expected the mismatch to describe Paris, Texas, got %v", err)
+	}
+
+	if err := (Expectation{Bounds: france}).Validate("Paris", parisTexas); !errors.Is(err, ErrLocationMismatch) {
+		t.Errorf("Expected Paris, Texas to lie outside France, got %v", err)
+	}
+
+	unknown := &GeocodeResult{Point: NewPoint(48.8566, 2.3522), FormattedAddress: "Paris"}
+	if err := (Expectation{CountryCode: "FR"}).Validate("Paris", unknown); !errors.Is(err, ErrLocationMismatch) {
+		t.Errorf("Expected a result with no country to fail a country expectation, got %v", err)
+	}
+
+	if err := (Expectation{}).Validate("Paris", parisTexas); err != nil {
+		t.Errorf("Expected an empty Expectation to accept any result, got %v", err)
+	}
+}
+
+// Ensures that GeocodeExpecting skips candidates that don't meet the expectation.
+func TestGeocodeExpecting(t *testing.T) {
+	g := &stubResultGeocoder{results: mapquestFixtureResults(t, "test/data/mapquest_geocode_success.json")}
+
+	r, err := GeocodeExpecting(g, "San Francisco Airport", Expectation{CountryCode: "PE"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Point.Lat() != -12.533 {
+		t.Errorf("Expected the Peruvian airport, got %s", r.FormattedAddress)
+	}
+
+	_, err = GeocodeExpecting(g, "San Francisco Airport", Expectation{CountryCode: "FR"})
+	var mismatch *LocationMismatchError
+	if !errors.As(err, &mismatch) || mismatch.Result.CountryCode != "US" {
+		t.Errorf("Expected a mismatch describing the first candidate, got %v", err)
+	}
+}
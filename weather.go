@@ -0,0 +1,379 @@
+package geo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// This contains the default base URL for the OpenWeatherMap current weather API.
+const DEFAULT_OPENWEATHERMAP_URL = "https://api.openweathermap.org/data/2.5/weather"
+
+// This contains the default base URL for the Open-Meteo forecast API.
+const DEFAULT_OPEN_METEO_URL = "https://api.open-meteo.com/v1/forecast"
+
+// The geohash precision a CachedWeatherProvider shares conditions across unless told otherwise:
+// cells of about 1.2km by 0.6km.
+const DEFAULT_WEATHER_CACHE_PRECISION = 6
+
+// The current weather at a point.  Temperatures are in degrees Celsius,
+// speeds in meters per second, pressure in hectopascals and precipitation
+// in millimeters over the last hour.
+type WeatherConditions struct {
+	Point         *Point
+	Time          time.Time
+	Description   string
+	Temperature   float64
+	FeelsLike     float64
+	Humidity      float64
+	Pressure      float64
+	WindSpeed     float64
+	WindDirection float64
+	CloudCover    float64
+	Precipitation float64
+}
+
+// A WeatherProvider looks up the current weather at a point.
+type WeatherProvider interface {
+	Weather(p *Point) (*WeatherConditions, error)
+}
+
+// This struct contains all the functionality
+// of interacting with the OpenWeatherMap current weather API.
+type OpenWeatherMapProvider struct {
+	// If set, every request is counted against this Quota
+	// and refused once the "openweathermap" daily budget is spent.
+	Quota *Quota
+
+	// The client used to issue requests.  Defaults to a client sharing its connections
+	// with every provider, tuned with SetTransportConfig.
+	HTTPClient *http.Client
+
+	// The base URL of the API.  Defaults to DEFAULT_OPENWEATHERMAP_URL.
+	BaseURL string
+
+	apiKey   string
+	language string
+}
+
+// Creates and returns a pointer to a new OpenWeatherMapProvider configured by the passed in options.
+// OpenWeatherMap makes use of WithAPIKey, WithLanguage, WithHTTPClient, WithBaseURL and WithQuota.
+func NewOpenWeatherMapProvider(opts ...Option) *OpenWeatherMapProvider {
+	c := newGeocoderConfig(opts)
+	return &OpenWeatherMapProvider{
+		Quota:      c.quota,
+		HTTPClient: c.client("openweathermap"),
+		BaseURL:    c.baseURL,
+		apiKey:     c.apiKey,
+		language:   c.language,
+	}
+}
+
+// This struct contains selected fields from OpenWeatherMap's current weather response.
+type openWeatherMapResponse struct {
+	// Numeric on success, but a string in some error responses.
+	Cod     json.RawMessage `json:"cod"`
+	Message string          `json:"message"`
+	Dt      int64           `json:"dt"`
+	Weather []struct {
+		Description string `json:"description"`
+	} `json:"weather"`
+	Main struct {
+		Temp      float64 `json:"temp"`
+		FeelsLike float64 `json:"feels_like"`
+		Humidity  float64 `json:"humidity"`
+		Pressure  float64 `json:"pressure"`
+	} `json:"main"`
+	Wind struct {
+		Speed float64 `json:"speed"`
+		Deg   float64 `json:"deg"`
+	} `json:"wind"`
+	Clouds struct {
+		All float64 `json:"all"`
+	} `json:"clouds"`
+	Rain struct {
+		OneHour float64 `json:"1h"`
+	} `json:"rain"`
+	Snow struct {
+		OneHour float64 `json:"1h"`
+	} `json:"snow"`
+}
+
+// Returns the current weather at the passed in point, in metric units.
+// Implements the WeatherProvider Interface.
+func (o *OpenWeatherMapProvider) Weather(p *Point) (*WeatherConditions, error) {
+	if o.Quota != nil {
+		if err := o.Quota.Spend("openweathermap"); err != nil {
+			return nil, err
+		}
+	}
+
+	values := url.Values{
+		"lat":   {strconv.FormatFloat(p.lat, 'f', -1, 64)},
+		"lon":   {strconv.FormatFloat(p.lng, 'f', -1, 64)},
+		"units": {"metric"},
+		"appid": {o.apiKey},
+	}
+	if o.language != "" {
+		values.Set("lang", o.language)
+	}
+
+	base := o.BaseURL
+	if base == "" {
+		base = DEFAULT_OPENWEATHERMAP_URL
+	}
+
+	data, err := httpGet(o.HTTPClient, base+"?"+values.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	res := &openWeatherMapResponse{}
+	if err := json.Unmarshal(data, res); err != nil {
+		return nil, err
+	}
+
+	if cod := string(res.Cod); cod != "200" && cod != `"200"` {
+		return nil, fmt.Errorf("openweathermap: %s (%s)", res.Message, cod)
+	}
+
+	conditions := &WeatherConditions{
+		Point:         p,
+		Time:          time.Unix(res.Dt, 0).UTC(),
+		Temperature:   res.Main.Temp,
+		FeelsLike:     res.Main.FeelsLike,
+		Humidity:      res.Main.Humidity,
+		Pressure:      res.Main.Pressure,
+		WindSpeed:     res.Wind.Speed,
+		WindDirection: res.Wind.Deg,
+		CloudCover:    res.Clouds.All,
+		Precipitation: res.Rain.OneHour + res.Snow.OneHour,
+	}
+	if len(res.Weather) > 0 {
+		conditions.Description = res.Weather[0].Description
+	}
+
+	return conditions, nil
+}
+
+// This struct contains all the functionality
+// of interacting with the Open-Meteo forecast API, which needs no API key.
+type OpenMeteoProvider struct {
+	// If set, every request is counted against this Quota
+	// and refused once the "open-meteo" daily budget is spent.
+	Quota *Quota
+
+	// The client used to issue requests.  Defaults to a client sharing its connections
+	// with every provider, tuned with SetTransportConfig.
+	HTTPClient *http.Client
+
+	// The base URL of the API.  Defaults to DEFAULT_OPEN_METEO_URL.
+	BaseURL string
+
+	apiKey string
+}
+
+// Creates and returns a pointer to a new OpenMeteoProvider configured by the passed in options.
+// Open-Meteo makes use of WithHTTPClient, WithBaseURL, WithQuota, and WithAPIKey for its commercial plans.
+func NewOpenMeteoProvider(opts ...Option) *OpenMeteoProvider {
+	c := newGeocoderConfig(opts)
+	return &OpenMeteoProvider{
+		Quota:      c.quota,
+		HTTPClient: c.client("open-meteo"),
+		BaseURL:    c.baseURL,
+		apiKey:     c.apiKey,
+	}
+}
+
+// The current weather variables requested from Open-Meteo.
+const openMeteoCurrentVariables = "temperature_2m,apparent_temperature,relative_humidity_2m,surface_pressure," +
+	"wind_speed_10m,wind_direction_10m,cloud_cover,precipitation,weather_code"
+
+// This struct contains selected fields from Open-Meteo's forecast response.
+type openMeteoResponse struct {
+	Error   bool   `json:"error"`
+	Reason  string `json:"reason"`
+	Current struct {
+		Time                int64   `json:"time"`
+		Temperature         float64 `json:"temperature_2m"`
+		ApparentTemperature float64 `json:"apparent_temperature"`
+		RelativeHumidity    float64 `json:"relative_humidity_2m"`
+		SurfacePressure     float64 `json:"surface_pressure"`
+		WindSpeed           float64 `json:"wind_speed_10m"`
+		WindDirection       float64 `json:"wind_direction_10m"`
+		CloudCover          float64 `json:"cloud_cover"`
+		Precipitation       float64 `json:"precipitation"`
+		WeatherCode         int     `json:"weather_code"`
+	} `json:"current"`
+}
+
+// Descriptions of the WMO weather interpretation codes that Open-Meteo reports.
+var wmoWeatherCodes = map[int]string{
+	0:  "clear sky",
+	1:  "mainly clear",
+	2:  "partly cloudy",
+	3:  "overcast",
+	45: "fog",
+	48: "depositing rime fog",
+	51: "light drizzle",
+	53: "moderate drizzle",
+	55: "dense drizzle",
+	56: "light freezing drizzle",
+	57: "dense freezing drizzle",
+	61: "slight rain",
+	63: "moderate rain",
+	65: "heavy rain",
+	66: "light freezing rain",
+	67: "heavy freezing rain",
+	71: "slight snow fall",
+	73: "moderate snow fall",
+	75: "heavy snow fall",
+	77: "snow grains",
+	80: "slight rain showers",
+	81: "moderate rain showers",
+	82: "violent rain showers",
+	85: "slight snow showers",
+	86: "heavy snow showers",
+	95: "thunderstorm",
+	96: "thunderstorm with slight hail",
+	99: "thunderstorm with heavy hail",
+}
+
+// Returns the current weather at the passed in point, in metric units.
+// Implements the WeatherProvider Interface.
+func (o *OpenMeteoProvider) Weather(p *Point) (*WeatherConditions, error) {
+	if o.Quota != nil {
+		if err := o.Quota.Spend("open-meteo"); err != nil {
+			return nil, err
+		}
+	}
+
+	values := url.Values{
+		"latitude":        {strconv.FormatFloat(p.lat, 'f', -1, 64)},
+		"longitude":       {strconv.FormatFloat(p.lng, 'f', -1, 64)},
+		"current":         {openMeteoCurrentVariables},
+		"wind_speed_unit": {"ms"},
+		"timeformat":      {"unixtime"},
+	}
+	if o.apiKey != "" {
+		values.Set("apikey", o.apiKey)
+	}
+
+	base := o.BaseURL
+	if base == "" {
+		base = DEFAULT_OPEN_METEO_URL
+	}
+
+	data, err := httpGet(o.HTTPClient, base+"?"+values.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	res := &openMeteoResponse{}
+	if err := json.Unmarshal(data, res); err != nil {
+		return nil, err
+	}
+
+	if res.Error {
+		return nil, fmt.Errorf("open-meteo: %s", res.Reason)
+	}
+
+	current := res.Current
+	return &WeatherConditions{
+		Point:         p,
+		Time:          time.Unix(current.Time, 0).UTC(),
+		Description:   wmoWeatherCodes[current.WeatherCode],
+		Temperature:   current.Temperature,
+		FeelsLike:     current.ApparentTemperature,
+		Humidity:      current.RelativeHumidity,
+		Pressure:      current.SurfacePressure,
+		WindSpeed:     current.WindSpeed,
+		WindDirection: current.WindDirection,
+		CloudCover:    current.CloudCover,
+		Precipitation: current.Precipitation,
+	}, nil
+}
+
+// A WeatherProvider that remembers the conditions the wrapped WeatherProvider
+// returns for TTL, sharing them between points in the same geohash cell.
+// A CachedWeatherProvider is safe for concurrent use.
+type CachedWeatherProvider struct {
+	Provider WeatherProvider
+	TTL      time.Duration
+
+	// The geohash precision of the cells conditions are shared across.
+	// Defaults to DEFAULT_WEATHER_CACHE_PRECISION.
+	Precision int
+
+	mu      sync.Mutex
+	entries map[string]*WeatherConditions
+	stored  map[string]time.Time
+
+	// Used to determine entry age.  Overridable for testing.
+	now func() time.Time
+}
+
+// Creates and returns a pointer to a new CachedWeatherProvider wrapping the passed in provider,
+// remembering conditions for the passed in duration.
+func NewCachedWeatherProvider(provider WeatherProvider, ttl time.Duration) *CachedWeatherProvider {
+	return &CachedWeatherProvider{
+		Provider:  provider,
+		TTL:       ttl,
+		Precision: DEFAULT_WEATHER_CACHE_PRECISION,
+		now:       time.Now,
+	}
+}
+
+// Returns the current weather at the passed in point, from the cache if it holds
+// conditions for the point's cell that are younger than TTL.
+// Implements the WeatherProvider Interface.
+func (c *CachedWeatherProvider) Weather(p *Point) (*WeatherConditions, error) {
+	precision := c.Precision
+	if precision <= 0 {
+		precision = DEFAULT_WEATHER_CACHE_PRECISION
+	}
+
+	now := time.Now
+	if c.now != nil {
+		now = c.now
+	}
+
+	key := EncodeGeohash(p.lat, p.lng, precision)
+
+	c.mu.Lock()
+	if conditions, ok := c.entries[key]; ok && now().Sub(c.stored[key]) < c.TTL {
+		c.mu.Unlock()
+		return conditions, nil
+	}
+	c.mu.Unlock()
+
+	conditions, err := c.Provider.Weather(p)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.entries = make(map[string]*WeatherConditions)
+		c.stored = make(map[string]time.Time)
+	}
+
+	// Drop expired entries as we go, so that long-running processes don't grow without bound.
+	for k, stored := range c.stored {
+		if now().Sub(stored) >= c.TTL {
+			delete(c.entries, k)
+			delete(c.stored, k)
+		}
+	}
+
+	c.entries[key] = conditions
+	c.stored[key] = now()
+
+	return conditions, nil
+}
@@ -0,0 +1,163 @@
+package geo
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// Returns a server that responds to every request with the passed in body,
+// recording the query of each request it receives.
+func newWeatherServer(t *testing.T, body string, queries *[]url.Values) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*queries = append(*queries, r.URL.Query())
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// Ensures that OpenWeatherMapProvider requests metric conditions and reads them from the response.
+func TestOpenWeatherMapWeather(t *testing.T) {
+	var queries []url.Values
+	server := newWeatherServer(t, `{
+		"weather": [{"description": "light rain"}],
+		"main": {"temp": 12.5, "feels_like": 11.8, "humidity": 81, "pressure": 1012},
+		"wind": {"speed": 4.1, "deg": 230},
+		"clouds": {"all": 90},
+		"rain": {"1h": 0.6},
+		"dt": 1700000000,
+		"cod": 200
+	}`, &queries)
+
+	o := NewOpenWeatherMapProvider(WithAPIKey("secret"), WithBaseURL(server.URL), WithLanguage("fr"))
+	conditions, err := o.Weather(NewPoint(51.5, -0.12))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	q := queries[0]
+	if q.Get("lat") != "51.5" || q.Get("lon") != "-0.12" || q.Get("appid") != "secret" || q.Get("units") != "metric" || q.Get("lang") != "fr" {
+		t.Errorf("Unexpected query: %v", q)
+	}
+
+	if conditions.Description != "light rain" || conditions.Temperature != 12.5 || conditions.Humidity != 81 ||
+		conditions.WindDirection != 230 || conditions.Precipitation != 0.6 || conditions.CloudCover != 90 {
+		t.Errorf("Unexpected conditions: %+v", conditions)
+	}
+
+	if !conditions.Time.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("Expected the observation time to be read from dt, got %v", conditions.Time)
+	}
+}
+
+// Ensures that OpenWeatherMap's error responses are returned as errors.
+func TestOpenWeatherMapWeatherError(t *testing.T) {
+	var queries []url.Values
+	server := newWeatherServer(t, `{"cod": 401, "message": "Invalid API key."}`, &queries)
+
+	_, err := NewOpenWeatherMapProvider(WithBaseURL(server.URL)).Weather(NewPoint(0, 0))
+	if err == nil || err.Error() != "openweathermap: Invalid API key. (401)" {
+		t.Errorf("Expected an invalid API key error, got %v", err)
+	}
+}
+
+// Ensures that OpenMeteoProvider requests current conditions and describes their weather code.
+func TestOpenMeteoWeather(t *testing.T) {
+	var queries []url.Values
+	server := newWeatherServer(t, `{
+		"current": {
+			"time": 1700000000,
+			"temperature_2m": -3.2,
+			"apparent_temperature": -7.9,
+			"relative_humidity_2m": 74,
+			"surface_pressure": 1003.4,
+			"wind_speed_10m": 6.2,
+			"wind_direction_10m": 315,
+			"cloud_cover": 100,
+			"precipitation": 1.2,
+			"weather_code": 73
+		}
+	}`, &queries)
+
+	conditions, err := NewOpenMeteoProvider(WithBaseURL(server.URL)).Weather(NewPoint(60.17, 24.94))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	q := queries[0]
+	if q.Get("latitude") != "60.17" || q.Get("longitude") != "24.94" || q.Get("wind_speed_unit") != "ms" || q.Get("apikey") != "" {
+		t.Errorf("Unexpected query: %v", q)
+	}
+
+	if conditions.Description != "moderate snow fall" || conditions.Temperature != -3.2 || conditions.FeelsLike != -7.9 ||
+		math.Abs(conditions.Pressure-1003.4) > 1e-9 || conditions.WindSpeed != 6.2 {
+		t.Errorf("Unexpected conditions: %+v", conditions)
+	}
+}
+
+// Ensures that Open-Meteo's error responses are returned as errors.
+func TestOpenMeteoWeatherError(t *testing.T) {
+	var queries []url.Values
+	server := newWeatherServer(t, `{"error": true, "reason": "Latitude must be in range of -90 to 90°."}`, &queries)
+
+	_, err := NewOpenMeteoProvider(WithBaseURL(server.URL)).Weather(NewPoint(0, 0))
+	if err == nil || err.Error() != "open-meteo: Latitude must be in range of -90 to 90°." {
+		t.Errorf("Expected a range error, got %v", err)
+	}
+}
+
+// Ensures that weather requests are counted against a Quota and refused once it is spent.
+func TestWeatherQuota(t *testing.T) {
+	var queries []url.Values
+	server := newWeatherServer(t, `{"current": {"time": 1700000000}}`, &queries)
+
+	q := NewQuota()
+	q.SetDailyBudget("open-meteo", 1)
+	o := NewOpenMeteoProvider(WithBaseURL(server.URL), WithQuota(q))
+
+	if _, err := o.Weather(NewPoint(0, 0)); err != nil {
+		t.Errorf("Expected the first request to be within budget, got %v", err)
+	}
+
+	if _, err := o.Weather(NewPoint(0, 0)); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Expected ErrBudgetExceeded, got %v", err)
+	}
+
+	if len(queries) != 1 {
+		t.Errorf("Expected 1 request to reach the server, got %d", len(queries))
+	}
+}
+
+// Ensures that CachedWeatherProvider shares conditions within a geohash cell until they expire.
+func TestCachedWeatherProvider(t *testing.T) {
+	var queries []url.Values
+	server := newWeatherServer(t, `{"current": {"time": 1700000000, "temperature_2m": 20}}`, &queries)
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := NewCachedWeatherProvider(NewOpenMeteoProvider(WithBaseURL(server.URL)), 10*time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.Weather(NewPoint(51.5, -0.12))
+	c.Weather(NewPoint(51.5001, -0.1201))
+	if len(queries) != 1 {
+		t.Errorf("Expected nearby points to share cached conditions, got %d requests", len(queries))
+	}
+
+	c.Weather(NewPoint(48.85, 2.35))
+	if len(queries) != 2 {
+		t.Errorf("Expected a distant point to miss the cache, got %d requests", len(queries))
+	}
+
+	now = now.Add(11 * time.Minute)
+	c.Weather(NewPoint(51.5, -0.12))
+	if len(queries) != 3 {
+		t.Errorf("Expected expired conditions to be fetched again, got %d requests", len(queries))
+	}
+}
@@ -0,0 +1,253 @@
+package geo
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// The geometry types of Well-Known Binary.
+const (
+	wkbPoint      = 1
+	wkbLineString = 2
+	wkbPolygon    = 3
+)
+
+// The flags PostGIS's Extended Well-Known Binary sets on a geometry type.
+const (
+	ewkbZ    = 0x80000000
+	ewkbM    = 0x40000000
+	ewkbSRID = 0x20000000
+)
+
+// This is the error that consumers can compare against with errors.Is when data isn't
+// Well-Known Binary, or holds a geometry this package has no type for.
+var ErrInvalidWKB = errors.New("geo: invalid WKB geometry")
+
+// Returns an error matching ErrInvalidWKB, explaining why the data is invalid.
+func invalidWKB(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: "+format, append([]interface{}{ErrInvalidWKB}, args...)...)
+}
+
+// Renders the passed in geometry, a *Point, a Line or a *Polygon, as little endian Well-Known Binary
+// with longitude as x and latitude as y, as ST_GeomFromWKB reads it.
+func MarshalWKB(g Geometry) ([]byte, error) {
+	return appendWKB(nil, g, 0)
+}
+
+// Renders the passed in geometry as PostGIS's Extended Well-Known Binary, which is Well-Known Binary
+// along with a spatial reference system, such as SQL_SRID.  PostGIS reads it as a geometry parameter.
+func MarshalEWKB(g Geometry, srid int) ([]byte, error) {
+	return appendWKB(nil, g, srid)
+}
+
+// Appends the passed in geometry to dst as Well-Known Binary, extended with the passed in SRID unless it is zero.
+func appendWKB(dst []byte, g Geometry, srid int) ([]byte, error) {
+	var kind uint32
+	switch g.(type) {
+	case *Point:
+		kind = wkbPoint
+	case Line:
+		kind = wkbLineString
+	case *Polygon:
+		kind = wkbPolygon
+	default:
+		return nil, fmt.Errorf("geo: can't encode a %T as WKB", g)
+	}
+
+	dst = append(dst, 1)
+	if srid != 0 {
+		dst = binary.LittleEndian.AppendUint32(dst, kind|ewkbSRID)
+		dst = binary.LittleEndian.AppendUint32(dst, uint32(srid))
+	} else {
+		dst = binary.LittleEndian.AppendUint32(dst, kind)
+	}
+
+	switch g := g.(type) {
+	case *Point:
+		return appendWKBPoint(dst, g), nil
+	case Line:
+		return appendWKBPoints(dst, g)
+	default:
+		// WKB rings repeat their first point at the end.
+		ring := g.(*Polygon).Points()
+		if len(ring) > 0 && ring[0] != nil && ring[len(ring)-1] != nil && *ring[0] != *ring[len(ring)-1] {
+			ring = append(ring[:len(ring):len(ring)], ring[0])
+		}
+		dst = binary.LittleEndian.AppendUint32(dst, 1)
+		return appendWKBPoints(dst, ring)
+	}
+}
+
+// Appends the passed in point's longitude and latitude to dst.
+func appendWKBPoint(dst []byte, p *Point) []byte {
+	dst = binary.LittleEndian.AppendUint64(dst, math.Float64bits(p.lng))
+	return binary.LittleEndian.AppendUint64(dst, math.Float64bits(p.lat))
+}
+
+// Appends the number of passed in points, then each of them, to dst.
+func appendWKBPoints(dst []byte, points []*Point) ([]byte, error) {
+	dst = binary.LittleEndian.AppendUint32(dst, uint32(len(points)))
+	for i, p := range points {
+		if p == nil {
+			return nil, fmt.Errorf("geo: point %d is nil", i)
+		}
+		dst = appendWKBPoint(dst, p)
+	}
+
+	return dst, nil
+}
+
+// Decodes a *Point, a Line or a *Polygon from Well-Known Binary or PostGIS's Extended Well-Known Binary,
+// returning it along with its SRID, or zero if the data has none.  Z and M coordinates are dropped.
+// Polygons with holes are refused, since a Polygon has none.
+func UnmarshalWKB(data []byte) (Geometry, int, error) {
+	r := &wkbReader{data: data}
+	g, srid := r.geometry()
+	if r.err == nil && len(r.data) > 0 {
+		r.err = invalidWKB("%d trailing bytes", len(r.data))
+	}
+
+	if r.err != nil {
+		return nil, 0, r.err
+	}
+
+	return g, srid, nil
+}
+
+// Reads Well-Known Binary, recording the first error it runs into.
+type wkbReader struct {
+	data  []byte
+	order binary.ByteOrder
+	err   error
+}
+
+// Returns the next n bytes, or nil if there aren't as many left.
+func (r *wkbReader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+
+	if len(r.data) < n {
+		r.err = invalidWKB("truncated")
+		return nil
+	}
+
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *wkbReader) uint32() uint32 {
+	b := r.next(4)
+	if b == nil {
+		return 0
+	}
+
+	return r.order.Uint32(b)
+}
+
+func (r *wkbReader) float64() float64 {
+	b := r.next(8)
+	if b == nil {
+		return 0
+	}
+
+	return math.Float64frombits(r.order.Uint64(b))
+}
+
+// Reads a point of the passed in number of coordinates, the first two being its longitude and latitude.
+func (r *wkbReader) point(dims int) *Point {
+	lng, lat := r.float64(), r.float64()
+	for i := 2; i < dims; i++ {
+		r.float64()
+	}
+
+	return NewPoint(lat, lng)
+}
+
+// Reads a count of points, then as many points of the passed in number of coordinates.
+func (r *wkbReader) points(dims int) []*Point {
+	n := r.uint32()
+	if r.err == nil && uint64(n)*uint64(dims)*8 > uint64(len(r.data)) {
+		r.err = invalidWKB("%d bytes hold no %d points", len(r.data), n)
+	}
+
+	if r.err != nil {
+		return nil
+	}
+
+	points := make([]*Point, n)
+	for i := range points {
+		points[i] = r.point(dims)
+	}
+
+	return points
+}
+
+// Reads a geometry and its SRID.
+func (r *wkbReader) geometry() (Geometry, int) {
+	order := r.next(1)
+	if order == nil {
+		return nil, 0
+	}
+
+	switch order[0] {
+	case 0:
+		r.order = binary.BigEndian
+	case 1:
+		r.order = binary.LittleEndian
+	default:
+		r.err = invalidWKB("unknown byte order %d", order[0])
+		return nil, 0
+	}
+
+	kind := r.uint32()
+	srid := 0
+	if kind&ewkbSRID != 0 {
+		srid = int(r.uint32())
+	}
+
+	// Extended WKB flags extra coordinates; ISO WKB adds 1000 to the type for Z, 2000 for M and 3000 for both.
+	dims := 2
+	if kind&ewkbZ != 0 {
+		dims++
+	}
+	if kind&ewkbM != 0 {
+		dims++
+	}
+	kind &^= ewkbZ | ewkbM | ewkbSRID
+	switch kind / 1000 {
+	case 1, 2:
+		dims++
+	case 3:
+		dims += 2
+	}
+	kind %= 1000
+
+	if r.err != nil {
+		return nil, 0
+	}
+
+	switch kind {
+	case wkbPoint:
+		return r.point(dims), srid
+	case wkbLineString:
+		return Line(r.points(dims)), srid
+	case wkbPolygon:
+		rings := r.uint32()
+		if r.err == nil && rings != 1 {
+			r.err = invalidWKB("polygons must have exactly one ring, got %d", rings)
+		}
+
+		ring := r.points(dims)
+		if n := len(ring); n > 1 && *ring[0] == *ring[n-1] {
+			ring = ring[:n-1]
+		}
+		return NewPolygon(ring), srid
+	default:
+		r.err = invalidWKB("unsupported geometry type %d", kind)
+		return nil, 0
+	}
+}
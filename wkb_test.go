@@ -0,0 +1,54 @@
+package geo
+
+import (
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// Ensures that well known WKB, in either byte order, decodes to the point it describes.
+func TestUnmarshalWKB(t *testing.T) {
+	for _, s := range []string{
+		"0101000000000000000000f03f0000000000000040",
+		"00000000013ff00000000000004000000000000000",
+		// POINT Z (1 2 3), in ISO WKB.
+		"01e9030000000000000000f03f00000000000000400000000000000840",
+	} {
+		data, _ := hex.DecodeString(s)
+		g, srid, err := UnmarshalWKB(data)
+		if err != nil || srid != 0 || !reflect.DeepEqual(g, NewPoint(2, 1)) {
+			t.Errorf("Expected %s to be POINT (1 2), got %v, SRID %d (%v)", s, g, srid, err)
+		}
+	}
+}
+
+// Ensures that geometries survive a round trip through WKB and EWKB.
+func TestWKBRoundTrip(t *testing.T) {
+	points := []*Point{NewPoint(37.6, -122.4), NewPoint(37.8, -122.4), NewPoint(37.7, -122.2)}
+	for _, g := range []Geometry{points[0], Line(points), NewPolygon(points)} {
+		data, err := MarshalEWKB(g, SQL_SRID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decoded, srid, err := UnmarshalWKB(data)
+		if err != nil || srid != SQL_SRID || !reflect.DeepEqual(decoded, g) {
+			t.Errorf("Expected %v in SRID %d, got %v in SRID %d (%v)", g, SQL_SRID, decoded, srid, err)
+		}
+	}
+
+	if data, _ := MarshalWKB(NewPolygon(points)); len(data) != 1+4+4+4+4*16 {
+		t.Errorf("Expected the polygon's ring to be closed, got %d bytes", len(data))
+	}
+}
+
+// Ensures that data that isn't a supported WKB geometry is refused.
+func TestUnmarshalWKBInvalid(t *testing.T) {
+	data, _ := MarshalWKB(NewPoint(1, 2))
+	for _, invalid := range [][]byte{nil, data[:len(data)-1], append(data, 0), {2, 1, 0, 0, 0}, {1, 7, 0, 0, 0}} {
+		if _, _, err := UnmarshalWKB(invalid); !errors.Is(err, ErrInvalidWKB) {
+			t.Errorf("Expected ErrInvalidWKB for %x, got %v", invalid, err)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// This is the error that consumers can compare against with errors.Is when text isn't
+// Well-Known Text, or holds a geometry this package has no type for.
+var ErrInvalidWKT = errors.New("geo: invalid WKT geometry")
+
+// Returns an error matching ErrInvalidWKT, explaining why the text is invalid.
+func invalidWKT(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: "+format, append([]interface{}{ErrInvalidWKT}, args...)...)
+}
+
+// Renders the passed in geometry, a *Point, a Line or a *Polygon, as Well-Known Text with longitude first,
+// e.g. "POINT(-122.389979 37.615223)", its coordinates written with CoordinatePrecision decimal places.
+// Polygons' rings are closed, repeating their first point at the end, as WKT requires.
+func MarshalWKT(g Geometry) (string, error) {
+	var wkt []byte
+	switch g := g.(type) {
+	case *Point:
+		wkt = appendWKTPoints(append(wkt, "POINT"...), []*Point{g})
+	case Line:
+		wkt = appendWKTPoints(append(wkt, "LINESTRING"...), g)
+	case *Polygon:
+		// Even a ring of one point is closed, by repeating it.
+		ring := g.Points()
+		if n := len(ring); n == 1 || n > 1 && *ring[0] != *ring[n-1] {
+			ring = append(ring[:len(ring):len(ring)], ring[0])
+		}
+
+		wkt = append(wkt, "POLYGON"...)
+		if len(ring) == 0 {
+			wkt = append(wkt, " EMPTY"...)
+		} else {
+			wkt = append(appendWKTPoints(append(wkt, '('), ring), ')')
+		}
+	default:
+		return "", fmt.Errorf("geo: can't encode a %T as WKT", g)
+	}
+
+	return string(wkt), nil
+}
+
+// Appends the passed in points to dst in parentheses, separated by commas, or " EMPTY" if there are none.
+func appendWKTPoints(dst []byte, points []*Point) []byte {
+	if len(points) == 0 {
+		return append(dst, " EMPTY"...)
+	}
+
+	dst = append(dst, '(')
+	for i, p := range points {
+		if i > 0 {
+			dst = append(dst, ", "...)
+		}
+		dst = appendCoordinate(dst, p.lng)
+		dst = append(dst, ' ')
+		dst = appendCoordinate(dst, p.lat)
+	}
+
+	return append(dst, ')')
+}
+
+// Decodes a *Point, a Line or a *Polygon from Well-Known Text with longitude first, such as
+// "POINT(-122.389979 37.615223)", in any case and spacing.  Z and M coordinates are dropped,
+// and the SRID PostGIS's Extended WKT starts with, as in "SRID=4326;POINT(1 2)", is skipped.
+// Polygons with holes are refused, since a Polygon has none, as are rings that aren't closed.
+func UnmarshalWKT(wkt string) (Geometry, error) {
+	return unmarshalWKT(wkt, false)
+}
+
+// Decodes a *Point, a Line or a *Polygon from Well-Known Text as UnmarshalWKT does, except that
+// rings that aren't closed are closed and polygons are repaired with Polygon.Normalize, so that
+// text from tools winding rings the other way, or repeating points, reads alike.
+func UnmarshalNormalizedWKT(wkt string) (Geometry, error) {
+	return unmarshalWKT(wkt, true)
+}
+
+// Decodes a geometry from Well-Known Text, normalizing polygons if normalize is true.
+func unmarshalWKT(wkt string, normalize bool) (Geometry, error) {
+	text := strings.TrimSpace(wkt)
+	if upper := strings.ToUpper(text); strings.HasPrefix(upper, "SRID=") {
+		semicolon := strings.IndexByte(text, ';')
+		if semicolon < 0 {
+			return nil, invalidWKT("SRID without a geometry in %q", wkt)
+		}
+		text = strings.TrimSpace(text[semicolon+1:])
+	}
+
+	end := strings.IndexAny(text, "( \t\r\n")
+	if end < 0 {
+		end = len(text)
+	}
+	kind, body := strings.ToUpper(text[:end]), strings.TrimSpace(text[end:])
+
+	// Dimensions may be written apart from the type, as in "POINT Z (1 2 3)".
+	for _, dims := range []string{"ZM", "Z", "M"} {
+		if len(body) > len(dims) && strings.EqualFold(body[:len(dims)], dims) && strings.ContainsAny(body[len(dims):len(dims)+1], "( \t\r\n") {
+			body = strings.TrimSpace(body[len(dims):])
+			break
+		}
+	}
+	kind = strings.TrimSuffix(strings.TrimSuffix(strings.TrimSuffix(kind, "ZM"), "Z"), "M")
+	empty := strings.EqualFold(body, "EMPTY")
+
+	switch kind {
+	case "POINT":
+		if empty {
+			return nil, invalidWKT("empty points have no Point")
+		}
+
+		points, err := parseWKTPoints(body)
+		if err != nil {
+			return nil, err
+		}
+		if len(points) != 1 {
+			return nil, invalidWKT("points have one position, got %d", len(points))
+		}
+		return points[0], nil
+	case "LINESTRING":
+		if empty {
+			return Line{}, nil
+		}
+
+		points, err := parseWKTPoints(body)
+		if err != nil {
+			return nil, err
+		}
+		return Line(points), nil
+	case "POLYGON":
+		if empty {
+			return NewPolygon(nil), nil
+		}
+
+		rings, err := wktParens(body)
+		if err != nil {
+			return nil, err
+		}
+		if n := strings.Count(rings, "("); n != 1 {
+			return nil, invalidWKT("polygons must have exactly one ring, got %d", n)
+		}
+
+		ring, err := parseWKTPoints(rings)
+		if err != nil {
+			return nil, err
+		}
+
+		n := len(ring)
+		closed := n > 1 && *ring[0] == *ring[n-1]
+		if closed {
+			ring = ring[:n-1]
+		}
+
+		polygon := NewPolygon(ring)
+		if normalize {
+			polygon.Normalize()
+		} else if !closed {
+			return nil, invalidWKT("polygon ring isn't closed")
+		}
+		return polygon, nil
+	default:
+		return nil, invalidWKT("unsupported geometry type %q", kind)
+	}
+}
+
+// Returns the text within the parentheses wrapping the passed in text.
+func wktParens(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 || s[0] != '(' || s[len(s)-1] != ')' {
+		return "", invalidWKT("expected parentheses, got %q", s)
+	}
+
+	return s[1 : len(s)-1], nil
+}
+
+// Returns the points of a parenthesized list of positions separated by commas, each of two to four
+// coordinates separated by spaces, with longitude first.
+func parseWKTPoints(s string) ([]*Point, error) {
+	list, err := wktParens(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var points []*Point
+	for _, position := range strings.Split(list, ",") {
+		fields := strings.Fields(position)
+		if len(fields) < 2 || len(fields) > 4 {
+			return nil, invalidWKT("position %q has %d coordinates", strings.TrimSpace(position), len(fields))
+		}
+
+		var coords [2]float64
+		for i := range coords {
+			if coords[i], err = strconv.ParseFloat(fields[i], 64); err != nil {
+				return nil, invalidWKT("invalid coordinate %q", fields[i])
+			}
+		}
+		points = append(points, NewPoint(coords[1], coords[0]))
+	}
+
+	return points, nil
+}
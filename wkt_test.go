@@ -0,0 +1,94 @@
+package geo
+
+import (
+	"errors"
+	"testing"
+)
+
+// Ensures that geometries are written as WKT with longitude first and closed polygon rings.
+func TestMarshalWKT(t *testing.T) {
+	triangle := NewPolygon([]*Point{NewPoint(0, 0), NewPoint(0, 1), NewPoint(1, 1)})
+	tests := []struct {
+		g        Geometry
+		expected string
+	}{
+		{NewPoint(37.615223, -122.389979), "POINT(-122.389979 37.615223)"},
+		{Line{NewPoint(1, 2), NewPoint(3.5, 4)}, "LINESTRING(2 1, 4 3.5)"},
+		{Line{}, "LINESTRING EMPTY"},
+		{triangle, "POLYGON((0 0, 1 0, 1 1, 0 0))"},
+		{NewPolygon(nil), "POLYGON EMPTY"},
+		{NewPolygon([]*Point{NewPoint(1, 2)}), "POLYGON((2 1, 2 1))"},
+	}
+
+	for _, test := range tests {
+		wkt, err := MarshalWKT(test.g)
+		if err != nil || wkt != test.expected {
+			t.Errorf("Expected %s, got %s (%v)", test.expected, wkt, err)
+		}
+	}
+
+	if len(triangle.Points()) != 3 {
+		t.Errorf("Expected the polygon to be left open, got %v", triangle.Points())
+	}
+}
+
+// Ensures that WKT is read back as the geometries it was written from, in any case, spacing and dimensions.
+func TestUnmarshalWKT(t *testing.T) {
+	triangle := NewPolygon([]*Point{NewPoint(0, 0), NewPoint(0, 1), NewPoint(1, 1)})
+	for _, g := range []Geometry{NewPoint(37.615223, -122.389979), Line{NewPoint(1, 2), NewPoint(3.5, 4)}, Line{}, triangle} {
+		wkt, err := MarshalWKT(g)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decoded, err := UnmarshalWKT(wkt)
+		if err != nil {
+			t.Errorf("Expected %s to be read, got %v", wkt, err)
+			continue
+		}
+		if again, _ := MarshalWKT(decoded); again != wkt {
+			t.Errorf("Expected %s to be read back as it was written, got %s", wkt, again)
+		}
+	}
+
+	for wkt, expected := range map[string]Geometry{
+		"point z (2 1 100)":                    NewPoint(1, 2),
+		"SRID=4326;POINTM(2 1 7)":              NewPoint(1, 2),
+		"  LineString ZM ( 2 1 0 0 ,4 3 0 0 )": Line{NewPoint(1, 2), NewPoint(3, 4)},
+	} {
+		g, err := UnmarshalWKT(wkt)
+		if err != nil {
+			t.Errorf("Expected %q to be read, got %v", wkt, err)
+			continue
+		}
+		if got, _ := MarshalWKT(g); got != mustMarshalWKT(t, expected) {
+			t.Errorf("Expected %q to be read as %s, got %s", wkt, mustMarshalWKT(t, expected), got)
+		}
+	}
+
+	for _, wkt := range []string{
+		"",
+		"POINT EMPTY",
+		"POINT(1)",
+		"POINT(1 2, 3 4)",
+		"POINT(a b)",
+		"LINESTRING(1 2",
+		"POLYGON((0 0, 1 0, 1 1, 0 0), (0.1 0.1, 0.2 0.1, 0.2 0.2, 0.1 0.1))",
+		"MULTIPOINT((1 2))",
+		"SRID=4326",
+	} {
+		if _, err := UnmarshalWKT(wkt); !errors.Is(err, ErrInvalidWKT) {
+			t.Errorf("Expected %v reading %q, got %v", ErrInvalidWKT, wkt, err)
+		}
+	}
+}
+
+func mustMarshalWKT(t *testing.T, g Geometry) string {
+	t.Helper()
+	wkt, err := MarshalWKT(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return wkt
+}